@@ -5,11 +5,20 @@ import (
 	"os"
 
 	"github.com/cosmos/state-mesh/internal/cmd"
+	"github.com/cosmos/state-mesh/internal/telemetry"
 )
 
 func main() {
-	if err := cmd.Execute(); err != nil {
+	err := execute()
+	telemetry.Flush()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// execute runs the command, reporting a panic before crashing
+func execute() error {
+	defer telemetry.Recover(nil)
+	return cmd.Execute()
+}
@@ -0,0 +1,386 @@
+// Package alerting evaluates tenants' alert rules against the balance and
+// delegation events of the stream and alerts their webhooks and email
+// subscriptions when a rule's condition becomes true
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/email"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/internal/webhook"
+	eventsv1 "github.com/cosmos/state-mesh/pkg/events/v1"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// ConsumerGroup is the Kafka consumer group the API servers evaluating alert
+// rules share, so that each event is evaluated once
+const ConsumerGroup = "state-mesh-alert-rules"
+
+var (
+	fired = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "alert_rules",
+		Name:      "fired_total",
+		Help:      "Alert rule conditions that became true",
+	})
+
+	deliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "alert_rules",
+		Name:      "deliveries_total",
+		Help:      "Alert rule deliveries by channel (webhook or email) and result (success or failure)",
+	}, []string{"channel", "result"})
+)
+
+// Engine evaluates alert rules. It reloads the enabled rules of every tenant
+// on the refresh interval and evaluates those of an event's chain against
+// each balance and delegation event read, limited to the addresses of their
+// watchlist. A rule fires when its condition becomes true for an account's
+// balance in a denom or delegation to a validator, and again only after an
+// event made it false, and is delivered to its webhook, or without one to
+// the tenant's webhooks and email subscriptions subscribed to alerts.
+type Engine struct {
+	cfg      config.AlertRuleConfig
+	storage  *storage.Manager
+	webhooks *webhook.Sender
+	emails   *email.Sender
+	logger   *zap.Logger
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	rules  map[string][]*rule // by chain
+	firing map[firingKey]bool
+}
+
+// rule is an enabled alert rule with its compiled condition, the addresses
+// it is limited to and the webhooks and email subscriptions it is delivered
+// to
+type rule struct {
+	types.AlertRule
+	condition *Condition
+	addresses map[string]bool // nil for every address
+	webhooks  []types.Webhook
+	emails    []types.EmailSubscription
+}
+
+// firingKey identifies what a rule fires for: an account's balance in a
+// denom or delegation to a validator
+type firingKey struct {
+	ruleID  string
+	subject string
+}
+
+// alert is an alert to deliver to a rule's webhooks and email subscriptions
+type alert struct {
+	rule    *rule
+	payload types.RuleAlert
+}
+
+// New creates an alert rule engine, emailing with the email sender when it is
+// not nil
+func New(cfg config.AlertRuleConfig, storage *storage.Manager, emails *email.Sender, logger *zap.Logger) *Engine {
+	return &Engine{
+		cfg:      cfg,
+		storage:  storage,
+		webhooks: webhook.NewSender(cfg.Timeout),
+		emails:   emails,
+		logger:   logger.Named("alert_rules"),
+		rules:    make(map[string][]*rule),
+		firing:   make(map[firingKey]bool),
+	}
+}
+
+// Start loads the rules, then evaluates them against the events the consumer
+// reads in the background, reloading them on the refresh interval
+func (e *Engine) Start(ctx context.Context, consumer *streaming.Consumer) {
+	ctx, e.cancel = context.WithCancel(ctx)
+
+	if err := e.Refresh(ctx); err != nil {
+		e.logger.Error("Failed to load alert rules", zap.Error(err))
+	}
+
+	e.wg.Add(2)
+	go func() {
+		defer e.wg.Done()
+
+		ticker := time.NewTicker(e.cfg.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := e.Refresh(ctx); err != nil {
+					e.logger.Error("Failed to reload alert rules", zap.Error(err))
+				}
+			}
+		}
+	}()
+	go func() {
+		defer e.wg.Done()
+
+		e.logger.Info("Alert rule engine started", zap.Duration("refresh_interval", e.cfg.RefreshInterval))
+		consumer.Run(ctx, func(event *streaming.Event) {
+			e.Handle(ctx, event)
+		})
+		e.logger.Info("Alert rule engine stopped")
+	}()
+}
+
+// Stop stops the engine and waits for the alerts being sent
+func (e *Engine) Stop() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.wg.Wait()
+}
+
+// Refresh reloads the enabled rules of every tenant. Rules whose condition
+// no longer compiles are skipped.
+func (e *Engine) Refresh(ctx context.Context) error {
+	tenants, err := e.storage.Tenants().GetTenants(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules := make(map[string][]*rule)
+	ids := make(map[string]bool)
+	for _, tenant := range tenants {
+		tenantRules, err := e.tenantRules(ctx, tenant.ID)
+		if err != nil {
+			return err
+		}
+		for _, r := range tenantRules {
+			rules[r.ChainName] = append(rules[r.ChainName], r)
+			ids[r.ID] = true
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = rules
+	for key := range e.firing {
+		if !ids[key.ruleID] {
+			delete(e.firing, key)
+		}
+	}
+	return nil
+}
+
+// tenantRules returns the enabled rules of a tenant that are delivered to at
+// least one webhook or email subscription
+func (e *Engine) tenantRules(ctx context.Context, tenantID string) ([]*rule, error) {
+	alertRules, err := e.storage.Tenants().GetAlertRules(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(alertRules) == 0 {
+		return nil, nil
+	}
+	webhooks, err := e.storage.Tenants().GetWebhooks(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	emails, err := e.emails.Subscriptions(ctx, e.storage.Tenants(), tenantID, webhook.EventAlert)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*rule
+	for _, alertRule := range alertRules {
+		if !alertRule.Enabled {
+			continue
+		}
+		condition, err := Compile(alertRule.Condition)
+		if err != nil {
+			e.logger.Warn("Skipping alert rule with an invalid condition",
+				zap.String("tenant", tenantID),
+				zap.String("rule", alertRule.ID),
+				zap.Error(err))
+			continue
+		}
+
+		r := &rule{AlertRule: alertRule, condition: condition}
+		for _, hook := range webhooks {
+			if hook.Enabled && (hook.ID == alertRule.WebhookID || (alertRule.WebhookID == "" && webhook.Subscribed(hook, webhook.EventAlert))) {
+				r.webhooks = append(r.webhooks, hook)
+			}
+		}
+		if alertRule.WebhookID == "" {
+			r.emails = emails
+		}
+		if len(r.webhooks) == 0 && len(r.emails) == 0 {
+			continue
+		}
+
+		if alertRule.WatchlistID != "" {
+			watchlist, err := e.storage.Tenants().GetWatchlist(ctx, tenantID, alertRule.WatchlistID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get watchlist of alert rule %s: %w", alertRule.ID, err)
+			}
+			r.addresses = make(map[string]bool, len(watchlist.Addresses))
+			for _, address := range watchlist.Addresses {
+				r.addresses[address] = true
+			}
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// Handle evaluates the rules of an event's chain against a balance or
+// delegation event and delivers the alerts of the rules it fires
+func (e *Engine) Handle(ctx context.Context, event *streaming.Event) {
+	if event.Type != streaming.EventBalance && event.Type != streaming.EventDelegation {
+		return
+	}
+	env, subject, err := eventEnv(event)
+	if err != nil {
+		e.logger.Warn("Failed to decode event for alert rules", zap.String("event", event.ID), zap.Error(err))
+		return
+	}
+
+	for _, a := range e.evaluate(event, env, subject) {
+		e.deliver(ctx, a)
+	}
+}
+
+// evaluate returns the alerts of the rules an event fires, recording which
+// rules hold for its subject
+func (e *Engine) evaluate(event *streaming.Event, env env, subject string) []alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	address, _ := env["address"].(string)
+	var alerts []alert
+	for _, r := range e.rules[event.Chain] {
+		if r.addresses != nil && !r.addresses[address] {
+			continue
+		}
+		matched, known := r.condition.Eval(env)
+		if !known {
+			continue
+		}
+
+		key := firingKey{ruleID: r.ID, subject: subject}
+		wasFiring := e.firing[key]
+		if matched {
+			e.firing[key] = true
+		} else {
+			delete(e.firing, key)
+		}
+		if !matched || wasFiring {
+			continue
+		}
+
+		fired.Inc()
+		alerts = append(alerts, alert{rule: r, payload: types.RuleAlert{
+			TenantID:  r.TenantID,
+			Rule:      r.AlertRule,
+			EventType: event.Type,
+			Event:     event.Data,
+		}})
+	}
+	return alerts
+}
+
+// deliver sends an alert to its rule's webhooks and email subscriptions
+func (e *Engine) deliver(ctx context.Context, a alert) {
+	a.payload.SentAt = time.Now().UTC()
+	for i := range a.rule.webhooks {
+		if err := e.webhooks.Send(ctx, &a.rule.webhooks[i], webhook.EventAlert, a.payload); err != nil {
+			e.logger.Warn("Failed to deliver alert",
+				zap.String("tenant", a.rule.TenantID),
+				zap.String("rule", a.rule.ID),
+				zap.String("webhook", a.rule.webhooks[i].ID),
+				zap.Error(err))
+			deliveries.WithLabelValues("webhook", "failure").Inc()
+			continue
+		}
+		deliveries.WithLabelValues("webhook", "success").Inc()
+	}
+	for _, sub := range a.rule.emails {
+		msg := email.Message{To: sub.Email, Event: webhook.EventAlert, Data: a.payload, UnsubscribeToken: sub.UnsubscribeToken}
+		if err := e.emails.Send(msg); err != nil {
+			e.logger.Warn("Failed to email alert",
+				zap.String("tenant", a.rule.TenantID),
+				zap.String("rule", a.rule.ID),
+				zap.String("email_subscription", sub.ID),
+				zap.Error(err))
+			deliveries.WithLabelValues("email", "failure").Inc()
+			continue
+		}
+		deliveries.WithLabelValues("email", "success").Inc()
+	}
+}
+
+// eventUnmarshal decodes events ignoring fields added to the schema later
+var eventUnmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// eventEnv returns the variables a balance or delegation event provides and
+// the subject rules fire for: the account's balance in the denom or its
+// delegation to the validator
+func eventEnv(event *streaming.Event) (env, string, error) {
+	env := env{"chain": event.Chain, "type": event.Type}
+
+	switch event.Type {
+	case streaming.EventBalance:
+		var balance eventsv1.BalanceEvent
+		if err := eventUnmarshal.Unmarshal(event.Data, &balance); err != nil {
+			return nil, "", fmt.Errorf("invalid balance event: %w", err)
+		}
+		env["address"] = balance.Address
+		env["denom"] = balance.Denom
+		env["change_type"] = balance.ChangeType
+		env["height"] = decimal.NewFromInt(balance.Height)
+		setChange(env, "balance", balance.Amount, balance.PreviousAmount)
+		return env, balance.Address + "/" + balance.Denom, nil
+
+	case streaming.EventDelegation:
+		var delegation eventsv1.DelegationEvent
+		if err := eventUnmarshal.Unmarshal(event.Data, &delegation); err != nil {
+			return nil, "", fmt.Errorf("invalid delegation event: %w", err)
+		}
+		env["address"] = delegation.DelegatorAddress
+		env["validator"] = delegation.ValidatorAddress
+		env["change_type"] = delegation.ChangeType
+		env["height"] = decimal.NewFromInt(delegation.Height)
+		setChange(env, "delegation", delegation.Shares, delegation.PreviousShares)
+		return env, delegation.DelegatorAddress + "/" + delegation.ValidatorAddress, nil
+	}
+	return nil, "", fmt.Errorf("unsupported event type %q", event.Type)
+}
+
+// setChange sets an amount variable and, when the previous amount is known,
+// its change and the change in percent of the previous amount if not zero
+func setChange(env env, name, amount, previous string) {
+	current, err := decimal.NewFromString(amount)
+	if err != nil {
+		return
+	}
+	env[name] = current
+
+	before, err := decimal.NewFromString(previous)
+	if err != nil {
+		return
+	}
+	change := current.Sub(before)
+	env[name+"_change"] = change
+	if !before.IsZero() {
+		env[name+"_change_pct"] = change.Mul(decimal.NewFromInt(100)).Div(before)
+	}
+}
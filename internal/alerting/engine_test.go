@@ -0,0 +1,106 @@
+package alerting
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	eventsv1 "github.com/cosmos/state-mesh/pkg/events/v1"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// balanceEvent returns the streamed event of a balance change
+func balanceEvent(t *testing.T, address, amount, previous string) *streaming.Event {
+	t.Helper()
+	data, err := protojson.Marshal(&eventsv1.BalanceEvent{
+		ChainName:      "cosmoshub",
+		Address:        address,
+		Denom:          "uatom",
+		Amount:         amount,
+		PreviousAmount: previous,
+		Height:         100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &streaming.Event{Type: streaming.EventBalance, Chain: "cosmoshub", Data: data}
+}
+
+func TestEventEnv(t *testing.T) {
+	env, subject, err := eventEnv(balanceEvent(t, "cosmos1a", "90", "120"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "cosmos1a/uatom" {
+		t.Errorf("subject = %q", subject)
+	}
+
+	for condition, want := range map[string]bool{
+		`balance(uatom) == 90 && address == "cosmos1a"`: true,
+		`balance_change == -30`:                         true,
+		`balance_change_pct == -25`:                     true,
+		`height == 100`:                                 true,
+	} {
+		c, err := Compile(condition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matched, _ := c.Eval(env); matched != want {
+			t.Errorf("%s = %v, want %v", condition, matched, want)
+		}
+	}
+
+	// A first balance has no change
+	env, _, err = eventEnv(balanceEvent(t, "cosmos1a", "90", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := env["balance_change"]; ok {
+		t.Errorf("balance_change set without a previous amount")
+	}
+}
+
+func TestEngineFiresOnTransitions(t *testing.T) {
+	condition, err := Compile(`balance(uatom) < 100`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := New(config.AlertRuleConfig{}, nil, nil, zap.NewNop())
+	e.rules["cosmoshub"] = []*rule{{
+		AlertRule: types.AlertRule{ID: "rule", TenantID: "tenant", ChainName: "cosmoshub"},
+		condition: condition,
+		addresses: map[string]bool{"cosmos1a": true, "cosmos1b": true},
+	}}
+
+	steps := []struct {
+		address string
+		amount  string
+		want    int
+	}{
+		{address: "cosmos1a", amount: "50", want: 1},
+		{address: "cosmos1a", amount: "40"},          // still below
+		{address: "cosmos1b", amount: "10", want: 1}, // another account
+		{address: "cosmos1c", amount: "10"},          // not watched
+		{address: "cosmos1a", amount: "500"},         // recovered
+		{address: "cosmos1a", amount: "60", want: 1}, // below again
+	}
+	for i, step := range steps {
+		event := balanceEvent(t, step.address, step.amount, "")
+		env, subject, err := eventEnv(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		alerts := e.evaluate(event, env, subject)
+		if len(alerts) != step.want {
+			t.Fatalf("step %d: %d alerts, want %d", i, len(alerts), step.want)
+		}
+		for _, a := range alerts {
+			if a.payload.TenantID != "tenant" || a.payload.Rule.ID != "rule" || a.payload.EventType != streaming.EventBalance {
+				t.Errorf("step %d: alert %+v", i, a.payload)
+			}
+		}
+	}
+}
@@ -0,0 +1,549 @@
+package alerting
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxConditionLength bounds the conditions rules may be created with
+const maxConditionLength = 1024
+
+// kind is the type of an expression
+type kind int
+
+const (
+	kindBool kind = iota
+	kindNumber
+	kindString
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindBool:
+		return "boolean"
+	case kindNumber:
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// variables lists the variables conditions may read and their kinds. Events
+// provide the ones that apply to them; the others are unknown.
+var variables = map[string]kind{
+	"chain":                 kindString,
+	"type":                  kindString, // "balance" or "delegation"
+	"address":               kindString, // the account or delegator
+	"change_type":           kindString,
+	"height":                kindNumber,
+	"denom":                 kindString,
+	"balance":               kindNumber,
+	"balance_change":        kindNumber,
+	"balance_change_pct":    kindNumber,
+	"validator":             kindString,
+	"delegation":            kindNumber,
+	"delegation_change":     kindNumber,
+	"delegation_change_pct": kindNumber,
+}
+
+// env is what a condition is evaluated against: the variables an event
+// provides, strings or numbers
+type env map[string]any
+
+// value is the result of evaluating an expression. Expressions reading a
+// variable the event does not provide are unknown.
+type value struct {
+	known bool
+	b     bool
+	n     decimal.Decimal
+	s     string
+}
+
+var unknown = value{}
+
+// expr is a node of a parsed condition
+type expr interface {
+	kind() kind
+	eval(env env) value
+}
+
+// Condition is a compiled alert rule condition
+type Condition struct {
+	source string
+	root   expr
+}
+
+// String returns the condition's source
+func (c *Condition) String() string {
+	return c.source
+}
+
+// Eval evaluates the condition for an event, reporting whether it holds and
+// whether the event provides enough of what it reads to tell. Unknown parts
+// follow three-valued logic, so "a || b" holds when either holds even if the
+// other is unknown.
+func (c *Condition) Eval(env env) (matched, known bool) {
+	v := c.root.eval(env)
+	return v.known && v.b, v.known
+}
+
+// Compile parses and type checks a condition. Conditions compare variables,
+// function calls and literals with <, <=, >, >=, == and != and combine the
+// comparisons with &&, || and !, for example:
+//
+//	balance(uatom) < 100000000 && chain == "cosmoshub"
+//	abs(delegation_change_pct) > 10 || change_type == "redelegate"
+//
+// balance(denom) is the balance of an account in a denom, quoted when it is
+// not an identifier such as "ibc/27394FB0", and abs(x) the absolute value of
+// a number.
+func Compile(source string) (*Condition, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("condition is empty")
+	}
+	if len(source) > maxConditionLength {
+		return nil, fmt.Errorf("condition is longer than %d characters", maxConditionLength)
+	}
+
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.typ != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at %d", tok, tok.pos)
+	}
+	if root.kind() != kindBool {
+		return nil, fmt.Errorf("condition is a %s, not a comparison", root.kind())
+	}
+	return &Condition{source: source, root: root}, nil
+}
+
+// tokenType is the type of a lexed token
+type tokenType int
+
+const (
+	tokEOF tokenType = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+)
+
+// token is a lexed token and its offset in the source
+type token struct {
+	typ tokenType
+	val string
+	pos int
+}
+
+func (t token) String() string {
+	if t.typ == tokEOF {
+		return "end of condition"
+	}
+	return strconv.Quote(t.val)
+}
+
+// operators are the operators and punctuation of the language, longest first
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")"}
+
+// lex splits a condition into tokens
+func lex(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(source) && (source[i] == '_' || unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokIdent, val: source[start:i], pos: start})
+		case unicode.IsDigit(c) || c == '.' || (c == '-' && i+1 < len(source) && (unicode.IsDigit(rune(source[i+1])) || source[i+1] == '.')):
+			start := i
+			i++
+			for i < len(source) && (unicode.IsDigit(rune(source[i])) || source[i] == '.') {
+				i++
+			}
+			if _, err := decimal.NewFromString(source[start:i]); err != nil {
+				return nil, fmt.Errorf("invalid number %q at %d", source[start:i], start)
+			}
+			tokens = append(tokens, token{typ: tokNumber, val: source[start:i], pos: start})
+		case c == '"':
+			start := i
+			for i++; i < len(source) && source[i] != '"'; i++ {
+				if source[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(source) {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			i++
+			s, err := strconv.Unquote(source[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at %d: %v", start, err)
+			}
+			tokens = append(tokens, token{typ: tokString, val: s, pos: start})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(source[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			tokens = append(tokens, token{typ: tokOp, val: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{typ: tokEOF, pos: len(source)}), nil
+}
+
+// parser is a recursive descent parser over the tokens of a condition:
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | comparison
+//	comparison = operand [ ( "<" | "<=" | ">" | ">=" | "==" | "!=" ) operand ]
+//	operand    = number | string | ident [ "(" or ")" ] | "(" or ")"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.typ != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the operator op
+func (p *parser) accept(op string) bool {
+	if tok := p.peek(); tok.typ == tokOp && tok.val == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := p.peek().pos
+		if !p.accept("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBool("||", pos, left, right); err != nil {
+			return nil, err
+		}
+		left = &logical{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := p.peek().pos
+		if !p.accept("&&") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBool("&&", pos, left, right); err != nil {
+			return nil, err
+		}
+		left = &logical{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	pos := p.peek().pos
+	if !p.accept("!") {
+		return p.parseComparison()
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBool("!", pos, operand); err != nil {
+		return nil, err
+	}
+	return &not{operand: operand}, nil
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	if tok.typ != tokOp {
+		return left, nil
+	}
+	switch tok.val {
+	case "<", "<=", ">", ">=", "==", "!=":
+	default:
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if left.kind() != right.kind() {
+		return nil, fmt.Errorf("%q at %d compares a %s with a %s", tok.val, tok.pos, left.kind(), right.kind())
+	}
+	if left.kind() == kindBool {
+		return nil, fmt.Errorf("%q at %d compares comparisons; combine them with && or ||", tok.val, tok.pos)
+	}
+	if left.kind() == kindString && tok.val != "==" && tok.val != "!=" {
+		return nil, fmt.Errorf("%q at %d orders strings; strings only compare with == and !=", tok.val, tok.pos)
+	}
+	return &comparison{op: tok.val, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (expr, error) {
+	tok := p.next()
+	switch tok.typ {
+	case tokNumber:
+		return &literal{v: value{known: true, n: decimal.RequireFromString(tok.val)}, k: kindNumber}, nil
+	case tokString:
+		return &literal{v: value{known: true, s: tok.val}, k: kindString}, nil
+	case tokIdent:
+		if p.accept("(") {
+			return p.parseCall(tok)
+		}
+		k, ok := variables[tok.val]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q at %d; known variables: %s", tok.val, tok.pos, strings.Join(variableNames(), ", "))
+		}
+		return &variable{name: tok.val, k: k}, nil
+	case tokOp:
+		if tok.val == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(")") {
+				return nil, fmt.Errorf("expected \")\" at %d, got %s", p.peek().pos, p.peek())
+			}
+			return inner, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at %d", tok, tok.pos)
+}
+
+// parseCall parses the arguments of a call to the function named by tok
+func (p *parser) parseCall(tok token) (expr, error) {
+	switch tok.val {
+	case "balance":
+		// The denom may be written bare, as an identifier
+		arg := p.next()
+		if arg.typ != tokIdent && arg.typ != tokString {
+			return nil, fmt.Errorf("balance at %d takes a denom, got %s", tok.pos, arg)
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected \")\" at %d, got %s", p.peek().pos, p.peek())
+		}
+		return &balanceOf{denom: arg.val}, nil
+	case "abs":
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if arg.kind() != kindNumber {
+			return nil, fmt.Errorf("abs at %d takes a number, got a %s", tok.pos, arg.kind())
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected \")\" at %d, got %s", p.peek().pos, p.peek())
+		}
+		return &abs{operand: arg}, nil
+	default:
+		return nil, fmt.Errorf("unknown function %q at %d; known functions: abs, balance", tok.val, tok.pos)
+	}
+}
+
+// checkBool checks that the operands of a logical operator are comparisons
+func checkBool(op string, pos int, operands ...expr) error {
+	for _, operand := range operands {
+		if operand.kind() != kindBool {
+			return fmt.Errorf("%q at %d takes comparisons, got a %s", op, pos, operand.kind())
+		}
+	}
+	return nil
+}
+
+// variableNames returns the names of the variables in alphabetical order
+func variableNames() []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// literal is a number or string literal
+type literal struct {
+	v value
+	k kind
+}
+
+func (l *literal) kind() kind         { return l.k }
+func (l *literal) eval(env env) value { return l.v }
+
+// variable reads a variable of the event
+type variable struct {
+	name string
+	k    kind
+}
+
+func (v *variable) kind() kind { return v.k }
+
+func (v *variable) eval(env env) value {
+	switch x := env[v.name].(type) {
+	case string:
+		return value{known: true, s: x}
+	case decimal.Decimal:
+		return value{known: true, n: x}
+	default:
+		return unknown
+	}
+}
+
+// balanceOf reads the balance of a balance event in a denom
+type balanceOf struct {
+	denom string
+}
+
+func (b *balanceOf) kind() kind { return kindNumber }
+
+func (b *balanceOf) eval(env env) value {
+	if denom, _ := env["denom"].(string); denom != b.denom {
+		return unknown
+	}
+	return (&variable{name: "balance", k: kindNumber}).eval(env)
+}
+
+// abs is the absolute value of a number
+type abs struct {
+	operand expr
+}
+
+func (a *abs) kind() kind { return kindNumber }
+
+func (a *abs) eval(env env) value {
+	v := a.operand.eval(env)
+	v.n = v.n.Abs()
+	return v
+}
+
+// comparison compares two numbers or strings
+type comparison struct {
+	op          string
+	left, right expr
+}
+
+func (c *comparison) kind() kind { return kindBool }
+
+func (c *comparison) eval(env env) value {
+	l, r := c.left.eval(env), c.right.eval(env)
+	if !l.known || !r.known {
+		return unknown
+	}
+
+	var cmp int
+	if c.left.kind() == kindString {
+		cmp = strings.Compare(l.s, r.s)
+	} else {
+		cmp = l.n.Cmp(r.n)
+	}
+
+	var b bool
+	switch c.op {
+	case "<":
+		b = cmp < 0
+	case "<=":
+		b = cmp <= 0
+	case ">":
+		b = cmp > 0
+	case ">=":
+		b = cmp >= 0
+	case "==":
+		b = cmp == 0
+	case "!=":
+		b = cmp != 0
+	}
+	return value{known: true, b: b}
+}
+
+// logical is a conjunction or disjunction in three-valued logic
+type logical struct {
+	op          string
+	left, right expr
+}
+
+func (l *logical) kind() kind { return kindBool }
+
+func (l *logical) eval(env env) value {
+	left, right := l.left.eval(env), l.right.eval(env)
+
+	// A false operand decides a conjunction and a true one a disjunction
+	decisive := l.op == "||"
+	if (left.known && left.b == decisive) || (right.known && right.b == decisive) {
+		return value{known: true, b: decisive}
+	}
+	if !left.known || !right.known {
+		return unknown
+	}
+	return value{known: true, b: !decisive}
+}
+
+// not negates a comparison, leaving it unknown when it is
+type not struct {
+	operand expr
+}
+
+func (n *not) kind() kind { return kindBool }
+
+func (n *not) eval(env env) value {
+	v := n.operand.eval(env)
+	v.b = !v.b
+	return v
+}
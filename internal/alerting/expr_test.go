@@ -0,0 +1,105 @@
+package alerting
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		condition string
+		wantErr   string
+	}{
+		{condition: `balance(uatom) < 100 && chain == "cosmoshub"`},
+		{condition: `delegation_change_pct > 10`},
+		{condition: `balance < 1000000`},
+		{condition: `!(abs(balance_change_pct) <= 5) || change_type == "redelegate"`},
+		{condition: `balance("ibc/27394FB0") >= 0.5 && height > -1`},
+		{condition: ``, wantErr: "empty"},
+		{condition: `balance`, wantErr: "not a comparison"},
+		{condition: `balances < 100`, wantErr: `unknown variable "balances"`},
+		{condition: `sum(balance) > 1`, wantErr: `unknown function "sum"`},
+		{condition: `chain == 1`, wantErr: "compares a string with a number"},
+		{condition: `chain < "cosmoshub"`, wantErr: "orders strings"},
+		{condition: `balance > 1 == chain`, wantErr: `unexpected "=="`},
+		{condition: `balance && delegation > 1`, wantErr: `takes comparisons`},
+		{condition: `(balance > 1`, wantErr: `expected ")"`},
+		{condition: `balance > 1 )`, wantErr: `unexpected ")"`},
+		{condition: `chain == "cosmoshub`, wantErr: "unterminated string"},
+		{condition: `balance > 1.2.3`, wantErr: "invalid number"},
+		{condition: `balance > 1 & delegation > 1`, wantErr: `unexpected '&'`},
+		{condition: `abs(chain) > 1`, wantErr: "abs at 0 takes a number"},
+		{condition: `balance(1) > 1`, wantErr: "takes a denom"},
+		{condition: strings.Repeat("balance > 1 || ", 100) + "balance > 1", wantErr: "longer than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			_, err := Compile(tt.condition)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Compile() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConditionEval(t *testing.T) {
+	balance := env{
+		"chain":          "cosmoshub",
+		"type":           "balance",
+		"address":        "cosmos1a",
+		"denom":          "uatom",
+		"balance":        decimal.NewFromInt(50),
+		"balance_change": decimal.NewFromInt(-150),
+	}
+	delegation := env{
+		"chain":                 "cosmoshub",
+		"type":                  "delegation",
+		"validator":             "cosmosvaloper1a",
+		"delegation":            decimal.NewFromInt(1200),
+		"delegation_change_pct": decimal.RequireFromString("-20"),
+	}
+
+	tests := []struct {
+		name        string
+		condition   string
+		env         env
+		wantMatched bool
+		wantKnown   bool
+	}{
+		{name: "balance in denom", condition: `balance(uatom) < 100 && chain == "cosmoshub"`, env: balance, wantMatched: true, wantKnown: true},
+		{name: "balance in another denom", condition: `balance(uosmo) < 100`, env: balance},
+		{name: "other chain", condition: `balance(uatom) < 100 && chain == "osmosis"`, env: balance, wantKnown: true},
+		{name: "not provided", condition: `delegation_change_pct > 10`, env: balance},
+		{name: "negative change", condition: `delegation_change_pct > 10`, env: delegation, wantKnown: true},
+		{name: "absolute change", condition: `abs(delegation_change_pct) > 10`, env: delegation, wantMatched: true, wantKnown: true},
+		{name: "negation", condition: `!(balance >= 100)`, env: balance, wantMatched: true, wantKnown: true},
+		{name: "negation of unknown", condition: `!(delegation >= 100)`, env: balance},
+		{name: "disjunction with unknown", condition: `delegation > 1 || balance_change < -100`, env: balance, wantMatched: true, wantKnown: true},
+		{name: "false disjunction with unknown", condition: `delegation > 1 || balance_change > 0`, env: balance},
+		{name: "false conjunction with unknown", condition: `delegation > 1 && balance_change > 0`, env: balance, wantKnown: true},
+		{name: "true conjunction with unknown", condition: `delegation > 1 && balance_change < 0`, env: balance},
+		{name: "precedence", condition: `type == "balance" || type == "delegation" && validator == "x"`, env: balance, wantMatched: true, wantKnown: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := Compile(tt.condition)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matched, known := condition.Eval(tt.env)
+			if matched != tt.wantMatched || known != tt.wantKnown {
+				t.Errorf("Eval() = %v, %v, want %v, %v", matched, known, tt.wantMatched, tt.wantKnown)
+			}
+		})
+	}
+}
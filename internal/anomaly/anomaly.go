@@ -0,0 +1,264 @@
+// Package anomaly detects anomalies in the chains' hourly delegation outflows,
+// supply changes and event rates, and alerts tenants' webhooks and email
+// subscriptions of them
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/email"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/webhook"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+var (
+	detected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "anomalies",
+		Name:      "detected_total",
+		Help:      "Anomalies detected by chain and metric",
+	}, []string{"chain", "metric"})
+
+	deliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "anomalies",
+		Name:      "deliveries_total",
+		Help:      "Anomaly alert deliveries by channel (webhook or email) and result (success or failure)",
+	}, []string{"channel", "result"})
+)
+
+// Detector detects anomalies. Each check it scores the last complete hour of
+// every metric of each enabled chain with a z-score against the exponentially
+// weighted moving average and variance of the hours before it. Anomalies are
+// recorded in the state store, so that only the API server recording one
+// alerts on it, and sent to the webhooks and email subscriptions of every
+// tenant subscribed to anomalies when tenancy is enabled.
+type Detector struct {
+	cfg      config.AnomalyConfig
+	chains   []config.ChainConfig
+	tenancy  bool
+	storage  *storage.Manager
+	webhooks *webhook.Sender
+	emails   *email.Sender
+	logger   *zap.Logger
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// New creates an anomaly detector over the enabled chains, alerting tenants
+// when tenancy is enabled and emailing them with the email sender when it is
+// not nil
+func New(cfg config.AnomalyConfig, chains []config.ChainConfig, tenancy bool, storage *storage.Manager, emails *email.Sender, logger *zap.Logger) *Detector {
+	var enabled []config.ChainConfig
+	for _, chain := range chains {
+		if chain.Enabled {
+			enabled = append(enabled, chain)
+		}
+	}
+	return &Detector{
+		cfg:      cfg,
+		chains:   enabled,
+		tenancy:  tenancy,
+		storage:  storage,
+		webhooks: webhook.NewSender(cfg.Timeout),
+		emails:   emails,
+		logger:   logger.Named("anomalies"),
+	}
+}
+
+// Start looks for anomalies in the background on the check interval
+func (d *Detector) Start(ctx context.Context) {
+	ctx, d.cancel = context.WithCancel(ctx)
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		ticker := time.NewTicker(d.cfg.CheckInterval)
+		defer ticker.Stop()
+
+		d.logger.Info("Anomaly detector started", zap.Duration("check_interval", d.cfg.CheckInterval))
+
+		for {
+			select {
+			case <-ctx.Done():
+				d.logger.Info("Anomaly detector stopped")
+				return
+			case <-ticker.C:
+				if err := d.RunOnce(ctx); err != nil {
+					d.logger.Error("Failed to detect anomalies", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the detector and waits for the alerts being sent
+func (d *Detector) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+}
+
+// RunOnce scores the last complete hour of every chain metric, recording and
+// alerting on the anomalies
+func (d *Detector) RunOnce(ctx context.Context) error {
+	now := time.Now().UTC()
+	hour := now.Truncate(time.Hour).Add(-time.Hour)
+
+	var errs []error
+	for _, chain := range d.chains {
+		for _, metric := range types.AnomalyMetrics {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			points, err := d.storage.GetHourlyMetric(ctx, chain.Name, metric, chain.StakingDenom(), hour.Add(-d.cfg.Window), hour.Add(time.Hour))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			anomaly, ok := d.score(points, hour)
+			if !ok {
+				continue
+			}
+			anomaly.ChainName = chain.Name
+			anomaly.Metric = metric
+			anomaly.DetectedAt = now
+
+			recorded, err := d.storage.Anomalies().RecordAnomaly(ctx, anomaly)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !recorded {
+				continue // detected before, or by another server
+			}
+			detected.WithLabelValues(chain.Name, metric).Inc()
+			d.logger.Info("Anomaly detected",
+				zap.String("chain", chain.Name),
+				zap.String("metric", metric),
+				zap.Time("hour", anomaly.Hour),
+				zap.Float64("value", anomaly.Value),
+				zap.Float64("expected", anomaly.Expected),
+				zap.Float64("z_score", anomaly.ZScore))
+
+			if d.tenancy {
+				if err := d.notify(ctx, anomaly); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// score scores the point of an hour against the exponentially weighted moving
+// average and variance of the points before it, returning an anomaly when it
+// is more than the threshold of standard deviations away. Leading zero points,
+// from before the chain was ingested, are skipped; metrics without enough
+// points or variance are not scored.
+func (d *Detector) score(points []types.MetricPoint, hour time.Time) (types.Anomaly, bool) {
+	if len(points) == 0 || !points[len(points)-1].Time.Equal(hour) {
+		return types.Anomaly{}, false
+	}
+	history, current := points[:len(points)-1], points[len(points)-1]
+	for len(history) > 0 && history[0].Value == 0 {
+		history = history[1:]
+	}
+	if len(history) < d.cfg.MinSamples {
+		return types.Anomaly{}, false
+	}
+
+	mean, variance := history[0].Value, 0.0
+	for _, point := range history[1:] {
+		diff := point.Value - mean
+		incr := d.cfg.Alpha * diff
+		mean += incr
+		variance = (1 - d.cfg.Alpha) * (variance + diff*incr)
+	}
+	stdDev := math.Sqrt(variance)
+	if stdDev == 0 {
+		return types.Anomaly{}, false
+	}
+
+	z := (current.Value - mean) / stdDev
+	if math.Abs(z) <= d.cfg.Threshold {
+		return types.Anomaly{}, false
+	}
+	return types.Anomaly{
+		Hour:     current.Time,
+		Value:    current.Value,
+		Expected: mean,
+		StdDev:   stdDev,
+		ZScore:   z,
+	}, true
+}
+
+// notify sends an anomaly to the webhooks and email subscriptions of every
+// tenant subscribed to anomalies
+func (d *Detector) notify(ctx context.Context, anomaly types.Anomaly) error {
+	tenants, err := d.storage.Tenants().GetTenants(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, tenant := range tenants {
+		webhooks, err := d.storage.Tenants().GetWebhooks(ctx, tenant.ID)
+		if err != nil {
+			return err
+		}
+		emails, err := d.emails.Subscriptions(ctx, d.storage.Tenants(), tenant.ID, webhook.EventAnomaly)
+		if err != nil {
+			return err
+		}
+
+		alert := types.AnomalyAlert{
+			TenantID: tenant.ID,
+			Anomaly:  anomaly,
+			SentAt:   time.Now().UTC(),
+		}
+		for i := range webhooks {
+			if !webhook.Subscribed(webhooks[i], webhook.EventAnomaly) {
+				continue
+			}
+			if err := d.webhooks.Send(ctx, &webhooks[i], webhook.EventAnomaly, alert); err != nil {
+				d.logger.Warn("Failed to deliver anomaly alert",
+					zap.String("tenant", tenant.ID),
+					zap.String("webhook", webhooks[i].ID),
+					zap.String("chain", anomaly.ChainName),
+					zap.String("metric", anomaly.Metric),
+					zap.Error(err))
+				deliveries.WithLabelValues("webhook", "failure").Inc()
+				continue
+			}
+			deliveries.WithLabelValues("webhook", "success").Inc()
+		}
+		for _, sub := range emails {
+			msg := email.Message{To: sub.Email, Event: webhook.EventAnomaly, Data: alert, UnsubscribeToken: sub.UnsubscribeToken}
+			if err := d.emails.Send(msg); err != nil {
+				d.logger.Warn("Failed to email anomaly alert",
+					zap.String("tenant", tenant.ID),
+					zap.String("email_subscription", sub.ID),
+					zap.String("chain", anomaly.ChainName),
+					zap.String("metric", anomaly.Metric),
+					zap.Error(err))
+				deliveries.WithLabelValues("email", "failure").Inc()
+				continue
+			}
+			deliveries.WithLabelValues("email", "success").Inc()
+		}
+	}
+	return nil
+}
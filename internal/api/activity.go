@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/cosmos/state-mesh/internal/authz"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// activityModules maps activity feed entry types to the modules whose data
+// they are
+var activityModules = map[string]string{
+	storage.AccountEventBalance:    "bank",
+	storage.AccountEventDelegation: "staking",
+	storage.ActivityVote:           "gov",
+	storage.ActivityTransaction:    "blocks",
+}
+
+// activityFilter parses the type and time query parameters of an activity
+// feed. Entry types the API key's role may not read are dropped unless
+// requested explicitly, which is denied.
+func (s *Server) activityFilter(c *gin.Context) (types.ActivityFilter, error) {
+	var filter types.ActivityFilter
+
+	requested := splitChains(c.Query("type"))
+	for _, kind := range requested {
+		if _, ok := activityModules[kind]; !ok {
+			return filter, fmt.Errorf("unknown activity type %q; known types: balance, delegation, vote, tx", kind)
+		}
+	}
+	policy := policyFromContext(c.Request.Context())
+	for _, kind := range storage.ActivityTypes {
+		if len(requested) > 0 && !slices.Contains(requested, kind) {
+			continue
+		}
+		resource := authz.Resource{Endpoint: authz.EndpointEvents, Modules: []string{activityModules[kind]}}
+		if err := policy.Authorize(resource, nil); err != nil {
+			if len(requested) > 0 {
+				return filter, err
+			}
+			continue
+		}
+		filter.Types = append(filter.Types, kind)
+	}
+
+	var err error
+	if filter.From, err = timeParam(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = timeParam(c, "to"); err != nil {
+		return filter, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+
+	return filter, nil
+}
+
+// getAccountActivity handles GET /api/v1/accounts/:address/activity. It
+// returns a page of the address's balance and delegation events, governance
+// votes and transactions on the requested chains, newest first, with the
+// cursor of the next page.
+func (s *Server) getAccountActivity(c *gin.Context) {
+	address := c.Param("address")
+	refs := accountRefsFromContext(c)
+
+	if s.storage.ClickHouse() == nil {
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, "analytics storage is not available")
+		return
+	}
+
+	filter, err := s.activityFilter(c)
+	if err != nil {
+		if errors.Is(err, authz.ErrDenied) {
+			s.abortWithError(c, http.StatusForbidden, CodePermissionDenied, err.Error())
+			return
+		}
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		s.badRequest(c, "limit must be between 1 and 100")
+		return
+	}
+
+	cursor := c.Query("cursor")
+	if cursor != "" {
+		if err := storage.ValidateActivityCursor(cursor); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	activity := []types.ActivityItem{}
+	if len(filter.Types) > 0 {
+		activity, err = s.accounts.GetAccountActivity(c.Request.Context(), refs, filter, cursor, limit)
+		if err != nil {
+			s.logger.Error("Failed to get account activity",
+				zap.String("address", address),
+				zap.Strings("chains", refChains(refs)),
+				zap.Error(err))
+			s.storageError(c, err, "failed to get account activity")
+			return
+		}
+	}
+
+	var next string
+	if len(activity) == limit {
+		next = activity[len(activity)-1].Cursor
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chains":      refChains(refs),
+		"address":     address,
+		"activity":    activity,
+		"next_cursor": next,
+	})
+}
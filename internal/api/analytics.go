@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// analyticsQuery describes a query template to callers, without its SQL
+type analyticsQuery struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Params      []analyticsParam `json:"params"`
+}
+
+// analyticsParam describes a parameter of a query template
+type analyticsParam struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// getAnalyticsQueries handles GET /api/v1/analytics/queries, listing the
+// configured query templates and their parameters
+func (s *Server) getAnalyticsQueries(c *gin.Context) {
+	queries := make([]analyticsQuery, 0, len(s.cfg.Analytics.Queries))
+	for name, template := range s.cfg.Analytics.Queries {
+		query := analyticsQuery{Name: name, Description: template.Description, Params: []analyticsParam{}}
+		for _, param := range template.Params {
+			query.Params = append(query.Params, analyticsParam{
+				Name:        param.Name,
+				Type:        param.Type,
+				Required:    param.Default == "",
+				Default:     param.Default,
+				Description: param.Description,
+			})
+		}
+		queries = append(queries, query)
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Name < queries[j].Name })
+
+	c.JSON(http.StatusOK, gin.H{"queries": queries})
+}
+
+// runAnalyticsQuery handles GET /api/v1/chains/:chain/analytics/:query,
+// running a query template on the chain with the query string as its
+// arguments
+func (s *Server) runAnalyticsQuery(c *gin.Context) {
+	chainName := c.Param("chain")
+	name := c.Param("query")
+
+	template, ok := s.cfg.Analytics.Queries[name]
+	if !ok {
+		s.abortWithError(c, http.StatusNotFound, CodeNotFound, "unknown analytics query: "+name)
+		return
+	}
+	if s.storage.ClickHouse() == nil {
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, "analytics storage is not available")
+		return
+	}
+
+	params, err := bindAnalyticsParams(template, c.Request.URL.Query())
+	if err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+	params[config.AnalyticsChainParam] = chainName
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), s.cfg.Analytics.Timeout)
+	defer cancel()
+
+	result, err := s.storage.ClickHouse().RunAnalyticsQuery(ctx, template.SQL, params, s.cfg.Analytics.MaxRows, s.cfg.Analytics.Timeout)
+	if err != nil {
+		s.logger.Error("Failed to run analytics query",
+			zap.String("chain", chainName),
+			zap.String("query", name),
+			zap.Error(err))
+		s.storageError(c, err, "failed to run analytics query")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":     chainName,
+		"query":     name,
+		"columns":   result.Columns,
+		"rows":      result.Rows,
+		"truncated": result.Truncated,
+	})
+}
+
+// bindAnalyticsParams checks the arguments of a query template against its
+// parameters, falling back to their defaults, and formats them for
+// ClickHouse
+func bindAnalyticsParams(template config.AnalyticsQueryConfig, args map[string][]string) (map[string]string, error) {
+	declared := make(map[string]bool, len(template.Params))
+	for _, param := range template.Params {
+		declared[param.Name] = true
+	}
+	for name := range args {
+		if !declared[name] {
+			return nil, fmt.Errorf("unknown parameter: %s", name)
+		}
+	}
+
+	params := make(map[string]string, len(template.Params)+1)
+	for _, param := range template.Params {
+		raw := param.Default
+		if values := args[param.Name]; len(values) > 0 && values[0] != "" {
+			raw = values[0]
+		}
+		if raw == "" {
+			return nil, fmt.Errorf("parameter %s is required", param.Name)
+		}
+
+		value, ok := formatAnalyticsParam(param.Type, raw)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s must be a valid %s", param.Name, param.Type)
+		}
+		params[param.Name] = value
+	}
+	return params, nil
+}
+
+// formatAnalyticsParam parses an argument of a parameter type and formats it
+// the way ClickHouse parses query parameters
+func formatAnalyticsParam(paramType, raw string) (string, bool) {
+	switch paramType {
+	case config.AnalyticsParamInt:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		return strconv.FormatInt(n, 10), err == nil
+	case config.AnalyticsParamFloat:
+		f, err := strconv.ParseFloat(raw, 64)
+		return strconv.FormatFloat(f, 'f', -1, 64), err == nil
+	case config.AnalyticsParamBool:
+		b, err := strconv.ParseBool(raw)
+		return strconv.FormatBool(b), err == nil
+	case config.AnalyticsParamTime:
+		t, err := time.Parse(time.RFC3339, raw)
+		return t.UTC().Format(time.DateTime), err == nil
+	default:
+		return raw, true
+	}
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// graphqlEndpoint is the endpoint GraphQL calls are audited under
+const graphqlEndpoint = "/graphql"
+
+var auditEventsDropped = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "statemesh",
+	Subsystem: "api",
+	Name:      "audit_events_dropped_total",
+	Help:      "API audit events dropped because the audit buffer was full",
+})
+
+// auditLog records authenticated API calls in ClickHouse in batches
+type auditLog struct {
+	store         *storage.ClickHouseStore
+	events        chan types.APIAuditEvent
+	batchSize     int
+	flushInterval time.Duration
+	logger        *zap.Logger
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// newAuditLog creates an audit log and starts its flush loop
+func newAuditLog(cfg config.AuditConfig, store *storage.ClickHouseStore, logger *zap.Logger) *auditLog {
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &auditLog{
+		store:         store,
+		events:        make(chan types.APIAuditEvent, cfg.BufferSize),
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		logger:        logger.Named("audit"),
+		cancel:        cancel,
+	}
+
+	a.wg.Add(1)
+	go a.run(ctx)
+	return a
+}
+
+// record queues an audit event without blocking the request
+func (a *auditLog) record(event types.APIAuditEvent) {
+	select {
+	case a.events <- event:
+	default:
+		auditEventsDropped.Inc()
+	}
+}
+
+// run writes queued events whenever a batch fills up or the flush interval
+// passes, and writes what is left when stopped
+func (a *auditLog) run(ctx context.Context) {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]types.APIAuditEvent, 0, a.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		writeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := a.store.InsertAuditEvents(writeCtx, batch); err != nil {
+			a.logger.Error("Failed to write audit events", zap.Int("events", len(batch)), zap.Error(err))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case event := <-a.events:
+			batch = append(batch, event)
+			if len(batch) >= a.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-a.events:
+					batch = append(batch, event)
+					if len(batch) >= a.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// stop writes the queued events and stops the flush loop
+func (a *auditLog) stop() {
+	a.cancel()
+	a.wg.Wait()
+}
+
+// paramsHash hashes request parameters so that identical calls can be told
+// apart without storing addresses or other request details
+func paramsHash(params []string, query string, body []byte) string {
+	sort.Strings(params)
+
+	h := sha256.New()
+	for _, param := range params {
+		h.Write([]byte(param))
+		h.Write([]byte{0})
+	}
+	h.Write([]byte(query))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// auditREST records authenticated REST calls, including those the API key's
+// role denies
+func (s *Server) auditREST() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.audit == nil {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		p, _ := c.Request.Context().Value(principalContextKey{}).(principal)
+		params := make([]string, 0, len(c.Params))
+		for _, param := range c.Params {
+			params = append(params, param.Key+"="+param.Value)
+		}
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+
+		s.audit.record(types.APIAuditEvent{
+			Timestamp:     start.UTC(),
+			TenantID:      p.tenantID,
+			KeyID:         p.keyID,
+			Method:        c.Request.Method,
+			Endpoint:      c.FullPath(),
+			ParamsHash:    paramsHash(params, c.Request.URL.Query().Encode(), nil),
+			Status:        c.Writer.Status(),
+			LatencyMs:     float64(time.Since(start).Microseconds()) / 1000,
+			ResponseBytes: int64(size),
+			RequestID:     c.GetString(requestIDKey),
+		})
+	}
+}
+
+// auditWriter captures the status and size of a response
+type auditWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+// WriteHeader records the response status
+func (w *auditWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write counts the response bytes
+func (w *auditWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// auditGraphQL records authenticated GraphQL calls. The parameters hash
+// covers the query and variables.
+func (s *Server) auditGraphQL(next http.Handler) http.Handler {
+	if s.audit == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		var body []byte
+		if r.Body != nil {
+			var err error
+			if body, err = io.ReadAll(r.Body); err != nil {
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		aw := &auditWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(aw, r)
+
+		p, _ := r.Context().Value(principalContextKey{}).(principal)
+		s.audit.record(types.APIAuditEvent{
+			Timestamp:     start.UTC(),
+			TenantID:      p.tenantID,
+			KeyID:         p.keyID,
+			Method:        r.Method,
+			Endpoint:      graphqlEndpoint,
+			ParamsHash:    paramsHash(nil, r.URL.Query().Encode(), body),
+			Status:        aw.status,
+			LatencyMs:     float64(time.Since(start).Microseconds()) / 1000,
+			ResponseBytes: aw.size,
+			RequestID:     r.Header.Get(requestIDHeader),
+		})
+	})
+}
+
+// usageRange parses the from and to query parameters of usage reports, as
+// dates or RFC 3339 times, and checks that audit logging is enabled. It
+// defaults to the last 30 days and writes the error response on failure.
+func (s *Server) usageRange(c *gin.Context) (time.Time, time.Time, bool) {
+	if s.audit == nil {
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, "API audit logging is not enabled")
+		return time.Time{}, time.Time{}, false
+	}
+
+	to, err := timeParam(c, "to")
+	if err != nil {
+		s.badRequest(c, err.Error())
+		return time.Time{}, time.Time{}, false
+	}
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	from, err := timeParam(c, "from")
+	if err != nil {
+		s.badRequest(c, err.Error())
+		return time.Time{}, time.Time{}, false
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+	if !from.Before(to) {
+		s.badRequest(c, "from must be before to")
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
+
+// getUsage handles GET /api/v1/usage, the calling tenant's API usage per key,
+// endpoint and day
+func (s *Server) getUsage(c *gin.Context) {
+	s.usageReport(c, c.GetString(tenantIDKey))
+}
+
+// getAdminUsage handles GET /admin/v1/usage, API usage per tenant, key,
+// endpoint and day, optionally limited to one tenant
+func (s *Server) getAdminUsage(c *gin.Context) {
+	s.usageReport(c, c.Query("tenant"))
+}
+
+// usageReport writes the API usage of a tenant, or of every tenant when
+// tenantID is empty
+func (s *Server) usageReport(c *gin.Context, tenantID string) {
+	from, to, ok := s.usageRange(c)
+	if !ok {
+		return
+	}
+
+	usage, err := s.storage.ClickHouse().GetAPIUsage(c.Request.Context(), tenantID, from, to)
+	if err != nil {
+		s.logger.Error("Failed to get API usage", zap.String("tenant", tenantID), zap.Error(err))
+		s.storageError(c, err, "failed to get API usage")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":  from,
+		"to":    to,
+		"usage": usage,
+	})
+}
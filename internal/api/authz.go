@@ -0,0 +1,245 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	gql "github.com/99designs/gqlgen/graphql"
+	"github.com/cosmos/state-mesh/internal/authz"
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// restResources maps REST routes, relative to their API version, to the
+// resources they return
+var restResources = map[string]authz.Resource{
+	"/search":                                 {Endpoint: authz.EndpointSearch},
+	"/stream":                                 {Endpoint: authz.EndpointStream},
+	"/analytics/queries":                      {Endpoint: authz.EndpointAnalytics},
+	"/overview":                               {Endpoint: authz.EndpointStats},
+	"/accounts/:address/balances":             {Endpoint: authz.EndpointBalances, Modules: []string{"bank"}},
+	"/accounts/:address/delegations":          {Endpoint: authz.EndpointDelegations, Modules: []string{"staking"}},
+	"/accounts/:address/unbondings":           {Endpoint: authz.EndpointDelegations, Modules: []string{"staking"}},
+	"/accounts/:address/state":                {Endpoint: authz.EndpointAccountState, Modules: []string{"bank", "staking"}},
+	"/accounts/:address/activity":             {Endpoint: authz.EndpointEvents},
+	"/accounts/:address/rewards":              {Endpoint: authz.EndpointDelegations, Modules: []string{"distribution"}},
+	"/chains/":                                {Endpoint: authz.EndpointChains},
+	"/chains/:chain/accounts/:address/events": {Endpoint: authz.EndpointEvents},
+	"/chains/:chain/accounts/:address/reconstructed-balances": {Endpoint: authz.EndpointBalances, Modules: []string{"bank"}},
+	"/chains/:chain/accounts/:address/balance-history":        {Endpoint: authz.EndpointBalances, Modules: []string{"bank"}},
+	"/chains/:chain/analytics/:query":                         {Endpoint: authz.EndpointAnalytics},
+	"/chains/:chain/blocks":                                   {Endpoint: authz.EndpointBlocks},
+	"/chains/:chain/blocks/:height":                           {Endpoint: authz.EndpointBlocks},
+	"/chains/:chain/validators":                               {Endpoint: authz.EndpointValidators, Modules: []string{"staking"}},
+	"/chains/:chain/validators/:address/delegators":           {Endpoint: authz.EndpointValidatorDelegators, Modules: []string{"staking"}},
+	"/chains/:chain/validators/:address/commission-changes":   {Endpoint: authz.EndpointValidators, Modules: []string{"staking"}},
+	"/chains/:chain/consumer-validators":                      {Endpoint: authz.EndpointValidators, Modules: []string{"staking"}},
+	"/chains/:chain/validator-set":                            {Endpoint: authz.EndpointValidators, Modules: []string{"staking"}},
+	"/chains/:chain/stats":                                    {Endpoint: authz.EndpointStats},
+	"/chains/:chain/stats/active-addresses":                   {Endpoint: authz.EndpointStats, Modules: []string{"bank"}},
+	"/chains/:chain/stats/delegation-volume":                  {Endpoint: authz.EndpointStats, Modules: []string{"staking"}},
+	"/chains/:chain/stats/reward-issuance":                    {Endpoint: authz.EndpointStats, Modules: []string{"distribution"}},
+	"/chains/:chain/stats/fees":                               {Endpoint: authz.EndpointStats, Modules: []string{"blocks"}},
+	"/chains/:chain/stats/messages":                           {Endpoint: authz.EndpointStats, Modules: []string{"blocks"}},
+	"/chains/:chain/stats/epochs":                             {Endpoint: authz.EndpointStats, Modules: []string{"blocks"}},
+	"/chains/:chain/stats/base-fee":                           {Endpoint: authz.EndpointStats, Modules: []string{"feemarket"}},
+	"/chains/:chain/gas-prices":                               {Endpoint: authz.EndpointStats, Modules: []string{"feemarket"}},
+	"/chains/:chain/ibc/channels":                             {Endpoint: authz.EndpointStats, Modules: []string{"blocks"}},
+	"/chains/:chain/ibc/relayers":                             {Endpoint: authz.EndpointStats, Modules: []string{"blocks"}},
+	"/chains/:chain/ibc/stuck-packets":                        {Endpoint: authz.EndpointStats, Modules: []string{"blocks"}},
+	"/chains/:chain/stats/unbonding-schedule":                 {Endpoint: authz.EndpointStats, Modules: []string{"staking"}},
+	"/chains/:chain/stats/block-production":                   {Endpoint: authz.EndpointStats, Modules: []string{"staking"}},
+	"/chains/:chain/stats/delegation-flows":                   {Endpoint: authz.EndpointStats, Modules: []string{"staking"}},
+	"/chains/:chain/stats/anomalies":                          {Endpoint: authz.EndpointStats},
+	"/chains/:chain/stats/halts":                              {Endpoint: authz.EndpointStats},
+	"/chains/:chain/stats/top-holders":                        {Endpoint: authz.EndpointStats, Modules: []string{"bank"}},
+	"/chains/:chain/stats/supply":                             {Endpoint: authz.EndpointStats, Modules: []string{"bank", "auth", "distribution"}},
+	"/chains/:chain/supply/circulating":                       {Endpoint: authz.EndpointStats, Modules: []string{"bank", "auth"}},
+	"/chains/:chain/params/:module":                           {Endpoint: authz.EndpointParams},
+	"/chains/:chain/params/:module/changes":                   {Endpoint: authz.EndpointParams},
+	"/cross-chain/accounts/:address":                          {Endpoint: authz.EndpointCrossChain, Modules: []string{"bank", "staking"}},
+	"/cross-chain/validators":                                 {Endpoint: authz.EndpointCrossChain, Modules: []string{"staking"}},
+	"/cross-chain/upgrades":                                   {Endpoint: authz.EndpointCrossChain, Modules: []string{"upgrade", "gov"}},
+	"/cross-chain/params/:module":                             {Endpoint: authz.EndpointCrossChain},
+	"/cross-chain/gas-prices":                                 {Endpoint: authz.EndpointCrossChain, Modules: []string{"feemarket"}},
+	"/governance/proposals":                                   {Endpoint: authz.EndpointGovernance, Modules: []string{"gov"}},
+	"/governance/proposals/:id":                               {Endpoint: authz.EndpointGovernance, Modules: []string{"gov"}},
+	"/governance/proposals/:id/votes":                         {Endpoint: authz.EndpointGovernance, Modules: []string{"gov"}},
+	"/watchlists":                                             {Endpoint: authz.EndpointWatchlists},
+	"/watchlists/:id":                                         {Endpoint: authz.EndpointWatchlists},
+	"/webhooks":                                               {Endpoint: authz.EndpointWebhooks},
+	"/webhooks/:id":                                           {Endpoint: authz.EndpointWebhooks},
+	"/email-subscriptions":                                    {Endpoint: authz.EndpointEmailSubscriptions},
+	"/email-subscriptions/:id":                                {Endpoint: authz.EndpointEmailSubscriptions},
+	"/alert-rules":                                            {Endpoint: authz.EndpointAlertRules},
+	"/alert-rules/:id":                                        {Endpoint: authz.EndpointAlertRules},
+	"/digests":                                                {Endpoint: authz.EndpointDigests},
+	"/digests/:id":                                            {Endpoint: authz.EndpointDigests},
+	"/portfolios":                                             {Endpoint: authz.EndpointPortfolios},
+	"/portfolios/:id":                                         {Endpoint: authz.EndpointPortfolios},
+	"/portfolios/:id/summary":                                 {Endpoint: authz.EndpointPortfolios, Modules: []string{"bank", "staking", "distribution"}},
+	"/portfolios/:id/history":                                 {Endpoint: authz.EndpointPortfolios, Modules: []string{"bank", "staking"}},
+	"/usage":                                                  {Endpoint: authz.EndpointUsage},
+}
+
+// graphqlField describes what a GraphQL field returns for authorization
+type graphqlField struct {
+	authz.Resource
+
+	// chainArg is the argument naming the chain the field reads, if any
+	chainArg string
+
+	// chainsArg is the argument listing the chains the field reads, if any
+	chainsArg string
+
+	// moduleArg is the argument naming the module whose data the field
+	// returns, if any
+	moduleArg string
+}
+
+// graphqlFields maps GraphQL fields, as Type.field, to the resources they return
+var graphqlFields = map[string]graphqlField{
+	"Query.chains":               {Resource: authz.Resource{Endpoint: authz.EndpointChains}},
+	"Query.chain":                {Resource: authz.Resource{Endpoint: authz.EndpointChains}, chainArg: "name"},
+	"Query.account":              {Resource: authz.Resource{Endpoint: authz.EndpointAccountState}, chainArg: "chain"},
+	"Query.validatorDelegators":  {Resource: authz.Resource{Endpoint: authz.EndpointValidatorDelegators, Modules: []string{"staking"}}, chainArg: "chain"},
+	"Query.consumerValidators":   {Resource: authz.Resource{Endpoint: authz.EndpointValidators, Modules: []string{"staking"}}, chainArg: "chain"},
+	"Query.compareParams":        {Resource: authz.Resource{Endpoint: authz.EndpointParams}, chainsArg: "chains", moduleArg: "module"},
+	"Query.accountEvents":        {Resource: authz.Resource{Endpoint: authz.EndpointEvents, Modules: []string{"bank", "staking"}}, chainArg: "chain"},
+	"AccountState.balances":      {Resource: authz.Resource{Endpoint: authz.EndpointBalances, Modules: []string{"bank"}}},
+	"AccountState.delegations":   {Resource: authz.Resource{Endpoint: authz.EndpointDelegations, Modules: []string{"staking"}}},
+	"AccountState.unbonding":     {Resource: authz.Resource{Endpoint: authz.EndpointDelegations, Modules: []string{"staking"}}},
+	"AccountState.redelegations": {Resource: authz.Resource{Endpoint: authz.EndpointDelegations, Modules: []string{"staking"}}},
+	"AccountState.rewards":       {Resource: authz.Resource{Endpoint: authz.EndpointDelegations, Modules: []string{"distribution"}}},
+}
+
+// graphqlEntity is the resource a federated entity type returns and its key
+// field naming the entity's chain
+type graphqlEntity struct {
+	authz.Resource
+	chainField string
+}
+
+// graphqlEntities maps the entity types resolved by the federation _entities
+// field to the resources they return
+var graphqlEntities = map[string]graphqlEntity{
+	"AccountState": {Resource: authz.Resource{Endpoint: authz.EndpointAccountState}, chainField: "chainName"},
+	"ChainInfo":    {Resource: authz.Resource{Endpoint: authz.EndpointChains}, chainField: "name"},
+	"Validator":    {Resource: authz.Resource{Endpoint: authz.EndpointValidators, Modules: []string{"staking"}}, chainField: "chainName"},
+}
+
+// rolePolicy returns the access policy of a role. Unrestricted keys have no
+// role; keys whose role was removed from the configuration are denied.
+func (s *Server) rolePolicy(role string) *authz.Policy {
+	if role == "" {
+		return nil
+	}
+	if policy, ok := s.roles[role]; ok {
+		return policy
+	}
+	return authz.DenyAll(role)
+}
+
+// authorize enforces the access policy of the request's API key on REST routes.
+// Routes that default to every chain narrow themselves to the allowed chains.
+func (s *Server) authorize() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy := policyFromContext(c.Request.Context())
+		path := versionedRoute(c)
+		if policy == nil || path == "" {
+			c.Next()
+			return
+		}
+
+		resource, ok := restResources[path]
+		if !ok {
+			s.abortWithError(c, http.StatusForbidden, CodePermissionDenied, "route is not available to restricted API keys")
+			return
+		}
+
+		if err := policy.Authorize(resource, requestChains(c)); err != nil {
+			s.abortWithError(c, http.StatusForbidden, CodePermissionDenied, err.Error())
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestChains returns the chains a REST request names in its path or query
+func requestChains(c *gin.Context) []string {
+	var chains []string
+	if chain := c.Param("chain"); chain != "" {
+		chains = append(chains, chain)
+	}
+	chains = append(chains, splitChains(c.Query("chain"))...)
+	for _, param := range c.QueryArray("chains") {
+		chains = append(chains, splitChains(param)...)
+	}
+	return chains
+}
+
+// authorizeField enforces the access policy of the request's API key on
+// GraphQL fields
+func (s *Server) authorizeField(ctx context.Context, next gql.Resolver) (any, error) {
+	policy := policyFromContext(ctx)
+	if policy == nil {
+		return next(ctx)
+	}
+
+	fc := gql.GetFieldContext(ctx)
+	if fc.Object == "Query" && fc.Field.Name == "_entities" {
+		if err := authorizeEntities(policy, fc.Args["representations"]); err != nil {
+			return nil, permissionDenied(err)
+		}
+		return next(ctx)
+	}
+
+	field, ok := graphqlFields[fc.Object+"."+fc.Field.Name]
+	if !ok {
+		return next(ctx)
+	}
+
+	var chains []string
+	if chain, ok := fc.Args[field.chainArg].(string); ok {
+		chains = append(chains, chain)
+	}
+	if list, ok := fc.Args[field.chainsArg].([]string); ok {
+		chains = append(chains, list...)
+	}
+	resource := field.Resource
+	if module, ok := fc.Args[field.moduleArg].(string); ok {
+		resource.Modules = append(slices.Clone(resource.Modules), module)
+	}
+	if err := policy.Authorize(resource, chains); err != nil {
+		return nil, permissionDenied(err)
+	}
+
+	return next(ctx)
+}
+
+// authorizeEntities checks that a policy allows every entity a federation
+// _entities query asks for by its representation
+func authorizeEntities(policy *authz.Policy, representations any) error {
+	list, _ := representations.([]map[string]any)
+	for _, representation := range list {
+		typename, _ := representation["__typename"].(string)
+		entity, ok := graphqlEntities[typename]
+		if !ok {
+			continue // not an entity of this subgraph, so not resolved
+		}
+		chain, _ := representation[entity.chainField].(string)
+		if err := policy.Authorize(entity.Resource, []string{chain}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// permissionDenied converts a policy denial to a GraphQL error
+func permissionDenied(err error) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    err.Error(),
+		Extensions: map[string]any{"code": CodePermissionDenied},
+	}
+}
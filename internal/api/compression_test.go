@@ -0,0 +1,29 @@
+package api
+
+import "testing"
+
+func TestAcceptedEncoding(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "none", header: "", want: ""},
+		{name: "gzip", header: "gzip", want: encodingGzip},
+		{name: "zstd preferred", header: "gzip, zstd", want: encodingZstd},
+		{name: "zstd refused", header: "zstd;q=0, gzip", want: encodingGzip},
+		{name: "unsupported", header: "br, deflate", want: ""},
+		{name: "wildcard", header: "*", want: encodingZstd},
+		{name: "wildcard refused", header: "*;q=0", want: ""},
+		{name: "refused before wildcard", header: "zstd;q=0, *", want: encodingGzip},
+		{name: "refused after wildcard", header: "*, zstd;q=0, gzip;q=0", want: ""},
+		{name: "listed over refused wildcard", header: "gzip, *;q=0", want: encodingGzip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acceptedEncoding(tt.header); got != tt.want {
+				t.Errorf("acceptedEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
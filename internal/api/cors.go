@@ -0,0 +1,141 @@
+package api
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// corsPolicy decides which origins may call the API from a browser and which
+// CORS headers they receive
+type corsPolicy struct {
+	anyOrigin   bool
+	origins     map[string]bool
+	patterns    []string
+	methods     string
+	headers     string
+	exposed     string
+	credentials bool
+	maxAge      string
+}
+
+// newCORSPolicy creates the CORS policy of the configuration
+func newCORSPolicy(cfg config.CORSConfig) *corsPolicy {
+	p := &corsPolicy{
+		origins:     make(map[string]bool),
+		methods:     strings.Join(cfg.Methods, ", "),
+		headers:     strings.Join(cfg.Headers, ", "),
+		exposed:     strings.Join(cfg.ExposedHeaders, ", "),
+		credentials: cfg.AllowCredentials,
+	}
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+
+	for _, origin := range cfg.Origins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			p.patterns = append(p.patterns, origin)
+		case origin != "":
+			p.origins[origin] = true
+		}
+	}
+
+	return p
+}
+
+// allowOrigin reports whether an origin matches the allowlist
+func (p *corsPolicy) allowOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if p.origins[origin] {
+		return true
+	}
+	for _, pattern := range p.patterns {
+		if ok, _ := path.Match(pattern, origin); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// apply sets the CORS headers of a response and reports whether the request
+// is a preflight that must not reach the handlers
+func (p *corsPolicy) apply(h http.Header, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+	// Responses differ by origin unless every origin gets the same wildcard
+	if !p.anyOrigin || p.credentials {
+		h.Add("Vary", "Origin")
+	}
+	if origin == "" || !p.allowOrigin(origin) {
+		return preflight
+	}
+
+	// Credentialed requests may not use the wildcard, so the origin is echoed
+	if p.anyOrigin && !p.credentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if p.credentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+
+	if !preflight {
+		if p.exposed != "" {
+			h.Set("Access-Control-Expose-Headers", p.exposed)
+		}
+		return false
+	}
+
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	if p.methods != "" {
+		h.Set("Access-Control-Allow-Methods", p.methods)
+	}
+	if p.headers != "" {
+		h.Set("Access-Control-Allow-Headers", p.headers)
+	}
+	if p.maxAge != "" {
+		h.Set("Access-Control-Max-Age", p.maxAge)
+	}
+	return true
+}
+
+// corsMiddleware adds CORS headers and answers preflight requests
+func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+	if s.cors == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.cors.apply(w.Header(), r) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ginCORS adds CORS headers and answers preflight requests for Gin
+func (s *Server) ginCORS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.cors.apply(c.Writer.Header(), c.Request) {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// arrayFlushEvery is how many array elements are written between flushes of
+// a streamed response
+const arrayFlushEvery = 100
+
+// arrayWriter writes a JSON object whose last field is an array, encoding each
+// element as storage reads it instead of materializing the whole list, so
+// memory stays flat however large the list is. The status and the leading
+// fields are only sent with the first element, so that a failure before it
+// can still be answered with an error response.
+type arrayWriter struct {
+	c       *gin.Context
+	head    []byte
+	count   int
+	started bool
+}
+
+// newArrayWriter returns a writer for an object with the given leading fields
+// followed by the array field name
+func newArrayWriter(c *gin.Context, fields gin.H, name string) (*arrayWriter, error) {
+	head, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	key, err := json.Marshal(name)
+	if err != nil {
+		return nil, err
+	}
+
+	head = head[:len(head)-1]
+	if len(fields) > 0 {
+		head = append(head, ',')
+	}
+	head = append(head, key...)
+	head = append(head, ':', '[')
+	return &arrayWriter{c: c, head: head}, nil
+}
+
+// Started reports whether the response has been sent, after which errors can
+// no longer change its status
+func (w *arrayWriter) Started() bool {
+	return w.started
+}
+
+// start sends the status and the object up to the array's opening bracket
+func (w *arrayWriter) start() error {
+	w.started = true
+	w.c.Header("Content-Type", "application/json; charset=utf-8")
+	w.c.Status(http.StatusOK)
+	_, err := w.c.Writer.Write(w.head)
+	return err
+}
+
+// Write encodes an element of the array
+func (w *arrayWriter) Write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	} else {
+		data = append([]byte{','}, data...)
+	}
+	if _, err := w.c.Writer.Write(data); err != nil {
+		return err
+	}
+
+	w.count++
+	if w.count%arrayFlushEvery == 0 {
+		w.c.Writer.Flush()
+	}
+	return nil
+}
+
+// Close ends the array and the object. A response abandoned after an error is
+// left unterminated, so that clients fail to parse it rather than taking a
+// partial list for a complete one.
+func (w *arrayWriter) Close() error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	_, err := w.c.Writer.Write([]byte("]}"))
+	return err
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// Machine-readable error codes returned in the error envelope
+const (
+	CodeInvalidArgument  = "INVALID_ARGUMENT"
+	CodeUnauthenticated  = "UNAUTHENTICATED"
+	CodePermissionDenied = "PERMISSION_DENIED"
+	CodeNotFound         = "NOT_FOUND"
+	CodeAlreadyExists    = "ALREADY_EXISTS"
+	CodeInternal         = "INTERNAL"
+	CodeUnavailable      = "UNAVAILABLE"
+	CodeDeadlineExceeded = "DEADLINE_EXCEEDED"
+)
+
+// requestIDHeader is the header used to propagate request IDs
+const requestIDHeader = "X-Request-ID"
+
+// requestIDKey is the Gin context key holding the request ID
+const requestIDKey = "request_id"
+
+// ErrorResponse is the standard error envelope for REST responses
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes an API error
+type ErrorBody struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+	Retryable bool   `json:"retryable"`
+}
+
+// requestIDMiddleware attaches a request ID to every request, reusing the
+// caller-supplied X-Request-ID header when present
+func (s *Server) requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Header(requestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// newRequestID generates a random request ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// abortWithError writes the error envelope and aborts the request
+func (s *Server) abortWithError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, ErrorResponse{
+		Error: ErrorBody{
+			Code:      code,
+			Message:   message,
+			RequestID: c.GetString(requestIDKey),
+			Retryable: status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout,
+		},
+	})
+}
+
+// badRequest writes an INVALID_ARGUMENT error
+func (s *Server) badRequest(c *gin.Context, message string) {
+	s.abortWithError(c, http.StatusBadRequest, CodeInvalidArgument, message)
+}
+
+// storageError maps a storage failure to the matching HTTP status: missing
+// entities become 404, name clashes 409, timeouts 504 and everything else 500
+func (s *Server) storageError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		s.abortWithError(c, http.StatusNotFound, CodeNotFound, message)
+	case errors.Is(err, storage.ErrExists):
+		s.abortWithError(c, http.StatusConflict, CodeAlreadyExists, message)
+	case errors.Is(err, context.DeadlineExceeded):
+		s.abortWithError(c, http.StatusGatewayTimeout, CodeDeadlineExceeded, message)
+	case errors.Is(err, context.Canceled), errors.Is(err, storage.ErrUnavailable):
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, message)
+	default:
+		s.abortWithError(c, http.StatusInternalServerError, CodeInternal, message)
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// getGasPrices handles GET /api/v1/chains/:chain/gas-prices: low, average and
+// high gas prices in each denom of the chain's fee market, estimated from the
+// base fees the feemarket module recorded over the gas price window
+func (s *Server) getGasPrices(c *gin.Context) {
+	chainName := c.Param("chain")
+	window := s.cfg.GasPrices.Window
+
+	estimates, err := s.storage.GetGasPriceEstimates(c.Request.Context(), chainName, window)
+	if err != nil {
+		s.logger.Error("Failed to get gas price estimates",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get gas price estimates")
+		return
+	}
+	if len(estimates) == 0 {
+		s.abortWithError(c, http.StatusNotFound, CodeNotFound, "no base fee recorded for the chain in the last "+window.String())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":     chainName,
+		"window":    window.String(),
+		"estimates": estimates,
+	})
+}
+
+// getBaseFeeHistory handles GET /api/v1/chains/:chain/stats/base-fee: the
+// lowest, mean and highest base fee of each denom per hour over the last
+// hours
+func (s *Server) getBaseFeeHistory(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil || hours <= 0 || hours > 24*90 {
+		s.badRequest(c, "hours must be between 1 and 2160")
+		return
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	history, err := s.storage.GetBaseFeeHistory(c.Request.Context(), chainName, since)
+	if err != nil {
+		s.logger.Error("Failed to get base fee history",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get base fee history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":   chainName,
+		"since":   since,
+		"history": history,
+	})
+}
+
+// getCrossChainGasPrices handles GET /api/v1/cross-chain/gas-prices: the gas
+// price estimates of the given chains, or of every enabled chain with the
+// feemarket module the API key may read, for estimating gas on many chains
+// from one place
+func (s *Server) getCrossChainGasPrices(c *gin.Context) {
+	var chains []string
+	for _, param := range c.QueryArray("chains") {
+		chains = append(chains, splitChains(param)...)
+	}
+	for _, chainName := range chains {
+		if err := s.validateChain(chainName); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	if len(chains) == 0 {
+		allowed := policyFromContext(c.Request.Context()).Chains()
+		for _, chain := range s.chains {
+			if chain.Enabled && chain.ModuleEnabled("feemarket") && (allowed == nil || slices.Contains(allowed, chain.Name)) {
+				chains = append(chains, chain.Name)
+			}
+		}
+	}
+
+	window := s.cfg.GasPrices.Window
+	estimates, chainErrors := s.storage.GetCrossChainGasPriceEstimates(c.Request.Context(), chains, window, s.cfg.ChainTimeout)
+	for _, chainErr := range chainErrors {
+		s.logger.Warn("Failed to get gas price estimates for cross-chain query",
+			zap.String("chain", chainErr.ChainName),
+			zap.String("error", chainErr.Error))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"window":    window.String(),
+		"estimates": estimates,
+		"errors":    chainErrors,
+	})
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Bounds of the interval of charted points: Grafana's interval is widened to
+// keep a query to the panel's maxDataPoints, and at most grafanaMaxPoints,
+// points per series
+const (
+	grafanaMinInterval = time.Minute
+	grafanaMaxPoints   = 2000
+)
+
+// grafanaMetricLabels are the names of the charted metrics in Grafana
+var grafanaMetricLabels = map[string]string{
+	types.SeriesIngestLag:       "Ingest lag",
+	types.SeriesDelegationFlows: "Delegation flows",
+	types.SeriesSupply:          "Supply",
+}
+
+// grafanaRange is the time range of a Grafana query or annotation request
+type grafanaRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// grafanaTarget is one query of a Grafana panel. Payload holds the chain and,
+// for the supply, the denom.
+type grafanaTarget struct {
+	RefID   string            `json:"refId"`
+	Target  string            `json:"target"`
+	Payload map[string]string `json:"payload"`
+}
+
+// grafanaSeries is a time series in the JSON datasource format, its
+// datapoints being [value, unix milliseconds] pairs
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	RefID      string       `json:"refId,omitempty"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+// grafanaAnnotation is an annotation in the JSON datasource format
+type grafanaAnnotation struct {
+	Time     int64    `json:"time"`
+	TimeEnd  int64    `json:"timeEnd,omitempty"` // the end of a region
+	IsRegion bool     `json:"isRegion,omitempty"`
+	Title    string   `json:"title"`
+	Text     string   `json:"text"`
+	Tags     []string `json:"tags"`
+}
+
+// grafanaHealth handles GET /api/v1/grafana/, which the JSON datasource calls
+// to test the connection
+func (s *Server) grafanaHealth(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+// grafanaMetrics handles POST /api/v1/grafana/metrics, listing the metrics a
+// panel can chart and their chain and denom options
+func (s *Server) grafanaMetrics(c *gin.Context) {
+	chains := make([]gin.H, 0, len(s.chains))
+	for _, chain := range s.chains {
+		if chain.Enabled {
+			chains = append(chains, gin.H{"label": chain.Name, "value": chain.Name})
+		}
+	}
+
+	metrics := make([]gin.H, 0, len(types.SeriesMetrics))
+	for _, metric := range types.SeriesMetrics {
+		payloads := []gin.H{{"label": "Chain", "name": "chain", "type": "select", "options": chains}}
+		if metric == types.SeriesSupply {
+			payloads = append(payloads, gin.H{"label": "Denom", "name": "denom", "type": "input",
+				"placeholder": "staking denom"})
+		}
+		metrics = append(metrics, gin.H{"label": grafanaMetricLabels[metric], "value": metric, "payloads": payloads})
+	}
+	c.JSON(http.StatusOK, metrics)
+}
+
+// grafanaVariable handles POST /api/v1/grafana/variable, listing the enabled
+// chains for a dashboard variable
+func (s *Server) grafanaVariable(c *gin.Context) {
+	values := make([]gin.H, 0, len(s.chains))
+	for _, chain := range s.chains {
+		if chain.Enabled {
+			values = append(values, gin.H{"__text": chain.Name, "__value": chain.Name})
+		}
+	}
+	c.JSON(http.StatusOK, values)
+}
+
+// grafanaQuery handles POST /api/v1/grafana/query, returning the series of
+// each target over the requested range. A target without a chain payload
+// charts the chain named by its target as "metric:chain".
+func (s *Server) grafanaQuery(c *gin.Context) {
+	var req struct {
+		Range         grafanaRange    `json:"range"`
+		IntervalMs    int64           `json:"intervalMs"`
+		MaxDataPoints int64           `json:"maxDataPoints"`
+		Targets       []grafanaTarget `json:"targets"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.badRequest(c, "invalid query: "+err.Error())
+		return
+	}
+	if !req.Range.To.After(req.Range.From) {
+		s.badRequest(c, "range.to must be after range.from")
+		return
+	}
+	interval := grafanaInterval(req.Range, time.Duration(req.IntervalMs)*time.Millisecond, req.MaxDataPoints)
+
+	result := []grafanaSeries{}
+	for _, target := range req.Targets {
+		metric, chainName := target.Target, target.Payload["chain"]
+		if name, chain, ok := strings.Cut(metric, ":"); ok && chainName == "" {
+			metric, chainName = name, chain
+		}
+		if metric == "" {
+			continue
+		}
+		if !slices.Contains(types.SeriesMetrics, metric) {
+			s.badRequest(c, fmt.Sprintf("target %s: metric must be one of %s", target.RefID, strings.Join(types.SeriesMetrics, ", ")))
+			return
+		}
+		if err := s.validateChain(chainName); err != nil {
+			s.badRequest(c, fmt.Sprintf("target %s: %s", target.RefID, err.Error()))
+			return
+		}
+		denom := target.Payload["denom"]
+		if denom == "" {
+			chain, _ := s.chainConfig(chainName)
+			denom = chain.StakingDenom()
+		}
+
+		series, err := s.storage.GetTimeSeries(c.Request.Context(), metric, chainName, denom, req.Range.From, req.Range.To, interval)
+		if err != nil {
+			s.logger.Error("Failed to get time series",
+				zap.String("chain", chainName),
+				zap.String("metric", metric),
+				zap.Error(err))
+			s.storageError(c, err, "failed to get time series")
+			return
+		}
+		for _, ts := range series {
+			datapoints := make([][2]float64, len(ts.Points))
+			for i, point := range ts.Points {
+				datapoints[i] = [2]float64{point.Value, float64(point.Time.UnixMilli())}
+			}
+			result = append(result, grafanaSeries{
+				Target:     chainName + " " + ts.Name,
+				RefID:      target.RefID,
+				Datapoints: datapoints,
+			})
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// grafanaAnnotations handles POST /api/v1/grafana/annotations, marking the
+// anomalies detected in the requested range and, as regions explaining gaps
+// in the charts, the chain halts overlapping it. The annotation query names
+// the chains, comma separated; every enabled chain when empty.
+func (s *Server) grafanaAnnotations(c *gin.Context) {
+	var req struct {
+		Range      grafanaRange `json:"range"`
+		Annotation struct {
+			Query string `json:"query"`
+		} `json:"annotation"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.badRequest(c, "invalid annotation query: "+err.Error())
+		return
+	}
+
+	chains := splitChains(req.Annotation.Query)
+	if len(chains) == 0 {
+		for _, chain := range s.chains {
+			if chain.Enabled {
+				chains = append(chains, chain.Name)
+			}
+		}
+	}
+	for _, chain := range chains {
+		if err := s.validateChain(chain); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	annotations := []grafanaAnnotation{}
+	for _, chain := range chains {
+		anomalies, err := s.storage.Anomalies().GetAnomalies(c.Request.Context(), chain, req.Range.From)
+		if err != nil {
+			s.logger.Error("Failed to get anomalies", zap.String("chain", chain), zap.Error(err))
+			s.storageError(c, err, "failed to get anomalies")
+			return
+		}
+		for _, anomaly := range anomalies {
+			if !req.Range.To.IsZero() && !anomaly.Hour.Before(req.Range.To) {
+				continue
+			}
+			annotations = append(annotations, grafanaAnnotation{
+				Time:  anomaly.Hour.UnixMilli(),
+				Title: fmt.Sprintf("%s %s anomaly", chain, anomaly.Metric),
+				Text: fmt.Sprintf("%s was %g against an expected %g (z-score %.1f)",
+					anomaly.Metric, anomaly.Value, anomaly.Expected, anomaly.ZScore),
+				Tags: []string{chain, "anomaly", anomaly.Metric},
+			})
+		}
+
+		halts, err := s.storage.Halts().GetHalts(c.Request.Context(), chain, req.Range.From)
+		if err != nil {
+			s.logger.Error("Failed to get chain halts", zap.String("chain", chain), zap.Error(err))
+			s.storageError(c, err, "failed to get chain halts")
+			return
+		}
+		for _, halt := range halts {
+			if !req.Range.To.IsZero() && !halt.Since.Before(req.Range.To) {
+				continue
+			}
+			annotation := grafanaAnnotation{
+				Time:     halt.Since.UnixMilli(),
+				TimeEnd:  time.Now().UnixMilli(),
+				IsRegion: true,
+				Title:    fmt.Sprintf("%s halted", chain),
+				Text:     fmt.Sprintf("No block after height %d", halt.Height),
+				Tags:     []string{chain, "halt"},
+			}
+			if halt.ResumedAt != nil {
+				annotation.TimeEnd = halt.ResumedAt.UnixMilli()
+				annotation.Text += fmt.Sprintf(" for %s", halt.ResumedAt.Sub(halt.Since).Round(time.Second))
+			}
+			annotations = append(annotations, annotation)
+		}
+	}
+	c.JSON(http.StatusOK, annotations)
+}
+
+// grafanaInterval returns the interval to chart a range at: Grafana's, at
+// least grafanaMinInterval and wide enough for maxPoints points
+func grafanaInterval(r grafanaRange, interval time.Duration, maxPoints int64) time.Duration {
+	if maxPoints <= 0 || maxPoints > grafanaMaxPoints {
+		maxPoints = grafanaMaxPoints
+	}
+	interval = max(interval, grafanaMinInterval)
+	if span := r.To.Sub(r.From); int64(span/interval) > maxPoints {
+		interval = span / time.Duration(maxPoints)
+	}
+	return interval.Truncate(time.Second)
+}
@@ -1,9 +1,15 @@
 package api
 
 import (
+	"math/big"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
 	"github.com/cosmos/state-mesh/pkg/types"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -12,259 +18,1214 @@ import (
 // getAccountBalances handles GET /api/v1/accounts/:address/balances
 func (s *Server) getAccountBalances(c *gin.Context) {
 	address := c.Param("address")
-	chainName := c.Query("chain")
+	refs := accountRefsFromContext(c)
+
+	balances, err := s.accounts.GetAccountBalances(c.Request.Context(), refs)
+	if err != nil {
+		s.logger.Error("Failed to get balances",
+			zap.String("address", address),
+			zap.Strings("chains", refChains(refs)),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get balances")
+		return
+	}
+	s.setDisplayAmounts(balances)
+
+	c.JSON(http.StatusOK, gin.H{
+		"chains":   refChains(refs),
+		"address":  address,
+		"balances": balances,
+	})
+}
+
+// getAccountDelegations handles GET /api/v1/accounts/:address/delegations
+func (s *Server) getAccountDelegations(c *gin.Context) {
+	address := c.Param("address")
+	refs := accountRefsFromContext(c)
+
+	delegations, err := s.accounts.GetAccountDelegations(c.Request.Context(), refs)
+	if err != nil {
+		s.logger.Error("Failed to get delegations",
+			zap.String("address", address),
+			zap.Strings("chains", refChains(refs)),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get delegations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chains":      refChains(refs),
+		"address":     address,
+		"delegations": delegations,
+	})
+}
+
+// getAccountUnbondings handles GET /api/v1/accounts/:address/unbondings. It
+// returns the address's pending unbonding entries on the requested chains,
+// soonest completion first.
+func (s *Server) getAccountUnbondings(c *gin.Context) {
+	address := c.Param("address")
+	refs := accountRefsFromContext(c)
+
+	unbondings, err := s.accounts.GetAccountUnbondings(c.Request.Context(), refs)
+	if err != nil {
+		s.logger.Error("Failed to get unbondings",
+			zap.String("address", address),
+			zap.Strings("chains", refChains(refs)),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get unbondings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chains":     refChains(refs),
+		"address":    address,
+		"unbondings": unbondings,
+	})
+}
+
+// getAccountState handles GET /api/v1/accounts/:address/state, listing the
+// account's state on each requested chain, even when only one is requested
+func (s *Server) getAccountState(c *gin.Context) {
+	address := c.Param("address")
+	refs := accountRefsFromContext(c)
+
+	states, err := s.accounts.GetAccountStates(c.Request.Context(), refs)
+	if err != nil {
+		s.logger.Error("Failed to get account state",
+			zap.String("address", address),
+			zap.Strings("chains", refChains(refs)),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get account state")
+		return
+	}
+	for _, state := range states {
+		s.setDisplayAmounts(state.Balances)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"address":  address,
+		"accounts": states,
+	})
+}
+
+// getAccountRewards handles GET /api/v1/accounts/:address/rewards, summarizing
+// the rewards the delegator withdrew and claimed on each requested chain and
+// whether its claims look auto-compounded
+func (s *Server) getAccountRewards(c *gin.Context) {
+	address := c.Param("address")
+	refs := accountRefsFromContext(c)
+
+	rewards, err := s.accounts.GetAccountRewards(c.Request.Context(), refs)
+	if err != nil {
+		s.logger.Error("Failed to get reward claims",
+			zap.String("address", address),
+			zap.Strings("chains", refChains(refs)),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get reward claims")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"address": address,
+		"rewards": rewards,
+	})
+}
+
+// refChains returns the chain names of the given accounts
+func refChains(refs []storage.AccountRef) []string {
+	chains := make([]string, len(refs))
+	for i, ref := range refs {
+		chains[i] = ref.ChainName
+	}
+	return chains
+}
+
+// setDisplayAmounts converts the balances in their chain's base denom to
+// display units
+func (s *Server) setDisplayAmounts(balances []types.Balance) {
+	for i := range balances {
+		chain, _ := s.chainConfig(balances[i].ChainName)
+		balances[i].DisplayAmount, _ = chain.DisplayAmount(balances[i].Denom, balances[i].Amount)
+	}
+}
+
+// getChains handles GET /api/v1/chains
+func (s *Server) getChains(c *gin.Context) {
+	chains, err := s.storage.GetChains(c.Request.Context())
+	if err != nil {
+		s.logger.Error("Failed to get chains", zap.Error(err))
+		s.storageError(c, err, "failed to get chains")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chains": chains,
+	})
+}
+
+// getBlocks handles GET /api/v1/chains/:chain/blocks
+func (s *Server) getBlocks(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		s.badRequest(c, "limit must be between 1 and 100")
+		return
+	}
+
+	before, err := strconv.ParseInt(c.DefaultQuery("before", "0"), 10, 64)
+	if err != nil || before < 0 {
+		s.badRequest(c, "before must be a non-negative block height")
+		return
+	}
+
+	blocks, err := s.storage.State().GetBlocks(c.Request.Context(), chainName, before, limit)
+	if err != nil {
+		s.logger.Error("Failed to get blocks",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get blocks")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":  chainName,
+		"blocks": blocks,
+	})
+}
+
+// getBlock handles GET /api/v1/chains/:chain/blocks/:height
+func (s *Server) getBlock(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	height, err := strconv.ParseInt(c.Param("height"), 10, 64)
+	if err != nil || height <= 0 {
+		s.badRequest(c, "invalid block height")
+		return
+	}
+
+	block, err := s.storage.State().GetBlock(c.Request.Context(), chainName, height)
+	if err != nil {
+		s.logger.Error("Failed to get block",
+			zap.String("chain", chainName),
+			zap.Int64("height", height),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get block")
+		return
+	}
+
+	c.JSON(http.StatusOK, block)
+}
+
+// getValidators handles GET /api/v1/chains/:chain/validators. Validators are
+// encoded as they are read from storage rather than collected first.
+func (s *Server) getValidators(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	w, err := newArrayWriter(c, gin.H{"chain": chainName}, "validators")
+	if err != nil {
+		s.abortWithError(c, http.StatusInternalServerError, CodeInternal, "failed to encode validators")
+		return
+	}
+
+	err = s.validators.EachValidator(c.Request.Context(), chainName, func(validator *types.Validator) error {
+		return w.Write(validator)
+	})
+	if err != nil {
+		s.logger.Error("Failed to get validators",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		if !w.Started() {
+			s.storageError(c, err, "failed to get validators")
+		}
+		return
+	}
+	w.Close()
+}
+
+// getConsumerValidators handles GET /api/v1/chains/:chain/consumer-validators
+func (s *Server) getConsumerValidators(c *gin.Context) {
+	chainName := c.Param("chain")
+	chain, _ := s.chainConfig(chainName)
+
+	set, err := s.validators.GetConsumerValidatorSet(c.Request.Context(), chain.ChainID)
+	if err != nil {
+		s.logger.Error("Failed to get consumer validators",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get consumer validators")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":          chainName,
+		"provider_chain": set.ProviderChain,
+		"chain_id":       set.ChainID,
+		"consumer_id":    set.ConsumerID,
+		"validators":     set.Validators,
+		"stake_at_risk":  set.StakeAtRisk,
+		"height":         set.Height,
+		"updated_at":     set.UpdatedAt,
+	})
+}
+
+// getValidatorSet handles GET /api/v1/chains/:chain/validator-set. Without a
+// height the latest snapshot is returned; otherwise the latest snapshot at or
+// below it, whose height is snapshot_height.
+func (s *Server) getValidatorSet(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	height, err := strconv.ParseInt(c.DefaultQuery("height", "0"), 10, 64)
+	if err != nil || height < 0 {
+		s.badRequest(c, "height must be a non-negative block height")
+		return
+	}
+
+	set, err := s.validators.GetValidatorSetAt(c.Request.Context(), chainName, height)
+	if err != nil {
+		s.logger.Error("Failed to get validator set",
+			zap.String("chain", chainName),
+			zap.Int64("height", height),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get validator set")
+		return
+	}
+	if height == 0 {
+		height = set.Height
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":              chainName,
+		"height":             height,
+		"snapshot_height":    set.Height,
+		"timestamp":          set.Timestamp,
+		"total_voting_power": set.TotalVotingPower,
+		"validators":         set.Validators,
+	})
+}
+
+// getValidatorDelegators handles GET /api/v1/chains/:chain/validators/:address/delegators
+func (s *Server) getValidatorDelegators(c *gin.Context) {
+	chainName := c.Param("chain")
+	validatorAddress := c.Param("address")
+
+	page, ok := s.pagination(c)
+	if !ok {
+		return
+	}
+
+	delegators, total, err := s.validators.GetValidatorDelegators(c.Request.Context(), chainName, validatorAddress, page.Limit, page.Offset)
+	if err != nil {
+		s.logger.Error("Failed to get validator delegators",
+			zap.String("chain", chainName),
+			zap.String("validator", validatorAddress),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get validator delegators")
+		return
+	}
+	page.Total = total
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":      chainName,
+		"validator":  validatorAddress,
+		"delegators": delegators,
+		"pagination": page,
+	})
+}
+
+// getValidatorCommissionChanges handles
+// GET /api/v1/chains/:chain/validators/:address/commission-changes. It returns
+// the validator's latest commission rate changes, newest first.
+func (s *Server) getValidatorCommissionChanges(c *gin.Context) {
+	chainName := c.Param("chain")
+	validatorAddress := c.Param("address")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		s.badRequest(c, "limit must be between 1 and 100")
+		return
+	}
+
+	changes, err := s.validators.GetCommissionChanges(c.Request.Context(), chainName, validatorAddress, limit)
+	if err != nil {
+		s.logger.Error("Failed to get commission changes",
+			zap.String("chain", chainName),
+			zap.String("validator", validatorAddress),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get commission changes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":              chainName,
+		"validator":          validatorAddress,
+		"commission_changes": changes,
+	})
+}
+
+// pagination parses the limit and offset query parameters
+func (s *Server) pagination(c *gin.Context) (types.Page, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		s.badRequest(c, "limit must be between 1 and 1000")
+		return types.Page{}, false
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		s.badRequest(c, "offset must be a non-negative integer")
+		return types.Page{}, false
+	}
+
+	return types.Page{Limit: limit, Offset: offset}, true
+}
+
+// getChainStats handles GET /api/v1/chains/:chain/stats
+func (s *Server) getChainStats(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	stats, err := s.analytics.GetChainStats(c.Request.Context(), chainName)
+	if err != nil {
+		s.logger.Error("Failed to get chain stats",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get chain stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// getDailyActiveAddresses handles GET /api/v1/chains/:chain/stats/active-addresses
+func (s *Server) getDailyActiveAddresses(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	days, ok := s.statsDays(c)
+	if !ok {
+		return
+	}
+
+	stats, err := s.analytics.GetDailyActiveAddresses(c.Request.Context(), chainName, days)
+	if err != nil {
+		s.logger.Error("Failed to get daily active addresses",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get daily active addresses")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain": chainName,
+		"days":  days,
+		"stats": stats,
+	})
+}
+
+// getDailyDelegationVolume handles GET /api/v1/chains/:chain/stats/delegation-volume
+func (s *Server) getDailyDelegationVolume(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	days, ok := s.statsDays(c)
+	if !ok {
+		return
+	}
+
+	stats, err := s.analytics.GetDailyDelegationVolume(c.Request.Context(), chainName, days)
+	if err != nil {
+		s.logger.Error("Failed to get daily delegation volume",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get daily delegation volume")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain": chainName,
+		"days":  days,
+		"stats": stats,
+	})
+}
+
+// getDailyRewardIssuance handles GET /api/v1/chains/:chain/stats/reward-issuance
+func (s *Server) getDailyRewardIssuance(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	days, ok := s.statsDays(c)
+	if !ok {
+		return
+	}
+
+	stats, err := s.analytics.GetDailyRewardIssuance(c.Request.Context(), chainName, days)
+	if err != nil {
+		s.logger.Error("Failed to get daily reward issuance",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get daily reward issuance")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain": chainName,
+		"days":  days,
+		"stats": stats,
+	})
+}
+
+// getFeeStats handles GET /api/v1/chains/:chain/stats/fees: fees and gas per
+// day and per message type, and the addresses paying the most fees in a denom
+// (the staking denom by default)
+func (s *Server) getFeeStats(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	if s.storage.ClickHouse() == nil {
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, "analytics storage is not available")
+		return
+	}
+
+	days, ok := s.statsDays(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		s.badRequest(c, "limit must be between 1 and 100")
+		return
+	}
+
+	denom := c.Query("denom")
+	if denom == "" {
+		chain, _ := s.chainConfig(chainName)
+		denom = chain.StakingDenom()
+	}
+
+	ctx := c.Request.Context()
+	daily, err := s.storage.ClickHouse().GetDailyFeeStats(ctx, chainName, days)
+	if err != nil {
+		s.logger.Error("Failed to get daily fee stats",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get daily fee stats")
+		return
+	}
+
+	messageTypes, err := s.storage.ClickHouse().GetMessageTypeFeeStats(ctx, chainName, days)
+	if err != nil {
+		s.logger.Error("Failed to get message type fee stats",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get message type fee stats")
+		return
+	}
+
+	payers, err := s.storage.GetTopFeePayers(ctx, chainName, denom, days, limit)
+	if err != nil {
+		s.logger.Error("Failed to get top fee payers",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get top fee payers")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":         chainName,
+		"days":          days,
+		"denom":         denom,
+		"daily":         daily,
+		"message_types": messageTypes,
+		"top_payers":    payers,
+	})
+}
+
+// getMessageActivity handles GET /api/v1/chains/:chain/stats/messages: the
+// messages of each type over the last days and their share of all messages,
+// with the daily counts
+func (s *Server) getMessageActivity(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	days, ok := s.statsDays(c)
+	if !ok {
+		return
+	}
+
+	breakdown, daily, err := s.storage.GetMessageActivity(c.Request.Context(), chainName, days)
+	if err != nil {
+		s.logger.Error("Failed to get message activity",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get message activity")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":    chainName,
+		"days":     days,
+		"messages": breakdown,
+		"daily":    daily,
+	})
+}
+
+// getEpochStats handles GET /api/v1/chains/:chain/stats/epochs: the last
+// epochs of an epoch-based chain with the fees and gas of the transactions in
+// each, bucketed by epoch instead of by day
+func (s *Server) getEpochStats(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	if s.storage.ClickHouse() == nil {
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, "analytics storage is not available")
+		return
+	}
+
+	identifier := c.Query("identifier")
+	if identifier == "" {
+		chain, _ := s.chainConfig(chainName)
+		identifier = chain.Epoch
+	}
+	if identifier == "" {
+		s.badRequest(c, "identifier is required for chains without a configured epoch")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "30"))
+	if err != nil || limit <= 0 || limit > 366 {
+		s.badRequest(c, "limit must be between 1 and 366")
+		return
+	}
+
+	ctx := c.Request.Context()
+	epochs, err := s.storage.ClickHouse().GetEpochs(ctx, chainName, identifier, limit)
+	if err != nil {
+		s.logger.Error("Failed to get epochs",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get epochs")
+		return
+	}
 
-	if chainName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "chain parameter is required",
-		})
+	fees, err := s.storage.ClickHouse().GetEpochFeeStats(ctx, chainName, identifier, limit)
+	if err != nil {
+		s.logger.Error("Failed to get epoch fee stats",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get epoch fee stats")
 		return
 	}
 
-	balances, err := s.storage.Postgres().GetBalances(c.Request.Context(), chainName, address)
+	c.JSON(http.StatusOK, gin.H{
+		"chain":      chainName,
+		"identifier": identifier,
+		"epochs":     epochs,
+		"fees":       fees,
+	})
+}
+
+// getUnbondingSchedule handles GET /api/v1/chains/:chain/stats/unbonding-schedule
+func (s *Server) getUnbondingSchedule(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	buckets, err := s.storage.State().GetUnbondingSchedule(c.Request.Context(), chainName)
 	if err != nil {
-		s.logger.Error("Failed to get balances", 
-			zap.String("address", address),
+		s.logger.Error("Failed to get unbonding schedule",
 			zap.String("chain", chainName),
 			zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to get balances",
-		})
+		s.storageError(c, err, "failed to get unbonding schedule")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"chain":    chainName,
-		"address":  address,
-		"balances": balances,
+		"chain":   chainName,
+		"buckets": buckets,
 	})
 }
 
-// getAccountDelegations handles GET /api/v1/accounts/:address/delegations
-func (s *Server) getAccountDelegations(c *gin.Context) {
-	address := c.Param("address")
-	chainName := c.Query("chain")
+// getBlockProduction handles GET /api/v1/chains/:chain/stats/block-production
+func (s *Server) getBlockProduction(c *gin.Context) {
+	chainName := c.Param("chain")
 
-	if chainName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "chain parameter is required",
-		})
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil || hours <= 0 || hours > 24*30 {
+		s.badRequest(c, "hours must be between 1 and 720")
 		return
 	}
 
-	delegations, err := s.storage.Postgres().GetDelegations(c.Request.Context(), chainName, address)
+	minStreak, err := strconv.ParseUint(c.DefaultQuery("min_streak", "0"), 10, 64)
 	if err != nil {
-		s.logger.Error("Failed to get delegations",
-			zap.String("address", address),
+		s.badRequest(c, "min_streak must be a non-negative integer")
+		return
+	}
+
+	production, total, err := s.storage.GetBlockProduction(c.Request.Context(), chainName, time.Now().Add(-time.Duration(hours)*time.Hour))
+	if err != nil {
+		s.logger.Error("Failed to get block production",
 			zap.String("chain", chainName),
 			zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to get delegations",
-		})
+		s.storageError(c, err, "failed to get block production")
 		return
 	}
 
+	// min_streak narrows the result to validators currently missing blocks
+	validators := make([]types.ValidatorBlockProduction, 0, len(production))
+	for _, p := range production {
+		if p.MissedStreak >= minStreak {
+			validators = append(validators, p)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"chain":       chainName,
-		"address":     address,
-		"delegations": delegations,
+		"chain":      chainName,
+		"hours":      hours,
+		"blocks":     total,
+		"validators": validators,
 	})
 }
 
-// getAccountState handles GET /api/v1/accounts/:address/state
-func (s *Server) getAccountState(c *gin.Context) {
-	address := c.Param("address")
-	chainName := c.Query("chain")
+// getDelegationFlows handles GET /api/v1/chains/:chain/stats/delegation-flows
+func (s *Server) getDelegationFlows(c *gin.Context) {
+	chainName := c.Param("chain")
 
-	if chainName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "chain parameter is required",
-		})
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil || hours <= 0 || hours > 24*30 {
+		s.badRequest(c, "hours must be between 1 and 720")
 		return
 	}
 
-	// Get balances
-	balances, err := s.storage.Postgres().GetBalances(c.Request.Context(), chainName, address)
-	if err != nil {
-		s.logger.Error("Failed to get balances for account state",
-			zap.String("address", address),
-			zap.String("chain", chainName),
-			zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to get account state",
-		})
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		s.badRequest(c, "limit must be between 1 and 100")
 		return
 	}
 
-	// Get delegations
-	delegations, err := s.storage.Postgres().GetDelegations(c.Request.Context(), chainName, address)
+	validator := c.Query("validator")
+	if validator != "" {
+		if err := s.validateAddress(chainName, validator, cosmos.ValidatorOperatorSuffix); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	to := time.Now()
+	graph, err := s.storage.GetDelegationFlowGraph(c.Request.Context(), chainName, to.Add(-time.Duration(hours)*time.Hour), to, validator, limit)
 	if err != nil {
-		s.logger.Error("Failed to get delegations for account state",
-			zap.String("address", address),
+		s.logger.Error("Failed to get delegation flows",
 			zap.String("chain", chainName),
 			zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to get account state",
-		})
+		s.storageError(c, err, "failed to get delegation flows")
 		return
 	}
 
-	// Create unified account state
-	accountState := types.AccountState{
-		ChainName:   chainName,
-		Address:     address,
-		Balances:    balances,
-		Delegations: delegations,
-		// TODO: Add unbonding, redelegations, rewards when implemented
+	c.JSON(http.StatusOK, gin.H{
+		"chain":   chainName,
+		"hours":   hours,
+		"from":    graph.From,
+		"to":      graph.To,
+		"total":   graph.Total,
+		"flows":   graph.Flows,
+		"gainers": graph.Gainers,
+		"losers":  graph.Losers,
+	})
+}
+
+// getAnomalies handles GET /api/v1/chains/:chain/stats/anomalies, listing the
+// anomalies detected in the chain's hourly metrics, optionally of one metric
+func (s *Server) getAnomalies(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "168"))
+	if err != nil || hours <= 0 || hours > 24*30 {
+		s.badRequest(c, "hours must be between 1 and 720")
+		return
 	}
 
-	c.JSON(http.StatusOK, accountState)
-}
+	metric := c.Query("metric")
+	if metric != "" && !slices.Contains(types.AnomalyMetrics, metric) {
+		s.badRequest(c, "metric must be one of "+strings.Join(types.AnomalyMetrics, ", "))
+		return
+	}
 
-// getChains handles GET /api/v1/chains
-func (s *Server) getChains(c *gin.Context) {
-	// For now, return hardcoded chain info
-	// In a real implementation, this would come from the database
-	chains := []types.ChainInfo{
-		{
-			Name:    "cosmoshub",
-			ChainID: "cosmoshub-4",
-			Status:  "active",
-		},
-		{
-			Name:    "osmosis",
-			ChainID: "osmosis-1",
-			Status:  "active",
-		},
+	anomalies, err := s.storage.Anomalies().GetAnomalies(c.Request.Context(), chainName, time.Now().Add(-time.Duration(hours)*time.Hour))
+	if err != nil {
+		s.logger.Error("Failed to get anomalies",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get anomalies")
+		return
+	}
+	if metric != "" {
+		anomalies = slices.DeleteFunc(anomalies, func(anomaly types.Anomaly) bool {
+			return anomaly.Metric != metric
+		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"chains": chains,
+		"chain":     chainName,
+		"hours":     hours,
+		"anomalies": anomalies,
 	})
 }
 
-// getValidators handles GET /api/v1/chains/:chain/validators
-func (s *Server) getValidators(c *gin.Context) {
+// getHalts handles GET /api/v1/chains/:chain/stats/halts, listing the periods
+// in which the chain stopped producing blocks, ongoing or ended within the
+// last hours, to explain gaps in its analytics
+func (s *Server) getHalts(c *gin.Context) {
 	chainName := c.Param("chain")
 
-	validators, err := s.storage.Postgres().GetValidators(c.Request.Context(), chainName)
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "168"))
+	if err != nil || hours <= 0 || hours > 24*90 {
+		s.badRequest(c, "hours must be between 1 and 2160")
+		return
+	}
+
+	halts, err := s.storage.Halts().GetHalts(c.Request.Context(), chainName, time.Now().Add(-time.Duration(hours)*time.Hour))
 	if err != nil {
-		s.logger.Error("Failed to get validators",
+		s.logger.Error("Failed to get chain halts",
 			zap.String("chain", chainName),
 			zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to get validators",
-		})
+		s.storageError(c, err, "failed to get chain halts")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"chain":      chainName,
-		"validators": validators,
+		"chain": chainName,
+		"hours": hours,
+		"halts": halts,
 	})
 }
 
-// getChainStats handles GET /api/v1/chains/:chain/stats
-func (s *Server) getChainStats(c *gin.Context) {
+// getSupplyBreakdown handles GET /api/v1/chains/:chain/stats/supply, splitting
+// the supply of a denom (the staking denom by default) by where it is held
+func (s *Server) getSupplyBreakdown(c *gin.Context) {
 	chainName := c.Param("chain")
 
-	// Try to get stats from ClickHouse if available
-	if s.storage.ClickHouse() != nil {
-		stats, err := s.storage.ClickHouse().GetChainStats(c.Request.Context(), chainName)
-		if err == nil {
-			c.JSON(http.StatusOK, stats)
-			return
-		}
-		s.logger.Warn("Failed to get chain stats from ClickHouse, falling back",
+	denom := c.Query("denom")
+	if denom == "" {
+		chain, _ := s.chainConfig(chainName)
+		denom = chain.StakingDenom()
+	}
+
+	breakdown, err := s.storage.GetSupplyBreakdown(c.Request.Context(), chainName, denom)
+	if err != nil {
+		s.logger.Error("Failed to get supply breakdown",
 			zap.String("chain", chainName),
+			zap.String("denom", denom),
 			zap.Error(err))
+		s.storageError(c, err, "failed to get supply breakdown")
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// getCirculatingSupply handles GET /api/v1/chains/:chain/supply/circulating.
+// The supply of a denom (the staking denom by default) less vesting-locked
+// coins, module accounts and the auth module's treasury addresses, with its
+// daily history over the last days when analytics storage is available.
+func (s *Server) getCirculatingSupply(c *gin.Context) {
+	chainName := c.Param("chain")
+	chain, _ := s.chainConfig(chainName)
+
+	denom := c.Query("denom")
+	if denom == "" {
+		denom = chain.StakingDenom()
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 || days > 366 {
+		s.badRequest(c, "days must be between 1 and 366")
+		return
 	}
 
-	// Fallback: basic stats from PostgreSQL
-	validators, err := s.storage.Postgres().GetValidators(c.Request.Context(), chainName)
+	auth, _ := chain.Module("auth")
+	treasury := auth.ListOption("treasury")
+
+	supply, err := s.storage.GetCirculatingSupply(c.Request.Context(), chainName, denom, treasury)
 	if err != nil {
-		s.logger.Error("Failed to get validators for stats",
+		s.logger.Error("Failed to get circulating supply",
 			zap.String("chain", chainName),
+			zap.String("denom", denom),
 			zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to get chain stats",
-		})
+		s.storageError(c, err, "failed to get circulating supply")
 		return
 	}
 
-	stats := types.ChainStats{
-		ChainName:       chainName,
-		TotalValidators: int64(len(validators)),
-		// TODO: Calculate other stats from PostgreSQL
+	response := gin.H{
+		"chain":           chainName,
+		"denom":           denom,
+		"height":          supply.Height,
+		"total":           supply.Total,
+		"vesting_locked":  supply.VestingLocked,
+		"module_accounts": supply.ModuleAccounts,
+		"treasury":        supply.Treasury,
+		"circulating":     supply.Circulating,
 	}
 
-	c.JSON(http.StatusOK, stats)
+	if s.storage.ClickHouse() != nil {
+		since := time.Now().AddDate(0, 0, -days)
+		history, err := s.storage.GetCirculatingSupplyHistory(c.Request.Context(), chainName, denom, since)
+		if err != nil {
+			s.logger.Error("Failed to get circulating supply history",
+				zap.String("chain", chainName),
+				zap.String("denom", denom),
+				zap.Error(err))
+			s.storageError(c, err, "failed to get circulating supply history")
+			return
+		}
+		response["days"] = days
+		response["history"] = history
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// statsDays parses the days query parameter for daily stats endpoints. It
+// writes the error response on failure.
+func (s *Server) statsDays(c *gin.Context) (int, bool) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 || days > 366 {
+		s.badRequest(c, "days must be between 1 and 366")
+		return 0, false
+	}
+
+	return days, true
+}
+
+// crossChainAccountV1 is the account state across chains of API v1, keyed by
+// chain, whose totals add up amounts of the same denom on every chain
+type crossChainAccountV1 struct {
+	Address   string                        `json:"address"`
+	Chains    map[string]types.AccountState `json:"chains"`
+	Totals    crossChainTotalsV1            `json:"totals"`
+	Errors    []types.ChainError            `json:"errors,omitempty"`
+	UpdatedAt time.Time                     `json:"updated_at"`
+}
+
+// crossChainTotalsV1 maps denoms to their totals across chains
+type crossChainTotalsV1 struct {
+	TotalBalance   map[string]string `json:"total_balance"`
+	TotalDelegated map[string]string `json:"total_delegated"`
+	TotalUnbonding map[string]string `json:"total_unbonding"`
+	TotalRewards   map[string]string `json:"total_rewards"`
 }
 
 // getCrossChainAccount handles GET /api/v1/cross-chain/accounts/:address
 func (s *Server) getCrossChainAccount(c *gin.Context) {
 	address := c.Param("address")
+	refs := accountRefsFromContext(c)
 
-	// For now, return a placeholder response
-	// In a real implementation, this would aggregate data across all chains
-	crossChainState := types.CrossChainAccountState{
+	account := s.crossChainAccount(c, address, refs)
+	result := crossChainAccountV1{
 		Address: address,
-		Chains:  make(map[string]types.AccountState),
-		Totals: types.CrossChainTotals{
+		Chains:  make(map[string]types.AccountState, len(account.Chains)),
+		Totals: crossChainTotalsV1{
 			TotalBalance:   make(map[string]string),
 			TotalDelegated: make(map[string]string),
 			TotalUnbonding: make(map[string]string),
 			TotalRewards:   make(map[string]string),
 		},
+		Errors:    account.Errors,
+		UpdatedAt: account.UpdatedAt,
+	}
+	for _, chainState := range account.Chains {
+		result.Chains[chainState.ChainName] = chainState
+	}
+	for _, total := range account.Totals {
+		addAmount(result.Totals.TotalBalance, total.Denom, total.Balance)
+		addAmount(result.Totals.TotalDelegated, total.Denom, total.Delegated)
+		addAmount(result.Totals.TotalUnbonding, total.Denom, total.Unbonding)
+		addAmount(result.Totals.TotalRewards, total.Denom, total.Rewards)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// addAmount adds an integer amount to a denom's total, leaving totals without
+// any amount out
+func addAmount(totals map[string]string, denom, amount string) {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok || value.Sign() == 0 {
+		return
+	}
+	if total, ok := new(big.Int).SetString(totals[denom], 10); ok {
+		value.Add(value, total)
+	}
+	totals[denom] = value.String()
+}
+
+// getCrossChainAccountV2 handles GET /api/v2/cross-chain/accounts/:address,
+// listing the account's state per chain with totals per chain and denom
+func (s *Server) getCrossChainAccountV2(c *gin.Context) {
+	account := s.crossChainAccount(c, c.Param("address"), accountRefsFromContext(c))
+	for i := range account.Totals {
+		total := &account.Totals[i]
+		chain, _ := s.chainConfig(total.ChainName)
+		total.DisplayBalance, _ = chain.DisplayAmount(total.Denom, total.Balance)
+		total.DisplayDelegated, _ = chain.DisplayAmount(total.Denom, total.Delegated)
 	}
 
-	// TODO: Implement cross-chain aggregation logic
+	c.JSON(http.StatusOK, account)
+}
 
-	c.JSON(http.StatusOK, crossChainState)
+// crossChainAccount returns an account's state across chains with display
+// amounts for its balances, logging the chains that failed
+func (s *Server) crossChainAccount(c *gin.Context, address string, refs []storage.AccountRef) *types.CrossChainAccount {
+	account := s.accounts.GetCrossChainAccount(c.Request.Context(), address, refs, s.cfg.ChainTimeout)
+	for _, chainErr := range account.Errors {
+		s.logger.Warn("Failed to get account state for cross-chain query",
+			zap.String("address", address),
+			zap.String("chain", chainErr.ChainName),
+			zap.String("error", chainErr.Error))
+	}
+	for _, chainState := range account.Chains {
+		s.setDisplayAmounts(chainState.Balances)
+	}
+	return account
 }
 
 // getCrossChainValidators handles GET /api/v1/cross-chain/validators
 func (s *Server) getCrossChainValidators(c *gin.Context) {
-	chains := c.QueryArray("chains")
+	var chains []string
+	for _, param := range c.QueryArray("chains") {
+		chains = append(chains, splitChains(param)...)
+	}
 	if len(chains) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "at least one chain must be specified",
-		})
+		s.badRequest(c, "at least one chain must be specified")
 		return
 	}
 
-	allValidators := make(map[string][]types.Validator)
+	for _, chainName := range chains {
+		if err := s.validateChain(chainName); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	validators, chainErrors := s.validators.GetCrossChainValidators(c.Request.Context(), chains, s.cfg.ChainTimeout)
+	for _, chainErr := range chainErrors {
+		s.logger.Warn("Failed to get validators for cross-chain query",
+			zap.String("chain", chainErr.ChainName),
+			zap.String("error", chainErr.Error))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"validators": validators,
+		"errors":     chainErrors,
+	})
+}
 
+// getUpgradeCalendar handles GET /api/v1/cross-chain/upgrades. It lists the
+// upcoming software upgrades of the given chains, or of every enabled chain
+// the API key may read, with their estimated halt times.
+func (s *Server) getUpgradeCalendar(c *gin.Context) {
+	var chains []string
+	for _, param := range c.QueryArray("chains") {
+		chains = append(chains, splitChains(param)...)
+	}
 	for _, chainName := range chains {
-		validators, err := s.storage.Postgres().GetValidators(c.Request.Context(), chainName)
-		if err != nil {
-			s.logger.Error("Failed to get validators for cross-chain query",
-				zap.String("chain", chainName),
-				zap.Error(err))
-			continue
+		if err := s.validateChain(chainName); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	if len(chains) == 0 {
+		allowed := policyFromContext(c.Request.Context()).Chains()
+		for _, chain := range s.chains {
+			if chain.Enabled && (allowed == nil || slices.Contains(allowed, chain.Name)) {
+				chains = append(chains, chain.Name)
+			}
+		}
+	}
+
+	upgrades, chainErrors := s.gov.GetUpgradeCalendar(c.Request.Context(), chains, s.cfg.ChainTimeout)
+	for _, chainErr := range chainErrors {
+		s.logger.Warn("Failed to get upgrades for the upgrade calendar",
+			zap.String("chain", chainErr.ChainName),
+			zap.String("error", chainErr.Error))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"upgrades": upgrades,
+		"errors":   chainErrors,
+	})
+}
+
+// getChainParams handles GET /api/v1/chains/:chain/params/:module
+func (s *Server) getChainParams(c *gin.Context) {
+	chainName := c.Param("chain")
+	module := paramsModuleFromContext(c)
+
+	params, err := s.storage.Params().GetChainParams(c.Request.Context(), chainName, module)
+	if err != nil {
+		s.logger.Error("Failed to get module params",
+			zap.String("chain", chainName),
+			zap.String("module", module),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get module params")
+		return
+	}
+
+	c.JSON(http.StatusOK, params)
+}
+
+// getChainParamChanges handles GET /api/v1/chains/:chain/params/:module/changes
+func (s *Server) getChainParamChanges(c *gin.Context) {
+	chainName := c.Param("chain")
+	module := paramsModuleFromContext(c)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		s.badRequest(c, "limit must be between 1 and 100")
+		return
+	}
+
+	changes, err := s.storage.Params().GetChainParamChanges(c.Request.Context(), chainName, module, limit)
+	if err != nil {
+		s.logger.Error("Failed to get module param changes",
+			zap.String("chain", chainName),
+			zap.String("module", module),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get module param changes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":   chainName,
+		"module":  module,
+		"changes": changes,
+	})
+}
+
+// getCrossChainParams handles GET /api/v1/cross-chain/params/:module
+func (s *Server) getCrossChainParams(c *gin.Context) {
+	module := paramsModuleFromContext(c)
+
+	differencesOnly := false
+	if value := c.Query("differences"); value != "" {
+		var err error
+		if differencesOnly, err = strconv.ParseBool(value); err != nil {
+			s.badRequest(c, "differences must be true or false")
+			return
+		}
+	}
+
+	var chains []string
+	for _, param := range c.QueryArray("chains") {
+		chains = append(chains, splitChains(param)...)
+	}
+	for _, chainName := range chains {
+		if err := s.validateChain(chainName); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	if len(chains) == 0 {
+		allowed := policyFromContext(c.Request.Context()).Chains()
+		for _, chain := range s.chains {
+			if chain.Enabled && (allowed == nil || slices.Contains(allowed, chain.Name)) {
+				chains = append(chains, chain.Name)
+			}
+		}
+	}
+
+	params, chainErrors := s.storage.CompareChainParams(c.Request.Context(), module, chains, s.cfg.ChainTimeout)
+	for _, chainErr := range chainErrors {
+		s.logger.Warn("Failed to get module params for comparison",
+			zap.String("chain", chainErr.ChainName),
+			zap.String("module", module),
+			zap.String("error", chainErr.Error))
+	}
+
+	if differencesOnly {
+		differing := params[:0]
+		for _, param := range params {
+			if param.Differs {
+				differing = append(differing, param)
+			}
 		}
-		allValidators[chainName] = validators
+		params = differing
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"module": module,
+		"chains": chains,
+		"params": params,
+		"errors": chainErrors,
+	})
+}
+
+// search handles GET /api/v1/search
+func (s *Server) search(c *gin.Context) {
+	term := strings.TrimSpace(c.Query("q"))
+	if len(term) < 2 {
+		s.badRequest(c, "q must be at least 2 characters")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		s.badRequest(c, "limit must be between 1 and 100")
+		return
+	}
+
+	chains := splitChains(c.Query("chain"))
+	if len(chains) == 0 {
+		chains = policyFromContext(c.Request.Context()).Chains()
+	}
+
+	results, err := s.storage.State().Search(c.Request.Context(), term, chains, limit)
+	if err != nil {
+		s.logger.Error("Failed to search",
+			zap.String("query", term),
+			zap.Error(err))
+		s.storageError(c, err, "failed to search")
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"validators": allValidators,
+		"query":   term,
+		"results": results,
 	})
 }
 
-// getProposals handles GET /api/v1/governance/proposals
+// getProposals handles GET /api/v1/governance/proposals. The optional status
+// filter takes a gov proposal status with or without its PROPOSAL_STATUS_
+// prefix, e.g. voting_period.
 func (s *Server) getProposals(c *gin.Context) {
 	chainName := c.Query("chain")
 	if chainName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "chain parameter is required",
-		})
+		s.badRequest(c, "chain parameter is required")
+		return
+	}
+
+	var status string
+	if param := c.Query("status"); param != "" {
+		var err error
+		if status, err = parseProposalStatus(param); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	page, ok := s.pagination(c)
+	if !ok {
+		return
+	}
+
+	proposals, total, err := s.gov.GetProposals(c.Request.Context(), chainName, status, page.Limit, page.Offset)
+	if err != nil {
+		s.logger.Error("Failed to get proposals",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get proposals")
 		return
 	}
+	page.Total = total
 
-	// TODO: Implement proposal queries
 	c.JSON(http.StatusOK, gin.H{
-		"chain":     chainName,
-		"proposals": []types.Proposal{},
+		"chain":      chainName,
+		"proposals":  proposals,
+		"pagination": page,
 	})
 }
 
@@ -272,26 +1233,31 @@ func (s *Server) getProposals(c *gin.Context) {
 func (s *Server) getProposal(c *gin.Context) {
 	chainName := c.Query("chain")
 	if chainName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "chain parameter is required",
-		})
+		s.badRequest(c, "chain parameter is required")
 		return
 	}
 
 	proposalIDStr := c.Param("id")
 	proposalID, err := strconv.ParseUint(proposalIDStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid proposal ID",
-		})
+		s.badRequest(c, "invalid proposal ID")
+		return
+	}
+
+	proposal, err := s.gov.GetProposal(c.Request.Context(), chainName, proposalID)
+	if err != nil {
+		s.logger.Error("Failed to get proposal",
+			zap.String("chain", chainName),
+			zap.Uint64("proposal_id", proposalID),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get proposal")
 		return
 	}
 
-	// TODO: Implement single proposal query
 	c.JSON(http.StatusOK, gin.H{
 		"chain":       chainName,
 		"proposal_id": proposalID,
-		"proposal":    nil,
+		"proposal":    proposal,
 	})
 }
 
@@ -299,18 +1265,14 @@ func (s *Server) getProposal(c *gin.Context) {
 func (s *Server) getProposalVotes(c *gin.Context) {
 	chainName := c.Query("chain")
 	if chainName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "chain parameter is required",
-		})
+		s.badRequest(c, "chain parameter is required")
 		return
 	}
 
 	proposalIDStr := c.Param("id")
 	proposalID, err := strconv.ParseUint(proposalIDStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid proposal ID",
-		})
+		s.badRequest(c, "invalid proposal ID")
 		return
 	}
 
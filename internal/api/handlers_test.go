@@ -0,0 +1,385 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/storage/mock"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// testChains are the chains the handler tests serve
+var testChains = []config.ChainConfig{
+	{Name: "cosmoshub", Bech32Prefix: "cosmos", BaseDenom: "uatom", DenomExponent: 6, Enabled: true},
+	{Name: "osmosis", Bech32Prefix: "osmo", BaseDenom: "uosmo", DenomExponent: 6, Enabled: true},
+}
+
+// testAddress returns the address of the test account on a chain
+func testAddress(t *testing.T, prefix string) string {
+	t.Helper()
+	address, err := bech32.ConvertAndEncode(prefix, make([]byte, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return address
+}
+
+// newTestServer returns a router serving the account, validator, governance
+// and stats routes of a server reading from the given read models
+func newTestServer(accounts *mock.AccountReader, validators *mock.ValidatorReader, gov *mock.GovReader, analytics *mock.AnalyticsReader) http.Handler {
+	gin.SetMode(gin.TestMode)
+	s := &Server{
+		cfg:        config.APIConfig{ChainTimeout: time.Second},
+		chains:     testChains,
+		logger:     zap.NewNop(),
+		accounts:   accounts,
+		validators: validators,
+		gov:        gov,
+		analytics:  analytics,
+	}
+
+	router := gin.New()
+	api := router.Group("/api/v1")
+	api.GET("/accounts/:address/state", s.requireValidAccount(), s.getAccountState)
+	api.GET("/accounts/:address/rewards", s.requireValidAccount(), s.getAccountRewards)
+	api.GET("/cross-chain/accounts/:address", s.requireValidAccount(), s.getCrossChainAccount)
+	api.GET("/chains/:chain/validators", s.getValidators)
+	api.GET("/chains/:chain/stats/active-addresses", s.getDailyActiveAddresses)
+	api.GET("/chains/:chain/accounts/:address/balance-history", s.getBalanceHistory)
+	api.GET("/governance/proposals/:id", s.getProposal)
+	return router
+}
+
+// serve sends a GET request and decodes the JSON response
+func serve(t *testing.T, handler http.Handler, path string) (int, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body.String(), err)
+	}
+	return rec.Code, body
+}
+
+func TestGetAccountState(t *testing.T) {
+	address := testAddress(t, "cosmos")
+
+	tests := []struct {
+		name        string
+		path        string
+		states      func(ctx context.Context, refs []storage.AccountRef) ([]types.AccountState, error)
+		wantStatus  int
+		wantDisplay string
+		wantErrCode string
+	}{
+		{
+			name: "account state",
+			path: "/api/v1/accounts/" + address + "/state?chain=cosmoshub",
+			states: func(ctx context.Context, refs []storage.AccountRef) ([]types.AccountState, error) {
+				if len(refs) != 1 || refs[0].ChainName != "cosmoshub" || refs[0].Denom != "uatom" {
+					return nil, errors.New("unexpected refs")
+				}
+				return []types.AccountState{{
+					ChainName: "cosmoshub",
+					Address:   refs[0].Address,
+					Balances:  []types.Balance{{ChainName: "cosmoshub", Denom: "uatom", Amount: "1500000"}},
+				}}, nil
+			},
+			wantStatus:  http.StatusOK,
+			wantDisplay: "1.5",
+		},
+		{
+			name: "storage unavailable",
+			path: "/api/v1/accounts/" + address + "/state?chain=cosmoshub",
+			states: func(ctx context.Context, refs []storage.AccountRef) ([]types.AccountState, error) {
+				return nil, storage.ErrUnavailable
+			},
+			wantStatus:  http.StatusServiceUnavailable,
+			wantErrCode: CodeUnavailable,
+		},
+		{
+			name:        "address of another chain",
+			path:        "/api/v1/accounts/" + testAddress(t, "osmo") + "/state?chain=cosmoshub",
+			wantStatus:  http.StatusBadRequest,
+			wantErrCode: CodeInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestServer(&mock.AccountReader{GetAccountStatesFunc: tt.states}, nil, nil, nil)
+
+			status, body := serve(t, router, tt.path)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %v", status, tt.wantStatus, body)
+			}
+			if tt.wantErrCode != "" {
+				if code := body["error"].(map[string]any)["code"]; code != tt.wantErrCode {
+					t.Errorf("error code = %v, want %s", code, tt.wantErrCode)
+				}
+				return
+			}
+
+			accounts := body["accounts"].([]any)
+			balance := accounts[0].(map[string]any)["balances"].([]any)[0].(map[string]any)
+			if balance["display_amount"] != tt.wantDisplay {
+				t.Errorf("display amount = %v, want %s", balance["display_amount"], tt.wantDisplay)
+			}
+		})
+	}
+}
+
+func TestGetAccountRewards(t *testing.T) {
+	tests := []struct {
+		name       string
+		rewards    func(ctx context.Context, refs []storage.AccountRef) ([]types.DelegatorRewards, error)
+		wantStatus int
+	}{
+		{
+			name: "rewards",
+			rewards: func(ctx context.Context, refs []storage.AccountRef) ([]types.DelegatorRewards, error) {
+				return []types.DelegatorRewards{{
+					ChainName:        refs[0].ChainName,
+					DelegatorAddress: refs[0].Address,
+					Totals:           []types.DelegatorRewardTotal{{Denom: "uatom", Withdrawn: "30", Claimed: "20", Compounded: "20"}},
+					Claims:           2,
+					CompoundedClaims: 2,
+					AutoCompounding:  true,
+				}}, nil
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "without analytics storage",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestServer(&mock.AccountReader{GetAccountRewardsFunc: tt.rewards}, nil, nil, nil)
+
+			status, body := serve(t, router, "/api/v1/accounts/"+testAddress(t, "cosmos")+"/rewards?chain=cosmoshub")
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %v", status, tt.wantStatus, body)
+			}
+			if status != http.StatusOK {
+				return
+			}
+			rewards := body["rewards"].([]any)[0].(map[string]any)
+			if rewards["auto_compounding"] != true || rewards["chain_name"] != "cosmoshub" {
+				t.Errorf("rewards = %v", rewards)
+			}
+		})
+	}
+}
+
+func TestGetCrossChainAccountV1(t *testing.T) {
+	accounts := &mock.AccountReader{
+		GetCrossChainAccountFunc: func(ctx context.Context, address string, refs []storage.AccountRef, timeout time.Duration) *types.CrossChainAccount {
+			return &types.CrossChainAccount{
+				Address: address,
+				Chains: []types.AccountState{
+					{ChainName: "cosmoshub", Address: refs[0].Address},
+					{ChainName: "osmosis", Address: refs[1].Address},
+				},
+				Totals: []types.CrossChainTotal{
+					{ChainName: "cosmoshub", Denom: "uatom", Balance: "100", Delegated: "50", Unbonding: "0", Rewards: "2"},
+					{ChainName: "osmosis", Denom: "uatom", Balance: "25", Delegated: "0", Unbonding: "0", Rewards: "0"},
+					{ChainName: "osmosis", Denom: "uosmo", Balance: "7", Delegated: "3", Unbonding: "1", Rewards: "0"},
+				},
+			}
+		},
+	}
+	router := newTestServer(accounts, nil, nil, nil)
+
+	status, body := serve(t, router, "/api/v1/cross-chain/accounts/"+testAddress(t, "cosmos")+"?chain=cosmoshub,osmosis")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %v", status, body)
+	}
+
+	// v1 keys chains by name and adds up each denom across chains
+	chains := body["chains"].(map[string]any)
+	if _, ok := chains["osmosis"]; !ok || len(chains) != 2 {
+		t.Errorf("chains = %v, want cosmoshub and osmosis", chains)
+	}
+	want := map[string]any{
+		"total_balance":   map[string]any{"uatom": "125", "uosmo": "7"},
+		"total_delegated": map[string]any{"uatom": "50", "uosmo": "3"},
+		"total_unbonding": map[string]any{"uosmo": "1"},
+		"total_rewards":   map[string]any{"uatom": "2"},
+	}
+	if totals := body["totals"]; !reflect.DeepEqual(totals, want) {
+		t.Errorf("totals = %v, want %v", totals, want)
+	}
+}
+
+func TestGetValidators(t *testing.T) {
+	validators := &mock.ValidatorReader{
+		EachValidatorFunc: func(ctx context.Context, chainName string, fn func(*types.Validator) error) error {
+			for _, address := range []string{"cosmosvaloper1a", "cosmosvaloper1b"} {
+				if err := fn(&types.Validator{ChainName: chainName, OperatorAddress: address}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	router := newTestServer(nil, validators, nil, nil)
+
+	status, body := serve(t, router, "/api/v1/chains/cosmoshub/validators")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %v", status, body)
+	}
+	if body["chain"] != "cosmoshub" {
+		t.Errorf("chain = %v, want cosmoshub", body["chain"])
+	}
+	if list := body["validators"].([]any); len(list) != 2 || list[1].(map[string]any)["operator_address"] != "cosmosvaloper1b" {
+		t.Errorf("validators = %v, want both validators in order", list)
+	}
+}
+
+func TestGetProposal(t *testing.T) {
+	gov := &mock.GovReader{
+		GetProposalFunc: func(ctx context.Context, chainName string, proposalID uint64) (*types.Proposal, error) {
+			if proposalID != 7 {
+				return nil, storage.ErrNotFound
+			}
+			return &types.Proposal{ChainName: chainName, ProposalID: proposalID, Status: "PROPOSAL_STATUS_PASSED"}, nil
+		},
+	}
+	router := newTestServer(nil, nil, gov, nil)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "proposal", path: "/api/v1/governance/proposals/7?chain=cosmoshub", wantStatus: http.StatusOK},
+		{name: "unknown proposal", path: "/api/v1/governance/proposals/8?chain=cosmoshub", wantStatus: http.StatusNotFound},
+		{name: "invalid ID", path: "/api/v1/governance/proposals/latest?chain=cosmoshub", wantStatus: http.StatusBadRequest},
+		{name: "no chain", path: "/api/v1/governance/proposals/7", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := serve(t, router, tt.path)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %v", status, tt.wantStatus, body)
+			}
+			if status == http.StatusOK && body["proposal"].(map[string]any)["status"] != "PROPOSAL_STATUS_PASSED" {
+				t.Errorf("proposal = %v", body["proposal"])
+			}
+		})
+	}
+}
+
+func TestGetDailyActiveAddresses(t *testing.T) {
+	analytics := &mock.AnalyticsReader{
+		GetDailyActiveAddressesFunc: func(ctx context.Context, chainName string, days int) ([]types.DailyActiveAddresses, error) {
+			if chainName == "osmosis" {
+				return nil, storage.ErrUnavailable
+			}
+			return []types.DailyActiveAddresses{{ChainName: chainName, ActiveAddresses: uint64(days)}}, nil
+		},
+	}
+	router := newTestServer(nil, nil, nil, analytics)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantDays   float64
+	}{
+		{name: "default days", path: "/api/v1/chains/cosmoshub/stats/active-addresses", wantStatus: http.StatusOK, wantDays: 30},
+		{name: "days", path: "/api/v1/chains/cosmoshub/stats/active-addresses?days=7", wantStatus: http.StatusOK, wantDays: 7},
+		{name: "too many days", path: "/api/v1/chains/cosmoshub/stats/active-addresses?days=400", wantStatus: http.StatusBadRequest},
+		{name: "without analytics storage", path: "/api/v1/chains/osmosis/stats/active-addresses", wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := serve(t, router, tt.path)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %v", status, tt.wantStatus, body)
+			}
+			if status != http.StatusOK {
+				if retryable := body["error"].(map[string]any)["retryable"]; retryable != (status == http.StatusServiceUnavailable) {
+					t.Errorf("retryable = %v", retryable)
+				}
+				return
+			}
+			if body["days"] != tt.wantDays {
+				t.Errorf("days = %v, want %v", body["days"], tt.wantDays)
+			}
+			stats := body["stats"].([]any)
+			if stats[0].(map[string]any)["chain_name"] != "cosmoshub" {
+				t.Errorf("stats = %v", stats)
+			}
+		})
+	}
+}
+
+func TestGetBalanceHistory(t *testing.T) {
+	address := testAddress(t, "cosmos")
+	analytics := &mock.AnalyticsReader{
+		GetBalanceHistoryFunc: func(ctx context.Context, q storage.BalanceHistoryQuery) ([]types.BalancePoint, error) {
+			if q.Denom != "uatom" || q.Limit != 1000 {
+				return nil, errors.New("unexpected query")
+			}
+			points := []types.BalancePoint{{Amount: "100", Height: 10, TxHash: "AB"}}
+			if q.Resolution != storage.ResolutionRaw {
+				points[0].TxHash = ""
+			}
+			return points, nil
+		},
+	}
+	router := newTestServer(nil, nil, nil, analytics)
+
+	tests := []struct {
+		name           string
+		query          string
+		wantStatus     int
+		wantResolution string
+	}{
+		{name: "raw by default", wantStatus: http.StatusOK, wantResolution: "raw"},
+		{name: "hourly", query: "?resolution=1h", wantStatus: http.StatusOK, wantResolution: "1h"},
+		{name: "daily in range", query: "?resolution=1d&from=2024-01-01&to=2024-02-01", wantStatus: http.StatusOK, wantResolution: "1d"},
+		{name: "unknown resolution", query: "?resolution=5m", wantStatus: http.StatusBadRequest},
+		{name: "empty range", query: "?from=2024-02-01&to=2024-01-01", wantStatus: http.StatusBadRequest},
+		{name: "too many points", query: "?limit=20000", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := serve(t, router, "/api/v1/chains/cosmoshub/accounts/"+address+"/balance-history"+tt.query)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %v", status, tt.wantStatus, body)
+			}
+			if status != http.StatusOK {
+				return
+			}
+			if body["resolution"] != tt.wantResolution || body["denom"] != "uatom" {
+				t.Errorf("resolution = %v, denom = %v", body["resolution"], body["denom"])
+			}
+			point := body["history"].([]any)[0].(map[string]any)
+			if _, ok := point["tx_hash"]; ok != (tt.wantResolution == "raw") {
+				t.Errorf("point = %v", point)
+			}
+		})
+	}
+}
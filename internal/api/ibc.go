@@ -0,0 +1,129 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ibcWindow parses the hours and stuck_after query parameters of the IBC
+// endpoints, answering the request itself when they are invalid or analytics
+// storage is not available
+func (s *Server) ibcWindow(c *gin.Context, defaultHours string) (time.Time, time.Duration, bool) {
+	if s.storage.ClickHouse() == nil {
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, "analytics storage is not available")
+		return time.Time{}, 0, false
+	}
+
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", defaultHours))
+	if err != nil || hours <= 0 || hours > 24*90 {
+		s.badRequest(c, "hours must be between 1 and 2160")
+		return time.Time{}, 0, false
+	}
+
+	stuckAfter, err := time.ParseDuration(c.DefaultQuery("stuck_after", "1h"))
+	if err != nil || stuckAfter <= 0 {
+		s.badRequest(c, "stuck_after must be a positive duration such as 30m or 2h")
+		return time.Time{}, 0, false
+	}
+
+	return time.Now().Add(-time.Duration(hours) * time.Hour), stuckAfter, true
+}
+
+// getChannelPerformance handles GET /api/v1/chains/:chain/ibc/channels: per
+// channel, the packets sent over the last hours, how many were acknowledged,
+// timed out or are stuck, and acknowledgement latency
+func (s *Server) getChannelPerformance(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	since, stuckAfter, ok := s.ibcWindow(c, "24")
+	if !ok {
+		return
+	}
+
+	channels, err := s.storage.ClickHouse().GetChannelPerformance(c.Request.Context(), chainName, since, stuckAfter)
+	if err != nil {
+		s.logger.Error("Failed to get channel performance",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get channel performance")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":       chainName,
+		"since":       since,
+		"stuck_after": stuckAfter.String(),
+		"channels":    channels,
+	})
+}
+
+// getStuckPackets handles GET /api/v1/chains/:chain/ibc/stuck-packets: packets
+// sent over the last hours that were neither acknowledged nor timed out within
+// stuck_after, oldest first
+func (s *Server) getStuckPackets(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	since, stuckAfter, ok := s.ibcWindow(c, "168")
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		s.badRequest(c, "limit must be between 1 and 1000")
+		return
+	}
+
+	packets, err := s.storage.ClickHouse().GetStuckPackets(c.Request.Context(), chainName, since, stuckAfter, limit)
+	if err != nil {
+		s.logger.Error("Failed to get stuck packets",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get stuck packets")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":       chainName,
+		"since":       since,
+		"stuck_after": stuckAfter.String(),
+		"packets":     packets,
+	})
+}
+
+// getRelayerLeaderboard handles GET /api/v1/chains/:chain/ibc/relayers: the
+// relayers that delivered, acknowledged or timed out the most packets on the
+// chain over the last hours, with their labels
+func (s *Server) getRelayerLeaderboard(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	since, _, ok := s.ibcWindow(c, "24")
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		s.badRequest(c, "limit must be between 1 and 100")
+		return
+	}
+
+	relayers, err := s.storage.GetRelayerLeaderboard(c.Request.Context(), chainName, since, limit)
+	if err != nil {
+		s.logger.Error("Failed to get relayer leaderboard",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get relayer leaderboard")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":    chainName,
+		"since":    since,
+		"relayers": relayers,
+	})
+}
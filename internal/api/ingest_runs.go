@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// getIngestRuns handles GET /admin/v1/ingest-runs: the reports of recent ingest
+// cycles, newest first, with the duration, rows written, gRPC calls and error
+// of each module polled
+func (s *Server) getIngestRuns(c *gin.Context) {
+	filter := types.IngestRunFilter{
+		ChainName: c.Query("chain"),
+	}
+	if filter.ChainName != "" {
+		if err := s.validateChain(filter.ChainName); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	if value := c.Query("failed"); value != "" {
+		failed, err := strconv.ParseBool(value)
+		if err != nil {
+			s.badRequest(c, "failed must be true or false")
+			return
+		}
+		filter.Failed = failed
+	}
+
+	since, err := timeParam(c, "since")
+	if err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+	if since.IsZero() {
+		since = time.Now().UTC().Add(-24 * time.Hour)
+	}
+	filter.Since = since
+
+	filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || filter.Limit <= 0 || filter.Limit > 1000 {
+		s.badRequest(c, "limit must be between 1 and 1000")
+		return
+	}
+
+	runs, err := s.storage.IngestRuns().GetIngestRuns(c.Request.Context(), filter)
+	if err != nil {
+		s.logger.Error("Failed to get ingest runs", zap.Error(err))
+		s.storageError(c, err, "failed to get ingest runs")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"since": since,
+		"runs":  runs,
+	})
+}
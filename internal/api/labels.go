@@ -0,0 +1,181 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// parseLabelCategories parses a comma separated list of label categories
+func parseLabelCategories(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var categories []string
+	for _, category := range strings.Split(value, ",") {
+		category = strings.TrimSpace(category)
+		if !slices.Contains(types.LabelCategories, category) {
+			return nil, fmt.Errorf("unknown label category %q; known categories: %s", category, strings.Join(types.LabelCategories, ", "))
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+// getLabels handles GET /admin/v1/labels
+func (s *Server) getLabels(c *gin.Context) {
+	filter := types.LabelFilter{
+		ChainName: c.Query("chain"),
+		Tag:       c.Query("tag"),
+	}
+	if filter.ChainName != "" {
+		if err := s.validateChain(filter.ChainName); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	categories, err := parseLabelCategories(c.Query("category"))
+	if err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+	filter.Categories = categories
+
+	labels, err := s.storage.Labels().GetLabels(c.Request.Context(), filter)
+	if err != nil {
+		s.logger.Error("Failed to get labels", zap.Error(err))
+		s.storageError(c, err, "failed to get labels")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"labels": labels,
+	})
+}
+
+// putLabel handles PUT /admin/v1/labels/:chain/:address
+func (s *Server) putLabel(c *gin.Context) {
+	chainName := c.Param("chain")
+	address := c.Param("address")
+	if err := s.validateChain(chainName); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+	if err := s.validateAddress(chainName, address, ""); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	var req struct {
+		Label    string   `json:"label"`
+		Category string   `json:"category"`
+		Tags     []string `json:"tags"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Label) == "" {
+		s.badRequest(c, "label is required")
+		return
+	}
+	if req.Category == "" {
+		req.Category = types.LabelCategoryOther
+	}
+	if _, err := parseLabelCategories(req.Category); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	label := &types.AddressLabel{
+		ChainName: chainName,
+		Address:   address,
+		Label:     strings.TrimSpace(req.Label),
+		Category:  req.Category,
+		Tags:      req.Tags,
+	}
+	if err := s.storage.Labels().UpsertLabel(c.Request.Context(), label); err != nil {
+		s.logger.Error("Failed to upsert label",
+			zap.String("chain", chainName),
+			zap.String("address", address),
+			zap.Error(err))
+		s.storageError(c, err, "failed to save label")
+		return
+	}
+
+	s.logger.Info("Address labeled",
+		zap.String("chain", chainName),
+		zap.String("address", address),
+		zap.String("label", label.Label))
+	c.JSON(http.StatusOK, label)
+}
+
+// deleteLabel handles DELETE /admin/v1/labels/:chain/:address
+func (s *Server) deleteLabel(c *gin.Context) {
+	chainName := c.Param("chain")
+	address := c.Param("address")
+	if err := s.validateChain(chainName); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+	if err := s.validateAddress(chainName, address, ""); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+	if err := s.storage.Labels().DeleteLabel(c.Request.Context(), chainName, address); err != nil {
+		s.logger.Error("Failed to delete label",
+			zap.String("chain", chainName),
+			zap.String("address", address),
+			zap.Error(err))
+		s.storageError(c, err, "failed to delete label")
+		return
+	}
+
+	s.logger.Info("Address label deleted", zap.String("chain", chainName), zap.String("address", address))
+	c.Status(http.StatusNoContent)
+}
+
+// getTopHolders handles GET /api/v1/chains/:chain/stats/top-holders. Labeled
+// addresses in the categories given by exclude are left out, e.g. exchange
+// wallets when looking at the holder distribution.
+func (s *Server) getTopHolders(c *gin.Context) {
+	chainName := c.Param("chain")
+
+	denom := c.Query("denom")
+	if denom == "" {
+		chain, _ := s.chainConfig(chainName)
+		denom = chain.StakingDenom()
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		s.badRequest(c, "limit must be between 1 and 100")
+		return
+	}
+
+	exclude, err := parseLabelCategories(c.Query("exclude"))
+	if err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	holders, err := s.storage.GetTopHolders(c.Request.Context(), chainName, denom, limit, exclude)
+	if err != nil {
+		s.logger.Error("Failed to get top holders",
+			zap.String("chain", chainName),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get top holders")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":    chainName,
+		"denom":    denom,
+		"excluded": exclude,
+		"holders":  holders,
+	})
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// getLogging handles GET /admin/v1/logging: the default log level, the level
+// of each logger name that overrides it and sampling
+func (s *Server) getLogging(c *gin.Context) {
+	if s.logControl == nil {
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, "log settings are not available")
+		return
+	}
+
+	c.JSON(http.StatusOK, s.logControl.Settings())
+}
+
+// putLogging handles PUT /admin/v1/logging. Fields left out of the request
+// keep their value; a logger name set to an empty level is removed.
+func (s *Server) putLogging(c *gin.Context) {
+	if s.logControl == nil {
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, "log settings are not available")
+		return
+	}
+
+	settings := s.logControl.Settings()
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		s.badRequest(c, "invalid log settings: "+err.Error())
+		return
+	}
+	if err := s.logControl.Update(settings); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	s.logger.Info("Log settings changed",
+		zap.String("level", settings.Level),
+		zap.Any("modules", settings.Modules),
+		zap.Bool("sampling", settings.Sampling.Enabled))
+
+	c.JSON(http.StatusOK, s.logControl.Settings())
+}
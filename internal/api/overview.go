@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// overviewCache holds the chain overviews of every enabled chain for the
+// overview cache TTL, so landing page traffic does not fan out to storage on
+// every request
+type overviewCache struct {
+	mu        sync.Mutex
+	chains    []types.ChainOverview
+	errors    []types.ChainError
+	updatedAt time.Time
+}
+
+// getOverview handles GET /api/v1/overview
+func (s *Server) getOverview(c *gin.Context) {
+	chains, chainErrors, updatedAt := s.chainOverviews(c.Request.Context())
+
+	// Restricted keys only see the chains their role allows
+	if allowed := policyFromContext(c.Request.Context()).Chains(); allowed != nil {
+		chains, chainErrors = filterOverviews(chains, chainErrors, allowed)
+	}
+
+	c.JSON(http.StatusOK, summarizeOverview(chains, chainErrors, updatedAt))
+}
+
+// chainOverviews returns the overviews of every enabled chain, cached for
+// the configured TTL
+func (s *Server) chainOverviews(ctx context.Context) ([]types.ChainOverview, []types.ChainError, time.Time) {
+	s.overview.mu.Lock()
+	defer s.overview.mu.Unlock()
+
+	if !s.overview.updatedAt.IsZero() && time.Since(s.overview.updatedAt) < s.cfg.Overview.CacheTTL {
+		return s.overview.chains, s.overview.errors, s.overview.updatedAt
+	}
+
+	var names []string
+	for _, chain := range s.chains {
+		if chain.Enabled {
+			names = append(names, chain.Name)
+		}
+	}
+
+	overviews, chainErrors := s.storage.GetChainOverviews(ctx, names, s.cfg.ChainTimeout)
+	for _, chainErr := range chainErrors {
+		s.logger.Warn("Failed to get chain overview",
+			zap.String("chain", chainErr.ChainName),
+			zap.String("error", chainErr.Error))
+	}
+
+	failed := make(map[string]bool, len(chainErrors))
+	for _, chainErr := range chainErrors {
+		failed[chainErr.ChainName] = true
+	}
+
+	prices := s.prices.USD(ctx)
+	for i := range overviews {
+		overview := &overviews[i]
+		chain, _ := s.chainConfig(overview.ChainName)
+		overview.ChainID = chain.ChainID
+		overview.BondDenom = chain.StakingDenom()
+		overview.Health = s.chainHealth(*overview, failed[overview.ChainName])
+
+		price, ok := prices[overview.ChainName]
+		if !ok {
+			continue
+		}
+		overview.PriceUSD = &price
+		if bonded, err := decimal.NewFromString(overview.BondedTokens); err == nil {
+			value := bonded.Shift(-int32(chain.DenomExponent)).Mul(decimal.NewFromFloat(price)).InexactFloat64()
+			overview.StakedValueUSD = &value
+		}
+	}
+
+	// Requests cancelled while loading do not poison the cache
+	if ctx.Err() == nil {
+		s.overview.chains = overviews
+		s.overview.errors = chainErrors
+		s.overview.updatedAt = time.Now()
+		return overviews, chainErrors, s.overview.updatedAt
+	}
+	return overviews, chainErrors, time.Now()
+}
+
+// chainHealth classifies a chain from its sync status
+func (s *Server) chainHealth(overview types.ChainOverview, failed bool) string {
+	switch {
+	case failed || overview.LatestHeight == 0:
+		return types.ChainUnknown
+	case overview.CatchingUp:
+		return types.ChainCatchingUp
+	case overview.HaltedSince != nil:
+		return types.ChainHalted
+	case time.Since(overview.LatestBlockTime) > s.cfg.Overview.StaleAfter:
+		return types.ChainStale
+	case overview.LatestHeight-overview.LastIngestedHeight > s.cfg.Overview.MaxLag:
+		return types.ChainLagging
+	default:
+		return types.ChainHealthy
+	}
+}
+
+// filterOverviews keeps the overviews and errors of the allowed chains
+func filterOverviews(chains []types.ChainOverview, chainErrors []types.ChainError, allowed []string) ([]types.ChainOverview, []types.ChainError) {
+	keep := make(map[string]bool, len(allowed))
+	for _, chain := range allowed {
+		keep[chain] = true
+	}
+
+	var filtered []types.ChainOverview
+	for _, chain := range chains {
+		if keep[chain.ChainName] {
+			filtered = append(filtered, chain)
+		}
+	}
+	var filteredErrors []types.ChainError
+	for _, chainErr := range chainErrors {
+		if keep[chainErr.ChainName] {
+			filteredErrors = append(filteredErrors, chainErr)
+		}
+	}
+	return filtered, filteredErrors
+}
+
+// summarizeOverview totals the chain overviews
+func summarizeOverview(chains []types.ChainOverview, chainErrors []types.ChainError, updatedAt time.Time) types.NetworkOverview {
+	overview := types.NetworkOverview{
+		TotalChains: len(chains),
+		Chains:      chains,
+		Errors:      chainErrors,
+		UpdatedAt:   updatedAt,
+	}
+	if overview.Chains == nil {
+		overview.Chains = []types.ChainOverview{}
+	}
+
+	for _, chain := range chains {
+		if chain.Health == types.ChainHealthy {
+			overview.HealthyChains++
+		}
+		overview.TotalAccounts += chain.AccountCount
+		overview.EventsPerDay += chain.EventsPerDay
+		if chain.StakedValueUSD != nil {
+			overview.StakedValueUSD += *chain.StakedValueUSD
+			overview.PricedChains++
+		}
+	}
+	return overview
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	gql "github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes of persisted query failures. PersistedQueryNotFound tells
+// Apollo clients to retry with the full query.
+const (
+	errPersistedQueryNotFound     = "PersistedQueryNotFound"
+	codePersistedQueryNotFound    = "PERSISTED_QUERY_NOT_FOUND"
+	codePersistedQueryNotAllowed  = "PERSISTED_QUERY_NOT_ALLOWED"
+	codePersistedQueryHashInvalid = "PERSISTED_QUERY_HASH_MISMATCH"
+)
+
+// queryAllowlist executes only pre-registered GraphQL queries. Clients send
+// either a registered query or its hash in the persistedQuery extension.
+type queryAllowlist struct {
+	queries map[string]string // by manifest ID and by SHA-256 hash
+	hashes  map[string]bool   // SHA-256 hashes of the registered queries
+}
+
+var _ interface {
+	gql.OperationParameterMutator
+	gql.HandlerExtension
+} = (*queryAllowlist)(nil)
+
+// loadQueryAllowlist reads a persisted query manifest, either an Apollo
+// persisted query manifest or a JSON object mapping IDs to queries
+func loadQueryAllowlist(path string) (*queryAllowlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read persisted query allowlist: %w", err)
+	}
+
+	var manifest struct {
+		Operations []struct {
+			ID   string `json:"id"`
+			Body string `json:"body"`
+		} `json:"operations"`
+	}
+	queries := make(map[string]string)
+	if err := json.Unmarshal(data, &manifest); err == nil && manifest.Operations != nil {
+		for _, op := range manifest.Operations {
+			queries[op.ID] = op.Body
+		}
+	} else if err := json.Unmarshal(data, &queries); err != nil {
+		return nil, fmt.Errorf("failed to parse persisted query allowlist %s: %w", path, err)
+	}
+
+	a := &queryAllowlist{
+		queries: make(map[string]string, 2*len(queries)),
+		hashes:  make(map[string]bool, len(queries)),
+	}
+	for id, query := range queries {
+		if query == "" {
+			return nil, fmt.Errorf("persisted query %s in %s is empty", id, path)
+		}
+		hash := queryHash(query)
+		a.queries[id] = query
+		a.queries[hash] = query
+		a.hashes[hash] = true
+	}
+
+	return a, nil
+}
+
+// queryHash returns the SHA-256 hash persisted queries are identified by
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// ExtensionName names the extension for gqlgen
+func (a *queryAllowlist) ExtensionName() string {
+	return "QueryAllowlist"
+}
+
+// Validate accepts any schema
+func (a *queryAllowlist) Validate(gql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationParameters resolves persisted query hashes and rejects
+// queries that are not registered
+func (a *queryAllowlist) MutateOperationParameters(ctx context.Context, params *gql.RawParams) *gqlerror.Error {
+	var hash string
+	if ext, ok := params.Extensions["persistedQuery"].(map[string]any); ok {
+		hash, _ = ext["sha256Hash"].(string)
+	}
+
+	if params.Query == "" {
+		query, ok := a.queries[hash]
+		if hash == "" || !ok {
+			err := gqlerror.Errorf(errPersistedQueryNotFound)
+			errcode.Set(err, codePersistedQueryNotFound)
+			return err
+		}
+		params.Query = query
+		return nil
+	}
+
+	sum := queryHash(params.Query)
+	if hash != "" && hash != sum {
+		err := gqlerror.Errorf("provided persisted query hash does not match query")
+		errcode.Set(err, codePersistedQueryHashInvalid)
+		return err
+	}
+	if !a.hashes[sum] {
+		err := gqlerror.Errorf("query is not in the persisted query allowlist")
+		errcode.Set(err, codePersistedQueryNotAllowed)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// maxPortfolioAccounts bounds the accounts of a portfolio, which its summary
+// and history read concurrently
+const maxPortfolioAccounts = 100
+
+// portfolioTotal is a portfolio's total in a denom on a chain, valued in USD
+// when the denom is the chain's staking denom and it has a price
+type portfolioTotal struct {
+	types.CrossChainTotal
+	ValueUSD *float64 `json:"value_usd,omitempty"`
+}
+
+// getPortfolios handles GET /api/v1/portfolios
+func (s *Server) getPortfolios(c *gin.Context) {
+	portfolios, err := s.storage.Tenants().GetPortfolios(c.Request.Context(), c.GetString(tenantIDKey))
+	if err != nil {
+		s.logger.Error("Failed to get portfolios", zap.String("tenant", c.GetString(tenantIDKey)), zap.Error(err))
+		s.storageError(c, err, "failed to get portfolios")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"portfolios": portfolios,
+	})
+}
+
+// getPortfolio handles GET /api/v1/portfolios/:id
+func (s *Server) getPortfolio(c *gin.Context) {
+	portfolio, err := s.storage.Tenants().GetPortfolio(c.Request.Context(), c.GetString(tenantIDKey), c.Param("id"))
+	if err != nil {
+		s.storageError(c, err, "failed to get portfolio")
+		return
+	}
+
+	c.JSON(http.StatusOK, portfolio)
+}
+
+// createPortfolio handles POST /api/v1/portfolios
+func (s *Server) createPortfolio(c *gin.Context) {
+	var req struct {
+		Name     string                   `json:"name"`
+		Accounts []types.PortfolioAccount `json:"accounts"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.badRequest(c, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		s.badRequest(c, "name is required")
+		return
+	}
+	if len(req.Accounts) > maxPortfolioAccounts {
+		s.badRequest(c, "a portfolio holds at most 100 accounts")
+		return
+	}
+	for _, account := range req.Accounts {
+		if err := s.validateChain(account.ChainName); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+		if err := s.validateAddress(account.ChainName, account.Address, ""); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	portfolio := &types.Portfolio{
+		TenantID: c.GetString(tenantIDKey),
+		Name:     strings.TrimSpace(req.Name),
+		Accounts: req.Accounts,
+	}
+	if portfolio.Accounts == nil {
+		portfolio.Accounts = []types.PortfolioAccount{}
+	}
+	if err := s.storage.Tenants().CreatePortfolio(c.Request.Context(), portfolio); err != nil {
+		s.logger.Error("Failed to create portfolio", zap.String("tenant", portfolio.TenantID), zap.Error(err))
+		s.storageError(c, err, "failed to create portfolio")
+		return
+	}
+
+	c.JSON(http.StatusCreated, portfolio)
+}
+
+// deletePortfolio handles DELETE /api/v1/portfolios/:id
+func (s *Server) deletePortfolio(c *gin.Context) {
+	if err := s.storage.Tenants().DeletePortfolio(c.Request.Context(), c.GetString(tenantIDKey), c.Param("id")); err != nil {
+		s.storageError(c, err, "failed to delete portfolio")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getPortfolioSummary handles GET /api/v1/portfolios/:id/summary, adding up
+// the balances, stake and pending rewards of the portfolio's accounts per
+// chain and denom and valuing them at current prices
+func (s *Server) getPortfolioSummary(c *gin.Context) {
+	portfolio, refs, ok := s.portfolioRefs(c)
+	if !ok {
+		return
+	}
+
+	states, err := s.accounts.GetAccountStates(c.Request.Context(), refs)
+	if err != nil {
+		s.logger.Error("Failed to get portfolio accounts",
+			zap.String("portfolio", portfolio.ID),
+			zap.Strings("chains", refChains(refs)),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get portfolio accounts")
+		return
+	}
+
+	prices := s.prices.USD(c.Request.Context())
+	totals := make([]portfolioTotal, 0)
+	var valueUSD float64
+	for _, total := range storage.PortfolioTotals(states, refs) {
+		chain, _ := s.chainConfig(total.ChainName)
+		total.DisplayBalance, _ = chain.DisplayAmount(total.Denom, total.Balance)
+		total.DisplayDelegated, _ = chain.DisplayAmount(total.Denom, total.Delegated)
+
+		value := s.valueUSD(prices, total.ChainName, total.Denom, total.Balance, total.Delegated, total.Unbonding, total.Rewards)
+		if value != nil {
+			valueUSD += *value
+		}
+		totals = append(totals, portfolioTotal{CrossChainTotal: total, ValueUSD: value})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"portfolio": portfolio,
+		"totals":    totals,
+		"value_usd": valueUSD,
+	})
+}
+
+// getPortfolioHistory handles GET /api/v1/portfolios/:id/history, listing
+// what the portfolio's accounts held in each chain's staking denom at the end
+// of each day and its value priced at current prices
+func (s *Server) getPortfolioHistory(c *gin.Context) {
+	days, ok := s.statsDays(c)
+	if !ok {
+		return
+	}
+	portfolio, refs, ok := s.portfolioRefs(c)
+	if !ok {
+		return
+	}
+
+	holdings, err := s.accounts.GetPortfolioHoldings(c.Request.Context(), refs, days)
+	if err != nil {
+		s.logger.Error("Failed to get portfolio history",
+			zap.String("portfolio", portfolio.ID),
+			zap.Strings("chains", refChains(refs)),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get portfolio history")
+		return
+	}
+
+	// Holdings are ordered by day, so each day's value adds up consecutively
+	prices := s.prices.USD(c.Request.Context())
+	valuation := make([]types.PortfolioValuation, 0, days)
+	for i := range holdings {
+		holding := &holdings[i]
+		holding.ValueUSD = s.valueUSD(prices, holding.ChainName, holding.Denom, holding.Balance, holding.Delegated)
+		if n := len(valuation); n == 0 || !valuation[n-1].Date.Equal(holding.Date) {
+			valuation = append(valuation, types.PortfolioValuation{Date: holding.Date})
+		}
+		if holding.ValueUSD != nil {
+			valuation[len(valuation)-1].ValueUSD += *holding.ValueUSD
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"portfolio": portfolio,
+		"days":      days,
+		"holdings":  holdings,
+		"valuation": valuation,
+	})
+}
+
+// portfolioRefs loads the requested portfolio of the tenant and returns the
+// accounts it may read, on enabled chains its role allows, with their chain's
+// staking denom. It writes the error response when it returns false.
+func (s *Server) portfolioRefs(c *gin.Context) (*types.Portfolio, []storage.AccountRef, bool) {
+	portfolio, err := s.storage.Tenants().GetPortfolio(c.Request.Context(), c.GetString(tenantIDKey), c.Param("id"))
+	if err != nil {
+		s.storageError(c, err, "failed to get portfolio")
+		return nil, nil, false
+	}
+
+	policy := policyFromContext(c.Request.Context())
+	refs := make([]storage.AccountRef, 0, len(portfolio.Accounts))
+	for _, account := range portfolio.Accounts {
+		chain, ok := s.chainConfig(account.ChainName)
+		if !ok || !chain.Enabled || !policy.AllowChain(chain.Name) {
+			continue
+		}
+		refs = append(refs, storage.AccountRef{
+			ChainName: chain.Name,
+			Address:   account.Address,
+			Denom:     chain.StakingDenom(),
+		})
+	}
+	return portfolio, refs, true
+}
+
+// valueUSD returns the value in USD of amounts of a chain's staking denom at
+// its current price, or nil for other denoms and chains without a price
+func (s *Server) valueUSD(prices map[string]float64, chainName, denom string, amounts ...string) *float64 {
+	chain, ok := s.chainConfig(chainName)
+	price, priced := prices[chainName]
+	if !ok || !priced || denom != chain.StakingDenom() {
+		return nil
+	}
+
+	total := decimal.Zero
+	for _, amount := range amounts {
+		if value, err := decimal.NewFromString(amount); err == nil {
+			total = total.Add(value)
+		}
+	}
+	value := total.Shift(-int32(chain.DenomExponent)).Mul(decimal.NewFromFloat(price)).InexactFloat64()
+	return &value
+}
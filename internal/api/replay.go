@@ -0,0 +1,302 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/authz"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Replay page sizes, the number of events read from ClickHouse at a time
+const (
+	defaultReplayPageSize = 1000
+	maxReplayPageSize     = 10000
+)
+
+// Balance history limits, the number of points returned at most
+const (
+	defaultBalanceHistoryLimit = 1000
+	maxBalanceHistoryLimit     = 10000
+)
+
+// replayModules maps replayable event types to the modules whose data they are
+var replayModules = map[string]string{
+	storage.AccountEventBalance:    "bank",
+	storage.AccountEventDelegation: "staking",
+}
+
+// timeParam parses an optional query parameter given as a date (YYYY-MM-DD)
+// or RFC 3339 time
+func timeParam(c *gin.Context, name string) (time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC 3339 time", name)
+}
+
+// replayFilter parses the type, height and time query parameters of a replay.
+// Event types the API key's role may not read are dropped unless requested
+// explicitly, which is denied.
+func (s *Server) replayFilter(c *gin.Context) (types.AccountEventFilter, error) {
+	var filter types.AccountEventFilter
+
+	requested := splitChains(c.Query("type"))
+	for _, eventType := range requested {
+		if _, ok := replayModules[eventType]; !ok {
+			return filter, fmt.Errorf("unknown event type %q; known types: balance, delegation", eventType)
+		}
+	}
+	policy := policyFromContext(c.Request.Context())
+	for _, eventType := range []string{storage.AccountEventBalance, storage.AccountEventDelegation} {
+		if len(requested) > 0 && !slices.Contains(requested, eventType) {
+			continue
+		}
+		resource := authz.Resource{Endpoint: authz.EndpointEvents, Modules: []string{replayModules[eventType]}}
+		if err := policy.Authorize(resource, nil); err != nil {
+			if len(requested) > 0 {
+				return filter, err
+			}
+			continue
+		}
+		filter.Types = append(filter.Types, eventType)
+	}
+
+	for name, height := range map[string]*int64{"from_height": &filter.FromHeight, "to_height": &filter.ToHeight} {
+		if value := c.Query(name); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed < 0 {
+				return filter, fmt.Errorf("%s must be a non-negative integer", name)
+			}
+			*height = parsed
+		}
+	}
+	if filter.ToHeight > 0 && filter.FromHeight > filter.ToHeight {
+		return filter, fmt.Errorf("from_height must not be above to_height")
+	}
+
+	var err error
+	if filter.From, err = timeParam(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = timeParam(c, "to"); err != nil {
+		return filter, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+
+	return filter, nil
+}
+
+// replayAccountEvents handles GET /api/v1/chains/:chain/accounts/:address/events.
+// It streams the account's balance and delegation events in height order as
+// newline-delimited JSON, reading them from ClickHouse a page at a time. Each
+// event carries a cursor that resumes the replay after it.
+func (s *Server) replayAccountEvents(c *gin.Context) {
+	chainName, address := c.Param("chain"), c.Param("address")
+	if err := s.validateAddress(chainName, address, ""); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	analytics := s.storage.ClickHouse()
+	if analytics == nil {
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, "analytics storage is not available")
+		return
+	}
+
+	filter, err := s.replayFilter(c)
+	if err != nil {
+		if errors.Is(err, authz.ErrDenied) {
+			s.abortWithError(c, http.StatusForbidden, CodePermissionDenied, err.Error())
+			return
+		}
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	pageSize := defaultReplayPageSize
+	if value := c.Query("page_size"); value != "" {
+		pageSize, err = strconv.Atoi(value)
+		if err != nil || pageSize <= 0 || pageSize > maxReplayPageSize {
+			s.badRequest(c, fmt.Sprintf("page_size must be between 1 and %d", maxReplayPageSize))
+			return
+		}
+	}
+
+	cursor := c.Query("cursor")
+	if cursor != "" {
+		if err := storage.ValidateReplayCursor(cursor); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+	if len(filter.Types) == 0 {
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+		return
+	}
+
+	ctx := c.Request.Context()
+	enc := json.NewEncoder(c.Writer)
+	for started := false; ; started = true {
+		events, err := analytics.ReplayAccountEvents(ctx, chainName, address, filter, cursor, pageSize)
+		if err != nil {
+			s.logger.Error("Failed to replay account events",
+				zap.String("chain", chainName),
+				zap.String("address", address),
+				zap.Error(err))
+			if !started {
+				s.storageError(c, err, "failed to replay account events")
+				return
+			}
+			// The status is already sent; report the failure in-band so that
+			// clients resume from the last cursor they received
+			enc.Encode(gin.H{"error": "failed to replay account events"})
+			return
+		}
+
+		if !started {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+		}
+		for i := range events {
+			if err := enc.Encode(&events[i]); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+
+		if len(events) < pageSize || ctx.Err() != nil {
+			return
+		}
+		cursor = events[len(events)-1].Cursor
+	}
+}
+
+// reconstructBalances handles GET /api/v1/chains/:chain/accounts/:address/reconstructed-balances
+func (s *Server) reconstructBalances(c *gin.Context) {
+	chainName, address := c.Param("chain"), c.Param("address")
+	if err := s.validateAddress(chainName, address, ""); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	at, err := timeParam(c, "time")
+	if err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+	if at.IsZero() {
+		s.badRequest(c, "time is required")
+		return
+	}
+
+	reconstruction, err := s.storage.ReconstructBalances(c.Request.Context(), chainName, address, c.Query("denom"), at)
+	if err != nil {
+		s.logger.Error("Failed to reconstruct balances",
+			zap.String("chain", chainName),
+			zap.String("address", address),
+			zap.Time("time", at),
+			zap.Error(err))
+		s.storageError(c, err, "failed to reconstruct balances")
+		return
+	}
+
+	c.JSON(http.StatusOK, reconstruction)
+}
+
+// getBalanceHistory handles GET /api/v1/chains/:chain/accounts/:address/balance-history.
+// It lists the address's balance in a denom, the staking denom by default,
+// newest first: every change, or with resolution=1h or 1d the last balance of
+// each hour or day, so that charts of long ranges don't read every change.
+func (s *Server) getBalanceHistory(c *gin.Context) {
+	chainName, address := c.Param("chain"), c.Param("address")
+	if err := s.validateAddress(chainName, address, ""); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	chain, _ := s.chainConfig(chainName)
+	denom := c.Query("denom")
+	if denom == "" {
+		denom = chain.StakingDenom()
+	}
+
+	resolution := c.DefaultQuery("resolution", storage.ResolutionRaw)
+	switch resolution {
+	case storage.ResolutionRaw, storage.ResolutionHour, storage.ResolutionDay:
+	default:
+		s.badRequest(c, fmt.Sprintf("resolution must be %s, %s or %s",
+			storage.ResolutionRaw, storage.ResolutionHour, storage.ResolutionDay))
+		return
+	}
+
+	from, err := timeParam(c, "from")
+	if err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+	to, err := timeParam(c, "to")
+	if err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		s.badRequest(c, "from must be before to")
+		return
+	}
+
+	limit := defaultBalanceHistoryLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxBalanceHistoryLimit {
+			s.badRequest(c, fmt.Sprintf("limit must be between 1 and %d", maxBalanceHistoryLimit))
+			return
+		}
+	}
+
+	history, err := s.analytics.GetBalanceHistory(c.Request.Context(), storage.BalanceHistoryQuery{
+		ChainName:  chainName,
+		Address:    address,
+		Denom:      denom,
+		Resolution: resolution,
+		From:       from,
+		To:         to,
+		Limit:      limit,
+	})
+	if err != nil {
+		s.logger.Error("Failed to get balance history",
+			zap.String("chain", chainName),
+			zap.String("address", address),
+			zap.String("denom", denom),
+			zap.String("resolution", resolution),
+			zap.Error(err))
+		s.storageError(c, err, "failed to get balance history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"chain":      chainName,
+		"address":    address,
+		"denom":      denom,
+		"resolution": resolution,
+		"history":    history,
+	})
+}
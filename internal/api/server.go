@@ -2,124 +2,385 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	gql "github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/gin-gonic/gin"
+	"github.com/cosmos/state-mesh/internal/authz"
 	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/diagnostics"
 	"github.com/cosmos/state-mesh/internal/graphql"
 	"github.com/cosmos/state-mesh/internal/graphql/generated"
+	"github.com/cosmos/state-mesh/internal/health"
+	"github.com/cosmos/state-mesh/internal/logging"
+	"github.com/cosmos/state-mesh/internal/prices"
 	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/telemetry"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vektah/gqlparser/v2/ast"
 	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 // Server represents the API server
 type Server struct {
 	cfg           config.APIConfig
+	chains        []config.ChainConfig
 	storage       *storage.Manager
 	logger        *zap.Logger
+
+	// Read models of the account, validator, governance and analytics
+	// endpoints, which the storage manager implements
+	accounts   storage.AccountReader
+	validators storage.ValidatorReader
+	gov        storage.GovReader
+	analytics  storage.AnalyticsReader
+
+	health        *health.Checker
+	roles         map[string]*authz.Policy
+	cors          *corsPolicy
+	audit         *auditLog
+	events        *eventHub
+	logControl    *logging.Control // nil when the logger was not built from configuration
+	prices        *prices.Source
+	overview      overviewCache
+
+	// Startup checks gate the startup probe and readiness until they pass
+	// once; draining fails readiness while the server shuts down
+	startup  *health.Checker
+	started  atomic.Bool
+	draining atomic.Bool
+
+	mu            sync.Mutex
+	closed        bool
 	graphqlServer *http.Server
 	restServer    *http.Server
 	metricsServer *http.Server
+	unifiedServer *http.Server
 }
 
 // NewServer creates a new API server
-func NewServer(cfg config.APIConfig, storage *storage.Manager, logger *zap.Logger) (*Server, error) {
-	return &Server{
+func NewServer(cfg config.APIConfig, chains []config.ChainConfig, storage *storage.Manager, logger *zap.Logger) (*Server, error) {
+	checker := health.NewChecker(5 * time.Second)
+	checker.Register(storage.Driver(), storage.State().Ping)
+	if storage.ClickHouse() != nil {
+		checker.Register("clickhouse", storage.ClickHouse().Ping)
+	}
+
+	roles := make(map[string]*authz.Policy, len(cfg.Tenancy.Roles))
+	for name, role := range cfg.Tenancy.Roles {
+		policy, err := authz.NewPolicy(name, role)
+		if err != nil {
+			return nil, err
+		}
+		roles[name] = policy
+	}
+
+	s := &Server{
 		cfg:     cfg,
+		chains:  chains,
 		storage: storage,
 		logger:  logger.Named("api"),
-	}, nil
+
+		accounts:   storage,
+		validators: storage,
+		gov:        storage,
+		analytics:  storage,
+
+		health:  checker,
+		startup: health.NewChecker(5 * time.Second),
+		roles:   roles,
+		events:  newEventHub(),
+		prices:  prices.New(cfg.Overview, chains, logger),
+	}
+
+	if cfg.CORS.Enabled {
+		s.cors = newCORSPolicy(cfg.CORS)
+	}
+
+	if cfg.Tenancy.Enabled && cfg.Tenancy.Audit.Enabled {
+		if storage.ClickHouse() == nil {
+			s.logger.Warn("ClickHouse is not available, API audit logging is disabled")
+		} else {
+			s.audit = newAuditLog(cfg.Tenancy.Audit, storage.ClickHouse(), s.logger)
+		}
+	}
+
+	return s, nil
+}
+
+// RegisterHealthCheck adds a dependency check to the readiness probe
+func (s *Server) RegisterHealthCheck(name string, check health.CheckFunc) {
+	s.health.Register(name, check)
+}
+
+// RegisterStartupCheck adds a check the startup probe waits for, such as the
+// database migrations. Readiness fails until every startup check has passed.
+func (s *Server) RegisterStartupCheck(name string, check health.CheckFunc) {
+	s.startup.Register(name, check)
+}
+
+// Drain fails the readiness probe so that load balancers stop routing
+// requests to the server ahead of Shutdown
+func (s *Server) Drain() {
+	s.draining.Store(true)
+}
+
+// UseLogControl lets the admin API change the log levels and sampling
+func (s *Server) UseLogControl(control *logging.Control) {
+	s.logControl = control
 }
 
 // StartGraphQL starts the GraphQL server
 func (s *Server) StartGraphQL(ctx context.Context) error {
+	handler, err := s.graphqlHandler()
+	if err != nil {
+		return err
+	}
+
+	graphqlServer, err := s.newHTTPServer(s.cfg.GraphQL.Port, handler)
+	if err != nil {
+		return err
+	}
+	if err := s.track(&s.graphqlServer, graphqlServer); err != nil {
+		return err
+	}
+
+	s.logger.Info("GraphQL server starting", zap.Int("port", s.cfg.GraphQL.Port), zap.Bool("tls", s.cfg.TLS.Enabled))
+
+	if err := s.serve(graphqlServer); err != nil {
+		return fmt.Errorf("GraphQL server error: %w", err)
+	}
+
+	return nil
+}
+
+// graphqlHandler serves GraphQL, the playground and the health checks
+func (s *Server) graphqlHandler() (http.Handler, error) {
 	// Initialize GraphQL handler
 	graphqlHandler, err := s.setupGraphQLHandler()
 	if err != nil {
-		return fmt.Errorf("failed to setup GraphQL handler: %w", err)
+		return nil, fmt.Errorf("failed to setup GraphQL handler: %w", err)
 	}
 
+	if s.cfg.Tenancy.Enabled {
+		graphqlHandler = s.tenantMiddleware(s.auditGraphQL(graphqlHandler))
+	}
+	graphqlHandler = s.requestTimeout(graphqlHandler)
+
 	mux := http.NewServeMux()
 	mux.Handle("/graphql", graphqlHandler)
-	
+
 	if s.cfg.GraphQL.Playground {
 		playgroundHandler := s.setupPlaygroundHandler()
 		mux.Handle("/playground", playgroundHandler)
 	}
 
-	// Health check endpoint
-	mux.HandleFunc("/health", s.healthHandler)
+	// Health check endpoints
+	mux.HandleFunc("/health", s.readinessHandler)
+	mux.HandleFunc("/healthz", s.livenessHandler)
+	mux.HandleFunc("/readyz", s.readinessHandler)
+	mux.HandleFunc("/startupz", s.startupHandler)
 
-	s.graphqlServer = &http.Server{
-		Addr:    fmt.Sprintf(":%d", s.cfg.GraphQL.Port),
-		Handler: s.corsMiddleware(mux),
+	return s.corsMiddleware(mux), nil
+}
+
+// StartREST starts the REST server
+func (s *Server) StartREST(ctx context.Context) error {
+	restServer, err := s.newHTTPServer(s.cfg.REST.Port, s.restHandler())
+	if err != nil {
+		return err
+	}
+	if err := s.track(&s.restServer, restServer); err != nil {
+		return err
 	}
 
-	s.logger.Info("GraphQL server starting", zap.Int("port", s.cfg.GraphQL.Port))
+	s.logger.Info("REST server starting", zap.Int("port", s.cfg.REST.Port), zap.Bool("tls", s.cfg.TLS.Enabled))
 
-	if err := s.graphqlServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		return fmt.Errorf("GraphQL server error: %w", err)
+	if err := s.serve(restServer); err != nil {
+		return fmt.Errorf("REST server error: %w", err)
 	}
 
 	return nil
 }
 
-// StartREST starts the REST server
-func (s *Server) StartREST(ctx context.Context) error {
+// restHandler serves the REST and admin APIs
+func (s *Server) restHandler() http.Handler {
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(s.reportPanics())
+	router.Use(s.requestIDMiddleware())
 	router.Use(s.ginLogger())
+	router.Use(s.ginRequestTimeout())
 
-	if s.cfg.CORS.Enabled {
+	if s.cors != nil {
 		router.Use(s.ginCORS())
 	}
 
 	// Setup REST routes
 	s.setupRESTRoutes(router)
+	router.NoRoute(func(c *gin.Context) {
+		if s.unsupportedVersion(c) {
+			return
+		}
+		s.abortWithError(c, http.StatusNotFound, CodeNotFound, "route not found")
+	})
+
+	// Paths without an API version are routed to the negotiated one
+	return s.negotiateVersion(router)
+}
 
-	s.restServer = &http.Server{
-		Addr:    fmt.Sprintf(":%d", s.cfg.REST.Port),
-		Handler: router,
+// StartMetrics starts the metrics server
+func (s *Server) StartMetrics(ctx context.Context) error {
+	metricsServer := &http.Server{
+		Addr:    fmt.Sprintf(":%d", s.cfg.Metrics.Port),
+		Handler: s.metricsHandler(),
+	}
+	if err := s.track(&s.metricsServer, metricsServer); err != nil {
+		return err
 	}
 
-	s.logger.Info("REST server starting", zap.Int("port", s.cfg.REST.Port))
+	s.logger.Info("Metrics server starting", zap.Int("port", s.cfg.Metrics.Port))
 
-	if err := s.restServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		return fmt.Errorf("REST server error: %w", err)
+	if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("metrics server error: %w", err)
 	}
 
 	return nil
 }
 
-// StartMetrics starts the metrics server
-func (s *Server) StartMetrics(ctx context.Context) error {
+// metricsHandler serves Prometheus metrics and the health checks
+func (s *Server) metricsHandler() http.Handler {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
-	mux.HandleFunc("/health", s.healthHandler)
+	mux.HandleFunc("/health", s.readinessHandler)
+	mux.HandleFunc("/healthz", s.livenessHandler)
+	mux.HandleFunc("/readyz", s.readinessHandler)
+	mux.HandleFunc("/startupz", s.startupHandler)
+	if s.cfg.Metrics.Diagnostics {
+		diagnostics.Handle(mux, nil)
+	}
+	return mux
+}
 
-	s.metricsServer = &http.Server{
-		Addr:    fmt.Sprintf(":%d", s.cfg.Metrics.Port),
-		Handler: mux,
+// StartUnified serves GraphQL, REST and metrics on a single port: GraphQL
+// (including websocket subscriptions) and the playground under /graphql and
+// /playground, metrics under /metrics and everything else, including the
+// /api/v1, /api/v2 and /admin/v1 routes, through the REST router. Diagnostics are
+// not served on the public port.
+func (s *Server) StartUnified(ctx context.Context) error {
+	graphqlHandler, err := s.graphqlHandler()
+	if err != nil {
+		return err
 	}
+	metricsHandler := s.metricsHandler()
 
-	s.logger.Info("Metrics server starting", zap.Int("port", s.cfg.Metrics.Port))
+	mux := http.NewServeMux()
+	mux.Handle("/graphql", graphqlHandler)
+	mux.Handle("/playground", graphqlHandler)
+	mux.Handle("/metrics", metricsHandler)
+	mux.HandleFunc("/health", s.readinessHandler)
+	mux.HandleFunc("/healthz", s.livenessHandler)
+	mux.HandleFunc("/readyz", s.readinessHandler)
+	mux.HandleFunc("/startupz", s.startupHandler)
+	mux.Handle("/", s.restHandler())
+
+	unifiedServer, err := s.newHTTPServer(s.cfg.Unified.Port, mux)
+	if err != nil {
+		return err
+	}
+	if err := s.track(&s.unifiedServer, unifiedServer); err != nil {
+		return err
+	}
 
-	if err := s.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		return fmt.Errorf("metrics server error: %w", err)
+	s.logger.Info("Unified server starting", zap.Int("port", s.cfg.Unified.Port), zap.Bool("tls", s.cfg.TLS.Enabled))
+
+	if err := s.serve(unifiedServer); err != nil {
+		return fmt.Errorf("unified server error: %w", err)
 	}
 
 	return nil
 }
 
+// newHTTPServer creates an API server with the configured response compression
+// and HTTP/2 support
+func (s *Server) newHTTPServer(port int, handler http.Handler) (*http.Server, error) {
+	if s.cfg.Compression.Enabled {
+		handler = compressionMiddleware(handler, s.cfg.Compression.MinSize)
+	}
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: handler,
+	}
+
+	switch {
+	case !s.cfg.HTTP2:
+		// A non-nil empty map disables HTTP/2 over TLS
+		srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	case !s.cfg.TLS.Enabled:
+		// Cleartext HTTP/2 (h2c); configuring the server lets Shutdown close
+		// h2c connections gracefully
+		h2s := &http2.Server{}
+		if err := http2.ConfigureServer(srv, h2s); err != nil {
+			return nil, fmt.Errorf("failed to configure HTTP/2: %w", err)
+		}
+		srv.Handler = h2c.NewHandler(handler, h2s)
+	}
+
+	return srv, nil
+}
+
+// track records a server for Shutdown to stop. A server started after
+// Shutdown is not served.
+func (s *Server) track(field **http.Server, srv *http.Server) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return http.ErrServerClosed
+	}
+	*field = srv
+	return nil
+}
+
+// serve runs an API server until it is shut down, terminating TLS when
+// configured
+func (s *Server) serve(srv *http.Server) error {
+	var err error
+	if s.cfg.TLS.Enabled {
+		err = srv.ListenAndServeTLS(s.cfg.TLS.CertFile, s.cfg.TLS.KeyFile)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
 // Shutdown gracefully shuts down all servers
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.Drain()
+
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
 	var errs []error
 
 	if s.graphqlServer != nil {
@@ -140,6 +401,17 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	if s.unifiedServer != nil {
+		if err := s.unifiedServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("unified server shutdown error: %w", err))
+		}
+	}
+
+	// Write the audit events of the calls served before shutdown
+	if s.audit != nil {
+		s.audit.stop()
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("server shutdown errors: %v", errs)
 	}
@@ -150,10 +422,40 @@ func (s *Server) Shutdown(ctx context.Context) error {
 // setupGraphQLHandler sets up the GraphQL handler using gqlgen
 func (s *Server) setupGraphQLHandler() (http.Handler, error) {
 	// Initialize GraphQL resolver with storage and logger
-	resolver := graphql.NewResolver(s.storage, s.logger)
+	resolver := graphql.NewResolver(s.storage, s.chains, s.logger)
 	
-	// Create gqlgen server with the resolver
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	// Create gqlgen server with the resolver and the default transports
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.Websocket{KeepAlivePingInterval: 10 * time.Second})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	srv.Use(extension.Introspection{})
+	srv.SetRecoverFunc(func(ctx context.Context, err any) error {
+		telemetry.CapturePanic(err, map[string]string{"endpoint": graphqlEndpoint})
+		return gql.DefaultRecover(ctx, err)
+	})
+
+	// An allowlist replaces automatic persisted queries so that clients
+	// cannot register queries of their own
+	persisted := s.cfg.GraphQL.PersistedQueries
+	switch {
+	case persisted.Allowlist != "":
+		allowlist, err := loadQueryAllowlist(persisted.Allowlist)
+		if err != nil {
+			return nil, err
+		}
+		srv.Use(allowlist)
+		s.logger.Info("GraphQL persisted query allowlist loaded", zap.Int("queries", len(allowlist.hashes)))
+	case persisted.APQ:
+		srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New[string](persisted.CacheSize)})
+	}
+
+	if s.cfg.Tenancy.Enabled {
+		srv.AroundFields(s.authorizeField)
+	}
 	
 	return srv, nil
 }
@@ -192,38 +494,137 @@ func (s *Server) setupPlaygroundHandler() http.Handler {
 	})
 }
 
-// setupRESTRoutes sets up REST API routes
+// setupRESTRoutes sets up the REST API routes of every API version
 func (s *Server) setupRESTRoutes(router *gin.Engine) {
-	api := router.Group("/api/v1")
+	for _, version := range APIVersions {
+		s.setupVersionRoutes(router, version)
+	}
+	if s.cfg.Tenancy.AdminKey != "" {
+		s.setupAdminRoutes(router)
+	}
+}
+
+// setupVersionRoutes sets up the REST API routes of an API version under
+// /api/<version>. Versions share their routes except where a later version
+// changes a response's shape.
+func (s *Server) setupVersionRoutes(router *gin.Engine, version string) {
+	prefix := "/api/" + version
+	api := router.Group(prefix, s.apiVersion(version))
+
+	// Health checks
+	api.GET("/health", s.ginReadinessHandler)
+	api.GET("/healthz", s.ginLivenessHandler)
+	api.GET("/readyz", s.ginReadinessHandler)
+	api.GET("/startupz", s.ginStartupHandler)
+
+	// With tenancy enabled every other route needs a tenant API key whose
+	// role allows it, and calls are audited. Unsubscribe links carry the
+	// token of an email subscription instead.
+	if s.cfg.Tenancy.Enabled {
+		api.GET("/unsubscribe", s.getUnsubscribe)
+		api.POST("/unsubscribe", s.unsubscribe)
+
+		api = router.Group(prefix, s.apiVersion(version), s.requireTenant(), s.auditREST(), s.authorize())
+		s.setupTenantRoutes(api)
+	}
+
+	// Search
+	api.GET("/search", s.requireKnownChainsQuery(), s.search)
+
+	// Server-sent events
+	api.GET("/stream", s.streamEvents)
+
+	// Network overview across all chains
+	api.GET("/overview", s.getOverview)
+
+	// SQL views for external GraphQL engines and BI tools
+	api.GET("/views", s.getSQLViews)
 
-	// Health check
-	api.GET("/health", s.ginHealthHandler)
+	// Grafana JSON datasource
+	grafana := api.Group("/grafana")
+	{
+		grafana.GET("/", s.grafanaHealth)
+		grafana.POST("/metrics", s.grafanaMetrics)
+		grafana.POST("/variable", s.grafanaVariable)
+		grafana.POST("/query", s.grafanaQuery)
+		grafana.POST("/annotations", s.grafanaAnnotations)
+	}
 
 	// Account routes
-	accounts := api.Group("/accounts")
+	accounts := api.Group("/accounts/:address", s.requireValidAccount())
 	{
-		accounts.GET("/:address/balances", s.getAccountBalances)
-		accounts.GET("/:address/delegations", s.getAccountDelegations)
-		accounts.GET("/:address/state", s.getAccountState)
+		accounts.GET("/balances", s.getAccountBalances)
+		accounts.GET("/delegations", s.getAccountDelegations)
+		accounts.GET("/unbondings", s.getAccountUnbondings)
+		accounts.GET("/state", s.getAccountState)
+		accounts.GET("/activity", s.getAccountActivity)
+		accounts.GET("/rewards", s.getAccountRewards)
 	}
 
 	// Chain routes
 	chains := api.Group("/chains")
 	{
 		chains.GET("/", s.getChains)
-		chains.GET("/:chain/validators", s.getValidators)
-		chains.GET("/:chain/stats", s.getChainStats)
+	}
+
+	chain := chains.Group("/:chain", s.requireKnownChain())
+	{
+		chain.GET("/accounts/:address/events", s.replayAccountEvents)
+		chain.GET("/accounts/:address/reconstructed-balances", s.reconstructBalances)
+		chain.GET("/accounts/:address/balance-history", s.getBalanceHistory)
+		chain.GET("/blocks", s.getBlocks)
+		chain.GET("/blocks/:height", s.getBlock)
+		chain.GET("/validators", s.getValidators)
+		chain.GET("/consumer-validators", s.getConsumerValidators)
+		chain.GET("/validator-set", s.getValidatorSet)
+		chain.GET("/validators/:address/delegators", s.requireValidValidator(), s.getValidatorDelegators)
+		chain.GET("/validators/:address/commission-changes", s.requireValidValidator(), s.getValidatorCommissionChanges)
+		chain.GET("/stats", s.getChainStats)
+		chain.GET("/stats/active-addresses", s.getDailyActiveAddresses)
+		chain.GET("/stats/delegation-volume", s.getDailyDelegationVolume)
+		chain.GET("/stats/reward-issuance", s.getDailyRewardIssuance)
+		chain.GET("/stats/fees", s.getFeeStats)
+		chain.GET("/stats/messages", s.getMessageActivity)
+		chain.GET("/stats/epochs", s.getEpochStats)
+		chain.GET("/stats/base-fee", s.getBaseFeeHistory)
+		chain.GET("/gas-prices", s.getGasPrices)
+		chain.GET("/ibc/channels", s.getChannelPerformance)
+		chain.GET("/ibc/relayers", s.getRelayerLeaderboard)
+		chain.GET("/ibc/stuck-packets", s.getStuckPackets)
+		chain.GET("/stats/unbonding-schedule", s.getUnbondingSchedule)
+		chain.GET("/stats/block-production", s.getBlockProduction)
+		chain.GET("/stats/delegation-flows", s.getDelegationFlows)
+		chain.GET("/stats/anomalies", s.getAnomalies)
+		chain.GET("/stats/halts", s.getHalts)
+		chain.GET("/stats/top-holders", s.getTopHolders)
+		chain.GET("/stats/supply", s.getSupplyBreakdown)
+		chain.GET("/supply/circulating", s.getCirculatingSupply)
+		chain.GET("/params/:module", s.requireParamsModule(), s.getChainParams)
+		chain.GET("/params/:module/changes", s.requireParamsModule(), s.getChainParamChanges)
 	}
 
 	// Cross-chain routes
 	crosschain := api.Group("/cross-chain")
 	{
-		crosschain.GET("/accounts/:address", s.getCrossChainAccount)
+		if version == APIVersion1 {
+			crosschain.GET("/accounts/:address", s.requireValidAccount(), s.getCrossChainAccount)
+		} else {
+			crosschain.GET("/accounts/:address", s.requireValidAccount(), s.getCrossChainAccountV2)
+		}
 		crosschain.GET("/validators", s.getCrossChainValidators)
+		crosschain.GET("/upgrades", s.getUpgradeCalendar)
+		crosschain.GET("/gas-prices", s.getCrossChainGasPrices)
+		crosschain.GET("/params/:module", s.requireParamsModule(), s.getCrossChainParams)
+	}
+
+	// Curated ClickHouse queries
+	if s.cfg.Analytics.Enabled {
+		api.GET("/analytics/queries", s.getAnalyticsQueries)
+		chain.GET("/analytics/:query", s.runAnalyticsQuery)
 	}
 
 	// Governance routes
-	gov := api.Group("/governance")
+	gov := api.Group("/governance", s.requireKnownChainQuery())
 	{
 		gov.GET("/proposals", s.getProposals)
 		gov.GET("/proposals/:id", s.getProposal)
@@ -231,80 +632,161 @@ func (s *Server) setupRESTRoutes(router *gin.Engine) {
 	}
 }
 
-// healthHandler handles health check requests
-func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
+// setupAdminRoutes sets up the admin API, authenticated with the admin key.
+// Tenant management is only mounted with tenancy enabled.
+func (s *Server) setupAdminRoutes(router *gin.Engine) {
+	admin := router.Group("/admin/v1", s.requireAdmin())
+	{
+		admin.GET("/labels", s.getLabels)
+		admin.PUT("/labels/:chain/:address", s.putLabel)
+		admin.DELETE("/labels/:chain/:address", s.deleteLabel)
+		admin.GET("/ingest-runs", s.getIngestRuns)
+		admin.GET("/logging", s.getLogging)
+		admin.PUT("/logging", s.putLogging)
+	}
 
-	// Check database connectivity
-	if err := s.storage.Ping(ctx); err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write([]byte(`{"status": "unhealthy", "error": "database connection failed"}`))
+	if !s.cfg.Tenancy.Enabled {
 		return
 	}
+	admin.GET("/tenants", s.getTenants)
+	admin.POST("/tenants", s.createTenant)
+	admin.GET("/tenants/:tenant", s.getTenant)
+	admin.DELETE("/tenants/:tenant", s.deleteTenant)
+	admin.GET("/tenants/:tenant/api-keys", s.getAPIKeys)
+	admin.POST("/tenants/:tenant/api-keys", s.createAPIKey)
+	admin.DELETE("/tenants/:tenant/api-keys/:id", s.revokeAPIKey)
+	admin.GET("/usage", s.getAdminUsage)
+}
 
+// setupTenantRoutes sets up the tenant-scoped routes
+func (s *Server) setupTenantRoutes(api *gin.RouterGroup) {
+	api.GET("/usage", s.getUsage)
+
+	api.GET("/watchlists", s.getWatchlists)
+	api.POST("/watchlists", s.createWatchlist)
+	api.GET("/watchlists/:id", s.getWatchlist)
+	api.DELETE("/watchlists/:id", s.deleteWatchlist)
+
+	api.GET("/webhooks", s.getWebhooks)
+	api.POST("/webhooks", s.createWebhook)
+	api.DELETE("/webhooks/:id", s.deleteWebhook)
+
+	api.GET("/email-subscriptions", s.getEmailSubscriptions)
+	api.POST("/email-subscriptions", s.createEmailSubscription)
+	api.DELETE("/email-subscriptions/:id", s.deleteEmailSubscription)
+
+	api.GET("/alert-rules", s.getAlertRules)
+	api.POST("/alert-rules", s.createAlertRule)
+	api.DELETE("/alert-rules/:id", s.deleteAlertRule)
+
+	api.GET("/digests", s.getDigests)
+	api.POST("/digests", s.createDigest)
+	api.DELETE("/digests/:id", s.deleteDigest)
+
+	api.GET("/portfolios", s.getPortfolios)
+	api.POST("/portfolios", s.createPortfolio)
+	api.GET("/portfolios/:id", s.getPortfolio)
+	api.DELETE("/portfolios/:id", s.deletePortfolio)
+	api.GET("/portfolios/:id/summary", s.getPortfolioSummary)
+	api.GET("/portfolios/:id/history", s.getPortfolioHistory)
+}
+
+// livenessHandler reports that the process is running
+func (s *Server) livenessHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(`{"status": "healthy"}`))
 }
 
-// ginHealthHandler handles health check requests for Gin
-func (s *Server) ginHealthHandler(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
-	defer cancel()
+// startupReport runs the startup checks until they have all passed once
+func (s *Server) startupReport(ctx context.Context) *health.Report {
+	if s.started.Load() {
+		return &health.Report{Status: health.StatusHealthy, Checks: []health.CheckResult{}, CheckedAt: time.Now()}
+	}
 
-	// Check database connectivity
-	if err := s.storage.Ping(ctx); err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status": "unhealthy",
-			"error":  "database connection failed",
-		})
-		return
+	report := s.startup.Check(ctx)
+	if report.Healthy() {
+		s.started.Store(true)
 	}
+	return report
+}
 
-	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+// readinessReport checks the dependencies once the server has started and
+// while it is not draining
+func (s *Server) readinessReport(ctx context.Context) *health.Report {
+	if s.draining.Load() {
+		return &health.Report{Status: health.StatusDraining, Checks: []health.CheckResult{}, CheckedAt: time.Now()}
+	}
+	if report := s.startupReport(ctx); !report.Healthy() {
+		return report
+	}
+	return s.health.Check(ctx)
 }
 
-// corsMiddleware adds CORS headers
-func (s *Server) corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if s.cfg.CORS.Enabled {
-			origin := "*"
-			if len(s.cfg.CORS.Origins) > 0 && s.cfg.CORS.Origins[0] != "*" {
-				origin = s.cfg.CORS.Origins[0]
-			}
-
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-		}
+// startupHandler reports whether the startup checks have passed and returns
+// 503 until they have
+func (s *Server) startupHandler(w http.ResponseWriter, r *http.Request) {
+	report := s.startupReport(r.Context())
 
-		next.ServeHTTP(w, r)
-	})
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
 }
 
-// ginCORS adds CORS middleware for Gin
-func (s *Server) ginCORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		origin := "*"
-		if len(s.cfg.CORS.Origins) > 0 && s.cfg.CORS.Origins[0] != "*" {
-			origin = s.cfg.CORS.Origins[0]
-		}
+// readinessHandler reports per-dependency status and returns 503 when any
+// dependency is degraded, before startup completes and while draining
+func (s *Server) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := s.readinessReport(r.Context())
 
-		c.Header("Access-Control-Allow-Origin", origin)
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusOK)
-			return
-		}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// ginLivenessHandler reports that the process is running for Gin
+func (s *Server) ginLivenessHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusHealthy})
+}
+
+// ginStartupHandler reports whether the startup checks have passed for Gin
+func (s *Server) ginStartupHandler(c *gin.Context) {
+	report := s.startupReport(c.Request.Context())
+
+	if !report.Healthy() {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ginReadinessHandler reports per-dependency status for Gin
+func (s *Server) ginReadinessHandler(c *gin.Context) {
+	report := s.readinessReport(c.Request.Context())
+
+	if !report.Healthy() {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
 
+	c.JSON(http.StatusOK, report)
+}
+
+// reportPanics reports panics in handlers with their route before Gin
+// recovers from them
+func (s *Server) reportPanics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer telemetry.Recover(map[string]string{"endpoint": c.FullPath()})
 		c.Next()
 	}
 }
@@ -333,6 +815,7 @@ func (s *Server) ginLogger() gin.HandlerFunc {
 			zap.Int("status", statusCode),
 			zap.Duration("latency", latency),
 			zap.String("client_ip", clientIP),
+			zap.String("request_id", c.GetString(requestIDKey)),
 		)
 	}
 }
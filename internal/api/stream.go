@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/authz"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// streamHeartbeat is how often idle streams send a comment to keep proxies
+// from closing the connection
+const streamHeartbeat = 15 * time.Second
+
+// streamBufferSize is the number of events buffered per stream before events
+// are dropped for that client
+const streamBufferSize = 256
+
+var streamEventsDropped = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "statemesh",
+	Subsystem: "api",
+	Name:      "stream_events_dropped_total",
+	Help:      "Streamed events dropped because a client did not keep up",
+})
+
+// streamFilter selects the events a stream receives. Empty sets match
+// everything.
+type streamFilter struct {
+	chains    map[string]bool
+	types     map[string]bool
+	addresses map[string]bool
+	policy    *authz.Policy
+}
+
+// matches reports whether an event passes the filter and the API key's policy
+func (f *streamFilter) matches(event *streaming.Event) bool {
+	if len(f.chains) > 0 && !f.chains[event.Chain] {
+		return false
+	}
+	if len(f.types) > 0 && !f.types[event.Type] {
+		return false
+	}
+	if len(f.addresses) > 0 && !slices.ContainsFunc(event.Addresses, func(address string) bool {
+		return f.addresses[address]
+	}) {
+		return false
+	}
+
+	resource := authz.Resource{Endpoint: authz.EndpointStream}
+	if event.Module != "" {
+		resource.Modules = []string{event.Module}
+	}
+	return f.policy.Authorize(resource, []string{event.Chain}) == nil
+}
+
+// streamSubscriber is one connected stream
+type streamSubscriber struct {
+	filter *streamFilter
+	events chan *streaming.Event
+}
+
+// eventHub fans events out to the connected streams
+type eventHub struct {
+	mu          sync.RWMutex
+	subscribers map[*streamSubscriber]struct{}
+	active      atomic.Bool
+}
+
+// newEventHub creates an event hub without an event source
+func newEventHub() *eventHub {
+	return &eventHub{subscribers: make(map[*streamSubscriber]struct{})}
+}
+
+// subscribe registers a stream
+func (h *eventHub) subscribe(filter *streamFilter) *streamSubscriber {
+	sub := &streamSubscriber{filter: filter, events: make(chan *streaming.Event, streamBufferSize)}
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// unsubscribe removes a stream
+func (h *eventHub) unsubscribe(sub *streamSubscriber) {
+	h.mu.Lock()
+	delete(h.subscribers, sub)
+	h.mu.Unlock()
+}
+
+// publish passes an event to every matching stream without blocking on slow
+// clients
+func (h *eventHub) publish(event *streaming.Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers {
+		if !sub.filter.matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			streamEventsDropped.Inc()
+		}
+	}
+}
+
+// StreamEvents serves the events read by a consumer on the stream endpoint
+// until ctx is done
+func (s *Server) StreamEvents(ctx context.Context, consumer *streaming.Consumer) {
+	s.events.active.Store(true)
+	defer s.events.active.Store(false)
+
+	consumer.Run(ctx, s.events.publish)
+}
+
+// streamFilterFromRequest parses the chain, type and address query parameters,
+// each a comma-separated list
+func (s *Server) streamFilterFromRequest(c *gin.Context) (*streamFilter, error) {
+	filter := &streamFilter{
+		chains:    make(map[string]bool),
+		types:     make(map[string]bool),
+		addresses: make(map[string]bool),
+		policy:    policyFromContext(c.Request.Context()),
+	}
+
+	for _, chain := range splitChains(c.Query("chain")) {
+		if err := s.validateChain(chain); err != nil {
+			return nil, err
+		}
+		filter.chains[chain] = true
+	}
+	for _, eventType := range splitChains(c.Query("type")) {
+		if !slices.Contains(streaming.EventTypes, eventType) {
+			return nil, fmt.Errorf("unknown event type %q; known types: %s", eventType, strings.Join(streaming.EventTypes, ", "))
+		}
+		filter.types[eventType] = true
+	}
+	for _, address := range splitChains(c.Query("address")) {
+		filter.addresses[address] = true
+	}
+
+	return filter, nil
+}
+
+// streamEvents handles GET /api/v1/stream, sending matching events as
+// server-sent events until the client disconnects
+func (s *Server) streamEvents(c *gin.Context) {
+	if !s.events.active.Load() {
+		s.abortWithError(c, http.StatusServiceUnavailable, CodeUnavailable, "event streaming is not enabled")
+		return
+	}
+
+	filter, err := s.streamFilterFromRequest(c)
+	if err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	sub := s.events.subscribe(filter)
+	defer s.events.unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		case event := <-sub.events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				s.logger.Error("Failed to encode streamed event", zap.Error(err))
+				return true
+			}
+			if event.ID != "" {
+				if _, err := fmt.Fprintf(w, "id: %s\n", event.ID); err != nil {
+					return false
+				}
+			}
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			return err == nil
+		}
+	})
+}
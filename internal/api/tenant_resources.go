@@ -0,0 +1,456 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/cosmos/state-mesh/internal/alerting"
+	"github.com/cosmos/state-mesh/internal/email"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// getWatchlists handles GET /api/v1/watchlists
+func (s *Server) getWatchlists(c *gin.Context) {
+	watchlists, err := s.storage.Tenants().GetWatchlists(c.Request.Context(), c.GetString(tenantIDKey))
+	if err != nil {
+		s.logger.Error("Failed to get watchlists", zap.String("tenant", c.GetString(tenantIDKey)), zap.Error(err))
+		s.storageError(c, err, "failed to get watchlists")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"watchlists": watchlists,
+	})
+}
+
+// getWatchlist handles GET /api/v1/watchlists/:id
+func (s *Server) getWatchlist(c *gin.Context) {
+	watchlist, err := s.storage.Tenants().GetWatchlist(c.Request.Context(), c.GetString(tenantIDKey), c.Param("id"))
+	if err != nil {
+		s.storageError(c, err, "failed to get watchlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// createWatchlist handles POST /api/v1/watchlists
+func (s *Server) createWatchlist(c *gin.Context) {
+	var req struct {
+		Name      string   `json:"name"`
+		Chain     string   `json:"chain"`
+		Addresses []string `json:"addresses"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.badRequest(c, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		s.badRequest(c, "name is required")
+		return
+	}
+	if err := s.validateChain(req.Chain); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+	for _, address := range req.Addresses {
+		if err := s.validateAddress(req.Chain, address, ""); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+	}
+
+	watchlist := &types.Watchlist{
+		TenantID:  c.GetString(tenantIDKey),
+		Name:      strings.TrimSpace(req.Name),
+		ChainName: req.Chain,
+		Addresses: req.Addresses,
+	}
+	if watchlist.Addresses == nil {
+		watchlist.Addresses = []string{}
+	}
+	if err := s.storage.Tenants().CreateWatchlist(c.Request.Context(), watchlist); err != nil {
+		s.logger.Error("Failed to create watchlist", zap.String("tenant", watchlist.TenantID), zap.Error(err))
+		s.storageError(c, err, "failed to create watchlist")
+		return
+	}
+
+	c.JSON(http.StatusCreated, watchlist)
+}
+
+// deleteWatchlist handles DELETE /api/v1/watchlists/:id
+func (s *Server) deleteWatchlist(c *gin.Context) {
+	if err := s.storage.Tenants().DeleteWatchlist(c.Request.Context(), c.GetString(tenantIDKey), c.Param("id")); err != nil {
+		s.storageError(c, err, "failed to delete watchlist")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getWebhooks handles GET /api/v1/webhooks
+func (s *Server) getWebhooks(c *gin.Context) {
+	webhooks, err := s.storage.Tenants().GetWebhooks(c.Request.Context(), c.GetString(tenantIDKey))
+	if err != nil {
+		s.logger.Error("Failed to get webhooks", zap.String("tenant", c.GetString(tenantIDKey)), zap.Error(err))
+		s.storageError(c, err, "failed to get webhooks")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": webhooks,
+	})
+}
+
+// createWebhook handles POST /api/v1/webhooks. A signing secret is generated
+// when none is given; it is only returned here.
+func (s *Server) createWebhook(c *gin.Context) {
+	var req struct {
+		URL    string   `json:"url"`
+		Secret string   `json:"secret"`
+		Events []string `json:"events"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.badRequest(c, "invalid request body")
+		return
+	}
+	endpoint, err := url.Parse(req.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		s.badRequest(c, "url must be an absolute http or https URL")
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			s.abortWithError(c, http.StatusInternalServerError, CodeInternal, "failed to generate webhook secret")
+			return
+		}
+		secret = hex.EncodeToString(b)
+	}
+
+	webhook := &types.Webhook{
+		TenantID: c.GetString(tenantIDKey),
+		URL:      req.URL,
+		Secret:   secret,
+		Events:   req.Events,
+		Enabled:  true,
+	}
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+	if err := s.storage.Tenants().CreateWebhook(c.Request.Context(), webhook); err != nil {
+		s.logger.Error("Failed to create webhook", zap.String("tenant", webhook.TenantID), zap.Error(err))
+		s.storageError(c, err, "failed to create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"webhook": webhook,
+		"secret":  secret,
+	})
+}
+
+// deleteWebhook handles DELETE /api/v1/webhooks/:id
+func (s *Server) deleteWebhook(c *gin.Context) {
+	if err := s.storage.Tenants().DeleteWebhook(c.Request.Context(), c.GetString(tenantIDKey), c.Param("id")); err != nil {
+		s.storageError(c, err, "failed to delete webhook")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getEmailSubscriptions handles GET /api/v1/email-subscriptions
+func (s *Server) getEmailSubscriptions(c *gin.Context) {
+	subscriptions, err := s.storage.Tenants().GetEmailSubscriptions(c.Request.Context(), c.GetString(tenantIDKey))
+	if err != nil {
+		s.logger.Error("Failed to get email subscriptions", zap.String("tenant", c.GetString(tenantIDKey)), zap.Error(err))
+		s.storageError(c, err, "failed to get email subscriptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"email_subscriptions": subscriptions,
+	})
+}
+
+// createEmailSubscription handles POST /api/v1/email-subscriptions. An
+// address is subscribed once per tenant.
+func (s *Server) createEmailSubscription(c *gin.Context) {
+	var req struct {
+		Email  string   `json:"email"`
+		Events []string `json:"events"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.badRequest(c, "invalid request body")
+		return
+	}
+	addr, err := mail.ParseAddress(req.Email)
+	if err != nil {
+		s.badRequest(c, "email must be a valid email address")
+		return
+	}
+	for _, event := range req.Events {
+		if !slices.Contains(email.Events, event) {
+			s.badRequest(c, fmt.Sprintf("events must be %s", strings.Join(email.Events, ", ")))
+			return
+		}
+	}
+
+	subscription := &types.EmailSubscription{
+		TenantID: c.GetString(tenantIDKey),
+		Email:    addr.Address,
+		Events:   req.Events,
+		Enabled:  true,
+	}
+	if subscription.Events == nil {
+		subscription.Events = []string{}
+	}
+	if err := s.storage.Tenants().CreateEmailSubscription(c.Request.Context(), subscription); err != nil {
+		s.logger.Error("Failed to create email subscription", zap.String("tenant", subscription.TenantID), zap.Error(err))
+		s.storageError(c, err, "failed to create email subscription")
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// deleteEmailSubscription handles DELETE /api/v1/email-subscriptions/:id
+func (s *Server) deleteEmailSubscription(c *gin.Context) {
+	if err := s.storage.Tenants().DeleteEmailSubscription(c.Request.Context(), c.GetString(tenantIDKey), c.Param("id")); err != nil {
+		s.storageError(c, err, "failed to delete email subscription")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// unsubscribePage is what the unsubscribe link of an email opens: a form
+// posting back to it, so that links followed by mail scanners unsubscribe
+// nobody
+const unsubscribePage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Unsubscribe</title></head>
+<body><form method="post"><p>Stop receiving these emails?</p><button type="submit">Unsubscribe</button></form></body></html>
+`
+
+// unsubscribedPage confirms that an email subscription was disabled
+const unsubscribedPage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Unsubscribed</title></head>
+<body><p>You will no longer receive these emails.</p></body></html>
+`
+
+// getUnsubscribe handles GET /api/v1/unsubscribe?token=, the unsubscribe
+// link of emails, which needs no API key
+func (s *Server) getUnsubscribe(c *gin.Context) {
+	if c.Query("token") == "" {
+		s.badRequest(c, "token is required")
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(unsubscribePage))
+}
+
+// unsubscribe handles POST /api/v1/unsubscribe?token=, from the unsubscribe
+// page or a mail client's one-click unsubscribe, disabling the email
+// subscription of the token
+func (s *Server) unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		s.badRequest(c, "token is required")
+		return
+	}
+	subscription, err := s.storage.Tenants().Unsubscribe(c.Request.Context(), token)
+	if err != nil {
+		s.storageError(c, err, "failed to unsubscribe")
+		return
+	}
+
+	s.logger.Info("Email subscription unsubscribed",
+		zap.String("tenant", subscription.TenantID),
+		zap.String("email_subscription", subscription.ID))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(unsubscribedPage))
+}
+
+// getAlertRules handles GET /api/v1/alert-rules
+func (s *Server) getAlertRules(c *gin.Context) {
+	rules, err := s.storage.Tenants().GetAlertRules(c.Request.Context(), c.GetString(tenantIDKey))
+	if err != nil {
+		s.logger.Error("Failed to get alert rules", zap.String("tenant", c.GetString(tenantIDKey)), zap.Error(err))
+		s.storageError(c, err, "failed to get alert rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alert_rules": rules,
+	})
+}
+
+// createAlertRule handles POST /api/v1/alert-rules. The watchlist and webhook
+// must belong to the calling tenant.
+func (s *Server) createAlertRule(c *gin.Context) {
+	var req struct {
+		Name        string `json:"name"`
+		Chain       string `json:"chain"`
+		WatchlistID string `json:"watchlist_id"`
+		WebhookID   string `json:"webhook_id"`
+		Condition   string `json:"condition"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.badRequest(c, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		s.badRequest(c, "name is required")
+		return
+	}
+	if strings.TrimSpace(req.Condition) == "" {
+		s.badRequest(c, "condition is required")
+		return
+	}
+	if _, err := alerting.Compile(req.Condition); err != nil {
+		s.badRequest(c, "invalid condition: "+err.Error())
+		return
+	}
+	if err := s.validateChain(req.Chain); err != nil {
+		s.badRequest(c, err.Error())
+		return
+	}
+
+	tenantID := c.GetString(tenantIDKey)
+	if req.WatchlistID != "" {
+		watchlist, err := s.storage.Tenants().GetWatchlist(c.Request.Context(), tenantID, req.WatchlistID)
+		if err != nil {
+			s.storageError(c, err, "failed to get watchlist")
+			return
+		}
+		if watchlist.ChainName != req.Chain {
+			s.badRequest(c, fmt.Sprintf("watchlist %s is for chain %s", req.WatchlistID, watchlist.ChainName))
+			return
+		}
+	}
+	if req.WebhookID != "" {
+		if _, err := s.storage.Tenants().GetWebhook(c.Request.Context(), tenantID, req.WebhookID); err != nil {
+			s.storageError(c, err, "failed to get webhook")
+			return
+		}
+	}
+
+	rule := &types.AlertRule{
+		TenantID:    tenantID,
+		Name:        strings.TrimSpace(req.Name),
+		ChainName:   req.Chain,
+		WatchlistID: req.WatchlistID,
+		WebhookID:   req.WebhookID,
+		Condition:   strings.TrimSpace(req.Condition),
+		Enabled:     true,
+	}
+	if err := s.storage.Tenants().CreateAlertRule(c.Request.Context(), rule); err != nil {
+		s.logger.Error("Failed to create alert rule", zap.String("tenant", tenantID), zap.Error(err))
+		s.storageError(c, err, "failed to create alert rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// deleteAlertRule handles DELETE /api/v1/alert-rules/:id
+func (s *Server) deleteAlertRule(c *gin.Context) {
+	if err := s.storage.Tenants().DeleteAlertRule(c.Request.Context(), c.GetString(tenantIDKey), c.Param("id")); err != nil {
+		s.storageError(c, err, "failed to delete alert rule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getDigests handles GET /api/v1/digests
+func (s *Server) getDigests(c *gin.Context) {
+	digests, err := s.storage.Tenants().GetDigests(c.Request.Context(), c.GetString(tenantIDKey))
+	if err != nil {
+		s.logger.Error("Failed to get digests", zap.String("tenant", c.GetString(tenantIDKey)), zap.Error(err))
+		s.storageError(c, err, "failed to get digests")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"digests": digests,
+	})
+}
+
+// createDigest handles POST /api/v1/digests. A digest is delivered to a
+// webhook of the calling tenant, an email address or both.
+func (s *Server) createDigest(c *gin.Context) {
+	var req struct {
+		Name      string `json:"name"`
+		Schedule  string `json:"schedule"`
+		WebhookID string `json:"webhook_id"`
+		Email     string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		s.badRequest(c, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		s.badRequest(c, "name is required")
+		return
+	}
+	if req.Schedule != types.DigestDaily && req.Schedule != types.DigestWeekly {
+		s.badRequest(c, "schedule must be daily or weekly")
+		return
+	}
+	if req.WebhookID == "" && req.Email == "" {
+		s.badRequest(c, "webhook_id or email is required")
+		return
+	}
+	if req.Email != "" {
+		addr, err := mail.ParseAddress(req.Email)
+		if err != nil {
+			s.badRequest(c, "email must be a valid email address")
+			return
+		}
+		req.Email = addr.Address
+	}
+
+	tenantID := c.GetString(tenantIDKey)
+	if req.WebhookID != "" {
+		if _, err := s.storage.Tenants().GetWebhook(c.Request.Context(), tenantID, req.WebhookID); err != nil {
+			s.storageError(c, err, "failed to get webhook")
+			return
+		}
+	}
+
+	digest := &types.Digest{
+		TenantID:  tenantID,
+		Name:      strings.TrimSpace(req.Name),
+		Schedule:  req.Schedule,
+		WebhookID: req.WebhookID,
+		Email:     req.Email,
+		Enabled:   true,
+	}
+	if err := s.storage.Tenants().CreateDigest(c.Request.Context(), digest); err != nil {
+		s.logger.Error("Failed to create digest", zap.String("tenant", tenantID), zap.Error(err))
+		s.storageError(c, err, "failed to create digest")
+		return
+	}
+
+	c.JSON(http.StatusCreated, digest)
+}
+
+// deleteDigest handles DELETE /api/v1/digests/:id
+func (s *Server) deleteDigest(c *gin.Context) {
+	if err := s.storage.Tenants().DeleteDigest(c.Request.Context(), c.GetString(tenantIDKey), c.Param("id")); err != nil {
+		s.storageError(c, err, "failed to delete digest")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,304 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cosmos/state-mesh/internal/authz"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// apiKeyHeader is the header carrying an API key when not sent as a bearer token
+const apiKeyHeader = "X-API-Key"
+
+// apiKeyPrefix starts every generated API key so that leaked keys are recognizable
+const apiKeyPrefix = "sm_"
+
+// tenantIDKey is the Gin context key holding the authenticated tenant ID
+const tenantIDKey = "tenant_id"
+
+// principalContextKey is the request context key holding the authenticated principal
+type principalContextKey struct{}
+
+// principal is the tenant, ID and access policy of an authenticated API key
+type principal struct {
+	tenantID string
+	keyID    string
+	policy   *authz.Policy
+}
+
+// API key errors reported as 401 Unauthorized
+var (
+	errMissingAPIKey = errors.New("missing API key")
+	errInvalidAPIKey = errors.New("invalid API key")
+)
+
+// unauthenticated reports whether an authentication failure is the caller's fault
+func unauthenticated(err error) bool {
+	return errors.Is(err, errMissingAPIKey) || errors.Is(err, errInvalidAPIKey)
+}
+
+// TenantFromContext returns the tenant ID of an authenticated request, or ""
+// when tenancy is disabled
+func TenantFromContext(ctx context.Context) string {
+	p, _ := ctx.Value(principalContextKey{}).(principal)
+	return p.tenantID
+}
+
+// policyFromContext returns the access policy of an authenticated request, or
+// nil when tenancy is disabled or the API key is unrestricted
+func policyFromContext(ctx context.Context) *authz.Policy {
+	p, _ := ctx.Value(principalContextKey{}).(principal)
+	return p.policy
+}
+
+// apiKeyFromRequest returns the API key sent as a bearer token or X-API-Key header
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return ""
+}
+
+// hashAPIKey returns the hash an API key is stored and looked up by
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// newAPIKey generates a random API key and the prefix identifying it
+func newAPIKey() (key, prefix string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key = apiKeyPrefix + hex.EncodeToString(b)
+	return key, key[:len(apiKeyPrefix)+8], nil
+}
+
+// authenticateTenant resolves the API key of a request to its tenant and
+// access policy
+func (s *Server) authenticateTenant(r *http.Request) (principal, error) {
+	key := apiKeyFromRequest(r)
+	if key == "" {
+		return principal{}, errMissingAPIKey
+	}
+
+	apiKey, err := s.storage.Tenants().GetAPIKeyByHash(r.Context(), hashAPIKey(key))
+	if errors.Is(err, storage.ErrNotFound) {
+		return principal{}, errInvalidAPIKey
+	}
+	if err != nil {
+		return principal{}, err
+	}
+	if apiKey.RevokedAt != nil {
+		return principal{}, errInvalidAPIKey
+	}
+
+	return principal{tenantID: apiKey.TenantID, keyID: apiKey.ID, policy: s.rolePolicy(apiKey.Role)}, nil
+}
+
+// requireTenant authenticates REST requests with a tenant API key
+func (s *Server) requireTenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := s.authenticateTenant(c.Request)
+		if err != nil {
+			if unauthenticated(err) {
+				s.abortWithError(c, http.StatusUnauthorized, CodeUnauthenticated, err.Error())
+				return
+			}
+			s.logger.Error("Failed to authenticate API key", zap.Error(err))
+			s.storageError(c, err, "failed to authenticate API key")
+			return
+		}
+
+		c.Set(tenantIDKey, p.tenantID)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), principalContextKey{}, p))
+		c.Next()
+	}
+}
+
+// tenantMiddleware authenticates GraphQL requests with a tenant API key
+func (s *Server) tenantMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := s.authenticateTenant(r)
+		if err != nil {
+			status := http.StatusUnauthorized
+			if !unauthenticated(err) {
+				s.logger.Error("Failed to authenticate API key", zap.Error(err))
+				status = http.StatusServiceUnavailable
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]any{
+				"errors": []map[string]string{{"message": err.Error()}},
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalContextKey{}, p)))
+	})
+}
+
+// requireAdmin authenticates admin API requests with the configured admin key
+func (s *Server) requireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := apiKeyFromRequest(c.Request)
+		if key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(s.cfg.Tenancy.AdminKey)) != 1 {
+			s.abortWithError(c, http.StatusUnauthorized, CodeUnauthenticated, "invalid admin key")
+			return
+		}
+		c.Next()
+	}
+}
+
+// createTenant handles POST /admin/v1/tenants
+func (s *Server) createTenant(c *gin.Context) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		s.badRequest(c, "name is required")
+		return
+	}
+
+	tenant := &types.Tenant{Name: strings.TrimSpace(req.Name)}
+	if err := s.storage.Tenants().CreateTenant(c.Request.Context(), tenant); err != nil {
+		s.logger.Error("Failed to create tenant", zap.String("name", tenant.Name), zap.Error(err))
+		s.storageError(c, err, "failed to create tenant")
+		return
+	}
+
+	s.logger.Info("Tenant created", zap.String("tenant", tenant.ID), zap.String("name", tenant.Name))
+	c.JSON(http.StatusCreated, tenant)
+}
+
+// getTenants handles GET /admin/v1/tenants
+func (s *Server) getTenants(c *gin.Context) {
+	tenants, err := s.storage.Tenants().GetTenants(c.Request.Context())
+	if err != nil {
+		s.logger.Error("Failed to get tenants", zap.Error(err))
+		s.storageError(c, err, "failed to get tenants")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tenants": tenants,
+	})
+}
+
+// getTenant handles GET /admin/v1/tenants/:tenant
+func (s *Server) getTenant(c *gin.Context) {
+	tenant, err := s.storage.Tenants().GetTenant(c.Request.Context(), c.Param("tenant"))
+	if err != nil {
+		s.storageError(c, err, "failed to get tenant")
+		return
+	}
+
+	c.JSON(http.StatusOK, tenant)
+}
+
+// deleteTenant handles DELETE /admin/v1/tenants/:tenant
+func (s *Server) deleteTenant(c *gin.Context) {
+	tenantID := c.Param("tenant")
+	if err := s.storage.Tenants().DeleteTenant(c.Request.Context(), tenantID); err != nil {
+		s.storageError(c, err, "failed to delete tenant")
+		return
+	}
+
+	s.logger.Info("Tenant deleted", zap.String("tenant", tenantID))
+	c.Status(http.StatusNoContent)
+}
+
+// createAPIKey handles POST /admin/v1/tenants/:tenant/api-keys. The key itself
+// is only returned here; afterwards only its prefix is known. Keys without a
+// role are unrestricted.
+func (s *Server) createAPIKey(c *gin.Context) {
+	var req struct {
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		s.badRequest(c, "name is required")
+		return
+	}
+	if _, ok := s.roles[req.Role]; req.Role != "" && !ok {
+		s.badRequest(c, fmt.Sprintf("unknown role %q", req.Role))
+		return
+	}
+
+	key, prefix, err := newAPIKey()
+	if err != nil {
+		s.abortWithError(c, http.StatusInternalServerError, CodeInternal, "failed to generate API key")
+		return
+	}
+
+	apiKey := &types.APIKey{
+		TenantID: c.Param("tenant"),
+		Name:     strings.TrimSpace(req.Name),
+		Prefix:   prefix,
+		Role:     req.Role,
+		KeyHash:  hashAPIKey(key),
+	}
+	if err := s.storage.Tenants().CreateAPIKey(c.Request.Context(), apiKey); err != nil {
+		s.logger.Error("Failed to create API key", zap.String("tenant", apiKey.TenantID), zap.Error(err))
+		s.storageError(c, err, "failed to create API key")
+		return
+	}
+
+	s.logger.Info("API key created",
+		zap.String("tenant", apiKey.TenantID),
+		zap.String("key", apiKey.ID),
+		zap.String("prefix", apiKey.Prefix),
+		zap.String("role", apiKey.Role))
+	c.JSON(http.StatusCreated, gin.H{
+		"api_key": apiKey,
+		"key":     key,
+	})
+}
+
+// getAPIKeys handles GET /admin/v1/tenants/:tenant/api-keys
+func (s *Server) getAPIKeys(c *gin.Context) {
+	tenantID := c.Param("tenant")
+	if _, err := s.storage.Tenants().GetTenant(c.Request.Context(), tenantID); err != nil {
+		s.storageError(c, err, "failed to get tenant")
+		return
+	}
+
+	keys, err := s.storage.Tenants().GetAPIKeys(c.Request.Context(), tenantID)
+	if err != nil {
+		s.logger.Error("Failed to get API keys", zap.String("tenant", tenantID), zap.Error(err))
+		s.storageError(c, err, "failed to get API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": keys,
+	})
+}
+
+// revokeAPIKey handles DELETE /admin/v1/tenants/:tenant/api-keys/:id
+func (s *Server) revokeAPIKey(c *gin.Context) {
+	tenantID, keyID := c.Param("tenant"), c.Param("id")
+	if err := s.storage.Tenants().RevokeAPIKey(c.Request.Context(), tenantID, keyID); err != nil {
+		s.storageError(c, err, "failed to revoke API key")
+		return
+	}
+
+	s.logger.Info("API key revoked", zap.String("tenant", tenantID), zap.String("key", keyID))
+	c.Status(http.StatusNoContent)
+}
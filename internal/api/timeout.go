@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamingRoutes are the REST routes, relative to their API version, that
+// stream for as long as the client stays connected, so they have no request
+// timeout
+var streamingRoutes = map[string]bool{
+	"/stream": true,
+	"/chains/:chain/accounts/:address/events": true,
+}
+
+// ginRequestTimeout puts the request timeout on the context of REST requests,
+// cancelling their storage queries when it runs out
+func (s *Server) ginRequestTimeout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.cfg.RequestTimeout <= 0 || streamingRoutes[versionedRoute(c)] {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), s.cfg.RequestTimeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// requestTimeout puts the request timeout on the context of GraphQL requests.
+// Websocket subscriptions are not bounded.
+func (s *Server) requestTimeout(next http.Handler) http.Handler {
+	if s.cfg.RequestTimeout <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), s.cfg.RequestTimeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,244 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	"github.com/cosmos/state-mesh/internal/authz"
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/gin-gonic/gin"
+)
+
+// chainConfig returns the configuration of a known chain
+func (s *Server) chainConfig(name string) (config.ChainConfig, bool) {
+	for _, chain := range s.chains {
+		if chain.Name == name {
+			return chain, true
+		}
+	}
+	return config.ChainConfig{}, false
+}
+
+// validateChain checks that a chain name is configured
+func (s *Server) validateChain(name string) error {
+	if _, ok := s.chainConfig(name); ok {
+		return nil
+	}
+
+	known := make([]string, 0, len(s.chains))
+	for _, chain := range s.chains {
+		known = append(known, chain.Name)
+	}
+	sort.Strings(known)
+
+	return fmt.Errorf("unknown chain %q; known chains: %s", name, strings.Join(known, ", "))
+}
+
+// validateAddress checks that an address is valid bech32 and, when the chain
+// configures a prefix, that it uses the chain's prefix (plus suffix)
+func (s *Server) validateAddress(chainName, address, suffix string) error {
+	hrp, _, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return fmt.Errorf("invalid bech32 address %q: %v", address, err)
+	}
+
+	chain, ok := s.chainConfig(chainName)
+	if !ok || chain.Bech32Prefix == "" {
+		return nil
+	}
+
+	expected := chain.Bech32Prefix + suffix
+	if hrp != expected {
+		return fmt.Errorf("address %q has prefix %q, expected %q for chain %s", address, hrp, expected, chainName)
+	}
+
+	return nil
+}
+
+// parseProposalStatus returns the gov proposal status a status parameter
+// names, case-insensitively and with or without its PROPOSAL_STATUS_ prefix
+func parseProposalStatus(param string) (string, error) {
+	status := strings.ToUpper(param)
+	if !strings.HasPrefix(status, "PROPOSAL_STATUS_") {
+		status = "PROPOSAL_STATUS_" + status
+	}
+	if _, ok := govtypes.ProposalStatus_value[status]; !ok || status == govtypes.StatusNil.String() {
+		return "", fmt.Errorf("unknown proposal status %q", param)
+	}
+	return status, nil
+}
+
+// requireKnownChain validates the :chain path parameter
+func (s *Server) requireKnownChain() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := s.validateChain(c.Param("chain")); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+		c.Next()
+	}
+}
+
+// requireKnownChainQuery validates the chain query parameter when present
+func (s *Server) requireKnownChainQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if chain := c.Query("chain"); chain != "" {
+			if err := s.validateChain(chain); err != nil {
+				s.badRequest(c, err.Error())
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// requireKnownChainsQuery validates every chain in an optional comma-separated
+// chain query parameter
+func (s *Server) requireKnownChainsQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, chain := range splitChains(c.Query("chain")) {
+			if err := s.validateChain(chain); err != nil {
+				s.badRequest(c, err.Error())
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// accountRefsKey is the context key holding the resolved account references
+const accountRefsKey = "accountRefs"
+
+// requireValidAccount resolves the :address path parameter against the chain
+// query parameter. The parameter may list several comma-separated chains or be
+// omitted to query every enabled chain the API key may access. A single
+// explicit chain requires the address to use that chain's prefix; otherwise the
+// address is re-encoded with each chain's prefix.
+func (s *Server) requireValidAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		refs, err := s.accountRefs(c.Param("address"), c.Query("chain"), policyFromContext(c.Request.Context()))
+		if err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+		c.Set(accountRefsKey, refs)
+		c.Next()
+	}
+}
+
+// accountRefs resolves an address and chain query value into per-chain accounts
+func (s *Server) accountRefs(address, chainParam string, policy *authz.Policy) ([]storage.AccountRef, error) {
+	chains := splitChains(chainParam)
+	if len(chains) == 1 {
+		if err := s.validateChain(chains[0]); err != nil {
+			return nil, err
+		}
+		if err := s.validateAddress(chains[0], address, ""); err != nil {
+			return nil, err
+		}
+		chain, _ := s.chainConfig(chains[0])
+		return []storage.AccountRef{{ChainName: chains[0], Address: address, Denom: chain.StakingDenom()}}, nil
+	}
+
+	_, bz, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bech32 address %q: %v", address, err)
+	}
+
+	if len(chains) == 0 {
+		for _, chain := range s.chains {
+			if chain.Enabled && policy.AllowChain(chain.Name) {
+				chains = append(chains, chain.Name)
+			}
+		}
+	}
+
+	refs := make([]storage.AccountRef, 0, len(chains))
+	for _, name := range chains {
+		if err := s.validateChain(name); err != nil {
+			return nil, err
+		}
+
+		chain, _ := s.chainConfig(name)
+		chainAddress := address
+		if chain.Bech32Prefix != "" {
+			chainAddress, err = bech32.ConvertAndEncode(chain.Bech32Prefix, bz)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode address for chain %s: %v", name, err)
+			}
+		}
+		refs = append(refs, storage.AccountRef{ChainName: name, Address: chainAddress, Denom: chain.StakingDenom()})
+	}
+
+	return refs, nil
+}
+
+// splitChains parses a comma-separated chain list, dropping blanks and duplicates
+func splitChains(param string) []string {
+	var chains []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(param, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		chains = append(chains, name)
+	}
+	return chains
+}
+
+// accountRefsFromContext returns the accounts resolved by requireValidAccount
+func accountRefsFromContext(c *gin.Context) []storage.AccountRef {
+	refs, _ := c.Get(accountRefsKey)
+	accounts, _ := refs.([]storage.AccountRef)
+	return accounts
+}
+
+// paramsModuleKey is the context key holding the module of the params routes
+const paramsModuleKey = "paramsModule"
+
+// requireParamsModule validates the :module path parameter as a module whose
+// params are ingested, and checks that the API key may access its data
+func (s *Server) requireParamsModule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		module := config.CanonicalModuleName(c.Param("module"))
+		if !slices.Contains(cosmos.ParamsModules, module) {
+			s.badRequest(c, fmt.Sprintf("unknown module %q; modules with params: %s",
+				c.Param("module"), strings.Join(cosmos.ParamsModules, ", ")))
+			return
+		}
+
+		resource := authz.Resource{Endpoint: authz.EndpointParams, Modules: []string{module}}
+		if err := policyFromContext(c.Request.Context()).Authorize(resource, nil); err != nil {
+			s.abortWithError(c, http.StatusForbidden, CodePermissionDenied, err.Error())
+			return
+		}
+		c.Set(paramsModuleKey, module)
+		c.Next()
+	}
+}
+
+// paramsModuleFromContext returns the module resolved by requireParamsModule
+func paramsModuleFromContext(c *gin.Context) string {
+	return c.GetString(paramsModuleKey)
+}
+
+// requireValidValidator validates the :address path parameter as a validator
+// operator address of the :chain path parameter
+func (s *Server) requireValidValidator() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := s.validateAddress(c.Param("chain"), c.Param("address"), cosmos.ValidatorOperatorSuffix); err != nil {
+			s.badRequest(c, err.Error())
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// REST API versions. Each is mounted under /api/<version>; a version changes
+// response shapes of its predecessor without breaking integrators pinned to
+// it.
+const (
+	APIVersion1 = "v1"
+	APIVersion2 = "v2" // cross-chain totals per chain and denom
+)
+
+// APIVersions lists the REST API versions, oldest first
+var APIVersions = []string{APIVersion1, APIVersion2}
+
+// deprecatedVersions maps deprecated API versions to the date they were
+// deprecated on, announced in the Deprecation header of their responses
+var deprecatedVersions = map[string]time.Time{
+	APIVersion1: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+}
+
+// apiVersion marks the responses of a version's routes with its API-Version
+// header and, for a deprecated version, the Deprecation header, the Sunset
+// header when its removal is scheduled, and a link to the same route in the
+// latest version
+func (s *Server) apiVersion(version string) gin.HandlerFunc {
+	deprecated, isDeprecated := deprecatedVersions[version]
+	var sunset time.Time
+	if version == APIVersion1 && s.cfg.REST.V1Sunset != "" {
+		sunset, _ = time.Parse(time.DateOnly, s.cfg.REST.V1Sunset)
+	}
+	latest := APIVersions[len(APIVersions)-1]
+
+	return func(c *gin.Context) {
+		c.Header("API-Version", version)
+		if isDeprecated {
+			c.Header("Deprecation", fmt.Sprintf("@%d", deprecated.Unix()))
+			if !sunset.IsZero() {
+				c.Header("Sunset", sunset.Format(http.TimeFormat))
+			}
+			successor := "/api/" + latest + strings.TrimPrefix(c.Request.URL.Path, "/api/"+version)
+			c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
+		c.Next()
+	}
+}
+
+// negotiateVersion serves requests to /api/ paths without a version with the
+// version their API-Version header names, or the default version. Requests
+// naming an unsupported version are passed on unchanged, for
+// unsupportedVersion to answer.
+func (s *Server) negotiateVersion(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := unversionedPath(r.URL.Path)
+		if version := s.requestedVersion(r); ok && slices.Contains(APIVersions, version) {
+			// As http.StripPrefix does, leave the caller's request untouched
+			r2 := new(http.Request)
+			*r2 = *r
+			r2.URL = new(url.URL)
+			*r2.URL = *r.URL
+			r2.URL.Path = "/api/" + version + "/" + rest
+			r2.URL.RawPath = ""
+			r = r2
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// unsupportedVersion answers a request to an /api/ path without a version
+// whose API-Version header names an unsupported version. It reports false for
+// other requests.
+func (s *Server) unsupportedVersion(c *gin.Context) bool {
+	if _, ok := unversionedPath(c.Request.URL.Path); !ok {
+		return false
+	}
+	s.abortWithError(c, http.StatusBadRequest, CodeInvalidArgument,
+		fmt.Sprintf("unsupported API version %q; supported versions: %s", s.requestedVersion(c.Request), strings.Join(APIVersions, ", ")))
+	return true
+}
+
+// requestedVersion returns the API version a request's API-Version header
+// names, or the default version
+func (s *Server) requestedVersion(r *http.Request) string {
+	if version := r.Header.Get("API-Version"); version != "" {
+		return version
+	}
+	return s.cfg.REST.DefaultVersion
+}
+
+// unversionedPath returns the path of an /api/ path without a version after
+// /api/
+func unversionedPath(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return "", false
+	}
+	if first, _, _ := strings.Cut(rest, "/"); slices.Contains(APIVersions, first) {
+		return "", false
+	}
+	return rest, true
+}
+
+// versionedRoute returns the route of a request relative to its version, e.g.
+// /accounts/:address/state for /api/v2/accounts/:address/state, so that
+// policies and route tables apply to every version
+func versionedRoute(c *gin.Context) string {
+	route := c.FullPath()
+	rest, ok := strings.CutPrefix(route, "/api/")
+	if !ok {
+		return route
+	}
+	if version, path, _ := strings.Cut(rest, "/"); slices.Contains(APIVersions, version) {
+		return "/" + path
+	}
+	return route
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/cosmos/state-mesh/internal/config"
+)
+
+func TestAPIVersions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &Server{cfg: config.APIConfig{REST: config.RESTConfig{DefaultVersion: APIVersion2, V1Sunset: "2027-06-30"}}}
+
+	router := gin.New()
+	for _, version := range APIVersions {
+		api := router.Group("/api/"+version, s.apiVersion(version))
+		api.GET("/chains/:chain", func(c *gin.Context) {
+			c.String(http.StatusOK, versionedRoute(c))
+		})
+	}
+	router.NoRoute(func(c *gin.Context) {
+		if s.unsupportedVersion(c) {
+			return
+		}
+		c.Status(http.StatusNotFound)
+	})
+	handler := s.negotiateVersion(router)
+
+	tests := []struct {
+		name          string
+		path          string
+		header        string
+		wantStatus    int
+		wantVersion   string
+		wantSunset    string
+		wantSuccessor string
+	}{
+		{
+			name:          "deprecated version",
+			path:          "/api/v1/chains/cosmoshub",
+			wantStatus:    http.StatusOK,
+			wantVersion:   APIVersion1,
+			wantSunset:    "Wed, 30 Jun 2027 00:00:00 GMT",
+			wantSuccessor: `</api/v2/chains/cosmoshub>; rel="successor-version"`,
+		},
+		{name: "latest version", path: "/api/v2/chains/cosmoshub", wantStatus: http.StatusOK, wantVersion: APIVersion2},
+		{name: "default version", path: "/api/chains/cosmoshub", wantStatus: http.StatusOK, wantVersion: APIVersion2},
+		{
+			name:          "negotiated version",
+			path:          "/api/chains/cosmoshub",
+			header:        APIVersion1,
+			wantStatus:    http.StatusOK,
+			wantVersion:   APIVersion1,
+			wantSunset:    "Wed, 30 Jun 2027 00:00:00 GMT",
+			wantSuccessor: `</api/v2/chains/cosmoshub>; rel="successor-version"`,
+		},
+		{name: "unsupported version", path: "/api/chains/cosmoshub", header: "v9", wantStatus: http.StatusBadRequest},
+		{name: "unknown route", path: "/api/v2/unknown", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("API-Version", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := rec.Body.String(); got != "/chains/:chain" {
+				t.Errorf("versioned route = %q, want /chains/:chain", got)
+			}
+			if got := rec.Header().Get("API-Version"); got != tt.wantVersion {
+				t.Errorf("API-Version = %q, want %q", got, tt.wantVersion)
+			}
+			if got := rec.Header().Get("Deprecation") != ""; got != (tt.wantSuccessor != "") {
+				t.Errorf("deprecated = %t, want %t", got, tt.wantSuccessor != "")
+			}
+			if got := rec.Header().Get("Sunset"); got != tt.wantSunset {
+				t.Errorf("Sunset = %q, want %q", got, tt.wantSunset)
+			}
+			if got := rec.Header().Get("Link"); got != tt.wantSuccessor {
+				t.Errorf("Link = %q, want %q", got, tt.wantSuccessor)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// getSQLViews handles GET /api/v1/views: the views of the api schema offered
+// to external GraphQL engines and BI tools, with their keys and columns
+func (s *Server) getSQLViews(c *gin.Context) {
+	views, err := s.storage.GetSQLViews(c.Request.Context())
+	if err != nil {
+		s.logger.Error("Failed to get SQL views", zap.Error(err))
+		s.storageError(c, err, "failed to get SQL views")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"views": views})
+}
@@ -0,0 +1,172 @@
+// Package authz decides which chains, modules and endpoints an API key may
+// access, shared by the REST and GraphQL servers
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cosmos/state-mesh/internal/config"
+)
+
+// API endpoints that roles grant access to. REST routes and GraphQL fields
+// returning the same data share an endpoint.
+const (
+	EndpointChains              = "chains"
+	EndpointSearch              = "search"
+	EndpointBalances            = "balances"
+	EndpointDelegations         = "delegations"
+	EndpointAccountState        = "account_state"
+	EndpointBlocks              = "blocks"
+	EndpointValidators          = "validators"
+	EndpointValidatorDelegators = "validator_delegators"
+	EndpointStats               = "stats"
+	EndpointCrossChain          = "cross_chain"
+	EndpointGovernance          = "governance"
+	EndpointParams              = "params"
+	EndpointAnalytics           = "analytics"
+	EndpointWatchlists          = "watchlists"
+	EndpointWebhooks            = "webhooks"
+	EndpointEmailSubscriptions  = "email_subscriptions"
+	EndpointAlertRules          = "alert_rules"
+	EndpointDigests             = "digests"
+	EndpointPortfolios          = "portfolios"
+	EndpointUsage               = "usage"
+	EndpointStream              = "stream"
+	EndpointEvents              = "events"
+)
+
+// Endpoints lists every endpoint a role may name
+var Endpoints = []string{
+	EndpointChains,
+	EndpointSearch,
+	EndpointBalances,
+	EndpointDelegations,
+	EndpointAccountState,
+	EndpointBlocks,
+	EndpointValidators,
+	EndpointValidatorDelegators,
+	EndpointStats,
+	EndpointCrossChain,
+	EndpointGovernance,
+	EndpointParams,
+	EndpointAnalytics,
+	EndpointWatchlists,
+	EndpointWebhooks,
+	EndpointEmailSubscriptions,
+	EndpointAlertRules,
+	EndpointDigests,
+	EndpointPortfolios,
+	EndpointUsage,
+	EndpointStream,
+	EndpointEvents,
+}
+
+// ErrDenied is returned when a policy does not allow a request
+var ErrDenied = errors.New("permission denied")
+
+// Resource is an endpoint together with the modules whose data it returns
+type Resource struct {
+	Endpoint string
+	Modules  []string
+}
+
+// Policy is what a role allows. A nil Policy allows everything.
+type Policy struct {
+	role      string
+	denyAll   bool
+	chains    map[string]bool
+	modules   map[string]bool
+	endpoints map[string]bool
+}
+
+// NewPolicy creates the policy of a configured role. Empty lists in the role
+// allow everything of their kind.
+func NewPolicy(role string, cfg config.RoleConfig) (*Policy, error) {
+	p := &Policy{role: role}
+
+	if len(cfg.Chains) > 0 {
+		p.chains = make(map[string]bool, len(cfg.Chains))
+		for _, chain := range cfg.Chains {
+			p.chains[chain] = true
+		}
+	}
+	if len(cfg.Modules) > 0 {
+		p.modules = make(map[string]bool, len(cfg.Modules))
+		for _, module := range cfg.Modules {
+			p.modules[config.CanonicalModuleName(module)] = true
+		}
+	}
+	if len(cfg.Endpoints) > 0 {
+		known := make(map[string]bool, len(Endpoints))
+		for _, endpoint := range Endpoints {
+			known[endpoint] = true
+		}
+
+		p.endpoints = make(map[string]bool, len(cfg.Endpoints))
+		for _, endpoint := range cfg.Endpoints {
+			if !known[endpoint] {
+				return nil, fmt.Errorf("role %s: unknown endpoint %q", role, endpoint)
+			}
+			p.endpoints[endpoint] = true
+		}
+	}
+
+	return p, nil
+}
+
+// DenyAll creates a policy allowing nothing, used for keys whose role is no
+// longer configured
+func DenyAll(role string) *Policy {
+	return &Policy{role: role, denyAll: true}
+}
+
+// Authorize checks that the policy allows a resource on the given chains
+func (p *Policy) Authorize(resource Resource, chains []string) error {
+	if p == nil {
+		return nil
+	}
+	if p.denyAll {
+		return fmt.Errorf("%w: role %s is not configured", ErrDenied, p.role)
+	}
+
+	if p.endpoints != nil && !p.endpoints[resource.Endpoint] {
+		return fmt.Errorf("%w: role %s may not access %s", ErrDenied, p.role, resource.Endpoint)
+	}
+	for _, module := range resource.Modules {
+		if p.modules != nil && !p.modules[config.CanonicalModuleName(module)] {
+			return fmt.Errorf("%w: role %s may not access %s data", ErrDenied, p.role, module)
+		}
+	}
+	for _, chain := range chains {
+		if !p.AllowChain(chain) {
+			return fmt.Errorf("%w: role %s may not access chain %s", ErrDenied, p.role, chain)
+		}
+	}
+
+	return nil
+}
+
+// AllowChain reports whether the policy allows a chain
+func (p *Policy) AllowChain(chain string) bool {
+	if p == nil {
+		return true
+	}
+	return !p.denyAll && (p.chains == nil || p.chains[chain])
+}
+
+// Chains returns the chains the policy is limited to, or nil when it allows
+// every chain
+func (p *Policy) Chains() []string {
+	if p == nil || p.chains == nil {
+		return nil
+	}
+
+	chains := make([]string, 0, len(p.chains))
+	for chain := range p.chains {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+	return chains
+}
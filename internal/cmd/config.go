@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the State Mesh configuration",
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the configuration and check connectivity",
+	Long: `Validate the configuration file and check that everything it points to
+is reachable, without starting ingestion.
+
+The readiness report covers:
+- The configuration file: unknown keys and invalid values
+- The gRPC and REST endpoints of every enabled chain
+- The state store (PostgreSQL or CockroachDB)
+- ClickHouse, when enabled
+- The Kafka brokers and topic, when streaming is enabled
+
+Every failed check comes with a hint on how to fix it. The command exits
+with an error when any check fails.`,
+	RunE:         runConfigValidate,
+	SilenceUsage: true,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+
+	configValidateCmd.Flags().Duration("timeout", 10*time.Second, "Timeout of each connectivity check")
+	configValidateCmd.Flags().Bool("skip-connectivity", false, "Only validate the configuration file")
+}
+
+// readinessCheck is the result of a check of the readiness report
+type readinessCheck struct {
+	name   string
+	detail string
+	err    error
+	hint   string
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	skipConnectivity, _ := cmd.Flags().GetBool("skip-connectivity")
+
+	checks := checkConfigFile()
+
+	cfg, err := config.Load()
+	if err != nil {
+		checks = append(checks, readinessCheck{
+			name: "config values",
+			err:  err,
+			hint: "check the secret references in the configuration and the secrets section",
+		})
+	} else {
+		check := readinessCheck{name: "config values", detail: fmt.Sprintf("%d chains", len(cfg.Chains))}
+		if err := cfg.Validate(); err != nil {
+			check.err = err
+			check.hint = "fix the value in the configuration file"
+		}
+		checks = append(checks, check)
+
+		if !skipConnectivity {
+			checks = append(checks, checkConnectivity(cmd.Context(), cfg, timeout)...)
+		}
+	}
+
+	failed := printReadiness(cmd.OutOrStdout(), checks)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// checkConfigFile reads the configuration file on its own and reports keys
+// that match no configuration field, which are otherwise silently ignored
+func checkConfigFile() []readinessCheck {
+	file := viper.ConfigFileUsed()
+	check := readinessCheck{name: "config file", detail: file}
+	if file == "" {
+		check.detail = "none found, using defaults and environment"
+		return []readinessCheck{check}
+	}
+
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		check.err = err
+		check.hint = "check that the file exists and is valid YAML"
+		return []readinessCheck{check}
+	}
+	if err := v.UnmarshalExact(&config.Config{}); err != nil {
+		check.err = err
+		check.hint = "remove or rename the keys listed; see the configuration in the README"
+	}
+	return []readinessCheck{check}
+}
+
+// checkConnectivity dials the chains and connects to the databases and
+// brokers of the configuration
+func checkConnectivity(ctx context.Context, cfg *config.Config, timeout time.Duration) []readinessCheck {
+	var checks []readinessCheck
+	for _, chain := range cfg.Chains {
+		if !chain.Enabled {
+			continue
+		}
+		checks = append(checks, checkChainGRPC(ctx, chain, timeout))
+		if chain.RESTEndpoint != "" {
+			checks = append(checks, checkChainREST(ctx, chain, timeout))
+		}
+	}
+
+	driver := cfg.Database.Driver
+	if driver == "" {
+		driver = config.DriverPostgres
+	}
+	if driver != config.DriverMemory {
+		pg := cfg.Database.Postgres
+		check := readinessCheck{
+			name:   driver,
+			detail: fmt.Sprintf("%s@%s:%d/%s", pg.User, pg.Host, pg.Port, pg.Database),
+			hint:   "check database.postgres host, port, user and password, and that the database exists",
+		}
+		check.err = withTimeout(ctx, timeout, func(ctx context.Context) error {
+			return storage.CheckStateStore(ctx, cfg.Database)
+		})
+		checks = append(checks, check)
+	}
+
+	if ch := cfg.Database.ClickHouse; ch.Enabled {
+		check := readinessCheck{
+			name:   "clickhouse",
+			detail: fmt.Sprintf("%s:%d/%s", ch.Host, ch.Port, ch.Database),
+			hint:   "check database.clickhouse host and native protocol port (9000 by default), or disable it",
+		}
+		check.err = withTimeout(ctx, timeout, func(ctx context.Context) error {
+			return storage.CheckClickHouse(ctx, ch)
+		})
+		checks = append(checks, check)
+	}
+
+	if kafka := cfg.Streaming.Kafka; cfg.Streaming.Enabled {
+		check := readinessCheck{
+			name:   "kafka",
+			detail: fmt.Sprintf("%s topic %s", strings.Join(kafka.Brokers, ","), kafka.Topic),
+			hint:   fmt.Sprintf("check streaming.kafka.brokers, and create topic %s or enable automatic topic creation", kafka.Topic),
+		}
+		if len(kafka.Brokers) == 0 {
+			check.err = fmt.Errorf("no brokers configured")
+		} else {
+			check.err = streaming.CheckKafka(kafka, timeout)
+		}
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// checkChainGRPC dials the gRPC endpoint of a chain and reads its node status
+func checkChainGRPC(ctx context.Context, chain config.ChainConfig, timeout time.Duration) readinessCheck {
+	check := readinessCheck{
+		name:   chain.Name + " grpc",
+		detail: chain.GRPCEndpoint,
+		hint:   fmt.Sprintf("check that grpc_endpoint of chain %s is reachable and serves gRPC (port 9090 by default)", chain.Name),
+	}
+
+	client, err := cosmos.NewClient(chain.Name, chain.GRPCEndpoint)
+	if err != nil {
+		check.err = err
+		return check
+	}
+	defer client.Close()
+
+	var status *cosmos.NodeStatus
+	check.err = withTimeout(ctx, timeout, func(ctx context.Context) (err error) {
+		status, err = client.GetNodeStatus(ctx)
+		return err
+	})
+	if check.err != nil {
+		return check
+	}
+
+	check.detail = fmt.Sprintf("%s height %d", chain.GRPCEndpoint, status.LatestHeight)
+	if status.CatchingUp {
+		check.detail += ", catching up"
+	}
+	return check
+}
+
+// checkChainREST requests the node info of a chain from its REST endpoint
+func checkChainREST(ctx context.Context, chain config.ChainConfig, timeout time.Duration) readinessCheck {
+	check := readinessCheck{
+		name:   chain.Name + " rest",
+		detail: chain.RESTEndpoint,
+		hint:   fmt.Sprintf("check that rest_endpoint of chain %s is reachable and has the API server enabled (port 1317 by default)", chain.Name),
+	}
+
+	url := strings.TrimRight(chain.RESTEndpoint, "/") + "/cosmos/base/tendermint/v1beta1/node_info"
+	check.err = withTimeout(ctx, timeout, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("GET %s: %s", url, resp.Status)
+		}
+		return nil
+	})
+	return check
+}
+
+// withTimeout runs a check with a deadline
+func withTimeout(ctx context.Context, timeout time.Duration, check func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return check(ctx)
+}
+
+// printReadiness prints the readiness report, followed by the error and hint
+// of each failed check, and returns how many checks failed
+func printReadiness(out io.Writer, checks []readinessCheck) int {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	var failed []readinessCheck
+	for _, check := range checks {
+		status := "ok"
+		if check.err != nil {
+			status = "FAIL"
+			failed = append(failed, check)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", check.name, status, check.detail)
+	}
+	w.Flush()
+
+	for _, check := range failed {
+		fmt.Fprintf(out, "\n%s: %v\n", check.name, check.err)
+		if check.hint != "" {
+			fmt.Fprintf(out, "  hint: %s\n", check.hint)
+		}
+	}
+	if len(failed) == 0 {
+		fmt.Fprintln(out, "Ready.")
+	}
+	return len(failed)
+}
@@ -3,14 +3,23 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"sync/atomic"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/diagnostics"
 	"github.com/cosmos/state-mesh/internal/ingester"
+	"github.com/cosmos/state-mesh/internal/modules"
+	"github.com/cosmos/state-mesh/internal/pruner"
 	"github.com/cosmos/state-mesh/internal/storage"
 	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -28,7 +37,12 @@ The ingester:
 - Subscribes to module state changes (bank, staking, distribution, etc.)
 - Normalizes and stores state data in PostgreSQL
 - Streams analytics data to ClickHouse
-- Publishes state changes to Kafka for real-time processing`,
+- Publishes state changes to Kafka for real-time processing
+
+With --dry-run the ingester runs one cycle of every chain against an
+in-memory store instead, prints what would have been written and exits,
+for validating chain configurations and modules before touching production
+databases. Nothing is written to PostgreSQL, ClickHouse or Kafka.`,
 	RunE: runIngest,
 }
 
@@ -42,6 +56,8 @@ func init() {
 	ingestCmd.Flags().Bool("enable-analytics", true, "Enable ClickHouse analytics storage")
 	ingestCmd.Flags().Int("batch-size", 1000, "Batch size for database operations")
 	ingestCmd.Flags().Duration("flush-interval", 0, "Flush interval for batched operations (0 = auto)")
+	ingestCmd.Flags().Int("metrics-port", 0, "Serve Prometheus metrics and diagnostics on this port (0 = disabled)")
+	ingestCmd.Flags().Bool("dry-run", false, "Run one cycle of every chain without writing anything and print what would have been written")
 
 	// Bind flags to viper
 	viper.BindPFlag("ingester.chains", ingestCmd.Flags().Lookup("chains"))
@@ -50,6 +66,8 @@ func init() {
 	viper.BindPFlag("ingester.analytics.enabled", ingestCmd.Flags().Lookup("enable-analytics"))
 	viper.BindPFlag("ingester.batch_size", ingestCmd.Flags().Lookup("batch-size"))
 	viper.BindPFlag("ingester.flush_interval", ingestCmd.Flags().Lookup("flush-interval"))
+	viper.BindPFlag("ingester.metrics_port", ingestCmd.Flags().Lookup("metrics-port"))
+	viper.BindPFlag("ingester.dry_run", ingestCmd.Flags().Lookup("dry-run"))
 }
 
 func runIngest(cmd *cobra.Command, args []string) error {
@@ -67,6 +85,10 @@ func runIngest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	if viper.GetBool("ingester.dry_run") {
+		return runDryRun(cmd, cfg)
+	}
+
 	// Initialize storage
 	storageManager, err := storage.NewManager(cfg.Database)
 	if err != nil {
@@ -88,11 +110,18 @@ func runIngest(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			logger.Warn("Failed to initialize streaming, continuing without it", zap.Error(err))
 		} else {
-			defer streamingManager.Close()
+			defer shutdownStreaming(cfg, streamingManager)
 			logger.Info("Streaming manager initialized")
 		}
 	}
 
+	// Start module plugins before the modules of each chain are resolved
+	stopPlugins, err := modules.LoadPlugins(context.Background(), cfg.Plugins, logger)
+	if err != nil {
+		return fmt.Errorf("failed to load plugins: %w", err)
+	}
+	defer stopPlugins()
+
 	// Filter chains and modules based on flags
 	chains := viper.GetStringSlice("ingester.chains")
 	modules := viper.GetStringSlice("ingester.modules")
@@ -103,6 +132,10 @@ func runIngest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize ingester: %w", err)
 	}
 
+	if streamingManager != nil {
+		ing.UseStreaming(streamingManager, cfg.Streaming.Outbox.Enabled)
+	}
+
 	// Apply filters
 	if len(chains) > 0 {
 		ing.FilterChains(chains)
@@ -115,14 +148,67 @@ func runIngest(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Start ingester
-	errChan := make(chan error, 1)
+	// Start background pruner (optional)
+	if cfg.Retention.Enabled {
+		p := pruner.New(cfg.Retention, storageManager)
+		p.Start(ctx)
+		defer p.Stop()
+	}
+
+	// Start the outbox relay publishing events written by state transactions
+	var relay *streaming.Relay
+	if cfg.Streaming.Outbox.Enabled && streamingManager != nil {
+		relay = streaming.NewRelay(cfg.Streaming.Outbox, streamingManager, storageManager.Outbox(), logger)
+		relay.Start(ctx)
+		defer stopRelay(cfg, relay)
+	}
+
+	// Start ingester once the database migrations are applied
+	errChan := make(chan error, 2)
+	var migrated, draining atomic.Bool
 	go func() {
+		if err := storageManager.WaitForMigrations(ctx, cfg.Lifecycle.MigrationsTimeout); err != nil {
+			errChan <- err
+			return
+		}
+		migrated.Store(true)
+		warnMissingIndexes(ctx, storageManager)
+
 		if err := ing.Start(ctx); err != nil {
 			errChan <- fmt.Errorf("ingester error: %w", err)
 		}
 	}()
 
+	// Serve metrics, the probes and, when enabled, diagnostics of the
+	// ingestion pipeline
+	if cfg.Ingester.MetricsPort > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		mux.HandleFunc("/healthz", probeHandler(func() bool { return true }))
+		mux.HandleFunc("/startupz", probeHandler(migrated.Load))
+		mux.HandleFunc("/readyz", probeHandler(func() bool { return migrated.Load() && !draining.Load() }))
+		if cfg.API.Metrics.Diagnostics {
+			diagnostics.Handle(mux, map[string]diagnostics.Source{
+				"ingester": ing.Diagnostics,
+			})
+		}
+
+		metricsServer := &http.Server{
+			Addr:    fmt.Sprintf(":%d", cfg.Ingester.MetricsPort),
+			Handler: mux,
+		}
+		defer metricsServer.Close()
+
+		go func() {
+			logger.Info("Starting ingester metrics server",
+				zap.Int("port", cfg.Ingester.MetricsPort),
+				zap.Bool("diagnostics", cfg.API.Metrics.Diagnostics))
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errChan <- fmt.Errorf("metrics server error: %w", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -134,7 +220,7 @@ func runIngest(cmd *cobra.Command, args []string) error {
 		logger.Info("Monitoring chain", 
 			zap.String("name", chain.Name),
 			zap.String("endpoint", chain.GRPCEndpoint),
-			zap.Strings("modules", chain.Modules))
+			zap.Strings("modules", chain.ModuleNames()))
 	}
 
 	select {
@@ -145,16 +231,105 @@ func runIngest(cmd *cobra.Command, args []string) error {
 		logger.Info("Received shutdown signal", zap.String("signal", sig.String()))
 	}
 
-	// Graceful shutdown
-	logger.Info("Shutting down ingester...")
-	cancel()
+	// Drain: stop scheduling ingest cycles and let those in flight commit,
+	// then publish what the outbox holds and deliver the buffered Kafka
+	// messages, all within the drain timeout
+	logger.Info("Draining ingester", zap.Duration("timeout", cfg.Lifecycle.DrainTimeout))
+	draining.Store(true)
+	drainCtx, cancelDrain := drainContext(cfg)
+	defer cancelDrain()
 
-	// Wait for ingester to stop
-	if err := ing.Stop(context.Background()); err != nil {
+	if err := ing.Stop(drainCtx); err != nil {
 		logger.Error("Error during ingester shutdown", zap.Error(err))
 		return err
 	}
+	if relay != nil {
+		relay.Stop(drainCtx)
+	}
+	if streamingManager != nil {
+		if err := streamingManager.Shutdown(drainCtx); err != nil {
+			logger.Warn("Failed to flush Kafka messages", zap.Error(err))
+		}
+	}
 
 	logger.Info("State Mesh ingester stopped")
 	return nil
 }
+
+// probeHandler answers a probe with 200 while ok reports true and 503 otherwise
+func probeHandler(ok func() bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status, body := http.StatusOK, `{"status": "healthy"}`
+		if !ok() {
+			status, body = http.StatusServiceUnavailable, `{"status": "unhealthy"}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+// runDryRun runs one ingest cycle of every selected chain against a dry-run
+// store and prints the rows each module would have written
+func runDryRun(cmd *cobra.Command, cfg *config.Config) error {
+	stopPlugins, err := modules.LoadPlugins(context.Background(), cfg.Plugins, GetLogger())
+	if err != nil {
+		return fmt.Errorf("failed to load plugins: %w", err)
+	}
+	defer stopPlugins()
+
+	storageManager := storage.NewDryRunManager()
+	defer storageManager.Close()
+
+	ing, err := ingester.New(cfg.Ingester, cfg.Chains, storageManager)
+	if err != nil {
+		return fmt.Errorf("failed to initialize ingester: %w", err)
+	}
+	ing.FilterChains(viper.GetStringSlice("ingester.chains"))
+	ing.FilterModules(viper.GetStringSlice("ingester.modules"))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	runs, err := ing.DryRun(ctx)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	var failures []string
+	for _, run := range runs {
+		fmt.Fprintf(w, "%s\theight %d\t%d rows\t%d gRPC calls\t%s\n",
+			run.ChainName, run.Height, run.RowsWritten, run.GRPCCalls, time.Duration(run.DurationMs)*time.Millisecond)
+		for _, module := range run.Modules {
+			fmt.Fprintf(w, "  %s\t\t%d rows\t%d gRPC calls\t%s\n",
+				module.Module, module.RowsWritten, module.GRPCCalls, time.Duration(module.DurationMs)*time.Millisecond)
+
+			tables := make([]string, 0, len(module.Writes))
+			for table := range module.Writes {
+				tables = append(tables, table)
+			}
+			sort.Strings(tables)
+			for _, table := range tables {
+				fmt.Fprintf(w, "    %s\t\t%d\t\t\n", table, module.Writes[table])
+			}
+			if module.Error != "" {
+				failures = append(failures, fmt.Sprintf("%s %s: %s", run.ChainName, module.Module, module.Error))
+			}
+		}
+		if len(run.Modules) == 0 && run.Error != "" {
+			failures = append(failures, fmt.Sprintf("%s: %s", run.ChainName, run.Error))
+		}
+	}
+	w.Flush()
+
+	for _, failure := range failures {
+		fmt.Fprintf(out, "error: %s\n", failure)
+	}
+	fmt.Fprintln(out, "Dry run: nothing was written.")
+	if len(failures) > 0 {
+		return fmt.Errorf("dry run found %d failures", len(failures))
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/ingester"
+	"github.com/cosmos/state-mesh/internal/listener"
+	"github.com/cosmos/state-mesh/internal/modules"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/cosmos/mock"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// loadgenCmd represents the loadgen command
+var loadgenCmd = &cobra.Command{
+	Use:   "loadgen",
+	Short: "Benchmark ingestion against simulated chains",
+	Long: `Loadgen replaces every enabled chain (or a single "loadgen" chain when none
+are enabled) with a deterministic simulated chain and runs the full ingestion
+pipeline against it: the ingester polls the simulated chain, and synthetic
+balance and delegation changes are pushed through the state listener into
+storage and streaming as configured.
+
+When the run ends, loadgen reports the ingested heights and how many state
+changes were processed per second.`,
+	RunE: runLoadgen,
+}
+
+func init() {
+	rootCmd.AddCommand(loadgenCmd)
+
+	// Loadgen-specific flags
+	loadgenCmd.Flags().Duration("duration", time.Minute, "How long to generate load")
+	loadgenCmd.Flags().Int64("seed", 1, "Seed for the simulated chains")
+	loadgenCmd.Flags().Int("validators", 100, "Validators per simulated chain")
+	loadgenCmd.Flags().Int("accounts", 10000, "Accounts per simulated chain")
+	loadgenCmd.Flags().Duration("block-time", time.Second, "Simulated block time")
+	loadgenCmd.Flags().Int("balance-changes", 500, "Balance changes per block")
+	loadgenCmd.Flags().Int("delegation-changes", 100, "Delegation changes per block")
+	loadgenCmd.Flags().Int("redelegation-changes", 10, "Redelegations per block")
+	loadgenCmd.Flags().Int("ibc-transfers", 5, "IBC packets sent and received per block")
+	loadgenCmd.Flags().Int("epoch-blocks", 600, "Blocks per simulated epoch, 0 for none")
+	loadgenCmd.Flags().Float64("miss-rate", 0.01, "Probability that a validator misses a block signature")
+	loadgenCmd.Flags().Bool("in-memory", false, "Write to an in-process store instead of the configured databases")
+
+	// Bind flags to viper
+	viper.BindPFlag("loadgen.duration", loadgenCmd.Flags().Lookup("duration"))
+	viper.BindPFlag("loadgen.seed", loadgenCmd.Flags().Lookup("seed"))
+	viper.BindPFlag("loadgen.validators", loadgenCmd.Flags().Lookup("validators"))
+	viper.BindPFlag("loadgen.accounts", loadgenCmd.Flags().Lookup("accounts"))
+	viper.BindPFlag("loadgen.block_time", loadgenCmd.Flags().Lookup("block-time"))
+	viper.BindPFlag("loadgen.balance_changes", loadgenCmd.Flags().Lookup("balance-changes"))
+	viper.BindPFlag("loadgen.delegation_changes", loadgenCmd.Flags().Lookup("delegation-changes"))
+	viper.BindPFlag("loadgen.redelegation_changes", loadgenCmd.Flags().Lookup("redelegation-changes"))
+	viper.BindPFlag("loadgen.ibc_transfers", loadgenCmd.Flags().Lookup("ibc-transfers"))
+	viper.BindPFlag("loadgen.epoch_blocks", loadgenCmd.Flags().Lookup("epoch-blocks"))
+	viper.BindPFlag("loadgen.miss_rate", loadgenCmd.Flags().Lookup("miss-rate"))
+	viper.BindPFlag("loadgen.in_memory", loadgenCmd.Flags().Lookup("in-memory"))
+}
+
+func runLoadgen(cmd *cobra.Command, args []string) error {
+	logger := GetLogger()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if viper.GetBool("loadgen.in_memory") {
+		cfg.Database.Driver = config.DriverMemory
+		cfg.Database.ClickHouse.Enabled = false
+		cfg.Streaming.Enabled = false
+		cfg.Streaming.Outbox.Enabled = false
+	}
+
+	blockTime := viper.GetDuration("loadgen.block_time")
+	if blockTime <= 0 {
+		return fmt.Errorf("block time must be positive")
+	}
+
+	// Simulate every enabled chain
+	var chains []config.ChainConfig
+	for _, chainCfg := range cfg.Chains {
+		if chainCfg.Enabled {
+			chains = append(chains, chainCfg)
+		}
+	}
+	if len(chains) == 0 {
+		chains = append(chains, config.ChainConfig{
+			Name:         "loadgen",
+			ChainID:      "loadgen-1",
+			Bech32Prefix: "cosmos",
+			Enabled:      true,
+			Epoch:        "day",
+			Modules: []config.ModuleConfig{
+				{Name: "bank", Enabled: true},
+				{Name: "staking", Enabled: true},
+				{Name: "mint", Enabled: true, Schedule: config.ScheduleEpoch},
+				{Name: "blocks", Enabled: true},
+			},
+		})
+	}
+	cfg.Chains = chains
+	cfg.Ingester.PollInterval = blockTime
+
+	clients := make(map[string]*mock.Client)
+	for i, chainCfg := range chains {
+		client, err := mock.NewClient(mock.Config{
+			ChainName:           chainCfg.Name,
+			Bech32Prefix:        chainCfg.Bech32Prefix,
+			Denom:               chainCfg.StakingDenom(),
+			Seed:                viper.GetInt64("loadgen.seed") + int64(i),
+			Validators:          viper.GetInt("loadgen.validators"),
+			Accounts:            viper.GetInt("loadgen.accounts"),
+			BlockTime:           blockTime,
+			BalanceChanges:      viper.GetInt("loadgen.balance_changes"),
+			DelegationChanges:   viper.GetInt("loadgen.delegation_changes"),
+			RedelegationChanges: viper.GetInt("loadgen.redelegation_changes"),
+			IBCTransfers:        viper.GetInt("loadgen.ibc_transfers"),
+			EpochBlocks:         viper.GetInt("loadgen.epoch_blocks"),
+			MissRate:            viper.GetFloat64("loadgen.miss_rate"),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create simulated chain %s: %w", chainCfg.Name, err)
+		}
+		clients[chainCfg.Name] = client
+	}
+
+	// Initialize storage
+	storageManager, err := storage.NewManager(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	defer storageManager.Close()
+
+	if err := storageManager.Ping(context.Background()); err != nil {
+		return fmt.Errorf("failed to connect to databases: %w", err)
+	}
+
+	// Initialize streaming (optional)
+	var streamingManager *streaming.Manager
+	if cfg.Streaming.Enabled {
+		streamingManager, err = streaming.NewManager(cfg.Streaming, logger)
+		if err != nil {
+			logger.Warn("Failed to initialize streaming, continuing without it", zap.Error(err))
+		} else {
+			defer shutdownStreaming(cfg, streamingManager)
+		}
+	}
+
+	// Start module plugins before the modules of each chain are resolved
+	stopPlugins, err := modules.LoadPlugins(context.Background(), cfg.Plugins, logger)
+	if err != nil {
+		return fmt.Errorf("failed to load plugins: %w", err)
+	}
+	defer stopPlugins()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if cfg.Streaming.Outbox.Enabled && streamingManager != nil {
+		relay := streaming.NewRelay(cfg.Streaming.Outbox, streamingManager, storageManager.Outbox(), logger)
+		relay.Start(ctx)
+		defer stopRelay(cfg, relay)
+	}
+
+	// Start the pipeline against the simulated chains
+	ing, err := ingester.New(cfg.Ingester, chains, storageManager)
+	if err != nil {
+		return fmt.Errorf("failed to initialize ingester: %w", err)
+	}
+	ing.UseClientFactory(func(chainCfg config.ChainConfig) (cosmos.ChainClient, error) {
+		return clients[chainCfg.Name], nil
+	})
+	if streamingManager != nil {
+		ing.UseStreaming(streamingManager, cfg.Streaming.Outbox.Enabled)
+	}
+
+	stateListener, err := listener.NewStateListener(*cfg, storageManager, streamingManager, logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize state listener: %w", err)
+	}
+	if err := stateListener.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start state listener: %w", err)
+	}
+	if err := ing.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start ingester: %w", err)
+	}
+
+	logger.Info("Generating load",
+		zap.Int("chains", len(chains)),
+		zap.Duration("duration", viper.GetDuration("loadgen.duration")),
+		zap.Duration("block_time", blockTime))
+
+	// Push each new height's state changes into the listener
+	start := time.Now()
+	feedCtx, stopFeeding := context.WithCancel(ctx)
+	emitted := make(map[string]*atomic.Int64)
+	var wg sync.WaitGroup
+	for name, client := range clients {
+		emitted[name] = new(atomic.Int64)
+
+		wg.Add(1)
+		go func(name string, client *mock.Client, count *atomic.Int64) {
+			defer wg.Done()
+
+			ticker := time.NewTicker(blockTime)
+			defer ticker.Stop()
+
+			var last int64
+			for {
+				select {
+				case <-feedCtx.Done():
+					return
+				case <-ticker.C:
+					latest := client.LatestHeight()
+					for h := last + 1; h <= latest; h++ {
+						for _, change := range client.Changes(h) {
+							stateListener.OnStateChange(name, change.StoreKey, change.Key, change.Value, change.Delete, change.Height)
+							count.Add(1)
+						}
+					}
+					last = latest
+				}
+			}
+		}(name, client, emitted[name])
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case <-time.After(viper.GetDuration("loadgen.duration")):
+	case sig := <-sigChan:
+		logger.Info("Received shutdown signal", zap.String("signal", sig.String()))
+	}
+
+	stopFeeding()
+	wg.Wait()
+
+	// Let the listener drain its queues before measuring
+	drainDeadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(drainDeadline) {
+		stats := stateListener.Stats()
+		if stats.Processed+stats.Failed+stats.Dropped >= stats.Received {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	elapsed := time.Since(start)
+
+	cancel()
+	if err := ing.Stop(context.Background()); err != nil {
+		logger.Error("Error during ingester shutdown", zap.Error(err))
+	}
+	stats := stateListener.Stats()
+	stateListener.Stop()
+
+	// Report
+	out := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(out, "CHAIN\tHEIGHT\tINGESTED\tBLOCKS\tCHANGES")
+	for _, chainCfg := range chains {
+		var ingested, blocks int64
+		if chain, err := storageManager.GetChain(context.Background(), chainCfg.Name); err == nil {
+			ingested = chain.LastIngestedHeight
+		}
+		if height, err := storageManager.State().GetLatestBlockHeight(context.Background(), chainCfg.Name); err == nil {
+			blocks = height
+		}
+		fmt.Fprintf(out, "%s\t%d\t%d\t%d\t%d\n",
+			chainCfg.Name,
+			clients[chainCfg.Name].LatestHeight(),
+			ingested,
+			blocks,
+			emitted[chainCfg.Name].Load())
+	}
+	out.Flush()
+
+	fmt.Fprintf(cmd.OutOrStdout(), "state changes: %d received, %d processed, %d failed, %d dropped, %d spilled in %s (%.0f/s)\n",
+		stats.Received,
+		stats.Processed,
+		stats.Failed,
+		stats.Dropped,
+		stats.Spilled,
+		elapsed.Round(time.Millisecond),
+		float64(stats.Processed)/elapsed.Seconds())
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/pruner"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Apply retention policies to stored state",
+	Long: `Prune runs the configured retention policies once and reports how many
+rows were reclaimed:
+
+- Balance and delegation history older than the history retention window
+- All history for dust accounts (every balance below the dust threshold)
+- Stale accounts with no balance or delegations that were not updated
+  within the stale account retention window
+- Ingest run reports older than the ingest run retention window`,
+	RunE: runPrune,
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+
+	// Prune-specific flags
+	pruneCmd.Flags().Duration("history-retention", 0, "Keep balance/delegation history for this long (e.g. 2160h)")
+	pruneCmd.Flags().String("dust-threshold", "", "Keep only latest state for accounts whose balances are all below this amount")
+	pruneCmd.Flags().Duration("stale-account-retention", 0, "Remove empty accounts not updated for this long")
+	pruneCmd.Flags().Duration("ingest-run-retention", 0, "Keep ingest run reports for this long (e.g. 720h)")
+	pruneCmd.Flags().Int("batch-size", 10000, "Rows deleted per statement")
+
+	// Bind flags to viper
+	viper.BindPFlag("retention.history_retention", pruneCmd.Flags().Lookup("history-retention"))
+	viper.BindPFlag("retention.dust_threshold", pruneCmd.Flags().Lookup("dust-threshold"))
+	viper.BindPFlag("retention.stale_account_retention", pruneCmd.Flags().Lookup("stale-account-retention"))
+	viper.BindPFlag("retention.ingest_run_retention", pruneCmd.Flags().Lookup("ingest-run-retention"))
+	viper.BindPFlag("retention.batch_size", pruneCmd.Flags().Lookup("batch-size"))
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	logger := GetLogger()
+	logger.Info("Running retention policies")
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Initialize storage
+	storageManager, err := storage.NewManager(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	defer storageManager.Close()
+
+	ctx := context.Background()
+	if err := storageManager.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to connect to databases: %w", err)
+	}
+
+	result, err := pruner.New(cfg.Retention, storageManager).RunOnce(ctx)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "balance history rows:    %d\n", result.BalanceHistory)
+	fmt.Fprintf(out, "delegation history rows: %d\n", result.DelegationHistory)
+	fmt.Fprintf(out, "dust account history:    %d\n", result.DustHistory)
+	fmt.Fprintf(out, "stale account rows:      %d\n", result.StaleAccounts)
+	fmt.Fprintf(out, "ingest runs:             %d\n", result.IngestRuns)
+	fmt.Fprintf(out, "total reclaimed rows:    %d (%s)\n", result.Total(), result.Duration.Round(1e6))
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/listener"
+	"github.com/cosmos/state-mesh/internal/modules"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// redecodeCmd represents the redecode command
+var redecodeCmd = &cobra.Command{
+	Use:   "redecode",
+	Short: "Reprocess archived state changes through the current decoders",
+	Long: `Redecode reads the raw state changes kept by the state listener archive
+(state_listener.archive) from ClickHouse and processes them again, in the
+order they were received, through the modules, plugins and decoders of the
+current configuration. Run it after adding support for a module to fill in
+the state of its store from before the support existed. Latest state already
+stored from a later height is kept.
+
+Events of reprocessed changes are only published again with --publish; they
+carry the same event IDs as the first time, so consumers drop duplicates.`,
+	RunE: runRedecode,
+}
+
+func init() {
+	rootCmd.AddCommand(redecodeCmd)
+
+	// Redecode-specific flags
+	redecodeCmd.Flags().StringSlice("chains", []string{}, "Specific chains to reprocess (default: all configured chains)")
+	redecodeCmd.Flags().StringSlice("stores", []string{}, "Specific stores to reprocess (default: all archived stores)")
+	redecodeCmd.Flags().Int64("from-height", 0, "First height to reprocess")
+	redecodeCmd.Flags().Int64("to-height", 0, "Last height to reprocess (default: latest archived)")
+	redecodeCmd.Flags().Bool("publish", false, "Publish the events of reprocessed changes again")
+
+	// Bind flags to viper
+	viper.BindPFlag("redecode.chains", redecodeCmd.Flags().Lookup("chains"))
+	viper.BindPFlag("redecode.stores", redecodeCmd.Flags().Lookup("stores"))
+	viper.BindPFlag("redecode.from_height", redecodeCmd.Flags().Lookup("from-height"))
+	viper.BindPFlag("redecode.to_height", redecodeCmd.Flags().Lookup("to-height"))
+	viper.BindPFlag("redecode.publish", redecodeCmd.Flags().Lookup("publish"))
+}
+
+func runRedecode(cmd *cobra.Command, args []string) error {
+	logger := GetLogger()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Reprocessed changes are not archived again
+	cfg.StateListener.Archive.Enabled = false
+	if !viper.GetBool("redecode.publish") {
+		cfg.Streaming.Enabled = false
+		cfg.Streaming.Outbox.Enabled = false
+	}
+
+	// Initialize storage
+	storageManager, err := storage.NewManager(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	defer storageManager.Close()
+
+	ctx := context.Background()
+	if err := storageManager.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to connect to databases: %w", err)
+	}
+	if storageManager.ClickHouse() == nil {
+		return fmt.Errorf("ClickHouse must be enabled to read the state change archive")
+	}
+
+	// Initialize streaming (optional)
+	var streamingManager *streaming.Manager
+	if cfg.Streaming.Enabled {
+		streamingManager, err = streaming.NewManager(cfg.Streaming, logger)
+		if err != nil {
+			logger.Warn("Failed to initialize streaming, continuing without it", zap.Error(err))
+		} else {
+			defer shutdownStreaming(cfg, streamingManager)
+		}
+	}
+
+	stopPlugins, err := modules.LoadPlugins(ctx, cfg.Plugins, logger)
+	if err != nil {
+		return fmt.Errorf("failed to load plugins: %w", err)
+	}
+	defer stopPlugins()
+
+	if err := modules.ApplySchemas(ctx, storageManager, cfg.Chains); err != nil {
+		return err
+	}
+
+	if cfg.Streaming.Outbox.Enabled && streamingManager != nil {
+		relay := streaming.NewRelay(cfg.Streaming.Outbox, streamingManager, storageManager.Outbox(), logger)
+		relay.Start(ctx)
+		defer stopRelay(cfg, relay)
+	}
+
+	sl, err := listener.NewStateListener(*cfg, storageManager, streamingManager, logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize state listener: %w", err)
+	}
+
+	chains := viper.GetStringSlice("redecode.chains")
+	if len(chains) == 0 {
+		for _, chain := range cfg.Chains {
+			if chain.Enabled {
+				chains = append(chains, chain.Name)
+			}
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	for _, chain := range chains {
+		start := time.Now()
+		var failed int64
+		processed, err := storageManager.ClickHouse().ScanArchivedStateChanges(ctx, types.StateArchiveFilter{
+			ChainName:  chain,
+			Stores:     viper.GetStringSlice("redecode.stores"),
+			FromHeight: viper.GetInt64("redecode.from_height"),
+			ToHeight:   viper.GetInt64("redecode.to_height"),
+		}, func(change *types.StateChange) error {
+			if err := sl.Reprocess(ctx, change); err != nil {
+				failed++
+				logger.Warn("Failed to reprocess state change",
+					zap.String("chain", chain),
+					zap.String("store", change.StoreKey),
+					zap.Int64("height", change.Height),
+					zap.Error(err))
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reprocess %s: %w", chain, err)
+		}
+
+		fmt.Fprintf(out, "%s: %d changes reprocessed, %d failed in %s\n",
+			chain, processed, failed, time.Since(start).Round(time.Millisecond))
+	}
+
+	return nil
+}
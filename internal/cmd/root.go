@@ -1,17 +1,25 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/logging"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/internal/telemetry"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
 var (
-	cfgFile string
-	logger  *zap.Logger
+	cfgFile    string
+	logger     *zap.Logger
+	logControl *logging.Control
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -44,10 +52,12 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.state-mesh.yaml)")
 	rootCmd.PersistentFlags().String("log-level", "info", "log level (debug, info, warn, error)")
 	rootCmd.PersistentFlags().String("log-format", "console", "log format (console, json)")
+	rootCmd.PersistentFlags().Bool("enable-diagnostics", false, "Serve pprof and runtime statistics on the metrics port")
 
 	// Bind flags to viper
 	viper.BindPFlag("log.level", rootCmd.PersistentFlags().Lookup("log-level"))
 	viper.BindPFlag("log.format", rootCmd.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("api.metrics.diagnostics", rootCmd.PersistentFlags().Lookup("enable-diagnostics"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -77,33 +87,13 @@ func initConfig() {
 
 // initializeConfig initializes the logger and validates configuration
 func initializeConfig() error {
-	// Initialize logger
-	var err error
-	logLevel := viper.GetString("log.level")
-	logFormat := viper.GetString("log.format")
-
-	var config zap.Config
-	if logFormat == "json" {
-		config = zap.NewProductionConfig()
-	} else {
-		config = zap.NewDevelopmentConfig()
-	}
-
-	// Set log level
-	switch logLevel {
-	case "debug":
-		config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
-	case "info":
-		config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-	case "warn":
-		config.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
-	case "error":
-		config.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
-	default:
-		config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	logger, err = config.Build()
+	// Initialize logger
+	logger, logControl, err = logging.New(cfg.Log)
 	if err != nil {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
@@ -111,6 +101,11 @@ func initializeConfig() error {
 	// Set global logger
 	zap.ReplaceGlobals(logger)
 
+	// Report panics and repeated ingestion failures
+	if err := telemetry.Init(cfg.Telemetry.Sentry); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -118,3 +113,53 @@ func initializeConfig() error {
 func GetLogger() *zap.Logger {
 	return logger
 }
+
+// GetLogControl returns the control of the global logger's levels and sampling
+func GetLogControl() *logging.Control {
+	return logControl
+}
+
+// drainContext bounds a graceful drain by the configured drain timeout
+func drainContext(cfg *config.Config) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), cfg.Lifecycle.DrainTimeout)
+}
+
+// stopRelay stops an outbox relay, publishing what the outbox holds within
+// the drain timeout
+func stopRelay(cfg *config.Config, relay *streaming.Relay) {
+	ctx, cancel := drainContext(cfg)
+	defer cancel()
+	relay.Stop(ctx)
+}
+
+// shutdownStreaming delivers the Kafka messages still buffered within the
+// drain timeout and closes the streaming manager
+func shutdownStreaming(cfg *config.Config, manager *streaming.Manager) {
+	ctx, cancel := drainContext(cfg)
+	defer cancel()
+	if err := manager.Shutdown(ctx); err != nil {
+		GetLogger().Warn("Failed to flush Kafka messages", zap.Error(err))
+	}
+}
+
+// indexCheckTimeout bounds the startup check of the expected indexes
+const indexCheckTimeout = 10 * time.Second
+
+// warnMissingIndexes warns about each index the API's hot queries rely on that
+// is missing, along with the migration that creates it
+func warnMissingIndexes(ctx context.Context, manager *storage.Manager) {
+	ctx, cancel := context.WithTimeout(ctx, indexCheckTimeout)
+	defer cancel()
+
+	missing, err := manager.CheckIndexes(ctx)
+	if err != nil {
+		GetLogger().Warn("Failed to check database indexes", zap.Error(err))
+	}
+	for _, index := range missing {
+		GetLogger().Warn("Database index is missing, queries will be slow",
+			zap.String("table", index.Table),
+			zap.String("index", index.Name),
+			zap.String("queries", index.Purpose),
+			zap.String("migration", "migrations/"+index.Migration))
+	}
+}
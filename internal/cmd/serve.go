@@ -9,9 +9,19 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/cosmos/state-mesh/internal/alerting"
+	"github.com/cosmos/state-mesh/internal/anomaly"
 	"github.com/cosmos/state-mesh/internal/api"
+	"github.com/cosmos/state-mesh/internal/commission"
 	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/demo"
+	"github.com/cosmos/state-mesh/internal/digest"
+	"github.com/cosmos/state-mesh/internal/email"
+	"github.com/cosmos/state-mesh/internal/incidents"
+	"github.com/cosmos/state-mesh/internal/reminders"
 	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -28,7 +38,10 @@ The server exposes:
 - GraphQL API on the configured port (default: 8080)
 - REST API on the configured port (default: 8081)
 - Metrics endpoint on /metrics
-- Health check endpoint on /health`,
+- Liveness probe on /healthz and readiness probe on /readyz
+
+With --in-memory the server uses an in-process store fed by simulated chains
+instead of PostgreSQL, ClickHouse, Kafka and chain nodes. State is lost on exit.`,
 	RunE: runServe,
 }
 
@@ -41,6 +54,7 @@ func init() {
 	serveCmd.Flags().Int("metrics-port", 9090, "Metrics server port")
 	serveCmd.Flags().Bool("enable-playground", true, "Enable GraphQL playground")
 	serveCmd.Flags().Bool("enable-cors", true, "Enable CORS headers")
+	serveCmd.Flags().Bool("in-memory", false, "Serve synthetic chain state from an in-process store without external dependencies")
 
 	// Bind flags to viper
 	viper.BindPFlag("api.graphql.port", serveCmd.Flags().Lookup("graphql-port"))
@@ -48,6 +62,7 @@ func init() {
 	viper.BindPFlag("api.metrics.port", serveCmd.Flags().Lookup("metrics-port"))
 	viper.BindPFlag("api.graphql.playground", serveCmd.Flags().Lookup("enable-playground"))
 	viper.BindPFlag("api.cors.enabled", serveCmd.Flags().Lookup("enable-cors"))
+	viper.BindPFlag("serve.in_memory", serveCmd.Flags().Lookup("in-memory"))
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
@@ -65,6 +80,13 @@ func runServe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	inMemory := viper.GetBool("serve.in_memory")
+	if inMemory {
+		cfg.Database.Driver = config.DriverMemory
+		cfg.Database.ClickHouse.Enabled = false
+		cfg.Streaming.Enabled = false
+	}
+
 	// Initialize storage
 	storageManager, err := storage.NewManager(cfg.Database)
 	if err != nil {
@@ -78,55 +100,229 @@ func runServe(cmd *cobra.Command, args []string) error {
 	}
 
 	logger.Info("Database connections established")
+	warnMissingIndexes(context.Background(), storageManager)
+
+	// Simulate the configured chains when running in memory
+	if inMemory {
+		var chains []config.ChainConfig
+		for _, chainCfg := range cfg.Chains {
+			if chainCfg.Enabled {
+				chains = append(chains, chainCfg)
+			}
+		}
+		if len(chains) == 0 {
+			demoChain := config.ChainConfig{Name: "demo", ChainID: "demo-1", Bech32Prefix: "cosmos", Enabled: true}
+			cfg.Chains = append(cfg.Chains, demoChain)
+			chains = append(chains, demoChain)
+		}
+
+		generator := demo.NewGenerator(chains, storageManager, 0)
+		if err := generator.Start(context.Background()); err != nil {
+			return fmt.Errorf("failed to start demo generator: %w", err)
+		}
+		defer generator.Stop()
+
+		logger.Info("Serving synthetic state from memory", zap.Int("chains", len(chains)))
+	}
 
 	// Initialize API server
-	apiServer, err := api.NewServer(cfg.API, storageManager, logger)
+	apiServer, err := api.NewServer(cfg.API, cfg.Chains, storageManager, logger)
 	if err != nil {
 		return fmt.Errorf("failed to initialize API server: %w", err)
 	}
+	apiServer.UseLogControl(GetLogControl())
+
+	// Hold the startup probe and readiness until the migrations are applied
+	apiServer.RegisterStartupCheck("migrations", storageManager.CheckMigrations)
+
+	// Register readiness checks for streaming and chain endpoints
+	if cfg.Streaming.Enabled {
+		streamingManager, err := streaming.NewManager(cfg.Streaming, logger)
+		if err != nil {
+			logger.Warn("Failed to initialize streaming, readiness will not cover Kafka", zap.Error(err))
+		} else {
+			defer streamingManager.Close()
+			apiServer.RegisterHealthCheck("kafka", streamingManager.Ping)
+		}
+	}
+
+	for _, chainCfg := range cfg.Chains {
+		if !chainCfg.Enabled || inMemory {
+			continue
+		}
+
+		client, err := cosmos.NewClient(chainCfg.Name, chainCfg.GRPCEndpoint)
+		if err != nil {
+			logger.Warn("Failed to create client for chain health check",
+				zap.String("chain", chainCfg.Name),
+				zap.Error(err))
+			continue
+		}
+		defer client.Close()
+
+		apiServer.RegisterHealthCheck("chain:"+chainCfg.Name, client.Ping)
+	}
 
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Email tenants' notifications through the SMTP server, if any
+	var emails *email.Sender
+	if smtpCfg := cfg.API.Tenancy.EmailSMTP(); cfg.API.Tenancy.Enabled && smtpCfg.Host != "" {
+		if emails, err = email.NewSender(cfg.API.Tenancy.Email, smtpCfg); err != nil {
+			return fmt.Errorf("failed to initialize email sender: %w", err)
+		}
+	}
+
+	// Send tenants' notification digests
+	if cfg.API.Tenancy.Enabled && cfg.API.Tenancy.Digests.Enabled {
+		scheduler := digest.New(cfg.API.Tenancy.Digests, storageManager, emails, logger)
+		scheduler.Start(ctx)
+		defer scheduler.Stop()
+	}
+
+	// Remind tenants of their watched delegators' unbonding completions
+	if cfg.API.Tenancy.Enabled && cfg.API.Tenancy.UnbondingReminders.Enabled {
+		scheduler := reminders.New(cfg.API.Tenancy.UnbondingReminders, storageManager, emails, logger)
+		scheduler.Start(ctx)
+		defer scheduler.Stop()
+	}
+
+	// Alert tenants of commission increases of their delegators' validators
+	if cfg.API.Tenancy.Enabled && cfg.API.Tenancy.CommissionAlerts.Enabled {
+		notifier := commission.New(cfg.API.Tenancy.CommissionAlerts, storageManager, emails, logger)
+		notifier.Start(ctx)
+		defer notifier.Stop()
+	}
+
+	// Detect anomalies in the chains' hourly metrics
+	if cfg.API.Anomalies.Enabled {
+		if storageManager.ClickHouse() == nil {
+			logger.Warn("ClickHouse is not available, anomaly detection is disabled")
+		} else {
+			detector := anomaly.New(cfg.API.Anomalies, cfg.Chains, cfg.API.Tenancy.Enabled, storageManager, emails, logger)
+			detector.Start(ctx)
+			defer detector.Stop()
+		}
+	}
+
 	// Start servers
 	errChan := make(chan error, 3)
 
-	// Start GraphQL server
-	go func() {
-		logger.Info("Starting GraphQL server", zap.Int("port", cfg.API.GraphQL.Port))
-		if err := apiServer.StartGraphQL(ctx); err != nil && err != http.ErrServerClosed {
-			errChan <- fmt.Errorf("GraphQL server error: %w", err)
+	// Serve the ingesters' events on the stream endpoint
+	if cfg.Streaming.Enabled && !inMemory {
+		consumer, err := streaming.NewConsumer(cfg.Streaming, logger)
+		if err != nil {
+			logger.Warn("Failed to initialize event consumer, streaming endpoint is disabled", zap.Error(err))
+		} else {
+			go func() {
+				apiServer.StreamEvents(ctx, consumer)
+				consumer.Close()
+			}()
+		}
+	}
+
+	// Evaluate tenants' alert rules against the ingesters' events
+	if cfg.API.Tenancy.Enabled && cfg.API.Tenancy.AlertRules.Enabled && !inMemory {
+		consumer, err := streaming.NewGroupConsumer(cfg.Streaming, alerting.ConsumerGroup, logger)
+		if err != nil {
+			logger.Warn("Failed to initialize alert rule consumer, alert rules are not evaluated", zap.Error(err))
+		} else {
+			engine := alerting.New(cfg.API.Tenancy.AlertRules, storageManager, emails, logger)
+			engine.Start(ctx, consumer)
+			defer consumer.Close()
+			defer engine.Stop()
+		}
+	}
+
+	// Page the operators when the deployment itself is unhealthy
+	if incidentsCfg := cfg.Telemetry.Incidents; incidentsCfg.Enabled {
+		monitor := incidents.New(incidentsCfg, logger)
+		monitor.Register(storageManager.Driver(), incidents.DatabaseCheck(storageManager.Driver(), storageManager.State().Ping))
+		if clickhouse := storageManager.ClickHouse(); clickhouse != nil {
+			monitor.Register("clickhouse", incidents.DatabaseCheck("clickhouse", clickhouse.Ping))
 		}
-	}()
 
-	// Start REST server
-	go func() {
-		logger.Info("Starting REST server", zap.Int("port", cfg.API.REST.Port))
-		if err := apiServer.StartREST(ctx); err != nil && err != http.ErrServerClosed {
-			errChan <- fmt.Errorf("REST server error: %w", err)
+		var chains []string
+		for _, chainCfg := range cfg.Chains {
+			if chainCfg.Enabled {
+				chains = append(chains, chainCfg.Name)
+			}
 		}
-	}()
+		monitor.Register("chain_lag", incidents.ChainLagCheck(storageManager.State(), chains, incidentsCfg.MaxChainLag))
+		monitor.Register("chain_halt", incidents.ChainHaltCheck(storageManager.State(), chains))
 
-	// Start metrics server
-	go func() {
-		logger.Info("Starting metrics server", zap.Int("port", cfg.API.Metrics.Port))
-		if err := apiServer.StartMetrics(ctx); err != nil && err != http.ErrServerClosed {
-			errChan <- fmt.Errorf("metrics server error: %w", err)
+		if cfg.Streaming.Enabled && !inMemory {
+			groups := incidentsCfg.KafkaGroups
+			if cfg.API.Tenancy.Enabled && cfg.API.Tenancy.AlertRules.Enabled {
+				groups = append([]string{alerting.ConsumerGroup}, groups...)
+			}
+			for _, group := range groups {
+				monitor.Register("kafka_backlog:"+group, incidents.KafkaBacklogCheck(cfg.Streaming.Kafka, group, incidentsCfg.MaxKafkaBacklog, incidentsCfg.Timeout))
+			}
 		}
-	}()
+
+		monitor.Start(ctx)
+		defer monitor.Stop()
+	}
+
+	if cfg.API.Unified.Enabled {
+		// Serve everything on one port
+		go func() {
+			logger.Info("Starting unified server", zap.Int("port", cfg.API.Unified.Port))
+			if err := apiServer.StartUnified(ctx); err != nil && err != http.ErrServerClosed {
+				errChan <- fmt.Errorf("unified server error: %w", err)
+			}
+		}()
+	} else {
+		// Start GraphQL server
+		go func() {
+			logger.Info("Starting GraphQL server", zap.Int("port", cfg.API.GraphQL.Port))
+			if err := apiServer.StartGraphQL(ctx); err != nil && err != http.ErrServerClosed {
+				errChan <- fmt.Errorf("GraphQL server error: %w", err)
+			}
+		}()
+
+		// Start REST server
+		go func() {
+			logger.Info("Starting REST server", zap.Int("port", cfg.API.REST.Port))
+			if err := apiServer.StartREST(ctx); err != nil && err != http.ErrServerClosed {
+				errChan <- fmt.Errorf("REST server error: %w", err)
+			}
+		}()
+
+		// Start metrics server
+		go func() {
+			logger.Info("Starting metrics server", zap.Int("port", cfg.API.Metrics.Port))
+			if err := apiServer.StartMetrics(ctx); err != nil && err != http.ErrServerClosed {
+				errChan <- fmt.Errorf("metrics server error: %w", err)
+			}
+		}()
+	}
 
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	scheme := "http"
+	if cfg.API.TLS.Enabled {
+		scheme = "https"
+	}
+	graphqlPort, restPort, metricsPort := cfg.API.GraphQL.Port, cfg.API.REST.Port, cfg.API.Metrics.Port
+	metricsScheme := "http"
+	if cfg.API.Unified.Enabled {
+		graphqlPort, restPort, metricsPort = cfg.API.Unified.Port, cfg.API.Unified.Port, cfg.API.Unified.Port
+		metricsScheme = scheme
+	}
+
 	logger.Info("State Mesh API server started successfully")
-	logger.Info("GraphQL endpoint", zap.String("url", fmt.Sprintf("http://localhost:%d/graphql", cfg.API.GraphQL.Port)))
-	logger.Info("REST endpoint", zap.String("url", fmt.Sprintf("http://localhost:%d/api/v1", cfg.API.REST.Port)))
-	logger.Info("Metrics endpoint", zap.String("url", fmt.Sprintf("http://localhost:%d/metrics", cfg.API.Metrics.Port)))
+	logger.Info("GraphQL endpoint", zap.String("url", fmt.Sprintf("%s://localhost:%d/graphql", scheme, graphqlPort)))
+	logger.Info("REST endpoint", zap.String("url", fmt.Sprintf("%s://localhost:%d/api/v1", scheme, restPort)))
+	logger.Info("Metrics endpoint", zap.String("url", fmt.Sprintf("%s://localhost:%d/metrics", metricsScheme, metricsPort)))
 
 	if cfg.API.GraphQL.Playground {
-		logger.Info("GraphQL Playground", zap.String("url", fmt.Sprintf("http://localhost:%d/playground", cfg.API.GraphQL.Port)))
+		logger.Info("GraphQL Playground", zap.String("url", fmt.Sprintf("%s://localhost:%d/playground", scheme, graphqlPort)))
 	}
 
 	select {
@@ -137,12 +333,20 @@ func runServe(cmd *cobra.Command, args []string) error {
 		logger.Info("Received shutdown signal", zap.String("signal", sig.String()))
 	}
 
-	// Graceful shutdown
-	logger.Info("Shutting down servers...")
+	// Drain: fail readiness so that load balancers stop routing requests
+	// here, then stop accepting requests and let those in flight finish
+	logger.Info("Draining servers",
+		zap.Duration("delay", cfg.Lifecycle.DrainDelay),
+		zap.Duration("timeout", cfg.Lifecycle.DrainTimeout))
+	apiServer.Drain()
+	select {
+	case <-time.After(cfg.Lifecycle.DrainDelay):
+	case <-sigChan:
+		// A second signal skips the delay
+	}
 	cancel()
 
-	// Give servers time to shutdown gracefully
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, shutdownCancel := drainContext(cfg)
 	defer shutdownCancel()
 
 	if err := apiServer.Shutdown(shutdownCtx); err != nil {
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/verifier"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Cross-check stored state against live chain queries",
+	Long: `Verify samples stored balances and delegations and compares each row
+against the chain's gRPC endpoint at the row's stored height.
+
+Discrepancies larger than the configured relative tolerance are reported.
+With --repair, mismatched rows are overwritten with the live chain value
+(or removed when the chain no longer has them).`,
+	RunE: runVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	// Verify-specific flags
+	verifyCmd.Flags().StringSlice("chains", []string{}, "Specific chains to verify (default: all configured chains)")
+	verifyCmd.Flags().Int("sample-size", 100, "Number of balances and delegations to sample per chain")
+	verifyCmd.Flags().Float64("tolerance", 0, "Allowed relative difference between stored and live values (e.g. 0.001 = 0.1%)")
+	verifyCmd.Flags().Bool("repair", false, "Overwrite mismatched rows with live chain values")
+
+	// Bind flags to viper
+	viper.BindPFlag("verify.chains", verifyCmd.Flags().Lookup("chains"))
+	viper.BindPFlag("verify.sample_size", verifyCmd.Flags().Lookup("sample-size"))
+	viper.BindPFlag("verify.tolerance", verifyCmd.Flags().Lookup("tolerance"))
+	viper.BindPFlag("verify.repair", verifyCmd.Flags().Lookup("repair"))
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	logger := GetLogger()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Initialize storage
+	storageManager, err := storage.NewManager(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	defer storageManager.Close()
+
+	ctx := context.Background()
+	if err := storageManager.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to connect to databases: %w", err)
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range viper.GetStringSlice("verify.chains") {
+		selected[name] = true
+	}
+
+	v := verifier.New(cfg.Verify, storageManager)
+
+	out := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(out, "CHAIN\tKIND\tADDRESS\tKEY\tHEIGHT\tSTORED\tLIVE\tREPAIRED")
+
+	unresolved := 0
+	var reports []*verifier.Report
+	for _, chainCfg := range cfg.Chains {
+		if !chainCfg.Enabled || (len(selected) > 0 && !selected[chainCfg.Name]) {
+			continue
+		}
+
+		client, err := cosmos.NewClient(chainCfg.Name, chainCfg.GRPCEndpoint)
+		if err != nil {
+			logger.Error("Failed to create client for chain",
+				zap.String("chain", chainCfg.Name),
+				zap.Error(err))
+			continue
+		}
+
+		report, err := v.VerifyChain(ctx, client)
+		client.Close()
+		if err != nil {
+			return fmt.Errorf("failed to verify chain %s: %w", chainCfg.Name, err)
+		}
+
+		for _, d := range report.Discrepancies {
+			fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%t\n",
+				d.ChainName, d.Kind, d.Address, d.Key, d.Height, d.Stored, d.Live, d.Repaired)
+		}
+
+		unresolved += report.Unresolved()
+		reports = append(reports, report)
+	}
+	out.Flush()
+
+	for _, report := range reports {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: checked %d balances, %d delegations; %d discrepancies, %d query errors (%s)\n",
+			report.ChainName,
+			report.BalancesChecked,
+			report.DelegationsChecked,
+			len(report.Discrepancies),
+			report.Errors,
+			report.Duration.Round(1e6))
+	}
+
+	if unresolved > 0 {
+		return fmt.Errorf("found %d unresolved discrepancies", unresolved)
+	}
+
+	return nil
+}
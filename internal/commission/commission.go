@@ -0,0 +1,250 @@
+// Package commission notifies tenants' webhooks and email subscriptions when
+// a validator their watched delegators delegate to raises its commission rate
+package commission
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/email"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/webhook"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+var deliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "statemesh",
+	Subsystem: "commission_alerts",
+	Name:      "deliveries_total",
+	Help:      "Commission alert deliveries by channel (webhook or email) and result (success or failure)",
+}, []string{"channel", "result"})
+
+// Notifier sends commission alerts. Each check it reads the commission
+// increases the staking module detected that no tenant was notified of yet,
+// and sends each to the webhooks and email subscriptions subscribed to
+// commission increases of every tenant with a watched delegator of the
+// validator. Increases are claimed in
+// the state store before they are sent, so that only one API server sends
+// them; an increase nothing received is retried at the next check, until
+// it is older than the maximum age.
+type Notifier struct {
+	cfg      config.CommissionAlertConfig
+	storage  *storage.Manager
+	webhooks *webhook.Sender
+	emails   *email.Sender
+	logger   *zap.Logger
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// subscriber is a tenant with webhooks or email subscriptions subscribed to
+// commission increases
+type subscriber struct {
+	tenantID   string
+	webhooks   []types.Webhook
+	emails     []types.EmailSubscription
+	delegators map[string][]string // watched addresses by chain
+}
+
+// New creates a commission alert notifier, emailing with the email sender
+// when it is not nil
+func New(cfg config.CommissionAlertConfig, storage *storage.Manager, emails *email.Sender, logger *zap.Logger) *Notifier {
+	return &Notifier{
+		cfg:      cfg,
+		storage:  storage,
+		webhooks: webhook.NewSender(cfg.Timeout),
+		emails:   emails,
+		logger:   logger.Named("commission_alerts"),
+	}
+}
+
+// Start looks for commission increases in the background on the check
+// interval
+func (n *Notifier) Start(ctx context.Context) {
+	ctx, n.cancel = context.WithCancel(ctx)
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		ticker := time.NewTicker(n.cfg.CheckInterval)
+		defer ticker.Stop()
+
+		n.logger.Info("Commission alert notifier started", zap.Duration("check_interval", n.cfg.CheckInterval))
+
+		for {
+			select {
+			case <-ctx.Done():
+				n.logger.Info("Commission alert notifier stopped")
+				return
+			case <-ticker.C:
+				if err := n.RunOnce(ctx); err != nil {
+					n.logger.Error("Failed to send commission alerts", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the notifier and waits for the alerts being sent
+func (n *Notifier) Stop() {
+	if n.cancel != nil {
+		n.cancel()
+	}
+	n.wg.Wait()
+}
+
+// RunOnce notifies tenants of the pending commission increases
+func (n *Notifier) RunOnce(ctx context.Context) error {
+	now := time.Now().UTC()
+	increases, err := n.storage.Commissions().GetPendingCommissionIncreases(ctx, now.Add(-n.cfg.MaxAge))
+	if err != nil {
+		return err
+	}
+	if len(increases) == 0 {
+		return nil
+	}
+
+	subscribers, err := n.subscribers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, change := range increases {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		claimed, err := n.storage.Commissions().ClaimCommissionChange(ctx, change, nil, &now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue // notified by another server
+		}
+
+		attempted, delivered, err := n.notify(ctx, subscribers, change)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if err != nil || (attempted > 0 && delivered == 0) {
+			if _, err := n.storage.Commissions().ClaimCommissionChange(ctx, change, &now, nil); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// subscribers returns the tenants with webhooks or email subscriptions
+// subscribed to commission increases and their watched addresses
+func (n *Notifier) subscribers(ctx context.Context) ([]subscriber, error) {
+	tenants, err := n.storage.Tenants().GetTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscribers []subscriber
+	for _, tenant := range tenants {
+		webhooks, err := n.storage.Tenants().GetWebhooks(ctx, tenant.ID)
+		if err != nil {
+			return nil, err
+		}
+		sub := subscriber{tenantID: tenant.ID, delegators: make(map[string][]string)}
+		for _, hook := range webhooks {
+			if webhook.Subscribed(hook, webhook.EventCommissionIncrease) {
+				sub.webhooks = append(sub.webhooks, hook)
+			}
+		}
+		if sub.emails, err = n.emails.Subscriptions(ctx, n.storage.Tenants(), tenant.ID, webhook.EventCommissionIncrease); err != nil {
+			return nil, err
+		}
+		if len(sub.webhooks) == 0 && len(sub.emails) == 0 {
+			continue
+		}
+
+		watchlists, err := n.storage.Tenants().GetWatchlists(ctx, tenant.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, watchlist := range watchlists {
+			for _, address := range watchlist.Addresses {
+				if !slices.Contains(sub.delegators[watchlist.ChainName], address) {
+					sub.delegators[watchlist.ChainName] = append(sub.delegators[watchlist.ChainName], address)
+				}
+			}
+		}
+		if len(sub.delegators) > 0 {
+			subscribers = append(subscribers, sub)
+		}
+	}
+	return subscribers, nil
+}
+
+// notify sends an increase to the webhooks and email subscriptions of the
+// subscribers watching one of the validator's delegators, returning how many deliveries were attempted
+// and how many succeeded
+func (n *Notifier) notify(ctx context.Context, subscribers []subscriber, change types.CommissionChange) (int, int, error) {
+	var attempted, delivered int
+	for _, sub := range subscribers {
+		candidates := sub.delegators[change.ChainName]
+		if len(candidates) == 0 {
+			continue
+		}
+		delegators, err := n.storage.Commissions().GetDelegatorsOf(ctx, change.ChainName, change.OperatorAddress, candidates)
+		if err != nil {
+			return attempted, delivered, err
+		}
+		if len(delegators) == 0 {
+			continue
+		}
+
+		alert := types.CommissionAlert{
+			TenantID:   sub.tenantID,
+			Change:     change,
+			Delegators: delegators,
+			SentAt:     time.Now().UTC(),
+		}
+		for i := range sub.webhooks {
+			attempted++
+			if err := n.webhooks.Send(ctx, &sub.webhooks[i], webhook.EventCommissionIncrease, alert); err != nil {
+				n.logger.Warn("Failed to deliver commission alert",
+					zap.String("tenant", sub.tenantID),
+					zap.String("webhook", sub.webhooks[i].ID),
+					zap.String("chain", change.ChainName),
+					zap.String("validator", change.OperatorAddress),
+					zap.Error(err))
+				deliveries.WithLabelValues("webhook", "failure").Inc()
+				continue
+			}
+			deliveries.WithLabelValues("webhook", "success").Inc()
+			delivered++
+		}
+		for _, subscription := range sub.emails {
+			attempted++
+			msg := email.Message{To: subscription.Email, Event: webhook.EventCommissionIncrease, Data: alert, UnsubscribeToken: subscription.UnsubscribeToken}
+			if err := n.emails.Send(msg); err != nil {
+				n.logger.Warn("Failed to email commission alert",
+					zap.String("tenant", sub.tenantID),
+					zap.String("email_subscription", subscription.ID),
+					zap.String("chain", change.ChainName),
+					zap.String("validator", change.OperatorAddress),
+					zap.Error(err))
+				deliveries.WithLabelValues("email", "failure").Inc()
+				continue
+			}
+			deliveries.WithLabelValues("email", "success").Inc()
+			delivered++
+		}
+	}
+	return attempted, delivered, nil
+}
@@ -1,9 +1,17 @@
 package config
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/spf13/viper"
 )
 
@@ -14,23 +22,250 @@ type Config struct {
 	Streaming StreamingConfig  `mapstructure:"streaming"`
 	API       APIConfig        `mapstructure:"api"`
 	Ingester  IngesterConfig   `mapstructure:"ingester"`
+	Verify    VerifyConfig     `mapstructure:"verify"`
+	Retention RetentionConfig  `mapstructure:"retention"`
 	Log       LogConfig        `mapstructure:"log"`
+	Telemetry TelemetryConfig  `mapstructure:"telemetry"`
+	Secrets   SecretsConfig    `mapstructure:"secrets"`
+	Lifecycle LifecycleConfig  `mapstructure:"lifecycle"`
+
+	StateListener StateListenerConfig `mapstructure:"state_listener"`
+	Plugins       []PluginConfig      `mapstructure:"plugins"`
 }
 
 // ChainConfig represents configuration for a single Cosmos SDK chain
 type ChainConfig struct {
-	Name         string   `mapstructure:"name"`
-	ChainID      string   `mapstructure:"chain_id"`
-	GRPCEndpoint string   `mapstructure:"grpc_endpoint"`
-	RESTEndpoint string   `mapstructure:"rest_endpoint"`
-	Modules      []string `mapstructure:"modules"`
-	Enabled      bool     `mapstructure:"enabled"`
+	Name         string         `mapstructure:"name"`
+	ChainID      string         `mapstructure:"chain_id"`
+	GRPCEndpoint string         `mapstructure:"grpc_endpoint"`
+	RESTEndpoint string         `mapstructure:"rest_endpoint"`
+	Bech32Prefix string         `mapstructure:"bech32_prefix"`
+	Modules      []ModuleConfig `mapstructure:"modules"`
+	Enabled      bool           `mapstructure:"enabled"`
+
+	// BaseDenom is the chain's staking denom, e.g. "uatom", and DenomExponent
+	// the decimals of its display denom, e.g. 6 for ATOM
+	BaseDenom     string `mapstructure:"base_denom"`
+	DenomExponent int    `mapstructure:"denom_exponent"`
+
+	// Epoch is the identifier of the epoch the chain processes most state
+	// changes at, e.g. "day" on Osmosis. When set, epoch boundaries are
+	// recorded and modules can be scheduled right after them.
+	Epoch string `mapstructure:"epoch"`
+
+	// Price values the chain's staking token in the network overview
+	Price ChainPriceConfig `mapstructure:"price"`
+
+	// HaltThreshold overrides the ingester's halt_threshold for chains with
+	// slower or faster blocks; 0 keeps it
+	HaltThreshold time.Duration `mapstructure:"halt_threshold"`
+}
+
+// ChainPriceConfig sets the USD price of a chain's display denom, either
+// fixed or read from CoinGecko by the coin's API ID, e.g. "cosmos" for ATOM
+type ChainPriceConfig struct {
+	USD         float64 `mapstructure:"usd"`
+	CoinGeckoID string  `mapstructure:"coingecko_id"`
+}
+
+// DefaultBaseDenom is the staking denom of chains that configure none, the
+// Cosmos SDK default
+const DefaultBaseDenom = "stake"
+
+// MaxDenomExponent is the largest supported display denom exponent
+const MaxDenomExponent = 18
+
+var (
+	bech32PrefixPattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	denomPattern        = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9/:._-]{2,127}$`)
+)
+
+// StakingDenom returns the denom delegations on the chain are made in
+func (c ChainConfig) StakingDenom() string {
+	if c.BaseDenom == "" {
+		return DefaultBaseDenom
+	}
+	return c.BaseDenom
+}
+
+// DisplayAmount converts an amount of the chain's base denom to display units,
+// e.g. 1500000 uatom to 1.5. Other denoms and unparsable amounts are not
+// converted.
+func (c ChainConfig) DisplayAmount(denom, amount string) (string, bool) {
+	if c.DenomExponent == 0 || denom != c.StakingDenom() {
+		return "", false
+	}
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return "", false
+	}
+	return value.Shift(-int32(c.DenomExponent)).String(), true
+}
+
+// ModuleConfig represents configuration for a single module of a chain
+type ModuleConfig struct {
+	Name     string            `mapstructure:"name"`
+	Enabled  bool              `mapstructure:"enabled"`
+	Interval time.Duration     `mapstructure:"interval"` // 0 = ingester poll interval
+	Schedule string            `mapstructure:"schedule"` // "" = every interval, or ScheduleEpoch
+	Options  map[string]string `mapstructure:"options"`
+
+	// Errors overrides the ingester's module_errors for this module when its
+	// policy is set
+	Errors ModuleErrorsConfig `mapstructure:"errors"`
+}
+
+// ModuleErrorsConfig decides what happens when polling a module fails. Under
+// every policy the failure is recorded, the chain's other modules carry on and
+// the module is polled again at the next tick. ModuleErrorRetry also retries
+// it up to Retries times within the cycle, waiting RetryBackoff and doubling.
+// ModuleErrorDisable stops polling it after DisableAfter consecutive failed
+// cycles, for DisableFor or, when 0, until the ingester restarts.
+type ModuleErrorsConfig struct {
+	Policy       string        `mapstructure:"policy"`
+	Retries      int           `mapstructure:"retries"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	DisableAfter int           `mapstructure:"disable_after"`
+	DisableFor   time.Duration `mapstructure:"disable_for"`
+}
+
+// Module error policies
+const (
+	ModuleErrorSkip    = "skip"
+	ModuleErrorRetry   = "retry"
+	ModuleErrorDisable = "disable"
+)
+
+// validate checks the policy and that no setting is negative
+func (c ModuleErrorsConfig) validate() error {
+	switch c.Policy {
+	case "", ModuleErrorSkip, ModuleErrorRetry, ModuleErrorDisable:
+	default:
+		return fmt.Errorf("unknown error policy %q", c.Policy)
+	}
+	if c.Retries < 0 || c.RetryBackoff < 0 || c.DisableAfter < 0 || c.DisableFor < 0 {
+		return fmt.Errorf("error policy settings must not be negative")
+	}
+	return nil
+}
+
+// Merge returns the module's error handling, c, with unset settings taken
+// from defaults. A module without a policy uses defaults as they are.
+func (c ModuleErrorsConfig) Merge(defaults ModuleErrorsConfig) ModuleErrorsConfig {
+	if c.Policy == "" {
+		return defaults
+	}
+	if c.Retries == 0 {
+		c.Retries = defaults.Retries
+	}
+	if c.RetryBackoff == 0 {
+		c.RetryBackoff = defaults.RetryBackoff
+	}
+	if c.DisableAfter == 0 {
+		c.DisableAfter = defaults.DisableAfter
+	}
+	if c.DisableFor == 0 {
+		c.DisableFor = defaults.DisableFor
+	}
+	return c
+}
+
+// ScheduleEpoch schedules a module once per epoch of the chain, right after
+// the epoch's state changes were processed, instead of every interval
+const ScheduleEpoch = "epoch"
+
+// PluginConfig represents an out-of-process module plugin. The plugin serves
+// the module it is named after, which chains then enable like a built-in one.
+type PluginConfig struct {
+	Name    string        `mapstructure:"name"`
+	Command string        `mapstructure:"command"`
+	Args    []string      `mapstructure:"args"`
+	Timeout time.Duration `mapstructure:"timeout"` // per call, 0 = 30s
+}
+
+// moduleAliases maps alternative module names to their canonical store key
+var moduleAliases = map[string]string{
+	"governance": "gov",
+}
+
+// CanonicalModuleName returns the canonical name of a module, resolving aliases
+func CanonicalModuleName(name string) string {
+	if canonical, ok := moduleAliases[name]; ok {
+		return canonical
+	}
+	return name
 }
 
+// Module returns the configuration of the named module
+func (c ChainConfig) Module(name string) (ModuleConfig, bool) {
+	name = CanonicalModuleName(name)
+	for _, module := range c.Modules {
+		if CanonicalModuleName(module.Name) == name {
+			return module, true
+		}
+	}
+	return ModuleConfig{}, false
+}
+
+// ModuleEnabled reports whether the named module is configured and enabled
+func (c ChainConfig) ModuleEnabled(name string) bool {
+	module, ok := c.Module(name)
+	return ok && module.Enabled
+}
+
+// EnabledModules returns the enabled modules of the chain
+func (c ChainConfig) EnabledModules() []ModuleConfig {
+	var modules []ModuleConfig
+	for _, module := range c.Modules {
+		if module.Enabled {
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
+
+// ModuleNames returns the names of the enabled modules of the chain
+func (c ChainConfig) ModuleNames() []string {
+	var names []string
+	for _, module := range c.EnabledModules() {
+		names = append(names, module.Name)
+	}
+	return names
+}
+
+// Option returns a module option, or the fallback when it is not set
+func (m ModuleConfig) Option(key, fallback string) string {
+	if value, ok := m.Options[key]; ok {
+		return value
+	}
+	return fallback
+}
+
+// ListOption returns a comma separated module option as a list, empty when it
+// is not set
+func (m ModuleConfig) ListOption(key string) []string {
+	var items []string
+	for _, item := range strings.Split(m.Option(key, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Supported state store drivers
+const (
+	DriverPostgres    = "postgres"
+	DriverCockroachDB = "cockroachdb"
+	DriverMemory      = "memory"
+)
+
 // DatabaseConfig represents database configuration
 type DatabaseConfig struct {
+	Driver     string           `mapstructure:"driver"`
 	Postgres   PostgresConfig   `mapstructure:"postgres"`
 	ClickHouse ClickHouseConfig `mapstructure:"clickhouse"`
+	WAL        WALConfig        `mapstructure:"wal"`
 }
 
 // PostgresConfig represents PostgreSQL configuration
@@ -43,12 +278,66 @@ type PostgresConfig struct {
 	SSLMode  string `mapstructure:"ssl_mode"`
 	MaxConns int    `mapstructure:"max_conns"`
 	MinConns int    `mapstructure:"min_conns"`
+
+	// SlowQueryThreshold logs queries taking longer than this; 0 disables it
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+
+	// credentials are set when the user or password reference secrets
+	credentials *rotatingCredentials
 }
 
 // DSN returns the PostgreSQL Data Source Name
 func (p PostgresConfig) DSN() string {
+	return p.DSNWith(p.User, p.Password)
+}
+
+// DSNWith returns the PostgreSQL Data Source Name with the given credentials
+func (p PostgresConfig) DSNWith(user, password string) string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		p.Host, p.Port, p.User, p.Password, p.Database, p.SSLMode)
+		p.Host, p.Port, user, password, p.Database, p.SSLMode)
+}
+
+// RotatesCredentials reports whether the user or password are read from
+// secrets, which may rotate while the process runs
+func (p PostgresConfig) RotatesCredentials() bool {
+	return p.credentials != nil
+}
+
+// RotationInterval is how long credentials read from secrets are reused
+// before being read again, or zero when they are not read from secrets
+func (p PostgresConfig) RotationInterval() time.Duration {
+	if p.credentials == nil {
+		return 0
+	}
+	return p.credentials.resolver.refresh
+}
+
+// Credentials reads the user and password from their secrets. Secrets are
+// reused for the secrets refresh interval unless refresh is set, e.g. after
+// the credentials were rejected.
+func (p PostgresConfig) Credentials(ctx context.Context, refresh bool) (user, password string, err error) {
+	if p.credentials == nil {
+		return p.User, p.Password, nil
+	}
+	user, err = p.credentials.resolver.resolve(ctx, p.credentials.user, refresh)
+	if err != nil {
+		return "", "", err
+	}
+	// The user was just read again, so the password is read from the cache
+	// when both are fields of the same secret
+	password, err = p.credentials.resolver.resolve(ctx, p.credentials.password, refresh && !sameSecret(p.credentials.user, p.credentials.password))
+	if err != nil {
+		return "", "", err
+	}
+	return user, password, nil
+}
+
+// WALConfig controls the write-ahead log that buffers state writes while the
+// state store is unreachable. An empty Dir disables buffering.
+type WALConfig struct {
+	Dir               string        `mapstructure:"dir"`
+	MaxBytes          int64         `mapstructure:"max_bytes"`
+	ReconnectInterval time.Duration `mapstructure:"reconnect_interval"`
 }
 
 // ClickHouseConfig represents ClickHouse configuration
@@ -59,61 +348,709 @@ type ClickHouseConfig struct {
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
 	Enabled  bool   `mapstructure:"enabled"`
+
+	// SlowQueryThreshold logs queries and batch inserts taking longer than
+	// this; 0 disables it
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+
+	// ReconnectInterval is how often an unreachable ClickHouse is re-probed
+	ReconnectInterval time.Duration     `mapstructure:"reconnect_interval"`
+	Spool             SpoolConfig       `mapstructure:"spool"`
+	EventWrites       EventWritesConfig `mapstructure:"event_writes"`
+}
+
+// EventWritesConfig controls how balance, delegation and reward events reach
+// ClickHouse. By default every batch is inserted synchronously, which stalls
+// the listener under high event volume.
+type EventWritesConfig struct {
+	AsyncInsert AsyncInsertConfig `mapstructure:"async_insert"`
+	BufferTable BufferTableConfig `mapstructure:"buffer_table"`
+	Aggregate   AggregateConfig   `mapstructure:"aggregate"`
+}
+
+// AsyncInsertConfig has ClickHouse collect event inserts server-side and
+// write them once MaxDataSize bytes are pending or BusyTimeout has passed.
+// Unless Wait is set, inserts return before the data is written and a failed
+// write is never reported, so the spool cannot replay it; Wait is required
+// while the spool is enabled.
+type AsyncInsertConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Wait        bool          `mapstructure:"wait"`
+	MaxDataSize int64         `mapstructure:"max_data_size"`
+	BusyTimeout time.Duration `mapstructure:"busy_timeout"`
+}
+
+// BufferTableConfig routes event inserts through Buffer tables in front of the
+// event tables, which flush to them once every threshold of a layer's minimums
+// or any one of its maximums is reached. Buffer tables ignore deduplication
+// tokens, so events with IDs are still inserted into the event tables.
+type BufferTableConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Layers   int           `mapstructure:"layers"`
+	MinTime  time.Duration `mapstructure:"min_time"`
+	MaxTime  time.Duration `mapstructure:"max_time"`
+	MinRows  int64         `mapstructure:"min_rows"`
+	MaxRows  int64         `mapstructure:"max_rows"`
+	MinBytes int64         `mapstructure:"min_bytes"`
+	MaxBytes int64         `mapstructure:"max_bytes"`
+}
+
+// AggregateConfig coalesces balance events in process by chain, address and
+// denom, keeping the latest balance of each, and inserts them every
+// FlushInterval or once MaxKeys balances are pending. Pending events are
+// flushed on shutdown but not persisted: a crash loses them, as the state
+// changes behind them are already committed and are not replayed.
+type AggregateConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	MaxKeys       int           `mapstructure:"max_keys"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+// SpoolConfig controls the local disk queue that buffers analytics events
+// while ClickHouse is unreachable. An empty Dir disables buffering.
+type SpoolConfig struct {
+	Dir      string `mapstructure:"dir"`
+	MaxBytes int64  `mapstructure:"max_bytes"`
 }
 
 // StreamingConfig represents streaming configuration
 type StreamingConfig struct {
-	Enabled bool        `mapstructure:"enabled"`
-	Kafka   KafkaConfig `mapstructure:"kafka"`
+	Enabled bool         `mapstructure:"enabled"`
+	Kafka   KafkaConfig  `mapstructure:"kafka"`
+	Outbox  OutboxConfig `mapstructure:"outbox"`
+}
+
+// OutboxConfig represents the transactional outbox. Events are written to the
+// state store in the transaction that produced them and published to Kafka by
+// a relay, so no committed state goes without its event.
+type OutboxConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	Retention    time.Duration `mapstructure:"retention"` // how long delivered messages are kept
 }
 
 // KafkaConfig represents Kafka configuration
 type KafkaConfig struct {
 	Brokers []string `mapstructure:"brokers"`
 	Topic   string   `mapstructure:"topic"`
+
+	// SecurityProtocol is plaintext, ssl, sasl_plaintext or sasl_ssl
+	SecurityProtocol string          `mapstructure:"security_protocol"`
+	SASL             KafkaSASLConfig `mapstructure:"sasl"`
+}
+
+// KafkaSASLConfig represents Kafka SASL authentication
+type KafkaSASLConfig struct {
+	Mechanism string `mapstructure:"mechanism"` // PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512
+	Username  string `mapstructure:"username"`
+	Password  string `mapstructure:"password"`
 }
 
 // APIConfig represents API server configuration
 type APIConfig struct {
-	GraphQL GraphQLConfig `mapstructure:"graphql"`
-	REST    RESTConfig    `mapstructure:"rest"`
-	Metrics MetricsConfig `mapstructure:"metrics"`
-	CORS    CORSConfig    `mapstructure:"cors"`
+	GraphQL      GraphQLConfig `mapstructure:"graphql"`
+	REST         RESTConfig    `mapstructure:"rest"`
+	Metrics      MetricsConfig `mapstructure:"metrics"`
+	CORS         CORSConfig    `mapstructure:"cors"`
+	Tenancy      TenancyConfig `mapstructure:"tenancy"`
+	ChainTimeout time.Duration `mapstructure:"chain_timeout"` // per-chain timeout for cross-chain queries
+
+	Overview OverviewConfig `mapstructure:"overview"`
+
+	GasPrices GasPricesConfig `mapstructure:"gas_prices"`
+
+	Anomalies AnomalyConfig `mapstructure:"anomalies"`
+
+	Analytics AnalyticsConfig `mapstructure:"analytics"`
+
+	// RequestTimeout bounds each REST and GraphQL request, cancelling its
+	// storage queries when it runs out; event streams and subscriptions are
+	// not bounded. 0 disables it.
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+
+	// Applied to the GraphQL and REST servers
+	Compression CompressionConfig `mapstructure:"compression"`
+	TLS         TLSConfig         `mapstructure:"tls"`
+	HTTP2       bool              `mapstructure:"http2"` // served over TLS, or as cleartext h2c without it
+
+	Unified UnifiedConfig `mapstructure:"unified"`
+}
+
+// OverviewConfig represents network overview configuration. A chain is
+// reported stale when its latest block is older than StaleAfter and lagging
+// when ingestion is more than MaxLag blocks behind it. The overview is cached
+// for CacheTTL and prices for PriceRefresh.
+type OverviewConfig struct {
+	StaleAfter   time.Duration   `mapstructure:"stale_after"`
+	MaxLag       int64           `mapstructure:"max_lag"`
+	CacheTTL     time.Duration   `mapstructure:"cache_ttl"`
+	PriceRefresh time.Duration   `mapstructure:"price_refresh"`
+	CoinGecko    CoinGeckoConfig `mapstructure:"coingecko"`
+}
+
+// GasPricesConfig represents gas price recommendations, estimated from the
+// base fees the feemarket module recorded over the last Window
+type GasPricesConfig struct {
+	Window time.Duration `mapstructure:"window"`
+}
+
+// AnomalyConfig represents anomaly detection configuration. Every
+// CheckInterval the API server scores the last complete hour of each enabled
+// chain's delegation outflows, staking denom supply change and event rate
+// against their exponentially weighted moving average and variance over the
+// Window before it, with smoothing factor Alpha. Hours more than Threshold
+// standard deviations away are recorded as anomalies once MinSamples hours
+// are known, and posted to the tenants' webhooks subscribed to anomalies
+// within Timeout. Requires ClickHouse.
+type AnomalyConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	Window        time.Duration `mapstructure:"window"`
+	Alpha         float64       `mapstructure:"alpha"`
+	Threshold     float64       `mapstructure:"threshold"`
+	MinSamples    int           `mapstructure:"min_samples"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+}
+
+// AnalyticsConfig represents the analytics query API. Callers run the
+// curated ClickHouse queries named in Queries with arguments for their
+// parameters, never SQL; each runs read-only within Timeout and returns at
+// most MaxRows rows. Requires ClickHouse.
+type AnalyticsConfig struct {
+	Enabled bool                            `mapstructure:"enabled"`
+	Timeout time.Duration                   `mapstructure:"timeout"`
+	MaxRows int                             `mapstructure:"max_rows"`
+	Queries map[string]AnalyticsQueryConfig `mapstructure:"queries"`
+}
+
+// AnalyticsQueryConfig represents a query template. Its SQL, a SELECT or
+// WITH statement, must refer to the requested chain as {chain:String}, and
+// refers to each parameter as {name:Type}; ClickHouse binds the arguments,
+// which are never interpolated into the SQL.
+type AnalyticsQueryConfig struct {
+	Description string                 `mapstructure:"description"`
+	SQL         string                 `mapstructure:"sql"`
+	Params      []AnalyticsParamConfig `mapstructure:"params"`
+}
+
+// AnalyticsParamConfig represents a parameter of a query template. Parameters
+// without a default are required.
+type AnalyticsParamConfig struct {
+	Name        string `mapstructure:"name"`
+	Type        string `mapstructure:"type"` // one of AnalyticsParamTypes
+	Default     string `mapstructure:"default"`
+	Description string `mapstructure:"description"`
+}
+
+// Analytics query parameter types: times are RFC 3339 and bound in UTC
+const (
+	AnalyticsParamString = "string"
+	AnalyticsParamInt    = "int"
+	AnalyticsParamFloat  = "float"
+	AnalyticsParamBool   = "bool"
+	AnalyticsParamTime   = "time"
+)
+
+// AnalyticsParamTypes lists the supported parameter types
+var AnalyticsParamTypes = []string{AnalyticsParamString, AnalyticsParamInt, AnalyticsParamFloat, AnalyticsParamBool, AnalyticsParamTime}
+
+// AnalyticsChainParam is the parameter the requested chain is bound to
+const AnalyticsChainParam = "chain"
+
+var (
+	analyticsNamePattern        = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	analyticsPlaceholderPattern = regexp.MustCompile(`\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*:[^}]+\}`)
+	analyticsStatementPattern   = regexp.MustCompile(`(?i)^\s*(SELECT|WITH)\b`)
+)
+
+// validate checks that the template is a single SELECT or WITH statement
+// whose placeholders are exactly the chain and the declared parameters. The
+// chain is required, so that a query returns only the data of chains the
+// caller may access.
+func (q AnalyticsQueryConfig) validate() error {
+	sql := strings.TrimSuffix(strings.TrimSpace(q.SQL), ";")
+	if !analyticsStatementPattern.MatchString(sql) {
+		return fmt.Errorf("sql must be a SELECT or WITH statement")
+	}
+	if strings.Contains(sql, ";") {
+		return fmt.Errorf("sql must be a single statement")
+	}
+
+	declared := map[string]bool{AnalyticsChainParam: true}
+	for _, param := range q.Params {
+		if !analyticsNamePattern.MatchString(param.Name) || param.Name == AnalyticsChainParam {
+			return fmt.Errorf("invalid parameter name %q", param.Name)
+		}
+		if declared[param.Name] {
+			return fmt.Errorf("duplicate parameter %q", param.Name)
+		}
+		declared[param.Name] = true
+		if !slices.Contains(AnalyticsParamTypes, param.Type) {
+			return fmt.Errorf("parameter %q has unsupported type %q", param.Name, param.Type)
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, match := range analyticsPlaceholderPattern.FindAllStringSubmatch(sql, -1) {
+		if !declared[match[1]] {
+			return fmt.Errorf("sql refers to undeclared parameter %q", match[1])
+		}
+		used[match[1]] = true
+	}
+	if !used[AnalyticsChainParam] {
+		return fmt.Errorf("sql must refer to the chain as {%s:String}", AnalyticsChainParam)
+	}
+	for _, param := range q.Params {
+		if !used[param.Name] {
+			return fmt.Errorf("parameter %q is not used by the sql", param.Name)
+		}
+	}
+	return nil
+}
+
+// CoinGeckoConfig represents the CoinGecko API prices are read from. With an
+// API key the Pro API is used unless URL is set.
+type CoinGeckoConfig struct {
+	URL    string `mapstructure:"url"`
+	APIKey string `mapstructure:"api_key"`
+}
+
+// UnifiedConfig represents single-port server configuration. When enabled,
+// GraphQL, REST and metrics are served on Port under their path prefixes
+// instead of on their own ports.
+type UnifiedConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	Port    int  `mapstructure:"port"`
 }
 
 // GraphQLConfig represents GraphQL server configuration
 type GraphQLConfig struct {
-	Port       int  `mapstructure:"port"`
-	Playground bool `mapstructure:"playground"`
+	Port             int                    `mapstructure:"port"`
+	Playground       bool                   `mapstructure:"playground"`
+	PersistedQueries PersistedQueriesConfig `mapstructure:"persisted_queries"`
+}
+
+// PersistedQueriesConfig represents GraphQL persisted query configuration.
+// With APQ, clients may send the SHA-256 hash of a query in place of a query
+// the server has seen before. With an Allowlist manifest, only the queries it
+// lists are executed and clients cannot register new ones.
+type PersistedQueriesConfig struct {
+	APQ       bool   `mapstructure:"apq"`
+	CacheSize int    `mapstructure:"cache_size"`
+	Allowlist string `mapstructure:"allowlist"`
 }
 
 // RESTConfig represents REST server configuration
 type RESTConfig struct {
 	Port int `mapstructure:"port"`
+
+	// DefaultVersion is the API version serving /api/ paths without a version
+	// when the request's API-Version header names none
+	DefaultVersion string `mapstructure:"default_version"`
+
+	// V1Sunset is the date, as YYYY-MM-DD, after which /api/v1 may be
+	// removed, announced in the Sunset header of its responses. Empty
+	// announces none.
+	V1Sunset string `mapstructure:"v1_sunset"`
 }
 
 // MetricsConfig represents metrics server configuration
 type MetricsConfig struct {
 	Port int `mapstructure:"port"`
+
+	// Diagnostics serves the Go profiler under /debug/pprof/ and runtime
+	// statistics under /debug/runtime on the metrics server, and on the
+	// ingester's metrics port
+	Diagnostics bool `mapstructure:"diagnostics"`
 }
 
-// CORSConfig represents CORS configuration
+// CORSConfig represents CORS configuration. Origins may contain wildcards such
+// as "https://*.example.com", or be "*" to allow every origin.
 type CORSConfig struct {
-	Enabled bool     `mapstructure:"enabled"`
-	Origins []string `mapstructure:"origins"`
+	Enabled          bool          `mapstructure:"enabled"`
+	Origins          []string      `mapstructure:"origins"`
+	Methods          []string      `mapstructure:"methods"`
+	Headers          []string      `mapstructure:"headers"`
+	ExposedHeaders   []string      `mapstructure:"exposed_headers"`
+	AllowCredentials bool          `mapstructure:"allow_credentials"`
+	MaxAge           time.Duration `mapstructure:"max_age"` // how long browsers may cache preflight results
+}
+
+// CompressionConfig represents response compression configuration. Responses
+// smaller than MinSize bytes are sent uncompressed.
+type CompressionConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	MinSize int  `mapstructure:"min_size"`
+}
+
+// TLSConfig represents TLS termination configuration
+type TLSConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+}
+
+// TenancyConfig represents multi-tenant API configuration. When enabled, API
+// requests must carry a tenant API key and tenants are managed through the
+// admin API. The admin API is authenticated with AdminKey and is mounted
+// whenever AdminKey is set, so labels and logging can be managed without
+// tenancy.
+type TenancyConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	AdminKey string `mapstructure:"admin_key"`
+
+	// Roles restrict what the API keys assigned to them may access; keys
+	// without a role may access everything
+	Roles map[string]RoleConfig `mapstructure:"roles"`
+
+	Audit AuditConfig `mapstructure:"audit"`
+
+	Digests DigestConfig `mapstructure:"digests"`
+
+	UnbondingReminders ReminderConfig `mapstructure:"unbonding_reminders"`
+
+	CommissionAlerts CommissionAlertConfig `mapstructure:"commission_alerts"`
+
+	AlertRules AlertRuleConfig `mapstructure:"alert_rules"`
+
+	Email EmailConfig `mapstructure:"email"`
+}
+
+// EmailSMTP returns the SMTP server emails are sent through: the email one,
+// or the digests' one it replaces
+func (t TenancyConfig) EmailSMTP() SMTPConfig {
+	if t.Email.SMTP.Host != "" {
+		return t.Email.SMTP
+	}
+	return t.Digests.SMTP
+}
+
+// DigestConfig represents notification digest configuration. The API server
+// looks for due digests every CheckInterval and compiles each within Timeout,
+// listing at most MaxChanges changes of watched addresses. Email digests are
+// sent like other email notifications; SMTP is only read when the email SMTP
+// server is not set.
+type DigestConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	MaxChanges    int           `mapstructure:"max_changes"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+	SMTP          SMTPConfig    `mapstructure:"smtp"`
+}
+
+// ReminderConfig represents unbonding reminder configuration. Every
+// CheckInterval the API server notifies tenants' webhooks subscribed to
+// unbonding completions of the watched delegators' entries completing within
+// Before, delivering each within Timeout.
+type ReminderConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Before        time.Duration `mapstructure:"before"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+}
+
+// CommissionAlertConfig represents validator commission alert configuration.
+// Every CheckInterval the API server notifies tenants' webhooks subscribed to
+// commission increases of the increases detected within MaxAge of validators
+// their watched delegators delegate to, delivering each within Timeout.
+type CommissionAlertConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	MaxAge        time.Duration `mapstructure:"max_age"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+}
+
+// AlertRuleConfig represents alert rule evaluation configuration. The API
+// servers evaluate the enabled alert rules of every tenant against the events
+// they read from the stream, reloading the rules every RefreshInterval, and
+// deliver each alert within Timeout.
+type AlertRuleConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	Timeout         time.Duration `mapstructure:"timeout"`
+}
+
+// EmailConfig represents email notification configuration. With an SMTP
+// server, tenants' email subscriptions receive the events they subscribe to,
+// each rendered with the template of its event type: the built-in one unless
+// TemplatesDir holds an <event>.tmpl. A recipient receives at most RateLimit
+// emails every RateWindow, or any number with a RateLimit of 0. Emails of a
+// subscription link to UnsubscribeURL with its unsubscribe token.
+type EmailConfig struct {
+	SMTP           SMTPConfig    `mapstructure:"smtp"`
+	TemplatesDir   string        `mapstructure:"templates_dir"`
+	RateLimit      int           `mapstructure:"rate_limit"`
+	RateWindow     time.Duration `mapstructure:"rate_window"`
+	UnsubscribeURL string        `mapstructure:"unsubscribe_url"`
+}
+
+// SMTPConfig represents the SMTP server emails are sent through.
+// Authentication is skipped without a username.
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+// AuditConfig represents API audit logging configuration. Authenticated calls
+// are recorded in ClickHouse in batches of up to BatchSize, written at least
+// every FlushInterval; calls beyond BufferSize pending events are dropped.
+type AuditConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	BufferSize    int           `mapstructure:"buffer_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+// RoleConfig lists the chains, modules and endpoints a role may access. An
+// empty list allows everything of its kind.
+type RoleConfig struct {
+	Chains    []string `mapstructure:"chains"`
+	Modules   []string `mapstructure:"modules"`
+	Endpoints []string `mapstructure:"endpoints"`
 }
 
 // IngesterConfig represents ingester configuration
 type IngesterConfig struct {
 	BatchSize     int           `mapstructure:"batch_size"`
 	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	PollInterval  time.Duration `mapstructure:"poll_interval"`
 	Workers       int           `mapstructure:"workers"`
+
+	// ModuleConcurrency is how many modules of a chain are polled at once
+	ModuleConcurrency int `mapstructure:"module_concurrency"`
+
+	// ModuleTimeout bounds each poll of a module, including its chain and
+	// storage calls; retries get a new timeout. 0 disables it.
+	ModuleTimeout time.Duration `mapstructure:"module_timeout"`
+
+	// ModuleErrors is how failing modules are handled unless they set their
+	// own policy
+	ModuleErrors ModuleErrorsConfig `mapstructure:"module_errors"`
+
+	// DedupCacheSize is the number of row hashes remembered to skip rewriting
+	// unchanged snapshot rows; 0 disables deduplication
+	DedupCacheSize int `mapstructure:"dedup_cache_size"`
+
+	// ReconnectInterval is how long after a failed connection a chain that
+	// could not be reached at startup is retried, doubling after each failed
+	// attempt up to MaxReconnectInterval; 0 disables retries
+	ReconnectInterval    time.Duration `mapstructure:"reconnect_interval"`
+	MaxReconnectInterval time.Duration `mapstructure:"max_reconnect_interval"`
+
+	// MetricsPort serves the ingester's Prometheus metrics and, with
+	// api.metrics.diagnostics, its diagnostics; 0 disables it
+	MetricsPort int `mapstructure:"metrics_port"`
+
+	// HaltThreshold is how long a chain's latest height may stay the same
+	// before the chain is reported halted; 0 disables halt detection
+	HaltThreshold time.Duration `mapstructure:"halt_threshold"`
+}
+
+// VerifyConfig represents data consistency checker configuration
+type VerifyConfig struct {
+	SampleSize int     `mapstructure:"sample_size"`
+	Tolerance  float64 `mapstructure:"tolerance"`
+	Repair     bool    `mapstructure:"repair"`
+}
+
+// RetentionConfig represents pruning and retention configuration
+type RetentionConfig struct {
+	Enabled               bool          `mapstructure:"enabled"`
+	Interval              time.Duration `mapstructure:"interval"`
+	HistoryRetention      time.Duration `mapstructure:"history_retention"`
+	DustThreshold         string        `mapstructure:"dust_threshold"`
+	StaleAccountRetention time.Duration `mapstructure:"stale_account_retention"`
+	IngestRunRetention    time.Duration `mapstructure:"ingest_run_retention"` // how long ingest run reports are kept
+	BatchSize             int           `mapstructure:"batch_size"`
+}
+
+// Backpressure policies applied by the state listener when its queues are full
+const (
+	BackpressureDrop   = "drop"   // drop the change
+	BackpressureBlock  = "block"  // wait up to block_timeout for room, then drop
+	BackpressureSpill  = "spill"  // queue changes on disk and feed them back in order
+	BackpressureSample = "sample" // keep only sample_rate of changes while the queue is nearly full
+)
+
+// StateListenerConfig represents ADR-038 state listener configuration
+type StateListenerConfig struct {
+	BufferSize       int                `mapstructure:"buffer_size"`
+	WorkerBufferSize int                `mapstructure:"worker_buffer_size"`
+	Backpressure     BackpressureConfig `mapstructure:"backpressure"`
+	Priority         PriorityConfig     `mapstructure:"priority"`
+
+	// DedupCacheSize is the number of balances remembered per chain to skip
+	// writing and publishing unchanged values; 0 disables deduplication
+	DedupCacheSize int `mapstructure:"dedup_cache_size"`
+
+	// ProcessTimeout bounds the storage writes and publishing of each state
+	// change; 0 disables it
+	ProcessTimeout time.Duration `mapstructure:"process_timeout"`
+
+	// Decoders turn changes to stores without a module into generic key/value
+	// analytics instead of ignoring them
+	Decoders []DecoderConfig `mapstructure:"decoders"`
+
+	// Archive keeps every raw state change received in ClickHouse so that it
+	// can be decoded again by `state-mesh redecode`
+	Archive ArchiveConfig `mapstructure:"archive"`
+}
+
+// ArchiveConfig represents raw state change archive configuration. Changes
+// are written in batches; they are dropped when the buffer is full.
+type ArchiveConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	BufferSize    int           `mapstructure:"buffer_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+// DecoderConfig decodes the changes to one store. Each key is matched to the
+// rule with the longest prefix; keys no rule matches are recorded raw.
+type DecoderConfig struct {
+	Store       string        `mapstructure:"store"`
+	Descriptors string        `mapstructure:"descriptors"` // FileDescriptorSet with the rules' messages, from protoc --include_imports --descriptor_set_out
+	Rules       []DecoderRule `mapstructure:"rules"`
+}
+
+// DecoderRule maps a key prefix to an entity type and optionally the protobuf
+// message its values hold
+type DecoderRule struct {
+	Prefix  string `mapstructure:"prefix"` // raw bytes, or hex when starting with 0x
+	Entity  string `mapstructure:"entity"`
+	Message string `mapstructure:"message"` // fully qualified, e.g. "myorg.oracle.v1.Price"; empty records values as hex
+}
+
+// DecodePrefix returns the bytes a rule's prefix matches
+func (r DecoderRule) DecodePrefix() ([]byte, error) {
+	if hexPrefix, ok := strings.CutPrefix(r.Prefix, "0x"); ok {
+		return hex.DecodeString(hexPrefix)
+	}
+	return []byte(r.Prefix), nil
+}
+
+// PriorityConfig selects the state changes the listener processes ahead of
+// bulk changes. A change is prioritized when its store is listed, its key has
+// one of the prefixes, or a segment of its key is a watched address.
+type PriorityConfig struct {
+	BufferSize       int      `mapstructure:"buffer_size"`
+	Stores           []string `mapstructure:"stores"`
+	KeyPrefixes      []string `mapstructure:"key_prefixes"`
+	WatchedAddresses []string `mapstructure:"watched_addresses"`
+}
+
+// BackpressureConfig controls how the state listener handles full queues
+type BackpressureConfig struct {
+	Policy        string        `mapstructure:"policy"`
+	BlockTimeout  time.Duration `mapstructure:"block_timeout"`
+	SpillDir      string        `mapstructure:"spill_dir"`
+	SpillMaxBytes int64         `mapstructure:"spill_max_bytes"`
+	SampleRate    float64       `mapstructure:"sample_rate"`
 }
 
 // LogConfig represents logging configuration
 type LogConfig struct {
-	Level  string `mapstructure:"level"`
-	Format string `mapstructure:"format"`
+	Level    string            `mapstructure:"level"`
+	Format   string            `mapstructure:"format"`
+	Modules  map[string]string `mapstructure:"modules"` // level by logger name, e.g. state_listener: debug
+	File     LogFileConfig     `mapstructure:"file"`
+	Sampling LogSamplingConfig `mapstructure:"sampling"`
+}
+
+// LogFileConfig represents log file output and rotation configuration. Logs
+// are written to the file in addition to stderr.
+type LogFileConfig struct {
+	Path       string `mapstructure:"path"` // empty disables file output
+	MaxSizeMB  int    `mapstructure:"max_size_mb"`
+	MaxBackups int    `mapstructure:"max_backups"`
+	MaxAgeDays int    `mapstructure:"max_age_days"`
+	Compress   bool   `mapstructure:"compress"`
+}
+
+// LogSamplingConfig represents log sampling configuration. Within each tick,
+// the first Initial entries with the same logger and message are logged, then
+// every Thereafter-th one.
+type LogSamplingConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Level      string        `mapstructure:"level"` // entries at or below this level are sampled
+	Initial    int           `mapstructure:"initial"`
+	Thereafter int           `mapstructure:"thereafter"`
+	Tick       time.Duration `mapstructure:"tick"`
+}
+
+// TelemetryConfig represents error reporting and operational alerting
+// configuration
+type TelemetryConfig struct {
+	Sentry    SentryConfig   `mapstructure:"sentry"`
+	Incidents IncidentConfig `mapstructure:"incidents"`
+}
+
+// LifecycleConfig controls the startup and shutdown of the ingester and the
+// API server. On SIGTERM readiness fails first, for DrainDelay, so that load
+// balancers stop routing to the instance; then API servers stop accepting
+// requests and the ingester stops scheduling cycles, and in-flight requests,
+// ingest transactions and Kafka messages get up to DrainTimeout to finish.
+type LifecycleConfig struct {
+	DrainDelay   time.Duration `mapstructure:"drain_delay"`
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
+
+	// MigrationsTimeout is how long the ingester waits at startup for the
+	// database migrations to be applied before giving up
+	MigrationsTimeout time.Duration `mapstructure:"migrations_timeout"`
+}
+
+// SentryConfig represents Sentry error reporting configuration. Panics are
+// reported as they happen, ingestion failures once a chain or module fails
+// FailureThreshold times in a row.
+type SentryConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	DSN              string        `mapstructure:"dsn"`
+	Environment      string        `mapstructure:"environment"`
+	Release          string        `mapstructure:"release"` // defaults to state-mesh@ and the build version
+	SampleRate       float64       `mapstructure:"sample_rate"`
+	FailureThreshold int           `mapstructure:"failure_threshold"`
+	FlushTimeout     time.Duration `mapstructure:"flush_timeout"` // how long to wait for events to be sent on exit
+}
+
+// IncidentConfig represents operational alerting configuration, paging the
+// operators rather than tenants. Every CheckInterval the API server checks
+// its databases, the ingestion lag of every enabled chain against MaxChainLag
+// blocks and the backlog of the alert rule consumer group and KafkaGroups
+// against MaxKafkaBacklog events; a failing check opens an incident on every
+// configured integration and is resolved once it passes. Timeout bounds each
+// check and integration call. Source names the reporting deployment and
+// defaults to the host name. StateFile keeps the open incidents across
+// restarts, so that those open when the server stopped are still resolved;
+// empty disables it.
+type IncidentConfig struct {
+	Enabled         bool            `mapstructure:"enabled"`
+	CheckInterval   time.Duration   `mapstructure:"check_interval"`
+	Timeout         time.Duration   `mapstructure:"timeout"`
+	MaxChainLag     int64           `mapstructure:"max_chain_lag"`
+	MaxKafkaBacklog int64           `mapstructure:"max_kafka_backlog"`
+	KafkaGroups     []string        `mapstructure:"kafka_groups"`
+	Source          string          `mapstructure:"source"`
+	StateFile       string          `mapstructure:"state_file"`
+	PagerDuty       PagerDutyConfig `mapstructure:"pagerduty"`
+	Opsgenie        OpsgenieConfig  `mapstructure:"opsgenie"`
+}
+
+// PagerDutyConfig represents a PagerDuty Events API v2 integration, enabled
+// by its routing key
+type PagerDutyConfig struct {
+	RoutingKey string `mapstructure:"routing_key"`
+	URL        string `mapstructure:"url"`
+}
+
+// OpsgenieConfig represents an Opsgenie API integration, enabled by its API
+// key. URL is https://api.eu.opsgenie.com for EU accounts.
+type OpsgenieConfig struct {
+	APIKey string `mapstructure:"api_key"`
+	URL    string `mapstructure:"url"`
 }
 
 // Load loads configuration from file and environment variables
@@ -128,6 +1065,11 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
 	}
 
+	// Replace secret references with the secrets
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
@@ -148,15 +1090,117 @@ func (c *Config) Validate() error {
 		if len(chain.Modules) == 0 {
 			return fmt.Errorf("chain[%d]: at least one module must be specified", i)
 		}
+		if chain.Bech32Prefix != "" && !bech32PrefixPattern.MatchString(chain.Bech32Prefix) {
+			return fmt.Errorf("chain[%d]: invalid bech32_prefix %q", i, chain.Bech32Prefix)
+		}
+		if chain.BaseDenom != "" && !denomPattern.MatchString(chain.BaseDenom) {
+			return fmt.Errorf("chain[%d]: invalid base_denom %q", i, chain.BaseDenom)
+		}
+		if chain.DenomExponent < 0 || chain.DenomExponent > MaxDenomExponent {
+			return fmt.Errorf("chain[%d]: denom_exponent must be between 0 and %d", i, MaxDenomExponent)
+		}
+		if chain.Price.USD < 0 {
+			return fmt.Errorf("chain[%d]: price usd must not be negative", i)
+		}
+		if chain.Price.USD > 0 && chain.Price.CoinGeckoID != "" {
+			return fmt.Errorf("chain[%d]: price usd and coingecko_id cannot both be set", i)
+		}
+		if chain.HaltThreshold < 0 {
+			return fmt.Errorf("chain[%d]: halt_threshold must not be negative", i)
+		}
+		for j, module := range chain.Modules {
+			if module.Name == "" {
+				return fmt.Errorf("chain[%d].modules[%d]: name is required", i, j)
+			}
+			if module.Interval < 0 {
+				return fmt.Errorf("chain[%d].modules[%d]: interval must not be negative", i, j)
+			}
+			switch module.Schedule {
+			case "":
+			case ScheduleEpoch:
+				if chain.Epoch == "" {
+					return fmt.Errorf("chain[%d].modules[%d]: epoch schedule requires the chain's epoch", i, j)
+				}
+			default:
+				return fmt.Errorf("chain[%d].modules[%d]: unknown schedule %q", i, j, module.Schedule)
+			}
+			if err := module.Errors.validate(); err != nil {
+				return fmt.Errorf("chain[%d].modules[%d]: %w", i, j, err)
+			}
+		}
+	}
+
+	plugins := make(map[string]bool)
+	for i, plugin := range c.Plugins {
+		if plugin.Name == "" || plugin.Command == "" {
+			return fmt.Errorf("plugins[%d]: name and command are required", i)
+		}
+		if plugins[plugin.Name] {
+			return fmt.Errorf("plugins[%d]: plugin %q configured twice", i, plugin.Name)
+		}
+		if plugin.Timeout < 0 {
+			return fmt.Errorf("plugins[%d]: timeout must not be negative", i)
+		}
+		plugins[plugin.Name] = true
+	}
+
+	if c.Ingester.ModuleConcurrency < 0 {
+		return fmt.Errorf("ingester module_concurrency must not be negative")
+	}
+	if c.Ingester.ModuleTimeout < 0 {
+		return fmt.Errorf("ingester module_timeout must not be negative")
+	}
+	if c.Ingester.HaltThreshold < 0 {
+		return fmt.Errorf("ingester halt_threshold must not be negative")
+	}
+	if err := c.Ingester.ModuleErrors.validate(); err != nil {
+		return fmt.Errorf("ingester module_errors: %w", err)
+	}
+
+	// Validate chain reconnection
+	if c.Ingester.ReconnectInterval < 0 {
+		return fmt.Errorf("ingester reconnect_interval must not be negative")
+	}
+	if c.Ingester.ReconnectInterval > 0 && c.Ingester.MaxReconnectInterval < c.Ingester.ReconnectInterval {
+		return fmt.Errorf("ingester max_reconnect_interval must not be less than reconnect_interval")
 	}
 
 	// Validate database
+	switch c.Database.Driver {
+	case DriverPostgres, DriverCockroachDB, DriverMemory:
+	default:
+		return fmt.Errorf("unsupported database driver: %s", c.Database.Driver)
+	}
 	if c.Database.Postgres.Host == "" {
 		return fmt.Errorf("postgres host is required")
 	}
 	if c.Database.Postgres.Database == "" {
 		return fmt.Errorf("postgres database is required")
 	}
+	if c.Database.Postgres.SlowQueryThreshold < 0 || c.Database.ClickHouse.SlowQueryThreshold < 0 {
+		return fmt.Errorf("database slow_query_threshold must not be negative")
+	}
+	if writes := c.Database.ClickHouse.EventWrites; c.Database.ClickHouse.Enabled {
+		if writes.AsyncInsert.Enabled && writes.BufferTable.Enabled {
+			return fmt.Errorf("clickhouse event_writes async_insert and buffer_table cannot both be enabled")
+		}
+		if writes.AsyncInsert.Enabled && (writes.AsyncInsert.MaxDataSize <= 0 || writes.AsyncInsert.BusyTimeout < time.Millisecond) {
+			return fmt.Errorf("clickhouse event_writes async_insert max_data_size and busy_timeout must be positive")
+		}
+		if writes.AsyncInsert.Enabled && !writes.AsyncInsert.Wait && c.Database.ClickHouse.Spool.Dir != "" {
+			return fmt.Errorf("clickhouse event_writes async_insert wait must be enabled while the spool is enabled")
+		}
+		if buffer := writes.BufferTable; buffer.Enabled {
+			if buffer.Layers <= 0 || buffer.MinTime < time.Second || buffer.MaxTime < buffer.MinTime ||
+				buffer.MinRows <= 0 || buffer.MaxRows < buffer.MinRows ||
+				buffer.MinBytes <= 0 || buffer.MaxBytes < buffer.MinBytes {
+				return fmt.Errorf("clickhouse event_writes buffer_table thresholds must be positive, with each maximum at least its minimum")
+			}
+		}
+		if writes.Aggregate.Enabled && (writes.Aggregate.MaxKeys <= 0 || writes.Aggregate.FlushInterval <= 0) {
+			return fmt.Errorf("clickhouse event_writes aggregate max_keys and flush_interval must be positive")
+		}
+	}
 
 	// Validate API ports
 	if c.API.GraphQL.Port <= 0 || c.API.GraphQL.Port > 65535 {
@@ -165,10 +1209,191 @@ func (c *Config) Validate() error {
 	if c.API.REST.Port <= 0 || c.API.REST.Port > 65535 {
 		return fmt.Errorf("invalid REST port: %d", c.API.REST.Port)
 	}
+	if c.API.REST.DefaultVersion != "v1" && c.API.REST.DefaultVersion != "v2" {
+		return fmt.Errorf("api rest default_version must be v1 or v2")
+	}
+	if sunset := c.API.REST.V1Sunset; sunset != "" {
+		if _, err := time.Parse(time.DateOnly, sunset); err != nil {
+			return fmt.Errorf("api rest v1_sunset must be a date as YYYY-MM-DD: %w", err)
+		}
+	}
+	if c.API.GraphQL.PersistedQueries.APQ && c.API.GraphQL.PersistedQueries.CacheSize <= 0 {
+		return fmt.Errorf("api graphql persisted query cache_size must be positive")
+	}
+	if c.API.Unified.Enabled && (c.API.Unified.Port <= 0 || c.API.Unified.Port > 65535) {
+		return fmt.Errorf("invalid unified server port: %d", c.API.Unified.Port)
+	}
+	for _, origin := range c.API.CORS.Origins {
+		if _, err := path.Match(origin, ""); err != nil {
+			return fmt.Errorf("invalid CORS origin %q: %w", origin, err)
+		}
+	}
+	if c.API.CORS.MaxAge < 0 {
+		return fmt.Errorf("api cors max_age must not be negative")
+	}
+	if c.API.TLS.Enabled && (c.API.TLS.CertFile == "" || c.API.TLS.KeyFile == "") {
+		return fmt.Errorf("api tls cert_file and key_file are required when TLS is enabled")
+	}
+	if c.API.Compression.MinSize < 0 {
+		return fmt.Errorf("api compression min_size must not be negative")
+	}
+	if c.API.RequestTimeout < 0 {
+		return fmt.Errorf("api request_timeout must not be negative")
+	}
+	if overview := c.API.Overview; overview.StaleAfter <= 0 || overview.MaxLag <= 0 || overview.CacheTTL < 0 || overview.PriceRefresh <= 0 {
+		return fmt.Errorf("api overview stale_after, max_lag and price_refresh must be positive and cache_ttl not negative")
+	}
+	if c.API.GasPrices.Window <= 0 {
+		return fmt.Errorf("api gas_prices window must be positive")
+	}
+	if anomalies := c.API.Anomalies; anomalies.Enabled {
+		if !c.Database.ClickHouse.Enabled {
+			return fmt.Errorf("api anomaly detection requires ClickHouse to be enabled")
+		}
+		if anomalies.CheckInterval <= 0 || anomalies.Window <= 0 || anomalies.Threshold <= 0 ||
+			anomalies.MinSamples <= 0 || anomalies.Timeout <= 0 {
+			return fmt.Errorf("api anomalies check_interval, window, threshold, min_samples and timeout must be positive")
+		}
+		if anomalies.Alpha <= 0 || anomalies.Alpha > 1 {
+			return fmt.Errorf("api anomalies alpha must be greater than 0 and at most 1")
+		}
+		if hours := int(anomalies.Window / time.Hour); hours <= anomalies.MinSamples {
+			return fmt.Errorf("api anomalies window must span more than min_samples hours")
+		}
+	}
+	if analytics := c.API.Analytics; analytics.Enabled {
+		if !c.Database.ClickHouse.Enabled {
+			return fmt.Errorf("api analytics queries require ClickHouse to be enabled")
+		}
+		if analytics.Timeout <= 0 || analytics.MaxRows <= 0 {
+			return fmt.Errorf("api analytics timeout and max_rows must be positive")
+		}
+		for name, query := range analytics.Queries {
+			if !analyticsNamePattern.MatchString(name) {
+				return fmt.Errorf("api analytics query %q: invalid name", name)
+			}
+			if err := query.validate(); err != nil {
+				return fmt.Errorf("api analytics query %q: %w", name, err)
+			}
+		}
+	}
 	if c.API.Metrics.Port <= 0 || c.API.Metrics.Port > 65535 {
 		return fmt.Errorf("invalid metrics port: %d", c.API.Metrics.Port)
 	}
 
+	if c.API.Tenancy.Enabled && c.API.Tenancy.AdminKey == "" {
+		return fmt.Errorf("api tenancy admin_key is required when tenancy is enabled")
+	}
+	if audit := c.API.Tenancy.Audit; audit.Enabled {
+		if !c.API.Tenancy.Enabled || !c.Database.ClickHouse.Enabled {
+			return fmt.Errorf("api audit logging requires tenancy and ClickHouse to be enabled")
+		}
+		if audit.BatchSize <= 0 || audit.BufferSize <= 0 || audit.FlushInterval <= 0 {
+			return fmt.Errorf("api audit batch_size, buffer_size and flush_interval must be positive")
+		}
+	}
+	if digests := c.API.Tenancy.Digests; digests.Enabled {
+		if !c.API.Tenancy.Enabled {
+			return fmt.Errorf("api digests require tenancy to be enabled")
+		}
+		if digests.CheckInterval <= 0 || digests.MaxChanges <= 0 || digests.Timeout <= 0 {
+			return fmt.Errorf("api digests check_interval, max_changes and timeout must be positive")
+		}
+		if smtp := digests.SMTP; smtp.Host != "" && (smtp.From == "" || smtp.Port <= 0 || smtp.Port > 65535) {
+			return fmt.Errorf("api digests smtp requires from and a valid port")
+		}
+	}
+	if email := c.API.Tenancy.Email; email.SMTP.Host != "" {
+		if email.SMTP.From == "" || email.SMTP.Port <= 0 || email.SMTP.Port > 65535 {
+			return fmt.Errorf("api email smtp requires from and a valid port")
+		}
+		if email.RateLimit < 0 || (email.RateLimit > 0 && email.RateWindow <= 0) {
+			return fmt.Errorf("api email rate_limit must not be negative and rate_window must be positive")
+		}
+		if email.UnsubscribeURL != "" {
+			if u, err := url.Parse(email.UnsubscribeURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("api email unsubscribe_url must be an absolute http or https URL")
+			}
+		}
+	}
+	if reminders := c.API.Tenancy.UnbondingReminders; reminders.Enabled {
+		if !c.API.Tenancy.Enabled {
+			return fmt.Errorf("api unbonding reminders require tenancy to be enabled")
+		}
+		if reminders.Before <= 0 || reminders.CheckInterval <= 0 || reminders.Timeout <= 0 {
+			return fmt.Errorf("api unbonding reminders before, check_interval and timeout must be positive")
+		}
+	}
+	if alerts := c.API.Tenancy.CommissionAlerts; alerts.Enabled {
+		if !c.API.Tenancy.Enabled {
+			return fmt.Errorf("api commission alerts require tenancy to be enabled")
+		}
+		if alerts.CheckInterval <= 0 || alerts.MaxAge <= 0 || alerts.Timeout <= 0 {
+			return fmt.Errorf("api commission alerts check_interval, max_age and timeout must be positive")
+		}
+	}
+	if rules := c.API.Tenancy.AlertRules; rules.Enabled {
+		if !c.API.Tenancy.Enabled || !c.Streaming.Enabled {
+			return fmt.Errorf("api alert rules require tenancy and streaming to be enabled")
+		}
+		if rules.RefreshInterval <= 0 || rules.Timeout <= 0 {
+			return fmt.Errorf("api alert rules refresh_interval and timeout must be positive")
+		}
+	}
+
+	// Validate state listener
+	if c.StateListener.BufferSize <= 0 || c.StateListener.WorkerBufferSize <= 0 || c.StateListener.Priority.BufferSize <= 0 {
+		return fmt.Errorf("state listener buffer sizes must be positive")
+	}
+	if c.StateListener.ProcessTimeout < 0 {
+		return fmt.Errorf("state listener process_timeout must not be negative")
+	}
+	switch c.StateListener.Backpressure.Policy {
+	case BackpressureDrop, BackpressureBlock:
+	case BackpressureSpill:
+		if c.StateListener.Backpressure.SpillDir == "" {
+			return fmt.Errorf("state listener spill_dir is required for the spill backpressure policy")
+		}
+	case BackpressureSample:
+		if rate := c.StateListener.Backpressure.SampleRate; rate < 0 || rate > 1 {
+			return fmt.Errorf("state listener sample_rate must be between 0 and 1")
+		}
+	default:
+		return fmt.Errorf("unsupported state listener backpressure policy: %s", c.StateListener.Backpressure.Policy)
+	}
+
+	decoders := make(map[string]bool)
+	for i, decoder := range c.StateListener.Decoders {
+		if decoder.Store == "" {
+			return fmt.Errorf("state listener decoders[%d]: store is required", i)
+		}
+		store := CanonicalModuleName(decoder.Store)
+		if decoders[store] {
+			return fmt.Errorf("state listener decoders[%d]: store %q configured twice", i, decoder.Store)
+		}
+		decoders[store] = true
+		for j, rule := range decoder.Rules {
+			if rule.Entity == "" {
+				return fmt.Errorf("state listener decoders[%d].rules[%d]: entity is required", i, j)
+			}
+			if _, err := rule.DecodePrefix(); err != nil {
+				return fmt.Errorf("state listener decoders[%d].rules[%d]: invalid prefix: %w", i, j, err)
+			}
+			if rule.Message != "" && decoder.Descriptors == "" {
+				return fmt.Errorf("state listener decoders[%d]: descriptors are required to decode messages", i)
+			}
+		}
+	}
+
+	if archive := c.StateListener.Archive; archive.Enabled {
+		if !c.Database.ClickHouse.Enabled {
+			return fmt.Errorf("the state change archive requires ClickHouse to be enabled")
+		}
+		if archive.BatchSize <= 0 || archive.BufferSize <= 0 || archive.FlushInterval <= 0 {
+			return fmt.Errorf("state listener archive batch_size, buffer_size and flush_interval must be positive")
+		}
+	}
+
 	// Validate streaming if enabled
 	if c.Streaming.Enabled {
 		if len(c.Streaming.Kafka.Brokers) == 0 {
@@ -177,6 +1402,58 @@ func (c *Config) Validate() error {
 		if c.Streaming.Kafka.Topic == "" {
 			return fmt.Errorf("kafka topic is required when streaming is enabled")
 		}
+		switch c.Streaming.Kafka.SecurityProtocol {
+		case "", "plaintext", "ssl", "sasl_plaintext", "sasl_ssl":
+		default:
+			return fmt.Errorf("unknown kafka security_protocol %q", c.Streaming.Kafka.SecurityProtocol)
+		}
+		switch c.Streaming.Kafka.SASL.Mechanism {
+		case "", "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512":
+		default:
+			return fmt.Errorf("unknown kafka sasl mechanism %q", c.Streaming.Kafka.SASL.Mechanism)
+		}
+	}
+	if c.Streaming.Outbox.Enabled {
+		if !c.Streaming.Enabled {
+			return fmt.Errorf("streaming must be enabled to use the outbox")
+		}
+		if c.Streaming.Outbox.BatchSize <= 0 {
+			return fmt.Errorf("streaming outbox batch_size must be positive")
+		}
+		if c.Streaming.Outbox.PollInterval <= 0 {
+			return fmt.Errorf("streaming outbox poll_interval must be positive")
+		}
+	}
+
+	if c.Lifecycle.DrainDelay < 0 {
+		return fmt.Errorf("lifecycle drain_delay must not be negative")
+	}
+	if c.Lifecycle.DrainTimeout <= 0 || c.Lifecycle.MigrationsTimeout <= 0 {
+		return fmt.Errorf("lifecycle drain_timeout and migrations_timeout must be positive")
+	}
+
+	if c.Secrets.RefreshInterval <= 0 || c.Secrets.Timeout <= 0 {
+		return fmt.Errorf("secrets refresh_interval and timeout must be positive")
+	}
+
+	if incidents := c.Telemetry.Incidents; incidents.Enabled {
+		if incidents.PagerDuty.RoutingKey == "" && incidents.Opsgenie.APIKey == "" {
+			return fmt.Errorf("telemetry incidents require a pagerduty routing_key or an opsgenie api_key")
+		}
+		if incidents.CheckInterval <= 0 || incidents.Timeout <= 0 {
+			return fmt.Errorf("telemetry incidents check_interval and timeout must be positive")
+		}
+		if incidents.MaxChainLag <= 0 || incidents.MaxKafkaBacklog <= 0 {
+			return fmt.Errorf("telemetry incidents max_chain_lag and max_kafka_backlog must be positive")
+		}
+		if len(incidents.KafkaGroups) > 0 && !c.Streaming.Enabled {
+			return fmt.Errorf("telemetry incidents kafka_groups require streaming to be enabled")
+		}
+		for _, endpoint := range []string{incidents.PagerDuty.URL, incidents.Opsgenie.URL} {
+			if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("telemetry incidents pagerduty and opsgenie url must be absolute http or https URLs")
+			}
+		}
 	}
 
 	return nil
@@ -219,12 +1496,21 @@ func setDefaults() {
 			ChainID:      "cosmoshub-4",
 			GRPCEndpoint: "localhost:9090",
 			RESTEndpoint: "localhost:1317",
-			Modules:      []string{"bank", "staking", "distribution", "gov"},
+			Bech32Prefix: "cosmos",
+			Modules: []ModuleConfig{
+				{Name: "bank", Enabled: true},
+				{Name: "staking", Enabled: true},
+				{Name: "distribution", Enabled: true},
+				{Name: "gov", Enabled: true},
+			},
 			Enabled:      true,
+			BaseDenom:     "uatom",
+			DenomExponent: 6,
 		},
 	})
 
 	// Database defaults
+	viper.SetDefault("database.driver", DriverPostgres)
 	viper.SetDefault("database.postgres.host", "localhost")
 	viper.SetDefault("database.postgres.port", 5432)
 	viper.SetDefault("database.postgres.database", "statemesh")
@@ -233,6 +1519,11 @@ func setDefaults() {
 	viper.SetDefault("database.postgres.ssl_mode", "disable")
 	viper.SetDefault("database.postgres.max_conns", 20)
 	viper.SetDefault("database.postgres.min_conns", 5)
+	viper.SetDefault("database.postgres.slow_query_threshold", "500ms")
+
+	viper.SetDefault("database.wal.dir", "data/wal")
+	viper.SetDefault("database.wal.max_bytes", 1<<30)
+	viper.SetDefault("database.wal.reconnect_interval", "5s")
 
 	viper.SetDefault("database.clickhouse.host", "localhost")
 	viper.SetDefault("database.clickhouse.port", 9000)
@@ -240,26 +1531,202 @@ func setDefaults() {
 	viper.SetDefault("database.clickhouse.user", "default")
 	viper.SetDefault("database.clickhouse.password", "")
 	viper.SetDefault("database.clickhouse.enabled", true)
+	viper.SetDefault("database.clickhouse.reconnect_interval", "10s")
+	viper.SetDefault("database.clickhouse.slow_query_threshold", "2s")
+	viper.SetDefault("database.clickhouse.spool.dir", "data/clickhouse-spool")
+	viper.SetDefault("database.clickhouse.spool.max_bytes", 256<<20)
+	viper.SetDefault("database.clickhouse.event_writes.async_insert.enabled", false)
+	viper.SetDefault("database.clickhouse.event_writes.async_insert.wait", true)
+	viper.SetDefault("database.clickhouse.event_writes.async_insert.max_data_size", 10<<20)
+	viper.SetDefault("database.clickhouse.event_writes.async_insert.busy_timeout", "200ms")
+	viper.SetDefault("database.clickhouse.event_writes.buffer_table.enabled", false)
+	viper.SetDefault("database.clickhouse.event_writes.buffer_table.layers", 16)
+	viper.SetDefault("database.clickhouse.event_writes.buffer_table.min_time", "10s")
+	viper.SetDefault("database.clickhouse.event_writes.buffer_table.max_time", "100s")
+	viper.SetDefault("database.clickhouse.event_writes.buffer_table.min_rows", 10000)
+	viper.SetDefault("database.clickhouse.event_writes.buffer_table.max_rows", 1000000)
+	viper.SetDefault("database.clickhouse.event_writes.buffer_table.min_bytes", 10<<20)
+	viper.SetDefault("database.clickhouse.event_writes.buffer_table.max_bytes", 100<<20)
+	viper.SetDefault("database.clickhouse.event_writes.aggregate.enabled", false)
+	viper.SetDefault("database.clickhouse.event_writes.aggregate.max_keys", 10000)
+	viper.SetDefault("database.clickhouse.event_writes.aggregate.flush_interval", "1s")
 
 	// Streaming defaults
 	viper.SetDefault("streaming.enabled", false)
 	viper.SetDefault("streaming.kafka.brokers", []string{"localhost:9092"})
 	viper.SetDefault("streaming.kafka.topic", "cosmos-state-changes")
+	viper.SetDefault("streaming.outbox.enabled", false)
+	viper.SetDefault("streaming.outbox.batch_size", 500)
+	viper.SetDefault("streaming.outbox.poll_interval", "500ms")
+	viper.SetDefault("streaming.outbox.retention", "24h")
 
 	// API defaults
 	viper.SetDefault("api.graphql.port", 8080)
 	viper.SetDefault("api.graphql.playground", true)
+	viper.SetDefault("api.graphql.persisted_queries.apq", true)
+	viper.SetDefault("api.graphql.persisted_queries.cache_size", 1000)
+	viper.SetDefault("api.graphql.persisted_queries.allowlist", "")
 	viper.SetDefault("api.rest.port", 8081)
+	viper.SetDefault("api.rest.default_version", "v2")
+	viper.SetDefault("api.rest.v1_sunset", "")
 	viper.SetDefault("api.metrics.port", 9090)
+	viper.SetDefault("api.metrics.diagnostics", false)
 	viper.SetDefault("api.cors.enabled", true)
 	viper.SetDefault("api.cors.origins", []string{"*"})
+	viper.SetDefault("api.cors.methods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
+	viper.SetDefault("api.cors.headers", []string{"Content-Type", "Authorization", "X-API-Key", "X-Request-ID"})
+	viper.SetDefault("api.cors.exposed_headers", []string{"X-Request-ID"})
+	viper.SetDefault("api.cors.allow_credentials", false)
+	viper.SetDefault("api.cors.max_age", "10m")
+	viper.SetDefault("api.compression.enabled", true)
+	viper.SetDefault("api.compression.min_size", 1024)
+	viper.SetDefault("api.tls.enabled", false)
+	viper.SetDefault("api.http2", true)
+	viper.SetDefault("api.unified.enabled", false)
+	viper.SetDefault("api.unified.port", 8000)
+	viper.SetDefault("api.tenancy.enabled", false)
+	viper.SetDefault("api.tenancy.audit.enabled", false)
+	viper.SetDefault("api.tenancy.audit.batch_size", 500)
+	viper.SetDefault("api.tenancy.audit.buffer_size", 10000)
+	viper.SetDefault("api.tenancy.audit.flush_interval", "5s")
+	viper.SetDefault("api.tenancy.digests.enabled", false)
+	viper.SetDefault("api.tenancy.digests.check_interval", "5m")
+	viper.SetDefault("api.tenancy.digests.max_changes", 200)
+	viper.SetDefault("api.tenancy.digests.timeout", "1m")
+	viper.SetDefault("api.tenancy.digests.smtp.host", "")
+	viper.SetDefault("api.tenancy.digests.smtp.port", 587)
+	viper.SetDefault("api.tenancy.digests.smtp.username", "")
+	viper.SetDefault("api.tenancy.digests.smtp.password", "")
+	viper.SetDefault("api.tenancy.digests.smtp.from", "")
+	viper.SetDefault("api.tenancy.email.smtp.host", "")
+	viper.SetDefault("api.tenancy.email.smtp.port", 587)
+	viper.SetDefault("api.tenancy.email.smtp.username", "")
+	viper.SetDefault("api.tenancy.email.smtp.password", "")
+	viper.SetDefault("api.tenancy.email.smtp.from", "")
+	viper.SetDefault("api.tenancy.email.templates_dir", "")
+	viper.SetDefault("api.tenancy.email.rate_limit", 20)
+	viper.SetDefault("api.tenancy.email.rate_window", "1h")
+	viper.SetDefault("api.tenancy.email.unsubscribe_url", "")
+	viper.SetDefault("api.tenancy.unbonding_reminders.enabled", false)
+	viper.SetDefault("api.tenancy.unbonding_reminders.before", "24h")
+	viper.SetDefault("api.tenancy.unbonding_reminders.check_interval", "5m")
+	viper.SetDefault("api.tenancy.unbonding_reminders.timeout", "30s")
+	viper.SetDefault("api.tenancy.commission_alerts.enabled", false)
+	viper.SetDefault("api.tenancy.commission_alerts.check_interval", "1m")
+	viper.SetDefault("api.tenancy.commission_alerts.max_age", "24h")
+	viper.SetDefault("api.tenancy.commission_alerts.timeout", "30s")
+	viper.SetDefault("api.tenancy.alert_rules.enabled", false)
+	viper.SetDefault("api.tenancy.alert_rules.refresh_interval", "1m")
+	viper.SetDefault("api.tenancy.alert_rules.timeout", "30s")
+	viper.SetDefault("api.chain_timeout", "5s")
+	viper.SetDefault("api.request_timeout", "30s")
+	viper.SetDefault("api.overview.stale_after", "5m")
+	viper.SetDefault("api.overview.max_lag", 100)
+	viper.SetDefault("api.overview.cache_ttl", "30s")
+	viper.SetDefault("api.overview.price_refresh", "5m")
+	viper.SetDefault("api.overview.coingecko.url", "")
+	viper.SetDefault("api.overview.coingecko.api_key", "")
+	viper.SetDefault("api.gas_prices.window", "1h")
+	viper.SetDefault("api.analytics.enabled", false)
+	viper.SetDefault("api.analytics.timeout", "30s")
+	viper.SetDefault("api.analytics.max_rows", 10000)
+	viper.SetDefault("api.anomalies.enabled", false)
+	viper.SetDefault("api.anomalies.check_interval", "15m")
+	viper.SetDefault("api.anomalies.window", "168h")
+	viper.SetDefault("api.anomalies.alpha", 0.1)
+	viper.SetDefault("api.anomalies.threshold", 4)
+	viper.SetDefault("api.anomalies.min_samples", 24)
+	viper.SetDefault("api.anomalies.timeout", "30s")
 
 	// Ingester defaults
 	viper.SetDefault("ingester.batch_size", 1000)
 	viper.SetDefault("ingester.flush_interval", "5s")
+	viper.SetDefault("ingester.poll_interval", "10s")
 	viper.SetDefault("ingester.workers", 4)
+	viper.SetDefault("ingester.module_concurrency", 4)
+	viper.SetDefault("ingester.module_timeout", "2m")
+	viper.SetDefault("ingester.module_errors.policy", ModuleErrorSkip)
+	viper.SetDefault("ingester.module_errors.retries", 3)
+	viper.SetDefault("ingester.module_errors.retry_backoff", "1s")
+	viper.SetDefault("ingester.module_errors.disable_after", 10)
+	viper.SetDefault("ingester.module_errors.disable_for", "30m")
+	viper.SetDefault("ingester.dedup_cache_size", 100000)
+	viper.SetDefault("ingester.reconnect_interval", "10s")
+	viper.SetDefault("ingester.max_reconnect_interval", "5m")
+	viper.SetDefault("ingester.metrics_port", 0)
+	viper.SetDefault("ingester.halt_threshold", "5m")
+
+	// Verify defaults
+	viper.SetDefault("verify.sample_size", 100)
+	viper.SetDefault("verify.tolerance", 0.0)
+	viper.SetDefault("verify.repair", false)
+
+	// Retention defaults
+	viper.SetDefault("retention.enabled", false)
+	viper.SetDefault("retention.interval", "1h")
+	viper.SetDefault("retention.history_retention", "2160h") // 90 days
+	viper.SetDefault("retention.dust_threshold", "1000")
+	viper.SetDefault("retention.stale_account_retention", "4320h") // 180 days
+	viper.SetDefault("retention.ingest_run_retention", "720h")     // 30 days
+	viper.SetDefault("retention.batch_size", 10000)
+
+	// State listener defaults
+	viper.SetDefault("state_listener.buffer_size", 10000)
+	viper.SetDefault("state_listener.worker_buffer_size", 1000)
+	viper.SetDefault("state_listener.backpressure.policy", BackpressureDrop)
+	viper.SetDefault("state_listener.backpressure.block_timeout", "1s")
+	viper.SetDefault("state_listener.backpressure.spill_dir", "data/listener-spill")
+	viper.SetDefault("state_listener.backpressure.spill_max_bytes", 1<<30)
+	viper.SetDefault("state_listener.backpressure.sample_rate", 0.1)
+	viper.SetDefault("state_listener.dedup_cache_size", 1000000)
+	viper.SetDefault("state_listener.process_timeout", "10s")
+	viper.SetDefault("state_listener.priority.buffer_size", 1000)
+	viper.SetDefault("state_listener.priority.stores", []string{"gov", "slashing"})
+	viper.SetDefault("state_listener.priority.key_prefixes", []string{"validators/"})
+	viper.SetDefault("state_listener.priority.watched_addresses", []string{})
+	viper.SetDefault("state_listener.archive.enabled", false)
+	viper.SetDefault("state_listener.archive.batch_size", 5000)
+	viper.SetDefault("state_listener.archive.buffer_size", 100000)
+	viper.SetDefault("state_listener.archive.flush_interval", "1s")
+
+	// Telemetry defaults
+	viper.SetDefault("telemetry.sentry.enabled", false)
+	viper.SetDefault("telemetry.sentry.environment", "production")
+	viper.SetDefault("telemetry.sentry.sample_rate", 1.0)
+	viper.SetDefault("telemetry.sentry.failure_threshold", 5)
+	viper.SetDefault("telemetry.sentry.flush_timeout", "2s")
+	viper.SetDefault("telemetry.incidents.enabled", false)
+	viper.SetDefault("telemetry.incidents.check_interval", "1m")
+	viper.SetDefault("telemetry.incidents.timeout", "10s")
+	viper.SetDefault("telemetry.incidents.max_chain_lag", 500)
+	viper.SetDefault("telemetry.incidents.max_kafka_backlog", 10000)
+	viper.SetDefault("telemetry.incidents.kafka_groups", []string{})
+	viper.SetDefault("telemetry.incidents.source", "")
+	viper.SetDefault("telemetry.incidents.state_file", "data/incidents.json")
+	viper.SetDefault("telemetry.incidents.pagerduty.routing_key", "")
+	viper.SetDefault("telemetry.incidents.pagerduty.url", "https://events.pagerduty.com/v2/enqueue")
+	viper.SetDefault("telemetry.incidents.opsgenie.api_key", "")
+	viper.SetDefault("telemetry.incidents.opsgenie.url", "https://api.opsgenie.com")
+
+	// Lifecycle defaults
+	viper.SetDefault("lifecycle.drain_delay", "5s")
+	viper.SetDefault("lifecycle.drain_timeout", "30s")
+	viper.SetDefault("lifecycle.migrations_timeout", "5m")
+
+	// Secrets defaults
+	viper.SetDefault("secrets.refresh_interval", "5m")
+	viper.SetDefault("secrets.timeout", "10s")
 
 	// Log defaults
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.format", "console")
+	viper.SetDefault("log.file.max_size_mb", 100)
+	viper.SetDefault("log.file.max_backups", 5)
+	viper.SetDefault("log.file.max_age_days", 28)
+	viper.SetDefault("log.file.compress", true)
+	viper.SetDefault("log.sampling.enabled", false)
+	viper.SetDefault("log.sampling.level", "debug")
+	viper.SetDefault("log.sampling.initial", 100)
+	viper.SetDefault("log.sampling.thereafter", 100)
+	viper.SetDefault("log.sampling.tick", "1s")
 }
@@ -0,0 +1,361 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Secret providers
+const (
+	SecretProviderEnv   = "env"
+	SecretProviderFile  = "file"
+	SecretProviderVault = "vault"
+	SecretProviderAWS   = "aws"
+)
+
+// SecretsConfig configures the providers that secret references in config
+// values are read from. A reference has the form ${provider:path#key}, e.g.
+// ${vault:secret/data/statemesh/postgres#password}, and can make up a whole
+// value or part of one:
+//   - env reads an environment variable or a variable of the env files
+//   - file reads a file, e.g. a mounted Kubernetes or Docker secret
+//   - vault reads a field of a HashiCorp Vault secret, from a KV (v1 or v2)
+//     or a dynamic secrets engine such as database/creds/<role>
+//   - aws reads AWS Secrets Manager, the whole secret string or, with #key,
+//     a field of a JSON secret
+type SecretsConfig struct {
+	// EnvFiles are read in order for env references; variables set in the
+	// environment take precedence over them
+	EnvFiles []string `mapstructure:"env_files"`
+
+	// RefreshInterval is how long a secret read from Vault or AWS is reused
+	// before rotating credentials, such as PostgreSQL's, are read again
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+
+	// Timeout bounds each request to Vault or AWS
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	Vault VaultConfig      `mapstructure:"vault"`
+	AWS   AWSSecretsConfig `mapstructure:"aws"`
+}
+
+// VaultConfig represents HashiCorp Vault configuration. The address and token
+// default to VAULT_ADDR and VAULT_TOKEN.
+type VaultConfig struct {
+	Address   string `mapstructure:"address"`
+	Token     string `mapstructure:"token"`
+	TokenFile string `mapstructure:"token_file"`
+	Namespace string `mapstructure:"namespace"`
+}
+
+// AWSSecretsConfig represents AWS Secrets Manager configuration. Credentials
+// are only read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN, not from the rest of the AWS SDK credential chain; the
+// region defaults to AWS_REGION. Loading a configuration that references an
+// aws secret fails when they are not set.
+type AWSSecretsConfig struct {
+	Region   string `mapstructure:"region"`
+	Endpoint string `mapstructure:"endpoint"` // defaults to the regional Secrets Manager endpoint
+}
+
+// secretReference matches a reference to a secret in a config value
+var secretReference = regexp.MustCompile(`\$\{([a-z]+):([^}#]+)(?:#([^}]+))?\}`)
+
+// hasSecretReference reports whether a config value references a secret
+func hasSecretReference(value string) bool {
+	return secretReference.MatchString(value)
+}
+
+// sameSecret reports whether two values are references to the same secret,
+// e.g. to two fields of it
+func sameSecret(a, b string) bool {
+	ma, mb := secretReference.FindStringSubmatch(a), secretReference.FindStringSubmatch(b)
+	return ma != nil && mb != nil && ma[1] == mb[1] && ma[2] == mb[2]
+}
+
+// secret is a secret read from a provider: a single value, a set of fields
+// or both. Lease is how long the secret is valid for, if the provider says.
+type secret struct {
+	value  string
+	fields map[string]string
+	lease  time.Duration
+}
+
+// lookup returns the value of a secret, or of one of its fields
+func (s secret) lookup(key string) (string, error) {
+	if key == "" {
+		if s.fields != nil && s.value == "" {
+			return "", fmt.Errorf("secret has fields, reference one with #key")
+		}
+		return s.value, nil
+	}
+
+	fields := s.fields
+	if fields == nil {
+		if err := json.Unmarshal([]byte(s.value), &fields); err != nil {
+			return "", fmt.Errorf("secret is not a JSON object, cannot read field %s", key)
+		}
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %s", key)
+	}
+	return value, nil
+}
+
+// secretProvider reads secrets by path
+type secretProvider interface {
+	read(ctx context.Context, path string) (secret, error)
+}
+
+// cachedSecret is a secret read by the resolver and when to read it again
+type cachedSecret struct {
+	secret  secret
+	expires time.Time
+}
+
+// secretResolver replaces secret references in config values with the
+// secrets they reference, reading each secret once per refresh interval
+type secretResolver struct {
+	refresh   time.Duration
+	providers map[string]secretProvider
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+// newSecretResolver creates a resolver for the configured providers. The
+// secrets configuration itself may only reference env and file secrets.
+func newSecretResolver(cfg SecretsConfig) (*secretResolver, error) {
+	env, err := newEnvProvider(cfg.EnvFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &secretResolver{
+		refresh: cfg.RefreshInterval,
+		providers: map[string]secretProvider{
+			SecretProviderEnv:  env,
+			SecretProviderFile: fileProvider{},
+		},
+		cache: make(map[string]cachedSecret),
+	}
+	if err := r.resolveStruct(context.Background(), reflect.ValueOf(&cfg).Elem()); err != nil {
+		return nil, fmt.Errorf("secrets: %w", err)
+	}
+
+	client := &http.Client{Timeout: cfg.Timeout}
+	r.providers[SecretProviderVault] = newVaultProvider(cfg.Vault, client)
+	r.providers[SecretProviderAWS] = newAWSProvider(cfg.AWS, client)
+	return r, nil
+}
+
+// resolve replaces the secret references in value. With refresh, cached
+// secrets are read again, e.g. after they failed to authenticate.
+func (r *secretResolver) resolve(ctx context.Context, value string, refresh bool) (string, error) {
+	var resolveErr error
+	resolved := secretReference.ReplaceAllStringFunc(value, func(ref string) string {
+		if resolveErr != nil {
+			return ""
+		}
+		match := secretReference.FindStringSubmatch(ref)
+		provider, path, key := match[1], match[2], match[3]
+
+		s, err := r.read(ctx, provider, path, refresh)
+		if err == nil {
+			var v string
+			if v, err = s.lookup(key); err == nil {
+				return v
+			}
+		}
+		resolveErr = fmt.Errorf("%s: %w", strings.TrimSuffix(strings.TrimPrefix(ref, "${"), "}"), err)
+		return ""
+	})
+	return resolved, resolveErr
+}
+
+// read returns a secret from the cache or its provider
+func (r *secretResolver) read(ctx context.Context, provider, path string, refresh bool) (secret, error) {
+	p, ok := r.providers[provider]
+	if !ok {
+		return secret{}, fmt.Errorf("unknown secret provider %q", provider)
+	}
+
+	id := provider + ":" + path
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cached, ok := r.cache[id]; ok && !refresh && now.Before(cached.expires) {
+		return cached.secret, nil
+	}
+
+	s, err := p.read(ctx, path)
+	if err != nil {
+		return secret{}, err
+	}
+
+	// Dynamic secrets are read again well before their lease runs out
+	ttl := r.refresh
+	if s.lease > 0 && s.lease/2 < ttl {
+		ttl = s.lease / 2
+	}
+	r.cache[id] = cachedSecret{secret: s, expires: now.Add(ttl)}
+	return s, nil
+}
+
+// resolveStruct replaces the secret references in every string of a config
+// struct, including those in slices, maps and nested structs
+func (r *secretResolver) resolveStruct(ctx context.Context, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !hasSecretReference(v.String()) {
+			return nil
+		}
+		resolved, err := r.resolve(ctx, v.String(), false)
+		if err != nil {
+			return err
+		}
+		v.SetString(resolved)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := r.resolveStruct(ctx, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := r.resolveStruct(ctx, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// Map values are not addressable, so resolve a copy and put it back
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			if err := r.resolveStruct(ctx, value); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), value)
+		}
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			return r.resolveStruct(ctx, v.Elem())
+		}
+	}
+	return nil
+}
+
+// resolveSecrets replaces the secret references in the configuration. The
+// references of the PostgreSQL credentials are kept so that rotated
+// credentials can be read again.
+func (c *Config) resolveSecrets() error {
+	resolver, err := newSecretResolver(c.Secrets)
+	if err != nil {
+		return err
+	}
+
+	pg := &c.Database.Postgres
+	if hasSecretReference(pg.User) || hasSecretReference(pg.Password) {
+		pg.credentials = &rotatingCredentials{
+			resolver: resolver,
+			user:     pg.User,
+			password: pg.Password,
+		}
+	}
+
+	if err := resolver.resolveStruct(context.Background(), reflect.ValueOf(c).Elem()); err != nil {
+		return fmt.Errorf("failed to resolve secret: %w", err)
+	}
+	return nil
+}
+
+// rotatingCredentials are credentials read from secret references, which are
+// read again when the secrets rotate
+type rotatingCredentials struct {
+	resolver       *secretResolver
+	user, password string
+}
+
+// envProvider reads environment variables and, for variables not set in the
+// environment, those of env files
+type envProvider struct {
+	files map[string]string
+}
+
+func newEnvProvider(files []string) (envProvider, error) {
+	p := envProvider{files: make(map[string]string)}
+	for _, file := range files {
+		if err := readEnvFile(file, p.files); err != nil {
+			return envProvider{}, err
+		}
+	}
+	return p, nil
+}
+
+func (p envProvider) read(ctx context.Context, name string) (secret, error) {
+	if value, ok := os.LookupEnv(name); ok {
+		return secret{value: value}, nil
+	}
+	if value, ok := p.files[name]; ok {
+		return secret{value: value}, nil
+	}
+	return secret{}, fmt.Errorf("environment variable %s is not set", name)
+}
+
+// readEnvFile reads the KEY=value lines of an env file into vars, skipping
+// blank lines and comments. Values may be quoted and lines prefixed with
+// export.
+func readEnvFile(path string, vars map[string]string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("%s:%d: expected KEY=value", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[strings.TrimSpace(name)] = value
+	}
+	return scanner.Err()
+}
+
+// fileProvider reads secrets from files, without their trailing newline
+type fileProvider struct{}
+
+func (fileProvider) read(ctx context.Context, path string) (secret, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return secret{}, err
+	}
+	return secret{value: strings.TrimRight(string(data), "\r\n")}, nil
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// awsProvider reads secrets from AWS Secrets Manager, signing its requests
+// with AWS Signature Version 4
+type awsProvider struct {
+	cfg    AWSSecretsConfig
+	client *http.Client
+}
+
+func newAWSProvider(cfg AWSSecretsConfig, client *http.Client) *awsProvider {
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_REGION")
+	}
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if cfg.Endpoint == "" && cfg.Region != "" {
+		cfg.Endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", cfg.Region)
+	}
+	return &awsProvider{cfg: cfg, client: client}
+}
+
+// read reads the current version of the secret with the given name or ARN
+func (p *awsProvider) read(ctx context.Context, id string) (secret, error) {
+	if p.cfg.Region == "" {
+		return secret{}, fmt.Errorf("secrets.aws.region is not set")
+	}
+	accessKey, secretKey, err := awsCredentials()
+	if err != nil {
+		return secret{}, err
+	}
+
+	payload, err := json.Marshal(map[string]string{"SecretId": id})
+	if err != nil {
+		return secret{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return secret{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if token := os.Getenv("AWS_SESSION_TOKEN"); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+	signAWSRequest(req, payload, p.cfg.Region, "secretsmanager", accessKey, secretKey, time.Now())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return secret{}, fmt.Errorf("failed to read AWS secret: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		SecretString string `json:"SecretString"`
+		Type         string `json:"__type"`
+		Message      string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return secret{}, fmt.Errorf("failed to decode AWS response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return secret{}, fmt.Errorf("secrets manager returned %s: %s %s", resp.Status, body.Type, body.Message)
+	}
+	return secret{value: body.SecretString}, nil
+}
+
+// awsCredentials returns the access key pair from the environment. Unlike the
+// AWS SDKs, no other source of the default credential chain is read: shared
+// credentials files, web identity tokens (EKS IRSA), ECS task roles and EC2
+// instance profiles are not supported.
+func awsCredentials() (accessKey, secretKey string, err error) {
+	accessKey, secretKey = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return "", "", fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set; " +
+			"credentials files, web identity tokens, ECS task roles and instance profiles are not supported")
+	}
+	return accessKey, secretKey, nil
+}
+
+// signAWSRequest adds the Signature Version 4 authorization of a request to an
+// AWS service, signing Host and every header set on the request. Requests are
+// expected to have no query string.
+func signAWSRequest(req *http.Request, payload []byte, region, service, accessKey, secretKey string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	// Canonical headers are lower-case and sorted
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s",
+		req.Method, path, req.URL.RawQuery,
+		canonicalHeaders.String(), signedHeaders, sha256Hex(payload))
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, region, service)
+	stringToSign := fmt.Sprintf("AWS4-HMAC-SHA256\n%s\n%s\n%s", amzDate, scope, sha256Hex([]byte(canonicalRequest)))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// vaultProvider reads secrets from HashiCorp Vault over its HTTP API
+type vaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+}
+
+func newVaultProvider(cfg VaultConfig, client *http.Client) *vaultProvider {
+	if cfg.Address == "" {
+		cfg.Address = os.Getenv("VAULT_ADDR")
+	}
+	return &vaultProvider{cfg: cfg, client: client}
+}
+
+// token returns the configured token, read from the token file if set, e.g.
+// one kept up to date by a Vault agent
+func (p *vaultProvider) token() (string, error) {
+	if p.cfg.TokenFile != "" {
+		data, err := os.ReadFile(p.cfg.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read Vault token: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	if p.cfg.Token != "" {
+		return p.cfg.Token, nil
+	}
+	return os.Getenv("VAULT_TOKEN"), nil
+}
+
+// read reads the secret at path. The fields of KV v2 secrets are under data.data,
+// those of KV v1 and dynamic secrets under data.
+func (p *vaultProvider) read(ctx context.Context, path string) (secret, error) {
+	if p.cfg.Address == "" {
+		return secret{}, fmt.Errorf("secrets.vault.address is not set")
+	}
+	token, err := p.token()
+	if err != nil {
+		return secret{}, err
+	}
+
+	url := strings.TrimRight(p.cfg.Address, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return secret{}, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return secret{}, fmt.Errorf("failed to read Vault secret: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		LeaseDuration int64           `json:"lease_duration"`
+		Data          json.RawMessage `json:"data"`
+		Errors        []string        `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return secret{}, fmt.Errorf("failed to decode Vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return secret{}, fmt.Errorf("vault returned %s: %s", resp.Status, strings.Join(body.Errors, "; "))
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(body.Data, &data); err != nil {
+		return secret{}, fmt.Errorf("failed to decode Vault secret: %w", err)
+	}
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	fields := make(map[string]string, len(data))
+	for key, value := range data {
+		if s, ok := value.(string); ok {
+			fields[key] = s
+		} else {
+			fields[key] = fmt.Sprint(value)
+		}
+	}
+	return secret{fields: fields, lease: time.Duration(body.LeaseDuration) * time.Second}, nil
+}
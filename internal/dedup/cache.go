@@ -0,0 +1,89 @@
+// Package dedup remembers the content of rows last written so that repeated
+// identical snapshots can skip the write
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+)
+
+// Cache maps row keys to the content hash last committed for them. It holds at
+// most size entries and starts over when full. A nil Cache reports every row as
+// changed.
+type Cache struct {
+	size int
+
+	mu     sync.Mutex
+	hashes map[string][sha256.Size]byte
+}
+
+// New creates a cache of the given size, or returns nil to disable
+// deduplication when size is not positive
+func New(size int) *Cache {
+	if size <= 0 {
+		return nil
+	}
+	return &Cache{
+		size:   size,
+		hashes: make(map[string][sha256.Size]byte),
+	}
+}
+
+// Batch starts a set of changes to be committed together with a transaction
+func (c *Cache) Batch() *Batch {
+	return &Batch{cache: c}
+}
+
+// Batch collects the hashes of changed rows until their transaction commits
+type Batch struct {
+	cache   *Cache
+	pending map[string][sha256.Size]byte
+}
+
+// Changed reports whether v differs from the content last committed for key.
+// Callers pass the row without fields that change on every write, such as the
+// height and update time.
+func (b *Batch) Changed(key string, v any) bool {
+	if b.cache == nil {
+		return true
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return true
+	}
+	hash := sha256.Sum256(data)
+
+	b.cache.mu.Lock()
+	last, ok := b.cache.hashes[key]
+	b.cache.mu.Unlock()
+	if ok && last == hash {
+		return false
+	}
+
+	if b.pending == nil {
+		b.pending = make(map[string][sha256.Size]byte)
+	}
+	b.pending[key] = hash
+	return true
+}
+
+// Commit records the changed rows as written. Call it only once their
+// transaction has committed.
+func (b *Batch) Commit() {
+	if b.cache == nil || len(b.pending) == 0 {
+		return
+	}
+
+	b.cache.mu.Lock()
+	defer b.cache.mu.Unlock()
+
+	if len(b.cache.hashes)+len(b.pending) > b.cache.size {
+		b.cache.hashes = make(map[string][sha256.Size]byte)
+	}
+	for key, hash := range b.pending {
+		b.cache.hashes[key] = hash
+	}
+	b.pending = nil
+}
@@ -0,0 +1,442 @@
+package demo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// Sizes of the synthetic state generated per chain
+const (
+	validatorCount = 10
+	accountCount   = 50
+)
+
+// chain is the synthetic state of a single demo chain
+type chain struct {
+	cfg        config.ChainConfig
+	height     int64
+	validators []types.Validator
+	accounts   []string
+	balances   map[string]*big.Int
+	// delegations maps delegator and validator to delegated shares
+	delegations map[[2]string]*big.Int
+}
+
+// Generator simulates chains by writing synthetic blocks, transfers and
+// delegation changes into storage, so the API can be served without chain nodes
+type Generator struct {
+	chains   []*chain
+	storage  *storage.Manager
+	interval time.Duration
+	rand     *rand.Rand
+	logger   *zap.Logger
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewGenerator creates a generator for the given chains, producing one block per
+// chain every interval
+func NewGenerator(chains []config.ChainConfig, storage *storage.Manager, interval time.Duration) *Generator {
+	if interval <= 0 {
+		interval = 6 * time.Second
+	}
+
+	g := &Generator{
+		storage:  storage,
+		interval: interval,
+		rand:     rand.New(rand.NewSource(1)),
+		logger:   zap.L().Named("demo"),
+	}
+	for _, cfg := range chains {
+		if cfg.Bech32Prefix == "" {
+			cfg.Bech32Prefix = "cosmos"
+		}
+		g.chains = append(g.chains, &chain{
+			cfg:         cfg,
+			balances:    make(map[string]*big.Int),
+			delegations: make(map[[2]string]*big.Int),
+		})
+	}
+
+	return g
+}
+
+// Start seeds the initial state and produces blocks in the background
+func (g *Generator) Start(ctx context.Context) error {
+	for _, c := range g.chains {
+		if err := g.seed(ctx, c); err != nil {
+			return fmt.Errorf("failed to seed demo chain %s: %w", c.cfg.Name, err)
+		}
+	}
+
+	ctx, g.cancel = context.WithCancel(ctx)
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		ticker := time.NewTicker(g.interval)
+		defer ticker.Stop()
+
+		g.logger.Info("Demo generator started",
+			zap.Int("chains", len(g.chains)),
+			zap.Duration("interval", g.interval))
+
+		for {
+			select {
+			case <-ctx.Done():
+				g.logger.Info("Demo generator stopped")
+				return
+			case <-ticker.C:
+				for _, c := range g.chains {
+					if err := g.step(ctx, c); err != nil {
+						g.logger.Error("Failed to generate demo block",
+							zap.String("chain", c.cfg.Name),
+							zap.Error(err))
+					}
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops block production and waits for it to exit
+func (g *Generator) Stop() {
+	if g.cancel != nil {
+		g.cancel()
+	}
+	g.wg.Wait()
+}
+
+// seed writes the chain, its validators, accounts, balances, delegations, supply
+// and mint parameters at height 1
+func (g *Generator) seed(ctx context.Context, c *chain) error {
+	now := time.Now()
+	c.height = 1
+
+	tx, err := g.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	chainID := c.cfg.ChainID
+	if chainID == "" {
+		chainID = c.cfg.Name + "-demo"
+	}
+	err = tx.State().UpsertChain(ctx, &types.ChainInfo{
+		Name:         c.cfg.Name,
+		ChainID:      chainID,
+		Status:       types.ChainStatusActive,
+		LatestHeight: c.height,
+		LatestTime:   now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert chain: %w", err)
+	}
+
+	for i := 0; i < validatorCount; i++ {
+		operator, err := g.address(c.cfg.Bech32Prefix+cosmos.ValidatorOperatorSuffix, c.cfg.Name, "validator", i)
+		if err != nil {
+			return err
+		}
+		consensus, err := g.address(c.cfg.Bech32Prefix+cosmos.ValidatorConsensusSuffix, c.cfg.Name, "consensus", i)
+		if err != nil {
+			return err
+		}
+
+		status := "BOND_STATUS_BONDED"
+		if i == validatorCount-1 {
+			status = "BOND_STATUS_UNBONDED"
+		}
+		c.validators = append(c.validators, types.Validator{
+			ChainName:        c.cfg.Name,
+			OperatorAddress:  operator,
+			ConsensusAddress: consensus,
+			Status:           status,
+			Tokens:           "0",
+			DelegatorShares:  "0",
+			Description: types.ValidatorDescription{
+				Moniker: fmt.Sprintf("%s validator %d", c.cfg.Name, i+1),
+			},
+			Commission: types.ValidatorCommission{
+				Rate:          "0.050000000000000000",
+				MaxRate:       "0.200000000000000000",
+				MaxChangeRate: "0.010000000000000000",
+			},
+			MinSelfDelegation: "1",
+		})
+	}
+
+	for i := 0; i < accountCount; i++ {
+		address, err := g.address(c.cfg.Bech32Prefix, c.cfg.Name, "account", i)
+		if err != nil {
+			return err
+		}
+		c.accounts = append(c.accounts, address)
+		c.balances[address] = big.NewInt(g.rand.Int63n(1_000_000_000_000) + 1_000_000)
+
+		validator := c.validators[g.rand.Intn(len(c.validators))].OperatorAddress
+		c.delegations[[2]string{address, validator}] = big.NewInt(g.rand.Int63n(100_000_000_000) + 1_000_000)
+
+		err = tx.State().UpsertAccount(ctx, &types.Account{
+			ChainName: c.cfg.Name,
+			Address:   address,
+			Height:    c.height,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upsert account: %w", err)
+		}
+		if err := g.writeBalance(ctx, tx, c, address, now); err != nil {
+			return err
+		}
+		if err := g.writeDelegation(ctx, tx, c, address, validator, now); err != nil {
+			return err
+		}
+	}
+
+	if err := g.writeValidators(ctx, tx, c, now); err != nil {
+		return err
+	}
+
+	supply := new(big.Int)
+	for _, balance := range c.balances {
+		supply.Add(supply, balance)
+	}
+	for _, shares := range c.delegations {
+		supply.Add(supply, shares)
+	}
+	err = tx.State().UpsertSupply(ctx, []types.Supply{{
+		ChainName: c.cfg.Name,
+		Denom:     c.cfg.StakingDenom(),
+		Amount:    supply.String(),
+		Height:    c.height,
+		UpdatedAt: now,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to upsert supply: %w", err)
+	}
+
+	err = tx.State().UpsertMintParams(ctx, &types.MintParams{
+		ChainName:           c.cfg.Name,
+		MintDenom:           c.cfg.StakingDenom(),
+		InflationRateChange: "0.130000000000000000",
+		InflationMax:        "0.200000000000000000",
+		InflationMin:        "0.070000000000000000",
+		GoalBonded:          "0.670000000000000000",
+		BlocksPerYear:       uint64(365 * 24 * time.Hour / g.interval),
+		CurrentInflation:    "0.100000000000000000",
+		AnnualProvisions:    new(big.Int).Div(supply, big.NewInt(10)).String(),
+		Height:              c.height,
+		UpdatedAt:           now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert mint params: %w", err)
+	}
+
+	if err := g.writeBlock(ctx, tx, c, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// step produces the next block of a chain with a few random transfers and a
+// delegation change
+func (g *Generator) step(ctx context.Context, c *chain) error {
+	now := time.Now()
+	c.height++
+
+	tx, err := g.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transfers := g.rand.Intn(5)
+	for i := 0; i < transfers; i++ {
+		from := c.accounts[g.rand.Intn(len(c.accounts))]
+		to := c.accounts[g.rand.Intn(len(c.accounts))]
+		if from == to || c.balances[from].Sign() == 0 {
+			continue
+		}
+
+		amount := new(big.Int).Rand(g.rand, c.balances[from])
+		c.balances[from].Sub(c.balances[from], amount)
+		c.balances[to].Add(c.balances[to], amount)
+
+		for _, address := range []string{from, to} {
+			if err := g.writeBalance(ctx, tx, c, address, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	delegator := c.accounts[g.rand.Intn(len(c.accounts))]
+	validator := c.validators[g.rand.Intn(len(c.validators))].OperatorAddress
+	key := [2]string{delegator, validator}
+	if c.delegations[key] == nil {
+		c.delegations[key] = new(big.Int)
+	}
+	if c.delegations[key].Sign() > 0 && g.rand.Intn(3) == 0 {
+		c.delegations[key].SetInt64(0)
+	} else {
+		c.delegations[key].Add(c.delegations[key], big.NewInt(g.rand.Int63n(10_000_000_000)+1))
+	}
+	if err := g.writeDelegation(ctx, tx, c, delegator, validator, now); err != nil {
+		return err
+	}
+	if err := g.writeValidators(ctx, tx, c, now); err != nil {
+		return err
+	}
+
+	err = tx.State().UpsertAccount(ctx, &types.Account{
+		ChainName: c.cfg.Name,
+		Address:   delegator,
+		Height:    c.height,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert account: %w", err)
+	}
+
+	if err := g.writeBlock(ctx, tx, c, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// writeBalance stores the current balance of an address
+func (g *Generator) writeBalance(ctx context.Context, tx *storage.Tx, c *chain, address string, now time.Time) error {
+	err := tx.State().UpsertBalance(ctx, &types.Balance{
+		ChainName: c.cfg.Name,
+		Address:   address,
+		Denom:     c.cfg.StakingDenom(),
+		Amount:    c.balances[address].String(),
+		Height:    c.height,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert balance: %w", err)
+	}
+	return nil
+}
+
+// writeDelegation stores a delegation, or deletes it once fully undelegated
+func (g *Generator) writeDelegation(ctx context.Context, tx *storage.Tx, c *chain, delegator, validator string, now time.Time) error {
+	shares := c.delegations[[2]string{delegator, validator}]
+	if shares.Sign() == 0 {
+		delete(c.delegations, [2]string{delegator, validator})
+		if err := tx.State().DeleteDelegation(ctx, c.cfg.Name, delegator, validator); err != nil {
+			return fmt.Errorf("failed to delete delegation: %w", err)
+		}
+		return nil
+	}
+
+	err := tx.State().UpsertDelegation(ctx, &types.Delegation{
+		ChainName:        c.cfg.Name,
+		DelegatorAddress: delegator,
+		ValidatorAddress: validator,
+		Shares:           shares.String() + ".000000000000000000",
+		Height:           c.height,
+		UpdatedAt:        now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert delegation: %w", err)
+	}
+	return nil
+}
+
+// writeValidators recomputes validator tokens from delegations and stores them
+func (g *Generator) writeValidators(ctx context.Context, tx *storage.Tx, c *chain, now time.Time) error {
+	tokens := make(map[string]*big.Int)
+	for key, shares := range c.delegations {
+		if tokens[key[1]] == nil {
+			tokens[key[1]] = new(big.Int)
+		}
+		tokens[key[1]].Add(tokens[key[1]], shares)
+	}
+
+	for i := range c.validators {
+		validator := &c.validators[i]
+		total := tokens[validator.OperatorAddress]
+		if total == nil {
+			total = new(big.Int)
+		}
+		validator.Tokens = total.String()
+		validator.DelegatorShares = total.String() + ".000000000000000000"
+		validator.Height = c.height
+		validator.UpdatedAt = now
+
+		if err := tx.State().UpsertValidator(ctx, validator); err != nil {
+			return fmt.Errorf("failed to upsert validator: %w", err)
+		}
+		if err := tx.State().UpsertConsensusAddress(ctx, c.cfg.Name, validator.ConsensusAddress, validator.OperatorAddress, c.height); err != nil {
+			return fmt.Errorf("failed to upsert consensus address: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeBlock stores the current block and advances the chain status
+func (g *Generator) writeBlock(ctx context.Context, tx *storage.Tx, c *chain, now time.Time) error {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", c.cfg.Name, c.height)))
+	proposer := c.validators[int(c.height)%(len(c.validators)-1)]
+
+	err := tx.State().InsertBlocks(ctx, []types.Block{{
+		ChainName:       c.cfg.Name,
+		Height:          c.height,
+		Hash:            strings.ToUpper(hex.EncodeToString(hash[:])),
+		ProposerAddress: proposer.ConsensusAddress,
+		TxCount:         g.rand.Intn(20),
+		Time:            now,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to insert block: %w", err)
+	}
+
+	err = tx.State().UpsertChainStatus(ctx, &types.ChainStatus{
+		ChainName:          c.cfg.Name,
+		LatestHeight:       c.height,
+		LatestBlockTime:    now,
+		LastIngestedHeight: c.height,
+		NodeVersion:        "demo",
+		AppVersion:         "demo",
+		UpdatedAt:          now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert chain status: %w", err)
+	}
+	return nil
+}
+
+// address derives a deterministic bech32 address for the i-th entity of a kind
+func (g *Generator) address(hrp, chainName, kind string, i int) (string, error) {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d", chainName, kind, i)))
+	address, err := bech32.ConvertAndEncode(hrp, sum[:20])
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s address: %w", kind, err)
+	}
+	return address, nil
+}
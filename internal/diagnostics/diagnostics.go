@@ -0,0 +1,83 @@
+// Package diagnostics serves the Go profiler and runtime statistics of the
+// process and its components, for profiling memory and CPU issues in
+// production. The handlers are only mounted when api.metrics.diagnostics is
+// enabled, since they expose internals of the process.
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+// Source returns the runtime state of a component, such as the depth of its
+// queues and the state of its workers, encoded as JSON
+type Source func() any
+
+// started is when the process started, for uptime
+var started = time.Now()
+
+// Runtime holds statistics of the Go runtime
+type Runtime struct {
+	Uptime       string `json:"uptime"`
+	GoVersion    string `json:"go_version"`
+	Goroutines   int    `json:"goroutines"`
+	GOMAXPROCS   int    `json:"gomaxprocs"`
+	CPUs         int    `json:"cpus"`
+	HeapAlloc    uint64 `json:"heap_alloc_bytes"`
+	HeapInuse    uint64 `json:"heap_inuse_bytes"`
+	HeapObjects  uint64 `json:"heap_objects"`
+	Sys          uint64 `json:"sys_bytes"`
+	NumGC        uint32 `json:"num_gc"`
+	PauseTotalNs uint64 `json:"gc_pause_total_ns"`
+	LastGC       string `json:"last_gc,omitempty"`
+}
+
+// ReadRuntime returns the current statistics of the Go runtime
+func ReadRuntime() Runtime {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := Runtime{
+		Uptime:       time.Since(started).Round(time.Second).String(),
+		GoVersion:    runtime.Version(),
+		Goroutines:   runtime.NumGoroutine(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		CPUs:         runtime.NumCPU(),
+		HeapAlloc:    mem.HeapAlloc,
+		HeapInuse:    mem.HeapInuse,
+		HeapObjects:  mem.HeapObjects,
+		Sys:          mem.Sys,
+		NumGC:        mem.NumGC,
+		PauseTotalNs: mem.PauseTotalNs,
+	}
+	if mem.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(mem.LastGC)).UTC().Format(time.RFC3339)
+	}
+	return stats
+}
+
+// Handle mounts the profiler under /debug/pprof/ and the runtime statistics,
+// with the state of each source by name, under /debug/runtime
+func Handle(mux *http.ServeMux, sources map[string]Source) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	mux.HandleFunc("/debug/runtime", func(w http.ResponseWriter, r *http.Request) {
+		components := make(map[string]any, len(sources))
+		for name, source := range sources {
+			components[name] = source()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"runtime":    ReadRuntime(),
+			"components": components,
+		})
+	})
+}
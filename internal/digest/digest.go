@@ -0,0 +1,285 @@
+// Package digest sends tenants' scheduled notification digests: the changes of
+// their watched addresses, upcoming proposal deadlines and unbonding
+// completions, in one webhook call or email per digest
+package digest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/email"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/webhook"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+var deliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "statemesh",
+	Subsystem: "digests",
+	Name:      "deliveries_total",
+	Help:      "Digest deliveries by channel (webhook or email) and result (success or failure)",
+}, []string{"channel", "result"})
+
+// Scheduler sends every tenant's enabled digests when they fall due: a daily
+// digest a day after it was last sent, or created, and a weekly digest a week
+// after. Due digests are claimed in the state store before they are sent, so
+// that only one API server sends each; a digest that could not be delivered
+// anywhere is retried at the next check.
+type Scheduler struct {
+	cfg      config.DigestConfig
+	storage  *storage.Manager
+	webhooks *webhook.Sender
+	emails   *email.Sender
+	logger   *zap.Logger
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// New creates a digest scheduler, mailing email digests with the email
+// sender when it is not nil
+func New(cfg config.DigestConfig, storage *storage.Manager, emails *email.Sender, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		cfg:      cfg,
+		storage:  storage,
+		webhooks: webhook.NewSender(cfg.Timeout),
+		emails:   emails,
+		logger:   logger.Named("digests"),
+	}
+}
+
+// Start looks for due digests in the background on the check interval
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.cfg.CheckInterval)
+		defer ticker.Stop()
+
+		s.logger.Info("Digest scheduler started", zap.Duration("check_interval", s.cfg.CheckInterval))
+
+		for {
+			select {
+			case <-ctx.Done():
+				s.logger.Info("Digest scheduler stopped")
+				return
+			case <-ticker.C:
+				if err := s.RunOnce(ctx); err != nil {
+					s.logger.Error("Failed to send digests", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduler and waits for the digest being sent
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// RunOnce sends the digests that are due
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	digests, err := s.storage.Tenants().GetEnabledDigests(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, digest := range digests {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		last := digest.CreatedAt
+		if digest.LastSentAt != nil {
+			last = *digest.LastSentAt
+		}
+		period := schedulePeriod(digest.Schedule)
+		if now.Sub(last) < period {
+			continue
+		}
+
+		claimed, err := s.storage.Tenants().ClaimDigest(ctx, digest.TenantID, digest.ID, digest.LastSentAt, &now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue // sent by another server
+		}
+
+		delivered, err := s.send(ctx, digest, last, now, now.Add(period))
+		if err != nil {
+			s.logger.Error("Failed to send digest",
+				zap.String("tenant", digest.TenantID),
+				zap.String("digest", digest.ID),
+				zap.Bool("partially_delivered", delivered),
+				zap.Error(err))
+		}
+		if !delivered {
+			if _, err := s.storage.Tenants().ClaimDigest(ctx, digest.TenantID, digest.ID, &now, digest.LastSentAt); err != nil {
+				s.logger.Error("Failed to release digest, it is sent again next period",
+					zap.String("tenant", digest.TenantID),
+					zap.String("digest", digest.ID),
+					zap.Error(err))
+			}
+			continue
+		}
+
+		s.logger.Info("Digest sent",
+			zap.String("tenant", digest.TenantID),
+			zap.String("digest", digest.ID),
+			zap.String("schedule", digest.Schedule))
+	}
+	return nil
+}
+
+// schedulePeriod returns the time between two digests of a schedule
+func schedulePeriod(schedule string) time.Duration {
+	if schedule == types.DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// send compiles a digest and delivers it to its webhook and email address,
+// reporting whether either received it. A digest that reached one of them is
+// not retried, so the other does not receive it again every check.
+func (s *Scheduler) send(ctx context.Context, digest types.Digest, from, to, until time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	report, err := s.Compile(ctx, digest, from, to, until)
+	if err != nil {
+		return false, err
+	}
+
+	var delivered bool
+	var errs []error
+	if digest.WebhookID != "" {
+		err := s.deliverWebhook(ctx, digest, report)
+		recordDelivery("webhook", err)
+		delivered = delivered || err == nil
+		errs = append(errs, err)
+	}
+	if digest.Email != "" {
+		err := s.deliverEmail(digest, report)
+		recordDelivery("email", err)
+		delivered = delivered || err == nil
+		errs = append(errs, err)
+	}
+	return delivered, errors.Join(errs...)
+}
+
+func recordDelivery(channel string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	deliveries.WithLabelValues(channel, result).Inc()
+}
+
+// Compile builds the report of a digest: the balance and delegation changes
+// of the tenant's watched addresses between from and to, and the deadlines of
+// their chains between to and until. Changes are only listed with ClickHouse.
+func (s *Scheduler) Compile(ctx context.Context, digest types.Digest, from, to, until time.Time) (*types.DigestReport, error) {
+	report := &types.DigestReport{
+		DigestID:    digest.ID,
+		TenantID:    digest.TenantID,
+		Name:        digest.Name,
+		Schedule:    digest.Schedule,
+		From:        from,
+		To:          to,
+		Until:       until,
+		Changes:     []types.ActivityItem{},
+		Proposals:   []types.ProposalDeadline{},
+		Unbondings:  []types.UnbondingCompletion{},
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	watchlists, err := s.storage.Tenants().GetWatchlists(ctx, digest.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Watched addresses by chain, in watchlist order
+	var chains []string
+	addresses := make(map[string][]string)
+	seen := make(map[storage.AccountRef]bool)
+	var refs []storage.AccountRef
+	for _, watchlist := range watchlists {
+		if _, ok := addresses[watchlist.ChainName]; !ok {
+			chains = append(chains, watchlist.ChainName)
+			addresses[watchlist.ChainName] = []string{}
+		}
+		for _, address := range watchlist.Addresses {
+			ref := storage.AccountRef{ChainName: watchlist.ChainName, Address: address}
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+			refs = append(refs, ref)
+			addresses[watchlist.ChainName] = append(addresses[watchlist.ChainName], address)
+		}
+	}
+	if len(chains) == 0 {
+		return report, nil
+	}
+
+	if len(refs) > 0 && s.storage.ClickHouse() != nil {
+		filter := types.ActivityFilter{
+			Types: []string{storage.AccountEventBalance, storage.AccountEventDelegation},
+			From:  from,
+			To:    to,
+		}
+		changes, err := s.storage.GetAccountActivity(ctx, refs, filter, "", s.cfg.MaxChanges+1)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) > s.cfg.MaxChanges {
+			changes, report.Truncated = changes[:s.cfg.MaxChanges], true
+		}
+		report.Changes = changes
+	}
+
+	if report.Proposals, err = s.storage.Deadlines().GetProposalDeadlines(ctx, chains, to, until); err != nil {
+		return nil, err
+	}
+
+	for _, chain := range chains {
+		if len(addresses[chain]) == 0 {
+			continue
+		}
+		completions, err := s.storage.Deadlines().GetUnbondingCompletions(ctx, chain, addresses[chain], to, until)
+		if err != nil {
+			return nil, err
+		}
+		report.Unbondings = append(report.Unbondings, completions...)
+	}
+
+	return report, nil
+}
+
+// deliverWebhook posts a report to the digest's webhook. Disabled webhooks are
+// skipped.
+func (s *Scheduler) deliverWebhook(ctx context.Context, digest types.Digest, report *types.DigestReport) error {
+	hook, err := s.storage.Tenants().GetWebhook(ctx, digest.TenantID, digest.WebhookID)
+	if err != nil {
+		return err
+	}
+	if !hook.Enabled {
+		return nil
+	}
+	return s.webhooks.Send(ctx, hook, webhook.EventDigest, report)
+}
@@ -0,0 +1,18 @@
+package digest
+
+import (
+	"fmt"
+
+	"github.com/cosmos/state-mesh/internal/email"
+	"github.com/cosmos/state-mesh/internal/webhook"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// deliverEmail mails a report to the digest's email address with the digest
+// email template
+func (s *Scheduler) deliverEmail(digest types.Digest, report *types.DigestReport) error {
+	if s.emails == nil {
+		return fmt.Errorf("email digests require api.tenancy.email.smtp.host")
+	}
+	return s.emails.Send(email.Message{To: digest.Email, Event: webhook.EventDigest, Data: report})
+}
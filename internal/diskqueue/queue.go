@@ -0,0 +1,189 @@
+// Package diskqueue implements a bounded on-disk FIFO of JSON records
+package diskqueue
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	pendingFile = "pending.jsonl"
+	replayFile  = "replay.jsonl"
+)
+
+// ErrFull is returned when a record would grow a queue beyond its size limit
+var ErrFull = errors.New("disk queue is full")
+
+// Queue is a bounded on-disk FIFO of JSON records, one per line. New
+// records are appended to the pending file; a replay moves the pending file
+// aside and drains it, so appends can continue while older records are
+// replayed. Both files survive restarts.
+type Queue struct {
+	dir      string
+	maxBytes int64
+	fsync    bool
+
+	mu   sync.Mutex
+	size int64
+}
+
+// New opens a queue directory, accounting for records left by a previous run.
+// A maxBytes of zero means unbounded. With fsync set, every append is flushed
+// to disk before it returns.
+func New(dir string, maxBytes int64, fsync bool) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	q := &Queue{dir: dir, maxBytes: maxBytes, fsync: fsync}
+	for _, name := range []string{pendingFile, replayFile} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err == nil {
+			q.size += info.Size()
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to stat queue file: %w", err)
+		}
+	}
+
+	return q, nil
+}
+
+// Append encodes and queues a record, or returns ErrFull when the queue has no
+// room for it
+func (q *Queue) Append(v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode queue record: %w", err)
+	}
+	line = append(line, '\n')
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.maxBytes > 0 && q.size+int64(len(line)) > q.maxBytes {
+		return ErrFull
+	}
+
+	f, err := os.OpenFile(filepath.Join(q.dir, pendingFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open queue file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("failed to write queue file: %w", err)
+	}
+	if q.fsync {
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("failed to sync queue file: %w", err)
+		}
+	}
+	q.size += int64(len(line))
+
+	return nil
+}
+
+// Empty reports whether no records are queued
+func (q *Queue) Empty() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size == 0
+}
+
+// Replay feeds queued records to fn in order. It first finishes any replay
+// interrupted earlier, then drains the records pending when it was called. If
+// fn fails, the failed record and everything after it stay queued for the
+// next replay.
+func (q *Queue) Replay(fn func([]byte) error) (int, error) {
+	replayPath := filepath.Join(q.dir, replayFile)
+
+	var replayed int
+	var rotated bool
+	for {
+		if _, err := os.Stat(replayPath); errors.Is(err, os.ErrNotExist) {
+			// Records appended after the rotation are left for the next replay
+			if rotated {
+				return replayed, nil
+			}
+
+			q.mu.Lock()
+			err := os.Rename(filepath.Join(q.dir, pendingFile), replayPath)
+			q.mu.Unlock()
+			if errors.Is(err, os.ErrNotExist) {
+				return replayed, nil
+			}
+			if err != nil {
+				return replayed, fmt.Errorf("failed to rotate queue file: %w", err)
+			}
+			rotated = true
+		} else if err != nil {
+			return replayed, fmt.Errorf("failed to stat queue file: %w", err)
+		}
+
+		n, err := q.drain(replayPath, fn)
+		replayed += n
+		if err != nil {
+			return replayed, err
+		}
+	}
+}
+
+// drain replays one queue file, removing it once every record is accepted
+func (q *Queue) drain(path string, fn func([]byte) error) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read queue file: %w", err)
+	}
+
+	var replayed int
+	var consumed int64
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if err := fn(line); err != nil {
+			if werr := q.truncate(path, data[consumed:], consumed); werr != nil {
+				return replayed, werr
+			}
+			return replayed, err
+		}
+		replayed++
+		consumed += int64(len(line)) + 1
+	}
+
+	if err := os.Remove(path); err != nil {
+		return replayed, fmt.Errorf("failed to remove queue file: %w", err)
+	}
+	q.release(int64(len(data)))
+
+	return replayed, nil
+}
+
+// truncate rewrites a queue file to its unreplayed remainder
+func (q *Queue) truncate(path string, remainder []byte, consumed int64) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, remainder, 0o644); err != nil {
+		return fmt.Errorf("failed to write queue file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace queue file: %w", err)
+	}
+	q.release(consumed)
+	return nil
+}
+
+// release accounts for replayed bytes
+func (q *Queue) release(n int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.size -= n
+	if q.size < 0 {
+		q.size = 0
+	}
+}
@@ -0,0 +1,268 @@
+// Package email delivers notifications by email, each rendered with the Go
+// template of its event type
+package email
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/webhook"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// Events lists the events email subscriptions may subscribe to. Digests are
+// emailed to the address of each digest instead.
+var Events = []string{
+	webhook.EventUnbondingCompletion,
+	webhook.EventCommissionIncrease,
+	webhook.EventAnomaly,
+	webhook.EventAlert,
+}
+
+// templateEvents lists the events emails have a template for
+var templateEvents = append([]string{webhook.EventDigest}, Events...)
+
+// ErrRateLimited is returned when a recipient was sent its maximum number of
+// emails in the rate window
+var ErrRateLimited = errors.New("email rate limit reached")
+
+//go:embed templates/*.tmpl
+var builtin embed.FS
+
+// timeLayout formats the times of the built-in templates
+const timeLayout = "2006-01-02 15:04 MST"
+
+var funcs = template.FuncMap{
+	"time": func(t time.Time) string { return t.UTC().Format(timeLayout) },
+	// percent formats a decimal rate, such as a commission rate, in percent
+	"percent": func(rate string) string {
+		d, err := decimal.NewFromString(rate)
+		if err != nil {
+			return rate
+		}
+		return d.Shift(2).String() + "%"
+	},
+}
+
+// Subscribed reports whether an enabled email subscription subscribes to an
+// event
+func Subscribed(sub types.EmailSubscription, event string) bool {
+	return sub.Enabled && slices.Contains(sub.Events, event)
+}
+
+// Message is a notification to email
+type Message struct {
+	To    string
+	Event string
+	Data  any // what the event's template renders
+	// UnsubscribeToken, when set with an unsubscribe URL, links the email to
+	// the page disabling its subscription
+	UnsubscribeToken string
+}
+
+// Sender renders messages with the template of their event and mails them
+// through an SMTP server, limiting the emails each recipient receives in the
+// rate window. Every attempt counts towards the limit, so that a failing
+// recipient is not retried faster than the others.
+type Sender struct {
+	cfg       config.EmailConfig
+	smtp      config.SMTPConfig
+	templates map[string]*template.Template
+	send      func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+	now       func() time.Time
+
+	mu   sync.Mutex
+	sent map[string][]time.Time // by recipient, within the rate window
+}
+
+// NewSender creates a sender mailing through an SMTP server. The template of
+// an event is read from the templates directory when it holds an
+// <event>.tmpl, and is the built-in one otherwise; each defines a "subject"
+// and a "body" template.
+func NewSender(cfg config.EmailConfig, smtpCfg config.SMTPConfig) (*Sender, error) {
+	s := &Sender{
+		cfg:       cfg,
+		smtp:      smtpCfg,
+		templates: make(map[string]*template.Template, len(templateEvents)),
+		send:      smtp.SendMail,
+		now:       time.Now,
+		sent:      make(map[string][]time.Time),
+	}
+
+	for _, event := range templateEvents {
+		name := event + ".tmpl"
+		text, err := fs.ReadFile(builtin, "templates/"+name)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.TemplatesDir != "" {
+			custom, err := os.ReadFile(filepath.Join(cfg.TemplatesDir, name))
+			switch {
+			case err == nil:
+				text = custom
+			case !errors.Is(err, fs.ErrNotExist):
+				return nil, fmt.Errorf("failed to read email template: %w", err)
+			}
+		}
+
+		tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid email template %s: %w", name, err)
+		}
+		for _, required := range []string{"subject", "body"} {
+			if tmpl.Lookup(required) == nil {
+				return nil, fmt.Errorf("email template %s does not define %q", name, required)
+			}
+		}
+		s.templates[event] = tmpl
+	}
+	return s, nil
+}
+
+// Send renders a message and mails it, or returns ErrRateLimited when its
+// recipient reached the rate limit
+func (s *Sender) Send(msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", msg.To)
+	}
+	body, err := s.Render(msg)
+	if err != nil {
+		return err
+	}
+	if !s.allow(msg.To) {
+		return ErrRateLimited
+	}
+
+	var auth smtp.Auth
+	if s.smtp.Username != "" {
+		auth = smtp.PlainAuth("", s.smtp.Username, s.smtp.Password, s.smtp.Host)
+	}
+	addr := net.JoinHostPort(s.smtp.Host, strconv.Itoa(s.smtp.Port))
+	if err := s.send(addr, auth, s.smtp.From, []string{msg.To}, body); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// Subscriptions returns a tenant's email subscriptions subscribed to an
+// event, none without a sender
+func (s *Sender) Subscriptions(ctx context.Context, tenants storage.TenantStore, tenantID, event string) ([]types.EmailSubscription, error) {
+	if s == nil {
+		return nil, nil
+	}
+	subs, err := tenants.GetEmailSubscriptions(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	var subscribed []types.EmailSubscription
+	for _, sub := range subs {
+		if Subscribed(sub, event) {
+			subscribed = append(subscribed, sub)
+		}
+	}
+	return subscribed, nil
+}
+
+// Render returns the email of a message: its headers and plain text body,
+// followed by the unsubscribe link when it has one
+func (s *Sender) Render(msg Message) ([]byte, error) {
+	tmpl, ok := s.templates[msg.Event]
+	if !ok {
+		return nil, fmt.Errorf("no email template for event %q", msg.Event)
+	}
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", msg.Data); err != nil {
+		return nil, fmt.Errorf("failed to render %s email subject: %w", msg.Event, err)
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", msg.Data); err != nil {
+		return nil, fmt.Errorf("failed to render %s email body: %w", msg.Event, err)
+	}
+
+	unsubscribe := s.unsubscribeLink(msg.UnsubscribeToken)
+	if unsubscribe != "" {
+		fmt.Fprintf(&body, "\n--\nTo stop receiving these emails, visit %s\n", unsubscribe)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.smtp.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	// Template output is folded onto one line, so it cannot add headers
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(subject.String()), " ")))
+	fmt.Fprintf(&b, "Date: %s\r\n", s.now().UTC().Format(time.RFC1123Z))
+	if unsubscribe != "" {
+		fmt.Fprintf(&b, "List-Unsubscribe: <%s>\r\n", unsubscribe)
+		b.WriteString("List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+	return []byte(b.String()), nil
+}
+
+// unsubscribeLink returns the unsubscribe URL with a token, or nothing
+// without either
+func (s *Sender) unsubscribeLink(token string) string {
+	if token == "" || s.cfg.UnsubscribeURL == "" {
+		return ""
+	}
+	u, err := url.Parse(s.cfg.UnsubscribeURL)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// allow records an email to a recipient unless it reached the rate limit
+func (s *Sender) allow(recipient string) bool {
+	if s.cfg.RateLimit == 0 {
+		return true
+	}
+	recipient = strings.ToLower(recipient)
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Forget the emails sent before the window, and recipients without any
+	for r, times := range s.sent {
+		i := 0
+		for i < len(times) && now.Sub(times[i]) >= s.cfg.RateWindow {
+			i++
+		}
+		if i == len(times) {
+			delete(s.sent, r)
+		} else {
+			s.sent[r] = times[i:]
+		}
+	}
+
+	if len(s.sent[recipient]) >= s.cfg.RateLimit {
+		return false
+	}
+	s.sent[recipient] = append(s.sent[recipient], now)
+	return true
+}
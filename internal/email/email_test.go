@@ -0,0 +1,209 @@
+package email
+
+import (
+	"errors"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/webhook"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+var testSMTP = config.SMTPConfig{Host: "smtp.example.com", Port: 587, From: "notifications@example.com"}
+
+func TestRenderBuiltinTemplates(t *testing.T) {
+	s, err := NewSender(config.EmailConfig{}, testSMTP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		event       string
+		data        any
+		wantSubject string
+		wantBody    []string
+	}{
+		{
+			event: webhook.EventDigest,
+			data: &types.DigestReport{
+				Name: "weekly", Schedule: types.DigestWeekly, From: at, To: at, Until: at,
+				Changes: []types.ActivityItem{{
+					Timestamp: at, ChainName: "cosmoshub", Type: "balance",
+					Balance: &types.BalanceEvent{Address: "cosmos1a", Denom: "uatom", PreviousAmount: "1", Amount: "2"},
+				}},
+				Truncated: true,
+			},
+			wantSubject: "weekly: 1 changes, 0 proposal deadlines, 0 unbonding completions",
+			wantBody:    []string{"Changes of watched addresses (1, more were left out)", "cosmos1a uatom: 1 -> 2"},
+		},
+		{
+			event: webhook.EventUnbondingCompletion,
+			data: types.UnbondingReminder{Unbonding: types.UnbondingCompletion{
+				ChainName: "cosmoshub", DelegatorAddress: "cosmos1a", ValidatorAddress: "cosmosvaloper1a", Balance: "100", CompletionTime: at,
+			}},
+			wantSubject: "Unbonding of cosmos1a on cosmoshub completes 2026-03-01 12:00 UTC",
+			wantBody:    []string{"cosmos1a unbonds 100 from cosmosvaloper1a on cosmoshub."},
+		},
+		{
+			event: webhook.EventCommissionIncrease,
+			data: types.CommissionAlert{
+				Change:     types.CommissionChange{ChainName: "cosmoshub", OperatorAddress: "cosmosvaloper1a", OldRate: "0.050000000000000000", NewRate: "0.1"},
+				Delegators: []string{"cosmos1a"},
+			},
+			wantSubject: "cosmosvaloper1a raised its commission to 10% on cosmoshub",
+			wantBody:    []string{"from 5% to 10%", "  cosmos1a\n"},
+		},
+		{
+			event:       webhook.EventAnomaly,
+			data:        types.AnomalyAlert{Anomaly: types.Anomaly{ChainName: "osmosis", Metric: "delegation_outflow", Value: 12.345, ZScore: 4}},
+			wantSubject: "Anomaly in delegation_outflow on osmosis",
+			wantBody:    []string{"was 12.35 in the hour", "z-score 4.00"},
+		},
+		{
+			event: webhook.EventAlert,
+			data: types.RuleAlert{
+				Rule:      types.AlertRule{Name: "low balance", ChainName: "cosmoshub", Condition: "balance(uatom) < 100"},
+				EventType: "balance",
+				Event:     []byte(`{"address":"cosmos1a"}`),
+			},
+			wantSubject: "Alert rule low balance fired on cosmoshub",
+			wantBody:    []string{": balance(uatom) < 100", `{"address":"cosmos1a"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			msg, err := s.Render(Message{To: "ops@example.com", Event: tt.event, Data: tt.data})
+			if err != nil {
+				t.Fatal(err)
+			}
+			text := strings.ReplaceAll(string(msg), "\r\n", "\n")
+			if !strings.Contains(text, "Subject: "+tt.wantSubject+"\n") {
+				t.Errorf("subject not %q in\n%s", tt.wantSubject, text)
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(text, want) {
+					t.Errorf("body does not contain %q:\n%s", want, text)
+				}
+			}
+			if strings.Contains(text, "List-Unsubscribe") {
+				t.Errorf("unsubscribe header without a token")
+			}
+		})
+	}
+}
+
+func TestRenderUnsubscribeLink(t *testing.T) {
+	s, err := NewSender(config.EmailConfig{UnsubscribeURL: "https://api.example.com/api/v1/unsubscribe"}, testSMTP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := s.Render(Message{To: "ops@example.com", Event: webhook.EventAnomaly, Data: types.AnomalyAlert{}, UnsubscribeToken: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := "https://api.example.com/api/v1/unsubscribe?token=abc"
+	for _, want := range []string{
+		"List-Unsubscribe: <" + link + ">\r\n",
+		"List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n",
+		"visit " + link + "\r\n",
+	} {
+		if !strings.Contains(string(msg), want) {
+			t.Errorf("email does not contain %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestCustomTemplates(t *testing.T) {
+	dir := t.TempDir()
+	custom := "{{define \"subject\"}}Alert\r\nBcc: evil@example.com{{end}}{{define \"body\"}}Rule {{.Rule.Name}}{{end}}"
+	if err := os.WriteFile(filepath.Join(dir, "alert.tmpl"), []byte(custom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSender(config.EmailConfig{TemplatesDir: dir}, testSMTP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := s.Render(Message{To: "ops@example.com", Event: webhook.EventAlert, Data: types.RuleAlert{Rule: types.AlertRule{Name: "x"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(msg), "Subject: Alert Bcc: evil@example.com\r\n") {
+		t.Errorf("subject not folded onto one line:\n%s", msg)
+	}
+	if !strings.HasSuffix(string(msg), "\r\n\r\nRule x") {
+		t.Errorf("custom body not rendered:\n%s", msg)
+	}
+
+	// Other events keep their built-in template
+	if _, err := s.Render(Message{Event: webhook.EventAnomaly, Data: types.AnomalyAlert{}}); err != nil {
+		t.Error(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "anomaly.tmpl"), []byte(`{{define "subject"}}x{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSender(config.EmailConfig{TemplatesDir: dir}, testSMTP); err == nil || !strings.Contains(err.Error(), `does not define "body"`) {
+		t.Errorf("NewSender() error = %v, want missing body", err)
+	}
+}
+
+func TestSendRateLimit(t *testing.T) {
+	s, err := NewSender(config.EmailConfig{RateLimit: 2, RateWindow: time.Hour}, testSMTP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	var sent []string
+	s.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		sent = append(sent, to[0])
+		return nil
+	}
+
+	steps := []struct {
+		to      string
+		advance time.Duration
+		wantErr error
+	}{
+		{to: "a@example.com"},
+		{to: "A@example.com", advance: 10 * time.Minute},
+		{to: "a@example.com", wantErr: ErrRateLimited},
+		{to: "b@example.com"},                                                    // limited per recipient
+		{to: "a@example.com", advance: 50 * time.Minute},                         // the first left the window
+		{to: "a@example.com", advance: 9 * time.Minute, wantErr: ErrRateLimited}, // the second has not
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		err := s.Send(Message{To: step.to, Event: webhook.EventAnomaly, Data: types.AnomalyAlert{}})
+		if !errors.Is(err, step.wantErr) {
+			t.Errorf("step %d: Send() error = %v, want %v", i, err, step.wantErr)
+		}
+	}
+	if len(sent) != 4 {
+		t.Errorf("sent %v, want 4 emails", sent)
+	}
+
+	if err := s.Send(Message{To: "a@example.com\r\nBcc: evil@example.com", Event: webhook.EventAnomaly}); err == nil {
+		t.Errorf("Send() to a recipient with a line break succeeded")
+	}
+}
+
+func TestSubscribed(t *testing.T) {
+	sub := types.EmailSubscription{Enabled: true, Events: []string{webhook.EventAlert}}
+	if !Subscribed(sub, webhook.EventAlert) || Subscribed(sub, webhook.EventAnomaly) {
+		t.Errorf("Subscribed() does not follow the subscription's events")
+	}
+	sub.Enabled = false
+	if Subscribed(sub, webhook.EventAlert) {
+		t.Errorf("disabled subscription is subscribed")
+	}
+}
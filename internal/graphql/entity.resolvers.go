@@ -0,0 +1,49 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cosmos/state-mesh/internal/graphql/generated"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// FindAccountStateByChainNameAndAddress is the resolver for the findAccountStateByChainNameAndAddress field.
+func (r *entityResolver) FindAccountStateByChainNameAndAddress(ctx context.Context, chainName string, address string) (*types.AccountState, error) {
+	states, err := r.accounts.GetAccountStates(ctx, []storage.AccountRef{{ChainName: chainName, Address: address}})
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+	r.setDisplayAmounts(states[0].Balances)
+	return &states[0], nil
+}
+
+// FindChainInfoByName is the resolver for the findChainInfoByName field.
+func (r *entityResolver) FindChainInfoByName(ctx context.Context, name string) (*types.ChainInfo, error) {
+	chain, err := r.storage.GetChain(ctx, name)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	return chain, err
+}
+
+// FindValidatorByChainNameAndOperatorAddress is the resolver for the findValidatorByChainNameAndOperatorAddress field.
+func (r *entityResolver) FindValidatorByChainNameAndOperatorAddress(ctx context.Context, chainName string, operatorAddress string) (*types.Validator, error) {
+	validator, err := r.validators.GetValidator(ctx, chainName, operatorAddress)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	return validator, err
+}
+
+// Entity returns generated.EntityResolver implementation.
+func (r *Resolver) Entity() generated.EntityResolver { return &entityResolver{r} }
+
+type entityResolver struct{ *Resolver }
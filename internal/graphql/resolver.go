@@ -1,7 +1,9 @@
 package graphql
 
 import (
+	"github.com/cosmos/state-mesh/internal/config"
 	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
 	"go.uber.org/zap"
 )
 
@@ -10,14 +12,33 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct{
-	storage *storage.Manager
-	logger  *zap.Logger
+	storage    *storage.Manager
+	accounts   storage.AccountReader
+	validators storage.ValidatorReader
+	chains     []config.ChainConfig
+	logger     *zap.Logger
 }
 
 // NewResolver creates a new GraphQL resolver with dependencies
-func NewResolver(storage *storage.Manager, logger *zap.Logger) *Resolver {
+func NewResolver(storage *storage.Manager, chains []config.ChainConfig, logger *zap.Logger) *Resolver {
 	return &Resolver{
-		storage: storage,
-		logger:  logger,
+		storage:    storage,
+		accounts:   storage,
+		validators: storage,
+		chains:     chains,
+		logger:     logger,
+	}
+}
+
+// setDisplayAmounts converts the balances in their chain's base denom to
+// display units, as the REST API does
+func (r *Resolver) setDisplayAmounts(balances []types.Balance) {
+	for i := range balances {
+		for _, chain := range r.chains {
+			if chain.Name == balances[i].ChainName {
+				balances[i].DisplayAmount, _ = chain.DisplayAmount(balances[i].Denom, balances[i].Amount)
+				break
+			}
+		}
 	}
 }
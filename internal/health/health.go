@@ -0,0 +1,126 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status values reported by checks and reports
+const (
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+	StatusDegraded  = "degraded"
+	StatusDraining  = "draining"
+)
+
+// CheckFunc checks a single dependency and returns an error when it is unavailable
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult represents the outcome of a single dependency check
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report represents the aggregated outcome of all dependency checks
+type Report struct {
+	Status    string        `json:"status"`
+	Degraded  []string      `json:"degraded,omitempty"`
+	Checks    []CheckResult `json:"checks"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// Healthy reports whether every dependency check passed
+func (r *Report) Healthy() bool {
+	return r.Status == StatusHealthy
+}
+
+// Checker runs registered dependency checks
+type Checker struct {
+	timeout time.Duration
+	mu      sync.RWMutex
+	checks  map[string]CheckFunc
+}
+
+// NewChecker creates a new checker with the given per-check timeout
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	return &Checker{
+		timeout: timeout,
+		checks:  make(map[string]CheckFunc),
+	}
+}
+
+// Register adds or replaces a named dependency check
+func (c *Checker) Register(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Check runs all registered checks concurrently and aggregates their results
+func (c *Checker) Check(ctx context.Context) *Report {
+	c.mu.RLock()
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	c.mu.RUnlock()
+	sort.Strings(names)
+
+	results := make([]CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		c.mu.RLock()
+		check := c.checks[name]
+		c.mu.RUnlock()
+
+		wg.Add(1)
+		go func(i int, name string, check CheckFunc) {
+			defer wg.Done()
+			results[i] = c.run(ctx, name, check)
+		}(i, name, check)
+	}
+	wg.Wait()
+
+	report := &Report{
+		Status:    StatusHealthy,
+		Checks:    results,
+		CheckedAt: time.Now(),
+	}
+	for _, result := range results {
+		if result.Status != StatusHealthy {
+			report.Status = StatusDegraded
+			report.Degraded = append(report.Degraded, result.Name)
+		}
+	}
+
+	return report
+}
+
+// run executes a single check with the configured timeout
+func (c *Checker) run(ctx context.Context, name string, check CheckFunc) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Name:      name,
+		Status:    StatusHealthy,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusUnhealthy
+		result.Error = err.Error()
+	}
+
+	return result
+}
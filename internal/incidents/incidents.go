@@ -0,0 +1,447 @@
+// Package incidents pages the operators through PagerDuty or Opsgenie when the
+// deployment itself is unhealthy: a database unreachable, a chain's ingestion
+// lagging or a Kafka consumer group falling behind. Unlike tenants' alerts,
+// incidents are about the service, not the chains' data.
+package incidents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/streaming"
+)
+
+// Incident severities
+const (
+	SeverityCritical = "critical"
+	SeverityError    = "error"
+	SeverityWarning  = "warning"
+)
+
+var (
+	openIncidents = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "statemesh",
+		Subsystem: "incidents",
+		Name:      "open",
+		Help:      "Incidents currently open by check",
+	}, []string{"check"})
+
+	notifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "incidents",
+		Name:      "notifications_total",
+		Help:      "Incident notifications by integration, action (trigger or resolve) and result (success or failure)",
+	}, []string{"integration", "action", "result"})
+)
+
+// Incident is a problem a check found. Its key identifies it across checks,
+// so that integrations deduplicate it and resolve it later.
+type Incident struct {
+	Key      string         `json:"key"`
+	Summary  string         `json:"summary"`
+	Severity string         `json:"severity"`
+	Details  map[string]any `json:"details,omitempty"`
+}
+
+// Check returns the incidents open now. A check that fails leaves the
+// incidents it opened before as they are.
+type Check func(ctx context.Context) ([]Incident, error)
+
+// Integration opens and resolves incidents on an incident management service
+type Integration interface {
+	Name() string
+	Trigger(ctx context.Context, source string, incident Incident) error
+	Resolve(ctx context.Context, source string, incident Incident) error
+}
+
+// Monitor runs the registered checks on the check interval, triggering the
+// incidents they find on every integration and resolving them once a check
+// no longer finds them. An incident an integration could not be notified of
+// is retried at the next check. Every API server runs its own monitor; the
+// integrations deduplicate their incidents by key.
+//
+// The open incidents are saved to the state file, so that those open when the
+// server stops are resolved after a restart once their check, having run
+// successfully, no longer finds them.
+type Monitor struct {
+	cfg          config.IncidentConfig
+	source       string
+	integrations []Integration
+	logger       *zap.Logger
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+
+	mu     sync.Mutex
+	checks map[string]Check
+
+	// Guarded by runMu, which serializes runs without holding mu while the
+	// checks and integrations are called
+	runMu sync.Mutex
+	open  map[string]map[string]openIncident // by integration, then key
+	found map[string][]Incident              // by check, as of its last success
+}
+
+// openIncident is an incident open on an integration, with the check that
+// found it
+type openIncident struct {
+	Check    string   `json:"check"`
+	Incident Incident `json:"incident"`
+}
+
+// New creates a monitor notifying the configured integrations
+func New(cfg config.IncidentConfig, logger *zap.Logger) *Monitor {
+	source := cfg.Source
+	if source == "" {
+		source, _ = os.Hostname()
+	}
+
+	var integrations []Integration
+	if cfg.PagerDuty.RoutingKey != "" {
+		integrations = append(integrations, NewPagerDuty(cfg.PagerDuty, cfg.Timeout))
+	}
+	if cfg.Opsgenie.APIKey != "" {
+		integrations = append(integrations, NewOpsgenie(cfg.Opsgenie, cfg.Timeout))
+	}
+	return newMonitor(cfg, source, integrations, logger)
+}
+
+func newMonitor(cfg config.IncidentConfig, source string, integrations []Integration, logger *zap.Logger) *Monitor {
+	m := &Monitor{
+		cfg:          cfg,
+		source:       source,
+		integrations: integrations,
+		logger:       logger.Named("incidents"),
+		checks:       make(map[string]Check),
+		open:         make(map[string]map[string]openIncident, len(integrations)),
+		found:        make(map[string][]Incident),
+	}
+	for _, integration := range integrations {
+		m.open[integration.Name()] = make(map[string]openIncident)
+	}
+	m.load()
+	return m
+}
+
+// load reads the incidents left open by a previous run from the state file.
+// Those of integrations no longer configured are dropped.
+func (m *Monitor) load() {
+	if m.cfg.StateFile == "" {
+		return
+	}
+	data, err := os.ReadFile(m.cfg.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	var saved map[string]map[string]openIncident
+	if err == nil {
+		err = json.Unmarshal(data, &saved)
+	}
+	if err != nil {
+		m.logger.Warn("Failed to load open incidents", zap.String("file", m.cfg.StateFile), zap.Error(err))
+		return
+	}
+
+	for name, incidents := range saved {
+		if opened, ok := m.open[name]; ok {
+			maps.Copy(opened, incidents)
+		}
+	}
+}
+
+// save writes the open incidents to the state file, replacing it atomically
+func (m *Monitor) save() {
+	if m.cfg.StateFile == "" {
+		return
+	}
+	err := func() error {
+		data, err := json.Marshal(m.open)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(m.cfg.StateFile), 0o755); err != nil {
+			return err
+		}
+		tmp := m.cfg.StateFile + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o600); err != nil {
+			return err
+		}
+		return os.Rename(tmp, m.cfg.StateFile)
+	}()
+	if err != nil {
+		m.logger.Warn("Failed to save open incidents", zap.String("file", m.cfg.StateFile), zap.Error(err))
+	}
+}
+
+// Register adds or replaces a named check
+func (m *Monitor) Register(name string, check Check) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checks[name] = check
+}
+
+// Start runs the checks in the background on the check interval
+func (m *Monitor) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(m.cfg.CheckInterval)
+		defer ticker.Stop()
+
+		m.logger.Info("Incident monitor started",
+			zap.Duration("check_interval", m.cfg.CheckInterval),
+			zap.Int("integrations", len(m.integrations)))
+
+		for {
+			select {
+			case <-ctx.Done():
+				m.logger.Info("Incident monitor stopped")
+				return
+			case <-ticker.C:
+				m.RunOnce(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the monitor and waits for the notifications being sent
+func (m *Monitor) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+}
+
+// RunOnce runs every check and notifies the integrations of the incidents
+// that opened or were resolved since the last run. An open incident is only
+// resolved once its check has succeeded since the monitor started, or when
+// its check is no longer registered.
+func (m *Monitor) RunOnce(ctx context.Context) {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+
+	m.mu.Lock()
+	checks := maps.Clone(m.checks)
+	m.mu.Unlock()
+
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		checkCtx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+		incidents, err := checks[name](checkCtx)
+		cancel()
+		if err != nil {
+			m.logger.Warn("Incident check failed", zap.String("check", name), zap.Error(err))
+			continue
+		}
+		m.found[name] = incidents
+		openIncidents.WithLabelValues(name).Set(float64(len(incidents)))
+	}
+
+	current := make(map[string]openIncident)
+	for name, incidents := range m.found {
+		for _, incident := range incidents {
+			current[incident.Key] = openIncident{Check: name, Incident: incident}
+		}
+	}
+
+	changed := false
+	for _, integration := range m.integrations {
+		opened := m.open[integration.Name()]
+		for key, incident := range current {
+			if _, ok := opened[key]; ok {
+				continue
+			}
+			if m.notify(ctx, integration, "trigger", incident.Incident) {
+				opened[key] = incident
+				changed = true
+			}
+		}
+		for key, incident := range opened {
+			if _, ok := current[key]; ok {
+				continue
+			}
+			_, ran := m.found[incident.Check]
+			_, registered := checks[incident.Check]
+			if registered && !ran {
+				continue
+			}
+			if m.notify(ctx, integration, "resolve", incident.Incident) {
+				delete(opened, key)
+				changed = true
+			}
+		}
+	}
+	if changed {
+		m.save()
+	}
+}
+
+// notify triggers or resolves an incident on an integration, reporting
+// whether it succeeded
+func (m *Monitor) notify(ctx context.Context, integration Integration, action string, incident Incident) bool {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+
+	var err error
+	if action == "trigger" {
+		err = integration.Trigger(ctx, m.source, incident)
+	} else {
+		err = integration.Resolve(ctx, m.source, incident)
+	}
+	if err != nil {
+		m.logger.Warn("Failed to notify incident",
+			zap.String("integration", integration.Name()),
+			zap.String("action", action),
+			zap.String("incident", incident.Key),
+			zap.Error(err))
+		notifications.WithLabelValues(integration.Name(), action, "failure").Inc()
+		return false
+	}
+
+	m.logger.Info("Incident notified",
+		zap.String("integration", integration.Name()),
+		zap.String("action", action),
+		zap.String("incident", incident.Key),
+		zap.String("summary", incident.Summary))
+	notifications.WithLabelValues(integration.Name(), action, "success").Inc()
+	return true
+}
+
+// DatabaseCheck opens a critical incident while a database does not answer
+// its ping
+func DatabaseCheck(name string, ping func(ctx context.Context) error) Check {
+	return func(ctx context.Context) ([]Incident, error) {
+		if err := ping(ctx); err != nil {
+			return []Incident{{
+				Key:      "database:" + name,
+				Summary:  fmt.Sprintf("%s is unreachable: %v", name, err),
+				Severity: SeverityCritical,
+				Details:  map[string]any{"database": name, "error": err.Error()},
+			}}, nil
+		}
+		return nil, nil
+	}
+}
+
+// ChainLagCheck opens an incident for each chain whose last ingested height
+// is more than maxLag blocks behind its latest height. Chains not ingested
+// yet are skipped.
+func ChainLagCheck(chains storage.StateStore, names []string, maxLag int64) Check {
+	return func(ctx context.Context) ([]Incident, error) {
+		var incidents []Incident
+		var errs []string
+		for _, name := range names {
+			chain, err := chains.GetChain(ctx, name)
+			if err != nil {
+				if !errors.Is(err, storage.ErrNotFound) {
+					errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+				}
+				continue
+			}
+			lag := chain.LatestHeight - chain.LastIngestedHeight
+			if chain.LastIngestedHeight == 0 || lag <= maxLag {
+				continue
+			}
+			incidents = append(incidents, Incident{
+				Key:      "chain_lag:" + name,
+				Summary:  fmt.Sprintf("Ingestion of %s is %d blocks behind", name, lag),
+				Severity: SeverityError,
+				Details: map[string]any{
+					"chain":                name,
+					"latest_height":        chain.LatestHeight,
+					"last_ingested_height": chain.LastIngestedHeight,
+					"lag":                  lag,
+					"max_lag":              maxLag,
+				},
+			})
+		}
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("failed to get chains: %s", strings.Join(errs, "; "))
+		}
+		return incidents, nil
+	}
+}
+
+// ChainHaltCheck opens an incident for each chain the ingester reports halted,
+// its latest height not increasing for longer than the halt threshold. Chains
+// not ingested yet are skipped.
+func ChainHaltCheck(chains storage.StateStore, names []string) Check {
+	return func(ctx context.Context) ([]Incident, error) {
+		var incidents []Incident
+		var errs []string
+		for _, name := range names {
+			chain, err := chains.GetChain(ctx, name)
+			if err != nil {
+				if !errors.Is(err, storage.ErrNotFound) {
+					errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+				}
+				continue
+			}
+			if chain.HaltedSince == nil {
+				continue
+			}
+			incidents = append(incidents, Incident{
+				Key: "chain_halt:" + name,
+				Summary: fmt.Sprintf("%s has not produced a block after height %d for %s",
+					name, chain.LatestHeight, time.Since(*chain.HaltedSince).Round(time.Minute)),
+				Severity: SeverityCritical,
+				Details: map[string]any{
+					"chain":         name,
+					"latest_height": chain.LatestHeight,
+					"halted_since":  chain.HaltedSince.UTC().Format(time.RFC3339),
+				},
+			})
+		}
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("failed to get chains: %s", strings.Join(errs, "; "))
+		}
+		return incidents, nil
+	}
+}
+
+// KafkaBacklogCheck opens an incident while a consumer group has more than
+// maxBacklog events of the topic left to read
+func KafkaBacklogCheck(cfg config.KafkaConfig, group string, maxBacklog int64, timeout time.Duration) Check {
+	return func(ctx context.Context) ([]Incident, error) {
+		backlog, err := streaming.GroupBacklog(cfg, group, timeout)
+		if err != nil {
+			return nil, err
+		}
+		if backlog <= maxBacklog {
+			return nil, nil
+		}
+		return []Incident{{
+			Key:      "kafka_backlog:" + group,
+			Summary:  fmt.Sprintf("Kafka consumer group %s is %d events behind", group, backlog),
+			Severity: SeverityWarning,
+			Details: map[string]any{
+				"group":       group,
+				"topic":       cfg.Topic,
+				"backlog":     backlog,
+				"max_backlog": maxBacklog,
+			},
+		}}, nil
+	}
+}
@@ -0,0 +1,309 @@
+package incidents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// recorder is an integration recording its notifications
+type recorder struct {
+	fail  bool
+	calls []string
+}
+
+func (r *recorder) Name() string { return "recorder" }
+
+func (r *recorder) Trigger(ctx context.Context, source string, incident Incident) error {
+	return r.record("trigger " + incident.Key)
+}
+
+func (r *recorder) Resolve(ctx context.Context, source string, incident Incident) error {
+	return r.record("resolve " + incident.Key)
+}
+
+func (r *recorder) record(call string) error {
+	if r.fail {
+		return errors.New("unavailable")
+	}
+	r.calls = append(r.calls, call)
+	return nil
+}
+
+func TestMonitorTransitions(t *testing.T) {
+	integration := &recorder{}
+	m := newMonitor(config.IncidentConfig{Timeout: time.Second}, "test", []Integration{integration}, zap.NewNop())
+
+	var dbDown, lagging bool
+	var lagErr error
+	m.Register("database", func(ctx context.Context) ([]Incident, error) {
+		if dbDown {
+			return []Incident{{Key: "database:postgres"}}, nil
+		}
+		return nil, nil
+	})
+	m.Register("chain_lag", func(ctx context.Context) ([]Incident, error) {
+		if lagErr != nil {
+			return nil, lagErr
+		}
+		if lagging {
+			return []Incident{{Key: "chain_lag:cosmoshub"}}, nil
+		}
+		return nil, nil
+	})
+
+	steps := []struct {
+		name        string
+		apply       func()
+		unavailable bool
+		want        []string
+	}{
+		{name: "healthy", apply: func() {}},
+		{name: "database down", apply: func() { dbDown = true }, want: []string{"trigger database:postgres"}},
+		{name: "still down", apply: func() {}},
+		{name: "lagging", apply: func() { lagging = true }, want: []string{"trigger chain_lag:cosmoshub"}},
+		{name: "lag check fails", apply: func() { lagErr = errors.New("timeout"); lagging = false }},
+		{name: "recovered", apply: func() { lagErr = nil; dbDown = false }, want: []string{"resolve chain_lag:cosmoshub", "resolve database:postgres"}},
+		{name: "integration down", apply: func() { dbDown = true }, unavailable: true},
+		{name: "integration back", apply: func() {}, want: []string{"trigger database:postgres"}},
+	}
+	for _, step := range steps {
+		step.apply()
+		integration.fail = step.unavailable
+		integration.calls = nil
+		m.RunOnce(context.Background())
+
+		sort.Strings(integration.calls)
+		if !reflect.DeepEqual(integration.calls, step.want) {
+			t.Errorf("%s: calls = %v, want %v", step.name, integration.calls, step.want)
+		}
+	}
+}
+
+func TestMonitorRestart(t *testing.T) {
+	cfg := config.IncidentConfig{Timeout: time.Second, StateFile: filepath.Join(t.TempDir(), "incidents.json")}
+
+	integration := &recorder{}
+	m := newMonitor(cfg, "test", []Integration{integration}, zap.NewNop())
+	m.Register("database", func(ctx context.Context) ([]Incident, error) {
+		return []Incident{{Key: "database:postgres"}}, nil
+	})
+	m.RunOnce(context.Background())
+
+	// After a restart, the incident stays open while its check fails and is
+	// resolved once the check passes
+	integration = &recorder{}
+	m = newMonitor(cfg, "test", []Integration{integration}, zap.NewNop())
+	var checkErr error
+	m.Register("database", func(ctx context.Context) ([]Incident, error) {
+		return nil, checkErr
+	})
+
+	checkErr = errors.New("timeout")
+	m.RunOnce(context.Background())
+	if len(integration.calls) != 0 {
+		t.Errorf("calls while the check fails = %v, want none", integration.calls)
+	}
+
+	checkErr = nil
+	m.RunOnce(context.Background())
+	if want := []string{"resolve database:postgres"}; !reflect.DeepEqual(integration.calls, want) {
+		t.Errorf("calls once the check passes = %v, want %v", integration.calls, want)
+	}
+}
+
+// blockingIntegration blocks its notifications until released
+type blockingIntegration struct {
+	started, release chan struct{}
+}
+
+func (b *blockingIntegration) Name() string { return "blocking" }
+
+func (b *blockingIntegration) Trigger(ctx context.Context, source string, incident Incident) error {
+	close(b.started)
+	<-b.release
+	return nil
+}
+
+func (b *blockingIntegration) Resolve(ctx context.Context, source string, incident Incident) error {
+	return nil
+}
+
+func TestMonitorRegisterDuringNotify(t *testing.T) {
+	integration := &blockingIntegration{started: make(chan struct{}), release: make(chan struct{})}
+	m := newMonitor(config.IncidentConfig{Timeout: time.Minute}, "test", []Integration{integration}, zap.NewNop())
+	m.Register("database", func(ctx context.Context) ([]Incident, error) {
+		return []Incident{{Key: "database:postgres"}}, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		m.RunOnce(context.Background())
+		close(done)
+	}()
+	<-integration.started
+
+	registered := make(chan struct{})
+	go func() {
+		m.Register("kafka", func(ctx context.Context) ([]Incident, error) { return nil, nil })
+		close(registered)
+	}()
+	select {
+	case <-registered:
+	case <-time.After(time.Second):
+		t.Error("Register blocked on a notification in flight")
+	}
+
+	close(integration.release)
+	<-done
+}
+
+func TestChainLagCheck(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	tx, _ := store.BeginTx(ctx)
+	for _, status := range []types.ChainStatus{
+		{ChainName: "cosmoshub", LatestHeight: 1000, LastIngestedHeight: 400},
+		{ChainName: "osmosis", LatestHeight: 1000, LastIngestedHeight: 900},
+	} {
+		if err := tx.UpsertChain(ctx, &types.ChainInfo{Name: status.ChainName}); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.UpsertChainStatus(ctx, &status); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	incidents, err := ChainLagCheck(store, []string{"cosmoshub", "osmosis", "juno"}, 500)(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(incidents) != 1 || incidents[0].Key != "chain_lag:cosmoshub" || incidents[0].Details["lag"] != int64(600) {
+		t.Errorf("incidents = %+v", incidents)
+	}
+}
+
+func TestChainHaltCheck(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	haltedSince := time.Now().Add(-time.Hour)
+	tx, _ := store.BeginTx(ctx)
+	for _, status := range []types.ChainStatus{
+		{ChainName: "cosmoshub", LatestHeight: 1000, HaltedSince: &haltedSince},
+		{ChainName: "osmosis", LatestHeight: 1000},
+	} {
+		if err := tx.UpsertChain(ctx, &types.ChainInfo{Name: status.ChainName}); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.UpsertChainStatus(ctx, &status); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	incidents, err := ChainHaltCheck(store, []string{"cosmoshub", "osmosis", "juno"})(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(incidents) != 1 || incidents[0].Key != "chain_halt:cosmoshub" || incidents[0].Severity != SeverityCritical || incidents[0].Details["latest_height"] != int64(1000) {
+		t.Errorf("incidents = %+v", incidents)
+	}
+}
+
+func TestPagerDuty(t *testing.T) {
+	var events []pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event pagerDutyEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Error(err)
+		}
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	p := NewPagerDuty(config.PagerDutyConfig{RoutingKey: "key", URL: server.URL}, time.Second)
+	incident := Incident{Key: "database:postgres", Summary: "postgres is unreachable", Severity: SeverityCritical}
+	if err := p.Trigger(context.Background(), "api-1", incident); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Resolve(context.Background(), "api-1", incident); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []pagerDutyEvent{
+		{RoutingKey: "key", EventAction: "trigger", DedupKey: "database:postgres", Payload: &pagerDutyPayload{
+			Summary: "postgres is unreachable", Source: "api-1", Severity: SeverityCritical, Component: "state-mesh",
+		}},
+		{RoutingKey: "key", EventAction: "resolve", DedupKey: "database:postgres"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %+v, want %+v", events, want)
+	}
+}
+
+func TestOpsgenie(t *testing.T) {
+	var requests []string
+	var alert opsgenieAlert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "GenieKey key" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		requests = append(requests, r.URL.RequestURI())
+		if r.URL.Path == "/v2/alerts" {
+			if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+				t.Error(err)
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	o := NewOpsgenie(config.OpsgenieConfig{APIKey: "key", URL: server.URL + "/"}, time.Second)
+	incident := Incident{Key: "kafka_backlog:group", Summary: "behind", Severity: SeverityWarning, Details: map[string]any{"backlog": int64(12)}}
+	if err := o.Trigger(context.Background(), "api-1", incident); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Resolve(context.Background(), "api-1", incident); err != nil {
+		t.Fatal(err)
+	}
+
+	wantAlert := opsgenieAlert{
+		Message: "behind", Alias: "kafka_backlog:group", Description: "behind", Priority: "P3", Source: "api-1",
+		Tags: []string{"state-mesh"}, Details: map[string]string{"backlog": "12"},
+	}
+	if !reflect.DeepEqual(alert, wantAlert) {
+		t.Errorf("alert = %+v, want %+v", alert, wantAlert)
+	}
+	wantRequests := []string{"/v2/alerts", "/v2/alerts/kafka_backlog:group/close?identifierType=alias"}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("requests = %v, want %v", requests, wantRequests)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("héllo", 2); got != "h" {
+		t.Errorf("truncate() = %q, want %q", got, "h")
+	}
+	if got := truncate("hello", 10); got != "hello" {
+		t.Errorf("truncate() = %q", got)
+	}
+}
@@ -0,0 +1,185 @@
+package incidents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cosmos/state-mesh/internal/config"
+)
+
+// PagerDuty triggers and resolves incidents through the PagerDuty Events API
+// v2, deduplicated by the incident key
+type PagerDuty struct {
+	cfg    config.PagerDutyConfig
+	client *http.Client
+}
+
+// NewPagerDuty creates a PagerDuty integration whose calls time out after
+// timeout
+func NewPagerDuty(cfg config.PagerDutyConfig, timeout time.Duration) *PagerDuty {
+	return &PagerDuty{cfg: cfg, client: &http.Client{Timeout: timeout}}
+}
+
+// Name returns "pagerduty"
+func (p *PagerDuty) Name() string {
+	return "pagerduty"
+}
+
+// pagerDutyEvent is an Events API v2 event
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Component     string         `json:"component"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+// Trigger opens an incident, or updates the open incident of its key
+func (p *PagerDuty) Trigger(ctx context.Context, source string, incident Incident) error {
+	return p.send(ctx, pagerDutyEvent{
+		RoutingKey:  p.cfg.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    incident.Key,
+		Payload: &pagerDutyPayload{
+			Summary:       truncate(incident.Summary, 1024),
+			Source:        source,
+			Severity:      incident.Severity,
+			Component:     "state-mesh",
+			CustomDetails: incident.Details,
+		},
+	})
+}
+
+// Resolve resolves the incident of a key
+func (p *PagerDuty) Resolve(ctx context.Context, source string, incident Incident) error {
+	return p.send(ctx, pagerDutyEvent{
+		RoutingKey:  p.cfg.RoutingKey,
+		EventAction: "resolve",
+		DedupKey:    incident.Key,
+	})
+}
+
+func (p *PagerDuty) send(ctx context.Context, event pagerDutyEvent) error {
+	return post(ctx, p.client, p.cfg.URL, nil, event)
+}
+
+// Opsgenie creates and closes alerts through the Opsgenie Alert API,
+// deduplicated by using the incident key as the alert's alias
+type Opsgenie struct {
+	cfg    config.OpsgenieConfig
+	client *http.Client
+}
+
+// NewOpsgenie creates an Opsgenie integration whose calls time out after
+// timeout
+func NewOpsgenie(cfg config.OpsgenieConfig, timeout time.Duration) *Opsgenie {
+	return &Opsgenie{cfg: cfg, client: &http.Client{Timeout: timeout}}
+}
+
+// Name returns "opsgenie"
+func (o *Opsgenie) Name() string {
+	return "opsgenie"
+}
+
+// opsgenieAlert is an alert of the Opsgenie Alert API
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Priority    string            `json:"priority"`
+	Source      string            `json:"source"`
+	Tags        []string          `json:"tags"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+// opsgeniePriorities maps incident severities to alert priorities
+var opsgeniePriorities = map[string]string{
+	SeverityCritical: "P1",
+	SeverityError:    "P2",
+	SeverityWarning:  "P3",
+}
+
+// Trigger creates an alert, or counts another occurrence of the open alert
+// of its key
+func (o *Opsgenie) Trigger(ctx context.Context, source string, incident Incident) error {
+	alert := opsgenieAlert{
+		Message:     truncate(incident.Summary, 130),
+		Alias:       incident.Key,
+		Description: incident.Summary,
+		Priority:    opsgeniePriorities[incident.Severity],
+		Source:      source,
+		Tags:        []string{"state-mesh"},
+	}
+	if alert.Priority == "" {
+		alert.Priority = "P3"
+	}
+	if len(incident.Details) > 0 {
+		alert.Details = make(map[string]string, len(incident.Details))
+		for key, value := range incident.Details {
+			alert.Details[key] = fmt.Sprint(value)
+		}
+	}
+	return post(ctx, o.client, strings.TrimSuffix(o.cfg.URL, "/")+"/v2/alerts", o.headers(), alert)
+}
+
+// Resolve closes the alert of a key
+func (o *Opsgenie) Resolve(ctx context.Context, source string, incident Incident) error {
+	endpoint := strings.TrimSuffix(o.cfg.URL, "/") + "/v2/alerts/" + url.PathEscape(incident.Key) + "/close?identifierType=alias"
+	return post(ctx, o.client, endpoint, o.headers(), map[string]string{"source": source})
+}
+
+func (o *Opsgenie) headers() http.Header {
+	return http.Header{"Authorization": []string{"GenieKey " + o.cfg.APIKey}}
+}
+
+// post posts a JSON body. Any response other than 2xx is an error.
+func post(ctx context.Context, client *http.Client, endpoint string, header http.Header, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", req.URL.Host, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+	}
+	return nil
+}
+
+// truncate shortens s to at most n bytes without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,112 @@
+package ingester
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+var chainHalted = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "statemesh",
+	Subsystem: "ingester",
+	Name:      "chain_halted",
+	Help:      "Whether a chain's latest height has not increased for longer than the halt threshold",
+}, []string{"chain"})
+
+// haltTracker detects a chain halting from the latest heights its node
+// reports: a chain whose height has not increased for longer than the
+// threshold since its last block, while its node is not catching up, is
+// halted until the height increases again. It is used by its worker's
+// goroutine only.
+type haltTracker struct {
+	chain     string
+	threshold time.Duration // 0 disables detection
+	logger    *zap.Logger
+
+	height     int64     // the latest height seen
+	since      time.Time // the time of the block at height
+	current    *types.ChainHalt
+	unrecorded []types.ChainHalt // detected or ended halts not stored yet
+}
+
+func newHaltTracker(chain string, threshold time.Duration, logger *zap.Logger) *haltTracker {
+	return &haltTracker{
+		chain:     chain,
+		threshold: threshold,
+		logger:    logger,
+	}
+}
+
+// observe records the node status polled at now, and reports whether the
+// chain is halted
+func (t *haltTracker) observe(node *cosmos.NodeStatus, now time.Time) bool {
+	if node.LatestHeight > t.height {
+		if t.current != nil {
+			resumed := now
+			t.current.ResumedAt = &resumed
+			t.unrecorded = append(t.unrecorded, *t.current)
+			t.logger.Info("Chain resumed producing blocks",
+				zap.Int64("height", node.LatestHeight),
+				zap.Duration("halted_for", now.Sub(t.current.Since)))
+			t.current = nil
+			chainHalted.WithLabelValues(t.chain).Set(0)
+		}
+
+		// A node reporting no block time, or one ahead of the clock, is
+		// timed from the poll instead
+		t.height = node.LatestHeight
+		t.since = node.LatestBlockTime
+		if t.since.IsZero() || t.since.After(now) {
+			t.since = now
+		}
+	}
+
+	if t.current == nil && t.threshold > 0 && !node.CatchingUp && now.Sub(t.since) > t.threshold {
+		t.current = &types.ChainHalt{
+			ChainName:  t.chain,
+			Height:     t.height,
+			Since:      t.since,
+			DetectedAt: now,
+		}
+		t.unrecorded = append(t.unrecorded, *t.current)
+		t.logger.Warn("Chain halted",
+			zap.Int64("height", t.height),
+			zap.Time("since", t.since),
+			zap.Duration("threshold", t.threshold))
+		chainHalted.WithLabelValues(t.chain).Set(1)
+	}
+
+	return t.current != nil
+}
+
+// haltedSince returns the time of the last block of the halted chain, nil
+// unless it is halted
+func (t *haltTracker) haltedSince() *time.Time {
+	if t.current == nil {
+		return nil
+	}
+	since := t.current.Since
+	return &since
+}
+
+// record stores the halts detected or ended since the last call, keeping the
+// ones that fail to retry them on the next
+func (t *haltTracker) record(ctx context.Context, store storage.HaltStore) {
+	var failed []types.ChainHalt
+	for _, halt := range t.unrecorded {
+		if err := store.RecordHalt(ctx, halt); err != nil {
+			t.logger.Error("Failed to record chain halt",
+				zap.Int64("height", halt.Height),
+				zap.Error(err))
+			failed = append(failed, halt)
+		}
+	}
+	t.unrecorded = failed
+}
@@ -0,0 +1,69 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+)
+
+func TestHaltTracker(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	tracker := newHaltTracker("testchain", 5*time.Minute, zap.NewNop())
+
+	steps := []struct {
+		node   cosmos.NodeStatus
+		now    time.Time
+		halted bool
+	}{
+		{cosmos.NodeStatus{LatestHeight: 100, LatestBlockTime: at(0)}, at(1), false},
+		{cosmos.NodeStatus{LatestHeight: 100, LatestBlockTime: at(0)}, at(5), false},
+		{cosmos.NodeStatus{LatestHeight: 100, LatestBlockTime: at(0)}, at(6), true},
+		{cosmos.NodeStatus{LatestHeight: 100, LatestBlockTime: at(0)}, at(20), true},
+		{cosmos.NodeStatus{LatestHeight: 101, LatestBlockTime: at(29)}, at(30), false},
+		// A syncing node is not halted
+		{cosmos.NodeStatus{LatestHeight: 101, LatestBlockTime: at(29), CatchingUp: true}, at(40), false},
+	}
+	for i, step := range steps {
+		if got := tracker.observe(&step.node, step.now); got != step.halted {
+			t.Fatalf("step %d: observe() = %v, want %v", i, got, step.halted)
+		}
+		if got := tracker.haltedSince() != nil; got != step.halted {
+			t.Fatalf("step %d: haltedSince() set = %v, want %v", i, got, step.halted)
+		}
+	}
+
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	tracker.record(ctx, store)
+	if len(tracker.unrecorded) != 0 {
+		t.Fatalf("record() left %d halts unrecorded", len(tracker.unrecorded))
+	}
+
+	halts, err := store.GetHalts(ctx, "testchain", start)
+	if err != nil {
+		t.Fatalf("GetHalts() error = %v", err)
+	}
+	if len(halts) != 1 {
+		t.Fatalf("GetHalts() = %+v, want one halt", halts)
+	}
+	halt := halts[0]
+	if halt.Height != 100 || !halt.Since.Equal(at(0)) || !halt.DetectedAt.Equal(at(6)) || halt.ResumedAt == nil || !halt.ResumedAt.Equal(at(30)) {
+		t.Errorf("halt = %+v, want height 100 since %v, detected %v and resumed %v", halt, at(0), at(6), at(30))
+	}
+}
+
+func TestHaltTrackerDisabled(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newHaltTracker("testchain", 0, zap.NewNop())
+
+	node := &cosmos.NodeStatus{LatestHeight: 100, LatestBlockTime: start}
+	if tracker.observe(node, start.Add(24*time.Hour)) {
+		t.Error("observe() = true with halt detection disabled")
+	}
+}
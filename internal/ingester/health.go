@@ -0,0 +1,168 @@
+package ingester
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+)
+
+var (
+	modulePolls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "ingester",
+		Name:      "module_polls_total",
+		Help:      "Module polls, by result (success or failure) after retries",
+	}, []string{"chain", "module", "result"})
+
+	moduleRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "ingester",
+		Name:      "module_retries_total",
+		Help:      "Retries of failed module polls within an ingest cycle",
+	}, []string{"chain", "module"})
+
+	modulePollDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "statemesh",
+		Subsystem: "ingester",
+		Name:      "module_poll_duration_seconds",
+		Help:      "Duration of module polls, including retries",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"chain", "module"})
+
+	moduleConsecutiveFailures = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "statemesh",
+		Subsystem: "ingester",
+		Name:      "module_consecutive_failures",
+		Help:      "Consecutive failed polls of a module",
+	}, []string{"chain", "module"})
+
+	moduleDisabled = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "statemesh",
+		Subsystem: "ingester",
+		Name:      "module_disabled",
+		Help:      "Whether a module is disabled after failing repeatedly (1) or polled (0)",
+	}, []string{"chain", "module"})
+)
+
+// ModuleHealth is the health of the ingestion of a module of a chain
+type ModuleHealth struct {
+	Module              string     `json:"module"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Disabled            bool       `json:"disabled"`
+	DisabledUntil       *time.Time `json:"disabled_until,omitempty"` // unset while disabled until restart
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// moduleHealth tracks the health of the modules of a chain and disables the
+// ones failing under the disable policy
+type moduleHealth struct {
+	chain  string
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	modules map[string]*ModuleHealth
+}
+
+func newModuleHealth(chain string, logger *zap.Logger) *moduleHealth {
+	return &moduleHealth{
+		chain:   chain,
+		logger:  logger,
+		modules: make(map[string]*ModuleHealth),
+	}
+}
+
+// get returns the health of a module, creating it on first use. The caller
+// holds mu.
+func (h *moduleHealth) get(module string) *ModuleHealth {
+	health, ok := h.modules[module]
+	if !ok {
+		health = &ModuleHealth{Module: module}
+		h.modules[module] = health
+	}
+	return health
+}
+
+// disabled reports whether a module is disabled at now, re-enabling it once
+// its disable period is over
+func (h *moduleHealth) disabled(module string, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	health := h.get(module)
+	if !health.Disabled {
+		return false
+	}
+	if health.DisabledUntil == nil || now.Before(*health.DisabledUntil) {
+		return true
+	}
+
+	h.logger.Info("Re-enabling module after its disable period", zap.String("module", module))
+	health.Disabled = false
+	health.DisabledUntil = nil
+	health.ConsecutiveFailures = 0
+	moduleDisabled.WithLabelValues(h.chain, module).Set(0)
+	moduleConsecutiveFailures.WithLabelValues(h.chain, module).Set(0)
+	return false
+}
+
+// succeeded records a successful poll of a module
+func (h *moduleHealth) succeeded(module string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	health := h.get(module)
+	health.ConsecutiveFailures = 0
+	health.LastError = ""
+	modulePolls.WithLabelValues(h.chain, module, "success").Inc()
+	moduleConsecutiveFailures.WithLabelValues(h.chain, module).Set(0)
+}
+
+// failed records a failed poll of a module, disabling it once it has failed
+// disable_after times in a row under the disable policy
+func (h *moduleHealth) failed(module string, policy config.ModuleErrorsConfig, err error, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	health := h.get(module)
+	health.ConsecutiveFailures++
+	health.LastError = err.Error()
+	modulePolls.WithLabelValues(h.chain, module, "failure").Inc()
+	moduleConsecutiveFailures.WithLabelValues(h.chain, module).Set(float64(health.ConsecutiveFailures))
+
+	if policy.Policy != config.ModuleErrorDisable || policy.DisableAfter <= 0 || health.ConsecutiveFailures < policy.DisableAfter {
+		return
+	}
+
+	health.Disabled = true
+	if policy.DisableFor > 0 {
+		until := now.Add(policy.DisableFor)
+		health.DisabledUntil = &until
+	}
+	moduleDisabled.WithLabelValues(h.chain, module).Set(1)
+	h.logger.Warn("Disabling module after repeated failures",
+		zap.String("module", module),
+		zap.Int("consecutive_failures", health.ConsecutiveFailures),
+		zap.Duration("disable_for", policy.DisableFor),
+		zap.Error(err))
+}
+
+// snapshot returns the health of every polled module, by name
+func (h *moduleHealth) snapshot() []ModuleHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	modules := make([]ModuleHealth, 0, len(h.modules))
+	for _, health := range h.modules {
+		modules = append(modules, *health)
+	}
+	sort.Slice(modules, func(a, b int) bool {
+		return modules[a].Module < modules[b].Module
+	})
+	return modules
+}
@@ -2,14 +2,21 @@ package ingester
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/dedup"
+	"github.com/cosmos/state-mesh/internal/modules"
 	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/internal/telemetry"
 	"github.com/cosmos/state-mesh/pkg/cosmos"
 	"github.com/cosmos/state-mesh/pkg/types"
 )
@@ -19,13 +26,18 @@ type Ingester struct {
 	cfg              config.IngesterConfig
 	chains           []config.ChainConfig
 	storage          *storage.Manager
-	// streaming        *streaming.Manager
+	streaming        *streaming.Manager // nil when streaming is disabled
+	outbox           bool               // events are written to the outbox instead of published
 	logger           *zap.Logger
-	clients          map[string]*cosmos.Client
+	clients          map[string]cosmos.ChainClient
+	newClient        ClientFactory
 	workers          map[string]*ChainWorker
+	reconnecting     map[string]time.Time // next connection attempt by chain
 	mu               sync.RWMutex
-	ctx              context.Context
+	ctx              context.Context    // of in-flight work, canceled when a drain times out
 	cancel           context.CancelFunc
+	stopping         chan struct{}      // closed by Stop, after which no cycles start
+	stopped          bool
 	wg               sync.WaitGroup
 }
 
@@ -36,11 +48,35 @@ func New(cfg config.IngesterConfig, chains []config.ChainConfig, storage *storag
 		chains:  chains,
 		storage: storage,
 		logger:  zap.L().Named("ingester"),
-		clients: make(map[string]*cosmos.Client),
-		workers: make(map[string]*ChainWorker),
+		clients:   make(map[string]cosmos.ChainClient),
+		newClient: dialClient,
+		workers:   make(map[string]*ChainWorker),
+		reconnecting: make(map[string]time.Time),
+		stopping:  make(chan struct{}),
 	}, nil
 }
 
+// ClientFactory creates the chain client used to ingest a chain
+type ClientFactory func(chainCfg config.ChainConfig) (cosmos.ChainClient, error)
+
+// dialClient connects to the chain's gRPC endpoint
+func dialClient(chainCfg config.ChainConfig) (cosmos.ChainClient, error) {
+	return cosmos.NewClient(chainCfg.Name, chainCfg.GRPCEndpoint)
+}
+
+// UseClientFactory replaces how chain clients are created, e.g. with simulated
+// clients for load testing
+func (i *Ingester) UseClientFactory(factory ClientFactory) {
+	i.newClient = factory
+}
+
+// UseStreaming publishes the events of polled state changes, such as changed
+// validators and proposals, through manager or, with outbox, the outbox
+func (i *Ingester) UseStreaming(manager *streaming.Manager, outbox bool) {
+	i.streaming = manager
+	i.outbox = outbox
+}
+
 // FilterChains filters chains to ingest
 func (i *Ingester) FilterChains(chainNames []string) {
 	if len(chainNames) == 0 {
@@ -70,13 +106,13 @@ func (i *Ingester) FilterModules(moduleNames []string) {
 
 	nameSet := make(map[string]bool)
 	for _, name := range moduleNames {
-		nameSet[name] = true
+		nameSet[config.CanonicalModuleName(name)] = true
 	}
 
 	for j := range i.chains {
-		var filtered []string
+		var filtered []config.ModuleConfig
 		for _, module := range i.chains[j].Modules {
-			if nameSet[module] {
+			if nameSet[config.CanonicalModuleName(module.Name)] {
 				filtered = append(filtered, module)
 			}
 		}
@@ -84,32 +120,39 @@ func (i *Ingester) FilterModules(moduleNames []string) {
 	}
 }
 
-// Start starts the ingester
+// Start starts the ingester. Chains that cannot be reached are retried in the
+// background with backoff and start ingesting once they come online.
 func (i *Ingester) Start(ctx context.Context) error {
+	// Stop waits for Start, and a stopped ingester does not start
+	i.mu.Lock()
+	if i.stopped {
+		i.mu.Unlock()
+		return nil
+	}
 	i.ctx, i.cancel = context.WithCancel(ctx)
+	i.wg.Add(1)
+	i.mu.Unlock()
+	defer i.wg.Done()
 
 	// Initialize clients for each chain
+	var unreachable []config.ChainConfig
 	for _, chainCfg := range i.chains {
 		if !chainCfg.Enabled {
+			i.registerChain(chainCfg, types.ChainStatusDisabled)
 			continue
 		}
 
-		client, err := cosmos.NewClient(chainCfg.Name, chainCfg.GRPCEndpoint)
+		client, err := i.connect(chainCfg)
 		if err != nil {
-			i.logger.Error("Failed to create client for chain",
+			i.logger.Error("Failed to connect to chain",
 				zap.String("chain", chainCfg.Name),
 				zap.Error(err))
+			i.registerChain(chainCfg, types.ChainStatusUnreachable)
+			unreachable = append(unreachable, chainCfg)
 			continue
 		}
 
-		// Test connection
-		if err := client.Ping(i.ctx); err != nil {
-			i.logger.Error("Failed to ping chain",
-				zap.String("chain", chainCfg.Name),
-				zap.Error(err))
-			client.Close()
-			continue
-		}
+		i.registerChain(chainCfg, types.ChainStatusActive)
 
 		i.mu.Lock()
 		i.clients[chainCfg.Name] = client
@@ -120,45 +163,318 @@ func (i *Ingester) Start(ctx context.Context) error {
 			zap.String("endpoint", chainCfg.GRPCEndpoint))
 	}
 
+	// Create the tables of custom modules
+	if err := modules.ApplySchemas(i.ctx, i.storage, i.chains); err != nil {
+		return err
+	}
+
 	// Start workers for each chain
 	for _, chainCfg := range i.chains {
 		if !chainCfg.Enabled {
 			continue
 		}
 
+		i.mu.RLock()
 		client := i.clients[chainCfg.Name]
+		i.mu.RUnlock()
 		if client == nil {
 			continue
 		}
 
-		worker := NewChainWorker(chainCfg, client, i.storage, i.logger)
-		i.workers[chainCfg.Name] = worker
+		i.startWorker(chainCfg, client)
+	}
 
-		i.wg.Add(1)
-		go func(w *ChainWorker) {
-			defer i.wg.Done()
-			if err := w.Start(i.ctx); err != nil {
-				i.logger.Error("Chain worker error",
-					zap.String("chain", w.chainName),
-					zap.Error(err))
-			}
-		}(worker)
+	if len(unreachable) > 0 {
+		if i.cfg.ReconnectInterval > 0 {
+			i.wg.Add(1)
+			go i.reconnectLoop(unreachable)
+		} else {
+			i.logger.Warn("Reconnection disabled, unreachable chains are skipped until restart",
+				zap.Int("chains", len(unreachable)))
+		}
 	}
 
+	i.mu.RLock()
+	workers := len(i.workers)
+	i.mu.RUnlock()
+
 	i.logger.Info("Ingester started",
-		zap.Int("chains", len(i.workers)),
+		zap.Int("chains", workers),
+		zap.Int("unreachable", len(unreachable)),
 		zap.Int("workers", i.cfg.Workers))
 
 	return nil
 }
 
+// DryRun runs one ingest cycle of each enabled chain against the ingester's
+// storage, normally a dry-run store, and returns the report of each cycle.
+// No workers are started. Chains that cannot be reached are reported with the
+// connection error.
+func (i *Ingester) DryRun(ctx context.Context) ([]*types.IngestRun, error) {
+	i.ctx, i.cancel = context.WithCancel(ctx)
+	defer i.cancel()
+
+	if err := modules.ApplySchemas(i.ctx, i.storage, i.chains); err != nil {
+		return nil, err
+	}
+
+	var runs []*types.IngestRun
+	for _, chainCfg := range i.chains {
+		if !chainCfg.Enabled {
+			continue
+		}
+
+		client, err := i.connect(chainCfg)
+		if err != nil {
+			runs = append(runs, &types.IngestRun{
+				ChainName: chainCfg.Name,
+				StartedAt: time.Now(),
+				Modules:   []types.ModuleRun{},
+				Errors:    1,
+				Error:     err.Error(),
+			})
+			continue
+		}
+
+		// A failed cycle's errors are in its report
+		worker := i.newWorker(chainCfg, client)
+		worker.ingestChainState(i.ctx)
+		worker.ticker.Stop()
+		client.Close()
+		runs = append(runs, worker.lastReport())
+	}
+
+	return runs, nil
+}
+
+// connect creates a chain's client and checks that the chain answers
+func (i *Ingester) connect(chainCfg config.ChainConfig) (cosmos.ChainClient, error) {
+	client, err := i.newClient(chainCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if err := client.Ping(i.ctx); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping chain: %w", err)
+	}
+
+	return client, nil
+}
+
+// newWorker creates the worker of a connected chain
+func (i *Ingester) newWorker(chainCfg config.ChainConfig, client cosmos.ChainClient) *ChainWorker {
+	worker := NewChainWorker(chainCfg, i.cfg.PollInterval, client, i.storage, i.logger)
+	worker.env.Dedup = dedup.New(i.cfg.DedupCacheSize)
+	worker.env.Streaming = i.streaming
+	worker.env.Outbox = i.outbox
+	if i.cfg.ModuleConcurrency > 0 {
+		worker.concurrency = i.cfg.ModuleConcurrency
+	}
+	worker.errors = i.cfg.ModuleErrors
+	worker.timeout = i.cfg.ModuleTimeout
+	if chainCfg.HaltThreshold == 0 {
+		worker.halts.threshold = i.cfg.HaltThreshold
+	}
+	return worker
+}
+
+// startWorker starts ingesting a connected chain
+func (i *Ingester) startWorker(chainCfg config.ChainConfig, client cosmos.ChainClient) {
+	worker := i.newWorker(chainCfg, client)
+
+	worker.stop = i.stopping
+
+	i.mu.Lock()
+	i.workers[chainCfg.Name] = worker
+	i.mu.Unlock()
+
+	i.wg.Add(1)
+	go func(w *ChainWorker) {
+		defer i.wg.Done()
+		defer telemetry.Recover(map[string]string{"chain": w.chainName})
+		if err := w.Start(i.ctx); err != nil {
+			i.logger.Error("Chain worker error",
+				zap.String("chain", w.chainName),
+				zap.Error(err))
+		}
+	}(worker)
+}
+
+// unreachableChain is a chain waiting to be reconnected
+type unreachableChain struct {
+	cfg   config.ChainConfig
+	delay time.Duration // before the next attempt after this one fails
+	next  time.Time
+}
+
+// reconnectLoop retries connecting to chains that could not be reached,
+// doubling each chain's delay between attempts up to MaxReconnectInterval, and
+// starts a worker for each chain that comes online
+func (i *Ingester) reconnectLoop(chains []config.ChainConfig) {
+	defer i.wg.Done()
+
+	now := time.Now()
+	pending := make([]*unreachableChain, len(chains))
+	for j, chainCfg := range chains {
+		pending[j] = &unreachableChain{
+			cfg:   chainCfg,
+			delay: i.cfg.ReconnectInterval,
+			next:  now.Add(i.cfg.ReconnectInterval),
+		}
+		i.setReconnecting(chainCfg.Name, pending[j].next)
+	}
+
+	for len(pending) > 0 {
+		next := pending[0].next
+		for _, chain := range pending[1:] {
+			if chain.next.Before(next) {
+				next = chain.next
+			}
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-i.ctx.Done():
+			timer.Stop()
+			return
+		case <-i.stopping:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		remaining := pending[:0]
+		for _, chain := range pending {
+			if now.Before(chain.next) {
+				remaining = append(remaining, chain)
+				continue
+			}
+
+			client, err := i.connect(chain.cfg)
+			if err != nil {
+				if i.ctx.Err() != nil {
+					return
+				}
+				chain.delay = min(2*chain.delay, i.cfg.MaxReconnectInterval)
+				chain.next = now.Add(chain.delay)
+				i.setReconnecting(chain.cfg.Name, chain.next)
+				i.logger.Warn("Chain still unreachable",
+					zap.String("chain", chain.cfg.Name),
+					zap.Duration("retry_in", chain.delay),
+					zap.Error(err))
+				remaining = append(remaining, chain)
+				continue
+			}
+
+			i.registerChain(chain.cfg, types.ChainStatusActive)
+
+			i.mu.Lock()
+			i.clients[chain.cfg.Name] = client
+			delete(i.reconnecting, chain.cfg.Name)
+			i.mu.Unlock()
+
+			i.logger.Info("Reconnected to chain",
+				zap.String("chain", chain.cfg.Name),
+				zap.String("endpoint", chain.cfg.GRPCEndpoint))
+			i.startWorker(chain.cfg, client)
+		}
+		pending = remaining
+	}
+}
+
+// setReconnecting records when a chain waiting to be reconnected is retried
+func (i *Ingester) setReconnecting(chainName string, next time.Time) {
+	i.mu.Lock()
+	i.reconnecting[chainName] = next
+	i.mu.Unlock()
+}
+
+// WorkerDiagnostics is the runtime state of the ingestion of a chain
+type WorkerDiagnostics struct {
+	Chain       string           `json:"chain"`
+	State       string           `json:"state"`
+	NextAttempt *time.Time       `json:"next_attempt,omitempty"` // of a reconnecting chain
+	LastRun     *types.IngestRun `json:"last_run,omitempty"`     // report of the last ingest cycle
+	Modules     []ModuleHealth   `json:"modules,omitempty"`
+}
+
+// States of the ingestion of a chain
+const (
+	WorkerRunning      = "running"
+	WorkerReconnecting = "reconnecting"
+)
+
+// Diagnostics returns the state of the ingestion of each chain, by name
+func (i *Ingester) Diagnostics() any {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	workers := make([]WorkerDiagnostics, 0, len(i.workers)+len(i.reconnecting))
+	for name, worker := range i.workers {
+		workers = append(workers, WorkerDiagnostics{
+			Chain:   name,
+			State:   WorkerRunning,
+			LastRun: worker.lastReport(),
+			Modules: worker.health.snapshot(),
+		})
+	}
+	for name, next := range i.reconnecting {
+		workers = append(workers, WorkerDiagnostics{
+			Chain:       name,
+			State:       WorkerReconnecting,
+			NextAttempt: &next,
+		})
+	}
+	sort.Slice(workers, func(a, b int) bool {
+		return workers[a].Chain < workers[b].Chain
+	})
+
+	return workers
+}
+
+// registerChain records a configured chain and its status in the chains table
+func (i *Ingester) registerChain(chainCfg config.ChainConfig, status string) {
+	if err := upsertChain(i.ctx, i.storage, &types.ChainInfo{
+		Name:      chainCfg.Name,
+		ChainID:   chainCfg.ChainID,
+		Status:    status,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		i.logger.Error("Failed to register chain",
+			zap.String("chain", chainCfg.Name),
+			zap.Error(err))
+	}
+}
+
+// upsertChain writes a chain row in its own transaction
+func upsertChain(ctx context.Context, storage *storage.Manager, chain *types.ChainInfo) error {
+	tx, err := storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.State().UpsertChain(ctx, chain); err != nil {
+		return fmt.Errorf("failed to upsert chain: %w", err)
+	}
+
+	return tx.Commit()
+}
+
 // Stop stops the ingester
 func (i *Ingester) Stop(ctx context.Context) error {
-	if i.cancel != nil {
-		i.cancel()
+	i.mu.Lock()
+	if !i.stopped {
+		i.stopped = true
+		close(i.stopping)
 	}
+	cancel := i.cancel
+	i.mu.Unlock()
 
-	// Wait for workers to stop
+	// Let the cycles in flight finish their transactions until ctx is done,
+	// then abort them
 	done := make(chan struct{})
 	go func() {
 		i.wg.Wait()
@@ -169,7 +485,14 @@ func (i *Ingester) Stop(ctx context.Context) error {
 	case <-done:
 		i.logger.Info("All workers stopped")
 	case <-ctx.Done():
-		i.logger.Warn("Timeout waiting for workers to stop")
+		i.logger.Warn("Drain timed out, aborting in-flight ingest cycles")
+		if cancel != nil {
+			cancel()
+		}
+		<-done
+	}
+	if cancel != nil {
+		cancel()
 	}
 
 	// Close clients
@@ -181,7 +504,7 @@ func (i *Ingester) Stop(ctx context.Context) error {
 				zap.Error(err))
 		}
 	}
-	i.clients = make(map[string]*cosmos.Client)
+	i.clients = make(map[string]cosmos.ChainClient)
 	i.mu.Unlock()
 
 	return nil
@@ -189,34 +512,95 @@ func (i *Ingester) Stop(ctx context.Context) error {
 
 // ChainWorker handles ingestion for a single chain
 type ChainWorker struct {
-	chainName string
-	chainCfg  config.ChainConfig
-	client    *cosmos.Client
-	storage   *storage.Manager
-	logger    *zap.Logger
-	ticker    *time.Ticker
+	chainName    string
+	chainCfg     config.ChainConfig
+	client       cosmos.ChainClient
+	storage      *storage.Manager
+	logger       *zap.Logger
+	pollInterval time.Duration
+	ticker       *time.Ticker
+	lastRun      map[string]time.Time
+	epochRun     map[string]int64                  // epoch of the last run of epoch scheduled modules
+	lastEpoch    int64                             // last epoch recorded
+	modules      map[string]modules.ModuleIngester // by canonical name
+	concurrency  int                               // modules polled at once
+	errors       config.ModuleErrorsConfig         // unless a module sets its own
+	timeout      time.Duration                     // of each module poll, 0 for none
+	health       *moduleHealth
+	halts        *haltTracker
+	env          *modules.Env
+	stop         <-chan struct{} // closed to stop scheduling cycles
+
+	reportMu sync.Mutex
+	report   *types.IngestRun // of the last ingest cycle
 }
 
 // NewChainWorker creates a new chain worker
-func NewChainWorker(chainCfg config.ChainConfig, client *cosmos.Client, storage *storage.Manager, logger *zap.Logger) *ChainWorker {
+func NewChainWorker(chainCfg config.ChainConfig, pollInterval time.Duration, client cosmos.ChainClient, storage *storage.Manager, logger *zap.Logger) *ChainWorker {
+	if pollInterval <= 0 {
+		pollInterval = 10 * time.Second
+	}
+
+	logger = logger.Named("worker").With(zap.String("chain", chainCfg.Name))
+
+	instances, unknown := modules.ForChain(chainCfg)
+	for _, name := range unknown {
+		logger.Warn("Module is not registered, skipping it", zap.String("module", name))
+	}
+
 	return &ChainWorker{
-		chainName: chainCfg.Name,
-		chainCfg:  chainCfg,
-		client:    client,
-		storage:   storage,
-		logger:    logger.Named("worker").With(zap.String("chain", chainCfg.Name)),
-		ticker:    time.NewTicker(10 * time.Second), // Poll every 10 seconds
+		chainName:    chainCfg.Name,
+		chainCfg:     chainCfg,
+		client:       client,
+		storage:      storage,
+		logger:       logger,
+		pollInterval: pollInterval,
+		ticker:       time.NewTicker(pollInterval),
+		lastRun:      make(map[string]time.Time),
+		epochRun:     make(map[string]int64),
+		modules:      instances,
+		concurrency:  1,
+		health:       newModuleHealth(chainCfg.Name, logger),
+		halts:        newHaltTracker(chainCfg.Name, chainCfg.HaltThreshold, logger),
+		env: &modules.Env{
+			Chain:   chainCfg,
+			Client:  client,
+			Storage: storage,
+			Logger:  logger,
+		},
 	}
 }
 
-// Start starts the chain worker
+// moduleDue reports whether a module's interval has elapsed since its last run
+// or, for modules scheduled by epoch, whether an epoch started since. Without a
+// current epoch, epoch scheduled modules fall back to their interval.
+func (w *ChainWorker) moduleDue(module config.ModuleConfig, now time.Time, epoch *cosmos.EpochInfo) bool {
+	if module.Schedule == config.ScheduleEpoch && epoch != nil {
+		last, ok := w.epochRun[module.Name]
+		return !ok || epoch.CurrentEpoch > last
+	}
+
+	interval := module.Interval
+	if interval < w.pollInterval {
+		interval = w.pollInterval
+	}
+
+	last, ok := w.lastRun[module.Name]
+	return !ok || now.Sub(last) >= interval
+}
+
+// Start starts the chain worker. A cycle in flight when the worker is stopped
+// runs to completion unless ctx is canceled.
 func (w *ChainWorker) Start(ctx context.Context) error {
 	w.logger.Info("Starting chain worker")
+	defer w.ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			w.ticker.Stop()
+			w.logger.Info("Chain worker stopped")
+			return nil
+		case <-w.stop:
 			w.logger.Info("Chain worker stopped")
 			return nil
 		case <-w.ticker.C:
@@ -227,196 +611,332 @@ func (w *ChainWorker) Start(ctx context.Context) error {
 	}
 }
 
-// ingestChainState ingests the current state of the chain
+// ingestChainState ingests the current state of the chain and records a
+// report of the run
 func (w *ChainWorker) ingestChainState(ctx context.Context) error {
 	w.logger.Debug("Ingesting chain state")
 
+	run := &types.IngestRun{
+		ChainName: w.chainName,
+		StartedAt: time.Now(),
+		Modules:   []types.ModuleRun{},
+	}
+	calls := w.grpcCalls()
+
+	err := w.ingest(ctx, run)
+	if err != nil {
+		run.Errors++
+		run.Error = err.Error()
+	}
+	run.DurationMs = time.Since(run.StartedAt).Milliseconds()
+	run.GRPCCalls = w.grpcCalls() - calls
+	w.recordRun(ctx, run)
+	w.reportFailures(run, err)
+
+	w.reportMu.Lock()
+	w.report = run
+	w.reportMu.Unlock()
+
+	return err
+}
+
+// ingest polls the chain's modules whose interval has elapsed, filling in the
+// run report with each module's duration, rows written and gRPC calls. Up to
+// module_concurrency modules are polled at once. Each module writes in its own
+// transactions, so a failing module does not keep the others from committing
+// and a slow one does not hold back their writes; modules reading state
+// written by another may see it from the previous cycle. Failures are handled
+// by each module's error policy, and modules it disabled are skipped.
+func (w *ChainWorker) ingest(ctx context.Context, run *types.IngestRun) error {
 	// Get current height
-	height, err := w.client.GetLatestHeight(ctx)
+	node, err := w.client.GetNodeStatus(ctx)
 	if err != nil {
+		w.updateChain(ctx, types.ChainStatusUnreachable, nil, 0)
 		return fmt.Errorf("failed to get latest height: %w", err)
 	}
+	status := types.ChainStatusActive
+	if w.halts.observe(node, time.Now()) {
+		status = types.ChainStatusDegraded
+	}
+	w.updateChain(ctx, status, node, 0)
+	w.halts.record(ctx, w.storage.Halts())
+	height := node.LatestHeight
+	run.Height = height
+	epoch := w.currentEpoch(ctx)
+
+	// Collect the enabled modules whose interval has elapsed
+	type dueModule struct {
+		config   config.ModuleConfig
+		instance modules.ModuleIngester
+	}
+	now := time.Now()
+	var due []dueModule
+	for _, module := range w.chainCfg.EnabledModules() {
+		if !w.moduleDue(module, now, epoch) {
+			continue
+		}
+		instance, ok := w.modules[config.CanonicalModuleName(module.Name)]
+		if !ok || w.health.disabled(instance.Name(), now) {
+			continue
+		}
+		due = append(due, dueModule{config: module, instance: instance})
+	}
 
-	// Start transaction
-	tx, err := w.storage.BeginTx(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	results := make([]types.ModuleRun, len(due))
+	errs := make([]error, len(due))
+	slots := make(chan struct{}, w.concurrency)
+	var wg sync.WaitGroup
+	for i, module := range due {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			defer telemetry.Recover(map[string]string{"chain": w.chainName, "module": module.instance.Name()})
+			results[i], errs[i] = w.pollModule(ctx, module.config, module.instance, height)
+		}()
 	}
-	defer tx.Rollback()
+	wg.Wait()
+
+	var failed []error
+	for i, module := range due {
+		run.Modules = append(run.Modules, results[i])
+		run.RowsWritten += results[i].RowsWritten
+		if errs[i] != nil {
+			w.health.failed(module.instance.Name(), module.config.Errors.Merge(w.errors), errs[i], now)
+			failed = append(failed, fmt.Errorf("module %s: %w", module.instance.Name(), errs[i]))
+			continue
+		}
+		w.health.succeeded(module.instance.Name())
 
-	// Ingest data based on enabled modules
-	for _, module := range w.chainCfg.Modules {
-		switch module {
-		case "bank":
-			if err := w.ingestBankModule(ctx, height); err != nil {
-				w.logger.Error("Failed to ingest bank module",
-					zap.String("chain", w.chainName),
-					zap.Error(err))
-				return err
-			}
-		case "staking":
-			if err := w.ingestStakingModule(ctx, height); err != nil {
-				w.logger.Error("Failed to ingest staking module",
-					zap.String("chain", w.chainName),
-					zap.Error(err))
-				return err
-			}
-		case "distribution":
-			if err := w.ingestDistributionModule(ctx, height); err != nil {
-				w.logger.Error("Failed to ingest distribution module",
-					zap.String("chain", w.chainName),
-					zap.Error(err))
-				return err
-			}
-		case "governance":
-			if err := w.ingestGovernanceModule(ctx, height); err != nil {
-				w.logger.Error("Failed to ingest governance module",
-					zap.String("chain", w.chainName),
-					zap.Error(err))
-				return err
-			}
-		case "mint":
-			if err := w.ingestMintModule(ctx, height); err != nil {
-				w.logger.Error("Failed to ingest mint module",
-					zap.String("chain", w.chainName),
-					zap.Error(err))
-				return err
-			}
-		case "slashing":
-			if err := w.ingestSlashingModule(ctx, height); err != nil {
-				w.logger.Error("Failed to ingest slashing module",
-					zap.String("chain", w.chainName),
-					zap.Error(err))
-				return err
-			}
-		default:
-			w.logger.Debug("Unknown module",
-				zap.String("chain", w.chainName),
-				zap.String("module", module))
+		w.lastRun[module.config.Name] = now
+		if epoch != nil {
+			w.epochRun[module.config.Name] = epoch.CurrentEpoch
 		}
 	}
-
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if len(failed) > 0 {
+		return errors.Join(failed...)
 	}
 
+	w.updateChain(ctx, status, node, height)
+
 	return nil
 }
 
-// ingestBankModule ingests bank module state
-func (w *ChainWorker) ingestBankModule(ctx context.Context, height int64) error {
-	// Get total supply
-	supply, err := w.client.GetTotalSupply(ctx, "uatom")
-	if err != nil {
-		return fmt.Errorf("failed to get total supply: %w", err)
+// pollModule polls one module at height, retrying it under the retry policy,
+// and reports its duration, rows written and gRPC calls over all attempts
+func (w *ChainWorker) pollModule(ctx context.Context, module config.ModuleConfig, instance modules.ModuleIngester, height int64) (types.ModuleRun, error) {
+	policy := module.Errors.Merge(w.errors)
+
+	var rows storage.RowCounter
+	var calls atomic.Int64
+	pollCtx := cosmos.WithCallCount(storage.WithRowCounter(ctx, &rows), &calls)
+
+	poll := func() error {
+		if w.timeout <= 0 {
+			return instance.Poll(pollCtx, w.env, module, height)
+		}
+		ctx, cancel := context.WithTimeout(pollCtx, w.timeout)
+		defer cancel()
+		return instance.Poll(ctx, w.env, module, height)
 	}
 
-	// For now, we'll just log the supply
-	// In a real implementation, we'd track all account balances
-	if !supply.Amount.IsZero() {
-		w.logger.Debug("Bank module state",
-			zap.String("supply", supply.String()),
-			zap.Int64("height", height))
+	start := time.Now()
+	err := poll()
+	retries := 0
+retry:
+	for backoff := policy.RetryBackoff; err != nil && policy.Policy == config.ModuleErrorRetry && retries < policy.Retries; backoff *= 2 {
+		w.logger.Warn("Retrying failed module",
+			zap.String("module", instance.Name()),
+			zap.Int("retry", retries+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			break retry
+		case <-time.After(backoff):
+		}
+		retries++
+		moduleRetries.WithLabelValues(w.chainName, instance.Name()).Inc()
+		err = poll()
+	}
+	duration := time.Since(start)
+	modulePollDuration.WithLabelValues(w.chainName, instance.Name()).Observe(duration.Seconds())
+
+	moduleRun := types.ModuleRun{
+		Module:      instance.Name(),
+		DurationMs:  duration.Milliseconds(),
+		RowsWritten: rows.Rows(),
+		GRPCCalls:   calls.Load(),
+		Retries:     retries,
+		Writes:      rows.Tables(),
+	}
+	if err != nil {
+		moduleRun.Error = err.Error()
+		w.logger.Error("Failed to ingest module",
+			zap.String("chain", w.chainName),
+			zap.String("module", instance.Name()),
+			zap.Error(err))
 	}
+	return moduleRun, err
+}
 
-	return nil
+// lastReport returns the report of the worker's last ingest cycle, or nil
+// before the first one
+func (w *ChainWorker) lastReport() *types.IngestRun {
+	w.reportMu.Lock()
+	defer w.reportMu.Unlock()
+	return w.report
 }
 
-// ingestStakingModule ingests staking module state
-func (w *ChainWorker) ingestStakingModule(ctx context.Context, height int64) error {
-	// Get all validators
-	validators, err := w.client.GetValidators(ctx, "")
-	if err != nil {
-		return fmt.Errorf("failed to get validators: %w", err)
+// grpcCalls returns the number of gRPC calls made by the chain client, or 0
+// when it does not count them
+func (w *ChainWorker) grpcCalls() int64 {
+	if counter, ok := w.client.(cosmos.CallCounter); ok {
+		return counter.GRPCCalls()
 	}
+	return 0
+}
 
-	// Start transaction
-	tx, err := w.storage.BeginTx(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+// reportFailures tracks the consecutive failures of the chain and of the
+// modules of an ingest run for error reporting
+func (w *ChainWorker) reportFailures(run *types.IngestRun, err error) {
+	moduleFailed := false
+	for _, module := range run.Modules {
+		if module.Error == "" {
+			telemetry.IngestSucceeded(w.chainName, module.Module)
+			continue
+		}
+		moduleFailed = true
+		telemetry.IngestFailed(w.chainName, module.Module, errors.New(module.Error))
 	}
-	defer tx.Rollback()
 
-	now := time.Now()
+	switch {
+	case err == nil:
+		telemetry.IngestSucceeded(w.chainName, "")
+	case !moduleFailed:
+		telemetry.IngestFailed(w.chainName, "", err)
+	}
+}
 
-	// Process validators
-	for _, val := range validators {
-		validator := &types.Validator{
-			ChainName:       w.chainName,
-			OperatorAddress: val.OperatorAddress,
-			ConsensusPubkey: val.ConsensusPubkey.String(),
-			Jailed:          val.Jailed,
-			Status:          val.Status.String(),
-			Tokens:          val.Tokens.String(),
-			DelegatorShares: val.DelegatorShares.String(),
-			Description: types.ValidatorDescription{
-				Moniker:         val.Description.Moniker,
-				Identity:        val.Description.Identity,
-				Website:         val.Description.Website,
-				SecurityContact: val.Description.SecurityContact,
-				Details:         val.Description.Details,
-			},
-			UnbondingHeight: val.UnbondingHeight,
-			UnbondingTime:   val.UnbondingTime,
-			Commission: types.ValidatorCommission{
-				Rate:          val.Commission.Rate.String(),
-				MaxRate:       val.Commission.MaxRate.String(),
-				MaxChangeRate: val.Commission.MaxChangeRate.String(),
-			},
-			MinSelfDelegation: val.MinSelfDelegation.String(),
-			Height:            height,
-			UpdatedAt:         now,
-		}
+// recordRun stores the report of an ingest run. Reports are not logged to the
+// write-ahead log, so they are skipped while the state store is unreachable.
+func (w *ChainWorker) recordRun(ctx context.Context, run *types.IngestRun) {
+	if ctx.Err() != nil || w.storage.Buffering() {
+		return
+	}
+	if err := w.storage.IngestRuns().InsertIngestRun(ctx, run); err != nil {
+		w.logger.Error("Failed to record ingest run", zap.Error(err))
+	}
+}
 
-		if err := tx.Postgres().UpsertValidator(ctx, validator); err != nil {
-			return fmt.Errorf("failed to upsert validator: %w", err)
-		}
+// currentEpoch returns the current epoch of the chain's configured epoch
+// identifier, recording it when it is new, or nil when the chain has no epoch
+// or it could not be queried
+func (w *ChainWorker) currentEpoch(ctx context.Context) *cosmos.EpochInfo {
+	if w.chainCfg.Epoch == "" {
+		return nil
 	}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	client, ok := w.client.(cosmos.EpochsClient)
+	if !ok {
+		w.logger.Warn("Chain client does not support epoch queries", zap.String("epoch", w.chainCfg.Epoch))
+		return nil
 	}
 
-	w.logger.Debug("Staking module state ingested",
-		zap.Int("validators", len(validators)),
-		zap.Int64("height", height))
+	infos, err := client.GetEpochInfos(ctx)
+	if err != nil {
+		w.logger.Warn("Failed to get current epoch", zap.Error(err))
+		return nil
+	}
 
+	for i := range infos {
+		epoch := &infos[i]
+		if epoch.Identifier != w.chainCfg.Epoch {
+			continue
+		}
+		if epoch.CurrentEpoch > w.lastEpoch {
+			w.recordEpoch(ctx, epoch)
+		}
+		return epoch
+	}
+
+	w.logger.Warn("Epoch identifier not found on chain", zap.String("epoch", w.chainCfg.Epoch))
 	return nil
 }
 
-// ingestDistributionModule ingests distribution module state
-func (w *ChainWorker) ingestDistributionModule(ctx context.Context, height int64) error {
-	// Distribution module ingestion would go here
-	// For now, just log
-	w.logger.Debug("Distribution module state ingested", zap.Int64("height", height))
-	return nil
+// recordEpoch records the start of a new epoch for analytics
+func (w *ChainWorker) recordEpoch(ctx context.Context, epoch *cosmos.EpochInfo) {
+	w.logger.Info("Epoch started",
+		zap.String("epoch", epoch.Identifier),
+		zap.Int64("number", epoch.CurrentEpoch),
+		zap.Int64("start_height", epoch.CurrentEpochStartHeight))
+
+	if w.storage.ClickHouse() != nil {
+		err := w.storage.ClickHouse().InsertEpochs(ctx, []types.Epoch{{
+			ChainName:       w.chainName,
+			Identifier:      epoch.Identifier,
+			Number:          epoch.CurrentEpoch,
+			StartHeight:     epoch.CurrentEpochStartHeight,
+			StartTime:       epoch.CurrentEpochStartTime,
+			DurationSeconds: int64(epoch.Duration / time.Second),
+		}})
+		if err != nil {
+			w.logger.Error("Failed to record epoch", zap.Error(err))
+			return
+		}
+	}
+
+	w.lastEpoch = epoch.CurrentEpoch
 }
 
-// ingestGovernanceModule ingests governance module state
-func (w *ChainWorker) ingestGovernanceModule(ctx context.Context, height int64) error {
-	// Get all proposals
-	proposals, err := w.client.GetProposals(ctx, 0) // 0 = all statuses
+// updateChain records the chain's status and, when the node answered, its sync
+// status. A non-zero ingested height marks that height as fully ingested.
+func (w *ChainWorker) updateChain(ctx context.Context, status string, node *cosmos.NodeStatus, ingested int64) {
+	tx, err := w.storage.BeginTx(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get proposals: %w", err)
+		w.logger.Error("Failed to update chain status", zap.Error(err))
+		return
 	}
+	defer tx.Rollback()
 
-	w.logger.Debug("Governance module state ingested",
-		zap.Int("proposals", len(proposals)),
-		zap.Int64("height", height))
+	now := time.Now()
+	chain := &types.ChainInfo{
+		Name:      w.chainName,
+		ChainID:   w.chainCfg.ChainID,
+		Status:    status,
+		UpdatedAt: now,
+	}
+	if node != nil {
+		chain.LatestHeight = node.LatestHeight
+		chain.LatestTime = node.LatestBlockTime
+	}
 
-	return nil
-}
+	if err := tx.State().UpsertChain(ctx, chain); err != nil {
+		w.logger.Error("Failed to update chain status", zap.Error(err))
+		return
+	}
 
-// ingestMintModule ingests mint module state
-func (w *ChainWorker) ingestMintModule(ctx context.Context, height int64) error {
-	w.logger.Debug("Mint module state ingested", zap.Int64("height", height))
-	return nil
-}
+	if node != nil {
+		err := tx.State().UpsertChainStatus(ctx, &types.ChainStatus{
+			ChainName:          w.chainName,
+			LatestHeight:       node.LatestHeight,
+			LatestBlockTime:    node.LatestBlockTime,
+			LastIngestedHeight: ingested,
+			CatchingUp:         node.CatchingUp,
+			NodeVersion:        node.NodeVersion,
+			AppVersion:         node.AppVersion,
+			HaltedSince:        w.halts.haltedSince(),
+			UpdatedAt:          now,
+		})
+		if err != nil {
+			w.logger.Error("Failed to update chain sync status", zap.Error(err))
+			return
+		}
+	}
 
-// ingestSlashingModule ingests slashing module state
-func (w *ChainWorker) ingestSlashingModule(ctx context.Context, height int64) error {
-	w.logger.Debug("Slashing module state ingested", zap.Int64("height", height))
-	return nil
+	if err := tx.Commit(); err != nil {
+		w.logger.Error("Failed to update chain status", zap.Error(err))
+	}
 }
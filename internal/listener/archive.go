@@ -0,0 +1,102 @@
+package listener
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+// archive keeps every raw state change received in ClickHouse in batches
+type archive struct {
+	store         *storage.ClickHouseStore
+	changes       chan types.ArchivedStateChange
+	batchSize     int
+	flushInterval time.Duration
+	logger        *zap.Logger
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// newArchive creates an archive and starts its flush loop
+func newArchive(cfg config.ArchiveConfig, store *storage.ClickHouseStore, logger *zap.Logger) *archive {
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &archive{
+		store:         store,
+		changes:       make(chan types.ArchivedStateChange, cfg.BufferSize),
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		logger:        logger.Named("archive"),
+		cancel:        cancel,
+	}
+
+	a.wg.Add(1)
+	go a.run(ctx)
+	return a
+}
+
+// record queues a change without blocking the node
+func (a *archive) record(change *StateChange, sequence int64) {
+	select {
+	case a.changes <- types.ArchivedStateChange{StateChange: *change, Sequence: sequence}:
+	default:
+		archiveDropped.WithLabelValues(change.ChainName).Inc()
+	}
+}
+
+// run writes queued changes whenever a batch fills up or the flush interval
+// passes, and writes what is left when stopped
+func (a *archive) run(ctx context.Context) {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]types.ArchivedStateChange, 0, a.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		writeCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := a.store.ArchiveStateChanges(writeCtx, batch); err != nil {
+			a.logger.Error("Failed to archive state changes", zap.Int("changes", len(batch)), zap.Error(err))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case change := <-a.changes:
+			batch = append(batch, change)
+			if len(batch) >= a.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			for {
+				select {
+				case change := <-a.changes:
+					batch = append(batch, change)
+					if len(batch) >= a.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// stop writes the queued changes and stops the flush loop
+func (a *archive) stop() {
+	a.cancel()
+	a.wg.Wait()
+}
@@ -0,0 +1,167 @@
+package listener
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/diskqueue"
+	"go.uber.org/zap"
+)
+
+// sampleWatermark is the queue fill ratio above which the sample policy starts
+// discarding changes
+const sampleWatermark = 0.8
+
+// spillReplayInterval is how often spilled changes are fed back to the queue
+const spillReplayInterval = 100 * time.Millisecond
+
+// enqueue queues a received state change, applying the backpressure policy
+// when the queue is full
+func (sl *StateListener) enqueue(change *StateChange) {
+	switch sl.backpressure.Policy {
+	case config.BackpressureBlock:
+		select {
+		case sl.stateChanges <- change:
+			return
+		default:
+		}
+
+		timer := time.NewTimer(sl.backpressure.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case sl.stateChanges <- change:
+		case <-timer.C:
+			sl.drop(change, dropTimeout)
+		case <-sl.ctx.Done():
+			sl.drop(change, dropShutdown)
+		}
+
+	case config.BackpressureSpill:
+		// Once spilling, later changes queue behind the spilled ones so that
+		// changes are processed in order
+		if !sl.spilling.Load() {
+			select {
+			case sl.stateChanges <- change:
+				return
+			default:
+				sl.spilling.Store(true)
+			}
+		}
+		sl.spillChange(change)
+
+	case config.BackpressureSample:
+		if float64(len(sl.stateChanges)) >= sampleWatermark*float64(cap(sl.stateChanges)) &&
+			rand.Float64() >= sl.backpressure.SampleRate {
+			sl.drop(change, dropSampled)
+			return
+		}
+		select {
+		case sl.stateChanges <- change:
+		default:
+			sl.drop(change, dropQueueFull)
+		}
+
+	default:
+		select {
+		case sl.stateChanges <- change:
+		default:
+			sl.drop(change, dropQueueFull)
+		}
+	}
+}
+
+// route hands a state change to its chain worker. Under the block and spill
+// policies the router waits for room, pushing backpressure back to the main
+// queue; otherwise changes for a full worker are dropped.
+func (sl *StateListener) route(worker *ListenerWorker, change *StateChange) {
+	switch sl.backpressure.Policy {
+	case config.BackpressureBlock, config.BackpressureSpill:
+		select {
+		case worker.changes <- change:
+		case <-sl.ctx.Done():
+		}
+	default:
+		select {
+		case worker.changes <- change:
+		default:
+			sl.drop(change, dropQueueFull)
+		}
+	}
+}
+
+// drop counts and logs a discarded state change
+func (sl *StateListener) drop(change *StateChange, reason string) {
+	sl.dropped.Add(1)
+	changesDropped.WithLabelValues(change.ChainName, reason).Inc()
+	sl.logger.Warn("State change queue full, dropping change",
+		zap.String("chain", change.ChainName),
+		zap.String("store", change.StoreKey),
+		zap.Int64("height", change.Height),
+		zap.String("reason", reason))
+}
+
+// spillChange writes a state change to the spill queue
+func (sl *StateListener) spillChange(change *StateChange) {
+	err := sl.spill.Append(change)
+	if errors.Is(err, diskqueue.ErrFull) {
+		sl.drop(change, dropSpillFull)
+		return
+	}
+	if err != nil {
+		sl.logger.Error("Failed to spill state change", zap.Error(err))
+		sl.drop(change, dropSpillFull)
+		return
+	}
+
+	sl.spilled.Add(1)
+	changesSpilled.WithLabelValues(change.ChainName).Inc()
+}
+
+// replaySpill feeds spilled changes back into the queue in order, waiting
+// for room, and stops spilling once the spill queue is empty
+func (sl *StateListener) replaySpill() {
+	ticker := time.NewTicker(spillReplayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sl.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !sl.spilling.Load() && sl.spill.Empty() {
+			continue
+		}
+
+		_, err := sl.spill.Replay(func(line []byte) error {
+			var change StateChange
+			if err := json.Unmarshal(line, &change); err != nil {
+				// A change torn by a crash mid-write
+				sl.logger.Error("Discarding undecodable spilled state change", zap.Error(err))
+				return nil
+			}
+
+			select {
+			case sl.stateChanges <- &change:
+				return nil
+			case <-sl.ctx.Done():
+				return sl.ctx.Err()
+			}
+		})
+		if err != nil {
+			if sl.ctx.Err() == nil {
+				sl.logger.Error("Failed to replay spilled state changes", zap.Error(err))
+			}
+			continue
+		}
+
+		// Changes spilled during the replay are picked up on the next tick
+		if sl.spill.Empty() {
+			sl.spilling.Store(false)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package listener
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Value encodings of decoded state changes
+const (
+	encodingJSON = "json"
+	encodingHex  = "hex"
+)
+
+// decoders decodes the changes to stores without a module by the configured
+// rules. It is read-only once created and shared by the workers.
+type decoders struct {
+	stores map[string]*storeDecoder
+}
+
+// storeDecoder holds the rules of one store, longest prefix first
+type storeDecoder struct {
+	rules []decoderRule
+	types *dynamicpb.Types
+}
+
+// decoderRule is a rule with its prefix and message resolved
+type decoderRule struct {
+	prefix  []byte
+	entity  string
+	message protoreflect.MessageType // nil records values as hex
+}
+
+// newDecoders loads the decoders of the configured stores
+func newDecoders(cfgs []config.DecoderConfig) (*decoders, error) {
+	d := &decoders{stores: make(map[string]*storeDecoder, len(cfgs))}
+	for _, cfg := range cfgs {
+		store, err := newStoreDecoder(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s decoder: %w", cfg.Store, err)
+		}
+		d.stores[config.CanonicalModuleName(cfg.Store)] = store
+	}
+	return d, nil
+}
+
+// newStoreDecoder resolves the rules of a store against its descriptors
+func newStoreDecoder(cfg config.DecoderConfig) (*storeDecoder, error) {
+	sd := &storeDecoder{}
+	if cfg.Descriptors != "" {
+		data, err := os.ReadFile(cfg.Descriptors)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read descriptors: %w", err)
+		}
+		var set descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(data, &set); err != nil {
+			return nil, fmt.Errorf("failed to parse descriptors: %w", err)
+		}
+		files, err := protodesc.NewFiles(&set)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve descriptors: %w", err)
+		}
+		sd.types = dynamicpb.NewTypes(files)
+	}
+
+	for _, rule := range cfg.Rules {
+		prefix, err := rule.DecodePrefix()
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix %q: %w", rule.Prefix, err)
+		}
+		resolved := decoderRule{prefix: prefix, entity: rule.Entity}
+		if rule.Message != "" {
+			if sd.types == nil {
+				return nil, fmt.Errorf("no descriptors to decode %s", rule.Message)
+			}
+			resolved.message, err = sd.types.FindMessageByName(protoreflect.FullName(rule.Message))
+			if err != nil {
+				return nil, fmt.Errorf("unknown message %s: %w", rule.Message, err)
+			}
+		}
+		sd.rules = append(sd.rules, resolved)
+	}
+	sort.SliceStable(sd.rules, func(i, j int) bool {
+		return len(sd.rules[i].prefix) > len(sd.rules[j].prefix)
+	})
+
+	return sd, nil
+}
+
+// handles reports whether changes to a store are decoded
+func (d *decoders) handles(store string) bool {
+	_, ok := d.stores[config.CanonicalModuleName(store)]
+	return ok
+}
+
+// decode turns a change into a generic record. When its value cannot be decoded
+// as the rule's message, the record holds the value as hex and the error is
+// returned alongside it.
+func (d *decoders) decode(change *StateChange) (*types.DecodedStateChange, error) {
+	decoded := &types.DecodedStateChange{
+		ChainName: change.ChainName,
+		StoreKey:  change.StoreKey,
+		Key:       renderKey(change.Key),
+		Delete:    change.Delete,
+		Height:    change.Height,
+		Timestamp: change.Timestamp,
+	}
+
+	store := d.stores[config.CanonicalModuleName(change.StoreKey)]
+	var rule *decoderRule
+	for i := range store.rules {
+		if bytes.HasPrefix(change.Key, store.rules[i].prefix) {
+			rule = &store.rules[i]
+			break
+		}
+	}
+	if rule != nil {
+		decoded.Entity = rule.entity
+	}
+
+	if change.Delete {
+		return decoded, nil
+	}
+
+	decoded.Value, decoded.Encoding = hex.EncodeToString(change.Value), encodingHex
+	if rule == nil || rule.message == nil {
+		return decoded, nil
+	}
+
+	message := rule.message.New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: store.types}).Unmarshal(change.Value, message); err != nil {
+		return decoded, fmt.Errorf("failed to decode %s value: %w", rule.entity, err)
+	}
+	value, err := protojson.MarshalOptions{Resolver: store.types}.Marshal(message)
+	if err != nil {
+		return decoded, fmt.Errorf("failed to encode %s value: %w", rule.entity, err)
+	}
+	decoded.Value, decoded.Encoding = string(value), encodingJSON
+
+	return decoded, nil
+}
+
+// renderKey returns a key as is when printable, or 0x and its hex encoding
+func renderKey(key []byte) string {
+	if utf8.Valid(key) && !bytes.ContainsFunc(key, func(r rune) bool { return !unicode.IsPrint(r) }) {
+		return string(key)
+	}
+	return "0x" + hex.EncodeToString(key)
+}
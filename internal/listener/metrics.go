@@ -0,0 +1,66 @@
+package listener
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reasons a state change is dropped
+const (
+	dropQueueFull = "queue_full"
+	dropTimeout   = "timeout"
+	dropSampled   = "sampled"
+	dropSpillFull = "spill_full"
+	dropShutdown  = "shutdown"
+)
+
+var (
+	changesReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "state_listener",
+		Name:      "changes_received_total",
+		Help:      "State changes received by the state listener",
+	}, []string{"chain"})
+
+	changesDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "state_listener",
+		Name:      "changes_dropped_total",
+		Help:      "State changes dropped by the state listener, by reason",
+	}, []string{"chain", "reason"})
+
+	changesPrioritized = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "state_listener",
+		Name:      "changes_prioritized_total",
+		Help:      "State changes processed through the priority lane",
+	}, []string{"chain"})
+
+	changesSpilled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "state_listener",
+		Name:      "changes_spilled_total",
+		Help:      "State changes spilled to disk because the state listener queue was full",
+	}, []string{"chain"})
+
+	changesDecoded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "state_listener",
+		Name:      "changes_decoded_total",
+		Help:      "Changes to stores without a module recorded by the configured decoders, by entity",
+	}, []string{"chain", "store", "entity"})
+
+	decodeFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "state_listener",
+		Name:      "decode_failures_total",
+		Help:      "Values that did not decode as their rule's message and were recorded as hex",
+	}, []string{"chain", "store"})
+
+	archiveDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "state_listener",
+		Name:      "archive_dropped_total",
+		Help:      "State changes not archived because the archive buffer was full",
+	}, []string{"chain"})
+)
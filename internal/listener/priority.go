@@ -0,0 +1,56 @@
+package listener
+
+import (
+	"strings"
+
+	"github.com/cosmos/state-mesh/internal/config"
+)
+
+// prioritizer selects the state changes that take the priority lane. Lanes are
+// chosen by store and key only, so changes to the same key always share a lane
+// and stay in order.
+type prioritizer struct {
+	stores   map[string]bool
+	prefixes []string
+	watched  map[string]bool
+}
+
+// newPrioritizer creates a prioritizer from configuration
+func newPrioritizer(cfg config.PriorityConfig) *prioritizer {
+	p := &prioritizer{
+		stores:   make(map[string]bool, len(cfg.Stores)),
+		prefixes: cfg.KeyPrefixes,
+		watched:  make(map[string]bool, len(cfg.WatchedAddresses)),
+	}
+	for _, store := range cfg.Stores {
+		p.stores[config.CanonicalModuleName(store)] = true
+	}
+	for _, address := range cfg.WatchedAddresses {
+		p.watched[address] = true
+	}
+	return p
+}
+
+// match reports whether a state change takes the priority lane
+func (p *prioritizer) match(change *StateChange) bool {
+	if p.stores[config.CanonicalModuleName(change.StoreKey)] {
+		return true
+	}
+
+	key := string(change.Key)
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	if len(p.watched) > 0 {
+		for _, segment := range strings.Split(key, "/") {
+			if p.watched[segment] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/dedup"
+	"github.com/cosmos/state-mesh/internal/diskqueue"
+	"github.com/cosmos/state-mesh/internal/modules"
 	"github.com/cosmos/state-mesh/internal/storage"
 	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/internal/telemetry"
 	"github.com/cosmos/state-mesh/pkg/types"
 	"go.uber.org/zap"
 )
@@ -20,12 +25,28 @@ type StateListener struct {
 	streaming *streaming.Manager
 	logger    *zap.Logger
 	
-	// State change channels
-	stateChanges chan *StateChange
+	// State change channels. Priority changes have their own lane that is
+	// always served first and never dropped.
+	stateChanges    chan *StateChange
+	priorityChanges chan *StateChange
+	priority        *prioritizer
+	decoders        *decoders
+	archive         *archive
+	backpressure    config.BackpressureConfig
+	spill           *diskqueue.Queue
+	spilling        atomic.Bool
 	
 	// Worker management
 	workers    map[string]*ListenerWorker
 	workersMux sync.RWMutex
+
+	// Counters
+	received    atomic.Int64
+	dropped     atomic.Int64
+	spilled     atomic.Int64
+	prioritized atomic.Int64
+	processed   atomic.Int64
+	failed      atomic.Int64
 	
 	// Shutdown
 	ctx    context.Context
@@ -34,46 +55,96 @@ type StateListener struct {
 }
 
 // StateChange represents a state change event from ADR-038
-type StateChange struct {
-	ChainName string
-	StoreKey  string
-	Key       []byte
-	Value     []byte
-	Delete    bool
-	Height    int64
-	Timestamp time.Time
+type StateChange = types.StateChange
+
+// Stats counts the state changes handled by the listener. Spilled changes are
+// also counted as processed, failed or dropped once replayed.
+type Stats struct {
+	Received    int64 `json:"received"`
+	Dropped     int64 `json:"dropped"`
+	Spilled     int64 `json:"spilled"`
+	Prioritized int64 `json:"prioritized"`
+	Processed   int64 `json:"processed"`
+	Failed      int64 `json:"failed"`
 }
 
 // ListenerWorker handles state changes for a specific chain
 type ListenerWorker struct {
 	chainName string
 	cfg       config.ChainConfig
-	storage   *storage.Manager
-	streaming *streaming.Manager
 	logger    *zap.Logger
+
+	// Module handlers by canonical name, and what they write to
+	modules map[string]modules.ModuleIngester
+	env     *modules.Env
+
+	// Decoders of stores without a module
+	decoders *decoders
 	
 	// State change processing
-	changes chan *StateChange
+	changes   chan *StateChange
+	priority  chan *StateChange
+	processed *atomic.Int64
+	failed    *atomic.Int64
+	timeout   time.Duration // of each state change, 0 for none
 	
 	// Shutdown
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
-// NewStateListener creates a new state listener
-func NewStateListener(cfg config.Config, storage *storage.Manager, streaming *streaming.Manager, logger *zap.Logger) *StateListener {
+// NewStateListener creates a new state listener. It fails when the decoders of
+// stores without a module cannot be loaded.
+func NewStateListener(cfg config.Config, storage *storage.Manager, streaming *streaming.Manager, logger *zap.Logger) (*StateListener, error) {
+	decoders, err := newDecoders(cfg.StateListener.Decoders)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
-	
-	return &StateListener{
-		cfg:          cfg,
-		storage:      storage,
-		streaming:    streaming,
-		logger:       logger.Named("state_listener"),
-		stateChanges: make(chan *StateChange, 10000), // Buffer for high throughput
-		workers:      make(map[string]*ListenerWorker),
-		ctx:          ctx,
-		cancel:       cancel,
+
+	bufferSize := cfg.StateListener.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = 10000
 	}
+
+	priorityBufferSize := cfg.StateListener.Priority.BufferSize
+	if priorityBufferSize <= 0 {
+		priorityBufferSize = 1000
+	}
+
+	sl := &StateListener{
+		cfg:             cfg,
+		storage:         storage,
+		streaming:       streaming,
+		logger:          logger.Named("state_listener"),
+		stateChanges:    make(chan *StateChange, bufferSize), // Buffer for high throughput
+		priorityChanges: make(chan *StateChange, priorityBufferSize),
+		priority:        newPrioritizer(cfg.StateListener.Priority),
+		backpressure:    cfg.StateListener.Backpressure,
+		workers:         make(map[string]*ListenerWorker),
+		decoders:        decoders,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+
+	if cfg.StateListener.Archive.Enabled && storage.ClickHouse() != nil {
+		sl.archive = newArchive(cfg.StateListener.Archive, storage.ClickHouse(), sl.logger)
+	}
+
+	if sl.backpressure.Policy == config.BackpressureSpill {
+		spill, err := diskqueue.New(sl.backpressure.SpillDir, sl.backpressure.SpillMaxBytes, false)
+		if err != nil {
+			sl.logger.Warn("Failed to open spill queue, dropping changes when full", zap.Error(err))
+			sl.backpressure.Policy = config.BackpressureDrop
+		} else {
+			sl.spill = spill
+			// Changes spilled by a previous run go first
+			sl.spilling.Store(!spill.Empty())
+		}
+	}
+
+	return sl, nil
 }
 
 // Start starts the state listener
@@ -94,6 +165,7 @@ func (sl *StateListener) Start(ctx context.Context) error {
 		sl.wg.Add(1)
 		go func(w *ListenerWorker) {
 			defer sl.wg.Done()
+			defer telemetry.Recover(map[string]string{"chain": w.chainName})
 			if err := w.start(sl.ctx); err != nil {
 				sl.logger.Error("Worker failed",
 					zap.String("chain", w.chainName),
@@ -106,8 +178,17 @@ func (sl *StateListener) Start(ctx context.Context) error {
 	sl.wg.Add(1)
 	go func() {
 		defer sl.wg.Done()
+		defer telemetry.Recover(nil)
 		sl.processStateChanges()
 	}()
+
+	if sl.spill != nil {
+		sl.wg.Add(1)
+		go func() {
+			defer sl.wg.Done()
+			sl.replaySpill()
+		}()
+	}
 	
 	sl.logger.Info("State Listener started")
 	return nil
@@ -120,13 +201,75 @@ func (sl *StateListener) Stop() error {
 	sl.cancel()
 	sl.wg.Wait()
 	
+	if sl.archive != nil {
+		sl.archive.stop()
+	}
+
 	// Close channels
 	close(sl.stateChanges)
+	close(sl.priorityChanges)
 	
 	sl.logger.Info("State Listener stopped")
 	return nil
 }
 
+// Stats returns the number of state changes received, dropped or spilled on
+// full queues, processed and failed since the listener was created
+func (sl *StateListener) Stats() Stats {
+	return Stats{
+		Received:    sl.received.Load(),
+		Dropped:     sl.dropped.Load(),
+		Spilled:     sl.spilled.Load(),
+		Prioritized: sl.prioritized.Load(),
+		Processed:   sl.processed.Load(),
+		Failed:      sl.failed.Load(),
+	}
+}
+
+// QueueDepth is the number of state changes waiting in a queue and its capacity
+type QueueDepth struct {
+	Length   int `json:"length"`
+	Capacity int `json:"capacity"`
+}
+
+// Diagnostics is the runtime state of the listener: its counters, the depth
+// of its queues and of each chain worker's
+type Diagnostics struct {
+	Stats    Stats                            `json:"stats"`
+	Spilling bool                             `json:"spilling"`
+	Queues   map[string]QueueDepth            `json:"queues"`
+	Workers  map[string]map[string]QueueDepth `json:"workers"` // queues by chain
+}
+
+// Diagnostics returns the runtime state of the listener
+func (sl *StateListener) Diagnostics() any {
+	diag := Diagnostics{
+		Stats:    sl.Stats(),
+		Spilling: sl.spilling.Load(),
+		Queues: map[string]QueueDepth{
+			"changes":  queueDepth(sl.stateChanges),
+			"priority": queueDepth(sl.priorityChanges),
+		},
+		Workers: make(map[string]map[string]QueueDepth),
+	}
+
+	sl.workersMux.RLock()
+	for name, worker := range sl.workers {
+		diag.Workers[name] = map[string]QueueDepth{
+			"changes":  queueDepth(worker.changes),
+			"priority": queueDepth(worker.priority),
+		}
+	}
+	sl.workersMux.RUnlock()
+
+	return diag
+}
+
+// queueDepth returns the depth of a queue
+func queueDepth(queue chan *StateChange) QueueDepth {
+	return QueueDepth{Length: len(queue), Capacity: cap(queue)}
+}
+
 // OnStateChange handles incoming state changes from ADR-038
 func (sl *StateListener) OnStateChange(chainName, storeKey string, key, value []byte, delete bool, height int64) {
 	change := &StateChange{
@@ -139,16 +282,56 @@ func (sl *StateListener) OnStateChange(chainName, storeKey string, key, value []
 		Timestamp: time.Now(),
 	}
 	
-	select {
-	case sl.stateChanges <- change:
-		// Successfully queued
-	default:
-		// Channel full, log warning
-		sl.logger.Warn("State change channel full, dropping change",
-			zap.String("chain", chainName),
-			zap.String("store", storeKey),
-			zap.Int64("height", height))
+	sequence := sl.received.Add(1)
+	changesReceived.WithLabelValues(chainName).Inc()
+
+	// Archive changes before backpressure can drop them
+	if sl.archive != nil {
+		sl.archive.record(change, sequence)
+	}
+
+	if sl.priority.match(change) {
+		sl.prioritized.Add(1)
+		changesPrioritized.WithLabelValues(chainName).Inc()
+		select {
+		case sl.priorityChanges <- change:
+		case <-sl.ctx.Done():
+			sl.drop(change, dropShutdown)
+		}
+		return
+	}
+
+	sl.enqueue(change)
+}
+
+// Reprocess handles a state change synchronously through the current modules
+// and decoders, bypassing the queues. It is used to decode archived changes
+// again; the listener does not need to be started.
+func (sl *StateListener) Reprocess(ctx context.Context, change *StateChange) error {
+	sl.workersMux.Lock()
+	worker, exists := sl.workers[change.ChainName]
+	if !exists {
+		chain, ok := sl.chain(change.ChainName)
+		if !ok {
+			sl.workersMux.Unlock()
+			return fmt.Errorf("chain %s is not configured", change.ChainName)
+		}
+		worker = sl.createWorker(chain)
+		sl.workers[change.ChainName] = worker
+	}
+	sl.workersMux.Unlock()
+
+	return worker.process(ctx, change)
+}
+
+// chain returns the configuration of a chain
+func (sl *StateListener) chain(name string) (config.ChainConfig, bool) {
+	for _, chain := range sl.cfg.Chains {
+		if chain.Name == name {
+			return chain, true
+		}
 	}
+	return config.ChainConfig{}, false
 }
 
 // processStateChanges processes incoming state changes
@@ -156,49 +339,87 @@ func (sl *StateListener) processStateChanges() {
 	sl.logger.Info("Starting state change processor")
 	
 	for {
+		// Serve the priority lane first
+		select {
+		case change := <-sl.priorityChanges:
+			sl.dispatch(change, true)
+			continue
+		default:
+		}
+
 		select {
 		case <-sl.ctx.Done():
 			sl.logger.Info("State change processor stopping")
 			return
+		case change := <-sl.priorityChanges:
+			sl.dispatch(change, true)
 		case change := <-sl.stateChanges:
-			if change == nil {
-				continue
-			}
-			
-			// Route to appropriate worker
-			sl.workersMux.RLock()
-			worker, exists := sl.workers[change.ChainName]
-			sl.workersMux.RUnlock()
-			
-			if !exists {
-				sl.logger.Warn("No worker for chain",
-					zap.String("chain", change.ChainName))
-				continue
-			}
-			
-			// Send to worker
-			select {
-			case worker.changes <- change:
-				// Successfully routed
-			default:
-				sl.logger.Warn("Worker channel full",
-					zap.String("chain", change.ChainName))
-			}
+			sl.dispatch(change, false)
+		}
+	}
+}
+
+// dispatch routes a state change to its chain's worker
+func (sl *StateListener) dispatch(change *StateChange, priority bool) {
+	if change == nil {
+		return
+	}
+
+	sl.workersMux.RLock()
+	worker, exists := sl.workers[change.ChainName]
+	sl.workersMux.RUnlock()
+
+	if !exists {
+		sl.logger.Warn("No worker for chain",
+			zap.String("chain", change.ChainName))
+		return
+	}
+
+	if priority {
+		select {
+		case worker.priority <- change:
+		case <-sl.ctx.Done():
 		}
+		return
 	}
+
+	sl.route(worker, change)
 }
 
 // createWorker creates a new listener worker for a chain
 func (sl *StateListener) createWorker(chainCfg config.ChainConfig) *ListenerWorker {
 	ctx, cancel := context.WithCancel(sl.ctx)
+
+	workerBufferSize := sl.cfg.StateListener.WorkerBufferSize
+	if workerBufferSize <= 0 {
+		workerBufferSize = 1000
+	}
 	
+	logger := sl.logger.Named(chainCfg.Name)
+	instances, unknown := modules.ForChain(chainCfg)
+	for _, name := range unknown {
+		logger.Warn("Module is not registered, ignoring its state changes", zap.String("module", name))
+	}
+
 	return &ListenerWorker{
 		chainName: chainCfg.Name,
 		cfg:       chainCfg,
-		storage:   sl.storage,
-		streaming: sl.streaming,
-		logger:    sl.logger.Named(chainCfg.Name),
-		changes:   make(chan *StateChange, 1000),
+		logger:    logger,
+		modules:   instances,
+		decoders:  sl.decoders,
+		env: &modules.Env{
+			Chain:     chainCfg,
+			Storage:   sl.storage,
+			Streaming: sl.streaming,
+			Outbox:    sl.cfg.Streaming.Outbox.Enabled,
+			Dedup:     dedup.New(sl.cfg.StateListener.DedupCacheSize),
+			Logger:    logger,
+		},
+		changes:   make(chan *StateChange, workerBufferSize),
+		priority:  make(chan *StateChange, workerBufferSize),
+		processed: &sl.processed,
+		failed:    &sl.failed,
+		timeout:   sl.cfg.StateListener.ProcessTimeout,
 		ctx:       ctx,
 		cancel:    cancel,
 	}
@@ -209,27 +430,56 @@ func (lw *ListenerWorker) start(ctx context.Context) error {
 	lw.logger.Info("Starting listener worker")
 	
 	for {
+		// Serve the priority lane first
+		select {
+		case change := <-lw.priority:
+			lw.handle(ctx, change)
+			continue
+		default:
+		}
+
 		select {
 		case <-ctx.Done():
 			lw.logger.Info("Listener worker stopping")
 			return nil
+		case change := <-lw.priority:
+			lw.handle(ctx, change)
 		case change := <-lw.changes:
-			if change == nil {
-				continue
-			}
-			
-			if err := lw.processStateChange(change); err != nil {
-				lw.logger.Error("Failed to process state change",
-					zap.String("store", change.StoreKey),
-					zap.Int64("height", change.Height),
-					zap.Error(err))
-			}
+			lw.handle(ctx, change)
 		}
 	}
 }
 
+// handle processes a state change and counts the outcome. Stopping the worker
+// cancels the change being processed.
+func (lw *ListenerWorker) handle(ctx context.Context, change *StateChange) {
+	if change == nil {
+		return
+	}
+
+	if err := lw.process(ctx, change); err != nil {
+		lw.failed.Add(1)
+		lw.logger.Error("Failed to process state change",
+			zap.String("store", change.StoreKey),
+			zap.Int64("height", change.Height),
+			zap.Error(err))
+		return
+	}
+	lw.processed.Add(1)
+}
+
+// process processes a state change within the process timeout
+func (lw *ListenerWorker) process(ctx context.Context, change *StateChange) error {
+	if lw.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, lw.timeout)
+		defer cancel()
+	}
+	return lw.processStateChange(ctx, change)
+}
+
 // processStateChange processes a single state change
-func (lw *ListenerWorker) processStateChange(change *StateChange) error {
+func (lw *ListenerWorker) processStateChange(ctx context.Context, change *StateChange) error {
 	lw.logger.Debug("Processing state change",
 		zap.String("store", change.StoreKey),
 		zap.Int("key_len", len(change.Key)),
@@ -237,179 +487,40 @@ func (lw *ListenerWorker) processStateChange(change *StateChange) error {
 		zap.Bool("delete", change.Delete),
 		zap.Int64("height", change.Height))
 	
-	// Parse the state change based on store key
-	switch change.StoreKey {
-	case "bank":
-		return lw.processBankStateChange(change)
-	case "staking":
-		return lw.processStakingStateChange(change)
-	case "distribution":
-		return lw.processDistributionStateChange(change)
-	case "gov":
-		return lw.processGovernanceStateChange(change)
-	case "mint":
-		return lw.processMintStateChange(change)
-	case "slashing":
-		return lw.processSlashingStateChange(change)
-	default:
-		lw.logger.Debug("Unknown store key", zap.String("store", change.StoreKey))
-		return nil
+	if lw.cfg.ModuleEnabled(change.StoreKey) {
+		if module, ok := lw.modules[config.CanonicalModuleName(change.StoreKey)]; ok {
+			return module.HandleStateChange(ctx, lw.env, change)
+		}
 	}
-}
 
-// processBankStateChange processes bank module state changes
-func (lw *ListenerWorker) processBankStateChange(change *StateChange) error {
-	// Parse bank state change
-	// Key format: balances/{address}/{denom} or supply/{denom}
-	keyStr := string(change.Key)
-	
-	if len(keyStr) > 9 && keyStr[:9] == "balances/" {
-		// Balance change
-		return lw.processBalanceChange(change, keyStr[9:])
-	} else if len(keyStr) > 7 && keyStr[:7] == "supply/" {
-		// Supply change
-		return lw.processSupplyChange(change, keyStr[7:])
+	// Record changes to stores without a module when they have decoders
+	if lw.decoders.handles(change.StoreKey) {
+		return lw.recordDecoded(ctx, change)
 	}
-	
-	return nil
-}
 
-// processBalanceChange processes balance changes
-func (lw *ListenerWorker) processBalanceChange(change *StateChange, keyRemainder string) error {
-	// Parse address and denom from key
-	// Format: {address}/{denom}
-	parts := []string{} // TODO: Parse key properly
-	if len(parts) < 2 {
-		return fmt.Errorf("invalid balance key format")
-	}
-	
-	address := parts[0]
-	denom := parts[1]
-	
-	// Parse amount from value
-	amount := string(change.Value)
-	if change.Delete {
-		amount = "0"
-	}
-	
-	// Create balance event
-	balanceEvent := types.BalanceEvent{
-		ChainName: change.ChainName,
-		Address:   address,
-		Denom:     denom,
-		Amount:    amount,
-		EventType: "balance_change",
-		Height:    change.Height,
-		Timestamp: change.Timestamp,
-	}
-	
-	// Store in database
-	balance := types.Balance{
-		ChainName: change.ChainName,
-		Address:   address,
-		Denom:     denom,
-		Amount:    amount,
-		Height:    change.Height,
-		UpdatedAt: change.Timestamp,
-	}
-	
-	if err := lw.storage.Postgres().UpsertBalance(context.Background(), balance); err != nil {
-		return fmt.Errorf("failed to upsert balance: %w", err)
-	}
-	
-	// Stream event
-	if lw.streaming != nil {
-		if err := lw.streaming.PublishBalanceEvent(balanceEvent); err != nil {
-			lw.logger.Warn("Failed to publish balance event", zap.Error(err))
-		}
-	}
-	
-	// Store in ClickHouse for analytics
-	if lw.storage.ClickHouse() != nil {
-		if err := lw.storage.ClickHouse().InsertBalanceEvent(context.Background(), balanceEvent); err != nil {
-			lw.logger.Warn("Failed to insert balance event to ClickHouse", zap.Error(err))
-		}
+	if lw.cfg.ModuleEnabled(change.StoreKey) {
+		lw.logger.Debug("Unknown store key", zap.String("store", change.StoreKey))
 	}
-	
-	return nil
-}
-
-// processSupplyChange processes supply changes
-func (lw *ListenerWorker) processSupplyChange(change *StateChange, denom string) error {
-	// TODO: Implement supply change processing
-	lw.logger.Debug("Supply change detected",
-		zap.String("denom", denom),
-		zap.Int64("height", change.Height))
 	return nil
 }
 
-// processStakingStateChange processes staking module state changes
-func (lw *ListenerWorker) processStakingStateChange(change *StateChange) error {
-	// Parse staking state change
-	// Key formats: validators/{validator}, delegations/{delegator}/{validator}, etc.
-	keyStr := string(change.Key)
-	
-	if len(keyStr) > 11 && keyStr[:11] == "validators/" {
-		// Validator change
-		return lw.processValidatorChange(change, keyStr[11:])
-	} else if len(keyStr) > 12 && keyStr[:12] == "delegations/" {
-		// Delegation change
-		return lw.processDelegationChange(change, keyStr[12:])
+// recordDecoded stores a change decoded by its store's rules for analytics
+func (lw *ListenerWorker) recordDecoded(ctx context.Context, change *StateChange) error {
+	decoded, err := lw.decoders.decode(change)
+	if err != nil {
+		decodeFailures.WithLabelValues(lw.chainName, change.StoreKey).Inc()
+		lw.logger.Debug("Failed to decode state change value",
+			zap.String("store", change.StoreKey),
+			zap.String("key", decoded.Key),
+			zap.Error(err))
 	}
-	
-	return nil
-}
-
-// processValidatorChange processes validator changes
-func (lw *ListenerWorker) processValidatorChange(change *StateChange, validatorAddr string) error {
-	// TODO: Parse validator data from protobuf value
-	lw.logger.Debug("Validator change detected",
-		zap.String("validator", validatorAddr),
-		zap.Int64("height", change.Height))
-	return nil
-}
-
-// processDelegationChange processes delegation changes
-func (lw *ListenerWorker) processDelegationChange(change *StateChange, keyRemainder string) error {
-	// TODO: Parse delegation data from protobuf value
-	lw.logger.Debug("Delegation change detected",
-		zap.String("key", keyRemainder),
-		zap.Int64("height", change.Height))
-	return nil
-}
-
-// processDistributionStateChange processes distribution module state changes
-func (lw *ListenerWorker) processDistributionStateChange(change *StateChange) error {
-	// TODO: Implement distribution state change processing
-	lw.logger.Debug("Distribution state change",
-		zap.String("key", string(change.Key)),
-		zap.Int64("height", change.Height))
-	return nil
-}
+	changesDecoded.WithLabelValues(lw.chainName, change.StoreKey, decoded.Entity).Inc()
 
-// processGovernanceStateChange processes governance module state changes
-func (lw *ListenerWorker) processGovernanceStateChange(change *StateChange) error {
-	// TODO: Implement governance state change processing
-	lw.logger.Debug("Governance state change",
-		zap.String("key", string(change.Key)),
-		zap.Int64("height", change.Height))
-	return nil
-}
-
-// processMintStateChange processes mint module state changes
-func (lw *ListenerWorker) processMintStateChange(change *StateChange) error {
-	// TODO: Implement mint state change processing
-	lw.logger.Debug("Mint state change",
-		zap.String("key", string(change.Key)),
-		zap.Int64("height", change.Height))
-	return nil
-}
-
-// processSlashingStateChange processes slashing module state changes
-func (lw *ListenerWorker) processSlashingStateChange(change *StateChange) error {
-	// TODO: Implement slashing state change processing
-	lw.logger.Debug("Slashing state change",
-		zap.String("key", string(change.Key)),
-		zap.Int64("height", change.Height))
+	if lw.env.Storage.ClickHouse() == nil {
+		return nil
+	}
+	if err := lw.env.Storage.ClickHouse().InsertDecodedStateChanges(ctx, []types.DecodedStateChange{*decoded}); err != nil {
+		return fmt.Errorf("failed to insert decoded state change: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,278 @@
+// Package logging builds the process logger from the log configuration: stderr
+// and an optional rotated log file, a level per logger name and sampling of
+// high-frequency entries. Levels and sampling can be changed at runtime
+// through the Control returned with the logger.
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/cosmos/state-mesh/internal/config"
+)
+
+// Settings are the log levels and sampling that can be changed at runtime
+type Settings struct {
+	Level    string            `json:"level"`
+	Modules  map[string]string `json:"modules"` // level by logger name
+	Sampling SamplingSettings  `json:"sampling"`
+}
+
+// SamplingSettings configure sampling of entries at or below Level. Within
+// each tick, the first Initial entries with the same logger and message are
+// logged, then every Thereafter-th one.
+type SamplingSettings struct {
+	Enabled    bool   `json:"enabled"`
+	Level      string `json:"level"`
+	Initial    int    `json:"initial"`
+	Thereafter int    `json:"thereafter"`
+	Tick       string `json:"tick"` // duration, such as 1s
+}
+
+// Control holds the log levels and sampling of a logger built by New
+type Control struct {
+	mu       sync.RWMutex
+	settings Settings
+	level    zapcore.Level
+	modules  map[string]zapcore.Level
+	sampler  *sampler // nil when sampling is disabled
+
+	minLevel atomic.Int32 // lowest level enabled by any logger
+}
+
+// New builds a logger from the log configuration and the control of its levels
+func New(cfg config.LogConfig) (*zap.Logger, *Control, error) {
+	development := cfg.Format != "json"
+
+	var encoder zapcore.Encoder
+	if development {
+		encoder = zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	} else {
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	}
+
+	sinks := []zapcore.WriteSyncer{zapcore.Lock(os.Stderr)}
+	if cfg.File.Path != "" {
+		sinks = append(sinks, zapcore.AddSync(&lumberjack.Logger{
+			Filename:   cfg.File.Path,
+			MaxSize:    cfg.File.MaxSizeMB,
+			MaxBackups: cfg.File.MaxBackups,
+			MaxAge:     cfg.File.MaxAgeDays,
+			Compress:   cfg.File.Compress,
+		}))
+	}
+
+	tick := ""
+	if cfg.Sampling.Tick > 0 {
+		tick = cfg.Sampling.Tick.String()
+	}
+	control := &Control{}
+	err := control.Update(Settings{
+		Level:   cfg.Level,
+		Modules: cfg.Modules,
+		Sampling: SamplingSettings{
+			Enabled:    cfg.Sampling.Enabled,
+			Level:      cfg.Sampling.Level,
+			Initial:    cfg.Sampling.Initial,
+			Thereafter: cfg.Sampling.Thereafter,
+			Tick:       tick,
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	core := &core{
+		Core:    zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(sinks...), zapcore.DebugLevel),
+		control: control,
+	}
+
+	options := []zap.Option{zap.AddCaller(), zap.ErrorOutput(zapcore.Lock(os.Stderr))}
+	if development {
+		options = append(options, zap.Development(), zap.AddStacktrace(zapcore.WarnLevel))
+	} else {
+		options = append(options, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+
+	return zap.New(core, options...), control, nil
+}
+
+// Settings returns the current log levels and sampling
+func (c *Control) Settings() Settings {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	settings := c.settings
+	settings.Modules = make(map[string]string, len(c.settings.Modules))
+	for name, level := range c.settings.Modules {
+		settings.Modules[name] = level
+	}
+	return settings
+}
+
+// Update replaces the log levels and sampling. An empty level defaults to
+// info; modules with an empty level are removed.
+func (c *Control) Update(settings Settings) error {
+	if settings.Level == "" {
+		settings.Level = "info"
+	}
+	level, err := zapcore.ParseLevel(settings.Level)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q", settings.Level)
+	}
+
+	modules := make(map[string]zapcore.Level, len(settings.Modules))
+	names := make(map[string]string, len(settings.Modules))
+	minLevel := level
+	for name, value := range settings.Modules {
+		if value == "" {
+			continue
+		}
+		moduleLevel, err := zapcore.ParseLevel(value)
+		if err != nil {
+			return fmt.Errorf("invalid log level %q for %s", value, name)
+		}
+		modules[name] = moduleLevel
+		names[name] = value
+		minLevel = min(minLevel, moduleLevel)
+	}
+	settings.Modules = names
+
+	var s *sampler
+	if settings.Sampling.Enabled {
+		s, err = newSampler(settings.Sampling)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.settings = settings
+	c.level = level
+	c.modules = modules
+	c.sampler = s
+	c.minLevel.Store(int32(minLevel))
+	return nil
+}
+
+// enabled reports whether any logger logs entries at level
+func (c *Control) enabled(level zapcore.Level) bool {
+	return level >= zapcore.Level(c.minLevel.Load())
+}
+
+// allow reports whether an entry passes the level of the logger that wrote it
+// and sampling
+func (c *Control) allow(entry zapcore.Entry) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if entry.Level < c.levelOf(entry.LoggerName) {
+		return false
+	}
+	return c.sampler == nil || c.sampler.allow(entry)
+}
+
+// levelOf returns the level of a logger: that of the longest configured name
+// equal to it or to one of its parents, such as ingester for ingester.worker
+func (c *Control) levelOf(name string) zapcore.Level {
+	level, matched := c.level, -1
+	for module, moduleLevel := range c.modules {
+		if len(module) > matched && (name == module || strings.HasPrefix(name, module+".")) {
+			level, matched = moduleLevel, len(module)
+		}
+	}
+	return level
+}
+
+// core filters entries by the level of the logger that wrote them and samples
+// them before writing them to the wrapped core
+type core struct {
+	zapcore.Core
+	control *Control
+}
+
+func (c *core) Enabled(level zapcore.Level) bool {
+	return c.control.enabled(level)
+}
+
+func (c *core) Level() zapcore.Level {
+	return zapcore.Level(c.control.minLevel.Load())
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(fields), control: c.control}
+}
+
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.control.allow(entry) {
+		return checked
+	}
+	return checked.AddCore(entry, c)
+}
+
+// sampler counts the entries with the same logger and message per tick
+type sampler struct {
+	level      zapcore.Level
+	initial    uint64
+	thereafter uint64
+	tick       time.Duration
+
+	mu     sync.Mutex
+	window time.Time
+	counts map[string]uint64
+}
+
+// newSampler creates a sampler from sampling settings
+func newSampler(settings SamplingSettings) (*sampler, error) {
+	level, err := zapcore.ParseLevel(settings.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sampling level %q", settings.Level)
+	}
+	tick, err := time.ParseDuration(settings.Tick)
+	if err != nil || tick <= 0 {
+		return nil, fmt.Errorf("sampling tick must be a positive duration such as 1s")
+	}
+	if settings.Initial < 0 || settings.Thereafter < 0 {
+		return nil, fmt.Errorf("sampling initial and thereafter must not be negative")
+	}
+
+	return &sampler{
+		level:      level,
+		initial:    uint64(settings.Initial),
+		thereafter: uint64(settings.Thereafter),
+		tick:       tick,
+		counts:     make(map[string]uint64),
+	}, nil
+}
+
+// allow reports whether a sampled entry is logged. Entries above the
+// sampling level are always logged; a zero thereafter drops every entry past
+// the initial ones.
+func (s *sampler) allow(entry zapcore.Entry) bool {
+	if entry.Level > s.level {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry.Time.Sub(s.window) >= s.tick {
+		s.window = entry.Time
+		clear(s.counts)
+	}
+
+	key := entry.LoggerName + "\x00" + entry.Message
+	s.counts[key]++
+	n := s.counts[key]
+	return n <= s.initial || (s.thereafter > 0 && (n-s.initial)%s.thereafter == 0)
+}
@@ -0,0 +1,186 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("auth", func() ModuleIngester { return &authModule{} })
+}
+
+// authModule ingests the module accounts of a chain and their balances, which
+// the supply breakdown splits the total supply by, along with the balances of
+// the treasury addresses and the coins locked in vesting accounts that
+// circulating supply excludes. Options:
+//
+//	treasury: comma separated addresses excluded from circulating supply
+//	vesting:  "true" to scan every account for vesting schedules
+type authModule struct {
+	Base
+}
+
+// Name returns the module name
+func (m *authModule) Name() string {
+	return "auth"
+}
+
+// Poll ingests the module accounts, the treasury and vesting accounts and
+// records the circulating supply of the staking denom
+func (m *authModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	infos, err := env.Client.GetModuleAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	now := time.Now()
+	changes := env.Dedup.Batch()
+	chainName := env.Chain.Name
+
+	accounts := make([]types.ModuleAccount, len(infos))
+	addresses := make([]string, 0, len(infos))
+	for i, info := range infos {
+		accounts[i] = types.ModuleAccount{
+			ChainName:   chainName,
+			Name:        info.Name,
+			Address:     info.Address,
+			Permissions: info.Permissions,
+		}
+		addresses = append(addresses, info.Address)
+	}
+	treasury := module.ListOption("treasury")
+	addresses = append(addresses, treasury...)
+
+	var balances []types.Balance
+	for _, address := range addresses {
+		coins, err := env.Client.GetAllBalances(ctx, address)
+		if err != nil {
+			return fmt.Errorf("failed to get balances of %s: %w", address, err)
+		}
+		for _, coin := range coins {
+			if !changes.Changed(address+"/"+coin.Denom, coin.Amount.String()) {
+				continue
+			}
+			balances = append(balances, types.Balance{
+				ChainName: chainName,
+				Address:   address,
+				Denom:     coin.Denom,
+				Amount:    coin.Amount.String(),
+				Height:    height,
+				UpdatedAt: now,
+			})
+		}
+	}
+
+	var vesting []types.VestingLocked
+	scanVesting := module.Option("vesting", "false") == "true"
+	if scanVesting {
+		vestingAccounts, err := env.Client.GetVestingAccounts(ctx, now)
+		if err != nil {
+			return err
+		}
+		for _, info := range vestingAccounts {
+			for _, coin := range info.Locked {
+				vesting = append(vesting, types.VestingLocked{
+					ChainName: chainName,
+					Address:   info.Address,
+					Type:      info.Type,
+					Denom:     coin.Denom,
+					Amount:    coin.Amount.String(),
+					EndTime:   info.EndTime,
+				})
+			}
+		}
+	}
+
+	replaceAccounts := changes.Changed("module_accounts", accounts)
+	replaceVesting := scanVesting && changes.Changed("vesting_locked", vesting)
+	if !replaceAccounts && !replaceVesting && len(balances) == 0 {
+		return nil
+	}
+
+	// Start transaction
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if replaceAccounts {
+		for i := range accounts {
+			accounts[i].Height = height
+			accounts[i].UpdatedAt = now
+		}
+		if err := tx.State().ReplaceModuleAccounts(ctx, chainName, accounts); err != nil {
+			return fmt.Errorf("failed to replace module accounts: %w", err)
+		}
+	}
+
+	if replaceVesting {
+		for i := range vesting {
+			vesting[i].Height = height
+			vesting[i].UpdatedAt = now
+		}
+		if err := tx.State().ReplaceVestingLocked(ctx, chainName, vesting); err != nil {
+			return fmt.Errorf("failed to replace vesting locked: %w", err)
+		}
+	}
+
+	if err := tx.State().UpsertBalances(ctx, balances); err != nil {
+		return fmt.Errorf("failed to upsert module account balances: %w", err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	changes.Commit()
+
+	if env.Storage.ClickHouse() != nil {
+		m.recordCirculatingSupply(ctx, env, treasury, height, now)
+	}
+
+	env.Logger.Debug("Auth module state ingested",
+		zap.Int("module_accounts", len(accounts)),
+		zap.Int("vesting_locked", len(vesting)),
+		zap.Int("balances", len(balances)),
+		zap.Int64("height", height))
+
+	return nil
+}
+
+// recordCirculatingSupply records the circulating supply of the staking denom
+// for its history when it changed. Failures are logged, not returned, as the
+// ingested state is already committed.
+func (m *authModule) recordCirculatingSupply(ctx context.Context, env *Env, treasury []string, height int64, now time.Time) {
+	denom := env.Chain.StakingDenom()
+	supply, err := env.Storage.GetCirculatingSupply(ctx, env.Chain.Name, denom, treasury)
+	if errors.Is(err, storage.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		env.Logger.Warn("Failed to compute circulating supply", zap.Error(err))
+		return
+	}
+
+	changes := env.Dedup.Batch()
+	if !changes.Changed("circulating/"+denom, supply.Circulating) {
+		return
+	}
+	supply.Height = height
+	supply.Timestamp = now
+	if err := env.Storage.ClickHouse().InsertCirculatingSupply(ctx, *supply); err != nil {
+		env.Logger.Warn("Failed to insert circulating supply to ClickHouse", zap.Error(err))
+		return
+	}
+	changes.Commit()
+}
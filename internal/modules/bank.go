@@ -0,0 +1,187 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	eventsv1 "github.com/cosmos/state-mesh/pkg/events/v1"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("bank", func() ModuleIngester { return &bankModule{} })
+}
+
+// bankModule ingests total supply and streamed balance changes
+type bankModule struct {
+	Base
+}
+
+// Name returns the module name
+func (m *bankModule) Name() string {
+	return "bank"
+}
+
+// Poll ingests the total supply of every denom
+func (m *bankModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	// Get total supply for all denoms
+	coins, err := env.Client.GetAllSupply(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get total supply: %w", err)
+	}
+
+	// Start transaction
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	changes := env.Dedup.Batch()
+	supply := make([]types.Supply, 0, len(coins))
+	for _, coin := range coins {
+		if !changes.Changed("supply/"+coin.Denom, coin.Amount.String()) {
+			continue
+		}
+		supply = append(supply, types.Supply{
+			ChainName: env.Chain.Name,
+			Denom:     coin.Denom,
+			Amount:    coin.Amount.String(),
+			Height:    height,
+			UpdatedAt: now,
+		})
+	}
+
+	if len(supply) > 0 {
+		if err := tx.State().UpsertSupply(ctx, supply); err != nil {
+			return fmt.Errorf("failed to upsert supply: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	changes.Commit()
+
+	env.Logger.Debug("Bank module state ingested",
+		zap.Int("denoms", len(supply)),
+		zap.Int64("height", height))
+
+	return nil
+}
+
+// HandleStateChange processes bank module state changes.
+// Key format: balances/{address}/{denom} or supply/{denom}
+func (m *bankModule) HandleStateChange(ctx context.Context, env *Env, change *types.StateChange) error {
+	key := string(change.Key)
+	if balance, ok := strings.CutPrefix(key, "balances/"); ok && balance != "" {
+		return m.handleBalanceChange(ctx, env, change, balance)
+	}
+	if denom, ok := strings.CutPrefix(key, "supply/"); ok && denom != "" {
+		// TODO: Implement supply change processing
+		env.Logger.Debug("Supply change detected",
+			zap.String("denom", denom),
+			zap.Int64("height", change.Height))
+	}
+	return nil
+}
+
+// handleBalanceChange stores a balance change and emits its event.
+// Key remainder format: {address}/{denom}
+func (m *bankModule) handleBalanceChange(ctx context.Context, env *Env, change *types.StateChange, keyRemainder string) error {
+	parts := strings.SplitN(keyRemainder, "/", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid balance key format")
+	}
+
+	address := parts[0]
+	denom := parts[1]
+
+	// Parse amount from value
+	amount := string(change.Value)
+	if change.Delete {
+		amount = "0"
+	}
+
+	// Skip the write and events when the balance is unchanged
+	changes := env.Dedup.Batch()
+	if !changes.Changed(address+"/"+denom, amount) {
+		return nil
+	}
+
+	// Create balance event
+	balanceEvent := types.BalanceEvent{
+		EventID:    streaming.EventID(change.ChainName, change.StoreKey, change.Height, change.Key),
+		ChainName:  change.ChainName,
+		Address:    address,
+		Denom:      denom,
+		Amount:     amount,
+		ChangeType: "current",
+		Height:     change.Height,
+		Timestamp:  change.Timestamp,
+	}
+
+	// Store in database
+	balance := types.Balance{
+		ChainName: change.ChainName,
+		Address:   address,
+		Denom:     denom,
+		Amount:    amount,
+		Height:    change.Height,
+		UpdatedAt: change.Timestamp,
+	}
+
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.State().UpsertBalance(ctx, &balance); err != nil {
+		return fmt.Errorf("failed to upsert balance: %w", err)
+	}
+
+	if err := discoverAccount(ctx, tx, address, change); err != nil {
+		return err
+	}
+
+	// Write the event to the outbox so that it is published if and only if
+	// the balance is committed
+	if env.Outbox {
+		message, err := streaming.BalanceEventMessage(eventsv1.NewBalanceEvent(&balanceEvent))
+		if err != nil {
+			return err
+		}
+		if err := tx.State().EnqueueOutbox(ctx, []types.OutboxMessage{message}); err != nil {
+			return fmt.Errorf("failed to enqueue balance event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	changes.Commit()
+
+	// Stream event
+	if env.Streaming != nil && !env.Outbox {
+		if err := env.Streaming.PublishBalanceEvent(ctx, eventsv1.NewBalanceEvent(&balanceEvent)); err != nil {
+			env.Logger.Warn("Failed to publish balance event", zap.Error(err))
+		}
+	}
+
+	// Store in ClickHouse for analytics
+	if env.Storage.ClickHouse() != nil {
+		if err := env.Storage.ClickHouse().InsertBalanceEvents(ctx, []types.BalanceEvent{balanceEvent}); err != nil {
+			env.Logger.Warn("Failed to insert balance event to ClickHouse", zap.Error(err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("blocks", func() ModuleIngester { return &blocksModule{} })
+}
+
+// blocksModule ingests block headers and, with analytics, their signatures
+// and, when the txs option is "true", their transactions and the IBC packet
+// and reward withdrawal events they emitted, flagging the rewards claimed and
+// delegated again within a block as compounded. Blocks have no store, so
+// there are no state changes to handle.
+type blocksModule struct {
+	Base
+	lastBlock int64 // last block height handed to storage
+}
+
+// Name returns the module name
+func (m *blocksModule) Name() string {
+	return "blocks"
+}
+
+// Poll ingests block headers since the last stored block, up to the module's
+// max_per_cycle option, so a chain far behind catches up over several cycles.
+// A chain without stored blocks starts at the tip.
+func (m *blocksModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	maxPerCycle, err := strconv.ParseInt(module.Option("max_per_cycle", "100"), 10, 64)
+	if err != nil || maxPerCycle <= 0 {
+		return fmt.Errorf("invalid max_per_cycle option %q", module.Option("max_per_cycle", ""))
+	}
+
+	chainName := env.Chain.Name
+
+	// While writes are buffered the store lags behind, and may be unreadable
+	last, err := env.Storage.State().GetLatestBlockHeight(ctx, chainName)
+	if err != nil {
+		if !env.Storage.Buffering() || m.lastBlock == 0 {
+			return err
+		}
+		last = m.lastBlock
+	}
+	if m.lastBlock > last {
+		last = m.lastBlock
+	}
+
+	from, to := last+1, min(height, last+maxPerCycle)
+	if last == 0 {
+		from, to = height, height
+	}
+	// The node can be behind the stored blocks after a resync or a failover
+	// to a lagging endpoint
+	if from > to {
+		return nil
+	}
+
+	analytics := env.Storage.ClickHouse() != nil
+	indexTxs := analytics && module.Option("txs", "false") == "true"
+
+	blocks := make([]types.Block, 0, to-from+1)
+	var signatures []types.BlockSignature
+	var txs []types.Transaction
+	var packets []types.PacketEvent
+	var rewards []types.RewardEvent
+	for h := from; h <= to; h++ {
+		info, err := env.Client.GetBlock(ctx, h)
+		if err != nil {
+			return err
+		}
+
+		blocks = append(blocks, types.Block{
+			ChainName:       chainName,
+			Height:          info.Height,
+			Hash:            info.Hash,
+			ProposerAddress: info.ProposerAddress,
+			TxCount:         info.TxCount,
+			Time:            info.Time,
+		})
+
+		if !analytics {
+			continue
+		}
+
+		// The block's last commit attributes signatures to the previous height
+		sigs, err := env.Client.GetLastCommitSignatures(ctx, info)
+		if err != nil {
+			return err
+		}
+		for _, sig := range sigs {
+			signatures = append(signatures, types.BlockSignature{
+				ChainName:        chainName,
+				Height:           info.Height - 1,
+				ConsensusAddress: sig.ConsensusAddress,
+				Signed:           sig.Signed,
+				Timestamp:        info.Time,
+			})
+		}
+
+		if !indexTxs || info.TxCount == 0 {
+			continue
+		}
+		infos, err := env.Client.GetBlockTxs(ctx, info.Height)
+		if err != nil {
+			return err
+		}
+		compounders := cosmos.Compounders(infos)
+		for _, tx := range infos {
+			indexed := types.Transaction{
+				ChainName:    chainName,
+				Height:       info.Height,
+				Hash:         tx.Hash,
+				Code:         tx.Code,
+				GasWanted:    tx.GasWanted,
+				GasUsed:      tx.GasUsed,
+				FeePayer:     tx.FeePayer,
+				MessageTypes: tx.MessageTypes,
+				Timestamp:    info.Time,
+			}
+			if len(tx.Fee) > 0 {
+				indexed.FeeDenom = tx.Fee[0].Denom
+				indexed.FeeAmount = tx.Fee[0].Amount.String()
+			}
+			txs = append(txs, indexed)
+
+			for _, packet := range tx.Packets {
+				packets = append(packets, types.PacketEvent{
+					ChainName:  chainName,
+					Type:       packet.Type,
+					Sequence:   packet.Sequence,
+					SrcPort:    packet.SrcPort,
+					SrcChannel: packet.SrcChannel,
+					DstPort:    packet.DstPort,
+					DstChannel: packet.DstChannel,
+					Relayer:    tx.FeePayer,
+					TxHash:     tx.Hash,
+					Height:     info.Height,
+					Timestamp:  info.Time,
+				})
+			}
+
+			for _, reward := range tx.Rewards {
+				for _, coin := range reward.Amount {
+					rewards = append(rewards, types.RewardEvent{
+						Timestamp:        info.Time,
+						ChainName:        chainName,
+						DelegatorAddress: reward.Delegator,
+						ValidatorAddress: reward.Validator,
+						Denom:            coin.Denom,
+						Amount:           coin.Amount.String(),
+						Height:           info.Height,
+						TxHash:           tx.Hash,
+						Claimed:          reward.Claimed,
+						Compounded:       reward.Claimed && compounders[reward.Delegator],
+					})
+				}
+			}
+		}
+	}
+
+	if analytics {
+		if err := env.Storage.ClickHouse().InsertBlockProduction(ctx, blocks, signatures); err != nil {
+			return fmt.Errorf("failed to insert block production: %w", err)
+		}
+		if err := env.Storage.ClickHouse().InsertTransactions(ctx, txs); err != nil {
+			return fmt.Errorf("failed to insert transactions: %w", err)
+		}
+		if err := env.Storage.ClickHouse().InsertPacketEvents(ctx, packets); err != nil {
+			return fmt.Errorf("failed to insert IBC packet events: %w", err)
+		}
+		if err := env.Storage.ClickHouse().InsertRewardEvents(ctx, rewards); err != nil {
+			return fmt.Errorf("failed to insert reward events: %w", err)
+		}
+	}
+
+	// Start transaction
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.State().InsertBlocks(ctx, blocks); err != nil {
+		return fmt.Errorf("failed to insert blocks: %w", err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	m.lastBlock = to
+
+	env.Logger.Debug("Blocks ingested",
+		zap.Int("blocks", len(blocks)),
+		zap.Int("txs", len(txs)),
+		zap.Int("ibc_packets", len(packets)),
+		zap.Int("rewards", len(rewards)),
+		zap.Int64("height", to))
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package modules
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+)
+
+// blockClient serves a block at every height
+type blockClient struct {
+	cosmos.ChainClient
+}
+
+func (blockClient) GetBlock(ctx context.Context, height int64) (*cosmos.BlockInfo, error) {
+	return &cosmos.BlockInfo{Height: height, Time: time.Unix(height, 0)}, nil
+}
+
+func TestBlocksCatchUp(t *testing.T) {
+	ctx := context.Background()
+	manager, err := storage.NewManager(config.DatabaseConfig{Driver: config.DriverMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &Env{
+		Chain:   config.ChainConfig{Name: "testchain"},
+		Client:  blockClient{},
+		Storage: manager,
+		Logger:  zap.NewNop(),
+	}
+	module := config.ModuleConfig{Name: "blocks", Options: map[string]string{"max_per_cycle": "100"}}
+	blocks := &blocksModule{}
+
+	// A chain without stored blocks starts at the tip
+	if err := blocks.Poll(ctx, env, module, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	// More than one cycle behind: each poll ingests up to max_per_cycle
+	// blocks, oldest first
+	for cycle, want := range []int64{200, 300, 350, 350} {
+		if err := blocks.Poll(ctx, env, module, 350); err != nil {
+			t.Fatal(err)
+		}
+		latest, err := manager.State().GetLatestBlockHeight(ctx, "testchain")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if latest != want {
+			t.Fatalf("cycle %d: latest block = %d, want %d", cycle, latest, want)
+		}
+	}
+
+	stored, err := manager.State().GetBlocks(ctx, "testchain", 0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 251 {
+		t.Fatalf("stored %d blocks, want 251", len(stored))
+	}
+	for i, block := range stored {
+		if want := int64(350 - i); block.Height != want {
+			t.Fatalf("block %d has height %d, want %d", i, block.Height, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("distribution", func() ModuleIngester { return &distributionModule{} })
+}
+
+// distributionModule ingests the community pool
+type distributionModule struct {
+	Base
+}
+
+// Name returns the module name
+func (m *distributionModule) Name() string {
+	return "distribution"
+}
+
+// Poll ingests the distribution params and the community pool
+func (m *distributionModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	if err := ingestParams(ctx, env, m.Name(), height); err != nil {
+		return err
+	}
+
+	coins, err := env.Client.GetCommunityPool(ctx)
+	if err != nil {
+		return err
+	}
+
+	pool := make([]types.PoolBalance, len(coins))
+	for i, coin := range coins {
+		pool[i] = types.PoolBalance{
+			ChainName: env.Chain.Name,
+			Denom:     coin.Denom,
+			Amount:    coin.Amount.String(),
+		}
+	}
+
+	changes := env.Dedup.Batch()
+	if !changes.Changed("community_pool", pool) {
+		return nil
+	}
+
+	now := time.Now()
+	for i := range pool {
+		pool[i].Height = height
+		pool[i].UpdatedAt = now
+	}
+
+	// Start transaction
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.State().ReplaceCommunityPool(ctx, env.Chain.Name, pool); err != nil {
+		return fmt.Errorf("failed to replace community pool: %w", err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	changes.Commit()
+
+	env.Logger.Debug("Distribution module state ingested",
+		zap.Int("community_pool_denoms", len(pool)),
+		zap.Int64("height", height))
+
+	return nil
+}
+
+// HandleStateChange processes distribution module state changes
+func (m *distributionModule) HandleStateChange(ctx context.Context, env *Env, change *types.StateChange) error {
+	// TODO: Implement distribution state change processing
+	env.Logger.Debug("Distribution state change",
+		zap.String("key", string(change.Key)),
+		zap.Int64("height", change.Height))
+	return nil
+}
@@ -0,0 +1,284 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/plugin"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// defaultPluginTimeout bounds a plugin call when the plugin sets no timeout
+const defaultPluginTimeout = 30 * time.Second
+
+// identifierPattern matches the table and column names plugins may write
+var identifierPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// LoadPlugins starts the configured module plugins and registers the module
+// each serves, so chains enable it like a built-in one. The returned function
+// stops the plugin processes.
+func LoadPlugins(ctx context.Context, cfgs []config.PluginConfig, logger *zap.Logger) (func(), error) {
+	var clients []*goplugin.Client
+	stop := func() {
+		for _, client := range clients {
+			client.Kill()
+		}
+	}
+
+	for _, cfg := range cfgs {
+		client := goplugin.NewClient(&goplugin.ClientConfig{
+			HandshakeConfig:  plugin.Handshake,
+			Plugins:          goplugin.PluginSet{plugin.PluginName: &plugin.GRPCPlugin{}},
+			Cmd:              exec.Command(cfg.Command, cfg.Args...),
+			AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+			Logger: hclog.New(&hclog.LoggerOptions{
+				Name:  "plugin." + cfg.Name,
+				Level: hclog.Info,
+			}),
+		})
+		clients = append(clients, client)
+
+		module, err := dispense(ctx, client, cfg)
+		if err != nil {
+			stop()
+			return nil, fmt.Errorf("failed to load plugin %s: %w", cfg.Name, err)
+		}
+		Register(cfg.Name, func() ModuleIngester { return module })
+
+		logger.Info("Module plugin loaded",
+			zap.String("module", cfg.Name),
+			zap.String("command", cfg.Command),
+			zap.Strings("tables", module.desc.Tables))
+	}
+
+	return stop, nil
+}
+
+// dispense connects to a started plugin and checks the module it serves
+func dispense(ctx context.Context, client *goplugin.Client, cfg config.PluginConfig) (*externalModule, error) {
+	rpc, err := client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start plugin: %w", err)
+	}
+	raw, err := rpc.Dispense(plugin.PluginName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispense module: %w", err)
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultPluginTimeout
+	}
+	module := &externalModule{impl: raw.(plugin.Module), timeout: timeout}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	desc, err := module.impl.Describe(callCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe module: %w", err)
+	}
+	if config.CanonicalModuleName(desc.Name) != config.CanonicalModuleName(cfg.Name) {
+		return nil, fmt.Errorf("plugin serves module %q, not %q", desc.Name, cfg.Name)
+	}
+	if _, ok := New(cfg.Name); ok {
+		return nil, fmt.Errorf("module %q is already registered", cfg.Name)
+	}
+
+	module.tables = make(map[string]bool, len(desc.Tables))
+	for _, table := range desc.Tables {
+		if !identifierPattern.MatchString(table) {
+			return nil, fmt.Errorf("invalid table name %q", table)
+		}
+		module.tables[table] = true
+	}
+	module.desc = desc
+	return module, nil
+}
+
+// externalModule ingests a module through a plugin process. It keeps no state
+// about a chain, so one instance serves every chain.
+type externalModule struct {
+	impl    plugin.Module
+	desc    *plugin.Description
+	tables  map[string]bool
+	timeout time.Duration
+}
+
+// Name returns the module name
+func (m *externalModule) Name() string {
+	return config.CanonicalModuleName(m.desc.Name)
+}
+
+// Schema returns the statements creating the plugin's tables
+func (m *externalModule) Schema() []string {
+	return m.desc.Schema
+}
+
+// Poll asks the plugin to ingest the module's state and writes its rows
+func (m *externalModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	callCtx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	resp, err := m.impl.Poll(callCtx, &plugin.PollRequest{
+		Chain:        env.Chain.Name,
+		ChainID:      env.Chain.ChainID,
+		GRPCEndpoint: env.Chain.GRPCEndpoint,
+		RESTEndpoint: env.Chain.RESTEndpoint,
+		Height:       height,
+		Options:      module.Options,
+	})
+	if err != nil {
+		return fmt.Errorf("plugin failed to poll: %w", err)
+	}
+
+	return m.write(ctx, env, resp.Writes)
+}
+
+// HandleStateChange passes a state change to the plugin and writes its rows
+func (m *externalModule) HandleStateChange(ctx context.Context, env *Env, change *types.StateChange) error {
+	callCtx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	resp, err := m.impl.HandleStateChange(callCtx, &plugin.StateChangeRequest{
+		Chain:     change.ChainName,
+		Store:     change.StoreKey,
+		Key:       change.Key,
+		Value:     change.Value,
+		Delete:    change.Delete,
+		Height:    change.Height,
+		Timestamp: change.Timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("plugin failed to handle state change: %w", err)
+	}
+
+	return m.write(ctx, env, resp.Writes)
+}
+
+// write applies a plugin's writes in one transaction
+func (m *externalModule) write(ctx context.Context, env *Env, writes []plugin.Write) error {
+	if len(writes) == 0 {
+		return nil
+	}
+
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range writes {
+		query, args, err := m.statement(&writes[i])
+		if err != nil {
+			return fmt.Errorf("invalid write %d: %w", i, err)
+		}
+		if err := tx.State().Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to write %s row: %w", writes[i].Table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// statement builds the upsert or delete of a write, checking that it only
+// touches the plugin's tables
+func (m *externalModule) statement(w *plugin.Write) (string, []any, error) {
+	if !m.tables[w.Table] {
+		return "", nil, fmt.Errorf("table %q is not declared by the plugin", w.Table)
+	}
+	if len(w.Key) == 0 {
+		return "", nil, fmt.Errorf("no key columns")
+	}
+
+	columns := make([]string, 0, len(w.Values))
+	for column := range w.Values {
+		if !identifierPattern.MatchString(column) {
+			return "", nil, fmt.Errorf("invalid column name %q", column)
+		}
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	key := make([]string, len(w.Key))
+	for i, column := range w.Key {
+		if _, ok := w.Values[column]; !ok {
+			return "", nil, fmt.Errorf("no value for key column %q", column)
+		}
+		key[i] = pq.QuoteIdentifier(column)
+	}
+
+	table := pq.QuoteIdentifier(w.Table)
+
+	if w.Delete {
+		conditions := make([]string, len(w.Key))
+		args := make([]any, len(w.Key))
+		for i, column := range w.Key {
+			conditions[i] = fmt.Sprintf("%s = $%d", key[i], i+1)
+			value, err := columnValue(w.Values[column])
+			if err != nil {
+				return "", nil, err
+			}
+			args[i] = value
+		}
+		return fmt.Sprintf("DELETE FROM %s WHERE %s", table, strings.Join(conditions, " AND ")), args, nil
+	}
+
+	quoted := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	updates := make([]string, 0, len(columns))
+	args := make([]any, len(columns))
+	isKey := make(map[string]bool, len(w.Key))
+	for _, column := range w.Key {
+		isKey[column] = true
+	}
+	for i, column := range columns {
+		quoted[i] = pq.QuoteIdentifier(column)
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		if !isKey[column] {
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", quoted[i], quoted[i]))
+		}
+		value, err := columnValue(w.Values[column])
+		if err != nil {
+			return "", nil, err
+		}
+		args[i] = value
+	}
+
+	conflict := "DO NOTHING"
+	if len(updates) > 0 {
+		conflict = "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) %s",
+		table, strings.Join(quoted, ", "), strings.Join(placeholders, ", "),
+		strings.Join(key, ", "), conflict), args, nil
+}
+
+// columnValue converts a JSON-decoded value to a statement argument. Objects
+// and arrays are passed as JSON text.
+func columnValue(value any) (any, error) {
+	switch value.(type) {
+	case map[string]any, []any:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode value: %w", err)
+		}
+		return string(data), nil
+	default:
+		return value, nil
+	}
+}
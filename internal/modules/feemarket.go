@@ -0,0 +1,72 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("feemarket", func() ModuleIngester { return &feeMarketModule{} })
+}
+
+// feeMarketModule records the base fee of a chain's fee market module at every
+// poll, for gas price recommendations. The denom of a base fee the fee market
+// does not name, as Osmosis' and EVM ones, is the module's denom option, the
+// staking denom by default. Requires ClickHouse; streamed changes are ignored.
+type feeMarketModule struct {
+	Base
+}
+
+// Name returns the module name
+func (m *feeMarketModule) Name() string {
+	return "feemarket"
+}
+
+// Poll records the current base fee in each denom
+func (m *feeMarketModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	if env.Storage.ClickHouse() == nil {
+		return nil
+	}
+
+	client, ok := env.Client.(cosmos.FeeMarketClient)
+	if !ok {
+		return fmt.Errorf("chain client does not support fee market queries")
+	}
+
+	prices, err := client.GetGasPrices(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	fees := make([]types.BaseFee, 0, len(prices))
+	for _, price := range prices {
+		denom := price.Denom
+		if denom == "" {
+			denom = module.Option("denom", env.Chain.StakingDenom())
+		}
+		fees = append(fees, types.BaseFee{
+			ChainName: env.Chain.Name,
+			Denom:     denom,
+			Amount:    price.Amount,
+			Height:    height,
+			Timestamp: now,
+		})
+	}
+
+	if err := env.Storage.ClickHouse().InsertBaseFees(ctx, fees); err != nil {
+		return fmt.Errorf("failed to insert base fees: %w", err)
+	}
+
+	env.Logger.Debug("Fee market base fee recorded",
+		zap.Int("denoms", len(fees)),
+		zap.Int64("height", height))
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	eventsv1 "github.com/cosmos/state-mesh/pkg/events/v1"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("gov", func() ModuleIngester { return &govModule{} })
+}
+
+// govModule ingests governance proposals
+type govModule struct {
+	Base
+}
+
+// Name returns the module name
+func (m *govModule) Name() string {
+	return "gov"
+}
+
+// Poll ingests the gov params and governance proposals, decoding what their
+// messages change
+func (m *govModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	if err := ingestParams(ctx, env, m.Name(), height); err != nil {
+		return err
+	}
+
+	// Get all proposals
+	proposals, err := env.Client.GetProposals(ctx, 0) // 0 = all statuses
+	if err != nil {
+		return fmt.Errorf("failed to get proposals: %w", err)
+	}
+
+	changes := env.Dedup.Batch()
+	now := time.Now()
+	var changed []types.Proposal
+	var events []types.OutboxMessage
+	for i := range proposals {
+		proposal := convertProposal(env.Chain.Name, &proposals[i])
+		key := fmt.Sprintf("proposal/%d", proposal.ProposalID)
+		if !changes.Changed(key, proposal) {
+			continue
+		}
+		proposal.Height = height
+		proposal.UpdatedAt = now
+		changed = append(changed, proposal)
+
+		eventID := streaming.EventID(env.Chain.Name, m.Name(), height, []byte(key))
+		message, err := streaming.ProposalEventMessage(eventsv1.NewProposalEvent(eventID, &proposal))
+		if err != nil {
+			return err
+		}
+		events = append(events, message)
+	}
+
+	if len(changed) > 0 {
+		tx, err := env.Storage.BeginTx(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		if err := tx.State().UpsertProposals(ctx, changed); err != nil {
+			return fmt.Errorf("failed to upsert proposals: %w", err)
+		}
+		if err := enqueueEvents(ctx, env, tx, events); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+	changes.Commit()
+	publishEvents(ctx, env, events)
+
+	env.Logger.Debug("Governance module state ingested",
+		zap.Int("proposals", len(proposals)),
+		zap.Int("changed", len(changed)),
+		zap.Int64("height", height))
+
+	return nil
+}
+
+// convertProposal converts a gov v1 proposal, decoding its content
+func convertProposal(chainName string, proposal *govtypes.Proposal) types.Proposal {
+	converted := types.Proposal{
+		ChainName:  chainName,
+		ProposalID: proposal.Id,
+		Content:    cosmos.DecodeProposalContent(proposal),
+		Status:     proposal.Status.String(),
+
+		SubmitTime:      timeOrZero(proposal.SubmitTime),
+		DepositEndTime:  timeOrZero(proposal.DepositEndTime),
+		VotingStartTime: timeOrZero(proposal.VotingStartTime),
+		VotingEndTime:   timeOrZero(proposal.VotingEndTime),
+	}
+	if tally := proposal.FinalTallyResult; tally != nil {
+		converted.FinalTallyResult = types.TallyResult{
+			Yes:        tally.YesCount,
+			Abstain:    tally.AbstainCount,
+			No:         tally.NoCount,
+			NoWithVeto: tally.NoWithVetoCount,
+		}
+	}
+	converted.TotalDeposit = make([]types.Coin, len(proposal.TotalDeposit))
+	for i, coin := range proposal.TotalDeposit {
+		converted.TotalDeposit[i] = types.Coin{Denom: coin.Denom, Amount: coin.Amount.String()}
+	}
+	return converted
+}
+
+// timeOrZero dereferences an optional time
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// HandleStateChange processes governance module state changes
+func (m *govModule) HandleStateChange(ctx context.Context, env *Env, change *types.StateChange) error {
+	// TODO: Implement governance state change processing
+	env.Logger.Debug("Governance state change",
+		zap.String("key", string(change.Key)),
+		zap.Int64("height", change.Height))
+	return nil
+}
@@ -0,0 +1,80 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// stuckPackets is the number of packets each channel sent that are stuck, for
+// alerting on channels whose relayers stopped
+var stuckPackets = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "statemesh",
+	Subsystem: "ibc",
+	Name:      "stuck_packets",
+	Help:      "Packets sent over a channel that were neither acknowledged nor timed out in time",
+}, []string{"chain", "channel"})
+
+func init() {
+	Register("ibc", func() ModuleIngester { return &ibcModule{stuck: make(map[string]uint64)} })
+}
+
+// ibcModule watches for stuck packets among the IBC packet events the blocks
+// module indexes, exporting their number per channel and warning when it
+// grows. Options:
+//
+//	stuck_after: how long a packet may go unacknowledged, "1h" by default
+//	window:      how far back sent packets are checked, "168h" by default
+type ibcModule struct {
+	Base
+	stuck map[string]uint64 // stuck packets by channel at the last poll
+}
+
+// Name returns the module name
+func (m *ibcModule) Name() string {
+	return "ibc"
+}
+
+// Poll updates the stuck packet counts of the chain's channels
+func (m *ibcModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	if env.Storage.ClickHouse() == nil {
+		return nil
+	}
+
+	stuckAfter, err := time.ParseDuration(module.Option("stuck_after", "1h"))
+	if err != nil || stuckAfter <= 0 {
+		return fmt.Errorf("invalid stuck_after option %q", module.Option("stuck_after", ""))
+	}
+	window, err := time.ParseDuration(module.Option("window", "168h"))
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid window option %q", module.Option("window", ""))
+	}
+
+	channels, err := env.Storage.ClickHouse().GetChannelPerformance(ctx, env.Chain.Name, time.Now().Add(-window), stuckAfter)
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		stuckPackets.WithLabelValues(env.Chain.Name, channel.Channel).Set(float64(channel.Stuck))
+		if channel.Stuck > m.stuck[channel.Channel] {
+			env.Logger.Warn("IBC packets stuck",
+				zap.String("channel", channel.Channel),
+				zap.String("counterparty_channel", channel.CounterpartyChannel),
+				zap.Uint64("stuck", channel.Stuck),
+				zap.Duration("stuck_after", stuckAfter))
+		}
+		m.stuck[channel.Channel] = channel.Stuck
+	}
+
+	env.Logger.Debug("IBC channels checked",
+		zap.Int("channels", len(channels)),
+		zap.Int64("height", height))
+
+	return nil
+}
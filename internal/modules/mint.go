@@ -0,0 +1,96 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("mint", func() ModuleIngester { return &mintModule{} })
+}
+
+// mintModule ingests mint parameters, inflation and annual provisions
+type mintModule struct {
+	Base
+}
+
+// Name returns the module name
+func (m *mintModule) Name() string {
+	return "mint"
+}
+
+// Poll ingests the mint parameters with the current inflation
+func (m *mintModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	if err := ingestParams(ctx, env, m.Name(), height); err != nil {
+		return err
+	}
+
+	params, err := env.Client.GetMintParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	inflation, err := env.Client.GetInflation(ctx)
+	if err != nil {
+		return err
+	}
+
+	provisions, err := env.Client.GetAnnualProvisions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Start transaction
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	mint := &types.MintParams{
+		ChainName:           env.Chain.Name,
+		MintDenom:           params.MintDenom,
+		InflationRateChange: params.InflationRateChange.String(),
+		InflationMax:        params.InflationMax.String(),
+		InflationMin:        params.InflationMin.String(),
+		GoalBonded:          params.GoalBonded.String(),
+		BlocksPerYear:       params.BlocksPerYear,
+		CurrentInflation:    inflation,
+		AnnualProvisions:    provisions,
+	}
+
+	changes := env.Dedup.Batch()
+	if changes.Changed("mint", mint) {
+		mint.Height = height
+		mint.UpdatedAt = time.Now()
+		if err := tx.State().UpsertMintParams(ctx, mint); err != nil {
+			return fmt.Errorf("failed to upsert mint params: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	changes.Commit()
+
+	env.Logger.Debug("Mint module state ingested",
+		zap.String("inflation", inflation),
+		zap.Int64("height", height))
+
+	return nil
+}
+
+// HandleStateChange processes mint module state changes
+func (m *mintModule) HandleStateChange(ctx context.Context, env *Env, change *types.StateChange) error {
+	// TODO: Implement mint state change processing
+	env.Logger.Debug("Mint state change",
+		zap.String("key", string(change.Key)),
+		zap.Int64("height", change.Height))
+	return nil
+}
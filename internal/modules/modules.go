@@ -0,0 +1,195 @@
+// Package modules defines how the state of a Cosmos SDK module is ingested and
+// holds the registry of modules the ingester and state listener support.
+// Built-in modules register themselves the same way custom ones do: a package
+// implementing ModuleIngester calls Register from an init function and is
+// linked into the binary with a blank import.
+package modules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/dedup"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+// Env is the chain a module instance ingests and what it writes to
+type Env struct {
+	Chain     config.ChainConfig
+	Client    cosmos.ChainClient // nil when handling streamed state changes
+	Storage   *storage.Manager
+	Streaming *streaming.Manager // nil when streaming is disabled
+	Outbox    bool               // events are written to the outbox instead of published
+	Dedup     *dedup.Cache       // nil disables change detection
+	Logger    *zap.Logger
+}
+
+// ModuleIngester ingests the state of one module. The ingester and the state
+// listener each create an instance per chain, so an instance may keep state
+// about its chain between calls; calls to an instance are not concurrent.
+type ModuleIngester interface {
+	// Name returns the module's canonical name, its store key
+	Name() string
+
+	// Poll ingests the module's state at height through the chain client
+	Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error
+
+	// HandleStateChange processes a change to the module's store streamed
+	// through ADR-038
+	HandleStateChange(ctx context.Context, env *Env, change *types.StateChange) error
+
+	// Schema returns the PostgreSQL statements creating the tables the module
+	// writes beyond the built-in schema. They run at every start, so they must
+	// be idempotent.
+	Schema() []string
+}
+
+// Factory creates an instance of a module
+type Factory func() ModuleIngester
+
+// Base implements ModuleIngester methods as no-ops, for modules to embed and
+// override what they support
+type Base struct{}
+
+// Poll does nothing
+func (Base) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	return nil
+}
+
+// HandleStateChange ignores the change
+func (Base) HandleStateChange(ctx context.Context, env *Env, change *types.StateChange) error {
+	return nil
+}
+
+// Schema returns no statements
+func (Base) Schema() []string {
+	return nil
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]Factory)
+)
+
+// Register makes a module available under its canonical name. It panics if
+// the name is registered twice.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	name = config.CanonicalModuleName(name)
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("modules: module %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// New creates an instance of the named module, resolving aliases
+func New(name string) (ModuleIngester, bool) {
+	mu.RLock()
+	factory, ok := registry[config.CanonicalModuleName(name)]
+	mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// Names returns the names of the registered modules in order
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ForChain creates an instance of each enabled module of a chain, keyed by
+// canonical name. Modules that are not registered are returned separately.
+func ForChain(chain config.ChainConfig) (map[string]ModuleIngester, []string) {
+	instances := make(map[string]ModuleIngester)
+	var unknown []string
+	for _, module := range chain.EnabledModules() {
+		instance, ok := New(module.Name)
+		if !ok {
+			unknown = append(unknown, module.Name)
+			continue
+		}
+		instances[instance.Name()] = instance
+	}
+	return instances, unknown
+}
+
+// ApplySchemas creates the tables of the modules enabled on any of the chains
+func ApplySchemas(ctx context.Context, store *storage.Manager, chains []config.ChainConfig) error {
+	applied := make(map[string]bool)
+	for _, chain := range chains {
+		for _, module := range chain.EnabledModules() {
+			instance, ok := New(module.Name)
+			if !ok || applied[instance.Name()] {
+				continue
+			}
+			applied[instance.Name()] = true
+
+			if statements := instance.Schema(); len(statements) > 0 {
+				if err := store.ApplySchema(ctx, statements); err != nil {
+					return fmt.Errorf("failed to apply %s module schema: %w", instance.Name(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// discoverAccount registers an address seen in a state change in the accounts
+// table, recording the height it was first seen
+func discoverAccount(ctx context.Context, tx *storage.Tx, address string, change *types.StateChange) error {
+	account := types.Account{
+		ChainName: change.ChainName,
+		Address:   address,
+		Height:    change.Height,
+		CreatedAt: change.Timestamp,
+		UpdatedAt: change.Timestamp,
+	}
+
+	if err := tx.State().UpsertAccount(ctx, &account); err != nil {
+		return fmt.Errorf("failed to register account: %w", err)
+	}
+	return nil
+}
+
+// enqueueEvents writes the messages of events to the outbox in tx, so that
+// they are published if and only if tx commits. It does nothing unless the
+// outbox is enabled.
+func enqueueEvents(ctx context.Context, env *Env, tx *storage.Tx, messages []types.OutboxMessage) error {
+	if !env.Outbox || len(messages) == 0 {
+		return nil
+	}
+	if err := tx.State().EnqueueOutbox(ctx, messages); err != nil {
+		return fmt.Errorf("failed to enqueue events: %w", err)
+	}
+	return nil
+}
+
+// publishEvents publishes the messages of events whose state was committed,
+// when streaming without the outbox. Failures are logged: the state is
+// committed either way.
+func publishEvents(ctx context.Context, env *Env, messages []types.OutboxMessage) {
+	if env.Streaming == nil || env.Outbox || len(messages) == 0 {
+		return
+	}
+	if _, err := env.Streaming.PublishBatch(ctx, messages); err != nil {
+		env.Logger.Warn("Failed to publish events", zap.Int("events", len(messages)), zap.Error(err))
+	}
+}
@@ -0,0 +1,59 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+// ingestParams ingests the parameters of one of cosmos.ParamsModules when
+// they changed, the store recording which did. Chain clients that cannot
+// query params are skipped.
+func ingestParams(ctx context.Context, env *Env, module string, height int64) error {
+	client, ok := env.Client.(cosmos.ParamsClient)
+	if !ok {
+		return nil
+	}
+
+	values, err := client.GetModuleParams(ctx, module)
+	if err != nil {
+		return err
+	}
+	params := &types.ChainParams{
+		ChainName: env.Chain.Name,
+		Module:    module,
+		Params:    values,
+	}
+
+	changes := env.Dedup.Batch()
+	if !changes.Changed("params/"+module, params) {
+		return nil
+	}
+	params.Height = height
+	params.UpdatedAt = time.Now()
+
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.State().UpsertChainParams(ctx, params); err != nil {
+		return fmt.Errorf("failed to upsert %s params: %w", module, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	changes.Commit()
+
+	env.Logger.Debug("Module params ingested",
+		zap.String("module", module),
+		zap.Int("params", len(values)),
+		zap.Int64("height", height))
+	return nil
+}
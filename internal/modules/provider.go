@@ -0,0 +1,103 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("provider", func() ModuleIngester { return &providerModule{} })
+}
+
+// providerModule ingests the consumer chains of an Interchain Security provider
+// chain and the provider validators securing each. Streamed changes to the
+// provider store are ignored; polling keeps the snapshot current.
+type providerModule struct {
+	Base
+}
+
+// Name returns the module name
+func (m *providerModule) Name() string {
+	return "provider"
+}
+
+// Poll ingests the consumer chains with their validator sets
+func (m *providerModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	client, ok := env.Client.(cosmos.ProviderClient)
+	if !ok {
+		return fmt.Errorf("chain client does not support Interchain Security provider queries")
+	}
+
+	chains, err := client.GetConsumerChains(ctx)
+	if err != nil {
+		return err
+	}
+
+	consumers := make([]types.ConsumerChain, 0, len(chains))
+	for _, chain := range chains {
+		keys, err := client.GetConsumerValidators(ctx, chain)
+		if err != nil {
+			return err
+		}
+
+		consumer := types.ConsumerChain{
+			ProviderChain: env.Chain.Name,
+			ChainID:       chain.ChainID,
+			ConsumerID:    chain.ConsumerID,
+			ClientID:      chain.ClientID,
+			Validators:    make([]types.ConsumerValidator, len(keys)),
+		}
+		for i, key := range keys {
+			consumer.Validators[i] = types.ConsumerValidator{
+				ProviderAddress: key.ProviderAddress,
+				ConsumerAddress: key.ConsumerAddress,
+			}
+		}
+		sort.Slice(consumer.Validators, func(i, j int) bool {
+			return consumer.Validators[i].ProviderAddress < consumer.Validators[j].ProviderAddress
+		})
+		consumers = append(consumers, consumer)
+	}
+	sort.Slice(consumers, func(i, j int) bool { return consumers[i].ChainID < consumers[j].ChainID })
+
+	changes := env.Dedup.Batch()
+	if !changes.Changed("consumers", consumers) {
+		return nil
+	}
+
+	now := time.Now()
+	for i := range consumers {
+		consumers[i].Height = height
+		consumers[i].UpdatedAt = now
+	}
+
+	// Start transaction
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.State().ReplaceConsumerChains(ctx, env.Chain.Name, consumers); err != nil {
+		return fmt.Errorf("failed to replace consumer chains: %w", err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	changes.Commit()
+
+	env.Logger.Debug("Provider module state ingested",
+		zap.Int("consumers", len(consumers)),
+		zap.Int64("height", height))
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package modules
+
+import (
+	"context"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("slashing", func() ModuleIngester { return &slashingModule{} })
+}
+
+// slashingModule ingests slashing module state
+type slashingModule struct {
+	Base
+}
+
+// Name returns the module name
+func (m *slashingModule) Name() string {
+	return "slashing"
+}
+
+// Poll ingests the slashing parameters
+func (m *slashingModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	return ingestParams(ctx, env, m.Name(), height)
+}
+
+// HandleStateChange processes slashing module state changes
+func (m *slashingModule) HandleStateChange(ctx context.Context, env *Env, change *types.StateChange) error {
+	// TODO: Implement slashing state change processing
+	env.Logger.Debug("Slashing state change",
+		zap.String("key", string(change.Key)),
+		zap.Int64("height", change.Height))
+	return nil
+}
@@ -0,0 +1,405 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/streaming"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	eventsv1 "github.com/cosmos/state-mesh/pkg/events/v1"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("staking", func() ModuleIngester { return &stakingModule{} })
+}
+
+// stakingModule ingests validators, the unbonding queue, redelegations and
+// streamed delegation changes, and records the validators' commission rate
+// changes
+type stakingModule struct {
+	Base
+
+	// rates holds the stored commission rate of each validator, loaded on the
+	// first poll, for detecting commission changes
+	rates map[string]decimal.Decimal
+}
+
+// Name returns the module name
+func (m *stakingModule) Name() string {
+	return "staking"
+}
+
+// Poll ingests the staking params, validators, the unbonding queue and the
+// pending redelegations
+func (m *stakingModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	if err := ingestParams(ctx, env, m.Name(), height); err != nil {
+		return err
+	}
+
+	// Get all validators
+	validators, err := env.Client.GetValidators(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to get validators: %w", err)
+	}
+
+	// Start transaction
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	changes := env.Dedup.Batch()
+	chainName := env.Chain.Name
+
+	if m.rates == nil {
+		if err := m.loadRates(ctx, env); err != nil {
+			env.Logger.Warn("Failed to load commission rates, commission changes are not detected", zap.Error(err))
+		}
+	}
+	rates := make(map[string]decimal.Decimal, len(validators))
+	var events []types.OutboxMessage
+
+	// Process validators, skipping those unchanged since the last snapshot
+	set := types.ValidatorSetSnapshot{ChainName: chainName, Height: height, Timestamp: now}
+	for _, val := range validators {
+		consensusAddress, err := cosmos.ValidatorConsensusAddress(val)
+		if err != nil {
+			env.Logger.Warn("Failed to derive consensus address",
+				zap.String("validator", val.OperatorAddress),
+				zap.Error(err))
+		}
+
+		validator := &types.Validator{
+			ChainName:        chainName,
+			OperatorAddress:  val.OperatorAddress,
+			ConsensusPubkey:  val.ConsensusPubkey.String(),
+			ConsensusAddress: consensusAddress,
+			Jailed:           val.Jailed,
+			Status:           val.Status.String(),
+			Tokens:           val.Tokens.String(),
+			DelegatorShares:  val.DelegatorShares.String(),
+			Description: types.ValidatorDescription{
+				Moniker:         val.Description.Moniker,
+				Identity:        val.Description.Identity,
+				Website:         val.Description.Website,
+				SecurityContact: val.Description.SecurityContact,
+				Details:         val.Description.Details,
+			},
+			UnbondingHeight: val.UnbondingHeight,
+			UnbondingTime:   val.UnbondingTime,
+			Commission: types.ValidatorCommission{
+				Rate:          val.Commission.Rate.String(),
+				MaxRate:       val.Commission.MaxRate.String(),
+				MaxChangeRate: val.Commission.MaxChangeRate.String(),
+			},
+			MinSelfDelegation: val.MinSelfDelegation.String(),
+		}
+
+		if val.IsBonded() {
+			member := types.ValidatorSetMember{
+				OperatorAddress:  val.OperatorAddress,
+				ConsensusAddress: consensusAddress,
+				Moniker:          val.Description.Moniker,
+				Tokens:           val.Tokens.String(),
+				VotingPower:      val.ConsensusPower(sdk.DefaultPowerReduction),
+			}
+			set.Validators = append(set.Validators, member)
+			set.TotalVotingPower += member.VotingPower
+		}
+
+		rate := decimal.NewFromBigInt(val.Commission.Rate.BigInt(), -sdkmath.LegacyPrecision)
+		rates[val.OperatorAddress] = rate
+		if previous, ok := m.rates[val.OperatorAddress]; ok && !previous.Equal(rate) {
+			change := &types.CommissionChange{
+				ChainName:       chainName,
+				OperatorAddress: val.OperatorAddress,
+				Moniker:         val.Description.Moniker,
+				OldRate:         previous.String(),
+				NewRate:         rate.String(),
+				EffectiveTime:   val.Commission.UpdateTime,
+				Height:          height,
+				DetectedAt:      now,
+			}
+			if err := tx.State().InsertCommissionChange(ctx, change); err != nil {
+				return fmt.Errorf("failed to insert commission change: %w", err)
+			}
+			env.Logger.Info("Validator commission changed",
+				zap.String("validator", val.OperatorAddress),
+				zap.String("old_rate", change.OldRate),
+				zap.String("new_rate", change.NewRate),
+				zap.Time("effective_time", change.EffectiveTime))
+		}
+
+		if changes.Changed("validator/"+val.OperatorAddress, validator) {
+			validator.Height = height
+			validator.UpdatedAt = now
+			if err := tx.State().UpsertValidator(ctx, validator); err != nil {
+				return fmt.Errorf("failed to upsert validator: %w", err)
+			}
+
+			eventID := streaming.EventID(chainName, m.Name(), height, []byte("validators/"+val.OperatorAddress))
+			message, err := streaming.ValidatorEventMessage(eventsv1.NewValidatorEvent(eventID, validator))
+			if err != nil {
+				return err
+			}
+			events = append(events, message)
+		}
+
+		if consensusAddress != "" && changes.Changed("consensus/"+consensusAddress, val.OperatorAddress) {
+			if err := tx.State().UpsertConsensusAddress(ctx, chainName, consensusAddress, val.OperatorAddress, height); err != nil {
+				return fmt.Errorf("failed to upsert consensus address: %w", err)
+			}
+		}
+	}
+
+	// Snapshot the unbonding queue
+	var unbondings []types.UnbondingDelegation
+	for _, val := range validators {
+		ubds, err := env.Client.GetValidatorUnbondingDelegations(ctx, val.OperatorAddress)
+		if err != nil {
+			return fmt.Errorf("failed to get unbonding delegations for %s: %w", val.OperatorAddress, err)
+		}
+
+		for _, ubd := range ubds {
+			unbonding := types.UnbondingDelegation{
+				ChainName:        chainName,
+				DelegatorAddress: ubd.DelegatorAddress,
+				ValidatorAddress: ubd.ValidatorAddress,
+				Height:           height,
+				UpdatedAt:        now,
+			}
+			for _, entry := range ubd.Entries {
+				unbonding.Entries = append(unbonding.Entries, types.UnbondingDelegationEntry{
+					CreationHeight: entry.CreationHeight,
+					CompletionTime: entry.CompletionTime,
+					InitialBalance: entry.InitialBalance.String(),
+					Balance:        entry.Balance.String(),
+				})
+			}
+			unbondings = append(unbondings, unbonding)
+		}
+	}
+
+	if changes.Changed("unbondings", unbondingEntries(unbondings)) {
+		if err := tx.State().ReplaceUnbondingDelegations(ctx, chainName, unbondings); err != nil {
+			return fmt.Errorf("failed to replace unbonding delegations: %w", err)
+		}
+	}
+
+	// Snapshot the pending redelegations
+	var redelegations []types.Redelegation
+	for _, val := range validators {
+		reds, err := env.Client.GetValidatorRedelegations(ctx, val.OperatorAddress)
+		if err != nil {
+			return fmt.Errorf("failed to get redelegations for %s: %w", val.OperatorAddress, err)
+		}
+
+		for _, red := range reds {
+			redelegation := types.Redelegation{
+				ChainName:           chainName,
+				DelegatorAddress:    red.Redelegation.DelegatorAddress,
+				ValidatorSrcAddress: red.Redelegation.ValidatorSrcAddress,
+				ValidatorDstAddress: red.Redelegation.ValidatorDstAddress,
+				Height:              height,
+				UpdatedAt:           now,
+			}
+			for _, entry := range red.Redelegation.Entries {
+				redelegation.Entries = append(redelegation.Entries, types.RedelegationEntry{
+					CreationHeight: entry.CreationHeight,
+					CompletionTime: entry.CompletionTime,
+					InitialBalance: entry.InitialBalance.String(),
+					SharesDst:      entry.SharesDst.String(),
+				})
+			}
+			redelegations = append(redelegations, redelegation)
+		}
+	}
+
+	if changes.Changed("redelegations", redelegationEntries(redelegations)) {
+		if err := tx.State().ReplaceRedelegations(ctx, chainName, redelegations); err != nil {
+			return fmt.Errorf("failed to replace redelegations: %w", err)
+		}
+	}
+
+	// Snapshot the bonded set for validator set history when it changed
+	sort.Slice(set.Validators, func(i, j int) bool {
+		if set.Validators[i].VotingPower != set.Validators[j].VotingPower {
+			return set.Validators[i].VotingPower > set.Validators[j].VotingPower
+		}
+		return set.Validators[i].OperatorAddress < set.Validators[j].OperatorAddress
+	})
+	snapshot := env.Storage.ClickHouse() != nil && changes.Changed("validator_set", set.Validators)
+
+	if err := enqueueEvents(ctx, env, tx, events); err != nil {
+		return err
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	changes.Commit()
+	publishEvents(ctx, env, events)
+	if m.rates != nil {
+		m.rates = rates
+	}
+
+	if snapshot {
+		if err := env.Storage.ClickHouse().InsertValidatorSet(ctx, set); err != nil {
+			env.Logger.Warn("Failed to insert validator set snapshot to ClickHouse", zap.Error(err))
+		}
+	}
+
+	env.Logger.Debug("Staking module state ingested",
+		zap.Int("validators", len(validators)),
+		zap.Int("unbonding_delegations", len(unbondings)),
+		zap.Int("redelegations", len(redelegations)),
+		zap.Int64("height", height))
+
+	return nil
+}
+
+// loadRates reads the stored commission rates of the chain's validators
+func (m *stakingModule) loadRates(ctx context.Context, env *Env) error {
+	rates := make(map[string]decimal.Decimal)
+	err := env.Storage.State().EachValidator(ctx, env.Chain.Name, func(validator *types.Validator) error {
+		rate, err := decimal.NewFromString(validator.Commission.Rate)
+		if err != nil {
+			return fmt.Errorf("invalid commission rate of %s: %w", validator.OperatorAddress, err)
+		}
+		rates[validator.OperatorAddress] = rate
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.rates = rates
+	return nil
+}
+
+// unbondingEntries returns the content of an unbonding queue snapshot without
+// its snapshot height and time, for change detection
+func unbondingEntries(unbondings []types.UnbondingDelegation) []types.UnbondingDelegation {
+	entries := make([]types.UnbondingDelegation, len(unbondings))
+	for i, ubd := range unbondings {
+		ubd.Height = 0
+		ubd.UpdatedAt = time.Time{}
+		entries[i] = ubd
+	}
+	return entries
+}
+
+// redelegationEntries returns the content of a redelegation snapshot without
+// its snapshot height and time, for change detection
+func redelegationEntries(redelegations []types.Redelegation) []types.Redelegation {
+	entries := make([]types.Redelegation, len(redelegations))
+	for i, red := range redelegations {
+		red.Height = 0
+		red.UpdatedAt = time.Time{}
+		entries[i] = red
+	}
+	return entries
+}
+
+// HandleStateChange processes staking module state changes.
+// Key formats: validators/{validator}, delegations/{delegator}/{validator},
+// redelegations/{delegator}/{src validator}/{dst validator}, etc.
+func (m *stakingModule) HandleStateChange(ctx context.Context, env *Env, change *types.StateChange) error {
+	key := string(change.Key)
+	if validator, ok := strings.CutPrefix(key, "validators/"); ok && validator != "" {
+		// TODO: Parse validator data from protobuf value
+		env.Logger.Debug("Validator change detected",
+			zap.String("validator", validator),
+			zap.Int64("height", change.Height))
+		return nil
+	}
+	if delegation, ok := strings.CutPrefix(key, "delegations/"); ok && delegation != "" {
+		return m.handleDelegationChange(ctx, env, change, delegation)
+	}
+	if redelegation, ok := strings.CutPrefix(key, "redelegations/"); ok && redelegation != "" {
+		return m.handleRedelegationChange(ctx, env, change, redelegation)
+	}
+	return nil
+}
+
+// handleDelegationChange registers the delegator of a changed delegation.
+// Key remainder format: {delegator}/{validator}
+func (m *stakingModule) handleDelegationChange(ctx context.Context, env *Env, change *types.StateChange, keyRemainder string) error {
+	// TODO: Parse delegation data from protobuf value
+	env.Logger.Debug("Delegation change detected",
+		zap.String("key", keyRemainder),
+		zap.Int64("height", change.Height))
+
+	parts := strings.SplitN(keyRemainder, "/", 2)
+	if len(parts) < 2 || parts[0] == "" {
+		return fmt.Errorf("invalid delegation key format")
+	}
+
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := discoverAccount(ctx, tx, parts[0], change); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// handleRedelegationChange records the stake moved by a new redelegation entry
+// for the delegation flow graph. The value is the entry's amount; removals of
+// completed entries move nothing.
+// Key remainder format: {delegator}/{src validator}/{dst validator}
+func (m *stakingModule) handleRedelegationChange(ctx context.Context, env *Env, change *types.StateChange, keyRemainder string) error {
+	parts := strings.SplitN(keyRemainder, "/", 3)
+	if len(parts) < 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("invalid redelegation key format")
+	}
+	if change.Delete {
+		return nil
+	}
+
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := discoverAccount(ctx, tx, parts[0], change); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if env.Storage.ClickHouse() != nil {
+		event := types.RedelegationEvent{
+			EventID:             streaming.EventID(change.ChainName, change.StoreKey, change.Height, change.Key),
+			Timestamp:           change.Timestamp,
+			ChainName:           change.ChainName,
+			DelegatorAddress:    parts[0],
+			SrcValidatorAddress: parts[1],
+			DstValidatorAddress: parts[2],
+			Amount:              string(change.Value),
+			Height:              change.Height,
+		}
+		if err := env.Storage.ClickHouse().InsertRedelegationEvents(ctx, []types.RedelegationEvent{event}); err != nil {
+			env.Logger.Warn("Failed to insert redelegation event to ClickHouse", zap.Error(err))
+		}
+	}
+
+	return nil
+}
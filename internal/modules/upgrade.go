@@ -0,0 +1,88 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("upgrade", func() ModuleIngester { return &upgradeModule{} })
+}
+
+// upgradeModule ingests the software upgrade scheduled by the upgrade module
+type upgradeModule struct {
+	Base
+}
+
+// Name returns the module name
+func (m *upgradeModule) Name() string {
+	return "upgrade"
+}
+
+// Poll ingests the scheduled upgrade, removing it once applied or cancelled
+func (m *upgradeModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	client, ok := env.Client.(cosmos.UpgradeClient)
+	if !ok {
+		return fmt.Errorf("chain client does not support upgrade module queries")
+	}
+
+	current, err := client.GetUpgradePlan(ctx)
+	if err != nil {
+		return err
+	}
+
+	var plan *types.UpgradePlan
+	if current != nil {
+		plan = &types.UpgradePlan{
+			ChainName:     env.Chain.Name,
+			Name:          current.Name,
+			UpgradeHeight: current.Height,
+			Info:          current.Info,
+		}
+	}
+
+	changes := env.Dedup.Batch()
+	if !changes.Changed("upgrade_plan", plan) {
+		return nil
+	}
+	if plan != nil {
+		plan.Height = height
+		plan.UpdatedAt = time.Now()
+	}
+
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.State().ReplaceUpgradePlan(ctx, env.Chain.Name, plan); err != nil {
+		return fmt.Errorf("failed to replace upgrade plan: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	changes.Commit()
+
+	if plan != nil {
+		env.Logger.Info("Software upgrade scheduled",
+			zap.String("name", plan.Name),
+			zap.Int64("upgrade_height", plan.UpgradeHeight))
+	}
+	return nil
+}
+
+// HandleStateChange processes upgrade module state changes
+func (m *upgradeModule) HandleStateChange(ctx context.Context, env *Env, change *types.StateChange) error {
+	env.Logger.Debug("Upgrade state change",
+		zap.String("key", string(change.Key)),
+		zap.Int64("height", change.Height))
+	return nil
+}
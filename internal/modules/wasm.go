@@ -0,0 +1,157 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+func init() {
+	Register("wasm", func() ModuleIngester { return &wasmModule{} })
+}
+
+// wasmModule ingests the CW20 balances and CW721 tokens of watched addresses
+// by smart-querying the token contracts listed in the cw20 and cw721 options.
+// Watched addresses are those on tenant watchlists for the chain plus the
+// addresses option. Contract storage is opaque, so streamed changes are
+// ignored.
+type wasmModule struct {
+	Base
+}
+
+// Name returns the module name
+func (m *wasmModule) Name() string {
+	return "wasm"
+}
+
+// Poll ingests the metadata of the configured contracts and the holdings of
+// the watched addresses in them
+func (m *wasmModule) Poll(ctx context.Context, env *Env, module config.ModuleConfig, height int64) error {
+	client, ok := env.Client.(cosmos.WasmClient)
+	if !ok {
+		return fmt.Errorf("chain client does not support CosmWasm queries")
+	}
+
+	cw20 := module.ListOption("cw20")
+	cw721 := module.ListOption("cw721")
+	if len(cw20) == 0 && len(cw721) == 0 {
+		return nil
+	}
+
+	addresses, err := env.Storage.Tenants().GetWatchedAddresses(ctx, env.Chain.Name)
+	if err != nil {
+		return err
+	}
+	addresses = append(addresses, module.ListOption("addresses")...)
+
+	now := time.Now()
+	var contracts []types.TokenContract
+	var holdings []types.TokenHolding
+	changes := env.Dedup.Batch()
+
+	for _, contract := range cw20 {
+		info, err := client.GetCW20TokenInfo(ctx, contract)
+		if err != nil {
+			return err
+		}
+		contracts = append(contracts, types.TokenContract{
+			ChainName: env.Chain.Name,
+			Contract:  contract,
+			Standard:  types.TokenStandardCW20,
+			Name:      info.Name,
+			Symbol:    info.Symbol,
+			Decimals:  info.Decimals,
+			Height:    height,
+			UpdatedAt: now,
+		})
+
+		for _, address := range addresses {
+			balance, err := client.GetCW20Balance(ctx, contract, address)
+			if err != nil {
+				return err
+			}
+			if !changes.Changed("cw20/"+contract+"/"+address, balance) {
+				continue
+			}
+			holdings = append(holdings, types.TokenHolding{
+				ChainName: env.Chain.Name,
+				Address:   address,
+				Contract:  contract,
+				Amount:    balance,
+				Height:    height,
+				UpdatedAt: now,
+			})
+		}
+	}
+
+	for _, contract := range cw721 {
+		info, err := client.GetCW721ContractInfo(ctx, contract)
+		if err != nil {
+			return err
+		}
+		contracts = append(contracts, types.TokenContract{
+			ChainName: env.Chain.Name,
+			Contract:  contract,
+			Standard:  types.TokenStandardCW721,
+			Name:      info.Name,
+			Symbol:    info.Symbol,
+			Height:    height,
+			UpdatedAt: now,
+		})
+
+		for _, address := range addresses {
+			tokens, err := client.GetCW721Tokens(ctx, contract, address)
+			if err != nil {
+				return err
+			}
+			if !changes.Changed("cw721/"+contract+"/"+address, tokens) {
+				continue
+			}
+			holdings = append(holdings, types.TokenHolding{
+				ChainName: env.Chain.Name,
+				Address:   address,
+				Contract:  contract,
+				Amount:    fmt.Sprint(len(tokens)),
+				TokenIDs:  tokens,
+				Height:    height,
+				UpdatedAt: now,
+			})
+		}
+	}
+
+	if !changes.Changed("contracts", contracts) && len(holdings) == 0 {
+		return nil
+	}
+
+	// Start transaction
+	tx, err := env.Storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.State().UpsertTokenContracts(ctx, contracts); err != nil {
+		return fmt.Errorf("failed to upsert token contracts: %w", err)
+	}
+	if err := tx.State().UpsertTokenHoldings(ctx, holdings); err != nil {
+		return fmt.Errorf("failed to upsert token holdings: %w", err)
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	changes.Commit()
+
+	env.Logger.Debug("Wasm module state ingested",
+		zap.Int("contracts", len(contracts)),
+		zap.Int("holdings", len(holdings)),
+		zap.Int64("height", height))
+
+	return nil
+}
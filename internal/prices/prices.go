@@ -0,0 +1,151 @@
+package prices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"go.uber.org/zap"
+)
+
+// CoinGecko API base URLs
+const (
+	coinGeckoURL    = "https://api.coingecko.com/api/v3"
+	coinGeckoProURL = "https://pro-api.coingecko.com/api/v3"
+)
+
+// requestTimeout bounds each request to CoinGecko
+const requestTimeout = 10 * time.Second
+
+// Source returns the USD price of the display denom of chains' staking
+// tokens, fixed in their configuration or read from CoinGecko. CoinGecko
+// prices are read for every chain at once and reused for the refresh
+// interval; when a read fails the previous prices are kept.
+type Source struct {
+	fixed   map[string]float64 // by chain
+	ids     map[string]string  // CoinGecko ID by chain
+	url     string
+	apiKey  string
+	refresh time.Duration
+	client  *http.Client
+	logger  *zap.Logger
+
+	mu      sync.Mutex
+	prices  map[string]float64 // by CoinGecko ID
+	fetched time.Time
+}
+
+// New creates a price source for the configured chains
+func New(cfg config.OverviewConfig, chains []config.ChainConfig, logger *zap.Logger) *Source {
+	s := &Source{
+		fixed:   make(map[string]float64),
+		ids:     make(map[string]string),
+		url:     cfg.CoinGecko.URL,
+		apiKey:  cfg.CoinGecko.APIKey,
+		refresh: cfg.PriceRefresh,
+		client:  &http.Client{Timeout: requestTimeout},
+		logger:  logger.Named("prices"),
+		prices:  make(map[string]float64),
+	}
+	if s.url == "" {
+		s.url = coinGeckoURL
+		if s.apiKey != "" {
+			s.url = coinGeckoProURL
+		}
+	}
+
+	for _, chain := range chains {
+		switch {
+		case chain.Price.USD > 0:
+			s.fixed[chain.Name] = chain.Price.USD
+		case chain.Price.CoinGeckoID != "":
+			s.ids[chain.Name] = chain.Price.CoinGeckoID
+		}
+	}
+	return s
+}
+
+// USD returns the prices of the chains that have one, by chain
+func (s *Source) USD(ctx context.Context) map[string]float64 {
+	prices := make(map[string]float64, len(s.fixed)+len(s.ids))
+	for chain, price := range s.fixed {
+		prices[chain] = price
+	}
+	if len(s.ids) == 0 {
+		return prices
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.fetched) >= s.refresh {
+		fetched, err := s.fetch(ctx)
+		if err != nil {
+			s.logger.Warn("Failed to read prices from CoinGecko, using the previous prices", zap.Error(err))
+		} else {
+			s.prices = fetched
+		}
+		// Failed reads are not retried before the next refresh either
+		s.fetched = time.Now()
+	}
+
+	for chain, id := range s.ids {
+		if price, ok := s.prices[id]; ok {
+			prices[chain] = price
+		}
+	}
+	return prices
+}
+
+// fetch reads the USD price of every configured CoinGecko ID
+func (s *Source) fetch(ctx context.Context) (map[string]float64, error) {
+	ids := make([]string, 0, len(s.ids))
+	seen := make(map[string]bool)
+	for _, id := range s.ids {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	query := url.Values{"ids": {strings.Join(ids, ",")}, "vs_currencies": {"usd"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(s.url, "/")+"/simple/price?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("x-cg-pro-api-key", s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("coingecko returned %s", resp.Status)
+	}
+
+	var body map[string]struct {
+		USD float64 `json:"usd"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode CoinGecko response: %w", err)
+	}
+
+	prices := make(map[string]float64, len(body))
+	for id, price := range body {
+		prices[id] = price.USD
+	}
+	return prices, nil
+}
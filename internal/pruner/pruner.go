@@ -0,0 +1,137 @@
+package pruner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+)
+
+// Result summarizes the rows reclaimed by a pruning run
+type Result struct {
+	BalanceHistory    int64         `json:"balance_history"`
+	DelegationHistory int64         `json:"delegation_history"`
+	DustHistory       int64         `json:"dust_history"`
+	StaleAccounts     int64         `json:"stale_accounts"` // accounts and their zero balances
+	IngestRuns        int64         `json:"ingest_runs"`
+	Duration          time.Duration `json:"duration"`
+}
+
+// Total returns the total number of reclaimed rows
+func (r *Result) Total() int64 {
+	return r.BalanceHistory + r.DelegationHistory + r.DustHistory + r.StaleAccounts + r.IngestRuns
+}
+
+// Pruner enforces retention policies on stored state
+type Pruner struct {
+	cfg     config.RetentionConfig
+	storage *storage.Manager
+	logger  *zap.Logger
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// New creates a new pruner
+func New(cfg config.RetentionConfig, storage *storage.Manager) *Pruner {
+	return &Pruner{
+		cfg:     cfg,
+		storage: storage,
+		logger:  zap.L().Named("pruner"),
+	}
+}
+
+// Start runs the pruner in the background on the configured interval
+func (p *Pruner) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	interval := p.cfg.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		p.logger.Info("Pruner started", zap.Duration("interval", interval))
+
+		for {
+			select {
+			case <-ctx.Done():
+				p.logger.Info("Pruner stopped")
+				return
+			case <-ticker.C:
+				result, err := p.RunOnce(ctx)
+				if err != nil {
+					p.logger.Error("Pruning run failed", zap.Error(err))
+					continue
+				}
+				p.logger.Info("Pruning run completed",
+					zap.Int64("reclaimed_rows", result.Total()),
+					zap.Duration("duration", result.Duration))
+			}
+		}
+	}()
+}
+
+// Stop stops the background pruner and waits for it to exit
+func (p *Pruner) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+// RunOnce applies all retention policies once
+func (p *Pruner) RunOnce(ctx context.Context) (*Result, error) {
+	start := time.Now()
+	result := &Result{}
+	store := p.storage.State()
+
+	var err error
+	if p.cfg.HistoryRetention > 0 {
+		cutoff := start.Add(-p.cfg.HistoryRetention)
+
+		result.BalanceHistory, err = store.PruneBalanceHistory(ctx, cutoff, p.cfg.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prune balance history: %w", err)
+		}
+
+		result.DelegationHistory, err = store.PruneDelegationHistory(ctx, cutoff, p.cfg.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prune delegation history: %w", err)
+		}
+	}
+
+	if p.cfg.DustThreshold != "" {
+		result.DustHistory, err = store.PruneDustHistory(ctx, p.cfg.DustThreshold, p.cfg.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prune dust account history: %w", err)
+		}
+	}
+
+	if p.cfg.StaleAccountRetention > 0 {
+		result.StaleAccounts, err = store.PruneStaleAccounts(ctx, start.Add(-p.cfg.StaleAccountRetention), p.cfg.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prune stale accounts: %w", err)
+		}
+	}
+
+	if p.cfg.IngestRunRetention > 0 {
+		result.IngestRuns, err = p.storage.IngestRuns().PruneIngestRuns(ctx, start.Add(-p.cfg.IngestRunRetention), p.cfg.BatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prune ingest runs: %w", err)
+		}
+	}
+
+	result.Duration = time.Since(start)
+	return result, nil
+}
@@ -0,0 +1,241 @@
+// Package reminders notifies tenants' webhooks and email subscriptions of the
+// unbonding entries of their watched delegators shortly before the entries
+// complete
+package reminders
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/email"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/internal/webhook"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+var deliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "statemesh",
+	Subsystem: "unbonding_reminders",
+	Name:      "deliveries_total",
+	Help:      "Unbonding reminder deliveries by channel (webhook or email) and result (success or failure)",
+}, []string{"channel", "result"})
+
+// Scheduler sends unbonding reminders. Each check it looks up the unbonding
+// entries of every tenant's watched delegators that complete within the
+// configured lead time and sends each entry once to the tenant's webhooks and
+// email subscriptions subscribed to unbonding completions. Reminders are
+// claimed in the state store before they are sent, so that only one API
+// server sends each; a reminder nothing received is retried at the next
+// check.
+type Scheduler struct {
+	cfg      config.ReminderConfig
+	storage  *storage.Manager
+	webhooks *webhook.Sender
+	emails   *email.Sender
+	logger   *zap.Logger
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// New creates an unbonding reminder scheduler, emailing with the email sender
+// when it is not nil
+func New(cfg config.ReminderConfig, storage *storage.Manager, emails *email.Sender, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		cfg:      cfg,
+		storage:  storage,
+		webhooks: webhook.NewSender(cfg.Timeout),
+		emails:   emails,
+		logger:   logger.Named("unbonding_reminders"),
+	}
+}
+
+// Start looks for upcoming completions in the background on the check
+// interval
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.cfg.CheckInterval)
+		defer ticker.Stop()
+
+		s.logger.Info("Unbonding reminder scheduler started",
+			zap.Duration("before", s.cfg.Before),
+			zap.Duration("check_interval", s.cfg.CheckInterval))
+
+		for {
+			select {
+			case <-ctx.Done():
+				s.logger.Info("Unbonding reminder scheduler stopped")
+				return
+			case <-ticker.C:
+				if err := s.RunOnce(ctx); err != nil {
+					s.logger.Error("Failed to send unbonding reminders", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduler and waits for the reminders being sent
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// RunOnce sends the reminders of the entries completing within the lead time
+// and forgets the reminders of completed entries
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	tenants, err := s.storage.Tenants().GetTenants(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var errs []error
+	for _, tenant := range tenants {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.remindTenant(ctx, tenant.ID, now); err != nil {
+			s.logger.Error("Failed to send unbonding reminders",
+				zap.String("tenant", tenant.ID),
+				zap.Error(err))
+			errs = append(errs, err)
+		}
+	}
+
+	// Entries completed a day ago are no longer listed, so their reminders
+	// cannot be sent twice
+	if pruned, err := s.storage.Tenants().PruneUnbondingReminders(ctx, now.Add(-24*time.Hour)); err != nil {
+		errs = append(errs, err)
+	} else if pruned > 0 {
+		s.logger.Debug("Pruned unbonding reminders", zap.Int64("reminders", pruned))
+	}
+
+	return errors.Join(errs...)
+}
+
+// remindTenant sends a tenant the reminders of its watched delegators'
+// entries completing between now and the lead time
+func (s *Scheduler) remindTenant(ctx context.Context, tenantID string, now time.Time) error {
+	webhooks, err := s.storage.Tenants().GetWebhooks(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	var subscribed []types.Webhook
+	for _, hook := range webhooks {
+		if webhook.Subscribed(hook, webhook.EventUnbondingCompletion) {
+			subscribed = append(subscribed, hook)
+		}
+	}
+	emails, err := s.emails.Subscriptions(ctx, s.storage.Tenants(), tenantID, webhook.EventUnbondingCompletion)
+	if err != nil {
+		return err
+	}
+	if len(subscribed) == 0 && len(emails) == 0 {
+		return nil
+	}
+
+	watchlists, err := s.storage.Tenants().GetWatchlists(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	// Watched delegators by chain, in watchlist order
+	var chains []string
+	delegators := make(map[string][]string)
+	for _, watchlist := range watchlists {
+		if _, ok := delegators[watchlist.ChainName]; !ok {
+			chains = append(chains, watchlist.ChainName)
+		}
+		for _, address := range watchlist.Addresses {
+			if !slices.Contains(delegators[watchlist.ChainName], address) {
+				delegators[watchlist.ChainName] = append(delegators[watchlist.ChainName], address)
+			}
+		}
+	}
+
+	for _, chain := range chains {
+		if len(delegators[chain]) == 0 {
+			continue
+		}
+		completions, err := s.storage.Deadlines().GetUnbondingCompletions(ctx, chain, delegators[chain], now, now.Add(s.cfg.Before))
+		if err != nil {
+			return err
+		}
+		for _, completion := range completions {
+			if err := s.remind(ctx, tenantID, subscribed, emails, completion); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// remind claims the reminder of an entry and sends it to the webhooks and
+// email subscriptions, releasing the claim when none received it
+func (s *Scheduler) remind(ctx context.Context, tenantID string, webhooks []types.Webhook, emails []types.EmailSubscription, completion types.UnbondingCompletion) error {
+	claimed, err := s.storage.Tenants().ClaimUnbondingReminder(ctx, tenantID, completion)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil // already sent
+	}
+
+	reminder := types.UnbondingReminder{
+		TenantID:  tenantID,
+		Unbonding: completion,
+		SentAt:    time.Now().UTC(),
+	}
+
+	var delivered bool
+	for i := range webhooks {
+		err := s.webhooks.Send(ctx, &webhooks[i], webhook.EventUnbondingCompletion, reminder)
+		if err != nil {
+			s.logger.Warn("Failed to deliver unbonding reminder",
+				zap.String("tenant", tenantID),
+				zap.String("webhook", webhooks[i].ID),
+				zap.String("chain", completion.ChainName),
+				zap.String("delegator", completion.DelegatorAddress),
+				zap.Error(err))
+			deliveries.WithLabelValues("webhook", "failure").Inc()
+			continue
+		}
+		deliveries.WithLabelValues("webhook", "success").Inc()
+		delivered = true
+	}
+	for _, sub := range emails {
+		msg := email.Message{To: sub.Email, Event: webhook.EventUnbondingCompletion, Data: reminder, UnsubscribeToken: sub.UnsubscribeToken}
+		if err := s.emails.Send(msg); err != nil {
+			s.logger.Warn("Failed to email unbonding reminder",
+				zap.String("tenant", tenantID),
+				zap.String("email_subscription", sub.ID),
+				zap.String("chain", completion.ChainName),
+				zap.String("delegator", completion.DelegatorAddress),
+				zap.Error(err))
+			deliveries.WithLabelValues("email", "failure").Inc()
+			continue
+		}
+		deliveries.WithLabelValues("email", "success").Inc()
+		delivered = true
+	}
+
+	if !delivered {
+		return s.storage.Tenants().ReleaseUnbondingReminder(ctx, tenantID, completion)
+	}
+	return nil
+}
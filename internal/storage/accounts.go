@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// AccountRef identifies an account on a single chain. The same key has a
+// different bech32 address on each chain, so the address is per chain.
+type AccountRef struct {
+	ChainName string
+	Address   string
+	Denom     string // the chain's staking denom, which delegations are totaled in
+}
+
+// fanOut runs fn for every account concurrently and concatenates the results
+// in the order of refs
+func fanOut[T any](ctx context.Context, refs []AccountRef, fn func(ctx context.Context, ref AccountRef) ([]T, error)) ([]T, error) {
+	results := make([][]T, len(refs))
+
+	g, ctx := errgroup.WithContext(ctx)
+	for i, ref := range refs {
+		g.Go(func() error {
+			items, err := fn(ctx, ref)
+			if err != nil {
+				return err
+			}
+			results[i] = items
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	merged := []T{}
+	for _, items := range results {
+		merged = append(merged, items...)
+	}
+	return merged, nil
+}
+
+// GetAccountBalances returns balances for the given accounts across chains
+func (m *Manager) GetAccountBalances(ctx context.Context, refs []AccountRef) ([]types.Balance, error) {
+	return fanOut(ctx, refs, func(ctx context.Context, ref AccountRef) ([]types.Balance, error) {
+		return m.state.GetBalances(ctx, ref.ChainName, ref.Address)
+	})
+}
+
+// GetAccountDelegations returns delegations for the given accounts across chains
+func (m *Manager) GetAccountDelegations(ctx context.Context, refs []AccountRef) ([]types.Delegation, error) {
+	return fanOut(ctx, refs, func(ctx context.Context, ref AccountRef) ([]types.Delegation, error) {
+		return m.state.GetDelegations(ctx, ref.ChainName, ref.Address)
+	})
+}
+
+// GetAccountStates returns the unified account state for the given accounts,
+// one entry per chain. Withdrawn rewards require ClickHouse; without it they
+// are left empty.
+func (m *Manager) GetAccountStates(ctx context.Context, refs []AccountRef) ([]types.AccountState, error) {
+	return fanOut(ctx, refs, func(ctx context.Context, ref AccountRef) ([]types.AccountState, error) {
+		balances, err := m.state.GetBalances(ctx, ref.ChainName, ref.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		delegations, err := m.state.GetDelegations(ctx, ref.ChainName, ref.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		unbondings, err := m.state.GetUnbondingDelegations(ctx, ref.ChainName, ref.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		redelegations, err := m.state.GetRedelegations(ctx, ref.ChainName, ref.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		rewards := []types.Reward{}
+		if m.clickhouse != nil {
+			if rewards, err = m.clickhouse.GetWithdrawnRewards(ctx, ref.ChainName, ref.Address); err != nil {
+				return nil, err
+			}
+		}
+
+		tokens, err := m.state.GetTokenHoldings(ctx, ref.ChainName, ref.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		label, err := m.accountLabel(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		return []types.AccountState{{
+			ChainName:     ref.ChainName,
+			Address:       ref.Address,
+			Balances:      balances,
+			Delegations:   delegations,
+			Unbonding:     unbondings,
+			Redelegations: redelegations,
+			Rewards:       rewards,
+			Tokens:        tokens,
+			Label:         label,
+			UpdatedAt:     time.Now(),
+		}}, nil
+	})
+}
+
+// GetAccountRewards returns the rewards the given delegators withdrew and
+// claimed, one summary per chain. Withdrawals are indexed from transactions
+// into ClickHouse; without it this returns ErrUnavailable.
+func (m *Manager) GetAccountRewards(ctx context.Context, refs []AccountRef) ([]types.DelegatorRewards, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("reward claims require analytics storage: %w", ErrUnavailable)
+	}
+	return fanOut(ctx, refs, func(ctx context.Context, ref AccountRef) ([]types.DelegatorRewards, error) {
+		rewards, err := m.clickhouse.GetDelegatorRewards(ctx, ref.ChainName, ref.Address)
+		if err != nil {
+			return nil, err
+		}
+		return []types.DelegatorRewards{*rewards}, nil
+	})
+}
+
+// perChain runs fn once per chain concurrently, each call bounded by timeout.
+// Failed chains are reported instead of failing the whole query.
+func perChain[T any](ctx context.Context, chains []string, timeout time.Duration, fn func(ctx context.Context, i int) (T, error)) ([]T, []bool, []types.ChainError) {
+	results := make([]T, len(chains))
+	failed := make([]bool, len(chains))
+	errs := make([]error, len(chains))
+
+	var g errgroup.Group
+	for i := range chains {
+		g.Go(func() error {
+			chainCtx := ctx
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				chainCtx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			results[i], errs[i] = fn(chainCtx, i)
+			return nil
+		})
+	}
+	g.Wait()
+
+	var chainErrors []types.ChainError
+	for i, err := range errs {
+		if err != nil {
+			failed[i] = true
+			chainErrors = append(chainErrors, types.ChainError{ChainName: chains[i], Error: err.Error()})
+		}
+	}
+	return results, failed, chainErrors
+}
+
+// GetCrossChainAccount returns the account state on every given chain, in
+// the order of refs, with its totals per chain and denom. Chains that fail or
+// exceed the timeout are listed in the result's Errors.
+func (m *Manager) GetCrossChainAccount(ctx context.Context, address string, refs []AccountRef, timeout time.Duration) *types.CrossChainAccount {
+	chains := make([]string, len(refs))
+	for i, ref := range refs {
+		chains[i] = ref.ChainName
+	}
+
+	states, failed, chainErrors := perChain(ctx, chains, timeout, func(ctx context.Context, i int) ([]types.AccountState, error) {
+		return m.GetAccountStates(ctx, refs[i:i+1])
+	})
+
+	result := &types.CrossChainAccount{
+		Address:   address,
+		Chains:    []types.AccountState{},
+		Totals:    []types.CrossChainTotal{},
+		Errors:    chainErrors,
+		UpdatedAt: time.Now(),
+	}
+	for i, state := range states {
+		if failed[i] || len(state) == 0 {
+			continue
+		}
+		result.Chains = append(result.Chains, state[0])
+		result.Totals = append(result.Totals, ChainTotals(state[0], refs[i].Denom)...)
+	}
+	return result
+}
+
+// ChainTotals totals an account's holdings on one chain per denom, sorted by
+// denom: its balance, its delegations, unbonding and redelegation entries in
+// the staking denom, and its rewards. Shares are counted as tokens, which they
+// equal unless the validator was slashed, and rewards are truncated to whole
+// base units. Redelegated stake stays delegated, so it is part of the
+// delegated total as well.
+func ChainTotals(state types.AccountState, stakingDenom string) []types.CrossChainTotal {
+	type amounts struct{ balance, delegated, unbonding, redelegating, rewards decimal.Decimal }
+	totals := make(map[string]*amounts)
+	add := func(denom, amount string, field func(*amounts) *decimal.Decimal) {
+		value, err := decimal.NewFromString(amount)
+		if denom == "" || err != nil {
+			return
+		}
+		if totals[denom] == nil {
+			totals[denom] = &amounts{}
+		}
+		total := field(totals[denom])
+		*total = total.Add(value)
+	}
+
+	for _, balance := range state.Balances {
+		add(balance.Denom, balance.Amount, func(a *amounts) *decimal.Decimal { return &a.balance })
+	}
+	for _, delegation := range state.Delegations {
+		add(stakingDenom, delegation.Shares, func(a *amounts) *decimal.Decimal { return &a.delegated })
+	}
+	for _, unbonding := range state.Unbonding {
+		for _, entry := range unbonding.Entries {
+			add(stakingDenom, entry.Balance, func(a *amounts) *decimal.Decimal { return &a.unbonding })
+		}
+	}
+	for _, redelegation := range state.Redelegations {
+		for _, entry := range redelegation.Entries {
+			add(stakingDenom, entry.InitialBalance, func(a *amounts) *decimal.Decimal { return &a.redelegating })
+		}
+	}
+	for _, reward := range state.Rewards {
+		for _, coin := range reward.Reward {
+			add(coin.Denom, coin.Amount, func(a *amounts) *decimal.Decimal { return &a.rewards })
+		}
+	}
+
+	result := make([]types.CrossChainTotal, 0, len(totals))
+	for denom, total := range totals {
+		result = append(result, types.CrossChainTotal{
+			ChainName:    state.ChainName,
+			Denom:        denom,
+			Balance:      total.balance.Truncate(0).String(),
+			Delegated:    total.delegated.Truncate(0).String(),
+			Unbonding:    total.unbonding.Truncate(0).String(),
+			Redelegating: total.redelegating.Truncate(0).String(),
+			Rewards:      total.rewards.Truncate(0).String(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Denom < result[j].Denom })
+	return result
+}
+
+// GetCrossChainValidators returns validators for every given chain. Chains that
+// fail or exceed the timeout are returned as errors alongside the partial result.
+func (m *Manager) GetCrossChainValidators(ctx context.Context, chains []string, timeout time.Duration) (map[string][]types.Validator, []types.ChainError) {
+	validators, failed, chainErrors := perChain(ctx, chains, timeout, func(ctx context.Context, i int) ([]types.Validator, error) {
+		return m.state.GetValidators(ctx, chains[i])
+	})
+
+	result := make(map[string][]types.Validator, len(chains))
+	for i, chain := range chains {
+		if !failed[i] {
+			result[chain] = validators[i]
+		}
+	}
+	return result, chainErrors
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+func TestChainTotals(t *testing.T) {
+	state := types.AccountState{
+		ChainName: "cosmoshub",
+		Balances: []types.Balance{
+			{Denom: "uatom", Amount: "1000"},
+			{Denom: "ibc/ABC", Amount: "5"},
+		},
+		Delegations: []types.Delegation{
+			{ValidatorAddress: "val1", Shares: "300.75"},
+			{ValidatorAddress: "val2", Shares: "200.5"},
+		},
+		Unbonding: []types.UnbondingDelegation{
+			{ValidatorAddress: "val1", Entries: []types.UnbondingDelegationEntry{{Balance: "40"}, {Balance: "60"}}},
+		},
+		Redelegations: []types.Redelegation{
+			{ValidatorSrcAddress: "val3", ValidatorDstAddress: "val2", Entries: []types.RedelegationEntry{{InitialBalance: "200", SharesDst: "200.5"}}},
+		},
+		Rewards: []types.Reward{
+			{ValidatorAddress: "val1", Reward: []types.Coin{{Denom: "uatom", Amount: "12.9"}, {Denom: "ibc/ABC", Amount: "1"}}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		stakingDenom string
+		want         []types.CrossChainTotal
+	}{
+		{
+			name:         "staking denom",
+			stakingDenom: "uatom",
+			want: []types.CrossChainTotal{
+				{ChainName: "cosmoshub", Denom: "ibc/ABC", Balance: "5", Delegated: "0", Unbonding: "0", Redelegating: "0", Rewards: "1"},
+				{ChainName: "cosmoshub", Denom: "uatom", Balance: "1000", Delegated: "501", Unbonding: "100", Redelegating: "200", Rewards: "12"},
+			},
+		},
+		{
+			// Without a staking denom, delegations, unbonding and redelegation
+			// entries can't be attributed to a denom
+			name: "no staking denom",
+			want: []types.CrossChainTotal{
+				{ChainName: "cosmoshub", Denom: "ibc/ABC", Balance: "5", Delegated: "0", Unbonding: "0", Redelegating: "0", Rewards: "1"},
+				{ChainName: "cosmoshub", Denom: "uatom", Balance: "1000", Delegated: "0", Unbonding: "0", Redelegating: "0", Rewards: "12"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChainTotals(state, tt.stakingDenom); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChainTotals() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
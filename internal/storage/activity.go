@@ -0,0 +1,430 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// Activity feed entry types, besides the balance and delegation events
+const (
+	ActivityVote        = "vote"
+	ActivityTransaction = "tx"
+)
+
+// ActivityTypes lists the entry types of an activity feed
+var ActivityTypes = []string{AccountEventBalance, AccountEventDelegation, ActivityVote, ActivityTransaction}
+
+// activityCursor is the position of an entry in feed order: entries are
+// ordered newest first by time, then by chain, type and denom, validator,
+// proposal or transaction hash, all descending
+type activityCursor struct {
+	timestamp time.Time // truncated to milliseconds, the precision of ClickHouse
+	chain     string
+	kind      string
+	key       string
+}
+
+// encode returns the opaque form of the cursor given to clients
+func (c activityCursor) encode() string {
+	raw := fmt.Sprintf("%d:%s:%s:%s", c.timestamp.UnixMilli(), c.chain, c.kind, c.key)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeActivityCursor parses a cursor returned with a feed entry
+func decodeActivityCursor(cursor string) (activityCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return activityCursor{}, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 || !slices.Contains(ActivityTypes, parts[2]) {
+		return activityCursor{}, fmt.Errorf("invalid cursor")
+	}
+	millis, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return activityCursor{}, fmt.Errorf("invalid cursor")
+	}
+	return activityCursor{timestamp: time.UnixMilli(millis).UTC(), chain: parts[1], kind: parts[2], key: parts[3]}, nil
+}
+
+// ValidateActivityCursor reports whether a cursor can continue an activity feed
+func ValidateActivityCursor(cursor string) error {
+	_, err := decodeActivityCursor(cursor)
+	return err
+}
+
+// newActivityCursor returns the cursor of an entry
+func newActivityCursor(item types.ActivityItem, key string) activityCursor {
+	return activityCursor{timestamp: item.Timestamp.Truncate(time.Millisecond), chain: item.ChainName, kind: item.Type, key: key}
+}
+
+// less reports whether c comes after other in feed order
+func (c activityCursor) less(other activityCursor) bool {
+	if !c.timestamp.Equal(other.timestamp) {
+		return c.timestamp.Before(other.timestamp)
+	}
+	if c.chain != other.chain {
+		return c.chain < other.chain
+	}
+	if c.kind != other.kind {
+		return c.kind < other.kind
+	}
+	return c.key < other.key
+}
+
+// after returns the condition selecting the entries of a kind that come after
+// the cursor, given the timestamp, chain and key columns. arg adds a query
+// argument and returns its placeholder.
+func (c activityCursor) after(kind, timestamp, chain, key string, arg func(any) string) string {
+	switch {
+	case kind < c.kind:
+		return "(" + timestamp + ", " + chain + ") <= (" + arg(c.timestamp) + ", " + arg(c.chain) + ")"
+	case kind > c.kind:
+		return "(" + timestamp + ", " + chain + ") < (" + arg(c.timestamp) + ", " + arg(c.chain) + ")"
+	default:
+		return "(" + timestamp + ", " + chain + ", " + key + ") < (" + arg(c.timestamp) + ", " + arg(c.chain) + ", " + arg(c.key) + ")"
+	}
+}
+
+// activityQuery holds the conditions and arguments of a feed query against one
+// table, in PostgreSQL or ClickHouse
+type activityQuery struct {
+	clickhouse bool
+	conds      []string
+	args       []any
+}
+
+// arg adds a query argument and returns its placeholder. Times are bound to
+// ClickHouse in milliseconds, which positional arguments otherwise truncate to
+// seconds.
+func (q *activityQuery) arg(value any) string {
+	if !q.clickhouse {
+		q.args = append(q.args, value)
+		return "$" + strconv.Itoa(len(q.args))
+	}
+	if t, ok := value.(time.Time); ok {
+		q.args = append(q.args, t.UnixMilli())
+		return "fromUnixTimestamp64Milli(?)"
+	}
+	q.args = append(q.args, value)
+	return "?"
+}
+
+// accounts selects the rows of the given accounts
+func (q *activityQuery) accounts(chainColumn, addressColumn string, refs []AccountRef) {
+	pairs := make([]string, len(refs))
+	for i, ref := range refs {
+		pairs[i] = "(" + q.arg(ref.ChainName) + ", " + q.arg(ref.Address) + ")"
+	}
+	q.conds = append(q.conds, "("+chainColumn+", "+addressColumn+") IN ("+strings.Join(pairs, ", ")+")")
+}
+
+// bounds applies the filter's time bounds and the cursor
+func (q *activityQuery) bounds(filter types.ActivityFilter, cursor *activityCursor, kind, timestamp, chain, key string) {
+	if !filter.From.IsZero() {
+		q.conds = append(q.conds, timestamp+" >= "+q.arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		q.conds = append(q.conds, timestamp+" < "+q.arg(filter.To))
+	}
+	if cursor != nil {
+		q.conds = append(q.conds, cursor.after(kind, timestamp, chain, key, q.arg))
+	}
+}
+
+// where returns the conditions of the query
+func (q *activityQuery) where() string {
+	return strings.Join(q.conds, " AND ")
+}
+
+// wantsActivity reports whether a filter selects an entry type
+func wantsActivity(filter types.ActivityFilter, kind string) bool {
+	return len(filter.Types) == 0 || slices.Contains(filter.Types, kind)
+}
+
+// parseActivityCursor decodes an optional cursor
+func parseActivityCursor(after string) (*activityCursor, error) {
+	if after == "" {
+		return nil, nil
+	}
+	cursor, err := decodeActivityCursor(after)
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// VoteStore reads the governance votes of accounts
+type VoteStore interface {
+	GetAccountVotes(ctx context.Context, refs []AccountRef, filter types.ActivityFilter, after string, limit int) ([]types.ActivityItem, error)
+}
+
+var (
+	_ VoteStore = (*PostgresStore)(nil)
+	_ VoteStore = (*CockroachStore)(nil)
+)
+
+// GetAccountVotes returns up to limit governance votes cast by the given
+// accounts in feed order, starting after the given cursor ("" for the newest)
+func (s *PostgresStore) GetAccountVotes(ctx context.Context, refs []AccountRef, filter types.ActivityFilter, after string, limit int) ([]types.ActivityItem, error) {
+	cursor, err := parseActivityCursor(after)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	// Keys and chains are compared bytewise, as in ClickHouse and Go
+	const (
+		timestamp = `date_trunc('milliseconds', timestamp)`
+		chain     = `chain_name COLLATE "C"`
+		key       = `lpad(proposal_id::text, 20, '0') COLLATE "C"`
+	)
+	q := &activityQuery{}
+	q.accounts("chain_name", "voter", refs)
+	q.bounds(filter, cursor, ActivityVote, timestamp, chain, key)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain_name, proposal_id, voter, option, height, COALESCE(tx_hash, ''), timestamp
+		FROM votes
+		WHERE `+q.where()+`
+		ORDER BY `+timestamp+` DESC, `+chain+` DESC, proposal_id DESC
+		LIMIT `+q.arg(limit), q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account votes: %w", err)
+	}
+	defer rows.Close()
+
+	var items []types.ActivityItem
+	for rows.Next() {
+		var vote types.Vote
+		var item types.ActivityItem
+		if err := rows.Scan(&vote.ChainName, &vote.ProposalID, &vote.Voter, &vote.Option, &vote.Height, &item.TxHash, &vote.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+
+		item.Type = ActivityVote
+		item.ChainName = vote.ChainName
+		item.Height = vote.Height
+		item.Timestamp = vote.UpdatedAt
+		item.Vote = &vote
+		item.Cursor = newActivityCursor(item, fmt.Sprintf("%020d", vote.ProposalID)).encode()
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetAccountActivity returns up to limit balance and delegation events and
+// transactions paid for by the given accounts in feed order, starting after
+// the given cursor ("" for the newest). Each source is read up to limit
+// entries and the results merged.
+func (s *ClickHouseStore) GetAccountActivity(ctx context.Context, refs []AccountRef, filter types.ActivityFilter, after string, limit int) ([]types.ActivityItem, error) {
+	cursor, err := parseActivityCursor(after)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	sources := []struct {
+		kind string
+		read func(ctx context.Context, refs []AccountRef, filter types.ActivityFilter, cursor *activityCursor, limit int) ([]types.ActivityItem, error)
+	}{
+		{AccountEventBalance, s.balanceActivity},
+		{AccountEventDelegation, s.delegationActivity},
+		{ActivityTransaction, s.transactionActivity},
+	}
+
+	var items []types.ActivityItem
+	for _, source := range sources {
+		if !wantsActivity(filter, source.kind) {
+			continue
+		}
+		read, err := source.read(ctx, refs, filter, cursor, limit)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, read...)
+	}
+	return items, nil
+}
+
+// balanceActivity returns the balance events of an activity feed page
+func (s *ClickHouseStore) balanceActivity(ctx context.Context, refs []AccountRef, filter types.ActivityFilter, cursor *activityCursor, limit int) ([]types.ActivityItem, error) {
+	q := &activityQuery{clickhouse: true}
+	q.accounts("chain_name", "address", refs)
+	q.bounds(filter, cursor, AccountEventBalance, "timestamp", "chain_name", "denom")
+	rows, err := s.conn.Query(ctx, `
+		SELECT chain_name, address, denom, toString(amount), toString(previous_amount),
+		       change_type, height, tx_hash, event_id, timestamp
+		FROM balance_events
+		WHERE `+q.where()+`
+		ORDER BY timestamp DESC, chain_name DESC, denom DESC
+		LIMIT `+q.arg(limit), q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balance activity: %w", err)
+	}
+	defer rows.Close()
+
+	var items []types.ActivityItem
+	for rows.Next() {
+		var event types.BalanceEvent
+		var height uint64
+		if err := rows.Scan(&event.ChainName, &event.Address, &event.Denom, &event.Amount, &event.PreviousAmount,
+			&event.ChangeType, &height, &event.TxHash, &event.EventID, &event.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan balance event: %w", err)
+		}
+		event.Height = int64(height)
+
+		item := types.ActivityItem{
+			Type:      AccountEventBalance,
+			ChainName: event.ChainName,
+			Height:    event.Height,
+			Timestamp: event.Timestamp,
+			TxHash:    event.TxHash,
+			Balance:   &event,
+		}
+		item.Cursor = newActivityCursor(item, event.Denom).encode()
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// delegationActivity returns the delegation events of an activity feed page
+func (s *ClickHouseStore) delegationActivity(ctx context.Context, refs []AccountRef, filter types.ActivityFilter, cursor *activityCursor, limit int) ([]types.ActivityItem, error) {
+	q := &activityQuery{clickhouse: true}
+	q.accounts("chain_name", "delegator_address", refs)
+	q.bounds(filter, cursor, AccountEventDelegation, "timestamp", "chain_name", "validator_address")
+	rows, err := s.conn.Query(ctx, `
+		SELECT chain_name, delegator_address, validator_address, toString(shares), toString(previous_shares),
+		       change_type, height, tx_hash, event_id, timestamp
+		FROM delegation_events
+		WHERE `+q.where()+`
+		ORDER BY timestamp DESC, chain_name DESC, validator_address DESC
+		LIMIT `+q.arg(limit), q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query delegation activity: %w", err)
+	}
+	defer rows.Close()
+
+	var items []types.ActivityItem
+	for rows.Next() {
+		var event types.DelegationEvent
+		var height uint64
+		if err := rows.Scan(&event.ChainName, &event.DelegatorAddress, &event.ValidatorAddress, &event.Shares, &event.PreviousShares,
+			&event.ChangeType, &height, &event.TxHash, &event.EventID, &event.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan delegation event: %w", err)
+		}
+		event.Height = int64(height)
+
+		item := types.ActivityItem{
+			Type:       AccountEventDelegation,
+			ChainName:  event.ChainName,
+			Height:     event.Height,
+			Timestamp:  event.Timestamp,
+			TxHash:     event.TxHash,
+			Delegation: &event,
+		}
+		item.Cursor = newActivityCursor(item, event.ValidatorAddress).encode()
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// transactionActivity returns the transactions of an activity feed page. Only
+// transactions indexed by the blocks module's txs option are found, by their
+// fee payer.
+func (s *ClickHouseStore) transactionActivity(ctx context.Context, refs []AccountRef, filter types.ActivityFilter, cursor *activityCursor, limit int) ([]types.ActivityItem, error) {
+	q := &activityQuery{clickhouse: true}
+	q.accounts("chain_name", "fee_payer", refs)
+	q.bounds(filter, cursor, ActivityTransaction, "timestamp", "chain_name", "tx_hash")
+	rows, err := s.conn.Query(ctx, `
+		SELECT chain_name, height, tx_hash, code, gas_wanted, gas_used,
+		       fee_denom, toString(fee_amount), fee_payer, message_types, timestamp
+		FROM transactions FINAL
+		WHERE `+q.where()+`
+		ORDER BY timestamp DESC, chain_name DESC, tx_hash DESC
+		LIMIT `+q.arg(limit), q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transaction activity: %w", err)
+	}
+	defer rows.Close()
+
+	var items []types.ActivityItem
+	for rows.Next() {
+		var tx types.Transaction
+		var height, gasWanted, gasUsed uint64
+		if err := rows.Scan(&tx.ChainName, &height, &tx.Hash, &tx.Code, &gasWanted, &gasUsed,
+			&tx.FeeDenom, &tx.FeeAmount, &tx.FeePayer, &tx.MessageTypes, &tx.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		tx.Height, tx.GasWanted, tx.GasUsed = int64(height), int64(gasWanted), int64(gasUsed)
+
+		item := types.ActivityItem{
+			Type:        ActivityTransaction,
+			ChainName:   tx.ChainName,
+			Height:      tx.Height,
+			Timestamp:   tx.Timestamp,
+			TxHash:      tx.Hash,
+			Transaction: &tx,
+		}
+		item.Cursor = newActivityCursor(item, tx.Hash).encode()
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetAccountActivity returns the activity feed of the given accounts, newest
+// first: their balance and delegation events and the transactions they paid
+// for from ClickHouse, and their governance votes from the state store. At
+// most limit entries are returned, starting after the given cursor ("" for
+// the newest).
+func (m *Manager) GetAccountActivity(ctx context.Context, refs []AccountRef, filter types.ActivityFilter, after string, limit int) ([]types.ActivityItem, error) {
+	var items []types.ActivityItem
+	if m.clickhouse != nil {
+		analytics, err := m.clickhouse.GetAccountActivity(ctx, refs, filter, after, limit)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, analytics...)
+	}
+	if store, ok := m.state.(VoteStore); ok && wantsActivity(filter, ActivityVote) {
+		votes, err := store.GetAccountVotes(ctx, refs, filter, after, limit)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, votes...)
+	}
+
+	positions := make(map[string]activityCursor, len(items))
+	for _, item := range items {
+		positions[item.Cursor], _ = decodeActivityCursor(item.Cursor)
+	}
+	slices.SortFunc(items, func(a, b types.ActivityItem) int {
+		switch pa, pb := positions[a.Cursor], positions[b.Cursor]; {
+		case pb.less(pa):
+			return -1
+		case pa.less(pb):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	if items == nil {
+		items = []types.ActivityItem{}
+	}
+	return items, nil
+}
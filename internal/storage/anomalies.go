@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// AnomalyStore records the anomalies the anomaly detector finds in the chains'
+// hourly metrics
+type AnomalyStore interface {
+	RecordAnomaly(ctx context.Context, anomaly types.Anomaly) (bool, error)
+	GetAnomalies(ctx context.Context, chainName string, since time.Time) ([]types.Anomaly, error)
+}
+
+var (
+	_ AnomalyStore = (*PostgresStore)(nil)
+	_ AnomalyStore = (*CockroachStore)(nil)
+	_ AnomalyStore = (*MemoryStore)(nil)
+)
+
+// RecordAnomaly records an anomaly, and reports whether it was not already.
+// Servers record an anomaly before alerting on it, so that only one alerts.
+func (s *PostgresStore) RecordAnomaly(ctx context.Context, anomaly types.Anomaly) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO anomalies (chain_name, metric, hour, value, expected, std_dev, z_score, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT DO NOTHING
+	`, anomaly.ChainName, anomaly.Metric, anomaly.Hour, anomaly.Value, anomaly.Expected,
+		anomaly.StdDev, anomaly.ZScore, anomaly.DetectedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to record anomaly: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record anomaly: %w", err)
+	}
+	return n == 1, nil
+}
+
+// GetAnomalies returns the anomalies of a chain in the hours since a time,
+// newest first
+func (s *PostgresStore) GetAnomalies(ctx context.Context, chainName string, since time.Time) ([]types.Anomaly, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain_name, metric, hour, value, expected, std_dev, z_score, detected_at
+		FROM anomalies
+		WHERE chain_name = $1 AND hour >= $2
+		ORDER BY hour DESC, metric
+	`, chainName, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query anomalies: %w", err)
+	}
+	defer rows.Close()
+
+	anomalies := []types.Anomaly{}
+	for rows.Next() {
+		var anomaly types.Anomaly
+		if err := rows.Scan(&anomaly.ChainName, &anomaly.Metric, &anomaly.Hour, &anomaly.Value, &anomaly.Expected,
+			&anomaly.StdDev, &anomaly.ZScore, &anomaly.DetectedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan anomaly: %w", err)
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+	return anomalies, rows.Err()
+}
+
+// GetHourlyMetric returns one of types.AnomalyMetrics of a chain for each hour
+// since a time up to another, oldest first
+func (m *Manager) GetHourlyMetric(ctx context.Context, chain, metric, denom string, since, until time.Time) ([]types.MetricPoint, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("hourly metrics require analytics storage: %w", ErrUnavailable)
+	}
+	return m.clickhouse.GetHourlyMetric(ctx, chain, metric, denom, since, until)
+}
+
+// Anomalies returns the store of detected anomalies
+func (m *Manager) Anomalies() AnomalyStore {
+	return m.state.(AnomalyStore)
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// highPercentile is the percentile of the recent base fees recommended as the
+// high gas price
+const highPercentile = 0.9
+
+// GetGasPriceEstimates recommends gas prices in each denom of a chain's fee
+// market from the base fees recorded over the last window. A denom without a
+// base fee in the window has no estimate.
+func (m *Manager) GetGasPriceEstimates(ctx context.Context, chain string, window time.Duration) ([]types.GasPriceEstimate, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("gas price estimates require analytics storage: %w", ErrUnavailable)
+	}
+	fees, err := m.clickhouse.GetBaseFees(ctx, chain, time.Now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+	return estimateGasPrices(fees)
+}
+
+// GetCrossChainGasPriceEstimates recommends gas prices on each given chain, in
+// the order of chains. Chains that fail or exceed the timeout are reported
+// instead.
+func (m *Manager) GetCrossChainGasPriceEstimates(ctx context.Context, chains []string, window, timeout time.Duration) ([]types.GasPriceEstimate, []types.ChainError) {
+	results, _, chainErrors := perChain(ctx, chains, timeout, func(ctx context.Context, i int) ([]types.GasPriceEstimate, error) {
+		return m.GetGasPriceEstimates(ctx, chains[i], window)
+	})
+
+	estimates := []types.GasPriceEstimate{}
+	for _, result := range results {
+		estimates = append(estimates, result...)
+	}
+	return estimates, chainErrors
+}
+
+// GetBaseFeeHistory returns the hourly base fees of a chain since a time
+func (m *Manager) GetBaseFeeHistory(ctx context.Context, chain string, since time.Time) ([]types.BaseFeeStats, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("base fee history requires analytics storage: %w", ErrUnavailable)
+	}
+	return m.clickhouse.GetBaseFeeHistory(ctx, chain, since)
+}
+
+// estimateGasPrices recommends gas prices from base fees ordered by denom and
+// height. A transaction paying less than the current base fee is rejected, so
+// it bounds every recommendation from below: low is the current base fee,
+// average the mean over the fees and high their 90th percentile.
+func estimateGasPrices(fees []types.BaseFee) ([]types.GasPriceEstimate, error) {
+	estimates := []types.GasPriceEstimate{}
+	for start := 0; start < len(fees); {
+		end := start
+		for end < len(fees) && fees[end].Denom == fees[start].Denom {
+			end++
+		}
+		estimate, err := estimateGasPrice(fees[start:end])
+		if err != nil {
+			return nil, err
+		}
+		estimates = append(estimates, estimate)
+		start = end
+	}
+	return estimates, nil
+}
+
+// estimateGasPrice recommends gas prices from the base fees of one denom,
+// oldest first
+func estimateGasPrice(fees []types.BaseFee) (types.GasPriceEstimate, error) {
+	amounts := make([]decimal.Decimal, len(fees))
+	sum := decimal.Zero
+	for i, fee := range fees {
+		amount, err := decimal.NewFromString(fee.Amount)
+		if err != nil {
+			return types.GasPriceEstimate{}, fmt.Errorf("invalid base fee %q: %w", fee.Amount, err)
+		}
+		amounts[i] = amount
+		sum = sum.Add(amount)
+	}
+
+	latest := fees[len(fees)-1]
+	current := amounts[len(amounts)-1]
+	average := sum.DivRound(decimal.NewFromInt(int64(len(amounts))), decimalScale)
+
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i].LessThan(amounts[j]) })
+	high := amounts[int(highPercentile*float64(len(amounts)-1)+0.5)]
+
+	return types.GasPriceEstimate{
+		ChainName: latest.ChainName,
+		Denom:     latest.Denom,
+		BaseFee:   latest.Amount,
+		Low:       current.String(),
+		Average:   decimal.Max(current, average).String(),
+		High:      decimal.Max(current, high).String(),
+		Height:    latest.Height,
+		UpdatedAt: latest.Timestamp,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+func TestEstimateGasPrices(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var fees []types.BaseFee
+	for i, amount := range []string{"0.01", "0.02", "0.03", "0.04", "0.05", "0.06", "0.07", "0.08", "0.09", "0.02"} {
+		fees = append(fees, types.BaseFee{ChainName: "osmosis", Denom: "uosmo", Amount: amount, Height: int64(100 + i), Timestamp: at})
+	}
+	// A base fee above the window's mean and 90th percentile
+	fees = append(fees, types.BaseFee{ChainName: "osmosis", Denom: "uusdc", Amount: "0.001", Height: 100, Timestamp: at},
+		types.BaseFee{ChainName: "osmosis", Denom: "uusdc", Amount: "0.004", Height: 101, Timestamp: at})
+
+	estimates, err := estimateGasPrices(fees)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []types.GasPriceEstimate{
+		{ChainName: "osmosis", Denom: "uosmo", BaseFee: "0.02", Low: "0.02", Average: "0.047", High: "0.08", Height: 109, UpdatedAt: at},
+		{ChainName: "osmosis", Denom: "uusdc", BaseFee: "0.004", Low: "0.004", Average: "0.004", High: "0.004", Height: 101, UpdatedAt: at},
+	}
+	if !reflect.DeepEqual(estimates, want) {
+		t.Errorf("estimates = %+v, want %+v", estimates, want)
+	}
+
+	if estimates, err := estimateGasPrices(nil); err != nil || len(estimates) != 0 {
+		t.Errorf("estimates without base fees = %+v, %v", estimates, err)
+	}
+	if _, err := estimateGasPrices([]types.BaseFee{{Amount: "x"}}); err == nil {
+		t.Errorf("invalid base fee accepted")
+	}
+}
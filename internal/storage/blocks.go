@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// GetLatestBlockHeight returns the highest stored block height of a chain, or 0
+// when no blocks are stored
+func (s *PostgresStore) GetLatestBlockHeight(ctx context.Context, chainName string) (int64, error) {
+	var height int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(height), 0) FROM blocks WHERE chain_name = $1`,
+		chainName).Scan(&height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block height: %w", err)
+	}
+	return height, nil
+}
+
+// GetBlocks returns the most recent blocks of a chain below the given height,
+// or the most recent blocks overall when before is 0
+func (s *PostgresStore) GetBlocks(ctx context.Context, chainName string, before int64, limit int) ([]types.Block, error) {
+	query := `
+		SELECT chain_name, height, hash, proposer_address, tx_count, time
+		FROM blocks
+		WHERE chain_name = $1 AND ($2 = 0 OR height < $2)
+		ORDER BY height DESC
+		LIMIT $3
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, chainName, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocks: %w", err)
+	}
+	defer rows.Close()
+
+	blocks := []types.Block{}
+	for rows.Next() {
+		var block types.Block
+		err := rows.Scan(
+			&block.ChainName,
+			&block.Height,
+			&block.Hash,
+			&block.ProposerAddress,
+			&block.TxCount,
+			&block.Time,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan block: %w", err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, rows.Err()
+}
+
+// GetBlock returns the block of a chain at the given height
+func (s *PostgresStore) GetBlock(ctx context.Context, chainName string, height int64) (*types.Block, error) {
+	query := `
+		SELECT chain_name, height, hash, proposer_address, tx_count, time
+		FROM blocks
+		WHERE chain_name = $1 AND height = $2
+	`
+
+	var block types.Block
+	err := s.db.QueryRowContext(ctx, query, chainName, height).Scan(
+		&block.ChainName,
+		&block.Height,
+		&block.Hash,
+		&block.ProposerAddress,
+		&block.TxCount,
+		&block.Time,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("block %d: %w", height, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block: %w", err)
+	}
+
+	return &block, nil
+}
+
+// InsertBlocks stores block headers, ignoring heights that are already stored
+func (tx *PostgresTx) InsertBlocks(ctx context.Context, blocks []types.Block) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.tx.PrepareContext(ctx, `
+		INSERT INTO blocks (chain_name, height, hash, proposer_address, tx_count, time)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (chain_name, height) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare block insert statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, block := range blocks {
+		_, err := stmt.ExecContext(ctx,
+			block.ChainName,
+			block.Height,
+			block.Hash,
+			block.ProposerAddress,
+			block.TxCount,
+			block.Time,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert block: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/cosmos/state-mesh/internal/config"
+	"go.uber.org/zap"
+)
+
+// CheckStateStore connects to the configured state store and pings it,
+// without the write-ahead log or analytics storage a manager would open
+func CheckStateStore(ctx context.Context, cfg config.DatabaseConfig) error {
+	driver := cfg.Driver
+	if driver == "" {
+		driver = config.DriverPostgres
+	}
+
+	state, err := newStateStore(driver, cfg.Postgres, zap.L().Named("storage"))
+	if err != nil {
+		return err
+	}
+	defer state.Close()
+
+	return state.Ping(ctx)
+}
+
+// CheckClickHouse connects to ClickHouse and pings it, without the spool and
+// background reconnection of the analytics store
+func CheckClickHouse(ctx context.Context, cfg config.ClickHouseConfig) error {
+	conn, err := clickhouse.Open(&clickhouse.Options{
+		Addr: []string{fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)},
+		Auth: clickhouse.Auth{
+			Database: cfg.Database,
+			Username: cfg.User,
+			Password: cfg.Password,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to ClickHouse: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Ping(ctx)
+}
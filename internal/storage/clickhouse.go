@@ -2,19 +2,58 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/diskqueue"
 	"github.com/cosmos/state-mesh/pkg/types"
 	"go.uber.org/zap"
 )
 
-// ClickHouseStore handles ClickHouse operations for analytics
+// ClickHouseStore handles ClickHouse operations for analytics. When an insert
+// fails because ClickHouse is unreachable, the store buffers analytics events
+// to a local spool and reprobes in the background, replaying the spool once
+// ClickHouse answers again.
 type ClickHouseStore struct {
 	conn   driver.Conn
 	logger *zap.Logger
+
+	spool             *diskqueue.Queue
+	dropped           atomic.Int64
+	available         atomic.Bool
+	reconnectInterval time.Duration
+	writes            config.EventWritesConfig
+	bufferTables      atomic.Bool
+	balances          *balanceAggregator
+	cancel            context.CancelFunc
+	wg                sync.WaitGroup
+}
+
+// spoolRecord is one buffered analytics insert
+type spoolRecord struct {
+	Balances      []types.BalanceEvent         `json:"balances,omitempty"`
+	Delegations   []types.DelegationEvent      `json:"delegations,omitempty"`
+	Redelegations []types.RedelegationEvent    `json:"redelegations,omitempty"`
+	Rewards       []types.RewardEvent          `json:"rewards,omitempty"`
+	Blocks        []types.Block                `json:"blocks,omitempty"`
+	Signatures    []types.BlockSignature       `json:"signatures,omitempty"`
+	ValidatorSets []types.ValidatorSetSnapshot `json:"validator_sets,omitempty"`
+	Circulating   []types.CirculatingSupply    `json:"circulating,omitempty"`
+	Transactions  []types.Transaction          `json:"transactions,omitempty"`
+	Packets       []types.PacketEvent          `json:"packets,omitempty"`
+	Epochs        []types.Epoch                `json:"epochs,omitempty"`
+	BaseFees      []types.BaseFee              `json:"base_fees,omitempty"`
+	Audit         []types.APIAuditEvent        `json:"audit,omitempty"`
+
+	Decoded []types.DecodedStateChange  `json:"decoded,omitempty"`
+	Archive []types.ArchivedStateChange `json:"archive,omitempty"`
 }
 
 // NewClickHouseStore creates a new ClickHouse store
@@ -39,10 +78,37 @@ func NewClickHouseStore(cfg config.ClickHouseConfig) (*ClickHouseStore, error) {
 		return nil, fmt.Errorf("failed to connect to ClickHouse: %w", err)
 	}
 
-	return &ClickHouseStore{
-		conn:   conn,
-		logger: zap.L().Named("clickhouse"),
-	}, nil
+	logger := zap.L().Named("clickhouse")
+	s := &ClickHouseStore{
+		conn:              &queryConn{Conn: conn, stats: newQueryStats(queryStoreClickHouse, cfg.SlowQueryThreshold, logger)},
+		logger:            logger,
+		reconnectInterval: cfg.ReconnectInterval,
+		writes:            cfg.EventWrites,
+	}
+	if s.reconnectInterval <= 0 {
+		s.reconnectInterval = 10 * time.Second
+	}
+	s.available.Store(true)
+
+	if cfg.Spool.Dir != "" {
+		s.spool, err = diskqueue.New(cfg.Spool.Dir, cfg.Spool.MaxBytes, false)
+		if err != nil {
+			s.logger.Warn("Failed to open analytics spool, events will be dropped while ClickHouse is unreachable", zap.Error(err))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go s.reconnectLoop(ctx)
+
+	if aggregate := cfg.EventWrites.Aggregate; aggregate.Enabled {
+		s.balances = newBalanceAggregator(aggregate.MaxKeys)
+		s.wg.Add(1)
+		go s.aggregateLoop(ctx, aggregate.FlushInterval)
+	}
+
+	return s, nil
 }
 
 // Ping tests the ClickHouse connection
@@ -50,21 +116,191 @@ func (s *ClickHouseStore) Ping(ctx context.Context) error {
 	return s.conn.Ping(ctx)
 }
 
-// Close closes the ClickHouse connection
+// Close inserts the aggregated balance events, stops the reconnect loop and
+// closes the ClickHouse connection. Buffered events stay on disk for the next
+// run.
 func (s *ClickHouseStore) Close() error {
+	s.cancel()
+	s.wg.Wait()
 	return s.conn.Close()
 }
 
-// InsertBalanceEvents inserts balance change events for analytics
+// Available reports whether analytics inserts currently reach ClickHouse
+func (s *ClickHouseStore) Available() bool {
+	return s.available.Load()
+}
+
+// write runs an analytics insert. While ClickHouse is unreachable the events
+// are buffered to the spool instead, or dropped when no spool is configured,
+// so analytics outages never fail the caller.
+func (s *ClickHouseStore) write(ctx context.Context, rec spoolRecord, insert func(context.Context) error) error {
+	if s.available.Load() {
+		err := insert(ctx)
+		if err == nil || ctx.Err() != nil || s.ping(ctx) == nil {
+			return err
+		}
+
+		if s.available.CompareAndSwap(true, false) {
+			s.logger.Warn("ClickHouse unreachable, buffering analytics events", zap.Error(err))
+		}
+	}
+
+	if s.spool == nil {
+		return nil
+	}
+	if err := s.spool.Append(rec); errors.Is(err, diskqueue.ErrFull) {
+		s.dropped.Add(1)
+	} else if err != nil {
+		return fmt.Errorf("failed to buffer analytics events: %w", err)
+	}
+	return nil
+}
+
+// ping probes ClickHouse with a short timeout
+func (s *ClickHouseStore) ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return s.conn.Ping(ctx)
+}
+
+// reconnectLoop periodically probes ClickHouse while it is unreachable or
+// events are buffered
+func (s *ClickHouseStore) reconnectLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.reconnectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reconnect(ctx)
+		}
+	}
+}
+
+// reconnect replays buffered events once ClickHouse answers, then resumes
+// direct inserts
+func (s *ClickHouseStore) reconnect(ctx context.Context) {
+	if s.available.Load() && (s.spool == nil || s.spool.Empty()) {
+		return
+	}
+
+	if err := s.ping(ctx); err != nil {
+		s.logger.Debug("ClickHouse still unreachable", zap.Error(err))
+		return
+	}
+
+	if s.spool != nil {
+		replayed, err := s.spool.Replay(func(line []byte) error {
+			var rec spoolRecord
+			if err := json.Unmarshal(line, &rec); err != nil {
+				// A record torn by a crash mid-write
+				s.logger.Error("Discarding undecodable buffered analytics events", zap.Error(err))
+				return nil
+			}
+
+			err := s.apply(ctx, rec)
+			if err != nil && ctx.Err() == nil && s.ping(ctx) == nil {
+				// ClickHouse is up but rejects the events; replaying them
+				// again would block the spool forever
+				s.logger.Error("Discarding buffered analytics events rejected by ClickHouse", zap.Error(err))
+				return nil
+			}
+			return err
+		})
+		if replayed > 0 {
+			s.logger.Info("Replayed buffered analytics events", zap.Int("batches", replayed))
+		}
+		if err != nil {
+			s.logger.Warn("Failed to replay buffered analytics events", zap.Error(err))
+			return
+		}
+		if dropped := s.dropped.Swap(0); dropped > 0 {
+			s.logger.Warn("Analytics spool was full, events were dropped", zap.Int64("batches", dropped))
+		}
+	}
+
+	if s.available.CompareAndSwap(false, true) {
+		s.logger.Info("ClickHouse reachable again, resuming analytics inserts")
+	}
+}
+
+// apply inserts a buffered record
+func (s *ClickHouseStore) apply(ctx context.Context, rec spoolRecord) error {
+	if err := s.insertBalanceEvents(ctx, rec.Balances); err != nil {
+		return err
+	}
+	if err := s.insertDelegationEvents(ctx, rec.Delegations); err != nil {
+		return err
+	}
+	if err := s.insertRedelegationEvents(ctx, rec.Redelegations); err != nil {
+		return err
+	}
+	if err := s.insertRewardEvents(ctx, rec.Rewards); err != nil {
+		return err
+	}
+	if err := s.insertBlockProduction(ctx, rec.Blocks, rec.Signatures); err != nil {
+		return err
+	}
+	if err := s.insertValidatorSets(ctx, rec.ValidatorSets); err != nil {
+		return err
+	}
+	if err := s.insertCirculatingSupply(ctx, rec.Circulating); err != nil {
+		return err
+	}
+	if err := s.insertTransactions(ctx, rec.Transactions); err != nil {
+		return err
+	}
+	if err := s.insertPacketEvents(ctx, rec.Packets); err != nil {
+		return err
+	}
+	if err := s.insertEpochs(ctx, rec.Epochs); err != nil {
+		return err
+	}
+	if err := s.insertBaseFees(ctx, rec.BaseFees); err != nil {
+		return err
+	}
+	if err := s.insertAuditEvents(ctx, rec.Audit); err != nil {
+		return err
+	}
+	if err := s.insertDecodedStateChanges(ctx, rec.Decoded); err != nil {
+		return err
+	}
+	return s.insertArchivedStateChanges(ctx, rec.Archive)
+}
+
+// InsertBalanceEvents inserts balance change events for analytics. With
+// aggregation enabled the events are coalesced and inserted in the background,
+// and lost if the process dies before they are flushed.
 func (s *ClickHouseStore) InsertBalanceEvents(ctx context.Context, events []types.BalanceEvent) error {
 	if len(events) == 0 {
 		return nil
 	}
+	if s.balances != nil {
+		s.balances.add(events)
+		return nil
+	}
+
+	return s.write(ctx, spoolRecord{Balances: events}, func(ctx context.Context) error {
+		return s.insertBalanceEvents(ctx, events)
+	})
+}
+
+// insertBalanceEvents writes balance events to ClickHouse
+func (s *ClickHouseStore) insertBalanceEvents(ctx context.Context, events []types.BalanceEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	events, ids := uniqueEvents(events, func(e *types.BalanceEvent) string { return e.EventID })
 
-	batch, err := s.conn.PrepareBatch(ctx, `
-		INSERT INTO balance_events (
+	batch, err := s.conn.PrepareBatch(s.eventContext(ctx, ids), `
+		INSERT INTO `+s.eventTable("balance_events", ids)+` (
 			timestamp, chain_name, address, denom, amount, 
-			previous_amount, change_type, height, tx_hash
+			previous_amount, change_type, height, tx_hash, event_id
 		)
 	`)
 	if err != nil {
@@ -72,16 +308,26 @@ func (s *ClickHouseStore) InsertBalanceEvents(ctx context.Context, events []type
 	}
 
 	for _, event := range events {
-		err := batch.Append(
+		amount, err := uint256Value(event.Amount)
+		if err != nil {
+			return err
+		}
+		previousAmount, err := uint256Value(event.PreviousAmount)
+		if err != nil {
+			return err
+		}
+
+		err = batch.Append(
 			event.Timestamp,
 			event.ChainName,
 			event.Address,
 			event.Denom,
-			event.Amount,
-			event.PreviousAmount,
+			amount,
+			previousAmount,
 			event.ChangeType,
 			event.Height,
 			event.TxHash,
+			event.EventID,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to append balance event: %w", err)
@@ -97,10 +343,23 @@ func (s *ClickHouseStore) InsertDelegationEvents(ctx context.Context, events []t
 		return nil
 	}
 
-	batch, err := s.conn.PrepareBatch(ctx, `
-		INSERT INTO delegation_events (
+	return s.write(ctx, spoolRecord{Delegations: events}, func(ctx context.Context) error {
+		return s.insertDelegationEvents(ctx, events)
+	})
+}
+
+// insertDelegationEvents writes delegation events to ClickHouse
+func (s *ClickHouseStore) insertDelegationEvents(ctx context.Context, events []types.DelegationEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	events, ids := uniqueEvents(events, func(e *types.DelegationEvent) string { return e.EventID })
+
+	batch, err := s.conn.PrepareBatch(s.eventContext(ctx, ids), `
+		INSERT INTO `+s.eventTable("delegation_events", ids)+` (
 			timestamp, chain_name, delegator_address, validator_address, 
-			shares, previous_shares, change_type, height, tx_hash
+			shares, previous_shares, change_type, height, tx_hash, event_id
 		)
 	`)
 	if err != nil {
@@ -108,16 +367,26 @@ func (s *ClickHouseStore) InsertDelegationEvents(ctx context.Context, events []t
 	}
 
 	for _, event := range events {
-		err := batch.Append(
+		shares, err := decimal256Value(event.Shares)
+		if err != nil {
+			return err
+		}
+		previousShares, err := decimal256Value(event.PreviousShares)
+		if err != nil {
+			return err
+		}
+
+		err = batch.Append(
 			event.Timestamp,
 			event.ChainName,
 			event.DelegatorAddress,
 			event.ValidatorAddress,
-			event.Shares,
-			event.PreviousShares,
+			shares,
+			previousShares,
 			event.ChangeType,
 			event.Height,
 			event.TxHash,
+			event.EventID,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to append delegation event: %w", err)
@@ -127,51 +396,91 @@ func (s *ClickHouseStore) InsertDelegationEvents(ctx context.Context, events []t
 	return batch.Send()
 }
 
-// GetBalanceHistory returns balance history for analytics
-func (s *ClickHouseStore) GetBalanceHistory(ctx context.Context, chainName, address, denom string, limit int) ([]types.BalanceEvent, error) {
-	query := `
-		SELECT timestamp, chain_name, address, denom, amount, 
-		       previous_amount, change_type, height, tx_hash
-		FROM balance_events
-		WHERE chain_name = ? AND address = ? AND denom = ?
-		ORDER BY timestamp DESC
-		LIMIT ?
-	`
+// InsertRewardEvents inserts reward payout events for analytics
+func (s *ClickHouseStore) InsertRewardEvents(ctx context.Context, events []types.RewardEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return s.write(ctx, spoolRecord{Rewards: events}, func(ctx context.Context) error {
+		return s.insertRewardEvents(ctx, events)
+	})
+}
 
-	rows, err := s.conn.Query(ctx, query, chainName, address, denom, limit)
+// insertRewardEvents writes reward events to ClickHouse, one insert per block
+// tagged with a deduplication token, so that re-polling a block whose insert
+// already landed does not count its rewards twice
+func (s *ClickHouseStore) insertRewardEvents(ctx context.Context, events []types.RewardEvent) error {
+	for start := 0; start < len(events); {
+		end := start + 1
+		for end < len(events) && events[end].ChainName == events[start].ChainName && events[end].Height == events[start].Height {
+			end++
+		}
+		if err := s.insertRewardBlock(ctx, events[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// insertRewardBlock writes the reward events of one block
+func (s *ClickHouseStore) insertRewardBlock(ctx context.Context, events []types.RewardEvent) error {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = fmt.Sprintf("%s/%d/%s/%s/%s/%s/%d", event.ChainName, event.Height, event.TxHash,
+			event.DelegatorAddress, event.ValidatorAddress, event.Denom, i)
+	}
+
+	batch, err := s.conn.PrepareBatch(s.eventContext(ctx, ids), `
+		INSERT INTO `+s.eventTable("reward_events", ids)+` (
+			timestamp, chain_name, delegator_address, validator_address,
+			denom, amount, height, tx_hash, claimed, compounded
+		)
+	`)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query balance history: %w", err)
+		return fmt.Errorf("failed to prepare reward events batch: %w", err)
 	}
-	defer rows.Close()
 
-	var events []types.BalanceEvent
-	for rows.Next() {
-		var event types.BalanceEvent
-		err := rows.Scan(
-			&event.Timestamp,
-			&event.ChainName,
-			&event.Address,
-			&event.Denom,
-			&event.Amount,
-			&event.PreviousAmount,
-			&event.ChangeType,
-			&event.Height,
-			&event.TxHash,
+	for _, event := range events {
+		amount, err := decimal256Value(event.Amount)
+		if err != nil {
+			return err
+		}
+
+		var claimed, compounded uint8
+		if event.Claimed {
+			claimed = 1
+		}
+		if event.Compounded {
+			compounded = 1
+		}
+
+		err = batch.Append(
+			event.Timestamp,
+			event.ChainName,
+			event.DelegatorAddress,
+			event.ValidatorAddress,
+			event.Denom,
+			amount,
+			event.Height,
+			event.TxHash,
+			claimed,
+			compounded,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan balance event: %w", err)
+			return fmt.Errorf("failed to append reward event: %w", err)
 		}
-		events = append(events, event)
 	}
 
-	return events, rows.Err()
+	return batch.Send()
 }
 
 // GetDelegationHistory returns delegation history for analytics
 func (s *ClickHouseStore) GetDelegationHistory(ctx context.Context, chainName, delegatorAddress string, limit int) ([]types.DelegationEvent, error) {
 	query := `
-		SELECT timestamp, chain_name, delegator_address, validator_address, 
-		       shares, previous_shares, change_type, height, tx_hash
+		SELECT timestamp, chain_name, delegator_address, validator_address,
+		       toString(shares), toString(previous_shares), change_type, height, tx_hash
 		FROM delegation_events
 		WHERE chain_name = ? AND delegator_address = ?
 		ORDER BY timestamp DESC
@@ -207,51 +516,49 @@ func (s *ClickHouseStore) GetDelegationHistory(ctx context.Context, chainName, d
 	return events, rows.Err()
 }
 
-// GetChainStats returns aggregated chain statistics
-func (s *ClickHouseStore) GetChainStats(ctx context.Context, chainName string) (*types.ChainStats, error) {
-	query := `
-		SELECT 
-			chain_name,
-			count(DISTINCT address) as total_accounts,
-			sum(amount) as total_supply,
-			count(DISTINCT validator_address) as total_validators,
-			max(height) as latest_height
-		FROM (
-			SELECT chain_name, address, amount, 0 as validator_address, height
-			FROM balance_events
-			WHERE chain_name = ? AND change_type = 'current'
-			UNION ALL
-			SELECT chain_name, delegator_address as address, 0 as amount, validator_address, height
-			FROM delegation_events
-			WHERE chain_name = ? AND change_type = 'current'
-		)
-		GROUP BY chain_name
-	`
+// GetChainActivity returns activity for the last complete day from the
+// pre-aggregated stats views
+func (s *ClickHouseStore) GetChainActivity(ctx context.Context, chainName string) (*types.ChainActivity, error) {
+	var activity types.ChainActivity
 
-	var stats types.ChainStats
-	err := s.conn.QueryRow(ctx, query, chainName, chainName).Scan(
-		&stats.ChainName,
-		&stats.TotalValidators,
-		&stats.ActiveValidators,
-		&stats.TotalDelegated,
-		&stats.TotalSupply,
-	)
+	err := s.conn.QueryRow(ctx, `
+		SELECT yesterday(), uniqMerge(addresses)
+		FROM daily_active_addresses
+		WHERE chain_name = ? AND date = yesterday()
+	`, chainName).Scan(&activity.Date, &activity.ActiveAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily active addresses: %w", err)
+	}
 
+	err = s.conn.QueryRow(ctx, `
+		SELECT sum(delegated), sum(undelegated)
+		FROM daily_delegation_volume
+		WHERE chain_name = ? AND date = yesterday()
+	`, chainName).Scan(&activity.Delegated, &activity.Undelegated)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get chain stats: %w", err)
+		return nil, fmt.Errorf("failed to get daily delegation volume: %w", err)
 	}
 
-	return &stats, nil
+	return &activity, nil
 }
 
-// GetTopHolders returns top token holders for a specific denom
-func (s *ClickHouseStore) GetTopHolders(ctx context.Context, chainName, denom string, limit int) ([]types.TokenHolder, error) {
+// GetTopHolders returns top token holders for a specific denom, leaving out the
+// excluded addresses
+func (s *ClickHouseStore) GetTopHolders(ctx context.Context, chainName, denom string, limit int, exclude []string) ([]types.TokenHolder, error) {
+	args := []any{chainName, denom}
+	var excludeCondition string
+	if len(exclude) > 0 {
+		excludeCondition = "AND address NOT IN ?"
+		args = append(args, exclude)
+	}
+	args = append(args, limit)
+
 	query := `
-		SELECT address, amount
+		SELECT address, toString(amount)
 		FROM (
 			SELECT address, argMax(amount, timestamp) as amount
 			FROM balance_events
-			WHERE chain_name = ? AND denom = ?
+			WHERE chain_name = ? AND denom = ? ` + excludeCondition + `
 			GROUP BY address
 		)
 		WHERE amount > 0
@@ -259,7 +566,7 @@ func (s *ClickHouseStore) GetTopHolders(ctx context.Context, chainName, denom st
 		LIMIT ?
 	`
 
-	rows, err := s.conn.Query(ctx, query, chainName, denom, limit)
+	rows, err := s.conn.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query top holders: %w", err)
 	}
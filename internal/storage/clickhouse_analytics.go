@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// RunAnalyticsQuery runs a query template read-only with its parameters bound
+// by ClickHouse, returning at most maxRows rows. The server stops the query
+// after timeout.
+func (s *ClickHouseStore) RunAnalyticsQuery(ctx context.Context, query string, params map[string]string, maxRows int, timeout time.Duration) (*types.AnalyticsResult, error) {
+	ctx = clickhouse.Context(ctx,
+		clickhouse.WithParameters(clickhouse.Parameters(params)),
+		clickhouse.WithSettings(clickhouse.Settings{
+			"readonly":           2,
+			"max_execution_time": max(int(timeout.Seconds()), 1),
+		}))
+
+	rows, err := s.conn.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run analytics query: %w", err)
+	}
+	defer rows.Close()
+
+	columnTypes := rows.ColumnTypes()
+	result := &types.AnalyticsResult{
+		Columns: make([]types.AnalyticsColumn, len(columnTypes)),
+		Rows:    [][]any{},
+	}
+	for i, column := range columnTypes {
+		result.Columns[i] = types.AnalyticsColumn{Name: column.Name(), Type: column.DatabaseTypeName()}
+	}
+
+	for rows.Next() {
+		if len(result.Rows) == maxRows {
+			result.Truncated = true
+			break
+		}
+		dest := make([]any, len(columnTypes))
+		for i, column := range columnTypes {
+			dest[i] = reflect.New(column.ScanType()).Interface()
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan analytics row: %w", err)
+		}
+		row := make([]any, len(dest))
+		for i, value := range dest {
+			row[i] = analyticsValue(reflect.ValueOf(value).Elem().Interface())
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if !result.Truncated {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read analytics rows: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// analyticsValue converts a scanned value for JSON. Big integers, such as
+// token amounts, become strings like the other APIs' amounts.
+func analyticsValue(value any) any {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil
+		}
+		return v.String()
+	case big.Int:
+		return v.String()
+	}
+	return value
+}
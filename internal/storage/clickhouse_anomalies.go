@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// hourlyMetricQueries select the hour and value of the hours with events of
+// the summed metrics, over [since, until) by chain
+var hourlyMetricQueries = map[string]string{
+	types.MetricDelegationOutflow: `
+		SELECT toStartOfHour(timestamp) AS hour, sumIf(-delta, delta < 0)
+		FROM (
+			SELECT timestamp, toFloat64(shares) - toFloat64(previous_shares) AS delta
+			FROM delegation_events
+			WHERE chain_name = ? AND timestamp >= ? AND timestamp < ? AND change_type != 'current'
+		)
+		GROUP BY hour
+		ORDER BY hour`,
+	types.MetricEventRate: `
+		SELECT hour, toFloat64(sum(events))
+		FROM (
+			SELECT toStartOfHour(timestamp) AS hour, count() AS events
+			FROM balance_events
+			WHERE chain_name = ? AND timestamp >= ? AND timestamp < ?
+			GROUP BY hour
+			UNION ALL
+			SELECT toStartOfHour(timestamp) AS hour, count() AS events
+			FROM delegation_events
+			WHERE chain_name = ? AND timestamp >= ? AND timestamp < ?
+			GROUP BY hour
+		)
+		GROUP BY hour
+		ORDER BY hour`,
+}
+
+// GetHourlyMetric returns one of types.AnomalyMetrics for each hour from the
+// hour of since up to until, oldest first. Hours without events count as
+// zero. The supply change is that of denom's total supply since the previous
+// hour; hours before the first recorded supply are left out.
+func (s *ClickHouseStore) GetHourlyMetric(ctx context.Context, chainName, metric, denom string, since, until time.Time) ([]types.MetricPoint, error) {
+	since, until = since.UTC().Truncate(time.Hour), until.UTC()
+
+	if metric == types.MetricSupplyChange {
+		return s.getHourlySupplyChange(ctx, chainName, denom, since, until)
+	}
+	query, ok := hourlyMetricQueries[metric]
+	if !ok {
+		return nil, fmt.Errorf("unsupported metric %q", metric)
+	}
+	args := []any{chainName, since, until}
+	if metric == types.MetricEventRate {
+		args = append(args, chainName, since, until)
+	}
+
+	values, err := s.queryHourlyValues(ctx, metric, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []types.MetricPoint
+	for hour := since; hour.Before(until); hour = hour.Add(time.Hour) {
+		points = append(points, types.MetricPoint{Time: hour, Value: values[hour]})
+	}
+	return points, nil
+}
+
+// getHourlySupplyChange returns the change of a denom's total supply for each
+// hour since the first supply recorded from the hour before since
+func (s *ClickHouseStore) getHourlySupplyChange(ctx context.Context, chainName, denom string, since, until time.Time) ([]types.MetricPoint, error) {
+	totals, err := s.queryHourlyValues(ctx, types.MetricSupplyChange, `
+		SELECT toStartOfHour(timestamp) AS hour, toFloat64(argMax(total, height))
+		FROM circulating_supply FINAL
+		WHERE chain_name = ? AND denom = ? AND timestamp >= ? AND timestamp < ?
+		GROUP BY hour
+		ORDER BY hour
+	`, chainName, denom, since.Add(-time.Hour), until)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		points   []types.MetricPoint
+		previous float64
+		known    bool
+	)
+	for hour := since.Add(-time.Hour); hour.Before(until); hour = hour.Add(time.Hour) {
+		total, recorded := totals[hour]
+		if !recorded {
+			total = previous // unchanged
+		}
+		if known && !hour.Before(since) {
+			points = append(points, types.MetricPoint{Time: hour, Value: total - previous})
+		}
+		if recorded || known {
+			previous, known = total, true
+		}
+	}
+	return points, nil
+}
+
+// queryHourlyValues runs a query selecting hours and values
+func (s *ClickHouseStore) queryHourlyValues(ctx context.Context, metric, query string, args ...any) (map[time.Time]float64, error) {
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hourly %s: %w", metric, err)
+	}
+	defer rows.Close()
+
+	values := make(map[time.Time]float64)
+	for rows.Next() {
+		var (
+			hour  time.Time
+			value float64
+		)
+		if err := rows.Scan(&hour, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan hourly %s: %w", metric, err)
+		}
+		values[hour.UTC()] = value
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// archiveScanPage is the number of archived changes read per query
+const archiveScanPage = 10000
+
+// ArchiveStateChanges inserts raw state changes into the archive
+func (s *ClickHouseStore) ArchiveStateChanges(ctx context.Context, changes []types.ArchivedStateChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return s.write(ctx, spoolRecord{Archive: changes}, func(ctx context.Context) error {
+		return s.insertArchivedStateChanges(ctx, changes)
+	})
+}
+
+// insertArchivedStateChanges writes raw state changes to ClickHouse
+func (s *ClickHouseStore) insertArchivedStateChanges(ctx context.Context, changes []types.ArchivedStateChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	batch, err := s.conn.PrepareBatch(ctx, `
+		INSERT INTO state_change_archive (
+			chain_name, store_key, key, value, is_delete, height, timestamp, sequence
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare state change archive batch: %w", err)
+	}
+
+	for _, change := range changes {
+		var isDelete uint8
+		if change.Delete {
+			isDelete = 1
+		}
+		if err := batch.Append(
+			change.ChainName,
+			change.StoreKey,
+			string(change.Key),
+			string(change.Value),
+			isDelete,
+			uint64(change.Height),
+			change.Timestamp,
+			change.Sequence,
+		); err != nil {
+			return fmt.Errorf("failed to append archived state change: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to insert archived state changes: %w", err)
+	}
+	return nil
+}
+
+// ScanArchivedStateChanges calls fn with the archived changes of a chain in the
+// order they were received, reading the archive in pages. It stops at the
+// first error fn returns and reports how many changes fn was called with.
+func (s *ClickHouseStore) ScanArchivedStateChanges(ctx context.Context, filter types.StateArchiveFilter, fn func(*types.StateChange) error) (int64, error) {
+	where := []string{"chain_name = ?"}
+	args := []any{filter.ChainName}
+	if len(filter.Stores) > 0 {
+		where = append(where, "store_key IN ?")
+		args = append(args, filter.Stores)
+	}
+	if filter.FromHeight > 0 {
+		where = append(where, "height >= ?")
+		args = append(args, uint64(filter.FromHeight))
+	}
+	if filter.ToHeight > 0 {
+		where = append(where, "height <= ?")
+		args = append(args, uint64(filter.ToHeight))
+	}
+
+	var (
+		scanned     int64
+		afterHeight uint64
+		afterTime   time.Time
+		afterSeq    int64
+		first       = true
+	)
+	for {
+		query := `
+			SELECT store_key, key, value, is_delete, height, timestamp, sequence
+			FROM state_change_archive
+			WHERE ` + strings.Join(where, " AND ")
+		pageArgs := append([]any{}, args...)
+		if !first {
+			query += " AND (height, timestamp, sequence) > (?, ?, ?)"
+			pageArgs = append(pageArgs, afterHeight, afterTime, afterSeq)
+		}
+		query += " ORDER BY height, timestamp, sequence LIMIT ?"
+		pageArgs = append(pageArgs, archiveScanPage)
+
+		rows, err := s.conn.Query(ctx, query, pageArgs...)
+		if err != nil {
+			return scanned, fmt.Errorf("failed to query state change archive: %w", err)
+		}
+
+		var page []types.StateChange
+		for rows.Next() {
+			var (
+				change     types.StateChange
+				key, value string
+				isDelete   uint8
+				height     uint64
+			)
+			if err := rows.Scan(&change.StoreKey, &key, &value, &isDelete, &height, &change.Timestamp, &afterSeq); err != nil {
+				rows.Close()
+				return scanned, fmt.Errorf("failed to scan archived state change: %w", err)
+			}
+			change.ChainName = filter.ChainName
+			change.Key = []byte(key)
+			change.Value = []byte(value)
+			change.Delete = isDelete == 1
+			change.Height = int64(height)
+			afterHeight, afterTime = height, change.Timestamp
+			page = append(page, change)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return scanned, fmt.Errorf("failed to read state change archive: %w", err)
+		}
+
+		for i := range page {
+			if err := fn(&page[i]); err != nil {
+				return scanned, err
+			}
+			scanned++
+		}
+		if len(page) < archiveScanPage {
+			return scanned, nil
+		}
+		first = false
+	}
+}
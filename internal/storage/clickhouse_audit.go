@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// InsertAuditEvents records authenticated API calls
+func (s *ClickHouseStore) InsertAuditEvents(ctx context.Context, events []types.APIAuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return s.write(ctx, spoolRecord{Audit: events}, func(ctx context.Context) error {
+		return s.insertAuditEvents(ctx, events)
+	})
+}
+
+// insertAuditEvents writes API audit events to ClickHouse
+func (s *ClickHouseStore) insertAuditEvents(ctx context.Context, events []types.APIAuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	batch, err := s.conn.PrepareBatch(ctx, `
+		INSERT INTO api_audit (
+			timestamp, tenant_id, key_id, method, endpoint, params_hash,
+			status, latency_ms, response_bytes, request_id
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare audit batch: %w", err)
+	}
+
+	for _, event := range events {
+		if err := batch.Append(
+			event.Timestamp,
+			event.TenantID,
+			event.KeyID,
+			event.Method,
+			event.Endpoint,
+			event.ParamsHash,
+			uint16(event.Status),
+			event.LatencyMs,
+			uint64(event.ResponseBytes),
+			event.RequestID,
+		); err != nil {
+			return fmt.Errorf("failed to append audit event: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to insert audit events: %w", err)
+	}
+	return nil
+}
+
+// GetAPIUsage returns the calls of each API key per endpoint and day between
+// from and to. An empty tenant ID returns usage of every tenant.
+func (s *ClickHouseStore) GetAPIUsage(ctx context.Context, tenantID string, from, to time.Time) ([]types.APIUsage, error) {
+	query := `
+		SELECT date, tenant_id, key_id, endpoint,
+		       count(),
+		       countIf(status >= 400),
+		       sum(response_bytes),
+		       avg(latency_ms)
+		FROM api_audit
+		WHERE (? = '' OR tenant_id = ?) AND timestamp >= ? AND timestamp < ?
+		GROUP BY date, tenant_id, key_id, endpoint
+		ORDER BY date DESC, tenant_id, key_id, endpoint
+	`
+
+	rows, err := s.conn.Query(ctx, query, tenantID, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []types.APIUsage
+	for rows.Next() {
+		var u types.APIUsage
+		if err := rows.Scan(&u.Date, &u.TenantID, &u.KeyID, &u.Endpoint, &u.Calls, &u.Errors, &u.ResponseBytes, &u.AvgLatencyMs); err != nil {
+			return nil, fmt.Errorf("failed to scan API usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, rows.Err()
+}
@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// Resolutions of a balance history: every balance change, or the last
+// balance of each hour or day
+const (
+	ResolutionRaw  = "raw"
+	ResolutionHour = "1h"
+	ResolutionDay  = "1d"
+)
+
+// balanceHistoryTables are the downsampled balance history tables by resolution
+var balanceHistoryTables = map[string]string{
+	ResolutionHour: "balance_history_hourly",
+	ResolutionDay:  "balance_history_daily",
+}
+
+// BalanceHistoryQuery selects the balance history of an address in a denom,
+// newest first. Zero From and To leave the time range open.
+type BalanceHistoryQuery struct {
+	ChainName  string
+	Address    string
+	Denom      string
+	Resolution string // ResolutionRaw, ResolutionHour or ResolutionDay
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+// balanceHistoryView returns the stats view keeping the last balance of each
+// address and denom per bucket, bucket being the ClickHouse function
+// truncating event times, e.g. toStartOfHour
+func balanceHistoryView(table, bucket string) statsView {
+	selectBuckets := fmt.Sprintf(`
+			SELECT chain_name, address, denom, %s(timestamp) AS bucket,
+			       argMaxState(amount, height) AS amount, max(height) AS height
+			FROM balance_events
+			GROUP BY chain_name, address, denom, bucket`, bucket)
+
+	return statsView{
+		table: table,
+		create: fmt.Sprintf(`
+			CREATE TABLE IF NOT EXISTS %s (
+				chain_name LowCardinality(String),
+				address String,
+				denom LowCardinality(String),
+				bucket DateTime,
+				amount AggregateFunction(argMax, UInt256, UInt64),
+				height SimpleAggregateFunction(max, UInt64)
+			) ENGINE = AggregatingMergeTree()
+			PARTITION BY toYYYYMM(bucket)
+			ORDER BY (chain_name, address, denom, bucket)
+		`, table),
+		views: []string{
+			fmt.Sprintf("CREATE MATERIALIZED VIEW IF NOT EXISTS %s_mv\n\t\t\tTO %s AS%s", table, table, selectBuckets),
+		},
+		backfill: []string{
+			"INSERT INTO " + table + selectBuckets,
+		},
+	}
+}
+
+// GetBalanceHistory returns the balance history of an address in a denom,
+// newest first. At ResolutionRaw each point is a balance change; otherwise
+// each is the last balance of an hour or day, read from the downsampled
+// tables, timestamped with the start of its bucket.
+func (s *ClickHouseStore) GetBalanceHistory(ctx context.Context, q BalanceHistoryQuery) ([]types.BalancePoint, error) {
+	var query string
+	switch q.Resolution {
+	case ResolutionRaw:
+		query = `
+			SELECT timestamp, toString(amount), height, tx_hash
+			FROM balance_events
+			WHERE chain_name = ? AND address = ? AND denom = ?`
+	case ResolutionHour, ResolutionDay:
+		query = `
+			SELECT toDateTime64(bucket, 3), toString(argMaxMerge(amount)), max(height), ''
+			FROM ` + balanceHistoryTables[q.Resolution] + `
+			WHERE chain_name = ? AND address = ? AND denom = ?`
+	default:
+		return nil, fmt.Errorf("unknown balance history resolution %q", q.Resolution)
+	}
+
+	column := "timestamp"
+	if q.Resolution != ResolutionRaw {
+		column = "bucket"
+	}
+	args := []any{q.ChainName, q.Address, q.Denom}
+	var where strings.Builder
+	if !q.From.IsZero() {
+		where.WriteString(" AND " + column + " >= ?")
+		args = append(args, q.From)
+	}
+	if !q.To.IsZero() {
+		where.WriteString(" AND " + column + " < ?")
+		args = append(args, q.To)
+	}
+	query += where.String()
+	if q.Resolution != ResolutionRaw {
+		query += " GROUP BY bucket"
+	}
+	query += " ORDER BY " + column + " DESC LIMIT ?"
+	args = append(args, q.Limit)
+
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balance history: %w", err)
+	}
+	defer rows.Close()
+
+	points := []types.BalancePoint{}
+	for rows.Next() {
+		var point types.BalancePoint
+		var height uint64
+		if err := rows.Scan(&point.Timestamp, &point.Amount, &height, &point.TxHash); err != nil {
+			return nil, fmt.Errorf("failed to scan balance history: %w", err)
+		}
+		point.Height = int64(height)
+		points = append(points, point)
+	}
+	return points, rows.Err()
+}
+
+// GetDailyHoldings returns an account's balance in a denom and, taking it for
+// the staking denom, its delegations at the end of each of the days from
+// start. The last balance of each day comes from the downsampled daily
+// balances and the last shares of each delegation from the delegation events.
+func (s *ClickHouseStore) GetDailyHoldings(ctx context.Context, chainName, address, denom string, start time.Time, days int) ([]types.PortfolioHoldings, error) {
+	end := start.AddDate(0, 0, days)
+	var changes []holdingChange
+
+	// Changes before the first day are folded into it
+	rows, err := s.conn.Query(ctx, `
+		SELECT greatest(toDate(bucket), toDate(?)) AS day, '', toString(argMaxMerge(amount))
+		FROM balance_history_daily
+		WHERE chain_name = ? AND address = ? AND denom = ? AND bucket < ?
+		GROUP BY day
+		UNION ALL
+		SELECT greatest(toDate(timestamp), toDate(?)) AS day, validator_address, toString(argMax(shares, height))
+		FROM delegation_events
+		WHERE chain_name = ? AND delegator_address = ? AND timestamp < ?
+		GROUP BY day, validator_address
+		ORDER BY day
+	`, start, chainName, address, denom, end, start, chainName, address, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily holdings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var change holdingChange
+		var amount string
+		if err := rows.Scan(&change.day, &change.validator, &amount); err != nil {
+			return nil, fmt.Errorf("failed to scan daily holdings: %w", err)
+		}
+		if change.amount, err = decimal.NewFromString(amount); err != nil {
+			return nil, fmt.Errorf("invalid amount %q of daily holdings: %w", amount, err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	holdings := carryHoldings(start, days, changes)
+	for i := range holdings {
+		holdings[i].ChainName = chainName
+		holdings[i].Denom = denom
+	}
+	return holdings, nil
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// InsertBaseFees records the base fees of a chain's fee market
+func (s *ClickHouseStore) InsertBaseFees(ctx context.Context, fees []types.BaseFee) error {
+	if len(fees) == 0 {
+		return nil
+	}
+	return s.write(ctx, spoolRecord{BaseFees: fees}, func(ctx context.Context) error {
+		return s.insertBaseFees(ctx, fees)
+	})
+}
+
+// insertBaseFees writes base fees to ClickHouse
+func (s *ClickHouseStore) insertBaseFees(ctx context.Context, fees []types.BaseFee) error {
+	if len(fees) == 0 {
+		return nil
+	}
+
+	batch, err := s.conn.PrepareBatch(ctx, `
+		INSERT INTO base_fees (chain_name, denom, height, amount, timestamp)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare base fees batch: %w", err)
+	}
+
+	for _, fee := range fees {
+		amount, err := decimal256Value(fee.Amount)
+		if err != nil {
+			return err
+		}
+		if err := batch.Append(fee.ChainName, fee.Denom, uint64(fee.Height), amount, fee.Timestamp); err != nil {
+			return fmt.Errorf("failed to append base fee: %w", err)
+		}
+	}
+
+	return batch.Send()
+}
+
+// GetBaseFees returns the base fees of a chain recorded since a time by
+// denom, oldest first
+func (s *ClickHouseStore) GetBaseFees(ctx context.Context, chainName string, since time.Time) ([]types.BaseFee, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT denom, height, toString(amount), timestamp
+		FROM base_fees FINAL
+		WHERE chain_name = ? AND timestamp >= ?
+		ORDER BY denom, height
+	`, chainName, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query base fees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []types.BaseFee
+	for rows.Next() {
+		fee := types.BaseFee{ChainName: chainName}
+		var height uint64
+		if err := rows.Scan(&fee.Denom, &height, &fee.Amount, &fee.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan base fee: %w", err)
+		}
+		fee.Height = int64(height)
+		fees = append(fees, fee)
+	}
+
+	return fees, rows.Err()
+}
+
+// GetBaseFeeHistory returns the lowest, mean and highest base fee of each
+// denom of a chain per hour since a time, oldest first
+func (s *ClickHouseStore) GetBaseFeeHistory(ctx context.Context, chainName string, since time.Time) ([]types.BaseFeeStats, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT toStartOfHour(timestamp) AS hour, denom,
+		       toString(min(amount)), toString(toDecimal256(avg(amount), 18)), toString(max(amount)), count()
+		FROM base_fees FINAL
+		WHERE chain_name = ? AND timestamp >= ?
+		GROUP BY hour, denom
+		ORDER BY hour, denom
+	`, chainName, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query base fee history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []types.BaseFeeStats{}
+	for rows.Next() {
+		var stats types.BaseFeeStats
+		if err := rows.Scan(&stats.Time, &stats.Denom, &stats.Min, &stats.Average, &stats.Max, &stats.Samples); err != nil {
+			return nil, fmt.Errorf("failed to scan base fee history: %w", err)
+		}
+		history = append(history, stats)
+	}
+
+	return history, rows.Err()
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// InsertBlockProduction records block proposers and the commit signatures of
+// each validator
+func (s *ClickHouseStore) InsertBlockProduction(ctx context.Context, blocks []types.Block, signatures []types.BlockSignature) error {
+	if len(blocks) == 0 && len(signatures) == 0 {
+		return nil
+	}
+
+	return s.write(ctx, spoolRecord{Blocks: blocks, Signatures: signatures}, func(ctx context.Context) error {
+		return s.insertBlockProduction(ctx, blocks, signatures)
+	})
+}
+
+// insertBlockProduction writes block proposers and signatures to ClickHouse
+func (s *ClickHouseStore) insertBlockProduction(ctx context.Context, blocks []types.Block, signatures []types.BlockSignature) error {
+	if len(blocks) > 0 {
+		batch, err := s.conn.PrepareBatch(ctx, `
+			INSERT INTO block_proposers (chain_name, height, proposer_address, timestamp, ingested_at)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare block proposers batch: %w", err)
+		}
+
+		// Spooled blocks count as ingested once they reach ClickHouse
+		ingestedAt := time.Now()
+		for _, block := range blocks {
+			if err := batch.Append(block.ChainName, uint64(block.Height), block.ProposerAddress, block.Time, ingestedAt); err != nil {
+				return fmt.Errorf("failed to append block proposer: %w", err)
+			}
+		}
+
+		if err := batch.Send(); err != nil {
+			return fmt.Errorf("failed to insert block proposers: %w", err)
+		}
+	}
+
+	if len(signatures) > 0 {
+		batch, err := s.conn.PrepareBatch(ctx, `
+			INSERT INTO block_signatures (chain_name, height, consensus_address, signed, timestamp)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare block signatures batch: %w", err)
+		}
+
+		for _, sig := range signatures {
+			var signed uint8
+			if sig.Signed {
+				signed = 1
+			}
+			if err := batch.Append(sig.ChainName, uint64(sig.Height), sig.ConsensusAddress, signed, sig.Timestamp); err != nil {
+				return fmt.Errorf("failed to append block signature: %w", err)
+			}
+		}
+
+		if err := batch.Send(); err != nil {
+			return fmt.Errorf("failed to insert block signatures: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetBlockProduction returns proposed, signed and missed block counts per
+// consensus address since the given time, and the number of blocks proposed in
+// that window
+func (s *ClickHouseStore) GetBlockProduction(ctx context.Context, chainName string, since time.Time) (map[string]*types.ValidatorBlockProduction, uint64, error) {
+	production := make(map[string]*types.ValidatorBlockProduction)
+	entry := func(address string) *types.ValidatorBlockProduction {
+		if p, ok := production[address]; ok {
+			return p
+		}
+		p := &types.ValidatorBlockProduction{ConsensusAddress: address}
+		production[address] = p
+		return p
+	}
+
+	rows, err := s.conn.Query(ctx, `
+		SELECT proposer_address, uniqExact(height)
+		FROM block_proposers
+		WHERE chain_name = ? AND timestamp >= ?
+		GROUP BY proposer_address
+	`, chainName, since)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query block proposers: %w", err)
+	}
+	defer rows.Close()
+
+	var total uint64
+	for rows.Next() {
+		var address string
+		var proposed uint64
+		if err := rows.Scan(&address, &proposed); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan block proposer: %w", err)
+		}
+		entry(address).Proposed = proposed
+		total += proposed
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	sigRows, err := s.conn.Query(ctx, `
+		SELECT consensus_address,
+		       uniqExactIf(height, signed = 1),
+		       uniqExactIf(height, signed = 0),
+		       maxIf(height, signed = 1),
+		       max(height)
+		FROM block_signatures
+		WHERE chain_name = ? AND timestamp >= ?
+		GROUP BY consensus_address
+	`, chainName, since)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query block signatures: %w", err)
+	}
+	defer sigRows.Close()
+
+	for sigRows.Next() {
+		var address string
+		var signed, missed, lastSigned, lastSeen uint64
+		if err := sigRows.Scan(&address, &signed, &missed, &lastSigned, &lastSeen); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan block signatures: %w", err)
+		}
+
+		p := entry(address)
+		p.Signed = signed
+		p.Missed = missed
+		// Consecutive misses up to the latest recorded height
+		if lastSigned > 0 {
+			p.MissedStreak = lastSeen - lastSigned
+		} else {
+			p.MissedStreak = missed
+		}
+	}
+
+	return production, total, sigRows.Err()
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// InsertDecodedStateChanges inserts changes to stores without a built-in
+// module for analytics
+func (s *ClickHouseStore) InsertDecodedStateChanges(ctx context.Context, changes []types.DecodedStateChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return s.write(ctx, spoolRecord{Decoded: changes}, func(ctx context.Context) error {
+		return s.insertDecodedStateChanges(ctx, changes)
+	})
+}
+
+// insertDecodedStateChanges writes decoded state changes to ClickHouse
+func (s *ClickHouseStore) insertDecodedStateChanges(ctx context.Context, changes []types.DecodedStateChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	batch, err := s.conn.PrepareBatch(ctx, `
+		INSERT INTO decoded_state_changes (
+			chain_name, store_key, entity, key, value, value_encoding,
+			is_delete, height, timestamp
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare decoded state change batch: %w", err)
+	}
+
+	for _, change := range changes {
+		var isDelete uint8
+		if change.Delete {
+			isDelete = 1
+		}
+		if err := batch.Append(
+			change.ChainName,
+			change.StoreKey,
+			change.Entity,
+			change.Key,
+			change.Value,
+			change.Encoding,
+			isDelete,
+			uint64(change.Height),
+			change.Timestamp,
+		); err != nil {
+			return fmt.Errorf("failed to append decoded state change: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to insert decoded state changes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// uniqueEvents drops the events whose ID is repeated in the batch, keeping
+// the first, and returns the kept events and their IDs. Events without an ID
+// are kept. Stored events are not looked up: a replayed or retried batch
+// carries the same IDs, hence the same deduplication token, and ClickHouse
+// ignores it.
+func uniqueEvents[T any](events []T, eventID func(*T) string) ([]T, []string) {
+	kept := events[:0:0]
+	var ids []string
+	seen := make(map[string]bool)
+	for i := range events {
+		id := eventID(&events[i])
+		if id != "" {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+		kept = append(kept, events[i])
+	}
+	return kept, ids
+}
+
+// dedupContext tags an insert with a token derived from its event IDs, so that
+// ClickHouse ignores a retry of an insert that already landed, along with the
+// materialized views it feeds
+func dedupContext(ctx context.Context, ids []string) context.Context {
+	if len(ids) == 0 {
+		return ctx
+	}
+	return clickhouse.Context(ctx, clickhouse.WithSettings(dedupSettings(ids)))
+}
+
+// dedupSettings returns the insert settings carrying the deduplication token
+// of a batch of event IDs
+func dedupSettings(ids []string) clickhouse.Settings {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, ",")))
+
+	return clickhouse.Settings{
+		"insert_deduplication_token":                         hex.EncodeToString(sum[:]),
+		"deduplicate_blocks_in_dependent_materialized_views": 1,
+	}
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// InsertEpochs records epoch starts for bucketing analytics by epoch
+func (s *ClickHouseStore) InsertEpochs(ctx context.Context, epochs []types.Epoch) error {
+	if len(epochs) == 0 {
+		return nil
+	}
+	return s.write(ctx, spoolRecord{Epochs: epochs}, func(ctx context.Context) error {
+		return s.insertEpochs(ctx, epochs)
+	})
+}
+
+// insertEpochs writes epoch starts to ClickHouse
+func (s *ClickHouseStore) insertEpochs(ctx context.Context, epochs []types.Epoch) error {
+	if len(epochs) == 0 {
+		return nil
+	}
+
+	batch, err := s.conn.PrepareBatch(ctx, `
+		INSERT INTO epochs (
+			chain_name, identifier, number, start_height, start_time, duration_seconds
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare epochs batch: %w", err)
+	}
+
+	for _, epoch := range epochs {
+		err := batch.Append(
+			epoch.ChainName,
+			epoch.Identifier,
+			uint64(epoch.Number),
+			uint64(epoch.StartHeight),
+			epoch.StartTime,
+			uint64(epoch.DurationSeconds),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append epoch: %w", err)
+		}
+	}
+
+	return batch.Send()
+}
+
+// GetEpochs returns the last recorded epochs of an epoch identifier of a chain,
+// newest first
+func (s *ClickHouseStore) GetEpochs(ctx context.Context, chainName, identifier string, limit int) ([]types.Epoch, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT number, start_height, start_time, duration_seconds
+		FROM epochs FINAL
+		WHERE chain_name = ? AND identifier = ?
+		ORDER BY number DESC
+		LIMIT ?
+	`, chainName, identifier, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query epochs: %w", err)
+	}
+	defer rows.Close()
+
+	var epochs []types.Epoch
+	for rows.Next() {
+		epoch := types.Epoch{ChainName: chainName, Identifier: identifier}
+		var number, height, duration uint64
+		if err := rows.Scan(&number, &height, &epoch.StartTime, &duration); err != nil {
+			return nil, fmt.Errorf("failed to scan epoch: %w", err)
+		}
+		epoch.Number = int64(number)
+		epoch.StartHeight = int64(height)
+		epoch.DurationSeconds = int64(duration)
+		epochs = append(epochs, epoch)
+	}
+
+	return epochs, rows.Err()
+}
+
+// lastEpochs is a subquery with the start heights of the last recorded epochs
+// of an epoch identifier of a chain
+const lastEpochs = `
+	SELECT chain_name, number, start_height, start_time
+	FROM epochs FINAL
+	WHERE chain_name = ? AND identifier = ?
+	ORDER BY number DESC
+	LIMIT ?
+`
+
+// GetEpochFeeStats returns the fees and gas of a chain's transactions over the
+// last epochs of an epoch identifier by epoch and fee denom, newest first. A
+// transaction belongs to the last epoch started at or below its height.
+func (s *ClickHouseStore) GetEpochFeeStats(ctx context.Context, chainName, identifier string, epochs int) ([]types.EpochFeeStats, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT e.number, any(e.start_height), any(e.start_time), t.fee_denom,
+		       count(), countIf(t.code != 0), sum(toFloat64(t.fee_amount)),
+		       sum(t.gas_wanted), sum(t.gas_used)
+		FROM transactions AS t FINAL
+		ASOF INNER JOIN (`+lastEpochs+`) AS e
+		ON t.chain_name = e.chain_name AND t.height >= e.start_height
+		WHERE t.chain_name = ?
+		  AND t.height >= (SELECT min(start_height) FROM (`+lastEpochs+`))
+		GROUP BY e.number, t.fee_denom
+		ORDER BY e.number DESC, t.fee_denom
+	`, chainName, identifier, epochs, chainName, chainName, identifier, epochs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query epoch fee stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []types.EpochFeeStats
+	for rows.Next() {
+		var stat types.EpochFeeStats
+		var number, height uint64
+		err := rows.Scan(
+			&number,
+			&height,
+			&stat.StartTime,
+			&stat.Denom,
+			&stat.Txs,
+			&stat.FailedTxs,
+			&stat.Fees,
+			&stat.GasWanted,
+			&stat.GasUsed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan epoch fee stats: %w", err)
+		}
+		stat.Epoch = int64(number)
+		stat.StartHeight = int64(height)
+		stat.AverageFee = averageFee(stat.FeeStats)
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+// eventTables are the event tables whose writes are controlled by
+// event_writes. With buffer_table enabled, a Buffer table named
+// <table>_buffer is put in front of each.
+var eventTables = []string{"balance_events", "delegation_events", "reward_events"}
+
+// eventTable returns the table that an insert of events with the given IDs
+// goes to. Buffer tables ignore insert_deduplication_token, so inserts
+// deduplicated by their event IDs, such as streamed events and reward events,
+// go to the event table even with buffer_table enabled; only events without
+// IDs are buffered.
+func (s *ClickHouseStore) eventTable(table string, ids []string) string {
+	if s.bufferTables.Load() && len(ids) == 0 {
+		return table + "_buffer"
+	}
+	return table
+}
+
+// eventContext tags an event insert with the deduplication token of its event
+// IDs and, when async inserts are enabled, the async insert settings. Async
+// inserts are deduplicated by the same token.
+func (s *ClickHouseStore) eventContext(ctx context.Context, ids []string) context.Context {
+	settings := clickhouse.Settings{}
+	if len(ids) > 0 {
+		maps.Copy(settings, dedupSettings(ids))
+	}
+	if async := s.writes.AsyncInsert; async.Enabled {
+		wait := 0
+		if async.Wait {
+			wait = 1
+		}
+		settings["async_insert"] = 1
+		settings["async_insert_deduplicate"] = 1
+		settings["wait_for_async_insert"] = wait
+		settings["async_insert_max_data_size"] = async.MaxDataSize
+		settings["async_insert_busy_timeout_ms"] = async.BusyTimeout.Milliseconds()
+	}
+
+	if len(settings) == 0 {
+		return ctx
+	}
+	return clickhouse.Context(ctx, clickhouse.WithSettings(settings))
+}
+
+// EnsureEventBuffers creates the Buffer tables in front of the event tables
+// when buffer_table is enabled, and recreates those whose flush thresholds no
+// longer match the configuration. Dropping a Buffer table flushes its pending
+// rows to the event table first. Inserts only go through the Buffer tables
+// once they are all in place.
+func (s *ClickHouseStore) EnsureEventBuffers(ctx context.Context) error {
+	buffer := s.writes.BufferTable
+	if !buffer.Enabled {
+		return nil
+	}
+
+	thresholds := fmt.Sprintf("%d, %d, %d, %d, %d, %d, %d",
+		buffer.Layers,
+		int64(buffer.MinTime.Seconds()), int64(buffer.MaxTime.Seconds()),
+		buffer.MinRows, buffer.MaxRows,
+		buffer.MinBytes, buffer.MaxBytes)
+
+	for _, table := range eventTables {
+		name := table + "_buffer"
+
+		var engine string
+		err := s.conn.QueryRow(ctx, `
+			SELECT engine_full FROM system.tables WHERE database = currentDatabase() AND name = ?
+		`, name).Scan(&engine)
+		switch {
+		case err == nil && strings.HasSuffix(engine, thresholds+")"):
+			continue
+		case err == nil:
+			s.logger.Info("Recreating event buffer table with new flush thresholds", zap.String("table", name))
+			if err := s.conn.Exec(ctx, "DROP TABLE "+name); err != nil {
+				return fmt.Errorf("failed to drop buffer table %s: %w", name, err)
+			}
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("failed to check buffer table %s: %w", name, err)
+		}
+
+		err = s.conn.Exec(ctx, fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s AS %s ENGINE = Buffer(currentDatabase(), %s, %s)",
+			name, table, table, thresholds))
+		if err != nil {
+			return fmt.Errorf("failed to create buffer table %s: %w", name, err)
+		}
+	}
+
+	s.bufferTables.Store(true)
+	return nil
+}
+
+// balanceKey identifies the balance a balance event changes
+type balanceKey struct {
+	chain, address, denom string
+}
+
+// balanceAggregator coalesces balance events between inserts, keeping one
+// event per chain, address and denom with the latest balance and the previous
+// amount of the earliest event. Intermediate balances within a flush interval
+// are not recorded in the balance history.
+type balanceAggregator struct {
+	maxKeys int
+
+	mu     sync.Mutex
+	events map[balanceKey]types.BalanceEvent
+	full   chan struct{}
+}
+
+// newBalanceAggregator returns an aggregator that asks to be flushed once it
+// holds maxKeys balances
+func newBalanceAggregator(maxKeys int) *balanceAggregator {
+	return &balanceAggregator{
+		maxKeys: maxKeys,
+		events:  make(map[balanceKey]types.BalanceEvent),
+		full:    make(chan struct{}, 1),
+	}
+}
+
+// add merges events into the pending balances
+func (a *balanceAggregator) add(events []types.BalanceEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, event := range events {
+		key := balanceKey{event.ChainName, event.Address, event.Denom}
+		pending, ok := a.events[key]
+		switch {
+		case !ok:
+		case event.Height >= pending.Height:
+			event.PreviousAmount = pending.PreviousAmount
+		default:
+			pending.PreviousAmount = event.PreviousAmount
+			event = pending
+		}
+		a.events[key] = event
+	}
+
+	if len(a.events) >= a.maxKeys {
+		select {
+		case a.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// drain returns and clears the pending balances
+func (a *balanceAggregator) drain() []types.BalanceEvent {
+	a.mu.Lock()
+	pending := a.events
+	a.events = make(map[balanceKey]types.BalanceEvent, len(pending))
+	a.mu.Unlock()
+
+	events := make([]types.BalanceEvent, 0, len(pending))
+	for _, event := range pending {
+		events = append(events, event)
+	}
+	return events
+}
+
+// aggregateLoop inserts the aggregated balance events every flush interval or
+// once the aggregator is full, and a last time when the store closes
+func (s *ClickHouseStore) aggregateLoop(ctx context.Context, interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.flushBalances(context.Background())
+			return
+		case <-ticker.C:
+		case <-s.balances.full:
+		}
+		s.flushBalances(ctx)
+	}
+}
+
+// flushBalances inserts the aggregated balance events
+func (s *ClickHouseStore) flushBalances(ctx context.Context) {
+	events := s.balances.drain()
+	if len(events) == 0 {
+		return
+	}
+
+	err := s.write(ctx, spoolRecord{Balances: events}, func(ctx context.Context) error {
+		return s.insertBalanceEvents(ctx, events)
+	})
+	if err != nil {
+		s.logger.Warn("Failed to insert aggregated balance events", zap.Int("events", len(events)), zap.Error(err))
+	}
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// InsertRedelegationEvents inserts redelegation events for the delegation flow
+// graph
+func (s *ClickHouseStore) InsertRedelegationEvents(ctx context.Context, events []types.RedelegationEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return s.write(ctx, spoolRecord{Redelegations: events}, func(ctx context.Context) error {
+		return s.insertRedelegationEvents(ctx, events)
+	})
+}
+
+// insertRedelegationEvents writes redelegation events to ClickHouse
+func (s *ClickHouseStore) insertRedelegationEvents(ctx context.Context, events []types.RedelegationEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	events, ids := uniqueEvents(events, func(e *types.RedelegationEvent) string { return e.EventID })
+
+	batch, err := s.conn.PrepareBatch(dedupContext(ctx, ids), `
+		INSERT INTO redelegation_events (
+			timestamp, chain_name, delegator_address, src_validator_address,
+			dst_validator_address, amount, height, event_id
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare redelegation events batch: %w", err)
+	}
+
+	for _, event := range events {
+		amount, err := uint256Value(event.Amount)
+		if err != nil {
+			return err
+		}
+
+		err = batch.Append(
+			event.Timestamp,
+			event.ChainName,
+			event.DelegatorAddress,
+			event.SrcValidatorAddress,
+			event.DstValidatorAddress,
+			amount,
+			uint64(event.Height),
+			event.EventID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append redelegation event: %w", err)
+		}
+	}
+
+	return batch.Send()
+}
+
+// GetDelegationFlows returns the stake redelegated between each pair of
+// validators in [from, to), largest first. A validator narrows the flows to
+// those into or out of it.
+func (s *ClickHouseStore) GetDelegationFlows(ctx context.Context, chainName string, from, to time.Time, validator string) ([]types.DelegationFlow, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT src_validator_address, dst_validator_address,
+		       toString(sum(amount)) AS moved, count(), uniqExact(delegator_address)
+		FROM redelegation_events
+		WHERE chain_name = ? AND timestamp >= ? AND timestamp < ?
+		  AND (? = '' OR src_validator_address = ? OR dst_validator_address = ?)
+		GROUP BY src_validator_address, dst_validator_address
+		ORDER BY sum(amount) DESC, src_validator_address, dst_validator_address
+	`, chainName, from, to, validator, validator, validator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query delegation flows: %w", err)
+	}
+	defer rows.Close()
+
+	var flows []types.DelegationFlow
+	for rows.Next() {
+		var flow types.DelegationFlow
+		err := rows.Scan(
+			&flow.SrcValidatorAddress,
+			&flow.DstValidatorAddress,
+			&flow.Amount,
+			&flow.Redelegations,
+			&flow.Delegators,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delegation flow: %w", err)
+		}
+		flows = append(flows, flow)
+	}
+
+	return flows, rows.Err()
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// InsertPacketEvents inserts IBC packet events for relayer analytics
+func (s *ClickHouseStore) InsertPacketEvents(ctx context.Context, events []types.PacketEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return s.write(ctx, spoolRecord{Packets: events}, func(ctx context.Context) error {
+		return s.insertPacketEvents(ctx, events)
+	})
+}
+
+// insertPacketEvents writes IBC packet events to ClickHouse
+func (s *ClickHouseStore) insertPacketEvents(ctx context.Context, events []types.PacketEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	batch, err := s.conn.PrepareBatch(ctx, `
+		INSERT INTO ibc_packet_events (
+			chain_name, event_type, sequence, src_port, src_channel, dst_port,
+			dst_channel, relayer, tx_hash, height, timestamp
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare packet events batch: %w", err)
+	}
+
+	for _, event := range events {
+		err := batch.Append(
+			event.ChainName,
+			event.Type,
+			event.Sequence,
+			event.SrcPort,
+			event.SrcChannel,
+			event.DstPort,
+			event.DstChannel,
+			event.Relayer,
+			event.TxHash,
+			uint64(event.Height),
+			event.Timestamp,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append packet event: %w", err)
+		}
+	}
+
+	return batch.Send()
+}
+
+// outboundPackets is a subquery with one row per packet a chain sent since a
+// time: when it was sent and, if they happened, when it was acknowledged or
+// timed out
+const outboundPackets = `
+	SELECT
+		src_port, src_channel, sequence,
+		anyIf(dst_channel, event_type = 'send') AS dst_channel,
+		anyIf(relayer, event_type = 'send') AS sender,
+		anyIf(tx_hash, event_type = 'send') AS send_tx,
+		anyIf(height, event_type = 'send') AS send_height,
+		minIf(timestamp, event_type = 'send') AS sent_at,
+		countIf(event_type = 'acknowledge') > 0 AS acknowledged,
+		countIf(event_type = 'timeout') > 0 AS timed_out,
+		dateDiff('millisecond', sent_at, minIf(timestamp, event_type = 'acknowledge')) / 1000 AS latency
+	FROM ibc_packet_events FINAL
+	WHERE chain_name = ? AND event_type IN ('send', 'acknowledge', 'timeout')
+	  AND timestamp >= ?
+	GROUP BY src_port, src_channel, sequence
+	HAVING countIf(event_type = 'send') > 0 AND sent_at >= ?
+`
+
+// GetChannelPerformance returns, per channel, the packets a chain sent since a
+// time: how many were acknowledged or timed out, acknowledgement latency and
+// how many are stuck, unresolved for longer than stuckAfter
+func (s *ClickHouseStore) GetChannelPerformance(ctx context.Context, chainName string, since time.Time, stuckAfter time.Duration) ([]types.ChannelPerformance, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT
+			src_port, src_channel, any(dst_channel),
+			count(), countIf(acknowledged), countIf(timed_out),
+			countIf(NOT acknowledged AND NOT timed_out AND sent_at < ?),
+			ifNotFinite(avgIf(latency, acknowledged), 0),
+			ifNotFinite(quantileIf(0.5)(latency, acknowledged), 0),
+			ifNotFinite(quantileIf(0.95)(latency, acknowledged), 0)
+		FROM (`+outboundPackets+`)
+		GROUP BY src_port, src_channel
+		ORDER BY count() DESC, src_channel
+	`, time.Now().Add(-stuckAfter), chainName, since, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query channel performance: %w", err)
+	}
+	defer rows.Close()
+
+	var channels []types.ChannelPerformance
+	for rows.Next() {
+		var channel types.ChannelPerformance
+		err := rows.Scan(
+			&channel.Port,
+			&channel.Channel,
+			&channel.CounterpartyChannel,
+			&channel.Sent,
+			&channel.Acknowledged,
+			&channel.TimedOut,
+			&channel.Stuck,
+			&channel.AvgLatencySeconds,
+			&channel.P50LatencySeconds,
+			&channel.P95LatencySeconds,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan channel performance: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, rows.Err()
+}
+
+// GetStuckPackets returns the packets a chain sent since a time that were
+// neither acknowledged nor timed out within stuckAfter, oldest first
+func (s *ClickHouseStore) GetStuckPackets(ctx context.Context, chainName string, since time.Time, stuckAfter time.Duration, limit int) ([]types.StuckPacket, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT src_port, src_channel, sequence, sender, send_tx, send_height, sent_at
+		FROM (`+outboundPackets+`)
+		WHERE NOT acknowledged AND NOT timed_out AND sent_at < ?
+		ORDER BY sent_at, src_channel, sequence
+		LIMIT ?
+	`, chainName, since, since, time.Now().Add(-stuckAfter), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stuck packets: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var packets []types.StuckPacket
+	for rows.Next() {
+		var packet types.StuckPacket
+		var height uint64
+		err := rows.Scan(
+			&packet.Port,
+			&packet.Channel,
+			&packet.Sequence,
+			&packet.Sender,
+			&packet.TxHash,
+			&height,
+			&packet.SentAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stuck packet: %w", err)
+		}
+		packet.Height = int64(height)
+		packet.AgeSeconds = now.Sub(packet.SentAt).Seconds()
+		packets = append(packets, packet)
+	}
+
+	return packets, rows.Err()
+}
+
+// GetRelayerLeaderboard returns the relayers that delivered, acknowledged or
+// timed out the most packets on a chain since a time
+func (s *ClickHouseStore) GetRelayerLeaderboard(ctx context.Context, chainName string, since time.Time, limit int) ([]types.RelayerStats, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT
+			relayer,
+			countIf(event_type = 'recv'),
+			countIf(event_type = 'acknowledge'),
+			countIf(event_type = 'timeout'),
+			count() AS total,
+			uniqExact(if(event_type = 'recv', dst_channel, src_channel)),
+			max(timestamp)
+		FROM ibc_packet_events FINAL
+		WHERE chain_name = ? AND event_type IN ('recv', 'acknowledge', 'timeout')
+		  AND relayer != '' AND timestamp >= ?
+		GROUP BY relayer
+		ORDER BY total DESC, relayer
+		LIMIT ?
+	`, chainName, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query relayer leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var relayers []types.RelayerStats
+	for rows.Next() {
+		var relayer types.RelayerStats
+		err := rows.Scan(
+			&relayer.Address,
+			&relayer.Received,
+			&relayer.Acknowledged,
+			&relayer.TimedOut,
+			&relayer.Total,
+			&relayer.Channels,
+			&relayer.LastRelayedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan relayer stats: %w", err)
+		}
+		relayers = append(relayers, relayer)
+	}
+
+	return relayers, rows.Err()
+}
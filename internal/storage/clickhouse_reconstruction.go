@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// FoldBalanceEvents folds the balance events of an address up to a time into
+// its balance of each denom, or of denom only when given. Events of the
+// "current" change type set the balance; "increase" and "decrease" events add
+// and subtract their amount.
+func (s *ClickHouseStore) FoldBalanceEvents(ctx context.Context, chainName, address, denom string, at time.Time) ([]types.ReconstructedBalance, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT denom, change_type, toString(amount), height
+		FROM balance_events
+		WHERE chain_name = ? AND address = ? AND timestamp <= ? AND (? = '' OR denom = ?)
+		ORDER BY denom, height, timestamp
+	`, chainName, address, at, denom, denom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balance events: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []types.ReconstructedBalance
+	var balance *big.Int
+	for rows.Next() {
+		var (
+			eventDenom, changeType, amount string
+			height                         uint64
+		)
+		if err := rows.Scan(&eventDenom, &changeType, &amount, &height); err != nil {
+			return nil, fmt.Errorf("failed to scan balance event: %w", err)
+		}
+
+		if len(balances) == 0 || balances[len(balances)-1].Denom != eventDenom {
+			balances = append(balances, types.ReconstructedBalance{Denom: eventDenom})
+			balance = new(big.Int)
+		}
+		switch changeType {
+		case "increase":
+			balance.Add(balance, parseAmount(amount))
+		case "decrease":
+			balance.Sub(balance, parseAmount(amount))
+		default:
+			balance.Set(parseAmount(amount))
+		}
+
+		folded := &balances[len(balances)-1]
+		folded.Amount = balance.String()
+		folded.Events++
+		folded.EventHeight = int64(height)
+	}
+
+	return balances, rows.Err()
+}
+
+// HeightAt returns the height of a chain's latest block at or before a time
+// among the recorded block proposers, or 0 when none is recorded
+func (s *ClickHouseStore) HeightAt(ctx context.Context, chainName string, at time.Time) (int64, error) {
+	var height uint64
+	err := s.conn.QueryRow(ctx, `
+		SELECT max(height) FROM block_proposers
+		WHERE chain_name = ? AND timestamp <= ?
+	`, chainName, at).Scan(&height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query height at time: %w", err)
+	}
+	return int64(height), nil
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// Account event types that can be replayed
+const (
+	AccountEventBalance    = "balance"
+	AccountEventDelegation = "delegation"
+)
+
+// replayCursor is the position of an event in replay order: events are
+// ordered by height, then type, then denom or validator, then event ID, which
+// tells apart the changes of one key within a block
+type replayCursor struct {
+	height  int64
+	kind    string
+	eventID string
+	key     string
+}
+
+// encode returns the opaque form of the cursor given to clients. The key goes
+// last since denoms may contain colons.
+func (c replayCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s:%s:%s", c.height, c.kind, c.eventID, c.key)))
+}
+
+// decodeReplayCursor parses a cursor returned with a replayed event
+func decodeReplayCursor(cursor string) (replayCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return replayCursor{}, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 {
+		return replayCursor{}, fmt.Errorf("invalid cursor")
+	}
+	height, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return replayCursor{}, fmt.Errorf("invalid cursor")
+	}
+	return replayCursor{height: height, kind: parts[1], eventID: parts[2], key: parts[3]}, nil
+}
+
+// ValidateReplayCursor reports whether a cursor can resume a replay
+func ValidateReplayCursor(cursor string) error {
+	_, err := decodeReplayCursor(cursor)
+	return err
+}
+
+// ReplayAccountEvents returns up to limit balance and delegation events of an
+// address in height order, starting after the given cursor ("" for the
+// beginning). Each event carries the cursor to continue after it.
+func (s *ClickHouseStore) ReplayAccountEvents(ctx context.Context, chainName, address string, filter types.AccountEventFilter, after string, limit int) ([]types.AccountEvent, error) {
+	var bounds []string
+	var boundArgs []any
+	if filter.FromHeight > 0 {
+		bounds = append(bounds, "height >= ?")
+		boundArgs = append(boundArgs, uint64(filter.FromHeight))
+	}
+	if filter.ToHeight > 0 {
+		bounds = append(bounds, "height <= ?")
+		boundArgs = append(boundArgs, uint64(filter.ToHeight))
+	}
+	if !filter.From.IsZero() {
+		bounds = append(bounds, "timestamp >= ?")
+		boundArgs = append(boundArgs, filter.From)
+	}
+	if !filter.To.IsZero() {
+		bounds = append(bounds, "timestamp < ?")
+		boundArgs = append(boundArgs, filter.To)
+	}
+	where := ""
+	if len(bounds) > 0 {
+		where = " AND " + strings.Join(bounds, " AND ")
+	}
+
+	wants := func(kind string) bool {
+		return len(filter.Types) == 0 || slices.Contains(filter.Types, kind)
+	}
+
+	var selects []string
+	var args []any
+	if wants(AccountEventBalance) {
+		selects = append(selects, `
+			SELECT 'balance' AS kind, height, timestamp, denom AS key,
+			       toString(amount) AS value, toString(previous_amount) AS previous,
+			       change_type, tx_hash, event_id
+			FROM balance_events
+			WHERE chain_name = ? AND address = ?`+where)
+		args = append(append(args, chainName, address), boundArgs...)
+	}
+	if wants(AccountEventDelegation) {
+		selects = append(selects, `
+			SELECT 'delegation' AS kind, height, timestamp, validator_address AS key,
+			       toString(shares) AS value, toString(previous_shares) AS previous,
+			       change_type, tx_hash, event_id
+			FROM delegation_events
+			WHERE chain_name = ? AND delegator_address = ?`+where)
+		args = append(append(args, chainName, address), boundArgs...)
+	}
+	if len(selects) == 0 {
+		return nil, nil
+	}
+
+	query := "SELECT kind, height, timestamp, key, value, previous, change_type, tx_hash, event_id FROM (" +
+		strings.Join(selects, " UNION ALL ") + ")"
+	if after != "" {
+		cursor, err := decodeReplayCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		query += " WHERE (height, kind, key, event_id) > (?, ?, ?, ?)"
+		args = append(args, uint64(cursor.height), cursor.kind, cursor.key, cursor.eventID)
+	}
+	query += " ORDER BY height, kind, key, event_id LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []types.AccountEvent
+	for rows.Next() {
+		var (
+			kind, key, value, previous, changeType, txHash, eventID string
+			height                                                  uint64
+			event                                                   types.AccountEvent
+		)
+		if err := rows.Scan(&kind, &height, &event.Timestamp, &key, &value, &previous, &changeType, &txHash, &eventID); err != nil {
+			return nil, fmt.Errorf("failed to scan account event: %w", err)
+		}
+
+		event.Type = kind
+		event.Height = int64(height)
+		event.Cursor = replayCursor{height: event.Height, kind: kind, eventID: eventID, key: key}.encode()
+		switch kind {
+		case AccountEventBalance:
+			event.Balance = &types.BalanceEvent{
+				EventID:        eventID,
+				Timestamp:      event.Timestamp,
+				ChainName:      chainName,
+				Address:        address,
+				Denom:          key,
+				Amount:         value,
+				PreviousAmount: previous,
+				ChangeType:     changeType,
+				Height:         event.Height,
+				TxHash:         txHash,
+			}
+		case AccountEventDelegation:
+			event.Delegation = &types.DelegationEvent{
+				EventID:          eventID,
+				Timestamp:        event.Timestamp,
+				ChainName:        chainName,
+				DelegatorAddress: address,
+				ValidatorAddress: key,
+				Shares:           value,
+				PreviousShares:   previous,
+				ChangeType:       changeType,
+				Height:           event.Height,
+				TxHash:           txHash,
+			}
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
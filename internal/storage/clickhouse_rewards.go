@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// GetWithdrawnRewards returns the rewards a delegator withdrew from each
+// validator on a chain, totaled per denom, as indexed from its transactions.
+// Each reward's height and time are those of its latest withdrawal.
+func (s *ClickHouseStore) GetWithdrawnRewards(ctx context.Context, chainName, delegator string) ([]types.Reward, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT validator_address, denom, toString(sum(amount)), max(height), max(timestamp)
+		FROM reward_events
+		WHERE chain_name = ? AND delegator_address = ?
+		GROUP BY validator_address, denom
+		ORDER BY validator_address, denom
+	`, chainName, delegator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query withdrawn rewards: %w", err)
+	}
+	defer rows.Close()
+
+	rewards := []types.Reward{}
+	for rows.Next() {
+		var validator string
+		var coin types.Coin
+		var height uint64
+		var timestamp time.Time
+		if err := rows.Scan(&validator, &coin.Denom, &coin.Amount, &height, &timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan withdrawn reward: %w", err)
+		}
+
+		// Rows are ordered by validator, so a validator's denoms are adjacent
+		if n := len(rewards); n == 0 || rewards[n-1].ValidatorAddress != validator {
+			rewards = append(rewards, types.Reward{
+				ChainName:        chainName,
+				DelegatorAddress: delegator,
+				ValidatorAddress: validator,
+			})
+		}
+		reward := &rewards[len(rewards)-1]
+		reward.Reward = append(reward.Reward, coin)
+		reward.Height = max(reward.Height, int64(height))
+		if timestamp.After(reward.UpdatedAt) {
+			reward.UpdatedAt = timestamp
+		}
+	}
+	return rewards, rows.Err()
+}
+
+// GetDelegatorRewards returns the cumulative rewards a delegator withdrew on a
+// chain per denom, how many blocks it claimed rewards in and how many of those
+// claims were delegated again within the block
+func (s *ClickHouseStore) GetDelegatorRewards(ctx context.Context, chainName, delegator string) (*types.DelegatorRewards, error) {
+	summary := &types.DelegatorRewards{
+		ChainName:        chainName,
+		DelegatorAddress: delegator,
+		Totals:           []types.DelegatorRewardTotal{},
+	}
+
+	rows, err := s.conn.Query(ctx, `
+		SELECT denom, toString(sum(amount)), toString(sumIf(amount, claimed = 1)),
+		       toString(sumIf(amount, compounded = 1))
+		FROM reward_events
+		WHERE chain_name = ? AND delegator_address = ?
+		GROUP BY denom
+		ORDER BY denom
+	`, chainName, delegator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reward totals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total types.DelegatorRewardTotal
+		if err := rows.Scan(&total.Denom, &total.Withdrawn, &total.Claimed, &total.Compounded); err != nil {
+			return nil, fmt.Errorf("failed to scan reward total: %w", err)
+		}
+		summary.Totals = append(summary.Totals, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var lastHeight uint64
+	var lastTime time.Time
+	err = s.conn.QueryRow(ctx, `
+		SELECT uniqExactIf(height, claimed = 1), uniqExactIf(height, compounded = 1),
+		       maxIf(height, claimed = 1), maxIf(timestamp, claimed = 1)
+		FROM reward_events
+		WHERE chain_name = ? AND delegator_address = ?
+	`, chainName, delegator).Scan(&summary.Claims, &summary.CompoundedClaims, &lastHeight, &lastTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reward claims: %w", err)
+	}
+
+	if summary.Claims > 0 {
+		summary.LastClaimHeight = int64(lastHeight)
+		summary.LastClaimAt = &lastTime
+		summary.AutoCompounding = summary.CompoundedClaims*2 > summary.Claims
+	}
+	return summary, nil
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// seriesQueries select the start of each interval and the value of each
+// series of a charted metric over [from, to). Their arguments are the interval
+// in seconds, the chain, the metric's extra argument if any, from and to.
+var seriesQueries = map[string]struct {
+	series []string
+	query  string
+}{
+	types.SeriesIngestLag: {
+		series: []string{"ingest_lag_seconds"},
+		query: `
+			SELECT toStartOfInterval(block_time, toIntervalSecond(?)) AS bucket,
+			       avg(dateDiff('millisecond', block_time, first_ingested)) / 1000
+			FROM (
+				-- A re-polled block counts from its first ingestion
+				SELECT height, any(timestamp) AS block_time, min(ingested_at) AS first_ingested
+				FROM block_proposers
+				WHERE chain_name = ? AND timestamp >= ? AND timestamp < ? AND ingested_at IS NOT NULL
+				GROUP BY height
+			)
+			GROUP BY bucket
+			ORDER BY bucket`,
+	},
+	types.SeriesDelegationFlows: {
+		series: []string{"delegated", "undelegated", "redelegated"},
+		query: `
+			SELECT bucket, sum(delegated), sum(undelegated), sum(redelegated)
+			FROM (
+				SELECT toStartOfInterval(timestamp, toIntervalSecond(?)) AS bucket,
+				       if(delta > 0, delta, 0) AS delegated,
+				       if(delta < 0, -delta, 0) AS undelegated,
+				       0 AS redelegated
+				FROM (
+					SELECT timestamp, toFloat64(shares) - toFloat64(previous_shares) AS delta
+					FROM delegation_events
+					WHERE chain_name = ? AND timestamp >= ? AND timestamp < ? AND change_type != 'current'
+				)
+				UNION ALL
+				SELECT toStartOfInterval(timestamp, toIntervalSecond(?)) AS bucket,
+				       0, 0, toFloat64(amount)
+				FROM redelegation_events
+				WHERE chain_name = ? AND timestamp >= ? AND timestamp < ?
+			)
+			GROUP BY bucket
+			ORDER BY bucket`,
+	},
+	types.SeriesSupply: {
+		series: []string{"total", "circulating"},
+		query: `
+			SELECT toStartOfInterval(timestamp, toIntervalSecond(?)) AS bucket,
+			       toFloat64(argMax(total, height)), toFloat64(argMax(circulating, height))
+			FROM circulating_supply FINAL
+			WHERE chain_name = ? AND denom = ? AND timestamp >= ? AND timestamp < ?
+			GROUP BY bucket
+			ORDER BY bucket`,
+	},
+}
+
+// GetTimeSeries returns the series of one of types.SeriesMetrics for a chain
+// over [from, to), one point per interval with data. The supply is that of
+// denom; other metrics ignore it.
+func (s *ClickHouseStore) GetTimeSeries(ctx context.Context, metric, chainName, denom string, from, to time.Time, interval time.Duration) ([]types.TimeSeries, error) {
+	spec, ok := seriesQueries[metric]
+	if !ok {
+		return nil, fmt.Errorf("unsupported metric %q", metric)
+	}
+
+	seconds := max(int64(interval.Seconds()), 1)
+	var args []any
+	switch metric {
+	case types.SeriesSupply:
+		args = []any{seconds, chainName, denom, from, to}
+	case types.SeriesDelegationFlows:
+		args = []any{seconds, chainName, from, to, seconds, chainName, from, to}
+	default:
+		args = []any{seconds, chainName, from, to}
+	}
+
+	rows, err := s.conn.Query(ctx, spec.query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s series: %w", metric, err)
+	}
+	defer rows.Close()
+
+	series := make([]types.TimeSeries, len(spec.series))
+	for i, name := range spec.series {
+		series[i] = types.TimeSeries{Name: name, Points: []types.MetricPoint{}}
+	}
+	for rows.Next() {
+		var bucket time.Time
+		values := make([]float64, len(spec.series))
+		dest := []any{&bucket}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan %s series: %w", metric, err)
+		}
+		for i, value := range values {
+			series[i].Points = append(series[i].Points, types.MetricPoint{Time: bucket.UTC(), Value: value})
+		}
+	}
+	return series, rows.Err()
+}
+
+// GetTimeSeries returns the series of a charted metric of a chain over a
+// range of time
+func (m *Manager) GetTimeSeries(ctx context.Context, metric, chain, denom string, from, to time.Time, interval time.Duration) ([]types.TimeSeries, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("time series require analytics storage: %w", ErrUnavailable)
+	}
+	return m.clickhouse.GetTimeSeries(ctx, metric, chain, denom, from, to, interval)
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// InsertCirculatingSupply records the circulating supply of a denom
+func (s *ClickHouseStore) InsertCirculatingSupply(ctx context.Context, supply types.CirculatingSupply) error {
+	records := []types.CirculatingSupply{supply}
+	return s.write(ctx, spoolRecord{Circulating: records}, func(ctx context.Context) error {
+		return s.insertCirculatingSupply(ctx, records)
+	})
+}
+
+// insertCirculatingSupply writes circulating supply records to ClickHouse
+func (s *ClickHouseStore) insertCirculatingSupply(ctx context.Context, records []types.CirculatingSupply) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	batch, err := s.conn.PrepareBatch(ctx, `
+		INSERT INTO circulating_supply (
+			chain_name, denom, height, total, vesting_locked, module_accounts,
+			treasury, circulating, timestamp
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare circulating supply batch: %w", err)
+	}
+
+	for _, record := range records {
+		values := make([]any, 0, 9)
+		values = append(values, record.ChainName, record.Denom, uint64(record.Height))
+		for _, amount := range []string{record.Total, record.VestingLocked, record.ModuleAccounts, record.Treasury, record.Circulating} {
+			value, err := uint256Value(amount)
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+		values = append(values, record.Timestamp)
+
+		if err := batch.Append(values...); err != nil {
+			return fmt.Errorf("failed to append circulating supply: %w", err)
+		}
+	}
+
+	return batch.Send()
+}
+
+// GetCirculatingSupplyHistory returns the last circulating supply of a denom
+// recorded on each day since a time, oldest first
+func (s *ClickHouseStore) GetCirculatingSupplyHistory(ctx context.Context, chainName, denom string, since time.Time) ([]types.CirculatingSupply, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT
+			argMax(height, height),
+			toString(argMax(total, height)),
+			toString(argMax(vesting_locked, height)),
+			toString(argMax(module_accounts, height)),
+			toString(argMax(treasury, height)),
+			toString(argMax(circulating, height)),
+			argMax(timestamp, height)
+		FROM circulating_supply FINAL
+		WHERE chain_name = ? AND denom = ? AND timestamp >= ?
+		GROUP BY toDate(timestamp)
+		ORDER BY toDate(timestamp)
+	`, chainName, denom, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query circulating supply history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []types.CirculatingSupply{}
+	for rows.Next() {
+		record := types.CirculatingSupply{ChainName: chainName, Denom: denom}
+		var height uint64
+		err := rows.Scan(
+			&height,
+			&record.Total,
+			&record.VestingLocked,
+			&record.ModuleAccounts,
+			&record.Treasury,
+			&record.Circulating,
+			&record.Timestamp,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan circulating supply: %w", err)
+		}
+		record.Height = int64(height)
+		history = append(history, record)
+	}
+
+	return history, rows.Err()
+}
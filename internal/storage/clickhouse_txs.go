@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// InsertTransactions inserts indexed transactions for fee and gas analytics
+func (s *ClickHouseStore) InsertTransactions(ctx context.Context, txs []types.Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	return s.write(ctx, spoolRecord{Transactions: txs}, func(ctx context.Context) error {
+		return s.insertTransactions(ctx, txs)
+	})
+}
+
+// insertTransactions writes transactions to ClickHouse
+func (s *ClickHouseStore) insertTransactions(ctx context.Context, txs []types.Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	batch, err := s.conn.PrepareBatch(ctx, `
+		INSERT INTO transactions (
+			chain_name, height, tx_hash, code, gas_wanted, gas_used,
+			fee_denom, fee_amount, fee_payer, message_types, timestamp
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare transactions batch: %w", err)
+	}
+
+	for _, tx := range txs {
+		fee, err := uint256Value(tx.FeeAmount)
+		if err != nil {
+			return err
+		}
+
+		messageTypes := tx.MessageTypes
+		if messageTypes == nil {
+			messageTypes = []string{}
+		}
+
+		err = batch.Append(
+			tx.ChainName,
+			uint64(tx.Height),
+			tx.Hash,
+			tx.Code,
+			uint64(tx.GasWanted),
+			uint64(tx.GasUsed),
+			tx.FeeDenom,
+			fee,
+			tx.FeePayer,
+			messageTypes,
+			tx.Timestamp,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append transaction: %w", err)
+		}
+	}
+
+	return batch.Send()
+}
+
+// GetDailyFeeStats returns the fees and gas of a chain's transactions per day
+// and fee denom
+func (s *ClickHouseStore) GetDailyFeeStats(ctx context.Context, chainName string, days int) ([]types.DailyFeeStats, error) {
+	query := `
+		SELECT date, fee_denom, sum(txs), sum(failed_txs), sum(fees),
+		       sum(gas_wanted), sum(gas_used)
+		FROM daily_fee_stats
+		WHERE chain_name = ? AND date >= today() - ?
+		GROUP BY date, fee_denom
+		ORDER BY date DESC, fee_denom
+	`
+
+	rows, err := s.conn.Query(ctx, query, chainName, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily fee stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []types.DailyFeeStats
+	for rows.Next() {
+		stat := types.DailyFeeStats{ChainName: chainName}
+		err := rows.Scan(
+			&stat.Date,
+			&stat.Denom,
+			&stat.Txs,
+			&stat.FailedTxs,
+			&stat.Fees,
+			&stat.GasWanted,
+			&stat.GasUsed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan daily fee stats: %w", err)
+		}
+		stat.AverageFee = averageFee(stat.FeeStats)
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetMessageTypeFeeStats returns the fees and gas of a chain's transactions
+// over the last days by the type of their first message and fee denom, highest
+// fees first
+func (s *ClickHouseStore) GetMessageTypeFeeStats(ctx context.Context, chainName string, days int) ([]types.MessageTypeFeeStats, error) {
+	query := `
+		SELECT message_type, fee_denom, sum(txs), sum(failed_txs), sum(fees) AS total_fees,
+		       sum(gas_wanted), sum(gas_used)
+		FROM daily_fee_stats
+		WHERE chain_name = ? AND date >= today() - ?
+		GROUP BY message_type, fee_denom
+		ORDER BY total_fees DESC, message_type
+	`
+
+	rows, err := s.conn.Query(ctx, query, chainName, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query message type fee stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []types.MessageTypeFeeStats
+	for rows.Next() {
+		var stat types.MessageTypeFeeStats
+		err := rows.Scan(
+			&stat.MessageType,
+			&stat.Denom,
+			&stat.Txs,
+			&stat.FailedTxs,
+			&stat.Fees,
+			&stat.GasWanted,
+			&stat.GasUsed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message type fee stats: %w", err)
+		}
+		stat.AverageFee = averageFee(stat.FeeStats)
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetTopFeePayers returns the addresses that paid the most fees in a denom
+// over the last days
+func (s *ClickHouseStore) GetTopFeePayers(ctx context.Context, chainName, denom string, days, limit int) ([]types.FeePayer, error) {
+	query := `
+		SELECT fee_payer, sum(toFloat64(fee_amount)) AS fees, count() AS txs
+		FROM transactions FINAL
+		WHERE chain_name = ? AND fee_denom = ? AND fee_payer != ''
+		  AND date >= today() - ?
+		GROUP BY fee_payer
+		ORDER BY fees DESC, fee_payer
+		LIMIT ?
+	`
+
+	rows, err := s.conn.Query(ctx, query, chainName, denom, days, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top fee payers: %w", err)
+	}
+	defer rows.Close()
+
+	var payers []types.FeePayer
+	for rows.Next() {
+		payer := types.FeePayer{Denom: denom}
+		if err := rows.Scan(&payer.Address, &payer.Fees, &payer.Txs); err != nil {
+			return nil, fmt.Errorf("failed to scan fee payer: %w", err)
+		}
+		payers = append(payers, payer)
+	}
+
+	return payers, rows.Err()
+}
+
+// averageFee returns the fees per transaction, 0 without transactions
+func averageFee(stats types.FeeStats) float64 {
+	if stats.Txs == 0 {
+		return 0
+	}
+	return stats.Fees / float64(stats.Txs)
+}
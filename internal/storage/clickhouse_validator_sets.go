@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// InsertValidatorSet inserts a snapshot of a chain's bonded validator set
+func (s *ClickHouseStore) InsertValidatorSet(ctx context.Context, snapshot types.ValidatorSetSnapshot) error {
+	sets := []types.ValidatorSetSnapshot{snapshot}
+	return s.write(ctx, spoolRecord{ValidatorSets: sets}, func(ctx context.Context) error {
+		return s.insertValidatorSets(ctx, sets)
+	})
+}
+
+// insertValidatorSets writes validator set snapshots to ClickHouse, one row per
+// member
+func (s *ClickHouseStore) insertValidatorSets(ctx context.Context, sets []types.ValidatorSetSnapshot) error {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	batch, err := s.conn.PrepareBatch(ctx, `
+		INSERT INTO validator_set_snapshots (
+			chain_name, height, operator_address, consensus_address, moniker,
+			tokens, voting_power, timestamp
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare validator set batch: %w", err)
+	}
+
+	for _, set := range sets {
+		for _, validator := range set.Validators {
+			tokens, err := uint256Value(validator.Tokens)
+			if err != nil {
+				return err
+			}
+
+			err = batch.Append(
+				set.ChainName,
+				uint64(set.Height),
+				validator.OperatorAddress,
+				validator.ConsensusAddress,
+				validator.Moniker,
+				tokens,
+				uint64(validator.VotingPower),
+				set.Timestamp,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to append validator set member: %w", err)
+			}
+		}
+	}
+
+	return batch.Send()
+}
+
+// GetValidatorSetAt returns the latest validator set snapshot at or below a
+// height, or the latest snapshot when height is 0
+func (s *ClickHouseStore) GetValidatorSetAt(ctx context.Context, chainName string, height int64) (*types.ValidatorSetSnapshot, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT height, timestamp, operator_address, consensus_address, moniker,
+		       toString(tokens), voting_power
+		FROM validator_set_snapshots FINAL
+		WHERE chain_name = ? AND height = (
+			SELECT max(height) FROM validator_set_snapshots
+			WHERE chain_name = ? AND (? = 0 OR height <= ?)
+		)
+		ORDER BY voting_power DESC, operator_address
+	`, chainName, chainName, uint64(height), uint64(height))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query validator set: %w", err)
+	}
+	defer rows.Close()
+
+	set := &types.ValidatorSetSnapshot{ChainName: chainName, Validators: []types.ValidatorSetMember{}}
+	for rows.Next() {
+		var member types.ValidatorSetMember
+		var snapshotHeight, votingPower uint64
+		err := rows.Scan(
+			&snapshotHeight,
+			&set.Timestamp,
+			&member.OperatorAddress,
+			&member.ConsensusAddress,
+			&member.Moniker,
+			&member.Tokens,
+			&votingPower,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan validator set member: %w", err)
+		}
+		set.Height = int64(snapshotHeight)
+		member.VotingPower = int64(votingPower)
+		set.TotalVotingPower += member.VotingPower
+		set.Validators = append(set.Validators, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(set.Validators) == 0 {
+		return nil, fmt.Errorf("validator set of %s at height %d: %w", chainName, height, ErrNotFound)
+	}
+	return set, nil
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/shopspring/decimal"
+)
+
+// decimalScale is the number of fractional digits of Decimal256 columns,
+// matching the precision of the Cosmos SDK's LegacyDec
+const decimalScale = 18
+
+// uint256Value converts an integer amount to the value of a UInt256 column.
+// An empty amount is zero.
+func uint256Value(amount string) (*big.Int, error) {
+	if amount == "" {
+		return new(big.Int), nil
+	}
+
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok || value.Sign() < 0 || value.BitLen() > 256 {
+		return nil, fmt.Errorf("invalid UInt256 amount %q", amount)
+	}
+	return value, nil
+}
+
+// decimal256Value converts a decimal amount to the value of a Decimal256(18)
+// column. An empty amount is zero.
+func decimal256Value(amount string) (decimal.Decimal, error) {
+	if amount == "" {
+		return decimal.Zero, nil
+	}
+
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid decimal amount %q: %w", amount, err)
+	}
+	return value.Round(decimalScale), nil
+}
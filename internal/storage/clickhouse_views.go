@@ -0,0 +1,350 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+// statsView describes a pre-aggregated stats table and the materialized views
+// that feed it from raw event tables
+type statsView struct {
+	table    string
+	create   string
+	views    []string
+	backfill []string
+}
+
+// statsViews are the materialized views managed by the ClickHouse store
+var statsViews = []statsView{
+	{
+		table: "daily_active_addresses",
+		create: `
+			CREATE TABLE IF NOT EXISTS daily_active_addresses (
+				chain_name LowCardinality(String),
+				date Date,
+				addresses AggregateFunction(uniq, String)
+			) ENGINE = AggregatingMergeTree()
+			PARTITION BY toYYYYMM(date)
+			ORDER BY (chain_name, date)
+		`,
+		views: []string{
+			`CREATE MATERIALIZED VIEW IF NOT EXISTS daily_active_addresses_balances_mv
+			TO daily_active_addresses AS
+			SELECT chain_name, toDate(timestamp) AS date, uniqState(address) AS addresses
+			FROM balance_events
+			GROUP BY chain_name, date`,
+			`CREATE MATERIALIZED VIEW IF NOT EXISTS daily_active_addresses_delegations_mv
+			TO daily_active_addresses AS
+			SELECT chain_name, toDate(timestamp) AS date, uniqState(delegator_address) AS addresses
+			FROM delegation_events
+			GROUP BY chain_name, date`,
+		},
+		backfill: []string{
+			`INSERT INTO daily_active_addresses
+			SELECT chain_name, toDate(timestamp) AS date, uniqState(address) AS addresses
+			FROM balance_events
+			GROUP BY chain_name, date`,
+			`INSERT INTO daily_active_addresses
+			SELECT chain_name, toDate(timestamp) AS date, uniqState(delegator_address) AS addresses
+			FROM delegation_events
+			GROUP BY chain_name, date`,
+		},
+	},
+	{
+		table: "daily_delegation_volume",
+		create: `
+			CREATE TABLE IF NOT EXISTS daily_delegation_volume (
+				chain_name LowCardinality(String),
+				date Date,
+				delegated Float64,
+				undelegated Float64,
+				events UInt64
+			) ENGINE = SummingMergeTree()
+			PARTITION BY toYYYYMM(date)
+			ORDER BY (chain_name, date)
+		`,
+		views: []string{
+			`CREATE MATERIALIZED VIEW IF NOT EXISTS daily_delegation_volume_mv
+			TO daily_delegation_volume AS
+			SELECT
+				chain_name,
+				toDate(timestamp) AS date,
+				sumIf(delta, delta > 0) AS delegated,
+				sumIf(-delta, delta < 0) AS undelegated,
+				count() AS events
+			FROM (
+				SELECT chain_name, timestamp,
+				       toFloat64(shares) - toFloat64(previous_shares) AS delta
+				FROM delegation_events
+				WHERE change_type != 'current'
+			)
+			GROUP BY chain_name, date`,
+		},
+		backfill: []string{
+			`INSERT INTO daily_delegation_volume
+			SELECT
+				chain_name,
+				toDate(timestamp) AS date,
+				sumIf(delta, delta > 0) AS delegated,
+				sumIf(-delta, delta < 0) AS undelegated,
+				count() AS events
+			FROM (
+				SELECT chain_name, timestamp,
+				       toFloat64(shares) - toFloat64(previous_shares) AS delta
+				FROM delegation_events
+				WHERE change_type != 'current'
+			)
+			GROUP BY chain_name, date`,
+		},
+	},
+	{
+		table: "daily_reward_issuance",
+		create: `
+			CREATE TABLE IF NOT EXISTS daily_reward_issuance (
+				chain_name LowCardinality(String),
+				date Date,
+				denom LowCardinality(String),
+				amount Float64,
+				events UInt64
+			) ENGINE = SummingMergeTree()
+			PARTITION BY toYYYYMM(date)
+			ORDER BY (chain_name, date, denom)
+		`,
+		views: []string{
+			`CREATE MATERIALIZED VIEW IF NOT EXISTS daily_reward_issuance_mv
+			TO daily_reward_issuance AS
+			SELECT chain_name, toDate(timestamp) AS date, denom,
+			       sum(toFloat64(amount)) AS amount, count() AS events
+			FROM reward_events
+			GROUP BY chain_name, date, denom`,
+		},
+		backfill: []string{
+			`INSERT INTO daily_reward_issuance
+			SELECT chain_name, toDate(timestamp) AS date, denom,
+			       sum(toFloat64(amount)) AS amount, count() AS events
+			FROM reward_events
+			GROUP BY chain_name, date, denom`,
+		},
+	},
+	{
+		table: "daily_fee_stats",
+		create: `
+			CREATE TABLE IF NOT EXISTS daily_fee_stats (
+				chain_name LowCardinality(String),
+				date Date,
+				message_type LowCardinality(String),
+				fee_denom LowCardinality(String),
+				txs UInt64,
+				failed_txs UInt64,
+				fees Float64,
+				gas_wanted UInt64,
+				gas_used UInt64
+			) ENGINE = SummingMergeTree()
+			PARTITION BY toYYYYMM(date)
+			ORDER BY (chain_name, date, message_type, fee_denom)
+		`,
+		views: []string{
+			`CREATE MATERIALIZED VIEW IF NOT EXISTS daily_fee_stats_mv
+			TO daily_fee_stats AS
+			SELECT chain_name, toDate(timestamp) AS date,
+			       message_types[1] AS message_type, fee_denom,
+			       count() AS txs, countIf(code != 0) AS failed_txs,
+			       sum(toFloat64(fee_amount)) AS fees,
+			       sum(gas_wanted) AS gas_wanted, sum(gas_used) AS gas_used
+			FROM transactions
+			GROUP BY chain_name, date, message_type, fee_denom`,
+		},
+		backfill: []string{
+			`INSERT INTO daily_fee_stats
+			SELECT chain_name, toDate(timestamp) AS date,
+			       message_types[1] AS message_type, fee_denom,
+			       count() AS txs, countIf(code != 0) AS failed_txs,
+			       sum(toFloat64(fee_amount)) AS fees,
+			       sum(gas_wanted) AS gas_wanted, sum(gas_used) AS gas_used
+			FROM transactions FINAL
+			GROUP BY chain_name, date, message_type, fee_denom`,
+		},
+	},
+	{
+		table: "daily_message_activity",
+		create: `
+			CREATE TABLE IF NOT EXISTS daily_message_activity (
+				chain_name LowCardinality(String),
+				date Date,
+				message_type LowCardinality(String),
+				messages UInt64,
+				failed_messages UInt64
+			) ENGINE = SummingMergeTree()
+			PARTITION BY toYYYYMM(date)
+			ORDER BY (chain_name, date, message_type)
+		`,
+		views: []string{
+			`CREATE MATERIALIZED VIEW IF NOT EXISTS daily_message_activity_mv
+			TO daily_message_activity AS
+			SELECT chain_name, toDate(timestamp) AS date, message_type,
+			       count() AS messages, countIf(code != 0) AS failed_messages
+			FROM transactions
+			ARRAY JOIN message_types AS message_type
+			GROUP BY chain_name, date, message_type`,
+		},
+		backfill: []string{
+			`INSERT INTO daily_message_activity
+			SELECT chain_name, toDate(timestamp) AS date, message_type,
+			       count() AS messages, countIf(code != 0) AS failed_messages
+			FROM transactions FINAL
+			ARRAY JOIN message_types AS message_type
+			GROUP BY chain_name, date, message_type`,
+		},
+	},
+	balanceHistoryView("balance_history_hourly", "toStartOfHour"),
+	balanceHistoryView("balance_history_daily", "toStartOfDay"),
+}
+
+// EnsureStatsViews creates the pre-aggregated stats tables and their materialized
+// views. Tables that did not exist before are backfilled from the raw event
+// tables before their views are created, so no event is counted by both; it
+// runs at startup, before the process writes events.
+func (s *ClickHouseStore) EnsureStatsViews(ctx context.Context) error {
+	for _, view := range statsViews {
+		var exists uint8
+		if err := s.conn.QueryRow(ctx, "EXISTS TABLE "+view.table).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check stats table %s: %w", view.table, err)
+		}
+
+		if err := s.conn.Exec(ctx, view.create); err != nil {
+			return fmt.Errorf("failed to create stats table %s: %w", view.table, err)
+		}
+
+		if exists == 0 {
+			s.logger.Info("Backfilling stats table", zap.String("table", view.table))
+			for _, stmt := range view.backfill {
+				if err := s.conn.Exec(ctx, stmt); err != nil {
+					return fmt.Errorf("failed to backfill stats table %s: %w", view.table, err)
+				}
+			}
+		}
+
+		for _, stmt := range view.views {
+			if err := s.conn.Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("failed to create materialized view for %s: %w", view.table, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetDailyActiveAddresses returns the number of distinct active addresses per day
+func (s *ClickHouseStore) GetDailyActiveAddresses(ctx context.Context, chainName string, days int) ([]types.DailyActiveAddresses, error) {
+	query := `
+		SELECT date, uniqMerge(addresses) AS active_addresses
+		FROM daily_active_addresses
+		WHERE chain_name = ? AND date >= today() - ?
+		GROUP BY date
+		ORDER BY date DESC
+	`
+
+	rows, err := s.conn.Query(ctx, query, chainName, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily active addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []types.DailyActiveAddresses
+	for rows.Next() {
+		stat := types.DailyActiveAddresses{ChainName: chainName}
+		if err := rows.Scan(&stat.Date, &stat.ActiveAddresses); err != nil {
+			return nil, fmt.Errorf("failed to scan daily active addresses: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetDailyDelegationVolume returns delegated and undelegated volume per day
+func (s *ClickHouseStore) GetDailyDelegationVolume(ctx context.Context, chainName string, days int) ([]types.DailyDelegationVolume, error) {
+	query := `
+		SELECT date, sum(delegated), sum(undelegated), sum(events)
+		FROM daily_delegation_volume
+		WHERE chain_name = ? AND date >= today() - ?
+		GROUP BY date
+		ORDER BY date DESC
+	`
+
+	rows, err := s.conn.Query(ctx, query, chainName, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily delegation volume: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []types.DailyDelegationVolume
+	for rows.Next() {
+		stat := types.DailyDelegationVolume{ChainName: chainName}
+		if err := rows.Scan(&stat.Date, &stat.Delegated, &stat.Undelegated, &stat.Events); err != nil {
+			return nil, fmt.Errorf("failed to scan daily delegation volume: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetDailyRewardIssuance returns rewards issued per day and denom
+func (s *ClickHouseStore) GetDailyRewardIssuance(ctx context.Context, chainName string, days int) ([]types.DailyRewardIssuance, error) {
+	query := `
+		SELECT date, denom, sum(amount), sum(events)
+		FROM daily_reward_issuance
+		WHERE chain_name = ? AND date >= today() - ?
+		GROUP BY date, denom
+		ORDER BY date DESC, denom
+	`
+
+	rows, err := s.conn.Query(ctx, query, chainName, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily reward issuance: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []types.DailyRewardIssuance
+	for rows.Next() {
+		stat := types.DailyRewardIssuance{ChainName: chainName}
+		if err := rows.Scan(&stat.Date, &stat.Denom, &stat.Amount, &stat.Events); err != nil {
+			return nil, fmt.Errorf("failed to scan daily reward issuance: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetDailyMessageActivity returns the number of messages per day and type
+func (s *ClickHouseStore) GetDailyMessageActivity(ctx context.Context, chainName string, days int) ([]types.DailyMessageActivity, error) {
+	query := `
+		SELECT date, message_type, sum(messages), sum(failed_messages)
+		FROM daily_message_activity
+		WHERE chain_name = ? AND date >= today() - ?
+		GROUP BY date, message_type
+		ORDER BY date DESC, message_type
+	`
+
+	rows, err := s.conn.Query(ctx, query, chainName, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily message activity: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []types.DailyMessageActivity
+	for rows.Next() {
+		stat := types.DailyMessageActivity{ChainName: chainName}
+		if err := rows.Scan(&stat.Date, &stat.MessageType, &stat.Messages, &stat.FailedMessages); err != nil {
+			return nil, fmt.Errorf("failed to scan daily message activity: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
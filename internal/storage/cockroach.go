@@ -0,0 +1,309 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// serializationFailure is the SQLSTATE CockroachDB uses for retryable transaction aborts
+const serializationFailure = "40001"
+
+// CockroachStore runs the PostgreSQL store against CockroachDB, which speaks the
+// PostgreSQL wire protocol and accepts the same queries
+type CockroachStore struct {
+	*PostgresStore
+}
+
+// NewCockroachStore creates a new CockroachDB store
+func NewCockroachStore(dsn string, logger *zap.Logger) (*CockroachStore, error) {
+	pg, err := NewPostgresStore(dsn, logger)
+	if err != nil {
+		return nil, err
+	}
+	return newCockroachStore(pg), nil
+}
+
+// newCockroachStore runs a PostgreSQL store against CockroachDB
+func newCockroachStore(pg *PostgresStore) *CockroachStore {
+	pg.logger = zap.L().Named("cockroachdb")
+	return &CockroachStore{PostgresStore: pg}
+}
+
+// Ping tests the database connection and checks that the server is CockroachDB
+func (s *CockroachStore) Ping(ctx context.Context) error {
+	var version string
+	if err := s.db.QueryRowContext(ctx, "SELECT version()").Scan(&version); err != nil {
+		return err
+	}
+	if !strings.Contains(version, "CockroachDB") {
+		return fmt.Errorf("database.driver is cockroachdb but server reports %q", version)
+	}
+
+	return nil
+}
+
+// BeginTx starts a new transaction
+func (s *CockroachStore) BeginTx(ctx context.Context) (StateTx, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cockroachTx{
+		PostgresTx: &PostgresTx{tx: tx, logger: s.logger},
+		store:      s,
+		ctx:        ctx,
+	}, nil
+}
+
+// Serialization aborts are retried up to maxConflictRetries times, waiting
+// conflictBackoff times the attempt number, plus jitter, before each
+const (
+	maxConflictRetries = 5
+	conflictBackoff    = 20 * time.Millisecond
+)
+
+// cockroachTx is a CockroachDB transaction. CockroachDB runs every transaction
+// as SERIALIZABLE and aborts conflicting ones, at any statement or at commit
+// time, expecting the client to retry. The transaction records its writes as
+// the write-ahead log does, and replays them in a new transaction when it is
+// aborted. Statements against custom tables cannot be replayed, so a
+// transaction that ran any still fails with ErrConflict.
+type cockroachTx struct {
+	*PostgresTx
+	store *CockroachStore
+	ctx   context.Context // of BeginTx, for the retries at commit
+
+	writes    walTx // records the writes, without a log
+	custom    bool  // ran statements against custom tables
+	conflicts bool  // aborted by a serialization failure
+}
+
+// isSerializationFailure reports whether err is a retryable transaction abort
+func isSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == serializationFailure
+}
+
+// write records a write and reports the error of running it. Once the
+// transaction is aborted, statements fail until it is retried at commit, so
+// their errors are dropped.
+func (tx *cockroachTx) write(err error, record func() error) error {
+	if isSerializationFailure(err) {
+		tx.conflicts = true
+	}
+	switch {
+	case tx.conflicts && tx.custom:
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	case err != nil && !tx.conflicts:
+		return err
+	}
+	return record()
+}
+
+// Commit commits the transaction, retrying it when it is aborted by a
+// serialization failure, and reporting aborts it cannot retry as ErrConflict
+func (tx *cockroachTx) Commit() error {
+	var err error
+	if tx.conflicts {
+		tx.PostgresTx.Rollback()
+	} else if err = tx.PostgresTx.Commit(); !isSerializationFailure(err) {
+		return err
+	}
+	if tx.custom {
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+
+	entry := walEntry{Ops: tx.writes.ops}
+	for attempt := 1; attempt <= maxConflictRetries; attempt++ {
+		backoff := conflictBackoff * time.Duration(attempt)
+		select {
+		case <-tx.ctx.Done():
+			return tx.ctx.Err()
+		case <-time.After(backoff + rand.N(backoff)):
+		}
+
+		tx.store.logger.Debug("Retrying transaction aborted by a conflict", zap.Int("attempt", attempt))
+		if err = tx.retry(entry); !isSerializationFailure(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrConflict, err)
+}
+
+// retry replays the recorded writes in a new transaction and commits it
+func (tx *cockroachTx) retry(entry walEntry) error {
+	sqlTx, err := tx.store.db.BeginTx(tx.ctx, nil)
+	if err != nil {
+		return err
+	}
+	retry := &PostgresTx{tx: sqlTx, logger: tx.store.logger}
+	if err := entry.apply(tx.ctx, retry); err != nil {
+		retry.Rollback()
+		return err
+	}
+	return retry.Commit()
+}
+
+// UpsertChain upserts a chain
+func (tx *cockroachTx) UpsertChain(ctx context.Context, chain *types.ChainInfo) error {
+	return tx.write(tx.PostgresTx.UpsertChain(ctx, chain), func() error { return tx.writes.UpsertChain(ctx, chain) })
+}
+
+// UpsertChainStatus upserts a chain's status
+func (tx *cockroachTx) UpsertChainStatus(ctx context.Context, status *types.ChainStatus) error {
+	return tx.write(tx.PostgresTx.UpsertChainStatus(ctx, status), func() error { return tx.writes.UpsertChainStatus(ctx, status) })
+}
+
+// UpsertAccount upserts an account
+func (tx *cockroachTx) UpsertAccount(ctx context.Context, account *types.Account) error {
+	return tx.write(tx.PostgresTx.UpsertAccount(ctx, account), func() error { return tx.writes.UpsertAccount(ctx, account) })
+}
+
+// UpsertBalance upserts a balance
+func (tx *cockroachTx) UpsertBalance(ctx context.Context, balance *types.Balance) error {
+	return tx.write(tx.PostgresTx.UpsertBalance(ctx, balance), func() error { return tx.writes.UpsertBalance(ctx, balance) })
+}
+
+// UpsertBalances upserts a batch of balances
+func (tx *cockroachTx) UpsertBalances(ctx context.Context, balances []types.Balance) error {
+	return tx.write(tx.PostgresTx.UpsertBalances(ctx, balances), func() error { return tx.writes.UpsertBalances(ctx, balances) })
+}
+
+// UpsertDelegation upserts a delegation
+func (tx *cockroachTx) UpsertDelegation(ctx context.Context, delegation *types.Delegation) error {
+	return tx.write(tx.PostgresTx.UpsertDelegation(ctx, delegation), func() error { return tx.writes.UpsertDelegation(ctx, delegation) })
+}
+
+// DeleteDelegation removes a delegation
+func (tx *cockroachTx) DeleteDelegation(ctx context.Context, chainName, delegatorAddress, validatorAddress string) error {
+	return tx.write(tx.PostgresTx.DeleteDelegation(ctx, chainName, delegatorAddress, validatorAddress), func() error {
+		return tx.writes.DeleteDelegation(ctx, chainName, delegatorAddress, validatorAddress)
+	})
+}
+
+// UpsertTokenContracts upserts token contracts
+func (tx *cockroachTx) UpsertTokenContracts(ctx context.Context, contracts []types.TokenContract) error {
+	return tx.write(tx.PostgresTx.UpsertTokenContracts(ctx, contracts), func() error { return tx.writes.UpsertTokenContracts(ctx, contracts) })
+}
+
+// UpsertTokenHoldings upserts token holdings
+func (tx *cockroachTx) UpsertTokenHoldings(ctx context.Context, holdings []types.TokenHolding) error {
+	return tx.write(tx.PostgresTx.UpsertTokenHoldings(ctx, holdings), func() error { return tx.writes.UpsertTokenHoldings(ctx, holdings) })
+}
+
+// ReplaceUnbondingDelegations replaces a chain's unbonding queue
+func (tx *cockroachTx) ReplaceUnbondingDelegations(ctx context.Context, chainName string, unbondings []types.UnbondingDelegation) error {
+	return tx.write(tx.PostgresTx.ReplaceUnbondingDelegations(ctx, chainName, unbondings), func() error {
+		return tx.writes.ReplaceUnbondingDelegations(ctx, chainName, unbondings)
+	})
+}
+
+// ReplaceRedelegations replaces a chain's pending redelegations
+func (tx *cockroachTx) ReplaceRedelegations(ctx context.Context, chainName string, redelegations []types.Redelegation) error {
+	return tx.write(tx.PostgresTx.ReplaceRedelegations(ctx, chainName, redelegations), func() error {
+		return tx.writes.ReplaceRedelegations(ctx, chainName, redelegations)
+	})
+}
+
+// UpsertValidator upserts a validator
+func (tx *cockroachTx) UpsertValidator(ctx context.Context, validator *types.Validator) error {
+	return tx.write(tx.PostgresTx.UpsertValidator(ctx, validator), func() error { return tx.writes.UpsertValidator(ctx, validator) })
+}
+
+// UpsertConsensusAddress upserts a validator consensus address
+func (tx *cockroachTx) UpsertConsensusAddress(ctx context.Context, chainName, consensusAddress, operatorAddress string, height int64) error {
+	return tx.write(tx.PostgresTx.UpsertConsensusAddress(ctx, chainName, consensusAddress, operatorAddress, height), func() error {
+		return tx.writes.UpsertConsensusAddress(ctx, chainName, consensusAddress, operatorAddress, height)
+	})
+}
+
+// InsertCommissionChange inserts a validator commission change
+func (tx *cockroachTx) InsertCommissionChange(ctx context.Context, change *types.CommissionChange) error {
+	return tx.write(tx.PostgresTx.InsertCommissionChange(ctx, change), func() error { return tx.writes.InsertCommissionChange(ctx, change) })
+}
+
+// ReplaceConsumerChains replaces a provider chain's consumer chains
+func (tx *cockroachTx) ReplaceConsumerChains(ctx context.Context, providerChain string, consumers []types.ConsumerChain) error {
+	return tx.write(tx.PostgresTx.ReplaceConsumerChains(ctx, providerChain, consumers), func() error {
+		return tx.writes.ReplaceConsumerChains(ctx, providerChain, consumers)
+	})
+}
+
+// UpsertSupply upserts a chain's supply
+func (tx *cockroachTx) UpsertSupply(ctx context.Context, supply []types.Supply) error {
+	return tx.write(tx.PostgresTx.UpsertSupply(ctx, supply), func() error { return tx.writes.UpsertSupply(ctx, supply) })
+}
+
+// ReplaceCommunityPool replaces a chain's community pool
+func (tx *cockroachTx) ReplaceCommunityPool(ctx context.Context, chainName string, pool []types.PoolBalance) error {
+	return tx.write(tx.PostgresTx.ReplaceCommunityPool(ctx, chainName, pool), func() error { return tx.writes.ReplaceCommunityPool(ctx, chainName, pool) })
+}
+
+// ReplaceModuleAccounts replaces a chain's module accounts
+func (tx *cockroachTx) ReplaceModuleAccounts(ctx context.Context, chainName string, accounts []types.ModuleAccount) error {
+	return tx.write(tx.PostgresTx.ReplaceModuleAccounts(ctx, chainName, accounts), func() error {
+		return tx.writes.ReplaceModuleAccounts(ctx, chainName, accounts)
+	})
+}
+
+// ReplaceVestingLocked replaces a chain's locked vesting amounts
+func (tx *cockroachTx) ReplaceVestingLocked(ctx context.Context, chainName string, locked []types.VestingLocked) error {
+	return tx.write(tx.PostgresTx.ReplaceVestingLocked(ctx, chainName, locked), func() error {
+		return tx.writes.ReplaceVestingLocked(ctx, chainName, locked)
+	})
+}
+
+// UpsertMintParams upserts a chain's mint params
+func (tx *cockroachTx) UpsertMintParams(ctx context.Context, params *types.MintParams) error {
+	return tx.write(tx.PostgresTx.UpsertMintParams(ctx, params), func() error { return tx.writes.UpsertMintParams(ctx, params) })
+}
+
+// UpsertProposals upserts governance proposals
+func (tx *cockroachTx) UpsertProposals(ctx context.Context, proposals []types.Proposal) error {
+	return tx.write(tx.PostgresTx.UpsertProposals(ctx, proposals), func() error { return tx.writes.UpsertProposals(ctx, proposals) })
+}
+
+// ReplaceUpgradePlan replaces a chain's upgrade plan
+func (tx *cockroachTx) ReplaceUpgradePlan(ctx context.Context, chainName string, plan *types.UpgradePlan) error {
+	return tx.write(tx.PostgresTx.ReplaceUpgradePlan(ctx, chainName, plan), func() error { return tx.writes.ReplaceUpgradePlan(ctx, chainName, plan) })
+}
+
+// UpsertChainParams upserts a module's params
+func (tx *cockroachTx) UpsertChainParams(ctx context.Context, params *types.ChainParams) error {
+	return tx.write(tx.PostgresTx.UpsertChainParams(ctx, params), func() error { return tx.writes.UpsertChainParams(ctx, params) })
+}
+
+// InsertBlocks inserts block headers
+func (tx *cockroachTx) InsertBlocks(ctx context.Context, blocks []types.Block) error {
+	return tx.write(tx.PostgresTx.InsertBlocks(ctx, blocks), func() error { return tx.writes.InsertBlocks(ctx, blocks) })
+}
+
+// EnqueueOutbox writes outbox messages
+func (tx *cockroachTx) EnqueueOutbox(ctx context.Context, messages []types.OutboxMessage) error {
+	return tx.write(tx.PostgresTx.EnqueueOutbox(ctx, messages), func() error { return tx.writes.EnqueueOutbox(ctx, messages) })
+}
+
+// Exec runs a statement against the tables of custom modules. It cannot be
+// replayed, so it leaves the transaction to fail on a conflict.
+func (tx *cockroachTx) Exec(ctx context.Context, query string, args ...any) error {
+	tx.custom = true
+	if tx.conflicts {
+		return fmt.Errorf("%w: transaction aborted before a custom module write", ErrConflict)
+	}
+	err := tx.PostgresTx.Exec(ctx, query, args...)
+	if isSerializationFailure(err) {
+		tx.conflicts = true
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/lib/pq"
+)
+
+// CommissionStore reads the validator commission changes detected by the
+// staking module and tracks which increases tenants were notified of
+type CommissionStore interface {
+	GetCommissionChanges(ctx context.Context, chainName, operatorAddress string, limit int) ([]types.CommissionChange, error)
+	GetPendingCommissionIncreases(ctx context.Context, since time.Time) ([]types.CommissionChange, error)
+	ClaimCommissionChange(ctx context.Context, change types.CommissionChange, previous, notifiedAt *time.Time) (bool, error)
+	GetDelegatorsOf(ctx context.Context, chainName, validatorAddress string, delegators []string) ([]string, error)
+}
+
+var (
+	_ CommissionStore = (*PostgresStore)(nil)
+	_ CommissionStore = (*CockroachStore)(nil)
+	_ CommissionStore = (*MemoryStore)(nil)
+)
+
+// InsertCommissionChange records a detected commission rate change. A change
+// recorded again at the same height is ignored.
+func (tx *PostgresTx) InsertCommissionChange(ctx context.Context, change *types.CommissionChange) error {
+	_, err := tx.tx.ExecContext(ctx, `
+		INSERT INTO validator_commission_changes (
+			chain_name, operator_address, moniker, old_rate, new_rate, effective_time, height, detected_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (chain_name, operator_address, height) DO NOTHING
+	`, change.ChainName, change.OperatorAddress, change.Moniker, change.OldRate, change.NewRate,
+		change.EffectiveTime, change.Height, change.DetectedAt)
+	return err
+}
+
+// GetCommissionChanges returns the latest commission changes of a validator,
+// newest first
+func (s *PostgresStore) GetCommissionChanges(ctx context.Context, chainName, operatorAddress string, limit int) ([]types.CommissionChange, error) {
+	return s.queryCommissionChanges(ctx, `
+		WHERE chain_name = $1 AND operator_address = $2
+		ORDER BY height DESC
+		LIMIT $3
+	`, chainName, operatorAddress, limit)
+}
+
+// GetPendingCommissionIncreases returns the commission increases detected
+// since the given time that tenants were not notified of, oldest first
+func (s *PostgresStore) GetPendingCommissionIncreases(ctx context.Context, since time.Time) ([]types.CommissionChange, error) {
+	return s.queryCommissionChanges(ctx, `
+		WHERE notified_at IS NULL AND new_rate > old_rate AND detected_at >= $1
+		ORDER BY detected_at, chain_name, operator_address
+	`, since)
+}
+
+func (s *PostgresStore) queryCommissionChanges(ctx context.Context, where string, args ...interface{}) ([]types.CommissionChange, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain_name, operator_address, COALESCE(moniker, ''), old_rate::TEXT, new_rate::TEXT,
+		       effective_time, height, detected_at
+		FROM validator_commission_changes
+	`+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commission changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []types.CommissionChange{}
+	for rows.Next() {
+		var change types.CommissionChange
+		if err := rows.Scan(&change.ChainName, &change.OperatorAddress, &change.Moniker, &change.OldRate, &change.NewRate,
+			&change.EffectiveTime, &change.Height, &change.DetectedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan commission change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// ClaimCommissionChange sets the time tenants were notified of a change to
+// notifiedAt if it is still previous, and reports whether it was. Servers
+// claim a change before notifying tenants of it, so that only one notifies
+// them, and put the previous time back when no notification was delivered.
+func (s *PostgresStore) ClaimCommissionChange(ctx context.Context, change types.CommissionChange, previous, notifiedAt *time.Time) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE validator_commission_changes SET notified_at = $5::timestamptz
+		WHERE chain_name = $1 AND operator_address = $2 AND height = $3
+		  AND notified_at IS NOT DISTINCT FROM $4::timestamptz
+	`, change.ChainName, change.OperatorAddress, change.Height, previous, notifiedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim commission change: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim commission change: %w", err)
+	}
+	return n == 1, nil
+}
+
+// GetDelegatorsOf returns which of the given delegators delegate to a
+// validator
+func (s *PostgresStore) GetDelegatorsOf(ctx context.Context, chainName, validatorAddress string, delegators []string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT delegator_address
+		FROM delegations
+		WHERE chain_name = $1 AND validator_address = $2 AND delegator_address = ANY($3)
+		ORDER BY delegator_address
+	`, chainName, validatorAddress, pq.Array(delegators))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query validator delegators: %w", err)
+	}
+	defer rows.Close()
+
+	var matched []string
+	for rows.Next() {
+		var delegator string
+		if err := rows.Scan(&delegator); err != nil {
+			return nil, fmt.Errorf("failed to scan validator delegator: %w", err)
+		}
+		matched = append(matched, delegator)
+	}
+	return matched, rows.Err()
+}
+
+// Commissions returns the store of validator commission changes
+func (m *Manager) Commissions() CommissionStore {
+	return m.state.(CommissionStore)
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// GetConsumerChain returns the consumer chain with the given chain ID and its
+// validator set. A chain moved between providers is returned from the one
+// that last reported it.
+func (s *PostgresStore) GetConsumerChain(ctx context.Context, chainID string) (*types.ConsumerChain, error) {
+	query := `
+		SELECT provider_chain, chain_id, consumer_id, client_id, height, updated_at
+		FROM consumer_chains
+		WHERE chain_id = $1
+		ORDER BY updated_at DESC
+		LIMIT 1
+	`
+
+	var consumer types.ConsumerChain
+	err := s.db.QueryRowContext(ctx, query, chainID).Scan(
+		&consumer.ProviderChain,
+		&consumer.ChainID,
+		&consumer.ConsumerID,
+		&consumer.ClientID,
+		&consumer.Height,
+		&consumer.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("consumer chain %s: %w", chainID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumer chain: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT provider_address, consumer_address
+		FROM consumer_validators
+		WHERE provider_chain = $1 AND chain_id = $2
+		ORDER BY provider_address
+	`, consumer.ProviderChain, consumer.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consumer validators: %w", err)
+	}
+	defer rows.Close()
+
+	consumer.Validators = []types.ConsumerValidator{}
+	for rows.Next() {
+		var validator types.ConsumerValidator
+		if err := rows.Scan(&validator.ProviderAddress, &validator.ConsumerAddress); err != nil {
+			return nil, fmt.Errorf("failed to scan consumer validator: %w", err)
+		}
+		consumer.Validators = append(consumer.Validators, validator)
+	}
+
+	return &consumer, rows.Err()
+}
+
+// ReplaceConsumerChains replaces the consumer chains of a provider chain and
+// their validator sets. Stopped consumers disappear from the provider, so a
+// full snapshot is kept rather than upserting.
+func (tx *PostgresTx) ReplaceConsumerChains(ctx context.Context, providerChain string, consumers []types.ConsumerChain) error {
+	if _, err := tx.tx.ExecContext(ctx, `DELETE FROM consumer_chains WHERE provider_chain = $1`, providerChain); err != nil {
+		return fmt.Errorf("failed to delete consumer chains: %w", err)
+	}
+
+	for _, consumer := range consumers {
+		_, err := tx.tx.ExecContext(ctx, `
+			INSERT INTO consumer_chains (provider_chain, chain_id, consumer_id, client_id, height, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`,
+			providerChain,
+			consumer.ChainID,
+			consumer.ConsumerID,
+			consumer.ClientID,
+			consumer.Height,
+			consumer.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert consumer chain %s: %w", consumer.ChainID, err)
+		}
+
+		for _, validator := range consumer.Validators {
+			_, err := tx.tx.ExecContext(ctx, `
+				INSERT INTO consumer_validators (provider_chain, chain_id, provider_address, consumer_address)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (provider_chain, chain_id, provider_address) DO NOTHING
+			`, providerChain, consumer.ChainID, validator.ProviderAddress, validator.ConsumerAddress)
+			if err != nil {
+				return fmt.Errorf("failed to insert consumer validator: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetConsumerValidatorSet returns the validator set of a consumer chain joined
+// to the provider validators backing it through their consensus addresses,
+// with the bonded tokens of those validators as the stake at risk
+func (m *Manager) GetConsumerValidatorSet(ctx context.Context, chainID string) (*types.ConsumerValidatorSet, error) {
+	consumer, err := m.state.GetConsumerChain(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	validators, err := m.state.GetValidators(ctx, consumer.ProviderChain)
+	if err != nil {
+		return nil, err
+	}
+	byConsensus := make(map[string]types.Validator, len(validators))
+	for _, validator := range validators {
+		byConsensus[validator.ConsensusAddress] = validator
+	}
+
+	set := &types.ConsumerValidatorSet{
+		ProviderChain: consumer.ProviderChain,
+		ChainID:       consumer.ChainID,
+		ConsumerID:    consumer.ConsumerID,
+		Validators:    make([]types.BackingValidator, 0, len(consumer.Validators)),
+		Height:        consumer.Height,
+		UpdatedAt:     consumer.UpdatedAt,
+	}
+
+	stake := new(big.Int)
+	for _, cv := range consumer.Validators {
+		backing := types.BackingValidator{ConsumerValidator: cv}
+		if validator, ok := byConsensus[cv.ProviderAddress]; ok {
+			backing.Validator = &validator
+			if tokens, ok := new(big.Int).SetString(validator.Tokens, 10); ok && validator.Status == "BOND_STATUS_BONDED" {
+				stake.Add(stake, tokens)
+			}
+		}
+		set.Validators = append(set.Validators, backing)
+	}
+	set.StakeAtRisk = stake.String()
+
+	return set, nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/lib/pq"
+)
+
+// DeadlineStore reads upcoming proposal deadlines and unbonding completions,
+// which notification digests, unbonding reminders and the pending unbondings
+// endpoint report
+type DeadlineStore interface {
+	GetProposalDeadlines(ctx context.Context, chains []string, from, until time.Time) ([]types.ProposalDeadline, error)
+	GetUnbondingCompletions(ctx context.Context, chainName string, delegators []string, from, until time.Time) ([]types.UnbondingCompletion, error)
+}
+
+var (
+	_ DeadlineStore = (*PostgresStore)(nil)
+	_ DeadlineStore = (*CockroachStore)(nil)
+	_ DeadlineStore = (*MemoryStore)(nil)
+)
+
+// GetProposalDeadlines returns the proposals of the given chains whose voting
+// period ends between from and until, soonest first
+func (s *PostgresStore) GetProposalDeadlines(ctx context.Context, chains []string, from, until time.Time) ([]types.ProposalDeadline, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain_name, proposal_id, COALESCE(content->>'title', ''), status, voting_end_time
+		FROM proposals
+		WHERE chain_name = ANY($1) AND voting_end_time >= $2 AND voting_end_time < $3
+		ORDER BY voting_end_time, chain_name, proposal_id
+	`, pq.Array(chains), from, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query proposal deadlines: %w", err)
+	}
+	defer rows.Close()
+
+	deadlines := []types.ProposalDeadline{}
+	for rows.Next() {
+		var deadline types.ProposalDeadline
+		if err := rows.Scan(&deadline.ChainName, &deadline.ProposalID, &deadline.Title, &deadline.Status, &deadline.VotingEndTime); err != nil {
+			return nil, fmt.Errorf("failed to scan proposal deadline: %w", err)
+		}
+		deadlines = append(deadlines, deadline)
+	}
+	return deadlines, rows.Err()
+}
+
+// GetUnbondingCompletions returns the unbonding entries of the given
+// delegators on a chain that complete between from and until, soonest first.
+// A zero until leaves the window open.
+func (s *PostgresStore) GetUnbondingCompletions(ctx context.Context, chainName string, delegators []string, from, until time.Time) ([]types.UnbondingCompletion, error) {
+	query := `
+		SELECT delegator_address, validator_address, creation_height, balance::TEXT, completion_time
+		FROM unbonding_delegations
+		WHERE chain_name = $1 AND delegator_address = ANY($2) AND completion_time >= $3`
+	args := []interface{}{chainName, pq.Array(delegators), from}
+	if !until.IsZero() {
+		query += ` AND completion_time < $4`
+		args = append(args, until)
+	}
+	query += ` ORDER BY completion_time, delegator_address, validator_address, creation_height`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unbonding completions: %w", err)
+	}
+	defer rows.Close()
+
+	completions := []types.UnbondingCompletion{}
+	for rows.Next() {
+		completion := types.UnbondingCompletion{ChainName: chainName}
+		if err := rows.Scan(&completion.DelegatorAddress, &completion.ValidatorAddress, &completion.CreationHeight,
+			&completion.Balance, &completion.CompletionTime); err != nil {
+			return nil, fmt.Errorf("failed to scan unbonding completion: %w", err)
+		}
+		completions = append(completions, completion)
+	}
+	return completions, rows.Err()
+}
+
+// GetAccountUnbondings returns the pending unbonding entries of the given
+// accounts across chains, soonest first
+func (m *Manager) GetAccountUnbondings(ctx context.Context, refs []AccountRef) ([]types.UnbondingCompletion, error) {
+	now := time.Now()
+	unbondings, err := fanOut(ctx, refs, func(ctx context.Context, ref AccountRef) ([]types.UnbondingCompletion, error) {
+		return m.Deadlines().GetUnbondingCompletions(ctx, ref.ChainName, []string{ref.Address}, now, time.Time{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(unbondings, func(i, j int) bool {
+		return unbondings[i].CompletionTime.Before(unbondings[j].CompletionTime)
+	})
+	return unbondings, nil
+}
+
+// Deadlines returns the store of upcoming deadlines
+func (m *Manager) Deadlines() DeadlineStore {
+	return m.state.(DeadlineStore)
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// dryRunStore keeps the writes of a dry run in memory, so that modules reading
+// state written earlier in the run see it, and accepts the schemas and
+// statements of custom modules without running them
+type dryRunStore struct {
+	*MemoryStore
+}
+
+var (
+	_ StateStore  = dryRunStore{}
+	_ SchemaStore = dryRunStore{}
+)
+
+// BeginTx starts a transaction against the in-memory state
+func (s dryRunStore) BeginTx(ctx context.Context) (StateTx, error) {
+	tx, err := s.MemoryStore.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dryRunTx{StateTx: tx}, nil
+}
+
+// ApplySchema accepts the tables of custom modules without creating them
+func (s dryRunStore) ApplySchema(ctx context.Context, statements []string) error {
+	return nil
+}
+
+// dryRunTx is a transaction of a dry run
+type dryRunTx struct {
+	StateTx
+}
+
+// Exec accepts a custom module statement without running it
+func (tx dryRunTx) Exec(ctx context.Context, query string, args ...any) error {
+	return nil
+}
+
+// NewDryRunManager creates a storage manager that never touches a database,
+// for validating chain configurations and modules. Writes are kept in memory
+// for the life of the manager; there is no analytics storage.
+func NewDryRunManager() *Manager {
+	return &Manager{
+		driver: "dry-run",
+		state:  dryRunStore{MemoryStore: NewMemoryStore()},
+		logger: zap.L().Named("storage"),
+	}
+}
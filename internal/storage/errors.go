@@ -0,0 +1,17 @@
+package storage
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when a requested entity does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrUnavailable is returned when a query needs a storage backend that is not configured
+	ErrUnavailable = errors.New("storage backend unavailable")
+
+	// ErrConflict is returned when a transaction was aborted by a concurrent write and may be retried
+	ErrConflict = errors.New("transaction conflict")
+
+	// ErrExists is returned when creating an entity whose unique name is already taken
+	ErrExists = errors.New("already exists")
+)
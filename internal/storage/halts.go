@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// HaltStore records the periods in which the ingester saw a chain stop
+// producing blocks
+type HaltStore interface {
+	RecordHalt(ctx context.Context, halt types.ChainHalt) error
+	GetHalts(ctx context.Context, chainName string, since time.Time) ([]types.ChainHalt, error)
+}
+
+var (
+	_ HaltStore = (*PostgresStore)(nil)
+	_ HaltStore = (*CockroachStore)(nil)
+	_ HaltStore = (*MemoryStore)(nil)
+)
+
+// RecordHalt records a halt when it is detected and again when it ends. A halt
+// is keyed by the chain and its last height, so ingesters restarting or
+// running side by side record it once, with the earliest start and detection.
+func (s *PostgresStore) RecordHalt(ctx context.Context, halt types.ChainHalt) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO chain_halts (chain_name, height, since, detected_at, resumed_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (chain_name, height)
+		DO UPDATE SET
+			since = LEAST(chain_halts.since, EXCLUDED.since),
+			detected_at = LEAST(chain_halts.detected_at, EXCLUDED.detected_at),
+			resumed_at = COALESCE(EXCLUDED.resumed_at, chain_halts.resumed_at)
+	`, halt.ChainName, halt.Height, halt.Since, halt.DetectedAt, halt.ResumedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record chain halt: %w", err)
+	}
+	return nil
+}
+
+// GetHalts returns the halts of a chain ongoing at or since a time, newest
+// first
+func (s *PostgresStore) GetHalts(ctx context.Context, chainName string, since time.Time) ([]types.ChainHalt, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain_name, height, since, detected_at, resumed_at
+		FROM chain_halts
+		WHERE chain_name = $1 AND (resumed_at IS NULL OR resumed_at >= $2)
+		ORDER BY since DESC
+	`, chainName, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chain halts: %w", err)
+	}
+	defer rows.Close()
+
+	halts := []types.ChainHalt{}
+	for rows.Next() {
+		var halt types.ChainHalt
+		if err := rows.Scan(&halt.ChainName, &halt.Height, &halt.Since, &halt.DetectedAt, &halt.ResumedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chain halt: %w", err)
+		}
+		halts = append(halts, halt)
+	}
+	return halts, rows.Err()
+}
+
+// Halts returns the store of chain halts
+func (m *Manager) Halts() HaltStore {
+	return m.state.(HaltStore)
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+func TestMemoryHalts(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	resumed := at(30)
+
+	halts := []types.ChainHalt{
+		{ChainName: "cosmoshub", Height: 100, Since: at(0), DetectedAt: at(6)},
+		{ChainName: "cosmoshub", Height: 100, Since: at(1), DetectedAt: at(7)}, // another ingester
+		{ChainName: "cosmoshub", Height: 100, Since: at(0), DetectedAt: at(6), ResumedAt: &resumed},
+		{ChainName: "cosmoshub", Height: 200, Since: at(60), DetectedAt: at(66)},
+		{ChainName: "osmosis", Height: 50, Since: at(0), DetectedAt: at(6)},
+	}
+	for _, halt := range halts {
+		if err := store.RecordHalt(ctx, halt); err != nil {
+			t.Fatalf("RecordHalt() error = %v", err)
+		}
+	}
+
+	got, err := store.GetHalts(ctx, "cosmoshub", start)
+	if err != nil {
+		t.Fatalf("GetHalts() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Height != 200 || got[1].Height != 100 {
+		t.Fatalf("GetHalts() = %+v, want halts at heights 200 and 100", got)
+	}
+	if first := got[1]; !first.Since.Equal(at(0)) || !first.DetectedAt.Equal(at(6)) || first.ResumedAt == nil || !first.ResumedAt.Equal(resumed) {
+		t.Errorf("GetHalts() halt at 100 = %+v, want since %v, detected %v and resumed %v", first, at(0), at(6), resumed)
+	}
+	if got[0].ResumedAt != nil {
+		t.Errorf("GetHalts() halt at 200 resumed at %v, want ongoing", got[0].ResumedAt)
+	}
+
+	// Halts that ended before the time are left out, ongoing ones are not
+	got, err = store.GetHalts(ctx, "cosmoshub", at(45))
+	if err != nil {
+		t.Fatalf("GetHalts() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Height != 200 {
+		t.Errorf("GetHalts() since %v = %+v, want the ongoing halt only", at(45), got)
+	}
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// ExpectedIndex is an index or projection the API's hot queries rely on
+type ExpectedIndex struct {
+	Table      string
+	Name       string
+	Projection bool   // a ClickHouse projection rather than an index
+	Purpose    string // the queries it serves
+	Migration  string // that creates it
+}
+
+// ExpectedPostgresIndexes are the state store indexes checked at startup
+var ExpectedPostgresIndexes = []ExpectedIndex{
+	{Table: "balances", Name: "idx_balances_address_chain_denom", Purpose: "account balances by address across chains", Migration: "postgres/019_hot_query_indexes.sql"},
+	{Table: "delegations", Name: "idx_delegations_chain_delegator", Purpose: "account delegations", Migration: "postgres/001_initial_schema.sql"},
+	{Table: "delegations", Name: "idx_delegations_chain_validator_shares_delegator", Purpose: "validator delegator listings", Migration: "postgres/019_hot_query_indexes.sql"},
+	{Table: "balance_history", Name: "idx_balance_history_chain_address", Purpose: "balances at a height", Migration: "postgres/002_state_history.sql"},
+	{Table: "blocks", Name: "idx_blocks_chain_time", Purpose: "blocks and validator sets by time", Migration: "postgres/007_blocks.sql"},
+}
+
+// ExpectedClickHouseIndexes are the analytics indexes and projections checked
+// at startup
+var ExpectedClickHouseIndexes = []ExpectedIndex{
+	{Table: "balance_events", Name: "idx_address", Purpose: "balance events by address across chains", Migration: "clickhouse/001_initial_schema.sql"},
+	{Table: "balance_events", Name: "by_address_time", Projection: true, Purpose: "balance events by address and time", Migration: "clickhouse/015_event_address_projections.sql"},
+	{Table: "delegation_events", Name: "by_delegator_time", Projection: true, Purpose: "delegation events by delegator and time", Migration: "clickhouse/015_event_address_projections.sql"},
+	{Table: "transactions", Name: "idx_fee_payer", Purpose: "transactions in account activity feeds", Migration: "clickhouse/016_transaction_fee_payer_index.sql"},
+}
+
+// IndexStore reports which of the expected indexes are missing
+type IndexStore interface {
+	MissingIndexes(ctx context.Context, expected []ExpectedIndex) ([]ExpectedIndex, error)
+}
+
+var (
+	_ IndexStore = (*PostgresStore)(nil)
+	_ IndexStore = (*ClickHouseStore)(nil)
+)
+
+// MissingIndexes returns the expected indexes that do not exist or are not
+// valid, e.g. after a failed concurrent build
+func (s *PostgresStore) MissingIndexes(ctx context.Context, expected []ExpectedIndex) ([]ExpectedIndex, error) {
+	names := make([]string, len(expected))
+	for i, index := range expected {
+		names[i] = index.Name
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.relname
+		FROM pg_class c
+		JOIN pg_index i ON i.indexrelid = c.oid
+		WHERE c.relname = ANY($1) AND i.indisvalid AND pg_table_is_visible(c.oid)
+	`, pq.Array(names))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+	defer rows.Close()
+
+	present := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan index: %w", err)
+		}
+		present[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var missing []ExpectedIndex
+	for _, index := range expected {
+		if !present[index.Name] {
+			missing = append(missing, index)
+		}
+	}
+	return missing, nil
+}
+
+// MissingIndexes returns the expected data skipping indexes and projections
+// that do not exist
+func (s *ClickHouseStore) MissingIndexes(ctx context.Context, expected []ExpectedIndex) ([]ExpectedIndex, error) {
+	var missing []ExpectedIndex
+	for _, index := range expected {
+		var found uint64
+		var err error
+		if index.Projection {
+			// Projections are only listed in the table definition
+			err = s.conn.QueryRow(ctx, `
+				SELECT count() FROM system.tables
+				WHERE database = currentDatabase() AND name = ? AND position(create_table_query, ?) > 0
+			`, index.Table, "PROJECTION "+index.Name).Scan(&found)
+		} else {
+			err = s.conn.QueryRow(ctx, `
+				SELECT count() FROM system.data_skipping_indices
+				WHERE database = currentDatabase() AND table = ? AND name = ?
+			`, index.Table, index.Name).Scan(&found)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s.%s: %w", index.Table, index.Name, err)
+		}
+		if found == 0 {
+			missing = append(missing, index)
+		}
+	}
+	return missing, nil
+}
+
+// CheckIndexes returns the expected indexes missing from the state store and
+// ClickHouse. Stores without indexes report none.
+func (m *Manager) CheckIndexes(ctx context.Context) ([]ExpectedIndex, error) {
+	var missing []ExpectedIndex
+	var errs []error
+	if store, ok := m.state.(IndexStore); ok {
+		indexes, err := store.MissingIndexes(ctx, ExpectedPostgresIndexes)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		missing = append(missing, indexes...)
+	}
+	if m.clickhouse != nil && m.clickhouse.Available() {
+		indexes, err := m.clickhouse.MissingIndexes(ctx, ExpectedClickHouseIndexes)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		missing = append(missing, indexes...)
+	}
+
+	return missing, errors.Join(errs...)
+}
@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// IngestRunStore holds the summaries of ingest cycles
+type IngestRunStore interface {
+	InsertIngestRun(ctx context.Context, run *types.IngestRun) error
+	GetIngestRuns(ctx context.Context, filter types.IngestRunFilter) ([]types.IngestRun, error)
+	PruneIngestRuns(ctx context.Context, before time.Time, batchSize int) (int64, error)
+}
+
+var (
+	_ IngestRunStore = (*PostgresStore)(nil)
+	_ IngestRunStore = (*CockroachStore)(nil)
+	_ IngestRunStore = (*MemoryStore)(nil)
+)
+
+// InsertIngestRun stores the summary of an ingest cycle, assigning its ID
+func (s *PostgresStore) InsertIngestRun(ctx context.Context, run *types.IngestRun) error {
+	if run.Modules == nil {
+		run.Modules = []types.ModuleRun{}
+	}
+	modules, err := json.Marshal(run.Modules)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ingest run modules: %w", err)
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO ingest_runs (
+			chain_name, height, started_at, duration_ms, rows_written, grpc_calls,
+			errors, error, modules
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, run.ChainName, run.Height, run.StartedAt, run.DurationMs, run.RowsWritten,
+		run.GRPCCalls, run.Errors, run.Error, modules).Scan(&run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert ingest run: %w", err)
+	}
+	return nil
+}
+
+// GetIngestRuns returns the ingest runs matching a filter, newest first
+func (s *PostgresStore) GetIngestRuns(ctx context.Context, filter types.IngestRunFilter) ([]types.IngestRun, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, chain_name, height, started_at, duration_ms, rows_written,
+		       grpc_calls, errors, error, modules
+		FROM ingest_runs
+		WHERE ($1 = '' OR chain_name = $1)
+		  AND (NOT $2 OR errors > 0)
+		  AND started_at >= $3
+		ORDER BY started_at DESC, id DESC
+		LIMIT NULLIF($4, 0)
+	`, filter.ChainName, filter.Failed, filter.Since, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ingest runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []types.IngestRun{}
+	for rows.Next() {
+		var run types.IngestRun
+		var modules []byte
+		err := rows.Scan(
+			&run.ID,
+			&run.ChainName,
+			&run.Height,
+			&run.StartedAt,
+			&run.DurationMs,
+			&run.RowsWritten,
+			&run.GRPCCalls,
+			&run.Errors,
+			&run.Error,
+			&modules,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ingest run: %w", err)
+		}
+		if err := json.Unmarshal(modules, &run.Modules); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal ingest run modules: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// PruneIngestRuns deletes the ingest runs started before the cutoff
+func (s *PostgresStore) PruneIngestRuns(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return s.deleteInBatches(ctx, `
+		DELETE FROM ingest_runs
+		WHERE id IN (
+			SELECT id FROM ingest_runs
+			WHERE started_at < $1
+			LIMIT $2
+		)
+	`, batchSize, before)
+}
+
+// IngestRuns returns the store holding ingest run summaries
+func (m *Manager) IngestRuns() IngestRunStore {
+	return m.state.(IngestRunStore)
+}
+
+// RowCounter counts the rows committed by the transactions begun with a
+// context carrying it, by table. Custom module statements count under
+// "custom".
+type RowCounter struct {
+	mu     sync.Mutex
+	rows   int64
+	tables map[string]int64
+}
+
+// Rows returns the number of rows committed so far
+func (c *RowCounter) Rows() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rows
+}
+
+// Tables returns the number of rows committed so far by table, or nil when
+// none were
+func (c *RowCounter) Tables() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.tables)
+}
+
+// add adds the rows committed by a transaction
+func (c *RowCounter) add(tables map[string]int64) {
+	if len(tables) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tables == nil {
+		c.tables = make(map[string]int64, len(tables))
+	}
+	for table, rows := range tables {
+		c.tables[table] += rows
+		c.rows += rows
+	}
+}
+
+type rowCounterKey struct{}
+
+// WithRowCounter returns a context whose transactions add the rows they commit
+// to counter
+func WithRowCounter(ctx context.Context, counter *RowCounter) context.Context {
+	return context.WithValue(ctx, rowCounterKey{}, counter)
+}
+
+// rowCounterFrom returns the row counter carried by a context, or nil
+func rowCounterFrom(ctx context.Context) *RowCounter {
+	counter, _ := ctx.Value(rowCounterKey{}).(*RowCounter)
+	return counter
+}
+
+// countingTx counts the rows written through a transaction, adding them to
+// its counter on commit. Replaced sets count their new rows, statements of
+// custom modules one row each.
+type countingTx struct {
+	StateTx
+	counter *RowCounter
+	tables  map[string]int64 // rows pending commit, by table
+}
+
+// Commit commits the transaction and adds its rows to the counter
+func (tx *countingTx) Commit() error {
+	if err := tx.StateTx.Commit(); err != nil {
+		return err
+	}
+	tx.counter.add(tx.tables)
+	tx.tables = nil
+	return nil
+}
+
+// UpsertChain writes through, counting the rows written
+func (tx *countingTx) UpsertChain(ctx context.Context, chain *types.ChainInfo) error {
+	return tx.count("chains", 1, tx.StateTx.UpsertChain(ctx, chain))
+}
+
+// UpsertChainStatus writes through, counting the rows written
+func (tx *countingTx) UpsertChainStatus(ctx context.Context, status *types.ChainStatus) error {
+	return tx.count("chain_status", 1, tx.StateTx.UpsertChainStatus(ctx, status))
+}
+
+// UpsertAccount writes through, counting the rows written
+func (tx *countingTx) UpsertAccount(ctx context.Context, account *types.Account) error {
+	return tx.count("accounts", 1, tx.StateTx.UpsertAccount(ctx, account))
+}
+
+// UpsertBalance writes through, counting the rows written
+func (tx *countingTx) UpsertBalance(ctx context.Context, balance *types.Balance) error {
+	return tx.count("balances", 1, tx.StateTx.UpsertBalance(ctx, balance))
+}
+
+// UpsertBalances writes through, counting the rows written
+func (tx *countingTx) UpsertBalances(ctx context.Context, balances []types.Balance) error {
+	return tx.count("balances", len(balances), tx.StateTx.UpsertBalances(ctx, balances))
+}
+
+// UpsertDelegation writes through, counting the rows written
+func (tx *countingTx) UpsertDelegation(ctx context.Context, delegation *types.Delegation) error {
+	return tx.count("delegations", 1, tx.StateTx.UpsertDelegation(ctx, delegation))
+}
+
+// DeleteDelegation writes through, counting the rows written
+func (tx *countingTx) DeleteDelegation(ctx context.Context, chainName, delegatorAddress, validatorAddress string) error {
+	return tx.count("delegations", 1, tx.StateTx.DeleteDelegation(ctx, chainName, delegatorAddress, validatorAddress))
+}
+
+// UpsertTokenContracts writes through, counting the rows written
+func (tx *countingTx) UpsertTokenContracts(ctx context.Context, contracts []types.TokenContract) error {
+	return tx.count("token_contracts", len(contracts), tx.StateTx.UpsertTokenContracts(ctx, contracts))
+}
+
+// UpsertTokenHoldings writes through, counting the rows written
+func (tx *countingTx) UpsertTokenHoldings(ctx context.Context, holdings []types.TokenHolding) error {
+	return tx.count("token_holdings", len(holdings), tx.StateTx.UpsertTokenHoldings(ctx, holdings))
+}
+
+// ReplaceUnbondingDelegations writes through, counting the rows written
+func (tx *countingTx) ReplaceUnbondingDelegations(ctx context.Context, chainName string, unbondings []types.UnbondingDelegation) error {
+	return tx.count("unbonding_delegations", len(unbondings), tx.StateTx.ReplaceUnbondingDelegations(ctx, chainName, unbondings))
+}
+
+// ReplaceRedelegations writes through, counting the rows written
+func (tx *countingTx) ReplaceRedelegations(ctx context.Context, chainName string, redelegations []types.Redelegation) error {
+	return tx.count("redelegations", len(redelegations), tx.StateTx.ReplaceRedelegations(ctx, chainName, redelegations))
+}
+
+// UpsertValidator writes through, counting the rows written
+func (tx *countingTx) UpsertValidator(ctx context.Context, validator *types.Validator) error {
+	return tx.count("validators", 1, tx.StateTx.UpsertValidator(ctx, validator))
+}
+
+// UpsertConsensusAddress writes through, counting the rows written
+func (tx *countingTx) UpsertConsensusAddress(ctx context.Context, chainName, consensusAddress, operatorAddress string, height int64) error {
+	return tx.count("validator_consensus_addresses", 1, tx.StateTx.UpsertConsensusAddress(ctx, chainName, consensusAddress, operatorAddress, height))
+}
+
+// ReplaceConsumerChains writes through, counting the rows written
+func (tx *countingTx) ReplaceConsumerChains(ctx context.Context, providerChain string, consumers []types.ConsumerChain) error {
+	return tx.count("consumer_chains", len(consumers), tx.StateTx.ReplaceConsumerChains(ctx, providerChain, consumers))
+}
+
+// UpsertSupply writes through, counting the rows written
+func (tx *countingTx) UpsertSupply(ctx context.Context, supply []types.Supply) error {
+	return tx.count("supply", len(supply), tx.StateTx.UpsertSupply(ctx, supply))
+}
+
+// ReplaceCommunityPool writes through, counting the rows written
+func (tx *countingTx) ReplaceCommunityPool(ctx context.Context, chainName string, pool []types.PoolBalance) error {
+	return tx.count("community_pool", len(pool), tx.StateTx.ReplaceCommunityPool(ctx, chainName, pool))
+}
+
+// ReplaceModuleAccounts writes through, counting the rows written
+func (tx *countingTx) ReplaceModuleAccounts(ctx context.Context, chainName string, accounts []types.ModuleAccount) error {
+	return tx.count("module_accounts", len(accounts), tx.StateTx.ReplaceModuleAccounts(ctx, chainName, accounts))
+}
+
+// ReplaceVestingLocked writes through, counting the rows written
+func (tx *countingTx) ReplaceVestingLocked(ctx context.Context, chainName string, locked []types.VestingLocked) error {
+	return tx.count("vesting_locked", len(locked), tx.StateTx.ReplaceVestingLocked(ctx, chainName, locked))
+}
+
+// UpsertMintParams writes through, counting the rows written
+func (tx *countingTx) UpsertMintParams(ctx context.Context, params *types.MintParams) error {
+	return tx.count("mint_params", 1, tx.StateTx.UpsertMintParams(ctx, params))
+}
+
+// UpsertProposals writes through, counting the rows written
+func (tx *countingTx) UpsertProposals(ctx context.Context, proposals []types.Proposal) error {
+	return tx.count("proposals", len(proposals), tx.StateTx.UpsertProposals(ctx, proposals))
+}
+
+// ReplaceUpgradePlan writes through, counting the rows written
+func (tx *countingTx) ReplaceUpgradePlan(ctx context.Context, chainName string, plan *types.UpgradePlan) error {
+	return tx.count("upgrade_plans", 1, tx.StateTx.ReplaceUpgradePlan(ctx, chainName, plan))
+}
+
+// UpsertChainParams writes through, counting the rows written
+func (tx *countingTx) UpsertChainParams(ctx context.Context, params *types.ChainParams) error {
+	return tx.count("chain_params", 1, tx.StateTx.UpsertChainParams(ctx, params))
+}
+
+// InsertBlocks writes through, counting the rows written
+func (tx *countingTx) InsertBlocks(ctx context.Context, blocks []types.Block) error {
+	return tx.count("blocks", len(blocks), tx.StateTx.InsertBlocks(ctx, blocks))
+}
+
+// EnqueueOutbox writes through, counting the rows written
+func (tx *countingTx) EnqueueOutbox(ctx context.Context, messages []types.OutboxMessage) error {
+	return tx.count("outbox", len(messages), tx.StateTx.EnqueueOutbox(ctx, messages))
+}
+
+// Exec runs a custom module statement, counted as one row
+func (tx *countingTx) Exec(ctx context.Context, query string, args ...any) error {
+	return tx.count("custom", 1, tx.StateTx.Exec(ctx, query, args...))
+}
+
+// count adds rows written to a table to the transaction's pending rows when
+// the write succeeded
+func (tx *countingTx) count(table string, rows int, err error) error {
+	if err == nil {
+		if tx.tables == nil {
+			tx.tables = make(map[string]int64)
+		}
+		tx.tables[table] += int64(rows)
+	}
+	return err
+}
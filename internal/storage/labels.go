@@ -0,0 +1,237 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/lib/pq"
+)
+
+// LabelStore holds the entity labels of addresses
+type LabelStore interface {
+	UpsertLabel(ctx context.Context, label *types.AddressLabel) error
+	GetLabel(ctx context.Context, chainName, address string) (*types.AddressLabel, error)
+	GetLabels(ctx context.Context, filter types.LabelFilter) ([]types.AddressLabel, error)
+	DeleteLabel(ctx context.Context, chainName, address string) error
+}
+
+var (
+	_ LabelStore = (*PostgresStore)(nil)
+	_ LabelStore = (*CockroachStore)(nil)
+	_ LabelStore = (*MemoryStore)(nil)
+)
+
+// UpsertLabel creates or replaces the label of an address, keeping the time it
+// was first labeled
+func (s *PostgresStore) UpsertLabel(ctx context.Context, label *types.AddressLabel) error {
+	now := time.Now().UTC()
+	if label.Tags == nil {
+		label.Tags = []string{}
+	}
+
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO address_labels (chain_name, address, label, category, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (chain_name, address) DO UPDATE SET
+			label = EXCLUDED.label,
+			category = EXCLUDED.category,
+			tags = EXCLUDED.tags,
+			updated_at = EXCLUDED.updated_at
+		RETURNING created_at, updated_at
+	`, label.ChainName, label.Address, label.Label, label.Category, pq.Array(label.Tags), now).
+		Scan(&label.CreatedAt, &label.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert label: %w", err)
+	}
+	return nil
+}
+
+// GetLabel returns the label of an address
+func (s *PostgresStore) GetLabel(ctx context.Context, chainName, address string) (*types.AddressLabel, error) {
+	var label types.AddressLabel
+	err := s.db.QueryRowContext(ctx, `
+		SELECT chain_name, address, label, category, tags, created_at, updated_at
+		FROM address_labels
+		WHERE chain_name = $1 AND address = $2
+	`, chainName, address).Scan(
+		&label.ChainName,
+		&label.Address,
+		&label.Label,
+		&label.Category,
+		pq.Array(&label.Tags),
+		&label.CreatedAt,
+		&label.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("label of %s: %w", address, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get label: %w", err)
+	}
+
+	return &label, nil
+}
+
+// GetLabels returns the labels matching a filter ordered by chain and address
+func (s *PostgresStore) GetLabels(ctx context.Context, filter types.LabelFilter) ([]types.AddressLabel, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain_name, address, label, category, tags, created_at, updated_at
+		FROM address_labels
+		WHERE ($1 = '' OR chain_name = $1)
+		  AND (cardinality($2::text[]) = 0 OR category = ANY($2))
+		  AND ($3 = '' OR $3 = ANY(tags))
+		ORDER BY chain_name, address
+	`, filter.ChainName, pq.Array(filter.Categories), filter.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := []types.AddressLabel{}
+	for rows.Next() {
+		var label types.AddressLabel
+		err := rows.Scan(
+			&label.ChainName,
+			&label.Address,
+			&label.Label,
+			&label.Category,
+			pq.Array(&label.Tags),
+			&label.CreatedAt,
+			&label.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan label: %w", err)
+		}
+		labels = append(labels, label)
+	}
+
+	return labels, rows.Err()
+}
+
+// DeleteLabel removes the label of an address
+func (s *PostgresStore) DeleteLabel(ctx context.Context, chainName, address string) error {
+	return s.execScoped(ctx, "delete label",
+		`DELETE FROM address_labels WHERE chain_name = $1 AND address = $2`,
+		chainName, address)
+}
+
+// Labels returns the store holding address labels
+func (m *Manager) Labels() LabelStore {
+	return m.state.(LabelStore)
+}
+
+// accountLabel returns the label of an account, or nil when it has none
+func (m *Manager) accountLabel(ctx context.Context, ref AccountRef) (*types.AddressLabel, error) {
+	label, err := m.Labels().GetLabel(ctx, ref.ChainName, ref.Address)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return label, err
+}
+
+// GetTopHolders returns the largest holders of a denom with their labels,
+// leaving out the addresses labeled with any of the excluded categories
+func (m *Manager) GetTopHolders(ctx context.Context, chain, denom string, limit int, exclude []string) ([]types.TokenHolder, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("top holders require analytics storage: %w", ErrUnavailable)
+	}
+
+	labels, err := m.Labels().GetLabels(ctx, types.LabelFilter{ChainName: chain})
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[string]bool, len(exclude))
+	for _, category := range exclude {
+		excluded[category] = true
+	}
+	byAddress := make(map[string]types.AddressLabel, len(labels))
+	var skip []string
+	for _, label := range labels {
+		byAddress[label.Address] = label
+		if excluded[label.Category] {
+			skip = append(skip, label.Address)
+		}
+	}
+
+	holders, err := m.clickhouse.GetTopHolders(ctx, chain, denom, limit, skip)
+	if err != nil {
+		return nil, err
+	}
+	for i := range holders {
+		if label, ok := byAddress[holders[i].Address]; ok {
+			holders[i].Label = &label
+		}
+	}
+
+	return holders, nil
+}
+
+// GetTopFeePayers returns the addresses that paid the most fees in a denom on
+// a chain over the last days, with their labels
+func (m *Manager) GetTopFeePayers(ctx context.Context, chain, denom string, days, limit int) ([]types.FeePayer, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("fee analytics require analytics storage: %w", ErrUnavailable)
+	}
+
+	payers, err := m.clickhouse.GetTopFeePayers(ctx, chain, denom, days, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	byAddress, err := m.labelsByAddress(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	for i := range payers {
+		if label, ok := byAddress[payers[i].Address]; ok {
+			payers[i].Label = &label
+		}
+	}
+
+	return payers, nil
+}
+
+// GetRelayerLeaderboard returns the relayers that relayed the most packets on
+// a chain since a time, with their labels
+func (m *Manager) GetRelayerLeaderboard(ctx context.Context, chain string, since time.Time, limit int) ([]types.RelayerStats, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("relayer analytics require analytics storage: %w", ErrUnavailable)
+	}
+
+	relayers, err := m.clickhouse.GetRelayerLeaderboard(ctx, chain, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	byAddress, err := m.labelsByAddress(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	for i := range relayers {
+		if label, ok := byAddress[relayers[i].Address]; ok {
+			relayers[i].Label = &label
+		}
+	}
+
+	return relayers, nil
+}
+
+// labelsByAddress returns the labels of a chain keyed by address
+func (m *Manager) labelsByAddress(ctx context.Context, chain string) (map[string]types.AddressLabel, error) {
+	labels, err := m.Labels().GetLabels(ctx, types.LabelFilter{ChainName: chain})
+	if err != nil {
+		return nil, err
+	}
+
+	byAddress := make(map[string]types.AddressLabel, len(labels))
+	for _, label := range labels {
+		byAddress[label.Address] = label
+	}
+	return byAddress, nil
+}
@@ -2,29 +2,49 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/diskqueue"
 	"github.com/cosmos/state-mesh/pkg/types"
 	"go.uber.org/zap"
 )
 
-// Manager manages database connections and operations
+// Manager manages database connections and operations. When the state store
+// becomes unreachable, new transactions are logged to a write-ahead log on disk
+// and replayed in order once it answers again.
 type Manager struct {
-	postgres   *PostgresStore
+	driver     string
+	state      StateStore
 	clickhouse *ClickHouseStore
 	logger     *zap.Logger
+
+	wal               *diskqueue.Queue
+	buffering         atomic.Bool
+	reconnectInterval time.Duration
+	cancel            context.CancelFunc
+	wg                sync.WaitGroup
 }
 
 // NewManager creates a new storage manager
 func NewManager(cfg config.DatabaseConfig) (*Manager, error) {
 	logger := zap.L().Named("storage")
 
-	// Initialize PostgreSQL
-	pgStore, err := NewPostgresStore(cfg.Postgres.DSN(), logger)
+	// Initialize the state store
+	driver := cfg.Driver
+	if driver == "" {
+		driver = config.DriverPostgres
+	}
+	state, err := newStateStore(driver, cfg.Postgres, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize PostgreSQL: %w", err)
+		return nil, fmt.Errorf("failed to initialize %s: %w", driver, err)
 	}
 
 	// Initialize ClickHouse (optional)
@@ -33,19 +53,86 @@ func NewManager(cfg config.DatabaseConfig) (*Manager, error) {
 		clickhouse, err = NewClickHouseStore(cfg.ClickHouse)
 		if err != nil {
 			logger.Warn("Failed to initialize ClickHouse, continuing without analytics", zap.Error(err))
+		} else if err := clickhouse.EnsureStatsViews(context.Background()); err != nil {
+			logger.Warn("Failed to ensure ClickHouse stats views", zap.Error(err))
+		}
+		if clickhouse != nil {
+			if err := clickhouse.EnsureEventBuffers(context.Background()); err != nil {
+				logger.Warn("Failed to ensure ClickHouse event buffer tables, inserting into the event tables directly", zap.Error(err))
+			}
 		}
 	}
 
-	return &Manager{
-		postgres:   pgStore,
-		clickhouse: clickhouse,
-		logger:     logger,
-	}, nil
+	m := &Manager{
+		driver:            driver,
+		state:             state,
+		clickhouse:        clickhouse,
+		logger:            logger,
+		reconnectInterval: cfg.WAL.ReconnectInterval,
+	}
+	if m.reconnectInterval <= 0 {
+		m.reconnectInterval = 5 * time.Second
+	}
+
+	// Initialize the write-ahead log (optional, pointless for the memory store)
+	if cfg.WAL.Dir != "" && driver != config.DriverMemory {
+		m.wal, err = diskqueue.New(cfg.WAL.Dir, cfg.WAL.MaxBytes, true)
+		if err != nil {
+			state.Close()
+			return nil, fmt.Errorf("failed to open write-ahead log: %w", err)
+		}
+
+		// Writes left by a previous run must land before any new ones
+		if !m.wal.Empty() {
+			logger.Info("Write-ahead log holds unreplayed writes, buffering until replayed")
+			m.buffering.Store(true)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		m.cancel = cancel
+		m.wg.Add(1)
+		go m.reconnectLoop(ctx)
+	}
+
+	return m, nil
+}
+
+// newStateStore opens the state store for the configured database driver
+func newStateStore(driver string, pg config.PostgresConfig, logger *zap.Logger) (StateStore, error) {
+	switch driver {
+	case config.DriverPostgres:
+		return openPostgresStore(pg, logger)
+	case config.DriverCockroachDB:
+		store, err := openPostgresStore(pg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return newCockroachStore(store), nil
+	case config.DriverMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
+
+// State returns the state store
+func (m *Manager) State() StateStore {
+	return m.state
+}
+
+// Tenants returns the store holding tenants and their rows
+func (m *Manager) Tenants() TenantStore {
+	return m.state.(TenantStore)
+}
+
+// Outbox returns the store holding messages awaiting the outbox relay
+func (m *Manager) Outbox() OutboxStore {
+	return m.state.(OutboxStore)
 }
 
-// Postgres returns the PostgreSQL store
-func (m *Manager) Postgres() *PostgresStore {
-	return m.postgres
+// Driver returns the name of the configured state store driver
+func (m *Manager) Driver() string {
+	return m.driver
 }
 
 // ClickHouse returns the ClickHouse store (may be nil)
@@ -55,9 +142,9 @@ func (m *Manager) ClickHouse() *ClickHouseStore {
 
 // Ping tests connectivity to all databases
 func (m *Manager) Ping(ctx context.Context) error {
-	// Test PostgreSQL
-	if err := m.postgres.Ping(ctx); err != nil {
-		return fmt.Errorf("PostgreSQL ping failed: %w", err)
+	// Test the state store
+	if err := m.state.Ping(ctx); err != nil {
+		return fmt.Errorf("%s ping failed: %w", m.driver, err)
 	}
 
 	// Test ClickHouse if enabled
@@ -70,12 +157,23 @@ func (m *Manager) Ping(ctx context.Context) error {
 	return nil
 }
 
+// Buffering reports whether state writes are currently going to the
+// write-ahead log instead of the state store
+func (m *Manager) Buffering() bool {
+	return m.buffering.Load()
+}
+
 // Close closes all database connections
 func (m *Manager) Close() error {
 	var errs []error
 
-	if err := m.postgres.Close(); err != nil {
-		errs = append(errs, fmt.Errorf("PostgreSQL close error: %w", err))
+	if m.cancel != nil {
+		m.cancel()
+		m.wg.Wait()
+	}
+
+	if err := m.state.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("%s close error: %w", m.driver, err))
 	}
 
 	if m.clickhouse != nil {
@@ -91,23 +189,141 @@ func (m *Manager) Close() error {
 	return nil
 }
 
-// BeginTx starts a new transaction
+// BeginTx starts a new transaction. While the state store is unreachable, or
+// earlier writes are still waiting in the write-ahead log, the transaction is
+// logged instead so writes are replayed in order. The rows it commits are
+// added to the row counter carried by ctx, if any.
 func (m *Manager) BeginTx(ctx context.Context) (*Tx, error) {
-	pgTx, err := m.postgres.BeginTx(ctx)
+	tx, err := m.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if counter := rowCounterFrom(ctx); counter != nil {
+		tx.state = &countingTx{StateTx: tx.state, counter: counter}
+	}
+	return tx, nil
+}
+
+// beginTx starts a transaction against the state store or the write-ahead log
+func (m *Manager) beginTx(ctx context.Context) (*Tx, error) {
+	if m.buffering.Load() {
+		return m.walTx(), nil
+	}
+
+	stateTx, err := m.state.BeginTx(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin PostgreSQL transaction: %w", err)
+		if m.wal != nil && ctx.Err() == nil && m.ping(ctx) != nil {
+			if m.buffering.CompareAndSwap(false, true) {
+				m.logger.Warn("State store unreachable, logging writes to the write-ahead log",
+					zap.String("driver", m.driver),
+					zap.Error(err))
+			}
+			return m.walTx(), nil
+		}
+		return nil, fmt.Errorf("failed to begin %s transaction: %w", m.driver, err)
 	}
 
 	return &Tx{
-		postgres: pgTx,
-		logger:   m.logger,
+		state:  stateTx,
+		logger: m.logger,
 	}, nil
 }
 
+// walTx starts a transaction that is logged to the write-ahead log
+func (m *Manager) walTx() *Tx {
+	return &Tx{
+		state:  &walTx{log: m.wal},
+		logger: m.logger,
+	}
+}
+
+// ping probes the state store with a short timeout
+func (m *Manager) ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return m.state.Ping(ctx)
+}
+
+// reconnectLoop periodically replays the write-ahead log while writes are
+// being buffered
+func (m *Manager) reconnectLoop(ctx context.Context) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.reconnectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.reconnect(ctx)
+		}
+	}
+}
+
+// reconnect replays logged transactions in order once the state store
+// answers, and resumes direct writes when the log is drained
+func (m *Manager) reconnect(ctx context.Context) {
+	if !m.buffering.Load() && m.wal.Empty() {
+		return
+	}
+
+	if err := m.ping(ctx); err != nil {
+		m.logger.Debug("State store still unreachable", zap.Error(err))
+		return
+	}
+
+	replayed, err := m.wal.Replay(func(line []byte) error {
+		var entry walEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			// An entry torn by a crash mid-write
+			m.logger.Error("Discarding undecodable write-ahead log entry", zap.Error(err))
+			return nil
+		}
+
+		err := m.replay(ctx, entry)
+		if err != nil && ctx.Err() == nil && m.ping(ctx) == nil {
+			// The store is up but rejects the entry; retrying it would block
+			// every later write
+			m.logger.Error("Discarding write-ahead log entry rejected by the state store", zap.Error(err))
+			return nil
+		}
+		return err
+	})
+	if replayed > 0 {
+		m.logger.Info("Replayed write-ahead log", zap.Int("transactions", replayed))
+	}
+	if err != nil {
+		m.logger.Warn("Failed to replay write-ahead log", zap.Error(err))
+		return
+	}
+
+	// Transactions logged during the replay are picked up next time; until
+	// the log is empty new writes keep queueing behind them
+	if m.wal.Empty() && m.buffering.CompareAndSwap(true, false) {
+		m.logger.Info("State store reachable again, resuming direct writes", zap.String("driver", m.driver))
+	}
+}
+
+// replay applies one logged transaction to the state store
+func (m *Manager) replay(ctx context.Context, entry walEntry) error {
+	tx, err := m.state.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin %s transaction: %w", m.driver, err)
+	}
+	defer tx.Rollback()
+
+	if err := entry.apply(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 
 // GetBalances returns balances for an address on a chain (Bank module)
 func (m *Manager) GetBalances(ctx context.Context, address, chain string) ([]*types.Balance, error) {
-	balances, err := m.postgres.GetBalances(ctx, chain, address)
+	balances, err := m.state.GetBalances(ctx, chain, address)
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +338,7 @@ func (m *Manager) GetBalances(ctx context.Context, address, chain string) ([]*ty
 
 // GetDelegations returns delegations for an address on a chain (Staking module)
 func (m *Manager) GetDelegations(ctx context.Context, address, chain string) ([]*types.Delegation, error) {
-	delegations, err := m.postgres.GetDelegations(ctx, chain, address)
+	delegations, err := m.state.GetDelegations(ctx, chain, address)
 	if err != nil {
 		return nil, err
 	}
@@ -135,56 +351,359 @@ func (m *Manager) GetDelegations(ctx context.Context, address, chain string) ([]
 	return result, nil
 }
 
-// GetChains returns all configured chains
-func (m *Manager) GetChains(ctx context.Context) ([]*types.ChainInfo, error) {
-	// For now, return demo data based on PRD requirements
-	return []*types.ChainInfo{
-		{
-			Name:         "cosmoshub",
-			ChainID:      "cosmoshub-4", 
-			Status:       "active",
-			LatestHeight: 12345678,
-			LatestTime:   time.Now(),
-			UpdatedAt:    time.Now(),
-		},
-	}, nil
+// GetChainStats returns aggregated statistics for a chain. Validator, supply,
+// account and inflation figures come from PostgreSQL; daily activity comes from
+// the ClickHouse stats views when analytics storage is available.
+func (m *Manager) GetChainStats(ctx context.Context, chain string) (*types.ChainStats, error) {
+	stats := &types.ChainStats{
+		ChainName:   chain,
+		TotalSupply: []types.Coin{},
+	}
+
+	var err error
+	stats.TotalValidators, stats.ActiveValidators, stats.BondedTokens, err = m.state.GetValidatorSummary(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	supply, err := m.state.GetSupply(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.AccountCount, err = m.state.CountAccounts(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	mint, err := m.state.GetMintParams(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	if mint != nil {
+		stats.InflationRate = mint.CurrentInflation
+	}
+
+	// Chains may mint a different denom from the one they stake
+	staking, err := m.Params().GetChainParams(ctx, chain, "staking")
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		if denom, ok := staking.Params["bond_denom"]; ok {
+			if err := json.Unmarshal(denom, &stats.BondDenom); err != nil {
+				return nil, fmt.Errorf("failed to decode staking bond_denom: %w", err)
+			}
+		}
+	}
+
+	for _, coin := range supply {
+		stats.TotalSupply = append(stats.TotalSupply, types.Coin{Denom: coin.Denom, Amount: coin.Amount})
+		if coin.Denom == stats.BondDenom {
+			stats.BondedRatio = ratio(stats.BondedTokens, coin.Amount)
+		}
+	}
+
+	if m.clickhouse != nil {
+		activity, err := m.clickhouse.GetChainActivity(ctx, chain)
+		if err != nil {
+			m.logger.Warn("Failed to get chain activity from ClickHouse",
+				zap.String("chain", chain),
+				zap.Error(err))
+		} else {
+			stats.Activity = activity
+		}
+	}
+
+	anomalies, err := m.Anomalies().GetAnomalies(ctx, chain, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		m.logger.Warn("Failed to get chain anomalies",
+			zap.String("chain", chain),
+			zap.Error(err))
+	} else {
+		stats.Anomalies = anomalies
+	}
+
+	halts, err := m.Halts().GetHalts(ctx, chain, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		m.logger.Warn("Failed to get chain halts",
+			zap.String("chain", chain),
+			zap.Error(err))
+	} else {
+		stats.Halts = halts
+	}
+
+	return stats, nil
 }
 
-// GetChain returns a specific chain by name
-func (m *Manager) GetChain(ctx context.Context, name string) (*types.ChainInfo, error) {
-	chains, err := m.GetChains(ctx)
+// GetBlockProduction returns per-validator proposed and signed block counts
+// since the given time, joined to validators through their consensus addresses,
+// and the number of blocks in the window. Expected proposals are based on each
+// bonded validator's share of bonded tokens.
+func (m *Manager) GetBlockProduction(ctx context.Context, chain string, since time.Time) ([]types.ValidatorBlockProduction, uint64, error) {
+	if m.clickhouse == nil {
+		return nil, 0, fmt.Errorf("block production requires analytics storage: %w", ErrUnavailable)
+	}
+
+	production, total, err := m.clickhouse.GetBlockProduction(ctx, chain, since)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	operators, err := m.state.GetConsensusAddressMap(ctx, chain)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	validators, err := m.state.GetValidators(ctx, chain)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	byOperator := make(map[string]types.Validator, len(validators))
+	bondedTotal := new(big.Float)
+	for _, validator := range validators {
+		byOperator[validator.OperatorAddress] = validator
+		if tokens, ok := new(big.Float).SetString(validator.Tokens); ok && validator.Status == "BOND_STATUS_BONDED" {
+			bondedTotal.Add(bondedTotal, tokens)
+		}
+	}
+
+	result := make([]types.ValidatorBlockProduction, 0, len(production))
+	for address, p := range production {
+		p.OperatorAddress = operators[address]
+		if validator, ok := byOperator[p.OperatorAddress]; ok {
+			p.Moniker = validator.Description.Moniker
+			tokens, ok := new(big.Float).SetString(validator.Tokens)
+			if ok && validator.Status == "BOND_STATUS_BONDED" && bondedTotal.Sign() > 0 {
+				share, _ := new(big.Float).Quo(tokens, bondedTotal).Float64()
+				p.ExpectedProposed = share * float64(total)
+			}
+		}
+		result = append(result, *p)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Proposed != result[j].Proposed {
+			return result[i].Proposed > result[j].Proposed
+		}
+		return result[i].ConsensusAddress < result[j].ConsensusAddress
+	})
+
+	return result, total, nil
+}
+
+// GetMessageActivity returns the messages of each type included in a chain's
+// transactions over the last days, most frequent first, with the daily counts
+// they sum
+func (m *Manager) GetMessageActivity(ctx context.Context, chain string, days int) ([]types.MessageActivity, []types.DailyMessageActivity, error) {
+	if m.clickhouse == nil {
+		return nil, nil, fmt.Errorf("message activity requires analytics storage: %w", ErrUnavailable)
+	}
+
+	daily, err := m.clickhouse.GetDailyMessageActivity(ctx, chain, days)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byType := make(map[string]*types.MessageActivity)
+	var total uint64
+	for _, day := range daily {
+		activity, ok := byType[day.MessageType]
+		if !ok {
+			activity = &types.MessageActivity{MessageType: day.MessageType}
+			byType[day.MessageType] = activity
+		}
+		activity.Messages += day.Messages
+		activity.FailedMessages += day.FailedMessages
+		total += day.Messages
+	}
+
+	breakdown := make([]types.MessageActivity, 0, len(byType))
+	for _, activity := range byType {
+		activity.Share = float64(activity.Messages) / float64(total)
+		breakdown = append(breakdown, *activity)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Messages != breakdown[j].Messages {
+			return breakdown[i].Messages > breakdown[j].Messages
+		}
+		return breakdown[i].MessageType < breakdown[j].MessageType
+	})
+
+	return breakdown, daily, nil
+}
+
+// GetValidatorSetAt returns the bonded validator set of a chain at a height
+// from the stored snapshots, or the latest set when height is 0
+func (m *Manager) GetValidatorSetAt(ctx context.Context, chain string, height int64) (*types.ValidatorSetSnapshot, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("validator set history requires analytics storage: %w", ErrUnavailable)
+	}
+	return m.clickhouse.GetValidatorSetAt(ctx, chain, height)
+}
+
+// GetDelegationFlowGraph returns the redelegation graph of a chain in [from,
+// to): the limit largest flows between validators and the limit validators
+// with the largest net gain and loss of stake. A validator narrows the graph
+// to the flows into or out of it.
+func (m *Manager) GetDelegationFlowGraph(ctx context.Context, chain string, from, to time.Time, validator string, limit int) (*types.DelegationFlowGraph, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("delegation flows require analytics storage: %w", ErrUnavailable)
+	}
+
+	flows, err := m.clickhouse.GetDelegationFlows(ctx, chain, from, to, validator)
 	if err != nil {
 		return nil, err
 	}
-	
-	for _, chain := range chains {
-		if chain.Name == name {
-			return chain, nil
+
+	validators, err := m.state.GetValidators(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	monikers := make(map[string]string, len(validators))
+	for _, v := range validators {
+		monikers[v.OperatorAddress] = v.Description.Moniker
+	}
+
+	type stake struct{ in, out *big.Int }
+	byValidator := make(map[string]*stake)
+	entry := func(address string) *stake {
+		if s, ok := byValidator[address]; ok {
+			return s
 		}
+		s := &stake{in: new(big.Int), out: new(big.Int)}
+		byValidator[address] = s
+		return s
 	}
-	
-	return nil, fmt.Errorf("chain not found: %s", name)
+
+	total := new(big.Int)
+	for i := range flows {
+		flow := &flows[i]
+		flow.SrcMoniker = monikers[flow.SrcValidatorAddress]
+		flow.DstMoniker = monikers[flow.DstValidatorAddress]
+
+		amount, ok := new(big.Int).SetString(flow.Amount, 10)
+		if !ok {
+			continue
+		}
+		total.Add(total, amount)
+		src, dst := entry(flow.SrcValidatorAddress), entry(flow.DstValidatorAddress)
+		src.out.Add(src.out, amount)
+		dst.in.Add(dst.in, amount)
+	}
+
+	var gainers, losers []types.ValidatorStakeFlow
+	nets := make(map[string]*big.Int, len(byValidator))
+	for address, s := range byValidator {
+		net := new(big.Int).Sub(s.in, s.out)
+		nets[address] = net
+		flow := types.ValidatorStakeFlow{
+			ValidatorAddress: address,
+			Moniker:          monikers[address],
+			Inflow:           s.in.String(),
+			Outflow:          s.out.String(),
+			Net:              net.String(),
+		}
+		switch net.Sign() {
+		case 1:
+			gainers = append(gainers, flow)
+		case -1:
+			losers = append(losers, flow)
+		}
+	}
+	sort.Slice(gainers, func(i, j int) bool {
+		if c := nets[gainers[i].ValidatorAddress].Cmp(nets[gainers[j].ValidatorAddress]); c != 0 {
+			return c > 0
+		}
+		return gainers[i].ValidatorAddress < gainers[j].ValidatorAddress
+	})
+	sort.Slice(losers, func(i, j int) bool {
+		if c := nets[losers[i].ValidatorAddress].Cmp(nets[losers[j].ValidatorAddress]); c != 0 {
+			return c < 0
+		}
+		return losers[i].ValidatorAddress < losers[j].ValidatorAddress
+	})
+
+	graph := &types.DelegationFlowGraph{
+		ChainName: chain,
+		From:      from,
+		To:        to,
+		Total:     total.String(),
+		Flows:     truncate(flows, limit),
+		Gainers:   truncate(gainers, limit),
+		Losers:    truncate(losers, limit),
+	}
+	return graph, nil
+}
+
+// truncate returns at most the first n items of a slice, never nil
+func truncate[T any](items []T, n int) []T {
+	if len(items) > n {
+		items = items[:n]
+	}
+	if items == nil {
+		items = []T{}
+	}
+	return items
+}
+
+// ratio returns num/den as a decimal string, or an empty string when either
+// value cannot be parsed or the denominator is zero
+func ratio(num, den string) string {
+	n, ok := new(big.Float).SetString(num)
+	if !ok {
+		return ""
+	}
+	d, ok := new(big.Float).SetString(den)
+	if !ok || d.Sign() == 0 {
+		return ""
+	}
+
+	return new(big.Float).Quo(n, d).Text('f', 6)
+}
+
+// GetChains returns all chains registered by the ingester
+func (m *Manager) GetChains(ctx context.Context) ([]*types.ChainInfo, error) {
+	chains, err := m.state.GetChains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert slice to pointer slice
+	result := make([]*types.ChainInfo, len(chains))
+	for i := range chains {
+		result[i] = &chains[i]
+	}
+	return result, nil
+}
+
+// GetChain returns a specific chain by name
+func (m *Manager) GetChain(ctx context.Context, name string) (*types.ChainInfo, error) {
+	return m.state.GetChain(ctx, name)
 }
 
 // Tx represents a database transaction
 type Tx struct {
-	postgres *PostgresTx
-	logger   *zap.Logger
+	state  StateTx
+	logger *zap.Logger
 }
 
 // Commit commits the transaction
 func (tx *Tx) Commit() error {
-	return tx.postgres.Commit()
+	return tx.state.Commit()
 }
 
 // Rollback rolls back the transaction
 func (tx *Tx) Rollback() error {
-	return tx.postgres.Rollback()
+	return tx.state.Rollback()
 }
 
-// Postgres returns the PostgreSQL transaction
-func (tx *Tx) Postgres() *PostgresTx {
-	return tx.postgres
+// State returns the state store transaction
+func (tx *Tx) State() StateTx {
+	return tx.state
 }
 
 // ClickHouse returns the ClickHouse store
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+func TestRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		num, den string
+		want     string
+	}{
+		{name: "bonded share", num: "250000", den: "1000000", want: "0.250000"},
+		{name: "fully bonded", num: "1000000", den: "1000000", want: "1.000000"},
+		{name: "rounded to six digits", num: "1", den: "3", want: "0.333333"},
+		{name: "large amounts", num: "123456789012345678901234567890", den: "246913578024691357802469135780", want: "0.500000"},
+		{name: "zero bonded", num: "0", den: "1000000", want: "0.000000"},
+		{name: "zero supply", num: "250000", den: "0", want: ""},
+		{name: "no supply", num: "250000", den: "", want: ""},
+		{name: "invalid bonded", num: "abc", den: "1000000", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ratio(tt.num, tt.den); got != tt.want {
+				t.Errorf("ratio(%q, %q) = %q, want %q", tt.num, tt.den, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetChainStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		validators []types.Validator
+		supply     []types.Supply
+		mint       *types.MintParams
+		bondDenom  string // of the staking params, unset without them
+
+		wantTotal, wantActive int64
+		wantBonded            string
+		wantRatio             string
+		wantInflation         string
+		wantDenom             string
+		wantSupply            int
+	}{
+		{
+			name: "bonded ratio and inflation",
+			validators: []types.Validator{
+				{OperatorAddress: "val1", Status: "BOND_STATUS_BONDED", Tokens: "300000"},
+				{OperatorAddress: "val2", Status: "BOND_STATUS_BONDED", Tokens: "200000", Jailed: true},
+				{OperatorAddress: "val3", Status: "BOND_STATUS_UNBONDING", Tokens: "100000"},
+			},
+			supply: []types.Supply{
+				{Denom: "uatom", Amount: "2000000"},
+				{Denom: "ibc/ABC", Amount: "5"},
+			},
+			mint:       &types.MintParams{MintDenom: "uatom", CurrentInflation: "0.070000000000000000"},
+			bondDenom:  "uatom",
+			wantTotal:  3,
+			wantActive: 1,
+			wantBonded: "500000",
+			wantRatio:  "0.250000", wantInflation: "0.070000000000000000", wantDenom: "uatom",
+			wantSupply: 2,
+		},
+		{
+			name: "zero bonded",
+			validators: []types.Validator{
+				{OperatorAddress: "val1", Status: "BOND_STATUS_UNBONDED", Tokens: "300000"},
+			},
+			supply:     []types.Supply{{Denom: "uatom", Amount: "2000000"}},
+			mint:       &types.MintParams{MintDenom: "uatom", CurrentInflation: "0.1"},
+			bondDenom:  "uatom",
+			wantTotal:  1,
+			wantBonded: "0",
+			wantRatio:  "0.000000", wantInflation: "0.1", wantDenom: "uatom",
+			wantSupply: 1,
+		},
+		{
+			name: "zero supply",
+			validators: []types.Validator{
+				{OperatorAddress: "val1", Status: "BOND_STATUS_BONDED", Tokens: "300000"},
+			},
+			supply:     []types.Supply{{Denom: "uatom", Amount: "0"}},
+			mint:       &types.MintParams{MintDenom: "uatom", CurrentInflation: "0"},
+			bondDenom:  "uatom",
+			wantTotal:  1,
+			wantActive: 1,
+			wantBonded: "300000",
+			wantRatio:  "", wantInflation: "0", wantDenom: "uatom",
+			wantSupply: 1,
+		},
+		{
+			name: "mint denom differs from bond denom",
+			validators: []types.Validator{
+				{OperatorAddress: "val1", Status: "BOND_STATUS_BONDED", Tokens: "300000"},
+			},
+			supply: []types.Supply{
+				{Denom: "ustake", Amount: "600000"},
+				{Denom: "ureward", Amount: "9000000"},
+			},
+			mint:       &types.MintParams{MintDenom: "ureward", CurrentInflation: "0.2"},
+			bondDenom:  "ustake",
+			wantTotal:  1,
+			wantActive: 1,
+			wantBonded: "300000",
+			wantRatio:  "0.500000", wantInflation: "0.2", wantDenom: "ustake",
+			wantSupply: 2,
+		},
+		{
+			name: "no mint module",
+			validators: []types.Validator{
+				{OperatorAddress: "val1", Status: "BOND_STATUS_BONDED", Tokens: "300000"},
+			},
+			supply:     []types.Supply{{Denom: "uatom", Amount: "2000000"}},
+			bondDenom:  "uatom",
+			wantTotal:  1,
+			wantActive: 1,
+			wantBonded: "300000",
+			wantRatio:  "0.150000", wantDenom: "uatom",
+			wantSupply: 1,
+		},
+		{
+			name: "no staking params",
+			validators: []types.Validator{
+				{OperatorAddress: "val1", Status: "BOND_STATUS_BONDED", Tokens: "300000"},
+			},
+			supply:        []types.Supply{{Denom: "uatom", Amount: "2000000"}},
+			mint:          &types.MintParams{MintDenom: "uatom", CurrentInflation: "0.1"},
+			wantTotal:     1,
+			wantActive:    1,
+			wantBonded:    "300000",
+			wantInflation: "0.1",
+			wantSupply:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			m, err := NewManager(config.DatabaseConfig{Driver: config.DriverMemory})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer m.Close()
+
+			tx, err := m.BeginTx(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range tt.validators {
+				tt.validators[i].ChainName = "testchain"
+				if err := tx.State().UpsertValidator(ctx, &tt.validators[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for i := range tt.supply {
+				tt.supply[i].ChainName = "testchain"
+			}
+			if err := tx.State().UpsertSupply(ctx, tt.supply); err != nil {
+				t.Fatal(err)
+			}
+			if tt.mint != nil {
+				tt.mint.ChainName = "testchain"
+				if err := tx.State().UpsertMintParams(ctx, tt.mint); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.bondDenom != "" {
+				params := &types.ChainParams{
+					ChainName: "testchain",
+					Module:    "staking",
+					Params:    map[string]json.RawMessage{"bond_denom": json.RawMessage(`"` + tt.bondDenom + `"`)},
+				}
+				if err := tx.State().UpsertChainParams(ctx, params); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tx.Commit(); err != nil {
+				t.Fatal(err)
+			}
+
+			stats, err := m.GetChainStats(ctx, "testchain")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.TotalValidators != tt.wantTotal || stats.ActiveValidators != tt.wantActive {
+				t.Errorf("validators = %d total, %d active, want %d, %d", stats.TotalValidators, stats.ActiveValidators, tt.wantTotal, tt.wantActive)
+			}
+			if stats.BondedTokens != tt.wantBonded {
+				t.Errorf("bonded tokens = %q, want %q", stats.BondedTokens, tt.wantBonded)
+			}
+			if stats.BondedRatio != tt.wantRatio {
+				t.Errorf("bonded ratio = %q, want %q", stats.BondedRatio, tt.wantRatio)
+			}
+			if stats.InflationRate != tt.wantInflation || stats.BondDenom != tt.wantDenom {
+				t.Errorf("inflation = %q in %q, want %q in %q", stats.InflationRate, stats.BondDenom, tt.wantInflation, tt.wantDenom)
+			}
+			if len(stats.TotalSupply) != tt.wantSupply {
+				t.Errorf("total supply has %d denoms, want %d", len(stats.TotalSupply), tt.wantSupply)
+			}
+		})
+	}
+}
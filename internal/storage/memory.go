@@ -0,0 +1,2283 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/big"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// memKey identifies a row in the in-memory store by chain and up to two more key columns
+type memKey struct {
+	chain string
+	a     string
+	b     string
+}
+
+// memoryState holds the rows of the in-memory store
+type memoryState struct {
+	chains        map[string]types.ChainInfo
+	chainStatus   map[string]types.ChainStatus
+	accounts      map[memKey]types.Account
+	balances      map[memKey]types.Balance
+	delegations   map[memKey]types.Delegation
+	unbondings    map[string][]types.UnbondingDelegation
+	redelegations map[string][]types.Redelegation // by chain
+	contracts     map[memKey]types.TokenContract  // by chain and contract
+	holdings      map[memKey]types.TokenHolding   // by chain, address and contract
+	validators    map[memKey]types.Validator
+	consensus     map[memKey]string
+	consumers     map[string][]types.ConsumerChain // by provider chain
+	supply        map[memKey]types.Supply
+	pool          map[string][]types.PoolBalance   // by chain
+	modules       map[string][]types.ModuleAccount // by chain
+	vesting       map[string][]types.VestingLocked // by chain
+	mint          map[string]types.MintParams
+	proposals     map[memKey]types.Proposal // by chain and proposal ID
+	upgrades      map[string]types.UpgradePlan
+	params        map[memKey]types.ChainParams // by chain and module
+	blocks        map[memKey]types.Block
+	commissions   []memoryCommissionChange
+	paramChanges  []types.ChainParamChange
+	anomalies     []types.Anomaly
+	halts         []types.ChainHalt
+	outbox        []types.OutboxMessage // undelivered only
+	outboxSeq     int64
+}
+
+// memoryTenants holds the tenant-owned rows of the in-memory store, keyed by ID
+type memoryTenants struct {
+	tenants    map[string]types.Tenant
+	apiKeys    map[string]types.APIKey
+	watchlists map[string]types.Watchlist
+	portfolios map[string]types.Portfolio
+	webhooks   map[string]types.Webhook
+	emails     map[string]types.EmailSubscription
+	alertRules map[string]types.AlertRule
+	digests    map[string]types.Digest
+	reminders  map[reminderKey]bool // claimed unbonding reminders
+}
+
+// reminderKey identifies an unbonding reminder of a tenant
+type reminderKey struct {
+	tenantID, chainName, delegator, validator string
+	creationHeight                            int64
+	completionTime                            int64 // unix nanoseconds
+}
+
+func newReminderKey(tenantID string, completion types.UnbondingCompletion) reminderKey {
+	return reminderKey{
+		tenantID:       tenantID,
+		chainName:      completion.ChainName,
+		delegator:      completion.DelegatorAddress,
+		validator:      completion.ValidatorAddress,
+		creationHeight: completion.CreationHeight,
+		completionTime: completion.CompletionTime.UnixNano(),
+	}
+}
+
+// MemoryStore is an in-process state store for demos and tests. It keeps only
+// current state, so history pruning is a no-op and proposals are not searchable.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	state   memoryState
+	tenants memoryTenants
+	labels  map[memKey]types.AddressLabel // by chain and address
+
+	ingestRuns   []types.IngestRun // oldest first
+	ingestRunSeq int64
+
+	deliverMu sync.Mutex // serializes outbox deliveries
+}
+
+// memoryCommissionChange is a commission change with the time tenants were
+// notified of it
+type memoryCommissionChange struct {
+	change     types.CommissionChange
+	notifiedAt *time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		state: memoryState{
+			chains:        make(map[string]types.ChainInfo),
+			chainStatus:   make(map[string]types.ChainStatus),
+			accounts:      make(map[memKey]types.Account),
+			balances:      make(map[memKey]types.Balance),
+			delegations:   make(map[memKey]types.Delegation),
+			unbondings:    make(map[string][]types.UnbondingDelegation),
+			redelegations: make(map[string][]types.Redelegation),
+			contracts:     make(map[memKey]types.TokenContract),
+			holdings:      make(map[memKey]types.TokenHolding),
+			validators:    make(map[memKey]types.Validator),
+			consensus:     make(map[memKey]string),
+			consumers:     make(map[string][]types.ConsumerChain),
+			supply:        make(map[memKey]types.Supply),
+			pool:          make(map[string][]types.PoolBalance),
+			modules:       make(map[string][]types.ModuleAccount),
+			vesting:       make(map[string][]types.VestingLocked),
+			mint:          make(map[string]types.MintParams),
+			proposals:     make(map[memKey]types.Proposal),
+			upgrades:      make(map[string]types.UpgradePlan),
+			params:        make(map[memKey]types.ChainParams),
+			blocks:        make(map[memKey]types.Block),
+		},
+		tenants: memoryTenants{
+			tenants:    make(map[string]types.Tenant),
+			apiKeys:    make(map[string]types.APIKey),
+			watchlists: make(map[string]types.Watchlist),
+			portfolios: make(map[string]types.Portfolio),
+			webhooks:   make(map[string]types.Webhook),
+			emails:     make(map[string]types.EmailSubscription),
+			alertRules: make(map[string]types.AlertRule),
+			digests:    make(map[string]types.Digest),
+			reminders:  make(map[reminderKey]bool),
+		},
+		labels: make(map[memKey]types.AddressLabel),
+	}
+}
+
+// Ping always succeeds
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close releases nothing; the store lives as long as the process
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// BeginTx starts a new transaction. Writes are buffered and applied atomically on commit.
+func (s *MemoryStore) BeginTx(ctx context.Context) (StateTx, error) {
+	return &memoryTx{store: s}, nil
+}
+
+// GetChains returns all registered chains
+func (s *MemoryStore) GetChains(ctx context.Context) ([]types.ChainInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var chains []types.ChainInfo
+	for name := range s.state.chains {
+		chains = append(chains, s.chainInfo(name))
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i].Name < chains[j].Name })
+
+	return chains, nil
+}
+
+// GetChain returns a registered chain by name
+func (s *MemoryStore) GetChain(ctx context.Context, name string) (*types.ChainInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.state.chains[name]; !ok {
+		return nil, fmt.Errorf("chain %s: %w", name, ErrNotFound)
+	}
+
+	chain := s.chainInfo(name)
+	return &chain, nil
+}
+
+// chainInfo merges a registered chain with its sync status
+func (s *MemoryStore) chainInfo(name string) types.ChainInfo {
+	chain := s.state.chains[name]
+	if status, ok := s.state.chainStatus[name]; ok {
+		chain.LatestHeight = status.LatestHeight
+		chain.LatestTime = status.LatestBlockTime
+		chain.LastIngestedHeight = status.LastIngestedHeight
+		chain.CatchingUp = status.CatchingUp
+		chain.NodeVersion = status.NodeVersion
+		chain.AppVersion = status.AppVersion
+		chain.HaltedSince = status.HaltedSince
+	}
+	return chain
+}
+
+// GetAccount returns an account, or nil when it is not tracked
+func (s *MemoryStore) GetAccount(ctx context.Context, chainName, address string) (*types.Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, ok := s.state.accounts[memKey{chain: chainName, a: address}]
+	if !ok {
+		return nil, nil
+	}
+	return &account, nil
+}
+
+// CountAccounts returns the number of tracked accounts for a chain
+func (s *MemoryStore) CountAccounts(ctx context.Context, chainName string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for key := range s.state.accounts {
+		if key.chain == chainName {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetBalances returns the balances of an address ordered by denom
+func (s *MemoryStore) GetBalances(ctx context.Context, chainName, address string) ([]types.Balance, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var balances []types.Balance
+	for key, balance := range s.state.balances {
+		if key.chain == chainName && key.a == address {
+			balances = append(balances, balance)
+		}
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Denom < balances[j].Denom })
+
+	return balances, nil
+}
+
+// GetTokenHoldings returns the non-empty token holdings of an address with the
+// metadata of their contracts
+func (s *MemoryStore) GetTokenHoldings(ctx context.Context, chainName, address string) ([]types.TokenHolding, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var holdings []types.TokenHolding
+	for key, holding := range s.state.holdings {
+		if key.chain != chainName || key.a != address || holding.Amount == "0" {
+			continue
+		}
+		contract := s.state.contracts[memKey{chain: chainName, a: holding.Contract}]
+		holding.Standard = contract.Standard
+		holding.Name = contract.Name
+		holding.Symbol = contract.Symbol
+		holding.Decimals = contract.Decimals
+		holding.TokenIDs = append([]string(nil), holding.TokenIDs...)
+		holdings = append(holdings, holding)
+	}
+	sort.Slice(holdings, func(i, j int) bool {
+		if holdings[i].Standard != holdings[j].Standard {
+			return holdings[i].Standard < holdings[j].Standard
+		}
+		if holdings[i].Symbol != holdings[j].Symbol {
+			return holdings[i].Symbol < holdings[j].Symbol
+		}
+		return holdings[i].Contract < holdings[j].Contract
+	})
+
+	return holdings, nil
+}
+
+// GetDelegations returns the delegations of a delegator ordered by validator
+func (s *MemoryStore) GetDelegations(ctx context.Context, chainName, delegatorAddress string) ([]types.Delegation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var delegations []types.Delegation
+	for key, delegation := range s.state.delegations {
+		if key.chain == chainName && key.a == delegatorAddress {
+			delegations = append(delegations, delegation)
+		}
+	}
+	sort.Slice(delegations, func(i, j int) bool {
+		return delegations[i].ValidatorAddress < delegations[j].ValidatorAddress
+	})
+
+	return delegations, nil
+}
+
+// GetUnbondingDelegations returns the pending unbonding entries of a
+// delegator grouped by validator, ordered by validator and then completion
+func (s *MemoryStore) GetUnbondingDelegations(ctx context.Context, chainName, delegatorAddress string) ([]types.UnbondingDelegation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	unbondings := []types.UnbondingDelegation{}
+	for _, unbonding := range s.state.unbondings[chainName] {
+		if unbonding.DelegatorAddress != delegatorAddress {
+			continue
+		}
+		var entries []types.UnbondingDelegationEntry
+		for _, entry := range unbonding.Entries {
+			if entry.CompletionTime.After(now) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].CompletionTime.Before(entries[j].CompletionTime) })
+		unbonding.Entries = entries
+		unbondings = append(unbondings, unbonding)
+	}
+	sort.SliceStable(unbondings, func(i, j int) bool {
+		return unbondings[i].ValidatorAddress < unbondings[j].ValidatorAddress
+	})
+
+	return unbondings, nil
+}
+
+// GetRedelegations returns the pending redelegation entries of a delegator
+// grouped by source and destination validator, ordered by validators and then
+// completion
+func (s *MemoryStore) GetRedelegations(ctx context.Context, chainName, delegatorAddress string) ([]types.Redelegation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	redelegations := []types.Redelegation{}
+	for _, redelegation := range s.state.redelegations[chainName] {
+		if redelegation.DelegatorAddress != delegatorAddress {
+			continue
+		}
+		var entries []types.RedelegationEntry
+		for _, entry := range redelegation.Entries {
+			if entry.CompletionTime.After(now) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].CompletionTime.Before(entries[j].CompletionTime) })
+		redelegation.Entries = entries
+		redelegations = append(redelegations, redelegation)
+	}
+	sort.SliceStable(redelegations, func(i, j int) bool {
+		if redelegations[i].ValidatorSrcAddress != redelegations[j].ValidatorSrcAddress {
+			return redelegations[i].ValidatorSrcAddress < redelegations[j].ValidatorSrcAddress
+		}
+		return redelegations[i].ValidatorDstAddress < redelegations[j].ValidatorDstAddress
+	})
+
+	return redelegations, nil
+}
+
+// SampleBalances returns a random sample of stored balances for a chain
+func (s *MemoryStore) SampleBalances(ctx context.Context, chainName string, limit int) ([]types.Balance, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var balances []types.Balance
+	for key, balance := range s.state.balances {
+		if key.chain == chainName {
+			balances = append(balances, balance)
+		}
+	}
+
+	return sample(balances, limit), nil
+}
+
+// SampleDelegations returns a random sample of stored delegations for a chain
+func (s *MemoryStore) SampleDelegations(ctx context.Context, chainName string, limit int) ([]types.Delegation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var delegations []types.Delegation
+	for key, delegation := range s.state.delegations {
+		if key.chain == chainName {
+			delegations = append(delegations, delegation)
+		}
+	}
+
+	return sample(delegations, limit), nil
+}
+
+// sample shuffles rows and returns at most limit of them
+func sample[T any](rows []T, limit int) []T {
+	rand.Shuffle(len(rows), func(i, j int) { rows[i], rows[j] = rows[j], rows[i] })
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// GetValidators returns the validators of a chain ordered by tokens
+func (s *MemoryStore) GetValidators(ctx context.Context, chainName string) ([]types.Validator, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var validators []types.Validator
+	for key, validator := range s.state.validators {
+		if key.chain == chainName {
+			validators = append(validators, validator)
+		}
+	}
+	sort.Slice(validators, func(i, j int) bool {
+		return compareDecimal(validators[i].Tokens, validators[j].Tokens) > 0
+	})
+
+	return validators, nil
+}
+
+// EachValidator passes the validators of a chain to fn ordered by tokens
+func (s *MemoryStore) EachValidator(ctx context.Context, chainName string, fn func(*types.Validator) error) error {
+	validators, err := s.GetValidators(ctx, chainName)
+	if err != nil {
+		return err
+	}
+	for i := range validators {
+		if err := fn(&validators[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetValidator returns a validator by operator address
+func (s *MemoryStore) GetValidator(ctx context.Context, chainName, operatorAddress string) (*types.Validator, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	validator, ok := s.state.validators[memKey{chain: chainName, a: operatorAddress}]
+	if !ok {
+		return nil, fmt.Errorf("validator %s: %w", operatorAddress, ErrNotFound)
+	}
+	return &validator, nil
+}
+
+// GetCommissionChanges returns the latest commission changes of a validator,
+// newest first
+func (s *MemoryStore) GetCommissionChanges(ctx context.Context, chainName, operatorAddress string, limit int) ([]types.CommissionChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	changes := []types.CommissionChange{}
+	for i := len(s.state.commissions) - 1; i >= 0 && len(changes) < limit; i-- {
+		change := s.state.commissions[i].change
+		if change.ChainName == chainName && change.OperatorAddress == operatorAddress {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// GetPendingCommissionIncreases returns the commission increases detected
+// since the given time that tenants were not notified of, oldest first
+func (s *MemoryStore) GetPendingCommissionIncreases(ctx context.Context, since time.Time) ([]types.CommissionChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	changes := []types.CommissionChange{}
+	for _, entry := range s.state.commissions {
+		if entry.notifiedAt != nil || entry.change.DetectedAt.Before(since) {
+			continue
+		}
+		oldRate, err1 := decimal.NewFromString(entry.change.OldRate)
+		newRate, err2 := decimal.NewFromString(entry.change.NewRate)
+		if err1 == nil && err2 == nil && newRate.GreaterThan(oldRate) {
+			changes = append(changes, entry.change)
+		}
+	}
+	return changes, nil
+}
+
+// ClaimCommissionChange sets the time tenants were notified of a change to
+// notifiedAt if it is still previous, and reports whether it was
+func (s *MemoryStore) ClaimCommissionChange(ctx context.Context, change types.CommissionChange, previous, notifiedAt *time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.state.commissions {
+		entry := &s.state.commissions[i]
+		if entry.change.ChainName != change.ChainName || entry.change.OperatorAddress != change.OperatorAddress ||
+			entry.change.Height != change.Height {
+			continue
+		}
+		switch {
+		case entry.notifiedAt == nil && previous == nil:
+		case entry.notifiedAt != nil && previous != nil && entry.notifiedAt.Equal(*previous):
+		default:
+			return false, nil
+		}
+		if notifiedAt != nil {
+			at := *notifiedAt
+			notifiedAt = &at
+		}
+		entry.notifiedAt = notifiedAt
+		return true, nil
+	}
+	return false, nil
+}
+
+// GetDelegatorsOf returns which of the given delegators delegate to a
+// validator
+func (s *MemoryStore) GetDelegatorsOf(ctx context.Context, chainName, validatorAddress string, delegators []string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []string
+	for _, delegator := range delegators {
+		if _, ok := s.state.delegations[memKey{chain: chainName, a: delegator, b: validatorAddress}]; ok {
+			matched = append(matched, delegator)
+		}
+	}
+	sort.Strings(matched)
+	return matched, nil
+}
+
+// GetValidatorByConsensusAddress returns the validator that uses or has used
+// the given consensus address
+func (s *MemoryStore) GetValidatorByConsensusAddress(ctx context.Context, chainName, consensusAddress string) (*types.Validator, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	operator, ok := s.state.consensus[memKey{chain: chainName, a: consensusAddress}]
+	if !ok {
+		return nil, fmt.Errorf("validator with consensus address %s: %w", consensusAddress, ErrNotFound)
+	}
+	validator, ok := s.state.validators[memKey{chain: chainName, a: operator}]
+	if !ok {
+		return nil, fmt.Errorf("validator with consensus address %s: %w", consensusAddress, ErrNotFound)
+	}
+	return &validator, nil
+}
+
+// GetValidatorDelegators returns a page of delegations to a validator ordered by
+// shares, with token amounts estimated from the validator's token/share ratio,
+// and the total number of delegators
+func (s *MemoryStore) GetValidatorDelegators(ctx context.Context, chainName, validatorAddress string, limit, offset int) ([]types.ValidatorDelegator, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	validator, ok := s.state.validators[memKey{chain: chainName, a: validatorAddress}]
+	if !ok {
+		return nil, 0, fmt.Errorf("validator %s: %w", validatorAddress, ErrNotFound)
+	}
+
+	var delegations []types.Delegation
+	for key, delegation := range s.state.delegations {
+		if key.chain == chainName && key.b == validatorAddress {
+			delegations = append(delegations, delegation)
+		}
+	}
+	sort.Slice(delegations, func(i, j int) bool {
+		if c := compareDecimal(delegations[i].Shares, delegations[j].Shares); c != 0 {
+			return c > 0
+		}
+		return delegations[i].DelegatorAddress < delegations[j].DelegatorAddress
+	})
+
+	total := int64(len(delegations))
+	delegators := []types.ValidatorDelegator{}
+	for i := offset; i < len(delegations) && len(delegators) < limit; i++ {
+		delegation := delegations[i]
+		delegators = append(delegators, types.ValidatorDelegator{
+			DelegatorAddress: delegation.DelegatorAddress,
+			Shares:           delegation.Shares,
+			Tokens:           sharesToTokens(delegation.Shares, validator.Tokens, validator.DelegatorShares),
+			Height:           delegation.Height,
+			UpdatedAt:        delegation.UpdatedAt,
+		})
+	}
+
+	return delegators, total, nil
+}
+
+// GetValidatorSummary returns validator counts and the total bonded tokens for a chain
+func (s *MemoryStore) GetValidatorSummary(ctx context.Context, chainName string) (total, active int64, bondedTokens string, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bonded := new(big.Int)
+	for key, validator := range s.state.validators {
+		if key.chain != chainName {
+			continue
+		}
+		total++
+		if validator.Status != "BOND_STATUS_BONDED" {
+			continue
+		}
+		if !validator.Jailed {
+			active++
+		}
+		if tokens, ok := new(big.Int).SetString(validator.Tokens, 10); ok {
+			bonded.Add(bonded, tokens)
+		}
+	}
+
+	return total, active, bonded.String(), nil
+}
+
+// GetConsensusAddressMap returns every known consensus address of a chain mapped
+// to its validator operator address
+func (s *MemoryStore) GetConsensusAddressMap(ctx context.Context, chainName string) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	addresses := make(map[string]string)
+	for key, operator := range s.state.consensus {
+		if key.chain == chainName {
+			addresses[key.a] = operator
+		}
+	}
+	return addresses, nil
+}
+
+// GetProposalDeadlines returns the proposals of the given chains whose voting
+// period ends between from and until, soonest first
+func (s *MemoryStore) GetProposalDeadlines(ctx context.Context, chains []string, from, until time.Time) ([]types.ProposalDeadline, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deadlines := []types.ProposalDeadline{}
+	for _, proposal := range s.state.proposals {
+		if !slices.Contains(chains, proposal.ChainName) ||
+			proposal.VotingEndTime.Before(from) || !proposal.VotingEndTime.Before(until) {
+			continue
+		}
+		deadlines = append(deadlines, types.ProposalDeadline{
+			ChainName:     proposal.ChainName,
+			ProposalID:    proposal.ProposalID,
+			Title:         proposal.Content.Title,
+			Status:        proposal.Status,
+			VotingEndTime: proposal.VotingEndTime,
+		})
+	}
+	sort.Slice(deadlines, func(i, j int) bool {
+		a, b := deadlines[i], deadlines[j]
+		if !a.VotingEndTime.Equal(b.VotingEndTime) {
+			return a.VotingEndTime.Before(b.VotingEndTime)
+		}
+		if a.ChainName != b.ChainName {
+			return a.ChainName < b.ChainName
+		}
+		return a.ProposalID < b.ProposalID
+	})
+	return deadlines, nil
+}
+
+// GetProposals returns the proposals of a chain, newest first, optionally
+// only those with a status, along with the total number matching
+func (s *MemoryStore) GetProposals(ctx context.Context, chainName, status string, limit, offset int) ([]types.Proposal, int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matching []types.Proposal
+	for key, proposal := range s.state.proposals {
+		if key.chain == chainName && (status == "" || proposal.Status == status) {
+			matching = append(matching, proposal)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].ProposalID > matching[j].ProposalID
+	})
+
+	proposals := []types.Proposal{}
+	for i := offset; i < len(matching) && len(proposals) < limit; i++ {
+		proposals = append(proposals, matching[i])
+	}
+	return proposals, int64(len(matching)), nil
+}
+
+// GetProposal returns a proposal of a chain
+func (s *MemoryStore) GetProposal(ctx context.Context, chainName string, proposalID uint64) (*types.Proposal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	proposal, ok := s.state.proposals[proposalKey(chainName, proposalID)]
+	if !ok {
+		return nil, fmt.Errorf("proposal %d: %w", proposalID, ErrNotFound)
+	}
+	return &proposal, nil
+}
+
+// GetUpgradePlan returns the scheduled upgrade of a chain, or nil when none is
+func (s *MemoryStore) GetUpgradePlan(ctx context.Context, chainName string) (*types.UpgradePlan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plan, ok := s.state.upgrades[chainName]
+	if !ok {
+		return nil, nil
+	}
+	return &plan, nil
+}
+
+// GetChainParams returns the latest parameters of a chain's module
+func (s *MemoryStore) GetChainParams(ctx context.Context, chainName, module string) (*types.ChainParams, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	params, ok := s.state.params[memKey{chain: chainName, a: module}]
+	if !ok {
+		return nil, fmt.Errorf("%s params: %w", module, ErrNotFound)
+	}
+	params.Params = maps.Clone(params.Params)
+	return &params, nil
+}
+
+// GetChainParamChanges returns the latest parameter changes of a chain's
+// module, newest first
+func (s *MemoryStore) GetChainParamChanges(ctx context.Context, chainName, module string, limit int) ([]types.ChainParamChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	changes := []types.ChainParamChange{}
+	for i := len(s.state.paramChanges) - 1; i >= 0 && len(changes) < limit; i-- {
+		change := s.state.paramChanges[i]
+		if change.ChainName == chainName && change.Module == module {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// RecordAnomaly records an anomaly, and reports whether it was not already
+func (s *MemoryStore) RecordAnomaly(ctx context.Context, anomaly types.Anomaly) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, recorded := range s.state.anomalies {
+		if recorded.ChainName == anomaly.ChainName && recorded.Metric == anomaly.Metric && recorded.Hour.Equal(anomaly.Hour) {
+			return false, nil
+		}
+	}
+	s.state.anomalies = append(s.state.anomalies, anomaly)
+	return true, nil
+}
+
+// GetAnomalies returns the anomalies of a chain in the hours since a time,
+// newest first
+func (s *MemoryStore) GetAnomalies(ctx context.Context, chainName string, since time.Time) ([]types.Anomaly, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	anomalies := []types.Anomaly{}
+	for _, anomaly := range s.state.anomalies {
+		if anomaly.ChainName == chainName && !anomaly.Hour.Before(since) {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	sort.Slice(anomalies, func(i, j int) bool {
+		if !anomalies[i].Hour.Equal(anomalies[j].Hour) {
+			return anomalies[i].Hour.After(anomalies[j].Hour)
+		}
+		return anomalies[i].Metric < anomalies[j].Metric
+	})
+	return anomalies, nil
+}
+
+// RecordHalt records a halt when it is detected and again when it ends,
+// keeping the earliest start and detection of a chain's halt at a height
+func (s *MemoryStore) RecordHalt(ctx context.Context, halt types.ChainHalt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, recorded := range s.state.halts {
+		if recorded.ChainName != halt.ChainName || recorded.Height != halt.Height {
+			continue
+		}
+		if halt.Since.After(recorded.Since) {
+			halt.Since = recorded.Since
+		}
+		if halt.DetectedAt.After(recorded.DetectedAt) {
+			halt.DetectedAt = recorded.DetectedAt
+		}
+		if halt.ResumedAt == nil {
+			halt.ResumedAt = recorded.ResumedAt
+		}
+		s.state.halts[i] = halt
+		return nil
+	}
+	s.state.halts = append(s.state.halts, halt)
+	return nil
+}
+
+// GetHalts returns the halts of a chain ongoing at or since a time, newest
+// first
+func (s *MemoryStore) GetHalts(ctx context.Context, chainName string, since time.Time) ([]types.ChainHalt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	halts := []types.ChainHalt{}
+	for _, halt := range s.state.halts {
+		if halt.ChainName == chainName && (halt.ResumedAt == nil || !halt.ResumedAt.Before(since)) {
+			halts = append(halts, halt)
+		}
+	}
+	sort.Slice(halts, func(i, j int) bool { return halts[i].Since.After(halts[j].Since) })
+	return halts, nil
+}
+
+// proposalKey keys a proposal in the in-memory store
+func proposalKey(chainName string, proposalID uint64) memKey {
+	return memKey{chain: chainName, a: strconv.FormatUint(proposalID, 10)}
+}
+
+// GetUnbondingCompletions returns the unbonding entries of the given
+// delegators on a chain that complete between from and until, soonest first.
+// A zero until leaves the window open.
+func (s *MemoryStore) GetUnbondingCompletions(ctx context.Context, chainName string, delegators []string, from, until time.Time) ([]types.UnbondingCompletion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	completions := []types.UnbondingCompletion{}
+	for _, unbonding := range s.state.unbondings[chainName] {
+		if !slices.Contains(delegators, unbonding.DelegatorAddress) {
+			continue
+		}
+		for _, entry := range unbonding.Entries {
+			if entry.CompletionTime.Before(from) || (!until.IsZero() && !entry.CompletionTime.Before(until)) {
+				continue
+			}
+			completions = append(completions, types.UnbondingCompletion{
+				ChainName:        chainName,
+				DelegatorAddress: unbonding.DelegatorAddress,
+				ValidatorAddress: unbonding.ValidatorAddress,
+				CreationHeight:   entry.CreationHeight,
+				Balance:          entry.Balance,
+				CompletionTime:   entry.CompletionTime,
+			})
+		}
+	}
+	sort.Slice(completions, func(i, j int) bool {
+		return completions[i].CompletionTime.Before(completions[j].CompletionTime)
+	})
+	return completions, nil
+}
+
+// GetUnbondingSchedule returns the unbonding balance completing per day from now
+// until the last pending entry
+func (s *MemoryStore) GetUnbondingSchedule(ctx context.Context, chainName string) ([]types.UnbondingBucket, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	amounts := make(map[time.Time]*big.Int)
+	counts := make(map[time.Time]int64)
+	for _, unbonding := range s.state.unbondings[chainName] {
+		for _, entry := range unbonding.Entries {
+			if !entry.CompletionTime.After(now) {
+				continue
+			}
+			day := entry.CompletionTime.UTC().Truncate(24 * time.Hour)
+			if amounts[day] == nil {
+				amounts[day] = new(big.Int)
+			}
+			if balance, ok := new(big.Int).SetString(entry.Balance, 10); ok {
+				amounts[day].Add(amounts[day], balance)
+			}
+			counts[day]++
+		}
+	}
+
+	buckets := []types.UnbondingBucket{}
+	for day, amount := range amounts {
+		buckets = append(buckets, types.UnbondingBucket{
+			ChainName: chainName,
+			Date:      day,
+			Amount:    amount.String(),
+			Entries:   counts[day],
+		})
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Date.Before(buckets[j].Date) })
+
+	return buckets, nil
+}
+
+// GetConsumerChain returns the consumer chain with the given chain ID, from the
+// provider that last reported it
+func (s *MemoryStore) GetConsumerChain(ctx context.Context, chainID string) (*types.ConsumerChain, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found *types.ConsumerChain
+	for _, consumers := range s.state.consumers {
+		for i := range consumers {
+			if consumers[i].ChainID == chainID && (found == nil || consumers[i].UpdatedAt.After(found.UpdatedAt)) {
+				found = &consumers[i]
+			}
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("consumer chain %s: %w", chainID, ErrNotFound)
+	}
+
+	consumer := *found
+	consumer.Validators = append([]types.ConsumerValidator{}, found.Validators...)
+	return &consumer, nil
+}
+
+// GetSupply returns the total supply of a chain ordered by denom
+func (s *MemoryStore) GetSupply(ctx context.Context, chainName string) ([]types.Supply, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var supply []types.Supply
+	for key, coin := range s.state.supply {
+		if key.chain == chainName {
+			supply = append(supply, coin)
+		}
+	}
+	sort.Slice(supply, func(i, j int) bool { return supply[i].Denom < supply[j].Denom })
+
+	return supply, nil
+}
+
+// GetCommunityPool returns the community pool of a chain ordered by denom
+func (s *MemoryStore) GetCommunityPool(ctx context.Context, chainName string) ([]types.PoolBalance, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]types.PoolBalance(nil), s.state.pool[chainName]...), nil
+}
+
+// GetModuleAccounts returns the module accounts of a chain ordered by name
+func (s *MemoryStore) GetModuleAccounts(ctx context.Context, chainName string) ([]types.ModuleAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]types.ModuleAccount(nil), s.state.modules[chainName]...), nil
+}
+
+// GetVestingLocked returns the total amount of a denom locked in the vesting
+// accounts of a chain
+func (s *MemoryStore) GetVestingLocked(ctx context.Context, chainName, denom string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	total := new(big.Int)
+	for _, locked := range s.state.vesting[chainName] {
+		if locked.Denom == denom {
+			total.Add(total, parseAmount(locked.Amount))
+		}
+	}
+	return total.String(), nil
+}
+
+// GetMintParams returns the mint parameters of a chain, or nil when none are stored
+func (s *MemoryStore) GetMintParams(ctx context.Context, chainName string) (*types.MintParams, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	params, ok := s.state.mint[chainName]
+	if !ok {
+		return nil, nil
+	}
+	return &params, nil
+}
+
+// GetLatestBlockHeight returns the highest stored block height of a chain, or 0
+// when no blocks are stored
+func (s *MemoryStore) GetLatestBlockHeight(ctx context.Context, chainName string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var height int64
+	for _, block := range s.state.blocks {
+		if block.ChainName == chainName && block.Height > height {
+			height = block.Height
+		}
+	}
+	return height, nil
+}
+
+// GetBlocks returns the most recent blocks of a chain below the given height,
+// or the most recent blocks overall when before is 0
+func (s *MemoryStore) GetBlocks(ctx context.Context, chainName string, before int64, limit int) ([]types.Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blocks := []types.Block{}
+	for _, block := range s.state.blocks {
+		if block.ChainName == chainName && (before == 0 || block.Height < before) {
+			blocks = append(blocks, block)
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Height > blocks[j].Height })
+	if len(blocks) > limit {
+		blocks = blocks[:limit]
+	}
+
+	return blocks, nil
+}
+
+// GetBlock returns the block of a chain at the given height
+func (s *MemoryStore) GetBlock(ctx context.Context, chainName string, height int64) (*types.Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	block, ok := s.state.blocks[memKey{chain: chainName, a: fmt.Sprint(height)}]
+	if !ok {
+		return nil, fmt.Errorf("block %d: %w", height, ErrNotFound)
+	}
+	return &block, nil
+}
+
+// Search finds validators by moniker, denoms, and validator and account addresses
+// by prefix. Results are ordered by how much of the label the term covers.
+func (s *MemoryStore) Search(ctx context.Context, term string, chains []string, limit int) ([]types.SearchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	selected := func(chain string) bool {
+		if len(chains) == 0 {
+			return true
+		}
+		for _, name := range chains {
+			if name == chain {
+				return true
+			}
+		}
+		return false
+	}
+	lower := strings.ToLower(term)
+	score := func(label string) float64 {
+		return float64(len(term)) / float64(len(label))
+	}
+
+	// A validator matching both by moniker and by address is listed once, with
+	// its best score
+	results := []types.SearchResult{}
+	found := make(map[[3]string]int)
+	add := func(kind, chain, id, label string, score float64) {
+		key := [3]string{kind, chain, id}
+		if i, ok := found[key]; ok {
+			results[i].Score = max(results[i].Score, score)
+			return
+		}
+		found[key] = len(results)
+		results = append(results, types.SearchResult{
+			Kind:      kind,
+			ChainName: chain,
+			ID:        id,
+			Label:     label,
+			Score:     score,
+		})
+	}
+
+	for key, validator := range s.state.validators {
+		if !selected(key.chain) {
+			continue
+		}
+		moniker := validator.Description.Moniker
+		if moniker != "" && strings.Contains(strings.ToLower(moniker), lower) {
+			add(types.SearchKindValidator, key.chain, validator.OperatorAddress, moniker, score(moniker))
+		}
+		if strings.HasPrefix(validator.OperatorAddress, term) {
+			add(types.SearchKindValidator, key.chain, validator.OperatorAddress, moniker, 1)
+		}
+	}
+	for key, coin := range s.state.supply {
+		if selected(key.chain) && strings.Contains(strings.ToLower(coin.Denom), lower) {
+			add(types.SearchKindDenom, key.chain, coin.Denom, coin.Denom, score(coin.Denom))
+		}
+	}
+	for key := range s.state.accounts {
+		if selected(key.chain) && strings.HasPrefix(key.a, term) {
+			add(types.SearchKindAddress, key.chain, key.a, key.a, 1)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Label < results[j].Label
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// PruneBalanceHistory is a no-op; the in-memory store keeps no history
+func (s *MemoryStore) PruneBalanceHistory(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return 0, nil
+}
+
+// PruneDelegationHistory is a no-op; the in-memory store keeps no history
+func (s *MemoryStore) PruneDelegationHistory(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return 0, nil
+}
+
+// PruneDustHistory is a no-op; the in-memory store keeps no history
+func (s *MemoryStore) PruneDustHistory(ctx context.Context, threshold string, batchSize int) (int64, error) {
+	return 0, nil
+}
+
+// PruneStaleAccounts deletes accounts not updated since the cutoff that hold no
+// balance and no delegations, along with their zero balance rows. It returns
+// the accounts and balances deleted.
+func (s *MemoryStore) PruneStaleAccounts(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stale := make(map[memKey]bool)
+	for key, account := range s.state.accounts {
+		if account.UpdatedAt.Before(before) {
+			stale[key] = true
+		}
+	}
+
+	for key, balance := range s.state.balances {
+		if compareDecimal(balance.Amount, "0") != 0 {
+			delete(stale, memKey{chain: key.chain, a: key.a})
+		}
+	}
+	for key := range s.state.delegations {
+		delete(stale, memKey{chain: key.chain, a: key.a})
+	}
+
+	var deleted int64
+	for key := range s.state.balances {
+		if stale[memKey{chain: key.chain, a: key.a}] {
+			delete(s.state.balances, key)
+			deleted++
+		}
+	}
+	for key := range stale {
+		delete(s.state.accounts, key)
+	}
+
+	return deleted + int64(len(stale)), nil
+}
+
+// compareDecimal compares two decimal strings, treating unparsable values as zero
+func compareDecimal(a, b string) int {
+	x, ok := new(big.Float).SetString(a)
+	if !ok {
+		x = new(big.Float)
+	}
+	y, ok := new(big.Float).SetString(b)
+	if !ok {
+		y = new(big.Float)
+	}
+	return x.Cmp(y)
+}
+
+// sharesToTokens estimates the tokens backing delegator shares from a
+// validator's token/share ratio, truncated to an integer
+func sharesToTokens(shares, tokens, delegatorShares string) string {
+	s, ok1 := new(big.Float).SetPrec(256).SetString(shares)
+	t, ok2 := new(big.Float).SetPrec(256).SetString(tokens)
+	d, ok3 := new(big.Float).SetPrec(256).SetString(delegatorShares)
+	if !ok1 || !ok2 || !ok3 || d.Sign() == 0 {
+		return "0"
+	}
+
+	result, _ := s.Mul(s, t).Quo(s, d).Int(nil)
+	return result.String()
+}
+
+// memoryTx buffers writes to a MemoryStore until commit
+type memoryTx struct {
+	store *MemoryStore
+	ops   []func(*memoryState)
+	done  bool
+}
+
+// Commit applies the buffered writes
+func (tx *memoryTx) Commit() error {
+	if tx.done {
+		return fmt.Errorf("transaction already finished")
+	}
+	tx.done = true
+
+	tx.store.mu.Lock()
+	defer tx.store.mu.Unlock()
+	for _, op := range tx.ops {
+		op(&tx.store.state)
+	}
+	return nil
+}
+
+// Rollback discards the buffered writes
+func (tx *memoryTx) Rollback() error {
+	tx.done = true
+	tx.ops = nil
+	return nil
+}
+
+// apply buffers a write
+func (tx *memoryTx) apply(op func(*memoryState)) error {
+	if tx.done {
+		return fmt.Errorf("transaction already finished")
+	}
+	tx.ops = append(tx.ops, op)
+	return nil
+}
+
+// UpsertChain registers a chain or updates its status. A zero LatestHeight keeps
+// the stored height.
+func (tx *memoryTx) UpsertChain(ctx context.Context, chain *types.ChainInfo) error {
+	c := *chain
+	return tx.apply(func(state *memoryState) {
+		if existing, ok := state.chains[c.Name]; ok && c.LatestHeight == 0 {
+			c.LatestHeight = existing.LatestHeight
+			c.LatestTime = existing.LatestTime
+		}
+		state.chains[c.Name] = c
+	})
+}
+
+// UpsertChainStatus inserts or updates a chain's sync status, keeping the
+// highest last ingested height
+func (tx *memoryTx) UpsertChainStatus(ctx context.Context, status *types.ChainStatus) error {
+	st := *status
+	return tx.apply(func(state *memoryState) {
+		if existing, ok := state.chainStatus[st.ChainName]; ok && existing.LastIngestedHeight > st.LastIngestedHeight {
+			st.LastIngestedHeight = existing.LastIngestedHeight
+		}
+		state.chainStatus[st.ChainName] = st
+	})
+}
+
+// UpsertAccount registers an account seen at account.Height, keeping the
+// earliest first-seen and the latest seen height
+func (tx *memoryTx) UpsertAccount(ctx context.Context, account *types.Account) error {
+	a := *account
+	a.FirstSeenHeight = a.Height
+	return tx.apply(func(state *memoryState) {
+		key := memKey{chain: a.ChainName, a: a.Address}
+		if existing, ok := state.accounts[key]; ok {
+			a.CreatedAt = existing.CreatedAt
+			a.FirstSeenHeight = min(existing.FirstSeenHeight, a.FirstSeenHeight)
+			a.Height = max(existing.Height, a.Height)
+		}
+		state.accounts[key] = a
+	})
+}
+
+// UpsertBalance inserts or updates a balance
+func (tx *memoryTx) UpsertBalance(ctx context.Context, balance *types.Balance) error {
+	b := *balance
+	return tx.apply(func(state *memoryState) {
+		key := memKey{chain: b.ChainName, a: b.Address, b: b.Denom}
+		if old, ok := state.balances[key]; ok && old.Height > b.Height {
+			return
+		}
+		state.balances[key] = b
+	})
+}
+
+// UpsertBalances inserts or updates multiple balances
+func (tx *memoryTx) UpsertBalances(ctx context.Context, balances []types.Balance) error {
+	for i := range balances {
+		if err := tx.UpsertBalance(ctx, &balances[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpsertDelegation inserts or updates a delegation
+func (tx *memoryTx) UpsertDelegation(ctx context.Context, delegation *types.Delegation) error {
+	d := *delegation
+	return tx.apply(func(state *memoryState) {
+		key := memKey{chain: d.ChainName, a: d.DelegatorAddress, b: d.ValidatorAddress}
+		if old, ok := state.delegations[key]; ok && old.Height > d.Height {
+			return
+		}
+		state.delegations[key] = d
+	})
+}
+
+// DeleteDelegation removes a delegation that no longer exists on chain
+func (tx *memoryTx) DeleteDelegation(ctx context.Context, chainName, delegatorAddress, validatorAddress string) error {
+	return tx.apply(func(state *memoryState) {
+		delete(state.delegations, memKey{chain: chainName, a: delegatorAddress, b: validatorAddress})
+	})
+}
+
+// UpsertTokenContracts inserts or updates the metadata of token contracts
+func (tx *memoryTx) UpsertTokenContracts(ctx context.Context, contracts []types.TokenContract) error {
+	snapshot := append([]types.TokenContract(nil), contracts...)
+	return tx.apply(func(state *memoryState) {
+		for _, contract := range snapshot {
+			key := memKey{chain: contract.ChainName, a: contract.Contract}
+			if old, ok := state.contracts[key]; ok && old.Height > contract.Height {
+				continue
+			}
+			state.contracts[key] = contract
+		}
+	})
+}
+
+// UpsertTokenHoldings inserts or updates token holdings
+func (tx *memoryTx) UpsertTokenHoldings(ctx context.Context, holdings []types.TokenHolding) error {
+	snapshot := make([]types.TokenHolding, len(holdings))
+	for i, holding := range holdings {
+		holding.TokenIDs = append([]string(nil), holding.TokenIDs...)
+		snapshot[i] = holding
+	}
+	return tx.apply(func(state *memoryState) {
+		for _, holding := range snapshot {
+			key := memKey{chain: holding.ChainName, a: holding.Address, b: holding.Contract}
+			if old, ok := state.holdings[key]; ok && old.Height > holding.Height {
+				continue
+			}
+			state.holdings[key] = holding
+		}
+	})
+}
+
+// ReplaceUnbondingDelegations replaces all stored unbonding delegation entries of a chain
+func (tx *memoryTx) ReplaceUnbondingDelegations(ctx context.Context, chainName string, unbondings []types.UnbondingDelegation) error {
+	snapshot := append([]types.UnbondingDelegation(nil), unbondings...)
+	return tx.apply(func(state *memoryState) {
+		state.unbondings[chainName] = snapshot
+	})
+}
+
+// ReplaceRedelegations replaces all stored redelegation entries of a chain
+func (tx *memoryTx) ReplaceRedelegations(ctx context.Context, chainName string, redelegations []types.Redelegation) error {
+	snapshot := append([]types.Redelegation(nil), redelegations...)
+	return tx.apply(func(state *memoryState) {
+		state.redelegations[chainName] = snapshot
+	})
+}
+
+// UpsertValidator inserts or updates a validator
+func (tx *memoryTx) UpsertValidator(ctx context.Context, validator *types.Validator) error {
+	v := *validator
+	return tx.apply(func(state *memoryState) {
+		key := memKey{chain: v.ChainName, a: v.OperatorAddress}
+		if old, ok := state.validators[key]; ok && old.Height > v.Height {
+			return
+		}
+		state.validators[key] = v
+	})
+}
+
+// InsertCommissionChange records a detected commission rate change. A change
+// recorded again at the same height is ignored.
+func (tx *memoryTx) InsertCommissionChange(ctx context.Context, change *types.CommissionChange) error {
+	c := *change
+	return tx.apply(func(state *memoryState) {
+		for _, existing := range state.commissions {
+			if existing.change.ChainName == c.ChainName && existing.change.OperatorAddress == c.OperatorAddress &&
+				existing.change.Height == c.Height {
+				return
+			}
+		}
+		state.commissions = append(state.commissions, memoryCommissionChange{change: c})
+	})
+}
+
+// UpsertConsensusAddress records the consensus address used by a validator
+func (tx *memoryTx) UpsertConsensusAddress(ctx context.Context, chainName, consensusAddress, operatorAddress string, height int64) error {
+	return tx.apply(func(state *memoryState) {
+		state.consensus[memKey{chain: chainName, a: consensusAddress}] = operatorAddress
+	})
+}
+
+// ReplaceConsumerChains replaces the consumer chains of a provider chain
+func (tx *memoryTx) ReplaceConsumerChains(ctx context.Context, providerChain string, consumers []types.ConsumerChain) error {
+	snapshot := make([]types.ConsumerChain, len(consumers))
+	for i, consumer := range consumers {
+		consumer.ProviderChain = providerChain
+		consumer.Validators = append([]types.ConsumerValidator(nil), consumer.Validators...)
+		snapshot[i] = consumer
+	}
+	return tx.apply(func(state *memoryState) {
+		state.consumers[providerChain] = snapshot
+	})
+}
+
+// UpsertSupply inserts or updates the total supply of multiple denoms
+func (tx *memoryTx) UpsertSupply(ctx context.Context, supply []types.Supply) error {
+	coins := append([]types.Supply(nil), supply...)
+	return tx.apply(func(state *memoryState) {
+		for _, coin := range coins {
+			key := memKey{chain: coin.ChainName, a: coin.Denom}
+			if old, ok := state.supply[key]; ok && old.Height > coin.Height {
+				continue
+			}
+			state.supply[key] = coin
+		}
+	})
+}
+
+// ReplaceCommunityPool replaces the community pool of a chain
+func (tx *memoryTx) ReplaceCommunityPool(ctx context.Context, chainName string, pool []types.PoolBalance) error {
+	snapshot := append([]types.PoolBalance(nil), pool...)
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Denom < snapshot[j].Denom })
+	return tx.apply(func(state *memoryState) {
+		state.pool[chainName] = snapshot
+	})
+}
+
+// ReplaceModuleAccounts replaces the module accounts of a chain
+func (tx *memoryTx) ReplaceModuleAccounts(ctx context.Context, chainName string, accounts []types.ModuleAccount) error {
+	snapshot := append([]types.ModuleAccount(nil), accounts...)
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Name < snapshot[j].Name })
+	return tx.apply(func(state *memoryState) {
+		state.modules[chainName] = snapshot
+	})
+}
+
+// ReplaceVestingLocked replaces the vesting locked amounts of a chain
+func (tx *memoryTx) ReplaceVestingLocked(ctx context.Context, chainName string, locked []types.VestingLocked) error {
+	snapshot := append([]types.VestingLocked(nil), locked...)
+	return tx.apply(func(state *memoryState) {
+		state.vesting[chainName] = snapshot
+	})
+}
+
+// UpsertMintParams inserts or updates mint parameters
+func (tx *memoryTx) UpsertMintParams(ctx context.Context, params *types.MintParams) error {
+	p := *params
+	return tx.apply(func(state *memoryState) {
+		if old, ok := state.mint[p.ChainName]; ok && old.Height > p.Height {
+			return
+		}
+		state.mint[p.ChainName] = p
+	})
+}
+
+// UpsertProposals inserts or updates governance proposals
+func (tx *memoryTx) UpsertProposals(ctx context.Context, proposals []types.Proposal) error {
+	snapshot := append([]types.Proposal(nil), proposals...)
+	return tx.apply(func(state *memoryState) {
+		for _, proposal := range snapshot {
+			key := proposalKey(proposal.ChainName, proposal.ProposalID)
+			if old, ok := state.proposals[key]; ok && old.Height > proposal.Height {
+				continue
+			}
+			state.proposals[key] = proposal
+		}
+	})
+}
+
+// ReplaceUpgradePlan replaces the scheduled upgrade of a chain, removing it
+// when plan is nil
+func (tx *memoryTx) ReplaceUpgradePlan(ctx context.Context, chainName string, plan *types.UpgradePlan) error {
+	if plan == nil {
+		return tx.apply(func(state *memoryState) {
+			delete(state.upgrades, chainName)
+		})
+	}
+	p := *plan
+	return tx.apply(func(state *memoryState) {
+		state.upgrades[chainName] = p
+	})
+}
+
+// UpsertChainParams stores the parameters of a chain's module, recording each
+// parameter that changed since the stored ones
+func (tx *memoryTx) UpsertChainParams(ctx context.Context, params *types.ChainParams) error {
+	p := *params
+	p.Params = maps.Clone(params.Params)
+	return tx.apply(func(state *memoryState) {
+		key := memKey{chain: p.ChainName, a: p.Module}
+		if old, ok := state.params[key]; ok {
+			if old.Height > p.Height {
+				return
+			}
+			state.paramChanges = append(state.paramChanges, diffParams(old.Params, &p)...)
+		}
+		state.params[key] = p
+	})
+}
+
+// InsertBlocks stores block headers, ignoring heights that are already stored
+func (tx *memoryTx) InsertBlocks(ctx context.Context, blocks []types.Block) error {
+	headers := append([]types.Block(nil), blocks...)
+	return tx.apply(func(state *memoryState) {
+		for _, block := range headers {
+			key := memKey{chain: block.ChainName, a: fmt.Sprint(block.Height)}
+			if _, ok := state.blocks[key]; !ok {
+				state.blocks[key] = block
+			}
+		}
+	})
+}
+
+// EnqueueOutbox buffers outbox messages, assigning their IDs on commit
+func (tx *memoryTx) EnqueueOutbox(ctx context.Context, messages []types.OutboxMessage) error {
+	queued := append([]types.OutboxMessage(nil), messages...)
+	return tx.apply(func(state *memoryState) {
+		for _, message := range queued {
+			state.outboxSeq++
+			message.ID = state.outboxSeq
+			message.CreatedAt = time.Now()
+			state.outbox = append(state.outbox, message)
+		}
+	})
+}
+
+// DeliverOutbox passes the oldest undelivered messages to deliver and drops
+// the ones it published
+func (s *MemoryStore) DeliverOutbox(ctx context.Context, limit int, deliver func([]types.OutboxMessage) (int, error)) (int, error) {
+	s.deliverMu.Lock()
+	defer s.deliverMu.Unlock()
+
+	s.mu.RLock()
+	messages := append([]types.OutboxMessage(nil), s.state.outbox[:min(limit, len(s.state.outbox))]...)
+	s.mu.RUnlock()
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	delivered, err := deliver(messages)
+	if delivered > 0 {
+		s.mu.Lock()
+		s.state.outbox = s.state.outbox[delivered:]
+		s.mu.Unlock()
+	}
+	return delivered, err
+}
+
+// PruneOutbox is a no-op; delivered messages are dropped right away
+func (s *MemoryStore) PruneOutbox(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return 0, nil
+}
+
+// CreateTenant stores a new tenant, assigning its ID
+func (s *MemoryStore) CreateTenant(ctx context.Context, tenant *types.Tenant) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.tenants.tenants {
+		if existing.Name == tenant.Name {
+			return fmt.Errorf("create tenant: %w", ErrExists)
+		}
+	}
+
+	tenant.ID = newID()
+	tenant.CreatedAt = time.Now().UTC()
+	s.tenants.tenants[tenant.ID] = *tenant
+	return nil
+}
+
+// GetTenants returns all tenants ordered by name
+func (s *MemoryStore) GetTenants(ctx context.Context) ([]types.Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenants := []types.Tenant{}
+	for _, tenant := range s.tenants.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return tenants, nil
+}
+
+// GetTenant returns a tenant by ID
+func (s *MemoryStore) GetTenant(ctx context.Context, id string) (*types.Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenant, ok := s.tenants.tenants[id]
+	if !ok {
+		return nil, fmt.Errorf("tenant %s: %w", id, ErrNotFound)
+	}
+	return &tenant, nil
+}
+
+// DeleteTenant removes a tenant together with everything it owns
+func (s *MemoryStore) DeleteTenant(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants.tenants[id]; !ok {
+		return fmt.Errorf("delete tenant: %w", ErrNotFound)
+	}
+	delete(s.tenants.tenants, id)
+	deleteOwned(s.tenants.apiKeys, id, func(key types.APIKey) string { return key.TenantID })
+	deleteOwned(s.tenants.watchlists, id, func(w types.Watchlist) string { return w.TenantID })
+	deleteOwned(s.tenants.portfolios, id, func(p types.Portfolio) string { return p.TenantID })
+	deleteOwned(s.tenants.webhooks, id, func(w types.Webhook) string { return w.TenantID })
+	deleteOwned(s.tenants.emails, id, func(e types.EmailSubscription) string { return e.TenantID })
+	deleteOwned(s.tenants.alertRules, id, func(r types.AlertRule) string { return r.TenantID })
+	deleteOwned(s.tenants.digests, id, func(d types.Digest) string { return d.TenantID })
+	for key := range s.tenants.reminders {
+		if key.tenantID == id {
+			delete(s.tenants.reminders, key)
+		}
+	}
+	return nil
+}
+
+// deleteOwned removes the rows of a tenant from a table
+func deleteOwned[T any](rows map[string]T, tenantID string, owner func(T) string) {
+	for id, row := range rows {
+		if owner(row) == tenantID {
+			delete(rows, id)
+		}
+	}
+}
+
+// ownedBy returns the rows of a tenant from a table ordered by less
+func ownedBy[T any](rows map[string]T, tenantID string, owner func(T) string, less func(a, b T) bool) []T {
+	owned := []T{}
+	for _, row := range rows {
+		if owner(row) == tenantID {
+			owned = append(owned, row)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool { return less(owned[i], owned[j]) })
+	return owned
+}
+
+// CreateAPIKey stores a new API key of a tenant, assigning its ID
+func (s *MemoryStore) CreateAPIKey(ctx context.Context, key *types.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants.tenants[key.TenantID]; !ok {
+		return fmt.Errorf("create API key: referenced row %w", ErrNotFound)
+	}
+
+	key.ID = newID()
+	key.CreatedAt = time.Now().UTC()
+	s.tenants.apiKeys[key.ID] = *key
+	return nil
+}
+
+// GetAPIKeys returns the API keys of a tenant, including revoked ones
+func (s *MemoryStore) GetAPIKeys(ctx context.Context, tenantID string) ([]types.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return ownedBy(s.tenants.apiKeys, tenantID,
+		func(key types.APIKey) string { return key.TenantID },
+		func(a, b types.APIKey) bool { return a.CreatedAt.Before(b.CreatedAt) }), nil
+}
+
+// GetAPIKeyByHash returns the API key with the given hash
+func (s *MemoryStore) GetAPIKeyByHash(ctx context.Context, keyHash string) (*types.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.tenants.apiKeys {
+		if key.KeyHash == keyHash {
+			return &key, nil
+		}
+	}
+	return nil, fmt.Errorf("API key: %w", ErrNotFound)
+}
+
+// RevokeAPIKey revokes an API key of a tenant
+func (s *MemoryStore) RevokeAPIKey(ctx context.Context, tenantID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.tenants.apiKeys[id]
+	if !ok || key.TenantID != tenantID || key.RevokedAt != nil {
+		return fmt.Errorf("revoke API key: %w", ErrNotFound)
+	}
+	now := time.Now().UTC()
+	key.RevokedAt = &now
+	s.tenants.apiKeys[id] = key
+	return nil
+}
+
+// CreateWatchlist stores a new watchlist of a tenant, assigning its ID
+func (s *MemoryStore) CreateWatchlist(ctx context.Context, watchlist *types.Watchlist) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants.tenants[watchlist.TenantID]; !ok {
+		return fmt.Errorf("create watchlist: referenced row %w", ErrNotFound)
+	}
+	for _, existing := range s.tenants.watchlists {
+		if existing.TenantID == watchlist.TenantID && existing.Name == watchlist.Name {
+			return fmt.Errorf("create watchlist: %w", ErrExists)
+		}
+	}
+
+	watchlist.ID = newID()
+	watchlist.CreatedAt = time.Now().UTC()
+	w := *watchlist
+	w.Addresses = append([]string(nil), watchlist.Addresses...)
+	s.tenants.watchlists[w.ID] = w
+	return nil
+}
+
+// GetWatchlists returns the watchlists of a tenant ordered by name
+func (s *MemoryStore) GetWatchlists(ctx context.Context, tenantID string) ([]types.Watchlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return ownedBy(s.tenants.watchlists, tenantID,
+		func(w types.Watchlist) string { return w.TenantID },
+		func(a, b types.Watchlist) bool { return a.Name < b.Name }), nil
+}
+
+// GetWatchlist returns a watchlist of a tenant
+func (s *MemoryStore) GetWatchlist(ctx context.Context, tenantID, id string) (*types.Watchlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	watchlist, ok := s.tenants.watchlists[id]
+	if !ok || watchlist.TenantID != tenantID {
+		return nil, fmt.Errorf("watchlist %s: %w", id, ErrNotFound)
+	}
+	return &watchlist, nil
+}
+
+// DeleteWatchlist removes a watchlist of a tenant and the alert rules using it
+func (s *MemoryStore) DeleteWatchlist(ctx context.Context, tenantID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watchlist, ok := s.tenants.watchlists[id]
+	if !ok || watchlist.TenantID != tenantID {
+		return fmt.Errorf("delete watchlist: %w", ErrNotFound)
+	}
+	delete(s.tenants.watchlists, id)
+	for ruleID, rule := range s.tenants.alertRules {
+		if rule.TenantID == tenantID && rule.WatchlistID == id {
+			delete(s.tenants.alertRules, ruleID)
+		}
+	}
+	return nil
+}
+
+// CreatePortfolio stores a new portfolio of a tenant, assigning its ID
+func (s *MemoryStore) CreatePortfolio(ctx context.Context, portfolio *types.Portfolio) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants.tenants[portfolio.TenantID]; !ok {
+		return fmt.Errorf("create portfolio: referenced row %w", ErrNotFound)
+	}
+	for _, existing := range s.tenants.portfolios {
+		if existing.TenantID == portfolio.TenantID && existing.Name == portfolio.Name {
+			return fmt.Errorf("create portfolio: %w", ErrExists)
+		}
+	}
+
+	portfolio.ID = newID()
+	portfolio.CreatedAt = time.Now().UTC()
+	p := *portfolio
+	p.Accounts = append([]types.PortfolioAccount(nil), portfolio.Accounts...)
+	s.tenants.portfolios[p.ID] = p
+	return nil
+}
+
+// GetPortfolios returns the portfolios of a tenant ordered by name
+func (s *MemoryStore) GetPortfolios(ctx context.Context, tenantID string) ([]types.Portfolio, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return ownedBy(s.tenants.portfolios, tenantID,
+		func(p types.Portfolio) string { return p.TenantID },
+		func(a, b types.Portfolio) bool { return a.Name < b.Name }), nil
+}
+
+// GetPortfolio returns a portfolio of a tenant
+func (s *MemoryStore) GetPortfolio(ctx context.Context, tenantID, id string) (*types.Portfolio, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	portfolio, ok := s.tenants.portfolios[id]
+	if !ok || portfolio.TenantID != tenantID {
+		return nil, fmt.Errorf("portfolio %s: %w", id, ErrNotFound)
+	}
+	return &portfolio, nil
+}
+
+// DeletePortfolio removes a portfolio of a tenant
+func (s *MemoryStore) DeletePortfolio(ctx context.Context, tenantID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	portfolio, ok := s.tenants.portfolios[id]
+	if !ok || portfolio.TenantID != tenantID {
+		return fmt.Errorf("delete portfolio: %w", ErrNotFound)
+	}
+	delete(s.tenants.portfolios, id)
+	return nil
+}
+
+// GetWatchedAddresses returns the addresses on any tenant's watchlists for a
+// chain in order
+func (s *MemoryStore) GetWatchedAddresses(ctx context.Context, chainName string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var addresses []string
+	for _, watchlist := range s.tenants.watchlists {
+		if watchlist.ChainName != chainName {
+			continue
+		}
+		for _, address := range watchlist.Addresses {
+			if !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	sort.Strings(addresses)
+
+	return addresses, nil
+}
+
+// CreateEmailSubscription stores a new email subscription of a tenant,
+// assigning its ID and unsubscribe token
+func (s *MemoryStore) CreateEmailSubscription(ctx context.Context, subscription *types.EmailSubscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants.tenants[subscription.TenantID]; !ok {
+		return fmt.Errorf("create email subscription: referenced row %w", ErrNotFound)
+	}
+	for _, existing := range s.tenants.emails {
+		if existing.TenantID == subscription.TenantID && existing.Email == subscription.Email {
+			return fmt.Errorf("create email subscription: %w", ErrExists)
+		}
+	}
+
+	subscription.ID = newID()
+	subscription.UnsubscribeToken = newToken()
+	subscription.CreatedAt = time.Now().UTC()
+	e := *subscription
+	e.Events = append([]string(nil), subscription.Events...)
+	s.tenants.emails[e.ID] = e
+	return nil
+}
+
+// GetEmailSubscriptions returns the email subscriptions of a tenant in
+// creation order
+func (s *MemoryStore) GetEmailSubscriptions(ctx context.Context, tenantID string) ([]types.EmailSubscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return ownedBy(s.tenants.emails, tenantID,
+		func(e types.EmailSubscription) string { return e.TenantID },
+		func(a, b types.EmailSubscription) bool { return a.CreatedAt.Before(b.CreatedAt) }), nil
+}
+
+// DeleteEmailSubscription removes an email subscription of a tenant
+func (s *MemoryStore) DeleteEmailSubscription(ctx context.Context, tenantID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription, ok := s.tenants.emails[id]
+	if !ok || subscription.TenantID != tenantID {
+		return fmt.Errorf("delete email subscription: %w", ErrNotFound)
+	}
+	delete(s.tenants.emails, id)
+	return nil
+}
+
+// Unsubscribe disables the email subscription of an unsubscribe token and
+// returns it
+func (s *MemoryStore) Unsubscribe(ctx context.Context, token string) (*types.EmailSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, subscription := range s.tenants.emails {
+		if subscription.UnsubscribeToken == token {
+			subscription.Enabled = false
+			s.tenants.emails[id] = subscription
+			return &subscription, nil
+		}
+	}
+	return nil, fmt.Errorf("unsubscribe token: %w", ErrNotFound)
+}
+
+// CreateWebhook stores a new webhook of a tenant, assigning its ID
+func (s *MemoryStore) CreateWebhook(ctx context.Context, webhook *types.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants.tenants[webhook.TenantID]; !ok {
+		return fmt.Errorf("create webhook: referenced row %w", ErrNotFound)
+	}
+
+	webhook.ID = newID()
+	webhook.CreatedAt = time.Now().UTC()
+	w := *webhook
+	w.Events = append([]string(nil), webhook.Events...)
+	s.tenants.webhooks[w.ID] = w
+	return nil
+}
+
+// GetWebhooks returns the webhooks of a tenant in creation order
+func (s *MemoryStore) GetWebhooks(ctx context.Context, tenantID string) ([]types.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return ownedBy(s.tenants.webhooks, tenantID,
+		func(w types.Webhook) string { return w.TenantID },
+		func(a, b types.Webhook) bool { return a.CreatedAt.Before(b.CreatedAt) }), nil
+}
+
+// GetWebhook returns a webhook of a tenant
+func (s *MemoryStore) GetWebhook(ctx context.Context, tenantID, id string) (*types.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	webhook, ok := s.tenants.webhooks[id]
+	if !ok || webhook.TenantID != tenantID {
+		return nil, fmt.Errorf("webhook %s: %w", id, ErrNotFound)
+	}
+	return &webhook, nil
+}
+
+// DeleteWebhook removes a webhook of a tenant and the alert rules and digests
+// using it
+func (s *MemoryStore) DeleteWebhook(ctx context.Context, tenantID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook, ok := s.tenants.webhooks[id]
+	if !ok || webhook.TenantID != tenantID {
+		return fmt.Errorf("delete webhook: %w", ErrNotFound)
+	}
+	delete(s.tenants.webhooks, id)
+	for ruleID, rule := range s.tenants.alertRules {
+		if rule.TenantID == tenantID && rule.WebhookID == id {
+			delete(s.tenants.alertRules, ruleID)
+		}
+	}
+	for digestID, digest := range s.tenants.digests {
+		if digest.TenantID == tenantID && digest.WebhookID == id {
+			delete(s.tenants.digests, digestID)
+		}
+	}
+	return nil
+}
+
+// CreateAlertRule stores a new alert rule of a tenant, assigning its ID. The
+// watchlist and webhook it references must belong to the same tenant.
+func (s *MemoryStore) CreateAlertRule(ctx context.Context, rule *types.AlertRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants.tenants[rule.TenantID]; !ok {
+		return fmt.Errorf("create alert rule: referenced row %w", ErrNotFound)
+	}
+	if rule.WatchlistID != "" {
+		if w, ok := s.tenants.watchlists[rule.WatchlistID]; !ok || w.TenantID != rule.TenantID {
+			return fmt.Errorf("create alert rule: referenced row %w", ErrNotFound)
+		}
+	}
+	if rule.WebhookID != "" {
+		if w, ok := s.tenants.webhooks[rule.WebhookID]; !ok || w.TenantID != rule.TenantID {
+			return fmt.Errorf("create alert rule: referenced row %w", ErrNotFound)
+		}
+	}
+	for _, existing := range s.tenants.alertRules {
+		if existing.TenantID == rule.TenantID && existing.Name == rule.Name {
+			return fmt.Errorf("create alert rule: %w", ErrExists)
+		}
+	}
+
+	rule.ID = newID()
+	rule.CreatedAt = time.Now().UTC()
+	s.tenants.alertRules[rule.ID] = *rule
+	return nil
+}
+
+// GetAlertRules returns the alert rules of a tenant ordered by name
+func (s *MemoryStore) GetAlertRules(ctx context.Context, tenantID string) ([]types.AlertRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return ownedBy(s.tenants.alertRules, tenantID,
+		func(r types.AlertRule) string { return r.TenantID },
+		func(a, b types.AlertRule) bool { return a.Name < b.Name }), nil
+}
+
+// DeleteAlertRule removes an alert rule of a tenant
+func (s *MemoryStore) DeleteAlertRule(ctx context.Context, tenantID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule, ok := s.tenants.alertRules[id]
+	if !ok || rule.TenantID != tenantID {
+		return fmt.Errorf("delete alert rule: %w", ErrNotFound)
+	}
+	delete(s.tenants.alertRules, id)
+	return nil
+}
+
+// CreateDigest stores a new digest of a tenant, assigning its ID. The webhook
+// it references must belong to the same tenant.
+func (s *MemoryStore) CreateDigest(ctx context.Context, digest *types.Digest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants.tenants[digest.TenantID]; !ok {
+		return fmt.Errorf("create digest: referenced row %w", ErrNotFound)
+	}
+	if digest.WebhookID != "" {
+		if w, ok := s.tenants.webhooks[digest.WebhookID]; !ok || w.TenantID != digest.TenantID {
+			return fmt.Errorf("create digest: referenced row %w", ErrNotFound)
+		}
+	}
+	for _, existing := range s.tenants.digests {
+		if existing.TenantID == digest.TenantID && existing.Name == digest.Name {
+			return fmt.Errorf("create digest: %w", ErrExists)
+		}
+	}
+
+	digest.ID = newID()
+	digest.CreatedAt = time.Now().UTC()
+	s.tenants.digests[digest.ID] = *digest
+	return nil
+}
+
+// GetDigests returns the digests of a tenant ordered by name
+func (s *MemoryStore) GetDigests(ctx context.Context, tenantID string) ([]types.Digest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return ownedBy(s.tenants.digests, tenantID,
+		func(d types.Digest) string { return d.TenantID },
+		func(a, b types.Digest) bool { return a.Name < b.Name }), nil
+}
+
+// GetEnabledDigests returns the enabled digests of every tenant
+func (s *MemoryStore) GetEnabledDigests(ctx context.Context) ([]types.Digest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	digests := []types.Digest{}
+	for _, digest := range s.tenants.digests {
+		if digest.Enabled {
+			digests = append(digests, digest)
+		}
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		if digests[i].TenantID != digests[j].TenantID {
+			return digests[i].TenantID < digests[j].TenantID
+		}
+		return digests[i].Name < digests[j].Name
+	})
+	return digests, nil
+}
+
+// DeleteDigest removes a digest of a tenant
+func (s *MemoryStore) DeleteDigest(ctx context.Context, tenantID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	digest, ok := s.tenants.digests[id]
+	if !ok || digest.TenantID != tenantID {
+		return fmt.Errorf("delete digest: %w", ErrNotFound)
+	}
+	delete(s.tenants.digests, id)
+	return nil
+}
+
+// ClaimDigest sets the time a digest was last sent to sentAt if it is still
+// previous, and reports whether it was
+func (s *MemoryStore) ClaimDigest(ctx context.Context, tenantID, id string, previous, sentAt *time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	digest, ok := s.tenants.digests[id]
+	if !ok || digest.TenantID != tenantID {
+		return false, nil
+	}
+	switch {
+	case digest.LastSentAt == nil && previous == nil:
+	case digest.LastSentAt != nil && previous != nil && digest.LastSentAt.Equal(*previous):
+	default:
+		return false, nil
+	}
+
+	if sentAt != nil {
+		at := *sentAt
+		sentAt = &at
+	}
+	digest.LastSentAt = sentAt
+	s.tenants.digests[id] = digest
+	return true, nil
+}
+
+// ClaimUnbondingReminder records that a tenant is reminded of an unbonding
+// entry, and reports whether it was not already
+func (s *MemoryStore) ClaimUnbondingReminder(ctx context.Context, tenantID string, completion types.UnbondingCompletion) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := newReminderKey(tenantID, completion)
+	if s.tenants.reminders[key] {
+		return false, nil
+	}
+	s.tenants.reminders[key] = true
+	return true, nil
+}
+
+// ReleaseUnbondingReminder removes the claim on a reminder
+func (s *MemoryStore) ReleaseUnbondingReminder(ctx context.Context, tenantID string, completion types.UnbondingCompletion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tenants.reminders, newReminderKey(tenantID, completion))
+	return nil
+}
+
+// PruneUnbondingReminders removes the reminders of entries that completed
+// before the given time
+func (s *MemoryStore) PruneUnbondingReminders(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pruned int64
+	for key := range s.tenants.reminders {
+		if key.completionTime < before.UnixNano() {
+			delete(s.tenants.reminders, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// UpsertLabel creates or replaces the label of an address, keeping the time it
+// was first labeled
+func (s *MemoryStore) UpsertLabel(ctx context.Context, label *types.AddressLabel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memKey{chain: label.ChainName, a: label.Address}
+	now := time.Now().UTC()
+	label.CreatedAt, label.UpdatedAt = now, now
+	if existing, ok := s.labels[key]; ok {
+		label.CreatedAt = existing.CreatedAt
+	}
+	if label.Tags == nil {
+		label.Tags = []string{}
+	}
+
+	l := *label
+	l.Tags = append([]string{}, label.Tags...)
+	s.labels[key] = l
+	return nil
+}
+
+// GetLabel returns the label of an address
+func (s *MemoryStore) GetLabel(ctx context.Context, chainName, address string) (*types.AddressLabel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	label, ok := s.labels[memKey{chain: chainName, a: address}]
+	if !ok {
+		return nil, fmt.Errorf("label of %s: %w", address, ErrNotFound)
+	}
+	return &label, nil
+}
+
+// GetLabels returns the labels matching a filter ordered by chain and address
+func (s *MemoryStore) GetLabels(ctx context.Context, filter types.LabelFilter) ([]types.AddressLabel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	labels := []types.AddressLabel{}
+	for _, label := range s.labels {
+		if filter.ChainName != "" && label.ChainName != filter.ChainName {
+			continue
+		}
+		if len(filter.Categories) > 0 && !slices.Contains(filter.Categories, label.Category) {
+			continue
+		}
+		if filter.Tag != "" && !slices.Contains(label.Tags, filter.Tag) {
+			continue
+		}
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].ChainName != labels[j].ChainName {
+			return labels[i].ChainName < labels[j].ChainName
+		}
+		return labels[i].Address < labels[j].Address
+	})
+
+	return labels, nil
+}
+
+// DeleteLabel removes the label of an address
+func (s *MemoryStore) DeleteLabel(ctx context.Context, chainName, address string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memKey{chain: chainName, a: address}
+	if _, ok := s.labels[key]; !ok {
+		return fmt.Errorf("delete label: %w", ErrNotFound)
+	}
+	delete(s.labels, key)
+	return nil
+}
+
+// maxMemoryIngestRuns is the number of ingest runs the in-memory store keeps
+const maxMemoryIngestRuns = 10000
+
+// InsertIngestRun stores the summary of an ingest cycle, assigning its ID. The
+// oldest runs are dropped beyond maxMemoryIngestRuns.
+func (s *MemoryStore) InsertIngestRun(ctx context.Context, run *types.IngestRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if run.Modules == nil {
+		run.Modules = []types.ModuleRun{}
+	}
+	s.ingestRunSeq++
+	run.ID = s.ingestRunSeq
+
+	r := *run
+	r.Modules = append([]types.ModuleRun{}, run.Modules...)
+	s.ingestRuns = append(s.ingestRuns, r)
+	if len(s.ingestRuns) > maxMemoryIngestRuns {
+		s.ingestRuns = s.ingestRuns[len(s.ingestRuns)-maxMemoryIngestRuns:]
+	}
+	return nil
+}
+
+// GetIngestRuns returns the ingest runs matching a filter, newest first
+func (s *MemoryStore) GetIngestRuns(ctx context.Context, filter types.IngestRunFilter) ([]types.IngestRun, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runs := []types.IngestRun{}
+	for _, run := range s.ingestRuns {
+		if filter.ChainName != "" && run.ChainName != filter.ChainName {
+			continue
+		}
+		if filter.Failed && run.Errors == 0 {
+			continue
+		}
+		if run.StartedAt.Before(filter.Since) {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].StartedAt.Equal(runs[j].StartedAt) {
+			return runs[i].StartedAt.After(runs[j].StartedAt)
+		}
+		return runs[i].ID > runs[j].ID
+	})
+	if filter.Limit > 0 && len(runs) > filter.Limit {
+		runs = runs[:filter.Limit]
+	}
+
+	return runs, nil
+}
+
+// PruneIngestRuns deletes the ingest runs started before the cutoff
+func (s *MemoryStore) PruneIngestRuns(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.ingestRuns[:0]
+	for _, run := range s.ingestRuns {
+		if !run.StartedAt.Before(before) {
+			kept = append(kept, run)
+		}
+	}
+	pruned := int64(len(s.ingestRuns) - len(kept))
+	s.ingestRuns = kept
+	return pruned, nil
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// SchemaVersion is the PostgreSQL migration the code requires. Migrations
+// record their number in schema_version; bump this with every migration.
+const SchemaVersion = 30
+
+// undefinedTable is the SQLSTATE of a query on a missing table
+const undefinedTable = "42P01"
+
+// migrationPollInterval is how often WaitForMigrations checks the schema
+const migrationPollInterval = 2 * time.Second
+
+// MigrationStore reports the version of the applied migrations
+type MigrationStore interface {
+	SchemaVersion(ctx context.Context) (int, error)
+}
+
+var (
+	_ MigrationStore = (*PostgresStore)(nil)
+	_ MigrationStore = (*CockroachStore)(nil)
+)
+
+// SchemaVersion returns the latest migration applied, or 0 when none recorded
+// its version
+func (s *PostgresStore) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == undefinedTable {
+		return 0, nil
+	}
+	return version, err
+}
+
+// CheckMigrations returns an error until the state store's migrations are
+// applied up to SchemaVersion. Stores without migrations always pass.
+func (m *Manager) CheckMigrations(ctx context.Context) error {
+	store, ok := m.state.(MigrationStore)
+	if !ok {
+		return nil
+	}
+
+	version, err := store.SchemaVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version < SchemaVersion {
+		return fmt.Errorf("database schema is at version %d, migrations up to %d must be applied", version, SchemaVersion)
+	}
+	return nil
+}
+
+// WaitForMigrations waits until the state store's migrations are applied, for
+// at most timeout
+func (m *Manager) WaitForMigrations(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(migrationPollInterval)
+	defer ticker.Stop()
+
+	logged := false
+	for {
+		err := m.CheckMigrations(ctx)
+		if err == nil {
+			return nil
+		}
+		if !logged {
+			m.logger.Info("Waiting for database migrations", zap.Error(err))
+			logged = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for migrations: %w", err)
+		case <-ticker.C:
+		}
+	}
+}
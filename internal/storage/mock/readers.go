@@ -0,0 +1,245 @@
+// Package mock implements the storage read models with functions, for
+// testing the API without a database. Each method calls its function field,
+// e.g. GetAccountStates calls GetAccountStatesFunc, and fails with
+// storage.ErrUnavailable when it is not set, so tests set only what they
+// expect to be read.
+package mock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+var (
+	_ storage.AccountReader   = (*AccountReader)(nil)
+	_ storage.ValidatorReader = (*ValidatorReader)(nil)
+	_ storage.GovReader       = (*GovReader)(nil)
+	_ storage.AnalyticsReader = (*AnalyticsReader)(nil)
+)
+
+// unset is the error of a method whose function is not set
+func unset(method string) error {
+	return fmt.Errorf("%s is not set: %w", method, storage.ErrUnavailable)
+}
+
+// AccountReader is a storage.AccountReader calling its functions
+type AccountReader struct {
+	GetAccountBalancesFunc    func(ctx context.Context, refs []storage.AccountRef) ([]types.Balance, error)
+	GetAccountDelegationsFunc func(ctx context.Context, refs []storage.AccountRef) ([]types.Delegation, error)
+	GetAccountUnbondingsFunc  func(ctx context.Context, refs []storage.AccountRef) ([]types.UnbondingCompletion, error)
+	GetAccountStatesFunc      func(ctx context.Context, refs []storage.AccountRef) ([]types.AccountState, error)
+	GetAccountActivityFunc    func(ctx context.Context, refs []storage.AccountRef, filter types.ActivityFilter, after string, limit int) ([]types.ActivityItem, error)
+	GetAccountRewardsFunc     func(ctx context.Context, refs []storage.AccountRef) ([]types.DelegatorRewards, error)
+	GetCrossChainAccountFunc  func(ctx context.Context, address string, refs []storage.AccountRef, timeout time.Duration) *types.CrossChainAccount
+	GetPortfolioHoldingsFunc  func(ctx context.Context, refs []storage.AccountRef, days int) ([]types.PortfolioHoldings, error)
+}
+
+func (r *AccountReader) GetAccountBalances(ctx context.Context, refs []storage.AccountRef) ([]types.Balance, error) {
+	if r.GetAccountBalancesFunc == nil {
+		return nil, unset("GetAccountBalances")
+	}
+	return r.GetAccountBalancesFunc(ctx, refs)
+}
+
+func (r *AccountReader) GetAccountDelegations(ctx context.Context, refs []storage.AccountRef) ([]types.Delegation, error) {
+	if r.GetAccountDelegationsFunc == nil {
+		return nil, unset("GetAccountDelegations")
+	}
+	return r.GetAccountDelegationsFunc(ctx, refs)
+}
+
+func (r *AccountReader) GetAccountUnbondings(ctx context.Context, refs []storage.AccountRef) ([]types.UnbondingCompletion, error) {
+	if r.GetAccountUnbondingsFunc == nil {
+		return nil, unset("GetAccountUnbondings")
+	}
+	return r.GetAccountUnbondingsFunc(ctx, refs)
+}
+
+func (r *AccountReader) GetAccountStates(ctx context.Context, refs []storage.AccountRef) ([]types.AccountState, error) {
+	if r.GetAccountStatesFunc == nil {
+		return nil, unset("GetAccountStates")
+	}
+	return r.GetAccountStatesFunc(ctx, refs)
+}
+
+func (r *AccountReader) GetAccountActivity(ctx context.Context, refs []storage.AccountRef, filter types.ActivityFilter, after string, limit int) ([]types.ActivityItem, error) {
+	if r.GetAccountActivityFunc == nil {
+		return nil, unset("GetAccountActivity")
+	}
+	return r.GetAccountActivityFunc(ctx, refs, filter, after, limit)
+}
+
+func (r *AccountReader) GetAccountRewards(ctx context.Context, refs []storage.AccountRef) ([]types.DelegatorRewards, error) {
+	if r.GetAccountRewardsFunc == nil {
+		return nil, unset("GetAccountRewards")
+	}
+	return r.GetAccountRewardsFunc(ctx, refs)
+}
+
+// GetCrossChainAccount reports every chain as failed when its function is
+// not set
+func (r *AccountReader) GetCrossChainAccount(ctx context.Context, address string, refs []storage.AccountRef, timeout time.Duration) *types.CrossChainAccount {
+	if r.GetCrossChainAccountFunc == nil {
+		account := &types.CrossChainAccount{Address: address, Chains: []types.AccountState{}, Totals: []types.CrossChainTotal{}}
+		for _, ref := range refs {
+			account.Errors = append(account.Errors, types.ChainError{ChainName: ref.ChainName, Error: unset("GetCrossChainAccount").Error()})
+		}
+		return account
+	}
+	return r.GetCrossChainAccountFunc(ctx, address, refs, timeout)
+}
+
+func (r *AccountReader) GetPortfolioHoldings(ctx context.Context, refs []storage.AccountRef, days int) ([]types.PortfolioHoldings, error) {
+	if r.GetPortfolioHoldingsFunc == nil {
+		return nil, unset("GetPortfolioHoldings")
+	}
+	return r.GetPortfolioHoldingsFunc(ctx, refs, days)
+}
+
+// ValidatorReader is a storage.ValidatorReader calling its functions
+type ValidatorReader struct {
+	EachValidatorFunc           func(ctx context.Context, chainName string, fn func(*types.Validator) error) error
+	GetValidatorFunc            func(ctx context.Context, chainName, operatorAddress string) (*types.Validator, error)
+	GetValidatorDelegatorsFunc  func(ctx context.Context, chainName, validatorAddress string, limit, offset int) ([]types.ValidatorDelegator, int64, error)
+	GetCommissionChangesFunc    func(ctx context.Context, chainName, operatorAddress string, limit int) ([]types.CommissionChange, error)
+	GetValidatorSetAtFunc       func(ctx context.Context, chain string, height int64) (*types.ValidatorSetSnapshot, error)
+	GetConsumerValidatorSetFunc func(ctx context.Context, chainID string) (*types.ConsumerValidatorSet, error)
+	GetCrossChainValidatorsFunc func(ctx context.Context, chains []string, timeout time.Duration) (map[string][]types.Validator, []types.ChainError)
+}
+
+func (r *ValidatorReader) EachValidator(ctx context.Context, chainName string, fn func(*types.Validator) error) error {
+	if r.EachValidatorFunc == nil {
+		return unset("EachValidator")
+	}
+	return r.EachValidatorFunc(ctx, chainName, fn)
+}
+
+func (r *ValidatorReader) GetValidator(ctx context.Context, chainName, operatorAddress string) (*types.Validator, error) {
+	if r.GetValidatorFunc == nil {
+		return nil, unset("GetValidator")
+	}
+	return r.GetValidatorFunc(ctx, chainName, operatorAddress)
+}
+
+func (r *ValidatorReader) GetValidatorDelegators(ctx context.Context, chainName, validatorAddress string, limit, offset int) ([]types.ValidatorDelegator, int64, error) {
+	if r.GetValidatorDelegatorsFunc == nil {
+		return nil, 0, unset("GetValidatorDelegators")
+	}
+	return r.GetValidatorDelegatorsFunc(ctx, chainName, validatorAddress, limit, offset)
+}
+
+func (r *ValidatorReader) GetCommissionChanges(ctx context.Context, chainName, operatorAddress string, limit int) ([]types.CommissionChange, error) {
+	if r.GetCommissionChangesFunc == nil {
+		return nil, unset("GetCommissionChanges")
+	}
+	return r.GetCommissionChangesFunc(ctx, chainName, operatorAddress, limit)
+}
+
+func (r *ValidatorReader) GetValidatorSetAt(ctx context.Context, chain string, height int64) (*types.ValidatorSetSnapshot, error) {
+	if r.GetValidatorSetAtFunc == nil {
+		return nil, unset("GetValidatorSetAt")
+	}
+	return r.GetValidatorSetAtFunc(ctx, chain, height)
+}
+
+func (r *ValidatorReader) GetConsumerValidatorSet(ctx context.Context, chainID string) (*types.ConsumerValidatorSet, error) {
+	if r.GetConsumerValidatorSetFunc == nil {
+		return nil, unset("GetConsumerValidatorSet")
+	}
+	return r.GetConsumerValidatorSetFunc(ctx, chainID)
+}
+
+// GetCrossChainValidators reports every chain as failed when its function is
+// not set
+func (r *ValidatorReader) GetCrossChainValidators(ctx context.Context, chains []string, timeout time.Duration) (map[string][]types.Validator, []types.ChainError) {
+	if r.GetCrossChainValidatorsFunc == nil {
+		var chainErrors []types.ChainError
+		for _, chain := range chains {
+			chainErrors = append(chainErrors, types.ChainError{ChainName: chain, Error: unset("GetCrossChainValidators").Error()})
+		}
+		return map[string][]types.Validator{}, chainErrors
+	}
+	return r.GetCrossChainValidatorsFunc(ctx, chains, timeout)
+}
+
+// GovReader is a storage.GovReader calling its functions
+type GovReader struct {
+	GetProposalsFunc       func(ctx context.Context, chainName, status string, limit, offset int) ([]types.Proposal, int64, error)
+	GetProposalFunc        func(ctx context.Context, chainName string, proposalID uint64) (*types.Proposal, error)
+	GetUpgradeCalendarFunc func(ctx context.Context, chains []string, timeout time.Duration) ([]types.ChainUpgrade, []types.ChainError)
+}
+
+func (r *GovReader) GetProposals(ctx context.Context, chainName, status string, limit, offset int) ([]types.Proposal, int64, error) {
+	if r.GetProposalsFunc == nil {
+		return nil, 0, unset("GetProposals")
+	}
+	return r.GetProposalsFunc(ctx, chainName, status, limit, offset)
+}
+
+func (r *GovReader) GetProposal(ctx context.Context, chainName string, proposalID uint64) (*types.Proposal, error) {
+	if r.GetProposalFunc == nil {
+		return nil, unset("GetProposal")
+	}
+	return r.GetProposalFunc(ctx, chainName, proposalID)
+}
+
+// GetUpgradeCalendar reports every chain as failed when its function is not
+// set
+func (r *GovReader) GetUpgradeCalendar(ctx context.Context, chains []string, timeout time.Duration) ([]types.ChainUpgrade, []types.ChainError) {
+	if r.GetUpgradeCalendarFunc == nil {
+		var chainErrors []types.ChainError
+		for _, chain := range chains {
+			chainErrors = append(chainErrors, types.ChainError{ChainName: chain, Error: unset("GetUpgradeCalendar").Error()})
+		}
+		return []types.ChainUpgrade{}, chainErrors
+	}
+	return r.GetUpgradeCalendarFunc(ctx, chains, timeout)
+}
+
+// AnalyticsReader is a storage.AnalyticsReader calling its functions
+type AnalyticsReader struct {
+	GetChainStatsFunc            func(ctx context.Context, chain string) (*types.ChainStats, error)
+	GetDailyActiveAddressesFunc  func(ctx context.Context, chainName string, days int) ([]types.DailyActiveAddresses, error)
+	GetDailyDelegationVolumeFunc func(ctx context.Context, chainName string, days int) ([]types.DailyDelegationVolume, error)
+	GetDailyRewardIssuanceFunc   func(ctx context.Context, chainName string, days int) ([]types.DailyRewardIssuance, error)
+	GetBalanceHistoryFunc        func(ctx context.Context, q storage.BalanceHistoryQuery) ([]types.BalancePoint, error)
+}
+
+func (r *AnalyticsReader) GetChainStats(ctx context.Context, chain string) (*types.ChainStats, error) {
+	if r.GetChainStatsFunc == nil {
+		return nil, unset("GetChainStats")
+	}
+	return r.GetChainStatsFunc(ctx, chain)
+}
+
+func (r *AnalyticsReader) GetDailyActiveAddresses(ctx context.Context, chainName string, days int) ([]types.DailyActiveAddresses, error) {
+	if r.GetDailyActiveAddressesFunc == nil {
+		return nil, unset("GetDailyActiveAddresses")
+	}
+	return r.GetDailyActiveAddressesFunc(ctx, chainName, days)
+}
+
+func (r *AnalyticsReader) GetDailyDelegationVolume(ctx context.Context, chainName string, days int) ([]types.DailyDelegationVolume, error) {
+	if r.GetDailyDelegationVolumeFunc == nil {
+		return nil, unset("GetDailyDelegationVolume")
+	}
+	return r.GetDailyDelegationVolumeFunc(ctx, chainName, days)
+}
+
+func (r *AnalyticsReader) GetDailyRewardIssuance(ctx context.Context, chainName string, days int) ([]types.DailyRewardIssuance, error) {
+	if r.GetDailyRewardIssuanceFunc == nil {
+		return nil, unset("GetDailyRewardIssuance")
+	}
+	return r.GetDailyRewardIssuanceFunc(ctx, chainName, days)
+}
+
+func (r *AnalyticsReader) GetBalanceHistory(ctx context.Context, q storage.BalanceHistoryQuery) ([]types.BalancePoint, error) {
+	if r.GetBalanceHistoryFunc == nil {
+		return nil, unset("GetBalanceHistory")
+	}
+	return r.GetBalanceHistoryFunc(ctx, q)
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/lib/pq"
+)
+
+// OutboxStore holds the Kafka messages written by state transactions until the
+// outbox relay has published them
+type OutboxStore interface {
+	// DeliverOutbox passes up to limit undelivered messages, oldest first, to
+	// deliver and marks the first n it reports as published delivered. Rows
+	// being delivered by another relay are skipped.
+	DeliverOutbox(ctx context.Context, limit int, deliver func([]types.OutboxMessage) (int, error)) (int, error)
+	PruneOutbox(ctx context.Context, before time.Time, batchSize int) (int64, error)
+}
+
+var (
+	_ OutboxStore = (*PostgresStore)(nil)
+	_ OutboxStore = (*CockroachStore)(nil)
+	_ OutboxStore = (*MemoryStore)(nil)
+)
+
+// EnqueueOutbox writes messages to the outbox, to be published once the
+// transaction commits
+func (tx *PostgresTx) EnqueueOutbox(ctx context.Context, messages []types.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.tx.PrepareContext(ctx, `
+		INSERT INTO outbox (message_key, payload, headers)
+		VALUES ($1, $2, $3)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare outbox insert statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, message := range messages {
+		headers, err := json.Marshal(message.Headers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox headers: %w", err)
+		}
+		if _, err := stmt.ExecContext(ctx, message.Key, message.Value, headers); err != nil {
+			return fmt.Errorf("failed to insert outbox message: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DeliverOutbox locks the oldest undelivered messages for the duration of the
+// delivery so concurrent relays publish disjoint batches
+func (s *PostgresStore) DeliverOutbox(ctx context.Context, limit int, deliver func([]types.OutboxMessage) (int, error)) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin outbox transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, message_key, payload, headers, created_at
+		FROM outbox
+		WHERE delivered_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query outbox: %w", err)
+	}
+
+	var messages []types.OutboxMessage
+	for rows.Next() {
+		var message types.OutboxMessage
+		var headers []byte
+		if err := rows.Scan(&message.ID, &message.Key, &message.Value, &headers, &message.CreatedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		if err := json.Unmarshal(headers, &message.Headers); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to unmarshal outbox headers: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	delivered, deliverErr := deliver(messages)
+	if delivered > 0 {
+		ids := make([]int64, delivered)
+		for i := range ids {
+			ids[i] = messages[i].ID
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE outbox SET delivered_at = NOW() WHERE id = ANY($1)
+		`, pq.Array(ids)); err != nil {
+			return 0, fmt.Errorf("failed to mark outbox messages delivered: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, fmt.Errorf("failed to commit outbox transaction: %w", err)
+		}
+	}
+
+	return delivered, deliverErr
+}
+
+// PruneOutbox deletes messages delivered before the cutoff
+func (s *PostgresStore) PruneOutbox(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return s.deleteInBatches(ctx, `
+		DELETE FROM outbox
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE delivered_at < $1
+			LIMIT $2
+		)
+	`, batchSize, before)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"go.uber.org/zap"
+)
+
+// GetChainOverviews returns the sync status, bonded tokens, tracked accounts
+// and events of the last 24 hours of every given chain. Chains not ingested
+// yet have no status. Chains that fail or exceed the timeout are listed in the
+// errors; event counts are left at zero without analytics storage.
+func (m *Manager) GetChainOverviews(ctx context.Context, chains []string, timeout time.Duration) ([]types.ChainOverview, []types.ChainError) {
+	overviews, _, chainErrors := perChain(ctx, chains, timeout, func(ctx context.Context, i int) (types.ChainOverview, error) {
+		overview := types.ChainOverview{ChainName: chains[i]}
+
+		info, err := m.state.GetChain(ctx, chains[i])
+		switch {
+		case errors.Is(err, ErrNotFound):
+		case err != nil:
+			return overview, err
+		default:
+			overview.LatestHeight = info.LatestHeight
+			overview.LatestBlockTime = info.LatestTime
+			overview.LastIngestedHeight = info.LastIngestedHeight
+			overview.CatchingUp = info.CatchingUp
+			overview.HaltedSince = info.HaltedSince
+		}
+
+		if _, _, overview.BondedTokens, err = m.state.GetValidatorSummary(ctx, chains[i]); err != nil {
+			return overview, err
+		}
+		if overview.AccountCount, err = m.state.CountAccounts(ctx, chains[i]); err != nil {
+			return overview, err
+		}
+		return overview, nil
+	})
+
+	if m.clickhouse != nil {
+		counts, err := m.clickhouse.GetEventCounts(ctx, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			m.logger.Warn("Failed to count events in ClickHouse", zap.Error(err))
+		}
+		for i := range overviews {
+			overviews[i].EventsPerDay = counts[chains[i]]
+		}
+	}
+	return overviews, chainErrors
+}
+
+// GetEventCounts returns the number of balance, delegation and redelegation
+// events recorded since the given time, by chain
+func (s *ClickHouseStore) GetEventCounts(ctx context.Context, since time.Time) (map[string]uint64, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT chain_name, count()
+		FROM (
+			SELECT chain_name FROM balance_events WHERE timestamp >= ?
+			UNION ALL
+			SELECT chain_name FROM delegation_events WHERE timestamp >= ?
+			UNION ALL
+			SELECT chain_name FROM redelegation_events WHERE timestamp >= ?
+		)
+		GROUP BY chain_name
+	`, since, since, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count events: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]uint64)
+	for rows.Next() {
+		var chain string
+		var count uint64
+		if err := rows.Scan(&chain, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan event count: %w", err)
+		}
+		counts[chain] = count
+	}
+	return counts, rows.Err()
+}
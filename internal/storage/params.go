@@ -0,0 +1,280 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// ParamStore reads the module parameters of the chains and their history
+type ParamStore interface {
+	GetChainParams(ctx context.Context, chainName, module string) (*types.ChainParams, error)
+	GetChainParamChanges(ctx context.Context, chainName, module string, limit int) ([]types.ChainParamChange, error)
+}
+
+var (
+	_ ParamStore = (*PostgresStore)(nil)
+	_ ParamStore = (*CockroachStore)(nil)
+	_ ParamStore = (*MemoryStore)(nil)
+)
+
+// UpsertChainParams stores the parameters of a chain's module, recording each
+// parameter that changed since the stored ones. Parameters older than the
+// stored ones are ignored.
+func (tx *PostgresTx) UpsertChainParams(ctx context.Context, params *types.ChainParams) error {
+	var stored []byte
+	var storedHeight int64
+	err := tx.tx.QueryRowContext(ctx, `
+		SELECT params, height FROM chain_params
+		WHERE chain_name = $1 AND module = $2
+		FOR UPDATE
+	`, params.ChainName, params.Module).Scan(&stored, &storedHeight)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get %s params: %w", params.Module, err)
+	}
+	if err == nil && storedHeight > params.Height {
+		return nil
+	}
+
+	if err == nil {
+		var old map[string]json.RawMessage
+		if err := json.Unmarshal(stored, &old); err != nil {
+			return fmt.Errorf("failed to decode stored %s params: %w", params.Module, err)
+		}
+		for _, change := range diffParams(old, params) {
+			_, err := tx.tx.ExecContext(ctx, `
+				INSERT INTO chain_param_changes (chain_name, module, param, old_value, new_value, height, changed_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+			`, change.ChainName, change.Module, change.Param, nullJSON(change.OldValue), nullJSON(change.NewValue),
+				change.Height, change.ChangedAt)
+			if err != nil {
+				return fmt.Errorf("failed to insert %s param change: %w", params.Module, err)
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(params.Params)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s params: %w", params.Module, err)
+	}
+	_, err = tx.tx.ExecContext(ctx, `
+		INSERT INTO chain_params (chain_name, module, params, height, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (chain_name, module)
+		DO UPDATE SET
+			params = EXCLUDED.params,
+			height = EXCLUDED.height,
+			updated_at = EXCLUDED.updated_at
+		WHERE EXCLUDED.height >= chain_params.height
+	`, params.ChainName, params.Module, encoded, params.Height, params.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert %s params: %w", params.Module, err)
+	}
+	return nil
+}
+
+// GetChainParams returns the latest parameters of a chain's module
+func (s *PostgresStore) GetChainParams(ctx context.Context, chainName, module string) (*types.ChainParams, error) {
+	params := types.ChainParams{ChainName: chainName, Module: module}
+	var encoded []byte
+	err := s.db.QueryRowContext(ctx, `
+		SELECT params, height, updated_at
+		FROM chain_params
+		WHERE chain_name = $1 AND module = $2
+	`, chainName, module).Scan(&encoded, &params.Height, &params.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%s params: %w", module, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s params: %w", module, err)
+	}
+
+	if err := json.Unmarshal(encoded, &params.Params); err != nil {
+		return nil, fmt.Errorf("failed to decode %s params: %w", module, err)
+	}
+	return &params, nil
+}
+
+// GetChainParamChanges returns the latest parameter changes of a chain's
+// module, newest first
+func (s *PostgresStore) GetChainParamChanges(ctx context.Context, chainName, module string, limit int) ([]types.ChainParamChange, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT param, old_value, new_value, height, changed_at
+		FROM chain_param_changes
+		WHERE chain_name = $1 AND module = $2
+		ORDER BY changed_at DESC, id DESC
+		LIMIT $3
+	`, chainName, module, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s param changes: %w", module, err)
+	}
+	defer rows.Close()
+
+	changes := []types.ChainParamChange{}
+	for rows.Next() {
+		change := types.ChainParamChange{ChainName: chainName, Module: module}
+		var oldValue, newValue []byte
+		if err := rows.Scan(&change.Param, &oldValue, &newValue, &change.Height, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan %s param change: %w", module, err)
+		}
+		change.OldValue, change.NewValue = oldValue, newValue
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// CompareChainParams compares the parameters of a module across the given
+// chains, by parameter name. Chains whose params are not ingested yet are
+// left out; chains that fail or exceed the timeout are listed in the errors.
+func (m *Manager) CompareChainParams(ctx context.Context, module string, chains []string, timeout time.Duration) ([]types.ParamComparison, []types.ChainError) {
+	results, _, chainErrors := perChain(ctx, chains, timeout, func(ctx context.Context, i int) (*types.ChainParams, error) {
+		params, err := m.Params().GetChainParams(ctx, chains[i], module)
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil // not ingested yet
+		}
+		return params, err
+	})
+
+	compared := 0
+	byParam := make(map[string]*types.ParamComparison)
+	for i, params := range results {
+		if params == nil {
+			continue
+		}
+		compared++
+		for key, value := range params.Params {
+			comparison, ok := byParam[key]
+			if !ok {
+				comparison = &types.ParamComparison{Param: key, Values: make(map[string]json.RawMessage)}
+				byParam[key] = comparison
+			}
+			comparison.Values[chains[i]] = value
+		}
+	}
+
+	comparisons := make([]types.ParamComparison, 0, len(byParam))
+	for _, comparison := range byParam {
+		comparison.Differs = len(comparison.Values) < compared
+		var first json.RawMessage
+		for _, value := range comparison.Values {
+			if first == nil {
+				first = value
+			} else if !jsonEqual(first, value) {
+				comparison.Differs = true
+			}
+		}
+		comparisons = append(comparisons, *comparison)
+	}
+	sort.Slice(comparisons, func(i, j int) bool {
+		return comparisons[i].Param < comparisons[j].Param
+	})
+	return comparisons, chainErrors
+}
+
+// GetParamMatrix compares the parameters of a module across the given chains
+// as a matrix, a row per parameter and a column per chain
+func (m *Manager) GetParamMatrix(ctx context.Context, module string, chains []string, timeout time.Duration) *types.ParamMatrix {
+	comparisons, chainErrors := m.CompareChainParams(ctx, module, chains, timeout)
+
+	matrix := &types.ParamMatrix{
+		Module: module,
+		Chains: chains,
+		Rows:   make([]types.ParamMatrixRow, len(comparisons)),
+		Errors: chainErrors,
+	}
+	for i, comparison := range comparisons {
+		row := types.ParamMatrixRow{
+			Param:   comparison.Param,
+			Values:  make([]*string, len(chains)),
+			Differs: comparison.Differs,
+		}
+		for j, chainName := range chains {
+			if value, ok := comparison.Values[chainName]; ok {
+				encoded := string(value)
+				row.Values[j] = &encoded
+			}
+		}
+		matrix.Rows[i] = row
+	}
+	return matrix
+}
+
+// diffParams lists the parameters that differ between the stored parameters
+// of a module and new ones, by name
+func diffParams(old map[string]json.RawMessage, params *types.ChainParams) []types.ChainParamChange {
+	keys := make(map[string]bool, len(params.Params))
+	for key := range old {
+		keys[key] = true
+	}
+	for key := range params.Params {
+		keys[key] = true
+	}
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	var changes []types.ChainParamChange
+	for _, name := range names {
+		oldValue, newValue := old[name], params.Params[name]
+		if jsonEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, types.ChainParamChange{
+			ChainName: params.ChainName,
+			Module:    params.Module,
+			Param:     name,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			Height:    params.Height,
+			ChangedAt: params.UpdatedAt,
+		})
+	}
+	return changes
+}
+
+// jsonEqual reports whether two JSON values are equal regardless of
+// formatting and key order, which JSONB does not preserve. Missing values
+// equal only each other.
+func jsonEqual(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if bytes.Equal(a, b) {
+		return true
+	}
+	va, errA := decodeJSON(a)
+	vb, errB := decodeJSON(b)
+	return errA == nil && errB == nil && reflect.DeepEqual(va, vb)
+}
+
+// decodeJSON decodes a JSON value, keeping numbers exact
+func decodeJSON(data json.RawMessage) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v any
+	err := decoder.Decode(&v)
+	return v, err
+}
+
+// nullJSON maps a missing JSON value to NULL
+func nullJSON(v json.RawMessage) any {
+	if v == nil {
+		return nil
+	}
+	return []byte(v)
+}
+
+// Params returns the store of module parameters
+func (m *Manager) Params() ParamStore {
+	return m.state.(ParamStore)
+}
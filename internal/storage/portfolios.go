@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// PortfolioTotals adds up the totals of a portfolio's accounts per chain and
+// denom, ordered by chain and denom. states are the accounts' states in the
+// order of refs, whose staking denoms their delegations are totaled in.
+func PortfolioTotals(states []types.AccountState, refs []AccountRef) []types.CrossChainTotal {
+	type key struct{ chain, denom string }
+	type amounts struct{ balance, delegated, unbonding, redelegating, rewards decimal.Decimal }
+	sums := make(map[key]*amounts)
+	add := func(sum *decimal.Decimal, amount string) {
+		if value, err := decimal.NewFromString(amount); err == nil {
+			*sum = sum.Add(value)
+		}
+	}
+
+	for i, state := range states {
+		var stakingDenom string
+		if i < len(refs) {
+			stakingDenom = refs[i].Denom
+		}
+		for _, total := range ChainTotals(state, stakingDenom) {
+			k := key{total.ChainName, total.Denom}
+			if sums[k] == nil {
+				sums[k] = &amounts{}
+			}
+			sum := sums[k]
+			add(&sum.balance, total.Balance)
+			add(&sum.delegated, total.Delegated)
+			add(&sum.unbonding, total.Unbonding)
+			add(&sum.redelegating, total.Redelegating)
+			add(&sum.rewards, total.Rewards)
+		}
+	}
+
+	totals := make([]types.CrossChainTotal, 0, len(sums))
+	for k, sum := range sums {
+		totals = append(totals, types.CrossChainTotal{
+			ChainName:    k.chain,
+			Denom:        k.denom,
+			Balance:      sum.balance.String(),
+			Delegated:    sum.delegated.String(),
+			Unbonding:    sum.unbonding.String(),
+			Redelegating: sum.redelegating.String(),
+			Rewards:      sum.rewards.String(),
+		})
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].ChainName != totals[j].ChainName {
+			return totals[i].ChainName < totals[j].ChainName
+		}
+		return totals[i].Denom < totals[j].Denom
+	})
+	return totals
+}
+
+// GetPortfolioHoldings returns what the given accounts held in their chains'
+// staking denoms at the end of each of the last days, added up per chain and
+// ordered by day and chain. Holdings are folded from the balance and
+// delegation events in ClickHouse; without it this returns ErrUnavailable.
+func (m *Manager) GetPortfolioHoldings(ctx context.Context, refs []AccountRef, days int) ([]types.PortfolioHoldings, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("portfolio history requires analytics storage: %w", ErrUnavailable)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	start := today.AddDate(0, 0, 1-days)
+	holdings, err := fanOut(ctx, refs, func(ctx context.Context, ref AccountRef) ([]types.PortfolioHoldings, error) {
+		return m.clickhouse.GetDailyHoldings(ctx, ref.ChainName, ref.Address, ref.Denom, start, days)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sumHoldings(holdings), nil
+}
+
+// sumHoldings adds up the holdings of accounts per day, chain and denom,
+// ordered by day, chain and denom
+func sumHoldings(holdings []types.PortfolioHoldings) []types.PortfolioHoldings {
+	type key struct {
+		date         time.Time
+		chain, denom string
+	}
+	type amounts struct{ balance, delegated decimal.Decimal }
+	sums := make(map[key]*amounts)
+	for _, holding := range holdings {
+		k := key{holding.Date, holding.ChainName, holding.Denom}
+		if sums[k] == nil {
+			sums[k] = &amounts{}
+		}
+		balance, _ := decimal.NewFromString(holding.Balance)
+		delegated, _ := decimal.NewFromString(holding.Delegated)
+		sums[k].balance = sums[k].balance.Add(balance)
+		sums[k].delegated = sums[k].delegated.Add(delegated)
+	}
+
+	result := make([]types.PortfolioHoldings, 0, len(sums))
+	for k, sum := range sums {
+		result = append(result, types.PortfolioHoldings{
+			Date:      k.date,
+			ChainName: k.chain,
+			Denom:     k.denom,
+			Balance:   sum.balance.String(),
+			Delegated: sum.delegated.String(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if a.ChainName != b.ChainName {
+			return a.ChainName < b.ChainName
+		}
+		return a.Denom < b.Denom
+	})
+	return result
+}
+
+// holdingChange is the balance, or the shares delegated to a validator, at
+// the end of a day with changes
+type holdingChange struct {
+	day       time.Time
+	validator string // empty for the balance
+	amount    decimal.Decimal
+}
+
+// carryHoldings returns the holdings at the end of each of the days from
+// start, carrying the latest balance and delegations forward over days
+// without changes. Changes are ordered by day; those before start count as
+// of start. Shares are counted as tokens and truncated to whole base units.
+func carryHoldings(start time.Time, days int, changes []holdingChange) []types.PortfolioHoldings {
+	holdings := make([]types.PortfolioHoldings, 0, days)
+	balance := decimal.Zero
+	shares := make(map[string]decimal.Decimal)
+
+	next := 0
+	for i := 0; i < days; i++ {
+		day := start.AddDate(0, 0, i)
+		for ; next < len(changes) && !changes[next].day.After(day); next++ {
+			change := changes[next]
+			if change.validator == "" {
+				balance = change.amount
+			} else {
+				shares[change.validator] = change.amount
+			}
+		}
+
+		delegated := decimal.Zero
+		for _, amount := range shares {
+			delegated = delegated.Add(amount)
+		}
+		holdings = append(holdings, types.PortfolioHoldings{
+			Date:      day,
+			Balance:   balance.Truncate(0).String(),
+			Delegated: delegated.Truncate(0).String(),
+		})
+	}
+	return holdings
+}
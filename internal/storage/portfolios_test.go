@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+func TestPortfolioTotals(t *testing.T) {
+	states := []types.AccountState{
+		{
+			ChainName:   "cosmoshub",
+			Balances:    []types.Balance{{Denom: "uatom", Amount: "1000"}},
+			Delegations: []types.Delegation{{ValidatorAddress: "val1", Shares: "500"}},
+			Rewards:     []types.Reward{{ValidatorAddress: "val1", Reward: []types.Coin{{Denom: "uatom", Amount: "7.5"}}}},
+		},
+		{
+			ChainName:   "cosmoshub",
+			Balances:    []types.Balance{{Denom: "uatom", Amount: "250"}, {Denom: "ibc/ABC", Amount: "3"}},
+			Delegations: []types.Delegation{{ValidatorAddress: "val2", Shares: "100"}},
+		},
+		{
+			ChainName: "osmosis",
+			Balances:  []types.Balance{{Denom: "uosmo", Amount: "42"}},
+		},
+	}
+	refs := []AccountRef{
+		{ChainName: "cosmoshub", Address: "cosmos1a", Denom: "uatom"},
+		{ChainName: "cosmoshub", Address: "cosmos1b", Denom: "uatom"},
+		{ChainName: "osmosis", Address: "osmo1a", Denom: "uosmo"},
+	}
+
+	want := []types.CrossChainTotal{
+		{ChainName: "cosmoshub", Denom: "ibc/ABC", Balance: "3", Delegated: "0", Unbonding: "0", Redelegating: "0", Rewards: "0"},
+		{ChainName: "cosmoshub", Denom: "uatom", Balance: "1250", Delegated: "600", Unbonding: "0", Redelegating: "0", Rewards: "7"},
+		{ChainName: "osmosis", Denom: "uosmo", Balance: "42", Delegated: "0", Unbonding: "0", Redelegating: "0", Rewards: "0"},
+	}
+	if got := PortfolioTotals(states, refs); !reflect.DeepEqual(got, want) {
+		t.Errorf("PortfolioTotals() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCarryHoldings(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(i int) time.Time { return start.AddDate(0, 0, i) }
+	amount := decimal.RequireFromString
+
+	tests := []struct {
+		name    string
+		changes []holdingChange
+		want    [][2]string // balance and delegated per day
+	}{
+		{
+			name: "no changes",
+			want: [][2]string{{"0", "0"}, {"0", "0"}, {"0", "0"}},
+		},
+		{
+			name: "carried forward",
+			changes: []holdingChange{
+				{day: day(0), amount: amount("100")},
+				{day: day(0), validator: "val1", amount: amount("50.5")},
+				{day: day(2), amount: amount("80")},
+			},
+			want: [][2]string{{"100", "50"}, {"100", "50"}, {"80", "50"}},
+		},
+		{
+			name: "delegations per validator",
+			changes: []holdingChange{
+				{day: day(0), validator: "val1", amount: amount("10")},
+				{day: day(1), validator: "val2", amount: amount("20")},
+				{day: day(2), validator: "val1", amount: amount("0")},
+			},
+			want: [][2]string{{"0", "10"}, {"0", "30"}, {"0", "20"}},
+		},
+		{
+			name: "changes after the last day",
+			changes: []holdingChange{
+				{day: day(1), amount: amount("5")},
+				{day: day(3), amount: amount("9")},
+			},
+			want: [][2]string{{"0", "0"}, {"5", "0"}, {"5", "0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holdings := carryHoldings(start, 3, tt.changes)
+			var got [][2]string
+			for i, holding := range holdings {
+				if !holding.Date.Equal(day(i)) {
+					t.Errorf("day %d dated %s", i, holding.Date)
+				}
+				got = append(got, [2]string{holding.Balance, holding.Delegated})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("carryHoldings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSumHoldings(t *testing.T) {
+	day1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	holdings := []types.PortfolioHoldings{
+		{Date: day2, ChainName: "cosmoshub", Denom: "uatom", Balance: "1", Delegated: "2"},
+		{Date: day1, ChainName: "osmosis", Denom: "uosmo", Balance: "5", Delegated: "0"},
+		{Date: day1, ChainName: "cosmoshub", Denom: "uatom", Balance: "10", Delegated: "20"},
+		{Date: day2, ChainName: "cosmoshub", Denom: "uatom", Balance: "3", Delegated: "4"},
+	}
+
+	want := []types.PortfolioHoldings{
+		{Date: day1, ChainName: "cosmoshub", Denom: "uatom", Balance: "10", Delegated: "20"},
+		{Date: day1, ChainName: "osmosis", Denom: "uosmo", Balance: "5", Delegated: "0"},
+		{Date: day2, ChainName: "cosmoshub", Denom: "uatom", Balance: "4", Delegated: "6"},
+	}
+	if got := sumHoldings(holdings); !reflect.DeepEqual(got, want) {
+		t.Errorf("sumHoldings() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMemoryPortfolios(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	var tenants []types.Tenant
+	for _, name := range []string{"a", "b"} {
+		tenant := types.Tenant{Name: name}
+		if err := store.CreateTenant(ctx, &tenant); err != nil {
+			t.Fatal(err)
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	portfolio := &types.Portfolio{
+		TenantID: tenants[0].ID,
+		Name:     "treasury",
+		Accounts: []types.PortfolioAccount{{ChainName: "cosmoshub", Address: "cosmos1a"}},
+	}
+	if err := store.CreatePortfolio(ctx, portfolio); err != nil {
+		t.Fatal(err)
+	}
+
+	duplicate := &types.Portfolio{TenantID: tenants[0].ID, Name: "treasury"}
+	if err := store.CreatePortfolio(ctx, duplicate); !errors.Is(err, ErrExists) {
+		t.Errorf("duplicate name: err = %v, want ErrExists", err)
+	}
+	sameName := &types.Portfolio{TenantID: tenants[1].ID, Name: "treasury"}
+	if err := store.CreatePortfolio(ctx, sameName); err != nil {
+		t.Errorf("same name of another tenant: %v", err)
+	}
+
+	got, err := store.GetPortfolio(ctx, tenants[0].ID, portfolio.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Accounts, portfolio.Accounts) {
+		t.Errorf("accounts = %+v, want %+v", got.Accounts, portfolio.Accounts)
+	}
+	if _, err := store.GetPortfolio(ctx, tenants[1].ID, portfolio.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("portfolio of another tenant: err = %v, want ErrNotFound", err)
+	}
+	if err := store.DeletePortfolio(ctx, tenants[1].ID, portfolio.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleting portfolio of another tenant: err = %v, want ErrNotFound", err)
+	}
+
+	if err := store.DeleteTenant(ctx, tenants[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetPortfolio(ctx, tenants[0].ID, portfolio.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("portfolio of deleted tenant: err = %v, want ErrNotFound", err)
+	}
+	portfolios, err := store.GetPortfolios(ctx, tenants[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(portfolios) != 1 || portfolios[0].ID != sameName.ID {
+		t.Errorf("portfolios of other tenant = %+v", portfolios)
+	}
+}
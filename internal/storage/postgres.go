@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/cosmos/state-mesh/pkg/types"
 	_ "github.com/lib/pq"
@@ -12,7 +13,7 @@ import (
 
 // PostgresStore handles PostgreSQL operations
 type PostgresStore struct {
-	db     *sql.DB
+	db     *queryDB
 	logger *zap.Logger
 }
 
@@ -23,14 +24,21 @@ func NewPostgresStore(dsn string, logger *zap.Logger) (*PostgresStore, error) {
 		return nil, fmt.Errorf("failed to open PostgreSQL connection: %w", err)
 	}
 
+	return newPostgresStore(db, 0), nil
+}
+
+// newPostgresStore creates a PostgreSQL store on an opened database, logging
+// queries slower than slowQuery unless it is 0
+func newPostgresStore(db *sql.DB, slowQuery time.Duration) *PostgresStore {
 	// Configure connection pool
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(5)
 
+	logger := zap.L().Named("postgres")
 	return &PostgresStore{
-		db:     db,
-		logger: zap.L().Named("postgres"),
-	}, nil
+		db:     &queryDB{DB: db, stats: newQueryStats(queryStorePostgres, slowQuery, logger)},
+		logger: logger,
+	}
 }
 
 // Ping tests the database connection
@@ -44,7 +52,7 @@ func (s *PostgresStore) Close() error {
 }
 
 // BeginTx starts a new transaction
-func (s *PostgresStore) BeginTx(ctx context.Context) (*PostgresTx, error) {
+func (s *PostgresStore) BeginTx(ctx context.Context) (StateTx, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -59,7 +67,7 @@ func (s *PostgresStore) BeginTx(ctx context.Context) (*PostgresTx, error) {
 // Account operations
 func (s *PostgresStore) GetAccount(ctx context.Context, chainName, address string) (*types.Account, error) {
 	query := `
-		SELECT chain_name, address, created_at, updated_at
+		SELECT chain_name, address, first_seen_height, height, created_at, updated_at
 		FROM accounts
 		WHERE chain_name = $1 AND address = $2
 	`
@@ -68,6 +76,8 @@ func (s *PostgresStore) GetAccount(ctx context.Context, chainName, address strin
 	err := s.db.QueryRowContext(ctx, query, chainName, address).Scan(
 		&account.ChainName,
 		&account.Address,
+		&account.FirstSeenHeight,
+		&account.Height,
 		&account.CreatedAt,
 		&account.UpdatedAt,
 	)
@@ -152,62 +162,588 @@ func (s *PostgresStore) GetDelegations(ctx context.Context, chainName, delegator
 	return delegations, rows.Err()
 }
 
+// GetUnbondingDelegations returns the pending unbonding entries of a
+// delegator grouped by validator, ordered by validator and then completion
+func (s *PostgresStore) GetUnbondingDelegations(ctx context.Context, chainName, delegatorAddress string) ([]types.UnbondingDelegation, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT validator_address, creation_height, completion_time, initial_balance::TEXT, balance::TEXT, height, updated_at
+		FROM unbonding_delegations
+		WHERE chain_name = $1 AND delegator_address = $2 AND completion_time > NOW()
+		ORDER BY validator_address, completion_time, creation_height
+	`, chainName, delegatorAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unbonding delegations: %w", err)
+	}
+	defer rows.Close()
+
+	unbondings := []types.UnbondingDelegation{}
+	for rows.Next() {
+		var validator string
+		var entry types.UnbondingDelegationEntry
+		var height int64
+		var updatedAt time.Time
+		if err := rows.Scan(&validator, &entry.CreationHeight, &entry.CompletionTime, &entry.InitialBalance,
+			&entry.Balance, &height, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan unbonding delegation: %w", err)
+		}
+
+		// Rows are ordered by validator, so a validator's entries are adjacent
+		if n := len(unbondings); n == 0 || unbondings[n-1].ValidatorAddress != validator {
+			unbondings = append(unbondings, types.UnbondingDelegation{
+				ChainName:        chainName,
+				DelegatorAddress: delegatorAddress,
+				ValidatorAddress: validator,
+				Height:           height,
+				UpdatedAt:        updatedAt,
+			})
+		}
+		unbonding := &unbondings[len(unbondings)-1]
+		unbonding.Entries = append(unbonding.Entries, entry)
+	}
+
+	return unbondings, rows.Err()
+}
+
+// GetRedelegations returns the pending redelegation entries of a delegator
+// grouped by source and destination validator, ordered by validators and then
+// completion
+func (s *PostgresStore) GetRedelegations(ctx context.Context, chainName, delegatorAddress string) ([]types.Redelegation, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT validator_src_address, validator_dst_address, creation_height, completion_time,
+			initial_balance::TEXT, shares_dst::TEXT, height, updated_at
+		FROM redelegations
+		WHERE chain_name = $1 AND delegator_address = $2 AND completion_time > NOW()
+		ORDER BY validator_src_address, validator_dst_address, completion_time, creation_height
+	`, chainName, delegatorAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query redelegations: %w", err)
+	}
+	defer rows.Close()
+
+	redelegations := []types.Redelegation{}
+	for rows.Next() {
+		var src, dst string
+		var entry types.RedelegationEntry
+		var height int64
+		var updatedAt time.Time
+		if err := rows.Scan(&src, &dst, &entry.CreationHeight, &entry.CompletionTime, &entry.InitialBalance,
+			&entry.SharesDst, &height, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan redelegation: %w", err)
+		}
+
+		n := len(redelegations)
+		if n == 0 || redelegations[n-1].ValidatorSrcAddress != src || redelegations[n-1].ValidatorDstAddress != dst {
+			redelegations = append(redelegations, types.Redelegation{
+				ChainName:           chainName,
+				DelegatorAddress:    delegatorAddress,
+				ValidatorSrcAddress: src,
+				ValidatorDstAddress: dst,
+				Height:              height,
+				UpdatedAt:           updatedAt,
+			})
+		}
+		redelegation := &redelegations[len(redelegations)-1]
+		redelegation.Entries = append(redelegation.Entries, entry)
+	}
+
+	return redelegations, rows.Err()
+}
+
+// SampleBalances returns a random sample of stored balances for a chain
+func (s *PostgresStore) SampleBalances(ctx context.Context, chainName string, limit int) ([]types.Balance, error) {
+	query := `
+		SELECT chain_name, address, denom, amount, height, updated_at
+		FROM balances
+		WHERE chain_name = $1
+		ORDER BY random()
+		LIMIT $2
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, chainName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample balances: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []types.Balance
+	for rows.Next() {
+		var balance types.Balance
+		err := rows.Scan(
+			&balance.ChainName,
+			&balance.Address,
+			&balance.Denom,
+			&balance.Amount,
+			&balance.Height,
+			&balance.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan balance: %w", err)
+		}
+		balances = append(balances, balance)
+	}
+
+	return balances, rows.Err()
+}
+
+// SampleDelegations returns a random sample of stored delegations for a chain
+func (s *PostgresStore) SampleDelegations(ctx context.Context, chainName string, limit int) ([]types.Delegation, error) {
+	query := `
+		SELECT chain_name, delegator_address, validator_address, shares, height, updated_at
+		FROM delegations
+		WHERE chain_name = $1
+		ORDER BY random()
+		LIMIT $2
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, chainName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample delegations: %w", err)
+	}
+	defer rows.Close()
+
+	var delegations []types.Delegation
+	for rows.Next() {
+		var delegation types.Delegation
+		err := rows.Scan(
+			&delegation.ChainName,
+			&delegation.DelegatorAddress,
+			&delegation.ValidatorAddress,
+			&delegation.Shares,
+			&delegation.Height,
+			&delegation.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delegation: %w", err)
+		}
+		delegations = append(delegations, delegation)
+	}
+
+	return delegations, rows.Err()
+}
+
 // Validator operations
 func (s *PostgresStore) GetValidators(ctx context.Context, chainName string) ([]types.Validator, error) {
+	var validators []types.Validator
+	err := s.EachValidator(ctx, chainName, func(validator *types.Validator) error {
+		validators = append(validators, *validator)
+		return nil
+	})
+	return validators, err
+}
+
+// EachValidator passes the validators of a chain to fn ordered by tokens,
+// reading rows as fn consumes them
+func (s *PostgresStore) EachValidator(ctx context.Context, chainName string, fn func(*types.Validator) error) error {
+	query := `
+		SELECT ` + validatorColumns + `
+		FROM validators
+		WHERE chain_name = $1
+		ORDER BY tokens DESC
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, chainName)
+	if err != nil {
+		return fmt.Errorf("failed to query validators: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		validator, err := scanValidator(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan validator: %w", err)
+		}
+		if err := fn(validator); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetValidatorByConsensusAddress returns the validator that uses or has used
+// the given consensus address
+func (s *PostgresStore) GetValidatorByConsensusAddress(ctx context.Context, chainName, consensusAddress string) (*types.Validator, error) {
+	query := `
+		SELECT ` + validatorColumns + `
+		FROM validators
+		WHERE chain_name = $1 AND operator_address = (
+			SELECT operator_address
+			FROM validator_consensus_addresses
+			WHERE chain_name = $1 AND consensus_address = $2
+		)
+	`
+
+	validator, err := scanValidator(s.db.QueryRowContext(ctx, query, chainName, consensusAddress))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("validator with consensus address %s: %w", consensusAddress, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator by consensus address: %w", err)
+	}
+
+	return validator, nil
+}
+
+// GetUnbondingSchedule returns the unbonding balance completing per day from now
+// until the last pending entry, which covers the chain's next unbonding period
+func (s *PostgresStore) GetUnbondingSchedule(ctx context.Context, chainName string) ([]types.UnbondingBucket, error) {
+	query := `
+		SELECT date_trunc('day', completion_time) AS day, SUM(balance)::TEXT, COUNT(*)
+		FROM unbonding_delegations
+		WHERE chain_name = $1 AND completion_time > NOW()
+		GROUP BY day
+		ORDER BY day
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, chainName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unbonding schedule: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []types.UnbondingBucket{}
+	for rows.Next() {
+		bucket := types.UnbondingBucket{ChainName: chainName}
+		if err := rows.Scan(&bucket.Date, &bucket.Amount, &bucket.Entries); err != nil {
+			return nil, fmt.Errorf("failed to scan unbonding bucket: %w", err)
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, rows.Err()
+}
+
+// GetValidator returns a validator by operator address
+func (s *PostgresStore) GetValidator(ctx context.Context, chainName, operatorAddress string) (*types.Validator, error) {
+	query := `
+		SELECT ` + validatorColumns + `
+		FROM validators
+		WHERE chain_name = $1 AND operator_address = $2
+	`
+
+	validator, err := scanValidator(s.db.QueryRowContext(ctx, query, chainName, operatorAddress))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("validator %s: %w", operatorAddress, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator: %w", err)
+	}
+
+	return validator, nil
+}
+
+// GetValidatorDelegators returns a page of delegations to a validator ordered by
+// shares, with token amounts estimated from the validator's token/share ratio,
+// and the total number of delegators
+func (s *PostgresStore) GetValidatorDelegators(ctx context.Context, chainName, validatorAddress string, limit, offset int) ([]types.ValidatorDelegator, int64, error) {
+	if _, err := s.GetValidator(ctx, chainName, validatorAddress); err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM delegations WHERE chain_name = $1 AND validator_address = $2`
+	if err := s.db.QueryRowContext(ctx, countQuery, chainName, validatorAddress).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count validator delegators: %w", err)
+	}
+
 	query := `
-		SELECT chain_name, operator_address, consensus_pubkey, jailed, status, tokens, 
+		SELECT d.delegator_address, d.shares,
+		       COALESCE(TRUNC(d.shares * v.tokens / NULLIF(v.delegator_shares, 0)), 0),
+		       d.height, d.updated_at
+		FROM delegations d
+		JOIN validators v ON v.chain_name = d.chain_name AND v.operator_address = d.validator_address
+		WHERE d.chain_name = $1 AND d.validator_address = $2
+		ORDER BY d.shares DESC, d.delegator_address
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, chainName, validatorAddress, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query validator delegators: %w", err)
+	}
+	defer rows.Close()
+
+	delegators := []types.ValidatorDelegator{}
+	for rows.Next() {
+		var delegator types.ValidatorDelegator
+		err := rows.Scan(
+			&delegator.DelegatorAddress,
+			&delegator.Shares,
+			&delegator.Tokens,
+			&delegator.Height,
+			&delegator.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan validator delegator: %w", err)
+		}
+		delegators = append(delegators, delegator)
+	}
+
+	return delegators, total, rows.Err()
+}
+
+// validatorColumns lists the validator columns read by scanValidator
+const validatorColumns = `chain_name, operator_address, consensus_pubkey, consensus_address, jailed, status, tokens,
 		       delegator_shares, description_moniker, description_identity, description_website,
 		       description_security_contact, description_details, unbonding_height, unbonding_time,
 		       commission_rate, commission_max_rate, commission_max_change_rate, min_self_delegation,
-		       height, updated_at
+		       height, updated_at`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanValidator scans a row selected with validatorColumns
+func scanValidator(row rowScanner) (*types.Validator, error) {
+	var validator types.Validator
+	err := row.Scan(
+		&validator.ChainName,
+		&validator.OperatorAddress,
+		&validator.ConsensusPubkey,
+		&validator.ConsensusAddress,
+		&validator.Jailed,
+		&validator.Status,
+		&validator.Tokens,
+		&validator.DelegatorShares,
+		&validator.Description.Moniker,
+		&validator.Description.Identity,
+		&validator.Description.Website,
+		&validator.Description.SecurityContact,
+		&validator.Description.Details,
+		&validator.UnbondingHeight,
+		&validator.UnbondingTime,
+		&validator.Commission.Rate,
+		&validator.Commission.MaxRate,
+		&validator.Commission.MaxChangeRate,
+		&validator.MinSelfDelegation,
+		&validator.Height,
+		&validator.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &validator, nil
+}
+
+// GetConsensusAddressMap returns every known consensus address of a chain mapped
+// to its validator operator address
+func (s *PostgresStore) GetConsensusAddressMap(ctx context.Context, chainName string) (map[string]string, error) {
+	query := `
+		SELECT consensus_address, operator_address
+		FROM validator_consensus_addresses
+		WHERE chain_name = $1
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, chainName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consensus addresses: %w", err)
+	}
+	defer rows.Close()
+
+	addresses := make(map[string]string)
+	for rows.Next() {
+		var consensusAddress, operatorAddress string
+		if err := rows.Scan(&consensusAddress, &operatorAddress); err != nil {
+			return nil, fmt.Errorf("failed to scan consensus address: %w", err)
+		}
+		addresses[consensusAddress] = operatorAddress
+	}
+
+	return addresses, rows.Err()
+}
+
+// GetValidatorSummary returns validator counts and the total bonded tokens for a chain
+func (s *PostgresStore) GetValidatorSummary(ctx context.Context, chainName string) (total, active int64, bondedTokens string, err error) {
+	query := `
+		SELECT
+			count(*),
+			count(*) FILTER (WHERE status = 'BOND_STATUS_BONDED' AND NOT jailed),
+			COALESCE(sum(tokens) FILTER (WHERE status = 'BOND_STATUS_BONDED'), 0)::TEXT
 		FROM validators
 		WHERE chain_name = $1
-		ORDER BY tokens DESC
+	`
+
+	err = s.db.QueryRowContext(ctx, query, chainName).Scan(&total, &active, &bondedTokens)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("failed to get validator summary: %w", err)
+	}
+
+	return total, active, bondedTokens, nil
+}
+
+// Supply operations
+func (s *PostgresStore) GetSupply(ctx context.Context, chainName string) ([]types.Supply, error) {
+	query := `
+		SELECT chain_name, denom, amount, height, updated_at
+		FROM supply
+		WHERE chain_name = $1
+		ORDER BY denom
 	`
 
 	rows, err := s.db.QueryContext(ctx, query, chainName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query validators: %w", err)
+		return nil, fmt.Errorf("failed to query supply: %w", err)
 	}
 	defer rows.Close()
 
-	var validators []types.Validator
+	var supply []types.Supply
 	for rows.Next() {
-		var validator types.Validator
+		var coin types.Supply
 		err := rows.Scan(
-			&validator.ChainName,
-			&validator.OperatorAddress,
-			&validator.ConsensusPubkey,
-			&validator.Jailed,
-			&validator.Status,
-			&validator.Tokens,
-			&validator.DelegatorShares,
-			&validator.Description.Moniker,
-			&validator.Description.Identity,
-			&validator.Description.Website,
-			&validator.Description.SecurityContact,
-			&validator.Description.Details,
-			&validator.UnbondingHeight,
-			&validator.UnbondingTime,
-			&validator.Commission.Rate,
-			&validator.Commission.MaxRate,
-			&validator.Commission.MaxChangeRate,
-			&validator.MinSelfDelegation,
-			&validator.Height,
-			&validator.UpdatedAt,
+			&coin.ChainName,
+			&coin.Denom,
+			&coin.Amount,
+			&coin.Height,
+			&coin.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan validator: %w", err)
+			return nil, fmt.Errorf("failed to scan supply: %w", err)
 		}
-		validators = append(validators, validator)
+		supply = append(supply, coin)
+	}
+
+	return supply, rows.Err()
+}
+
+// CountAccounts returns the number of tracked accounts for a chain
+func (s *PostgresStore) CountAccounts(ctx context.Context, chainName string) (int64, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx, `SELECT count(*) FROM accounts WHERE chain_name = $1`, chainName).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count accounts: %w", err)
 	}
 
-	return validators, rows.Err()
+	return count, nil
+}
+
+// Mint operations
+func (s *PostgresStore) GetMintParams(ctx context.Context, chainName string) (*types.MintParams, error) {
+	query := `
+		SELECT chain_name, mint_denom, inflation_rate_change, inflation_max, inflation_min,
+		       goal_bonded, blocks_per_year, current_inflation, annual_provisions, height, updated_at
+		FROM mint_params
+		WHERE chain_name = $1
+	`
+
+	var params types.MintParams
+	err := s.db.QueryRowContext(ctx, query, chainName).Scan(
+		&params.ChainName,
+		&params.MintDenom,
+		&params.InflationRateChange,
+		&params.InflationMax,
+		&params.InflationMin,
+		&params.GoalBonded,
+		&params.BlocksPerYear,
+		&params.CurrentInflation,
+		&params.AnnualProvisions,
+		&params.Height,
+		&params.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mint params: %w", err)
+	}
+
+	return &params, nil
+}
+
+// GetChains returns all registered chains
+func (s *PostgresStore) GetChains(ctx context.Context) ([]types.ChainInfo, error) {
+	query := `
+		SELECT c.name, c.chain_id, c.status,
+		       COALESCE(cs.latest_height, c.latest_height),
+		       COALESCE(cs.latest_block_time, c.latest_time),
+		       COALESCE(cs.last_ingested_height, 0),
+		       COALESCE(cs.catching_up, FALSE),
+		       COALESCE(cs.node_version, ''),
+		       COALESCE(cs.app_version, ''),
+		       cs.halted_since,
+		       c.updated_at
+		FROM chains c
+		LEFT JOIN chain_status cs ON cs.chain_name = c.name
+		ORDER BY c.name
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chains: %w", err)
+	}
+	defer rows.Close()
+
+	var chains []types.ChainInfo
+	for rows.Next() {
+		var chain types.ChainInfo
+		err := rows.Scan(
+			&chain.Name,
+			&chain.ChainID,
+			&chain.Status,
+			&chain.LatestHeight,
+			&chain.LatestTime,
+			&chain.LastIngestedHeight,
+			&chain.CatchingUp,
+			&chain.NodeVersion,
+			&chain.AppVersion,
+			&chain.HaltedSince,
+			&chain.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chain: %w", err)
+		}
+		chains = append(chains, chain)
+	}
+
+	return chains, rows.Err()
+}
+
+// GetChain returns a registered chain by name
+func (s *PostgresStore) GetChain(ctx context.Context, name string) (*types.ChainInfo, error) {
+	query := `
+		SELECT c.name, c.chain_id, c.status,
+		       COALESCE(cs.latest_height, c.latest_height),
+		       COALESCE(cs.latest_block_time, c.latest_time),
+		       COALESCE(cs.last_ingested_height, 0),
+		       COALESCE(cs.catching_up, FALSE),
+		       COALESCE(cs.node_version, ''),
+		       COALESCE(cs.app_version, ''),
+		       cs.halted_since,
+		       c.updated_at
+		FROM chains c
+		LEFT JOIN chain_status cs ON cs.chain_name = c.name
+		WHERE c.name = $1
+	`
+
+	var chain types.ChainInfo
+	err := s.db.QueryRowContext(ctx, query, name).Scan(
+		&chain.Name,
+		&chain.ChainID,
+		&chain.Status,
+		&chain.LatestHeight,
+		&chain.LatestTime,
+		&chain.LastIngestedHeight,
+		&chain.CatchingUp,
+		&chain.NodeVersion,
+		&chain.AppVersion,
+		&chain.HaltedSince,
+		&chain.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("chain %s: %w", name, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain: %w", err)
+	}
+
+	return &chain, nil
 }
 
 // PostgresTx represents a PostgreSQL transaction
 type PostgresTx struct {
-	tx     *sql.Tx
+	tx     *queryTx
 	logger *zap.Logger
 }
 
@@ -221,18 +757,23 @@ func (tx *PostgresTx) Rollback() error {
 	return tx.tx.Rollback()
 }
 
-// UpsertAccount inserts or updates an account
+// UpsertAccount registers an account seen at account.Height, keeping the
+// earliest first-seen and the latest seen height
 func (tx *PostgresTx) UpsertAccount(ctx context.Context, account *types.Account) error {
 	query := `
-		INSERT INTO accounts (chain_name, address, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO accounts (chain_name, address, first_seen_height, height, created_at, updated_at)
+		VALUES ($1, $2, $3, $3, $4, $5)
 		ON CONFLICT (chain_name, address)
-		DO UPDATE SET updated_at = EXCLUDED.updated_at
+		DO UPDATE SET
+			first_seen_height = LEAST(accounts.first_seen_height, EXCLUDED.first_seen_height),
+			height = GREATEST(accounts.height, EXCLUDED.height),
+			updated_at = EXCLUDED.updated_at
 	`
 
 	_, err := tx.tx.ExecContext(ctx, query,
 		account.ChainName,
 		account.Address,
+		account.Height,
 		account.CreatedAt,
 		account.UpdatedAt,
 	)
@@ -250,6 +791,7 @@ func (tx *PostgresTx) UpsertBalance(ctx context.Context, balance *types.Balance)
 			amount = EXCLUDED.amount,
 			height = EXCLUDED.height,
 			updated_at = EXCLUDED.updated_at
+		WHERE EXCLUDED.height >= balances.height
 	`
 
 	_, err := tx.tx.ExecContext(ctx, query,
@@ -278,6 +820,7 @@ func (tx *PostgresTx) UpsertBalances(ctx context.Context, balances []types.Balan
 			amount = EXCLUDED.amount,
 			height = EXCLUDED.height,
 			updated_at = EXCLUDED.updated_at
+		WHERE EXCLUDED.height >= balances.height
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare balance upsert statement: %w", err)
@@ -301,6 +844,243 @@ func (tx *PostgresTx) UpsertBalances(ctx context.Context, balances []types.Balan
 	return nil
 }
 
+// UpsertSupply inserts or updates the total supply of multiple denoms
+func (tx *PostgresTx) UpsertSupply(ctx context.Context, supply []types.Supply) error {
+	if len(supply) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.tx.PrepareContext(ctx, `
+		INSERT INTO supply (chain_name, denom, amount, height, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (chain_name, denom)
+		DO UPDATE SET
+			amount = EXCLUDED.amount,
+			height = EXCLUDED.height,
+			updated_at = EXCLUDED.updated_at
+		WHERE EXCLUDED.height >= supply.height
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare supply upsert statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, coin := range supply {
+		_, err := stmt.ExecContext(ctx,
+			coin.ChainName,
+			coin.Denom,
+			coin.Amount,
+			coin.Height,
+			coin.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert supply: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReplaceUnbondingDelegations replaces all stored unbonding delegation entries of
+// a chain. Completed entries disappear from the chain, so a full snapshot is kept
+// rather than upserting.
+func (tx *PostgresTx) ReplaceUnbondingDelegations(ctx context.Context, chainName string, unbondings []types.UnbondingDelegation) error {
+	if _, err := tx.tx.ExecContext(ctx, `DELETE FROM unbonding_delegations WHERE chain_name = $1`, chainName); err != nil {
+		return fmt.Errorf("failed to delete unbonding delegations: %w", err)
+	}
+
+	stmt, err := tx.tx.PrepareContext(ctx, `
+		INSERT INTO unbonding_delegations (
+			chain_name, delegator_address, validator_address, creation_height,
+			completion_time, initial_balance, balance, height, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare unbonding delegation insert statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, unbonding := range unbondings {
+		for _, entry := range unbonding.Entries {
+			_, err := stmt.ExecContext(ctx,
+				chainName,
+				unbonding.DelegatorAddress,
+				unbonding.ValidatorAddress,
+				entry.CreationHeight,
+				entry.CompletionTime,
+				entry.InitialBalance,
+				entry.Balance,
+				unbonding.Height,
+				unbonding.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert unbonding delegation: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ReplaceRedelegations replaces all stored redelegation entries of a chain.
+// Like unbonding entries, completed entries disappear from the chain.
+func (tx *PostgresTx) ReplaceRedelegations(ctx context.Context, chainName string, redelegations []types.Redelegation) error {
+	if _, err := tx.tx.ExecContext(ctx, `DELETE FROM redelegations WHERE chain_name = $1`, chainName); err != nil {
+		return fmt.Errorf("failed to delete redelegations: %w", err)
+	}
+
+	stmt, err := tx.tx.PrepareContext(ctx, `
+		INSERT INTO redelegations (
+			chain_name, delegator_address, validator_src_address, validator_dst_address, creation_height,
+			completion_time, initial_balance, shares_dst, height, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare redelegation insert statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, redelegation := range redelegations {
+		for _, entry := range redelegation.Entries {
+			_, err := stmt.ExecContext(ctx,
+				chainName,
+				redelegation.DelegatorAddress,
+				redelegation.ValidatorSrcAddress,
+				redelegation.ValidatorDstAddress,
+				entry.CreationHeight,
+				entry.CompletionTime,
+				entry.InitialBalance,
+				entry.SharesDst,
+				redelegation.Height,
+				redelegation.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert redelegation: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// UpsertConsensusAddress records the consensus address used by a validator at a height
+func (tx *PostgresTx) UpsertConsensusAddress(ctx context.Context, chainName, consensusAddress, operatorAddress string, height int64) error {
+	query := `
+		INSERT INTO validator_consensus_addresses (
+			chain_name, consensus_address, operator_address, first_seen_height, last_seen_height
+		) VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (chain_name, consensus_address)
+		DO UPDATE SET
+			operator_address = EXCLUDED.operator_address,
+			last_seen_height = GREATEST(validator_consensus_addresses.last_seen_height, EXCLUDED.last_seen_height)
+	`
+
+	_, err := tx.tx.ExecContext(ctx, query, chainName, consensusAddress, operatorAddress, height)
+	return err
+}
+
+// UpsertChain registers a chain or updates its status. A zero LatestHeight keeps
+// the stored height so status-only updates don't reset it.
+func (tx *PostgresTx) UpsertChain(ctx context.Context, chain *types.ChainInfo) error {
+	query := `
+		INSERT INTO chains (name, chain_id, status, latest_height, latest_time, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name)
+		DO UPDATE SET
+			chain_id = EXCLUDED.chain_id,
+			status = EXCLUDED.status,
+			latest_height = CASE WHEN EXCLUDED.latest_height > 0 THEN EXCLUDED.latest_height ELSE chains.latest_height END,
+			latest_time = CASE WHEN EXCLUDED.latest_height > 0 THEN EXCLUDED.latest_time ELSE chains.latest_time END,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := tx.tx.ExecContext(ctx, query,
+		chain.Name,
+		chain.ChainID,
+		chain.Status,
+		chain.LatestHeight,
+		chain.LatestTime,
+		chain.UpdatedAt,
+	)
+
+	return err
+}
+
+// UpsertChainStatus inserts or updates a chain's sync status. A zero
+// LastIngestedHeight keeps the stored value so the node status can be recorded
+// before ingestion completes.
+func (tx *PostgresTx) UpsertChainStatus(ctx context.Context, status *types.ChainStatus) error {
+	query := `
+		INSERT INTO chain_status (
+			chain_name, latest_height, latest_block_time, last_ingested_height,
+			catching_up, node_version, app_version, halted_since, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (chain_name)
+		DO UPDATE SET
+			latest_height = EXCLUDED.latest_height,
+			latest_block_time = EXCLUDED.latest_block_time,
+			last_ingested_height = GREATEST(chain_status.last_ingested_height, EXCLUDED.last_ingested_height),
+			catching_up = EXCLUDED.catching_up,
+			node_version = EXCLUDED.node_version,
+			app_version = EXCLUDED.app_version,
+			halted_since = EXCLUDED.halted_since,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := tx.tx.ExecContext(ctx, query,
+		status.ChainName,
+		status.LatestHeight,
+		status.LatestBlockTime,
+		status.LastIngestedHeight,
+		status.CatchingUp,
+		status.NodeVersion,
+		status.AppVersion,
+		status.HaltedSince,
+		status.UpdatedAt,
+	)
+
+	return err
+}
+
+// UpsertMintParams inserts or updates mint parameters
+func (tx *PostgresTx) UpsertMintParams(ctx context.Context, params *types.MintParams) error {
+	query := `
+		INSERT INTO mint_params (
+			chain_name, mint_denom, inflation_rate_change, inflation_max, inflation_min,
+			goal_bonded, blocks_per_year, current_inflation, annual_provisions, height, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (chain_name)
+		DO UPDATE SET
+			mint_denom = EXCLUDED.mint_denom,
+			inflation_rate_change = EXCLUDED.inflation_rate_change,
+			inflation_max = EXCLUDED.inflation_max,
+			inflation_min = EXCLUDED.inflation_min,
+			goal_bonded = EXCLUDED.goal_bonded,
+			blocks_per_year = EXCLUDED.blocks_per_year,
+			current_inflation = EXCLUDED.current_inflation,
+			annual_provisions = EXCLUDED.annual_provisions,
+			height = EXCLUDED.height,
+			updated_at = EXCLUDED.updated_at
+		WHERE EXCLUDED.height >= mint_params.height
+	`
+
+	_, err := tx.tx.ExecContext(ctx, query,
+		params.ChainName,
+		params.MintDenom,
+		params.InflationRateChange,
+		params.InflationMax,
+		params.InflationMin,
+		params.GoalBonded,
+		params.BlocksPerYear,
+		params.CurrentInflation,
+		params.AnnualProvisions,
+		params.Height,
+		params.UpdatedAt,
+	)
+
+	return err
+}
+
 // UpsertDelegation inserts or updates a delegation
 func (tx *PostgresTx) UpsertDelegation(ctx context.Context, delegation *types.Delegation) error {
 	query := `
@@ -311,6 +1091,7 @@ func (tx *PostgresTx) UpsertDelegation(ctx context.Context, delegation *types.De
 			shares = EXCLUDED.shares,
 			height = EXCLUDED.height,
 			updated_at = EXCLUDED.updated_at
+		WHERE EXCLUDED.height >= delegations.height
 	`
 
 	_, err := tx.tx.ExecContext(ctx, query,
@@ -325,6 +1106,17 @@ func (tx *PostgresTx) UpsertDelegation(ctx context.Context, delegation *types.De
 	return err
 }
 
+// DeleteDelegation removes a delegation that no longer exists on chain
+func (tx *PostgresTx) DeleteDelegation(ctx context.Context, chainName, delegatorAddress, validatorAddress string) error {
+	query := `
+		DELETE FROM delegations
+		WHERE chain_name = $1 AND delegator_address = $2 AND validator_address = $3
+	`
+
+	_, err := tx.tx.ExecContext(ctx, query, chainName, delegatorAddress, validatorAddress)
+	return err
+}
+
 // UpsertValidator inserts or updates a validator
 func (tx *PostgresTx) UpsertValidator(ctx context.Context, validator *types.Validator) error {
 	query := `
@@ -333,11 +1125,12 @@ func (tx *PostgresTx) UpsertValidator(ctx context.Context, validator *types.Vali
 			delegator_shares, description_moniker, description_identity, description_website,
 			description_security_contact, description_details, unbonding_height, unbonding_time,
 			commission_rate, commission_max_rate, commission_max_change_rate, min_self_delegation,
-			height, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+			height, updated_at, consensus_address
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 		ON CONFLICT (chain_name, operator_address)
 		DO UPDATE SET 
 			consensus_pubkey = EXCLUDED.consensus_pubkey,
+			consensus_address = EXCLUDED.consensus_address,
 			jailed = EXCLUDED.jailed,
 			status = EXCLUDED.status,
 			tokens = EXCLUDED.tokens,
@@ -355,6 +1148,7 @@ func (tx *PostgresTx) UpsertValidator(ctx context.Context, validator *types.Vali
 			min_self_delegation = EXCLUDED.min_self_delegation,
 			height = EXCLUDED.height,
 			updated_at = EXCLUDED.updated_at
+		WHERE EXCLUDED.height >= validators.height
 	`
 
 	_, err := tx.tx.ExecContext(ctx, query,
@@ -378,6 +1172,7 @@ func (tx *PostgresTx) UpsertValidator(ctx context.Context, validator *types.Vali
 		validator.MinSelfDelegation,
 		validator.Height,
 		validator.UpdatedAt,
+		validator.ConsensusAddress,
 	)
 
 	return err
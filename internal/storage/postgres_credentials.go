@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// SQLSTATEs PostgreSQL rejects credentials with
+const (
+	invalidPassword      = "28P01"
+	invalidAuthorization = "28000"
+)
+
+// openPostgresStore opens a PostgreSQL store. When the credentials are read
+// from secrets, connections are opened with the current credentials and
+// recycled every secrets refresh interval, so that rotated credentials are
+// picked up without a restart.
+func openPostgresStore(cfg config.PostgresConfig, logger *zap.Logger) (*PostgresStore, error) {
+	if !cfg.RotatesCredentials() {
+		db, err := sql.Open("postgres", cfg.DSN())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open PostgreSQL connection: %w", err)
+		}
+		return newPostgresStore(db, cfg.SlowQueryThreshold), nil
+	}
+
+	db := sql.OpenDB(&rotatingConnector{cfg: cfg, logger: logger.Named("postgres")})
+	store := newPostgresStore(db, cfg.SlowQueryThreshold)
+	db.SetConnMaxLifetime(cfg.RotationInterval())
+	return store, nil
+}
+
+// rotatingConnector opens PostgreSQL connections with credentials read from
+// secrets. Credentials rejected by the server are read again, bypassing the
+// cache, before giving up, which covers rotations between two refreshes.
+type rotatingConnector struct {
+	cfg    config.PostgresConfig
+	logger *zap.Logger
+
+	mu             sync.Mutex
+	user, password string
+	connector      driver.Connector
+}
+
+// Connect opens a connection with the current credentials
+func (c *rotatingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	connector, err := c.current(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := connector.Connect(ctx)
+	if !isAuthFailure(err) {
+		return conn, err
+	}
+
+	c.logger.Info("PostgreSQL rejected the credentials, reading them again", zap.Error(err))
+	connector, err = c.current(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(ctx)
+}
+
+// Driver returns the PostgreSQL driver
+func (c *rotatingConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
+
+// current returns a connector for the current credentials, creating a new one
+// when they have rotated
+func (c *rotatingConnector) current(ctx context.Context, refresh bool) (driver.Connector, error) {
+	user, password, err := c.cfg.Credentials(ctx, refresh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PostgreSQL credentials: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.connector != nil && user == c.user && password == c.password {
+		return c.connector, nil
+	}
+
+	connector, err := pq.NewConnector(c.cfg.DSNWith(user, password))
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure PostgreSQL connection: %w", err)
+	}
+	if c.connector != nil {
+		c.logger.Info("PostgreSQL credentials rotated", zap.String("user", user))
+	}
+	c.user, c.password, c.connector = user, password, connector
+	return connector, nil
+}
+
+// isAuthFailure reports whether PostgreSQL rejected the credentials
+func isAuthFailure(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == invalidPassword || pqErr.Code == invalidAuthorization
+}
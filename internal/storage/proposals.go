@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// ProposalStore reads the governance proposals the gov module ingests
+type ProposalStore interface {
+	GetProposals(ctx context.Context, chainName, status string, limit, offset int) ([]types.Proposal, int64, error)
+	GetProposal(ctx context.Context, chainName string, proposalID uint64) (*types.Proposal, error)
+}
+
+var (
+	_ ProposalStore = (*PostgresStore)(nil)
+	_ ProposalStore = (*CockroachStore)(nil)
+	_ ProposalStore = (*MemoryStore)(nil)
+)
+
+// proposalColumns lists the proposal columns read by scanProposal
+const proposalColumns = `chain_name, proposal_id, content, status, final_tally_result, submit_time, deposit_end_time,
+		       total_deposit, voting_start_time, voting_end_time, height, updated_at`
+
+// UpsertProposals inserts or updates governance proposals
+func (tx *PostgresTx) UpsertProposals(ctx context.Context, proposals []types.Proposal) error {
+	query := `
+		INSERT INTO proposals (
+			chain_name, proposal_id, content, status, final_tally_result, submit_time, deposit_end_time,
+			total_deposit, voting_start_time, voting_end_time, height, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (chain_name, proposal_id)
+		DO UPDATE SET
+			content = EXCLUDED.content,
+			status = EXCLUDED.status,
+			final_tally_result = EXCLUDED.final_tally_result,
+			submit_time = EXCLUDED.submit_time,
+			deposit_end_time = EXCLUDED.deposit_end_time,
+			total_deposit = EXCLUDED.total_deposit,
+			voting_start_time = EXCLUDED.voting_start_time,
+			voting_end_time = EXCLUDED.voting_end_time,
+			height = EXCLUDED.height,
+			updated_at = EXCLUDED.updated_at
+		WHERE EXCLUDED.height >= proposals.height
+	`
+
+	for _, proposal := range proposals {
+		content, err := json.Marshal(proposal.Content)
+		if err != nil {
+			return fmt.Errorf("failed to encode proposal %d content: %w", proposal.ProposalID, err)
+		}
+		tally, err := json.Marshal(proposal.FinalTallyResult)
+		if err != nil {
+			return fmt.Errorf("failed to encode proposal %d tally: %w", proposal.ProposalID, err)
+		}
+		deposit, err := json.Marshal(proposal.TotalDeposit)
+		if err != nil {
+			return fmt.Errorf("failed to encode proposal %d deposit: %w", proposal.ProposalID, err)
+		}
+
+		_, err = tx.tx.ExecContext(ctx, query,
+			proposal.ChainName,
+			proposal.ProposalID,
+			content,
+			proposal.Status,
+			tally,
+			proposal.SubmitTime,
+			proposal.DepositEndTime,
+			deposit,
+			nullTime(proposal.VotingStartTime),
+			nullTime(proposal.VotingEndTime),
+			proposal.Height,
+			proposal.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert proposal %d: %w", proposal.ProposalID, err)
+		}
+	}
+	return nil
+}
+
+// GetProposals returns the proposals of a chain, newest first, optionally
+// only those with a status, along with the total number matching
+func (s *PostgresStore) GetProposals(ctx context.Context, chainName, status string, limit, offset int) ([]types.Proposal, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM proposals WHERE chain_name = $1 AND ($2 = '' OR status = $2)`
+	if err := s.db.QueryRowContext(ctx, countQuery, chainName, status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count proposals: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+proposalColumns+`
+		FROM proposals
+		WHERE chain_name = $1 AND ($2 = '' OR status = $2)
+		ORDER BY proposal_id DESC
+		LIMIT $3 OFFSET $4
+	`, chainName, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query proposals: %w", err)
+	}
+	defer rows.Close()
+
+	proposals := []types.Proposal{}
+	for rows.Next() {
+		proposal, err := scanProposal(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		proposals = append(proposals, *proposal)
+	}
+	return proposals, total, rows.Err()
+}
+
+// GetProposal returns a proposal of a chain
+func (s *PostgresStore) GetProposal(ctx context.Context, chainName string, proposalID uint64) (*types.Proposal, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+proposalColumns+`
+		FROM proposals
+		WHERE chain_name = $1 AND proposal_id = $2
+	`, chainName, proposalID)
+
+	proposal, err := scanProposal(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("proposal %d: %w", proposalID, ErrNotFound)
+	}
+	return proposal, err
+}
+
+// scanProposal scans a row of proposalColumns
+func scanProposal(row interface{ Scan(...any) error }) (*types.Proposal, error) {
+	var (
+		proposal                       types.Proposal
+		content, tally, deposit        []byte
+		votingStartTime, votingEndTime sql.NullTime
+	)
+	err := row.Scan(
+		&proposal.ChainName,
+		&proposal.ProposalID,
+		&content,
+		&proposal.Status,
+		&tally,
+		&proposal.SubmitTime,
+		&proposal.DepositEndTime,
+		&deposit,
+		&votingStartTime,
+		&votingEndTime,
+		&proposal.Height,
+		&proposal.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan proposal: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &proposal.Content); err != nil {
+		return nil, fmt.Errorf("failed to decode proposal %d content: %w", proposal.ProposalID, err)
+	}
+	if len(tally) > 0 {
+		if err := json.Unmarshal(tally, &proposal.FinalTallyResult); err != nil {
+			return nil, fmt.Errorf("failed to decode proposal %d tally: %w", proposal.ProposalID, err)
+		}
+	}
+	if len(deposit) > 0 {
+		if err := json.Unmarshal(deposit, &proposal.TotalDeposit); err != nil {
+			return nil, fmt.Errorf("failed to decode proposal %d deposit: %w", proposal.ProposalID, err)
+		}
+	}
+	proposal.VotingStartTime = votingStartTime.Time
+	proposal.VotingEndTime = votingEndTime.Time
+	return &proposal, nil
+}
+
+// nullTime maps the zero time to NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// Proposals returns the store of governance proposals
+func (m *Manager) Proposals() ProposalStore {
+	return m.state.(ProposalStore)
+}
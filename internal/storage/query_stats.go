@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var (
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "statemesh",
+		Subsystem: "storage",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of storage queries by store and query name, until the first result",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15),
+	}, []string{"store", "query"})
+
+	queryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "storage",
+		Name:      "query_errors_total",
+		Help:      "Storage queries that failed, by store and query name",
+	}, []string{"store", "query"})
+
+	slowQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "storage",
+		Name:      "slow_queries_total",
+		Help:      "Storage queries slower than the store's slow query threshold, by store and query name",
+	}, []string{"store", "query"})
+)
+
+// Stores of the query statistics; CockroachDB queries are counted as postgres
+const (
+	queryStorePostgres   = "postgres"
+	queryStoreClickHouse = "clickhouse"
+)
+
+// maxLoggedStatement is how much of a slow statement is logged
+const maxLoggedStatement = 500
+
+// queryStats records the duration of a store's queries and logs those slower
+// than the threshold. Queries are named after the store method running them,
+// e.g. PostgresStore.GetBlocks.
+type queryStats struct {
+	store     string
+	threshold time.Duration // 0 disables the slow query log
+	logger    *zap.Logger
+}
+
+func newQueryStats(store string, threshold time.Duration, logger *zap.Logger) *queryStats {
+	return &queryStats{store: store, threshold: threshold, logger: logger}
+}
+
+// record records a query that started at start
+func (s *queryStats) record(name, statement string, start time.Time, err error) {
+	duration := time.Since(start)
+	queryDuration.WithLabelValues(s.store, name).Observe(duration.Seconds())
+	if err != nil && err != sql.ErrNoRows {
+		queryErrors.WithLabelValues(s.store, name).Inc()
+	}
+
+	if s.threshold <= 0 || duration < s.threshold {
+		return
+	}
+	slowQueries.WithLabelValues(s.store, name).Inc()
+
+	fields := []zap.Field{
+		zap.String("query", name),
+		zap.Duration("duration", duration),
+		zap.String("statement", compactStatement(statement)),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	s.logger.Warn("Slow query", fields...)
+}
+
+// compactStatement collapses the whitespace of a statement for logging and
+// truncates long ones
+func compactStatement(statement string) string {
+	statement = strings.Join(strings.Fields(statement), " ")
+	if len(statement) > maxLoggedStatement {
+		statement = statement[:maxLoggedStatement] + "..."
+	}
+	return statement
+}
+
+// queryNames caches query names by program counter
+var queryNames sync.Map
+
+// queryName names a query after the function two frames up from the caller,
+// i.e. the store method calling a wrapped connection. Closures are named after
+// the method they are in.
+func queryName() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+	if name, ok := queryNames.Load(pc); ok {
+		return name.(string)
+	}
+
+	name := "unknown"
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		name = fn.Name()
+		// github.com/cosmos/state-mesh/internal/storage.(*PostgresStore).GetBlocks.func1
+		name = name[strings.LastIndex(name, "/")+1:]
+		if _, rest, ok := strings.Cut(name, "."); ok {
+			name = rest
+		}
+		name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+		if i := strings.Index(name, ".func"); i > 0 {
+			name = name[:i]
+		}
+	}
+	queryNames.Store(pc, name)
+	return name
+}
+
+// queryDB is a PostgreSQL database whose queries are recorded
+type queryDB struct {
+	*sql.DB
+	stats *queryStats
+}
+
+func (db *queryDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	name, start := queryName(), time.Now()
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	db.stats.record(name, query, start, err)
+	return rows, err
+}
+
+func (db *queryDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	name, start := queryName(), time.Now()
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	db.stats.record(name, query, start, row.Err())
+	return row
+}
+
+func (db *queryDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	name, start := queryName(), time.Now()
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	db.stats.record(name, query, start, err)
+	return result, err
+}
+
+// BeginTx starts a transaction whose queries are recorded
+func (db *queryDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*queryTx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &queryTx{Tx: tx, stats: db.stats}, nil
+}
+
+// queryTx is a PostgreSQL transaction whose queries are recorded
+type queryTx struct {
+	*sql.Tx
+	stats *queryStats
+}
+
+func (tx *queryTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	name, start := queryName(), time.Now()
+	rows, err := tx.Tx.QueryContext(ctx, query, args...)
+	tx.stats.record(name, query, start, err)
+	return rows, err
+}
+
+func (tx *queryTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	name, start := queryName(), time.Now()
+	row := tx.Tx.QueryRowContext(ctx, query, args...)
+	tx.stats.record(name, query, start, row.Err())
+	return row
+}
+
+func (tx *queryTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	name, start := queryName(), time.Now()
+	result, err := tx.Tx.ExecContext(ctx, query, args...)
+	tx.stats.record(name, query, start, err)
+	return result, err
+}
+
+// PrepareContext prepares a statement whose executions are recorded under
+// the name of the method preparing it
+func (tx *queryTx) PrepareContext(ctx context.Context, query string) (*queryStmt, error) {
+	stmt, err := tx.Tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &queryStmt{Stmt: stmt, name: queryName(), query: query, stats: tx.stats}, nil
+}
+
+// queryStmt is a prepared PostgreSQL statement whose executions are recorded
+type queryStmt struct {
+	*sql.Stmt
+	name  string
+	query string
+	stats *queryStats
+}
+
+func (stmt *queryStmt) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := stmt.Stmt.ExecContext(ctx, args...)
+	stmt.stats.record(stmt.name, stmt.query, start, err)
+	return result, err
+}
+
+// queryConn is a ClickHouse connection whose queries and batch inserts are
+// recorded
+type queryConn struct {
+	driver.Conn
+	stats *queryStats
+}
+
+func (c *queryConn) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	name, start := queryName(), time.Now()
+	rows, err := c.Conn.Query(ctx, query, args...)
+	c.stats.record(name, query, start, err)
+	return rows, err
+}
+
+func (c *queryConn) QueryRow(ctx context.Context, query string, args ...any) driver.Row {
+	name, start := queryName(), time.Now()
+	row := c.Conn.QueryRow(ctx, query, args...)
+	c.stats.record(name, query, start, row.Err())
+	return row
+}
+
+func (c *queryConn) Exec(ctx context.Context, query string, args ...any) error {
+	name, start := queryName(), time.Now()
+	err := c.Conn.Exec(ctx, query, args...)
+	c.stats.record(name, query, start, err)
+	return err
+}
+
+// PrepareBatch prepares a batch insert whose sending is recorded under the
+// name of the method preparing it
+func (c *queryConn) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
+	batch, err := c.Conn.PrepareBatch(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &queryBatch{Batch: batch, name: queryName(), query: query, stats: c.stats}, nil
+}
+
+// queryBatch is a ClickHouse batch insert whose sending is recorded
+type queryBatch struct {
+	driver.Batch
+	name  string
+	query string
+	stats *queryStats
+}
+
+func (b *queryBatch) Send() error {
+	start := time.Now()
+	err := b.Batch.Send()
+	b.stats.record(b.name, b.query, start, err)
+	return err
+}
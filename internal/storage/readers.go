@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// AccountReader reads the state of accounts, for the API's account endpoints
+type AccountReader interface {
+	GetAccountBalances(ctx context.Context, refs []AccountRef) ([]types.Balance, error)
+	GetAccountDelegations(ctx context.Context, refs []AccountRef) ([]types.Delegation, error)
+	GetAccountUnbondings(ctx context.Context, refs []AccountRef) ([]types.UnbondingCompletion, error)
+	GetAccountStates(ctx context.Context, refs []AccountRef) ([]types.AccountState, error)
+	GetAccountActivity(ctx context.Context, refs []AccountRef, filter types.ActivityFilter, after string, limit int) ([]types.ActivityItem, error)
+	GetAccountRewards(ctx context.Context, refs []AccountRef) ([]types.DelegatorRewards, error)
+	GetCrossChainAccount(ctx context.Context, address string, refs []AccountRef, timeout time.Duration) *types.CrossChainAccount
+	GetPortfolioHoldings(ctx context.Context, refs []AccountRef, days int) ([]types.PortfolioHoldings, error)
+}
+
+// ValidatorReader reads validators, their delegators and validator sets
+type ValidatorReader interface {
+	EachValidator(ctx context.Context, chainName string, fn func(*types.Validator) error) error
+	GetValidator(ctx context.Context, chainName, operatorAddress string) (*types.Validator, error)
+	GetValidatorDelegators(ctx context.Context, chainName, validatorAddress string, limit, offset int) ([]types.ValidatorDelegator, int64, error)
+	GetCommissionChanges(ctx context.Context, chainName, operatorAddress string, limit int) ([]types.CommissionChange, error)
+	GetValidatorSetAt(ctx context.Context, chain string, height int64) (*types.ValidatorSetSnapshot, error)
+	GetConsumerValidatorSet(ctx context.Context, chainID string) (*types.ConsumerValidatorSet, error)
+	GetCrossChainValidators(ctx context.Context, chains []string, timeout time.Duration) (map[string][]types.Validator, []types.ChainError)
+}
+
+// GovReader reads governance proposals and scheduled upgrades
+type GovReader interface {
+	GetProposals(ctx context.Context, chainName, status string, limit, offset int) ([]types.Proposal, int64, error)
+	GetProposal(ctx context.Context, chainName string, proposalID uint64) (*types.Proposal, error)
+	GetUpgradeCalendar(ctx context.Context, chains []string, timeout time.Duration) ([]types.ChainUpgrade, []types.ChainError)
+}
+
+// AnalyticsReader reads chain statistics and the daily analytics aggregated
+// in ClickHouse. Daily analytics return ErrUnavailable without ClickHouse.
+type AnalyticsReader interface {
+	GetChainStats(ctx context.Context, chain string) (*types.ChainStats, error)
+	GetDailyActiveAddresses(ctx context.Context, chainName string, days int) ([]types.DailyActiveAddresses, error)
+	GetDailyDelegationVolume(ctx context.Context, chainName string, days int) ([]types.DailyDelegationVolume, error)
+	GetDailyRewardIssuance(ctx context.Context, chainName string, days int) ([]types.DailyRewardIssuance, error)
+	GetBalanceHistory(ctx context.Context, q BalanceHistoryQuery) ([]types.BalancePoint, error)
+}
+
+var (
+	_ AccountReader   = (*Manager)(nil)
+	_ ValidatorReader = (*Manager)(nil)
+	_ GovReader       = (*Manager)(nil)
+	_ AnalyticsReader = (*Manager)(nil)
+)
+
+// EachValidator calls fn with each validator of a chain as it is read
+func (m *Manager) EachValidator(ctx context.Context, chainName string, fn func(*types.Validator) error) error {
+	return m.state.EachValidator(ctx, chainName, fn)
+}
+
+// GetValidator returns a validator by its operator address
+func (m *Manager) GetValidator(ctx context.Context, chainName, operatorAddress string) (*types.Validator, error) {
+	return m.state.GetValidator(ctx, chainName, operatorAddress)
+}
+
+// GetValidatorDelegators returns a page of a validator's delegators and their
+// total count
+func (m *Manager) GetValidatorDelegators(ctx context.Context, chainName, validatorAddress string, limit, offset int) ([]types.ValidatorDelegator, int64, error) {
+	return m.state.GetValidatorDelegators(ctx, chainName, validatorAddress, limit, offset)
+}
+
+// GetCommissionChanges returns the latest commission changes of a validator,
+// newest first
+func (m *Manager) GetCommissionChanges(ctx context.Context, chainName, operatorAddress string, limit int) ([]types.CommissionChange, error) {
+	return m.Commissions().GetCommissionChanges(ctx, chainName, operatorAddress, limit)
+}
+
+// GetProposals returns a page of a chain's proposals, newest first, and their
+// total count
+func (m *Manager) GetProposals(ctx context.Context, chainName, status string, limit, offset int) ([]types.Proposal, int64, error) {
+	return m.Proposals().GetProposals(ctx, chainName, status, limit, offset)
+}
+
+// GetProposal returns a proposal by its ID
+func (m *Manager) GetProposal(ctx context.Context, chainName string, proposalID uint64) (*types.Proposal, error) {
+	return m.Proposals().GetProposal(ctx, chainName, proposalID)
+}
+
+// GetDailyActiveAddresses returns a chain's active addresses per day
+func (m *Manager) GetDailyActiveAddresses(ctx context.Context, chainName string, days int) ([]types.DailyActiveAddresses, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("active addresses require analytics storage: %w", ErrUnavailable)
+	}
+	return m.clickhouse.GetDailyActiveAddresses(ctx, chainName, days)
+}
+
+// GetDailyDelegationVolume returns a chain's delegation volume per day
+func (m *Manager) GetDailyDelegationVolume(ctx context.Context, chainName string, days int) ([]types.DailyDelegationVolume, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("delegation volume requires analytics storage: %w", ErrUnavailable)
+	}
+	return m.clickhouse.GetDailyDelegationVolume(ctx, chainName, days)
+}
+
+// GetDailyRewardIssuance returns a chain's reward issuance per day
+func (m *Manager) GetDailyRewardIssuance(ctx context.Context, chainName string, days int) ([]types.DailyRewardIssuance, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("reward issuance requires analytics storage: %w", ErrUnavailable)
+	}
+	return m.clickhouse.GetDailyRewardIssuance(ctx, chainName, days)
+}
+
+// GetBalanceHistory returns the balance history of an address in a denom at
+// the query's resolution
+func (m *Manager) GetBalanceHistory(ctx context.Context, q BalanceHistoryQuery) ([]types.BalancePoint, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("balance history requires analytics storage: %w", ErrUnavailable)
+	}
+	return m.clickhouse.GetBalanceHistory(ctx, q)
+}
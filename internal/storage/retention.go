@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PruneBalanceHistory deletes balance history rows recorded before the cutoff
+func (s *PostgresStore) PruneBalanceHistory(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return s.deleteInBatches(ctx, `
+		DELETE FROM balance_history
+		WHERE id IN (
+			SELECT id FROM balance_history
+			WHERE recorded_at < $1
+			LIMIT $2
+		)
+	`, batchSize, before)
+}
+
+// PruneDelegationHistory deletes delegation history rows recorded before the cutoff
+func (s *PostgresStore) PruneDelegationHistory(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return s.deleteInBatches(ctx, `
+		DELETE FROM delegation_history
+		WHERE id IN (
+			SELECT id FROM delegation_history
+			WHERE recorded_at < $1
+			LIMIT $2
+		)
+	`, batchSize, before)
+}
+
+// PruneDustHistory deletes all balance history for accounts whose every current
+// balance is below the dust threshold, keeping only their latest state
+func (s *PostgresStore) PruneDustHistory(ctx context.Context, threshold string, batchSize int) (int64, error) {
+	return s.deleteInBatches(ctx, `
+		DELETE FROM balance_history
+		WHERE id IN (
+			SELECT h.id FROM balance_history h
+			WHERE NOT EXISTS (
+				SELECT 1 FROM balances b
+				WHERE b.chain_name = h.chain_name
+				  AND b.address = h.address
+				  AND b.amount >= $1::DECIMAL
+			)
+			LIMIT $2
+		)
+	`, batchSize, threshold)
+}
+
+// PruneStaleAccounts deletes accounts not updated since the cutoff that hold no
+// balance and no delegations, along with their zero balance rows. It returns
+// the rows deleted from both tables.
+func (s *PostgresStore) PruneStaleAccounts(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	balances, err := s.deleteInBatches(ctx, `
+		DELETE FROM balances
+		WHERE id IN (
+			SELECT b.id FROM balances b
+			JOIN accounts a ON a.chain_name = b.chain_name AND a.address = b.address
+			WHERE a.updated_at < $1 AND b.amount = 0
+			  AND NOT EXISTS (
+				SELECT 1 FROM balances nb
+				WHERE nb.chain_name = a.chain_name AND nb.address = a.address AND nb.amount <> 0
+			  )
+			  AND NOT EXISTS (
+				SELECT 1 FROM delegations d
+				WHERE d.chain_name = a.chain_name AND d.delegator_address = a.address
+			  )
+			LIMIT $2
+		)
+	`, batchSize, before)
+	if err != nil {
+		return balances, err
+	}
+
+	accounts, err := s.deleteInBatches(ctx, `
+		DELETE FROM accounts
+		WHERE id IN (
+			SELECT a.id FROM accounts a
+			WHERE a.updated_at < $1
+			  AND NOT EXISTS (
+				SELECT 1 FROM balances b
+				WHERE b.chain_name = a.chain_name AND b.address = a.address
+			  )
+			  AND NOT EXISTS (
+				SELECT 1 FROM delegations d
+				WHERE d.chain_name = a.chain_name AND d.delegator_address = a.address
+			  )
+			LIMIT $2
+		)
+	`, batchSize, before)
+	return balances + accounts, err
+}
+
+// deleteInBatches repeatedly runs a batched DELETE until it affects no rows.
+// The query must take the batch size as its last positional parameter.
+func (s *PostgresStore) deleteInBatches(ctx context.Context, query string, batchSize int, args ...interface{}) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = 10000
+	}
+	args = append(args, batchSize)
+
+	var total int64
+	for {
+		result, err := s.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return total, fmt.Errorf("failed to prune rows: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, fmt.Errorf("failed to read affected rows: %w", err)
+		}
+
+		total += affected
+		if affected < int64(batchSize) {
+			return total, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+func TestMemoryPruneStaleAccounts(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	stale := cutoff.Add(-time.Hour)
+
+	account := func(address string, updatedAt time.Time) {
+		store.state.accounts[memKey{chain: "cosmoshub", a: address}] = types.Account{ChainName: "cosmoshub", Address: address, UpdatedAt: updatedAt}
+	}
+	balance := func(address, denom, amount string) {
+		store.state.balances[memKey{chain: "cosmoshub", a: address, b: denom}] = types.Balance{ChainName: "cosmoshub", Address: address, Denom: denom, Amount: amount}
+	}
+
+	account("empty", stale)
+	balance("empty", "uatom", "0")
+	account("holder", stale)
+	balance("holder", "uatom", "0")
+	balance("holder", "uosmo", "5")
+	account("delegator", stale)
+	balance("delegator", "uatom", "0")
+	store.state.delegations[memKey{chain: "cosmoshub", a: "delegator", b: "val1"}] = types.Delegation{ChainName: "cosmoshub", DelegatorAddress: "delegator", ValidatorAddress: "val1", Shares: "10"}
+	account("recent", cutoff.Add(time.Hour))
+	balance("recent", "uatom", "0")
+
+	deleted, err := store.PruneStaleAccounts(ctx, cutoff, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Errorf("PruneStaleAccounts() = %d, want the empty account and its balance", deleted)
+	}
+
+	for _, address := range []string{"holder", "delegator", "recent"} {
+		if _, ok := store.state.accounts[memKey{chain: "cosmoshub", a: address}]; !ok {
+			t.Errorf("account %s was pruned", address)
+		}
+		if _, ok := store.state.balances[memKey{chain: "cosmoshub", a: address, b: "uatom"}]; !ok {
+			t.Errorf("zero balance of kept account %s was pruned", address)
+		}
+	}
+	if _, ok := store.state.accounts[memKey{chain: "cosmoshub", a: "empty"}]; ok {
+		t.Error("empty stale account was kept")
+	}
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrCustomTablesUnsupported is returned by stores and transactions that cannot
+// hold the tables of custom modules
+var ErrCustomTablesUnsupported = errors.New("custom module tables are not supported by this store")
+
+// SchemaStore creates the tables of custom modules
+type SchemaStore interface {
+	ApplySchema(ctx context.Context, statements []string) error
+}
+
+var (
+	_ SchemaStore = (*PostgresStore)(nil)
+	_ SchemaStore = (*CockroachStore)(nil)
+	_ SchemaStore = (*MemoryStore)(nil)
+)
+
+// ApplySchema runs schema statements in one transaction
+func (s *PostgresStore) ApplySchema(ctx context.Context, statements []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to apply schema statement: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Exec runs a statement against a custom module's table
+func (tx *PostgresTx) Exec(ctx context.Context, query string, args ...any) error {
+	if _, err := tx.tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to execute statement: %w", err)
+	}
+	return nil
+}
+
+// ApplySchema fails unless there is nothing to apply
+func (s *MemoryStore) ApplySchema(ctx context.Context, statements []string) error {
+	if len(statements) > 0 {
+		return ErrCustomTablesUnsupported
+	}
+	return nil
+}
+
+// Exec fails; the in-memory store has no custom tables
+func (tx *memoryTx) Exec(ctx context.Context, query string, args ...any) error {
+	return ErrCustomTablesUnsupported
+}
+
+// Exec fails; statements against custom tables are not logged, so a module
+// writing them retries once the state store is reachable again
+func (tx *walTx) Exec(ctx context.Context, query string, args ...any) error {
+	return fmt.Errorf("custom module writes cannot be logged while the state store is unreachable")
+}
+
+// ApplySchema creates the tables of custom modules in the state store
+func (m *Manager) ApplySchema(ctx context.Context, statements []string) error {
+	return m.state.(SchemaStore).ApplySchema(ctx, statements)
+}
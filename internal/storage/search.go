@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// likeEscaper escapes LIKE wildcards in user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search finds validators by moniker, proposals by title, denoms, and validator
+// and account addresses by prefix. Results are ordered by trigram similarity;
+// an empty chains list searches every chain.
+func (s *PostgresStore) Search(ctx context.Context, term string, chains []string, limit int) ([]types.SearchResult, error) {
+	escaped := likeEscaper.Replace(term)
+	contains := "%" + escaped + "%"
+	prefix := escaped + "%"
+
+	// A validator matching both by moniker and by address is listed once, with
+	// its best score
+	query := `
+		SELECT kind, chain_name, id, label, score
+		FROM (
+			SELECT DISTINCT ON (kind, chain_name, id) kind, chain_name, id, label, score
+			FROM (
+				SELECT $6::TEXT AS kind, chain_name, operator_address AS id, COALESCE(moniker, '') AS label,
+				       similarity(moniker, $1) AS score
+				FROM validators
+				WHERE (moniker ILIKE $2 OR moniker % $1)
+				  AND ($4::text[] IS NULL OR chain_name = ANY($4))
+
+				UNION ALL
+
+				SELECT $7::TEXT, chain_name, proposal_id::TEXT, COALESCE(content->>'title', ''),
+				       similarity(content->>'title', $1)
+				FROM proposals
+				WHERE (content->>'title' ILIKE $2 OR content->>'title' % $1)
+				  AND ($4::text[] IS NULL OR chain_name = ANY($4))
+
+				UNION ALL
+
+				SELECT $8::TEXT, chain_name, denom, denom, similarity(denom, $1)
+				FROM supply
+				WHERE denom ILIKE $2
+				  AND ($4::text[] IS NULL OR chain_name = ANY($4))
+
+				UNION ALL
+
+				SELECT $6::TEXT, chain_name, operator_address, COALESCE(moniker, ''), 1.0
+				FROM validators
+				WHERE operator_address LIKE $3
+				  AND ($4::text[] IS NULL OR chain_name = ANY($4))
+
+				UNION ALL
+
+				SELECT $9::TEXT, chain_name, address, address, 1.0
+				FROM accounts
+				WHERE address LIKE $3
+				  AND ($4::text[] IS NULL OR chain_name = ANY($4))
+			) matches
+			ORDER BY kind, chain_name, id, score DESC
+		) results
+		ORDER BY score DESC, label
+		LIMIT $5
+	`
+
+	var chainFilter interface{}
+	if len(chains) > 0 {
+		chainFilter = pq.Array(chains)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, term, contains, prefix, chainFilter, limit,
+		types.SearchKindValidator, types.SearchKindProposal, types.SearchKindDenom, types.SearchKindAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	results := []types.SearchResult{}
+	for rows.Next() {
+		var result types.SearchResult
+		if err := rows.Scan(&result.Kind, &result.ChainName, &result.ID, &result.Label, &result.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
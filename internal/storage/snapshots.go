@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// SnapshotStore reads account snapshots at a height from the balance and
+// delegation history, passing each entry of at least minAmount to fn, largest
+// amount first, then by address. Only the SQL stores keep history.
+type SnapshotStore interface {
+	EachHolder(ctx context.Context, chainName, denom string, height int64, minAmount string, fn func(types.SnapshotEntry) error) error
+	EachDelegator(ctx context.Context, chainName string, height int64, minAmount string, fn func(types.SnapshotEntry) error) error
+	BalancesAt(ctx context.Context, chainName, address string, height int64) ([]types.Balance, error)
+}
+
+var (
+	_ SnapshotStore = (*PostgresStore)(nil)
+	_ SnapshotStore = (*CockroachStore)(nil)
+)
+
+// EachHolder reads the non-zero balance of a denom of every address as of a
+// height, from the latest history row at or below it
+func (s *PostgresStore) EachHolder(ctx context.Context, chainName, denom string, height int64, minAmount string, fn func(types.SnapshotEntry) error) error {
+	return s.eachSnapshotEntry(ctx, `
+		SELECT DISTINCT ON (address) address, amount
+		FROM balance_history
+		WHERE chain_name = $1 AND denom = $2 AND height <= $3
+		ORDER BY address, height DESC, id DESC
+	`, fn, chainName, denom, height, minAmount)
+}
+
+// EachDelegator reads the tokens delegated by every delegator as of a height.
+// Shares come from the delegation history and are converted to tokens at each
+// validator's current token/share ratio.
+func (s *PostgresStore) EachDelegator(ctx context.Context, chainName string, height int64, minAmount string, fn func(types.SnapshotEntry) error) error {
+	return s.eachSnapshotEntry(ctx, `
+		SELECT d.delegator_address AS address, floor(sum(d.shares * v.tokens / v.delegator_shares)) AS amount
+		FROM (
+			SELECT DISTINCT ON (delegator_address, validator_address) delegator_address, validator_address, shares
+			FROM delegation_history
+			WHERE chain_name = $1 AND height <= $2
+			ORDER BY delegator_address, validator_address, height DESC, id DESC
+		) d
+		JOIN validators v ON v.chain_name = $1 AND v.operator_address = d.validator_address
+		WHERE d.shares > 0 AND v.delegator_shares > 0
+		GROUP BY d.delegator_address
+	`, fn, chainName, height, minAmount)
+}
+
+// BalancesAt returns the balance of each denom of an address as of a height,
+// from the latest history row at or below it
+func (s *PostgresStore) BalancesAt(ctx context.Context, chainName, address string, height int64) ([]types.Balance, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT ON (denom) denom, amount::text, height
+		FROM balance_history
+		WHERE chain_name = $1 AND address = $2 AND height <= $3
+		ORDER BY denom, height DESC, id DESC
+	`, chainName, address, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balance history: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []types.Balance
+	for rows.Next() {
+		balance := types.Balance{ChainName: chainName, Address: address}
+		if err := rows.Scan(&balance.Denom, &balance.Amount, &balance.Height); err != nil {
+			return nil, fmt.Errorf("failed to scan balance history: %w", err)
+		}
+		balances = append(balances, balance)
+	}
+	return balances, rows.Err()
+}
+
+// eachSnapshotEntry runs a snapshot query selecting address and amount rows,
+// whose last argument is the minimum amount, and passes the positive amounts
+// of at least the minimum to fn in snapshot order. Rows are read as fn
+// consumes them, so snapshots of any size are never held in memory.
+func (s *PostgresStore) eachSnapshotEntry(ctx context.Context, query string, fn func(types.SnapshotEntry) error, args ...any) error {
+	query = fmt.Sprintf(`
+		SELECT address, amount::text
+		FROM (%s) snapshot
+		WHERE amount > 0 AND amount >= $%d::numeric
+		ORDER BY amount DESC, address
+	`, query, len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry types.SnapshotEntry
+		if err := rows.Scan(&entry.Address, &entry.Amount); err != nil {
+			return fmt.Errorf("failed to scan snapshot entry: %w", err)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// EachSnapshotEntry passes each entry of a holder or delegator snapshot to fn,
+// leaving out amounts below the minimum, the excluded addresses and addresses
+// labeled with an excluded category. Entries are ordered by amount, largest
+// first, then by address, so the same history always yields the same
+// snapshot. Entries are streamed from the database as fn consumes them.
+func (m *Manager) EachSnapshotEntry(ctx context.Context, filter types.SnapshotFilter, fn func(types.SnapshotEntry) error) error {
+	store, ok := m.state.(SnapshotStore)
+	if !ok {
+		return fmt.Errorf("snapshots are not supported by the %s store, which keeps no state history: %w", m.driver, ErrUnavailable)
+	}
+
+	minAmount := "0"
+	if filter.MinAmount != "" {
+		if _, ok := new(big.Int).SetString(filter.MinAmount, 10); !ok {
+			return fmt.Errorf("invalid minimum amount %q", filter.MinAmount)
+		}
+		minAmount = filter.MinAmount
+	}
+
+	excluded := make(map[string]bool, len(filter.ExcludeAddresses))
+	for _, address := range filter.ExcludeAddresses {
+		excluded[address] = true
+	}
+	if len(filter.ExcludeLabels) > 0 {
+		labels, err := m.Labels().GetLabels(ctx, types.LabelFilter{ChainName: filter.ChainName, Categories: filter.ExcludeLabels})
+		if err != nil {
+			return err
+		}
+		for _, label := range labels {
+			excluded[label.Address] = true
+		}
+	}
+
+	keep := func(entry types.SnapshotEntry) error {
+		if excluded[entry.Address] {
+			return nil
+		}
+		return fn(entry)
+	}
+
+	switch filter.Kind {
+	case types.SnapshotHolders:
+		return store.EachHolder(ctx, filter.ChainName, filter.Denom, filter.Height, minAmount, keep)
+	case types.SnapshotDelegators:
+		return store.EachDelegator(ctx, filter.ChainName, filter.Height, minAmount, keep)
+	default:
+		return fmt.Errorf("unknown snapshot kind %q", filter.Kind)
+	}
+}
+
+// ReconstructBalances reconstructs the balances of an address at a past time,
+// or of denom only when given, by folding its balance events, and cross-checks
+// them against the balance history as of the chain's height at that time. The
+// height is that of the latest block recorded at or before the time, or of
+// the latest event folded when no blocks are recorded. The check is skipped
+// by stores that keep no history.
+func (m *Manager) ReconstructBalances(ctx context.Context, chainName, address, denom string, at time.Time) (*types.BalanceReconstruction, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("balance reconstruction requires analytics storage: %w", ErrUnavailable)
+	}
+
+	folded, err := m.clickhouse.FoldBalanceEvents(ctx, chainName, address, denom, at)
+	if err != nil {
+		return nil, err
+	}
+	height, err := m.clickhouse.HeightAt(ctx, chainName, at)
+	if err != nil {
+		return nil, err
+	}
+	if height == 0 {
+		for _, balance := range folded {
+			height = max(height, balance.EventHeight)
+		}
+	}
+
+	index := make(map[string]int, len(folded))
+	for i, balance := range folded {
+		index[balance.Denom] = i
+	}
+
+	result := &types.BalanceReconstruction{
+		ChainName: chainName,
+		Address:   address,
+		Time:      at,
+		Height:    height,
+	}
+	if store, ok := m.state.(SnapshotStore); ok && height > 0 {
+		snapshot, err := store.BalancesAt(ctx, chainName, address, height)
+		if err != nil {
+			return nil, err
+		}
+		result.SnapshotChecked = true
+
+		for _, balance := range snapshot {
+			if denom != "" && balance.Denom != denom {
+				continue
+			}
+			i, ok := index[balance.Denom]
+			if !ok {
+				i = len(folded)
+				folded = append(folded, types.ReconstructedBalance{Denom: balance.Denom, Amount: "0"})
+			}
+			folded[i].SnapshotAmount = balance.Amount
+			folded[i].SnapshotHeight = balance.Height
+		}
+	}
+
+	result.Balances = make([]types.ReconstructedBalance, 0, len(folded))
+	for _, balance := range folded {
+		amount := parseAmount(balance.Amount)
+		if result.SnapshotChecked {
+			if balance.SnapshotAmount == "" {
+				balance.SnapshotAmount = "0"
+			}
+			if difference := new(big.Int).Sub(parseAmount(balance.SnapshotAmount), amount); difference.Sign() != 0 {
+				balance.Difference = difference.String()
+				result.Discrepancies++
+			}
+		}
+		if amount.Sign() == 0 && balance.Difference == "" {
+			continue // no balance in either source
+		}
+		result.Balances = append(result.Balances, balance)
+	}
+	sort.Slice(result.Balances, func(i, j int) bool {
+		return result.Balances[i].Denom < result.Balances[j].Denom
+	})
+	return result, nil
+}
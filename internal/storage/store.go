@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// StateStore is the relational store holding current chain state
+type StateStore interface {
+	Ping(ctx context.Context) error
+	Close() error
+	BeginTx(ctx context.Context) (StateTx, error)
+
+	GetChains(ctx context.Context) ([]types.ChainInfo, error)
+	GetChain(ctx context.Context, name string) (*types.ChainInfo, error)
+
+	GetAccount(ctx context.Context, chainName, address string) (*types.Account, error)
+	CountAccounts(ctx context.Context, chainName string) (int64, error)
+	GetBalances(ctx context.Context, chainName, address string) ([]types.Balance, error)
+	GetDelegations(ctx context.Context, chainName, delegatorAddress string) ([]types.Delegation, error)
+	GetUnbondingDelegations(ctx context.Context, chainName, delegatorAddress string) ([]types.UnbondingDelegation, error)
+	GetRedelegations(ctx context.Context, chainName, delegatorAddress string) ([]types.Redelegation, error)
+	GetTokenHoldings(ctx context.Context, chainName, address string) ([]types.TokenHolding, error)
+	SampleBalances(ctx context.Context, chainName string, limit int) ([]types.Balance, error)
+	SampleDelegations(ctx context.Context, chainName string, limit int) ([]types.Delegation, error)
+
+	GetValidators(ctx context.Context, chainName string) ([]types.Validator, error)
+	EachValidator(ctx context.Context, chainName string, fn func(*types.Validator) error) error
+	GetValidator(ctx context.Context, chainName, operatorAddress string) (*types.Validator, error)
+	GetValidatorByConsensusAddress(ctx context.Context, chainName, consensusAddress string) (*types.Validator, error)
+	GetValidatorDelegators(ctx context.Context, chainName, validatorAddress string, limit, offset int) ([]types.ValidatorDelegator, int64, error)
+	GetValidatorSummary(ctx context.Context, chainName string) (total, active int64, bondedTokens string, err error)
+	GetConsensusAddressMap(ctx context.Context, chainName string) (map[string]string, error)
+	GetUnbondingSchedule(ctx context.Context, chainName string) ([]types.UnbondingBucket, error)
+	GetConsumerChain(ctx context.Context, chainID string) (*types.ConsumerChain, error)
+
+	GetSupply(ctx context.Context, chainName string) ([]types.Supply, error)
+	GetCommunityPool(ctx context.Context, chainName string) ([]types.PoolBalance, error)
+	GetModuleAccounts(ctx context.Context, chainName string) ([]types.ModuleAccount, error)
+	GetVestingLocked(ctx context.Context, chainName, denom string) (string, error)
+	GetMintParams(ctx context.Context, chainName string) (*types.MintParams, error)
+
+	GetLatestBlockHeight(ctx context.Context, chainName string) (int64, error)
+	GetBlocks(ctx context.Context, chainName string, before int64, limit int) ([]types.Block, error)
+	GetBlock(ctx context.Context, chainName string, height int64) (*types.Block, error)
+
+	Search(ctx context.Context, term string, chains []string, limit int) ([]types.SearchResult, error)
+
+	PruneBalanceHistory(ctx context.Context, before time.Time, batchSize int) (int64, error)
+	PruneDelegationHistory(ctx context.Context, before time.Time, batchSize int) (int64, error)
+	PruneDustHistory(ctx context.Context, threshold string, batchSize int) (int64, error)
+	PruneStaleAccounts(ctx context.Context, before time.Time, batchSize int) (int64, error)
+}
+
+// StateTx is a write transaction against a StateStore. Upserts of state
+// recorded at a height keep the stored row when it is from a later height, so
+// that redecoding archived changes does not rewind the latest state.
+type StateTx interface {
+	Commit() error
+	Rollback() error
+
+	UpsertChain(ctx context.Context, chain *types.ChainInfo) error
+	UpsertChainStatus(ctx context.Context, status *types.ChainStatus) error
+
+	UpsertAccount(ctx context.Context, account *types.Account) error
+	UpsertBalance(ctx context.Context, balance *types.Balance) error
+	UpsertBalances(ctx context.Context, balances []types.Balance) error
+	UpsertDelegation(ctx context.Context, delegation *types.Delegation) error
+	DeleteDelegation(ctx context.Context, chainName, delegatorAddress, validatorAddress string) error
+	UpsertTokenContracts(ctx context.Context, contracts []types.TokenContract) error
+	UpsertTokenHoldings(ctx context.Context, holdings []types.TokenHolding) error
+	ReplaceUnbondingDelegations(ctx context.Context, chainName string, unbondings []types.UnbondingDelegation) error
+	ReplaceRedelegations(ctx context.Context, chainName string, redelegations []types.Redelegation) error
+
+	UpsertValidator(ctx context.Context, validator *types.Validator) error
+	UpsertConsensusAddress(ctx context.Context, chainName, consensusAddress, operatorAddress string, height int64) error
+	InsertCommissionChange(ctx context.Context, change *types.CommissionChange) error
+	ReplaceConsumerChains(ctx context.Context, providerChain string, consumers []types.ConsumerChain) error
+
+	UpsertSupply(ctx context.Context, supply []types.Supply) error
+	ReplaceCommunityPool(ctx context.Context, chainName string, pool []types.PoolBalance) error
+	ReplaceModuleAccounts(ctx context.Context, chainName string, accounts []types.ModuleAccount) error
+	ReplaceVestingLocked(ctx context.Context, chainName string, locked []types.VestingLocked) error
+	UpsertMintParams(ctx context.Context, params *types.MintParams) error
+	UpsertProposals(ctx context.Context, proposals []types.Proposal) error
+	ReplaceUpgradePlan(ctx context.Context, chainName string, plan *types.UpgradePlan) error
+	UpsertChainParams(ctx context.Context, params *types.ChainParams) error
+
+	InsertBlocks(ctx context.Context, blocks []types.Block) error
+
+	EnqueueOutbox(ctx context.Context, messages []types.OutboxMessage) error
+
+	// Exec writes to the tables of custom modules
+	Exec(ctx context.Context, query string, args ...any) error
+}
+
+var (
+	_ StateStore = (*PostgresStore)(nil)
+	_ StateStore = (*CockroachStore)(nil)
+	_ StateStore = (*MemoryStore)(nil)
+	_ StateTx    = (*PostgresTx)(nil)
+	_ StateTx    = (*cockroachTx)(nil)
+	_ StateTx    = (*memoryTx)(nil)
+	_ StateTx    = (*walTx)(nil)
+
+	_ TenantStore = (*PostgresStore)(nil)
+	_ TenantStore = (*CockroachStore)(nil)
+	_ TenantStore = (*MemoryStore)(nil)
+)
@@ -0,0 +1,345 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/lib/pq"
+)
+
+// Module accounts the supply breakdown reports separately
+const (
+	bondedPoolModule    = "bonded_tokens_pool"
+	notBondedPoolModule = "not_bonded_tokens_pool"
+	distributionModule  = "distribution"
+)
+
+// GetModuleAccounts returns the module accounts of a chain ordered by name
+func (s *PostgresStore) GetModuleAccounts(ctx context.Context, chainName string) ([]types.ModuleAccount, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain_name, name, address, permissions, height, updated_at
+		FROM module_accounts
+		WHERE chain_name = $1
+		ORDER BY name
+	`, chainName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query module accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []types.ModuleAccount
+	for rows.Next() {
+		var account types.ModuleAccount
+		err := rows.Scan(
+			&account.ChainName,
+			&account.Name,
+			&account.Address,
+			pq.Array(&account.Permissions),
+			&account.Height,
+			&account.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan module account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// GetCommunityPool returns the community pool of a chain ordered by denom
+func (s *PostgresStore) GetCommunityPool(ctx context.Context, chainName string) ([]types.PoolBalance, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain_name, denom, amount, height, updated_at
+		FROM community_pool
+		WHERE chain_name = $1
+		ORDER BY denom
+	`, chainName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query community pool: %w", err)
+	}
+	defer rows.Close()
+
+	var pool []types.PoolBalance
+	for rows.Next() {
+		var coin types.PoolBalance
+		if err := rows.Scan(&coin.ChainName, &coin.Denom, &coin.Amount, &coin.Height, &coin.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan community pool: %w", err)
+		}
+		pool = append(pool, coin)
+	}
+
+	return pool, rows.Err()
+}
+
+// ReplaceModuleAccounts replaces the module accounts of a chain
+func (tx *PostgresTx) ReplaceModuleAccounts(ctx context.Context, chainName string, accounts []types.ModuleAccount) error {
+	if _, err := tx.tx.ExecContext(ctx, `DELETE FROM module_accounts WHERE chain_name = $1`, chainName); err != nil {
+		return fmt.Errorf("failed to delete module accounts: %w", err)
+	}
+
+	for _, account := range accounts {
+		permissions := account.Permissions
+		if permissions == nil {
+			permissions = []string{}
+		}
+
+		_, err := tx.tx.ExecContext(ctx, `
+			INSERT INTO module_accounts (chain_name, name, address, permissions, height, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, chainName, account.Name, account.Address, pq.Array(permissions), account.Height, account.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert module account %s: %w", account.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ReplaceCommunityPool replaces the community pool of a chain
+func (tx *PostgresTx) ReplaceCommunityPool(ctx context.Context, chainName string, pool []types.PoolBalance) error {
+	if _, err := tx.tx.ExecContext(ctx, `DELETE FROM community_pool WHERE chain_name = $1`, chainName); err != nil {
+		return fmt.Errorf("failed to delete community pool: %w", err)
+	}
+
+	for _, coin := range pool {
+		_, err := tx.tx.ExecContext(ctx, `
+			INSERT INTO community_pool (chain_name, denom, amount, height, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, chainName, coin.Denom, coin.Amount, coin.Height, coin.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert community pool %s: %w", coin.Denom, err)
+		}
+	}
+
+	return nil
+}
+
+// GetVestingLocked returns the total amount of a denom locked in the vesting
+// accounts of a chain
+func (s *PostgresStore) GetVestingLocked(ctx context.Context, chainName, denom string) (string, error) {
+	var total string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0)::TEXT
+		FROM vesting_locked
+		WHERE chain_name = $1 AND denom = $2
+	`, chainName, denom).Scan(&total)
+	if err != nil {
+		return "", fmt.Errorf("failed to query vesting locked: %w", err)
+	}
+
+	return total, nil
+}
+
+// ReplaceVestingLocked replaces the vesting locked amounts of a chain
+func (tx *PostgresTx) ReplaceVestingLocked(ctx context.Context, chainName string, locked []types.VestingLocked) error {
+	if _, err := tx.tx.ExecContext(ctx, `DELETE FROM vesting_locked WHERE chain_name = $1`, chainName); err != nil {
+		return fmt.Errorf("failed to delete vesting locked: %w", err)
+	}
+
+	for _, l := range locked {
+		_, err := tx.tx.ExecContext(ctx, `
+			INSERT INTO vesting_locked (chain_name, address, denom, type, amount, end_time, height, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, chainName, l.Address, l.Denom, l.Type, l.Amount, l.EndTime, l.Height, l.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert vesting locked of %s: %w", l.Address, err)
+		}
+	}
+
+	return nil
+}
+
+// GetSupplyBreakdown splits the total supply of a denom into the bonded and
+// unbonding pools, the community pool, unclaimed rewards, other module
+// accounts and the circulating remainder. The distribution module account
+// holds both the community pool and unclaimed rewards, so rewards are its
+// balance less the pool.
+func (m *Manager) GetSupplyBreakdown(ctx context.Context, chain, denom string) (*types.SupplyBreakdown, error) {
+	supply, err := m.state.GetSupply(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := &types.SupplyBreakdown{ChainName: chain, Denom: denom, Modules: []types.ModuleBalance{}}
+	total, found := new(big.Int), false
+	for _, coin := range supply {
+		if coin.Denom == denom {
+			total, found = parseAmount(coin.Amount), true
+			breakdown.Height = coin.Height
+			breakdown.UpdatedAt = coin.UpdatedAt
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("supply of %s: %w", denom, ErrNotFound)
+	}
+
+	pool := new(big.Int)
+	communityPool, err := m.state.GetCommunityPool(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	for _, coin := range communityPool {
+		if coin.Denom == denom {
+			pool = parseAmount(coin.Amount)
+		}
+	}
+
+	accounts, err := m.state.GetModuleAccounts(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	bonded, unbonding, distribution, escrow := new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	for _, account := range accounts {
+		amount, err := m.balanceOf(ctx, chain, account.Address, denom)
+		if err != nil {
+			return nil, err
+		}
+
+		switch account.Name {
+		case bondedPoolModule:
+			bonded.Add(bonded, amount)
+		case notBondedPoolModule:
+			unbonding.Add(unbonding, amount)
+		case distributionModule:
+			distribution.Add(distribution, amount)
+		default:
+			escrow.Add(escrow, amount)
+		}
+		if amount.Sign() > 0 {
+			breakdown.Modules = append(breakdown.Modules, types.ModuleBalance{
+				Name:    account.Name,
+				Address: account.Address,
+				Amount:  amount.String(),
+			})
+		}
+	}
+
+	rewards := new(big.Int).Sub(distribution, pool)
+	if rewards.Sign() < 0 {
+		rewards.SetInt64(0)
+	}
+	circulating := new(big.Int).Sub(total, bonded)
+	circulating.Sub(circulating, unbonding).Sub(circulating, distribution).Sub(circulating, escrow)
+	if circulating.Sign() < 0 {
+		circulating.SetInt64(0)
+	}
+
+	breakdown.Total = total.String()
+	breakdown.Bonded = bonded.String()
+	breakdown.Unbonding = unbonding.String()
+	breakdown.CommunityPool = pool.String()
+	breakdown.UnclaimedRewards = rewards.String()
+	breakdown.ModuleEscrow = escrow.String()
+	breakdown.Circulating = circulating.String()
+	return breakdown, nil
+}
+
+// GetCirculatingSupply returns the supply of a denom less the amounts locked
+// in vesting accounts, held by module accounts and held by the treasury
+// addresses. Treasury addresses that are also module accounts are counted once.
+func (m *Manager) GetCirculatingSupply(ctx context.Context, chain, denom string, treasury []string) (*types.CirculatingSupply, error) {
+	supply, err := m.state.GetSupply(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.CirculatingSupply{ChainName: chain, Denom: denom}
+	total, found := new(big.Int), false
+	for _, coin := range supply {
+		if coin.Denom == denom {
+			total, found = parseAmount(coin.Amount), true
+			result.Height = coin.Height
+			result.Timestamp = coin.UpdatedAt
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("supply of %s: %w", denom, ErrNotFound)
+	}
+
+	locked, err := m.state.GetVestingLocked(ctx, chain, denom)
+	if err != nil {
+		return nil, err
+	}
+	vesting := parseAmount(locked)
+
+	accounts, err := m.state.GetModuleAccounts(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	modules := new(big.Int)
+	counted := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		amount, err := m.balanceOf(ctx, chain, account.Address, denom)
+		if err != nil {
+			return nil, err
+		}
+		modules.Add(modules, amount)
+		counted[account.Address] = true
+	}
+
+	treasuries := new(big.Int)
+	for _, address := range treasury {
+		if counted[address] {
+			continue
+		}
+		amount, err := m.balanceOf(ctx, chain, address, denom)
+		if err != nil {
+			return nil, err
+		}
+		treasuries.Add(treasuries, amount)
+		counted[address] = true
+	}
+
+	circulating := new(big.Int).Sub(total, vesting)
+	circulating.Sub(circulating, modules).Sub(circulating, treasuries)
+	if circulating.Sign() < 0 {
+		circulating.SetInt64(0)
+	}
+
+	result.Total = total.String()
+	result.VestingLocked = vesting.String()
+	result.ModuleAccounts = modules.String()
+	result.Treasury = treasuries.String()
+	result.Circulating = circulating.String()
+	return result, nil
+}
+
+// GetCirculatingSupplyHistory returns the last circulating supply recorded on
+// each day since a time, oldest first
+func (m *Manager) GetCirculatingSupplyHistory(ctx context.Context, chain, denom string, since time.Time) ([]types.CirculatingSupply, error) {
+	if m.clickhouse == nil {
+		return nil, fmt.Errorf("circulating supply history requires analytics storage: %w", ErrUnavailable)
+	}
+	return m.clickhouse.GetCirculatingSupplyHistory(ctx, chain, denom, since)
+}
+
+// balanceOf returns the stored balance of a denom held by an address
+func (m *Manager) balanceOf(ctx context.Context, chain, address, denom string) (*big.Int, error) {
+	balances, err := m.state.GetBalances(ctx, chain, address)
+	if err != nil {
+		return nil, err
+	}
+	for _, balance := range balances {
+		if balance.Denom == denom {
+			return parseAmount(balance.Amount), nil
+		}
+	}
+	return new(big.Int), nil
+}
+
+// parseAmount parses the integer part of a stored amount, which may be a
+// decimal, treating malformed amounts as zero
+func parseAmount(amount string) *big.Int {
+	whole, _, _ := strings.Cut(amount, ".")
+	value, ok := new(big.Int).SetString(whole, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return value
+}
@@ -0,0 +1,789 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/lib/pq"
+)
+
+// PostgreSQL error codes for constraint violations
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// TenantStore holds tenants, their API keys and the user-generated rows owned
+// by them. Every tenant-owned row is read and written scoped to its tenant, so
+// the ID of another tenant's row behaves as if it did not exist.
+type TenantStore interface {
+	CreateTenant(ctx context.Context, tenant *types.Tenant) error
+	GetTenants(ctx context.Context) ([]types.Tenant, error)
+	GetTenant(ctx context.Context, id string) (*types.Tenant, error)
+	DeleteTenant(ctx context.Context, id string) error
+
+	CreateAPIKey(ctx context.Context, key *types.APIKey) error
+	GetAPIKeys(ctx context.Context, tenantID string) ([]types.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*types.APIKey, error)
+	RevokeAPIKey(ctx context.Context, tenantID, id string) error
+
+	CreateWatchlist(ctx context.Context, watchlist *types.Watchlist) error
+	GetWatchlists(ctx context.Context, tenantID string) ([]types.Watchlist, error)
+	GetWatchlist(ctx context.Context, tenantID, id string) (*types.Watchlist, error)
+	DeleteWatchlist(ctx context.Context, tenantID, id string) error
+	GetWatchedAddresses(ctx context.Context, chainName string) ([]string, error)
+
+	CreatePortfolio(ctx context.Context, portfolio *types.Portfolio) error
+	GetPortfolios(ctx context.Context, tenantID string) ([]types.Portfolio, error)
+	GetPortfolio(ctx context.Context, tenantID, id string) (*types.Portfolio, error)
+	DeletePortfolio(ctx context.Context, tenantID, id string) error
+
+	CreateEmailSubscription(ctx context.Context, subscription *types.EmailSubscription) error
+	GetEmailSubscriptions(ctx context.Context, tenantID string) ([]types.EmailSubscription, error)
+	DeleteEmailSubscription(ctx context.Context, tenantID, id string) error
+	Unsubscribe(ctx context.Context, token string) (*types.EmailSubscription, error)
+
+	CreateWebhook(ctx context.Context, webhook *types.Webhook) error
+	GetWebhooks(ctx context.Context, tenantID string) ([]types.Webhook, error)
+	GetWebhook(ctx context.Context, tenantID, id string) (*types.Webhook, error)
+	DeleteWebhook(ctx context.Context, tenantID, id string) error
+
+	CreateAlertRule(ctx context.Context, rule *types.AlertRule) error
+	GetAlertRules(ctx context.Context, tenantID string) ([]types.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, tenantID, id string) error
+
+	CreateDigest(ctx context.Context, digest *types.Digest) error
+	GetDigests(ctx context.Context, tenantID string) ([]types.Digest, error)
+	DeleteDigest(ctx context.Context, tenantID, id string) error
+	GetEnabledDigests(ctx context.Context) ([]types.Digest, error)
+	ClaimDigest(ctx context.Context, tenantID, id string, previous, sentAt *time.Time) (bool, error)
+
+	ClaimUnbondingReminder(ctx context.Context, tenantID string, completion types.UnbondingCompletion) (bool, error)
+	ReleaseUnbondingReminder(ctx context.Context, tenantID string, completion types.UnbondingCompletion) error
+	PruneUnbondingReminders(ctx context.Context, before time.Time) (int64, error)
+}
+
+// newID generates a random ID for a tenant-owned row
+func newID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate ID: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// newToken returns a random token that can't be guessed
+func newToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate token: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// constraintError maps a constraint violation to ErrExists or ErrNotFound
+func constraintError(err error, action string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return fmt.Errorf("%s: %w", action, ErrExists)
+		case foreignKeyViolation:
+			return fmt.Errorf("%s: referenced row %w", action, ErrNotFound)
+		}
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+// execScoped runs a statement that affects a single row and reports ErrNotFound
+// when no row matched
+func (s *PostgresStore) execScoped(ctx context.Context, action, query string, args ...any) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", action, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", action, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s: %w", action, ErrNotFound)
+	}
+	return nil
+}
+
+// CreateTenant stores a new tenant, assigning its ID
+func (s *PostgresStore) CreateTenant(ctx context.Context, tenant *types.Tenant) error {
+	tenant.ID = newID()
+	tenant.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO tenants (id, name, created_at) VALUES ($1, $2, $3)`,
+		tenant.ID, tenant.Name, tenant.CreatedAt)
+	if err != nil {
+		return constraintError(err, "create tenant")
+	}
+	return nil
+}
+
+// GetTenants returns all tenants
+func (s *PostgresStore) GetTenants(ctx context.Context) ([]types.Tenant, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, created_at FROM tenants ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenants: %w", err)
+	}
+	defer rows.Close()
+
+	tenants := []types.Tenant{}
+	for rows.Next() {
+		var tenant types.Tenant
+		if err := rows.Scan(&tenant.ID, &tenant.Name, &tenant.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	return tenants, rows.Err()
+}
+
+// GetTenant returns a tenant by ID
+func (s *PostgresStore) GetTenant(ctx context.Context, id string) (*types.Tenant, error) {
+	var tenant types.Tenant
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, name, created_at FROM tenants WHERE id = $1`,
+		id).Scan(&tenant.ID, &tenant.Name, &tenant.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tenant %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	return &tenant, nil
+}
+
+// DeleteTenant removes a tenant together with everything it owns
+func (s *PostgresStore) DeleteTenant(ctx context.Context, id string) error {
+	return s.execScoped(ctx, "delete tenant", `DELETE FROM tenants WHERE id = $1`, id)
+}
+
+// CreateAPIKey stores a new API key of a tenant, assigning its ID
+func (s *PostgresStore) CreateAPIKey(ctx context.Context, key *types.APIKey) error {
+	key.ID = newID()
+	key.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO api_keys (id, tenant_id, name, prefix, role, key_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, key.ID, key.TenantID, key.Name, key.Prefix, nullString(key.Role), key.KeyHash, key.CreatedAt)
+	if err != nil {
+		return constraintError(err, "create API key")
+	}
+	return nil
+}
+
+// GetAPIKeys returns the API keys of a tenant, including revoked ones
+func (s *PostgresStore) GetAPIKeys(ctx context.Context, tenantID string) ([]types.APIKey, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, name, prefix, COALESCE(role, ''), key_hash, created_at, revoked_at
+		FROM api_keys
+		WHERE tenant_id = $1
+		ORDER BY created_at
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []types.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+// GetAPIKeyByHash returns the API key with the given hash
+func (s *PostgresStore) GetAPIKeyByHash(ctx context.Context, keyHash string) (*types.APIKey, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, tenant_id, name, prefix, COALESCE(role, ''), key_hash, created_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1
+	`, keyHash)
+
+	key, err := scanAPIKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("API key: %w", ErrNotFound)
+	}
+	return key, err
+}
+
+// scanAPIKey scans an api_keys row
+func scanAPIKey(row interface{ Scan(...any) error }) (*types.APIKey, error) {
+	var key types.APIKey
+	var revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.TenantID, &key.Name, &key.Prefix, &key.Role, &key.KeyHash, &key.CreatedAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan API key: %w", err)
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey revokes an API key of a tenant
+func (s *PostgresStore) RevokeAPIKey(ctx context.Context, tenantID, id string) error {
+	return s.execScoped(ctx, "revoke API key", `
+		UPDATE api_keys SET revoked_at = NOW()
+		WHERE tenant_id = $1 AND id = $2 AND revoked_at IS NULL
+	`, tenantID, id)
+}
+
+// CreateWatchlist stores a new watchlist of a tenant, assigning its ID
+func (s *PostgresStore) CreateWatchlist(ctx context.Context, watchlist *types.Watchlist) error {
+	watchlist.ID = newID()
+	watchlist.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO watchlists (id, tenant_id, name, chain_name, addresses, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, watchlist.ID, watchlist.TenantID, watchlist.Name, watchlist.ChainName,
+		pq.Array(watchlist.Addresses), watchlist.CreatedAt)
+	if err != nil {
+		return constraintError(err, "create watchlist")
+	}
+	return nil
+}
+
+// GetWatchlists returns the watchlists of a tenant
+func (s *PostgresStore) GetWatchlists(ctx context.Context, tenantID string) ([]types.Watchlist, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, name, chain_name, addresses, created_at
+		FROM watchlists
+		WHERE tenant_id = $1
+		ORDER BY name
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchlists: %w", err)
+	}
+	defer rows.Close()
+
+	watchlists := []types.Watchlist{}
+	for rows.Next() {
+		var watchlist types.Watchlist
+		err := rows.Scan(
+			&watchlist.ID,
+			&watchlist.TenantID,
+			&watchlist.Name,
+			&watchlist.ChainName,
+			pq.Array(&watchlist.Addresses),
+			&watchlist.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist: %w", err)
+		}
+		watchlists = append(watchlists, watchlist)
+	}
+
+	return watchlists, rows.Err()
+}
+
+// GetWatchlist returns a watchlist of a tenant
+func (s *PostgresStore) GetWatchlist(ctx context.Context, tenantID, id string) (*types.Watchlist, error) {
+	var watchlist types.Watchlist
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, tenant_id, name, chain_name, addresses, created_at
+		FROM watchlists
+		WHERE tenant_id = $1 AND id = $2
+	`, tenantID, id).Scan(
+		&watchlist.ID,
+		&watchlist.TenantID,
+		&watchlist.Name,
+		&watchlist.ChainName,
+		pq.Array(&watchlist.Addresses),
+		&watchlist.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("watchlist %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+
+	return &watchlist, nil
+}
+
+// DeleteWatchlist removes a watchlist of a tenant and the alert rules using it
+func (s *PostgresStore) DeleteWatchlist(ctx context.Context, tenantID, id string) error {
+	return s.execScoped(ctx, "delete watchlist",
+		`DELETE FROM watchlists WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+}
+
+// CreatePortfolio stores a new portfolio of a tenant, assigning its ID
+func (s *PostgresStore) CreatePortfolio(ctx context.Context, portfolio *types.Portfolio) error {
+	accounts, err := json.Marshal(portfolio.Accounts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal portfolio accounts: %w", err)
+	}
+	portfolio.ID = newID()
+	portfolio.CreatedAt = time.Now().UTC()
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO portfolios (id, tenant_id, name, accounts, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, portfolio.ID, portfolio.TenantID, portfolio.Name, accounts, portfolio.CreatedAt)
+	if err != nil {
+		return constraintError(err, "create portfolio")
+	}
+	return nil
+}
+
+// GetPortfolios returns the portfolios of a tenant
+func (s *PostgresStore) GetPortfolios(ctx context.Context, tenantID string) ([]types.Portfolio, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, name, accounts, created_at
+		FROM portfolios
+		WHERE tenant_id = $1
+		ORDER BY name
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query portfolios: %w", err)
+	}
+	defer rows.Close()
+
+	portfolios := []types.Portfolio{}
+	for rows.Next() {
+		portfolio, err := scanPortfolio(rows)
+		if err != nil {
+			return nil, err
+		}
+		portfolios = append(portfolios, *portfolio)
+	}
+
+	return portfolios, rows.Err()
+}
+
+// GetPortfolio returns a portfolio of a tenant
+func (s *PostgresStore) GetPortfolio(ctx context.Context, tenantID, id string) (*types.Portfolio, error) {
+	portfolio, err := scanPortfolio(s.db.QueryRowContext(ctx, `
+		SELECT id, tenant_id, name, accounts, created_at
+		FROM portfolios
+		WHERE tenant_id = $1 AND id = $2
+	`, tenantID, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("portfolio %s: %w", id, ErrNotFound)
+	}
+	return portfolio, err
+}
+
+// scanPortfolio scans a portfolio row
+func scanPortfolio(row interface{ Scan(...any) error }) (*types.Portfolio, error) {
+	var portfolio types.Portfolio
+	var accounts []byte
+	err := row.Scan(&portfolio.ID, &portfolio.TenantID, &portfolio.Name, &accounts, &portfolio.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan portfolio: %w", err)
+	}
+	if err := json.Unmarshal(accounts, &portfolio.Accounts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal accounts of portfolio %s: %w", portfolio.ID, err)
+	}
+	return &portfolio, nil
+}
+
+// DeletePortfolio removes a portfolio of a tenant
+func (s *PostgresStore) DeletePortfolio(ctx context.Context, tenantID, id string) error {
+	return s.execScoped(ctx, "delete portfolio",
+		`DELETE FROM portfolios WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+}
+
+// CreateEmailSubscription stores a new email subscription of a tenant,
+// assigning its ID and unsubscribe token
+func (s *PostgresStore) CreateEmailSubscription(ctx context.Context, subscription *types.EmailSubscription) error {
+	subscription.ID = newID()
+	subscription.UnsubscribeToken = newToken()
+	subscription.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO email_subscriptions (id, tenant_id, email, events, enabled, unsubscribe_token, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, subscription.ID, subscription.TenantID, subscription.Email, pq.Array(subscription.Events),
+		subscription.Enabled, subscription.UnsubscribeToken, subscription.CreatedAt)
+	if err != nil {
+		return constraintError(err, "create email subscription")
+	}
+	return nil
+}
+
+// GetEmailSubscriptions returns the email subscriptions of a tenant
+func (s *PostgresStore) GetEmailSubscriptions(ctx context.Context, tenantID string) ([]types.EmailSubscription, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, email, events, enabled, unsubscribe_token, created_at
+		FROM email_subscriptions
+		WHERE tenant_id = $1
+		ORDER BY created_at
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query email subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []types.EmailSubscription{}
+	for rows.Next() {
+		subscription, err := scanEmailSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// DeleteEmailSubscription removes an email subscription of a tenant
+func (s *PostgresStore) DeleteEmailSubscription(ctx context.Context, tenantID, id string) error {
+	return s.execScoped(ctx, "delete email subscription",
+		`DELETE FROM email_subscriptions WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+}
+
+// Unsubscribe disables the email subscription of an unsubscribe token and
+// returns it
+func (s *PostgresStore) Unsubscribe(ctx context.Context, token string) (*types.EmailSubscription, error) {
+	subscription, err := scanEmailSubscription(s.db.QueryRowContext(ctx, `
+		UPDATE email_subscriptions SET enabled = FALSE
+		WHERE unsubscribe_token = $1
+		RETURNING id, tenant_id, email, events, enabled, unsubscribe_token, created_at
+	`, token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("unsubscribe token: %w", ErrNotFound)
+	}
+	return subscription, err
+}
+
+// scanEmailSubscription scans an email subscription row
+func scanEmailSubscription(row interface{ Scan(...any) error }) (*types.EmailSubscription, error) {
+	var subscription types.EmailSubscription
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.TenantID,
+		&subscription.Email,
+		pq.Array(&subscription.Events),
+		&subscription.Enabled,
+		&subscription.UnsubscribeToken,
+		&subscription.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan email subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+// CreateWebhook stores a new webhook of a tenant, assigning its ID
+func (s *PostgresStore) CreateWebhook(ctx context.Context, webhook *types.Webhook) error {
+	webhook.ID = newID()
+	webhook.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhooks (id, tenant_id, url, secret, events, enabled, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, webhook.ID, webhook.TenantID, webhook.URL, webhook.Secret,
+		pq.Array(webhook.Events), webhook.Enabled, webhook.CreatedAt)
+	if err != nil {
+		return constraintError(err, "create webhook")
+	}
+	return nil
+}
+
+// GetWebhooks returns the webhooks of a tenant
+func (s *PostgresStore) GetWebhooks(ctx context.Context, tenantID string) ([]types.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, url, secret, events, enabled, created_at
+		FROM webhooks
+		WHERE tenant_id = $1
+		ORDER BY created_at
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []types.Webhook{}
+	for rows.Next() {
+		var webhook types.Webhook
+		err := rows.Scan(
+			&webhook.ID,
+			&webhook.TenantID,
+			&webhook.URL,
+			&webhook.Secret,
+			pq.Array(&webhook.Events),
+			&webhook.Enabled,
+			&webhook.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// GetWebhook returns a webhook of a tenant
+func (s *PostgresStore) GetWebhook(ctx context.Context, tenantID, id string) (*types.Webhook, error) {
+	var webhook types.Webhook
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, tenant_id, url, secret, events, enabled, created_at
+		FROM webhooks
+		WHERE tenant_id = $1 AND id = $2
+	`, tenantID, id).Scan(
+		&webhook.ID,
+		&webhook.TenantID,
+		&webhook.URL,
+		&webhook.Secret,
+		pq.Array(&webhook.Events),
+		&webhook.Enabled,
+		&webhook.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// DeleteWebhook removes a webhook of a tenant and the alert rules and digests
+// using it
+func (s *PostgresStore) DeleteWebhook(ctx context.Context, tenantID, id string) error {
+	return s.execScoped(ctx, "delete webhook",
+		`DELETE FROM webhooks WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+}
+
+// CreateAlertRule stores a new alert rule of a tenant, assigning its ID. The
+// watchlist and webhook it references must belong to the same tenant.
+func (s *PostgresStore) CreateAlertRule(ctx context.Context, rule *types.AlertRule) error {
+	rule.ID = newID()
+	rule.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_rules (
+			id, tenant_id, name, chain_name, watchlist_id, webhook_id,
+			condition, enabled, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, rule.ID, rule.TenantID, rule.Name, rule.ChainName,
+		nullString(rule.WatchlistID), nullString(rule.WebhookID),
+		rule.Condition, rule.Enabled, rule.CreatedAt)
+	if err != nil {
+		return constraintError(err, "create alert rule")
+	}
+	return nil
+}
+
+// GetAlertRules returns the alert rules of a tenant
+func (s *PostgresStore) GetAlertRules(ctx context.Context, tenantID string) ([]types.AlertRule, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, name, chain_name, COALESCE(watchlist_id, ''),
+		       COALESCE(webhook_id, ''), condition, enabled, created_at
+		FROM alert_rules
+		WHERE tenant_id = $1
+		ORDER BY name
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []types.AlertRule{}
+	for rows.Next() {
+		var rule types.AlertRule
+		err := rows.Scan(
+			&rule.ID,
+			&rule.TenantID,
+			&rule.Name,
+			&rule.ChainName,
+			&rule.WatchlistID,
+			&rule.WebhookID,
+			&rule.Condition,
+			&rule.Enabled,
+			&rule.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// DeleteAlertRule removes an alert rule of a tenant
+func (s *PostgresStore) DeleteAlertRule(ctx context.Context, tenantID, id string) error {
+	return s.execScoped(ctx, "delete alert rule",
+		`DELETE FROM alert_rules WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+}
+
+// CreateDigest stores a new digest of a tenant, assigning its ID. The webhook
+// it references must belong to the same tenant.
+func (s *PostgresStore) CreateDigest(ctx context.Context, digest *types.Digest) error {
+	digest.ID = newID()
+	digest.CreatedAt = time.Now().UTC()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO digests (id, tenant_id, name, schedule, webhook_id, email, enabled, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, digest.ID, digest.TenantID, digest.Name, digest.Schedule,
+		nullString(digest.WebhookID), nullString(digest.Email), digest.Enabled, digest.CreatedAt)
+	if err != nil {
+		return constraintError(err, "create digest")
+	}
+	return nil
+}
+
+// GetDigests returns the digests of a tenant
+func (s *PostgresStore) GetDigests(ctx context.Context, tenantID string) ([]types.Digest, error) {
+	return s.queryDigests(ctx, `WHERE tenant_id = $1 ORDER BY name`, tenantID)
+}
+
+// GetEnabledDigests returns the enabled digests of every tenant
+func (s *PostgresStore) GetEnabledDigests(ctx context.Context) ([]types.Digest, error) {
+	return s.queryDigests(ctx, `WHERE enabled ORDER BY tenant_id, name`)
+}
+
+// queryDigests returns the digests selected by a WHERE clause
+func (s *PostgresStore) queryDigests(ctx context.Context, where string, args ...any) ([]types.Digest, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, name, schedule, COALESCE(webhook_id, ''),
+		       COALESCE(email, ''), enabled, last_sent_at, created_at
+		FROM digests
+		`+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query digests: %w", err)
+	}
+	defer rows.Close()
+
+	digests := []types.Digest{}
+	for rows.Next() {
+		var digest types.Digest
+		var lastSent sql.NullTime
+		err := rows.Scan(
+			&digest.ID,
+			&digest.TenantID,
+			&digest.Name,
+			&digest.Schedule,
+			&digest.WebhookID,
+			&digest.Email,
+			&digest.Enabled,
+			&lastSent,
+			&digest.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan digest: %w", err)
+		}
+		if lastSent.Valid {
+			digest.LastSentAt = &lastSent.Time
+		}
+		digests = append(digests, digest)
+	}
+
+	return digests, rows.Err()
+}
+
+// DeleteDigest removes a digest of a tenant
+func (s *PostgresStore) DeleteDigest(ctx context.Context, tenantID, id string) error {
+	return s.execScoped(ctx, "delete digest",
+		`DELETE FROM digests WHERE tenant_id = $1 AND id = $2`, tenantID, id)
+}
+
+// ClaimDigest sets the time a digest was last sent to sentAt if it is still
+// previous, and reports whether it was. Servers claim a digest before sending
+// it, so that only one sends it, and put the previous time back when sending
+// fails.
+func (s *PostgresStore) ClaimDigest(ctx context.Context, tenantID, id string, previous, sentAt *time.Time) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE digests SET last_sent_at = $4::timestamptz
+		WHERE tenant_id = $1 AND id = $2 AND last_sent_at IS NOT DISTINCT FROM $3::timestamptz
+	`, tenantID, id, previous, sentAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest: %w", err)
+	}
+	return n == 1, nil
+}
+
+// ClaimUnbondingReminder records that a tenant is reminded of an unbonding
+// entry, and reports whether it was not already. Servers claim a reminder
+// before sending it, so that only one sends it, and release it when sending
+// fails.
+func (s *PostgresStore) ClaimUnbondingReminder(ctx context.Context, tenantID string, completion types.UnbondingCompletion) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO unbonding_reminders (tenant_id, chain_name, delegator_address, validator_address, creation_height, completion_time)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING
+	`, tenantID, completion.ChainName, completion.DelegatorAddress, completion.ValidatorAddress,
+		completion.CreationHeight, completion.CompletionTime)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim unbonding reminder: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim unbonding reminder: %w", err)
+	}
+	return n == 1, nil
+}
+
+// ReleaseUnbondingReminder removes the claim on a reminder, so it is sent again
+func (s *PostgresStore) ReleaseUnbondingReminder(ctx context.Context, tenantID string, completion types.UnbondingCompletion) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM unbonding_reminders
+		WHERE tenant_id = $1 AND chain_name = $2 AND delegator_address = $3 AND validator_address = $4
+		  AND creation_height = $5 AND completion_time = $6
+	`, tenantID, completion.ChainName, completion.DelegatorAddress, completion.ValidatorAddress,
+		completion.CreationHeight, completion.CompletionTime)
+	if err != nil {
+		return fmt.Errorf("failed to release unbonding reminder: %w", err)
+	}
+	return nil
+}
+
+// PruneUnbondingReminders removes the reminders of entries that completed
+// before the given time, returning how many were removed
+func (s *PostgresStore) PruneUnbondingReminders(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM unbonding_reminders WHERE completion_time < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune unbonding reminders: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// nullString stores an empty string as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+func TestMemoryEmailSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	tenant := types.Tenant{Name: "a"}
+	if err := store.CreateTenant(ctx, &tenant); err != nil {
+		t.Fatal(err)
+	}
+
+	subscription := &types.EmailSubscription{TenantID: tenant.ID, Email: "ops@example.com", Events: []string{"alert"}, Enabled: true}
+	if err := store.CreateEmailSubscription(ctx, subscription); err != nil {
+		t.Fatal(err)
+	}
+	if len(subscription.UnsubscribeToken) != 64 {
+		t.Errorf("unsubscribe token = %q", subscription.UnsubscribeToken)
+	}
+	duplicate := &types.EmailSubscription{TenantID: tenant.ID, Email: "ops@example.com"}
+	if err := store.CreateEmailSubscription(ctx, duplicate); !errors.Is(err, ErrExists) {
+		t.Errorf("duplicate email: err = %v, want ErrExists", err)
+	}
+
+	if _, err := store.Unsubscribe(ctx, "unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown token: err = %v, want ErrNotFound", err)
+	}
+	unsubscribed, err := store.Unsubscribe(ctx, subscription.UnsubscribeToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unsubscribed.ID != subscription.ID || unsubscribed.Enabled {
+		t.Errorf("unsubscribed %+v", unsubscribed)
+	}
+	subscriptions, err := store.GetEmailSubscriptions(ctx, tenant.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subscriptions) != 1 || subscriptions[0].Enabled {
+		t.Errorf("subscriptions after unsubscribing = %+v", subscriptions)
+	}
+
+	if err := store.DeleteEmailSubscription(ctx, "other", subscription.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleting subscription of another tenant: err = %v, want ErrNotFound", err)
+	}
+	if err := store.DeleteTenant(ctx, tenant.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Unsubscribe(ctx, subscription.UnsubscribeToken); !errors.Is(err, ErrNotFound) {
+		t.Errorf("token of deleted tenant: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/lib/pq"
+)
+
+// GetTokenHoldings returns the CW20 balances and CW721 tokens of an address
+// with the metadata of their contracts, leaving out emptied holdings
+func (s *PostgresStore) GetTokenHoldings(ctx context.Context, chainName, address string) ([]types.TokenHolding, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT h.chain_name, h.address, h.contract, c.standard, c.name, c.symbol, c.decimals,
+		       h.amount, h.token_ids, h.height, h.updated_at
+		FROM token_holdings h
+		JOIN token_contracts c ON c.chain_name = h.chain_name AND c.contract = h.contract
+		WHERE h.chain_name = $1 AND h.address = $2 AND h.amount > 0
+		ORDER BY c.standard, c.symbol, h.contract
+	`, chainName, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token holdings: %w", err)
+	}
+	defer rows.Close()
+
+	var holdings []types.TokenHolding
+	for rows.Next() {
+		var holding types.TokenHolding
+		err := rows.Scan(
+			&holding.ChainName,
+			&holding.Address,
+			&holding.Contract,
+			&holding.Standard,
+			&holding.Name,
+			&holding.Symbol,
+			&holding.Decimals,
+			&holding.Amount,
+			pq.Array(&holding.TokenIDs),
+			&holding.Height,
+			&holding.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan token holding: %w", err)
+		}
+		holdings = append(holdings, holding)
+	}
+
+	return holdings, rows.Err()
+}
+
+// UpsertTokenContracts inserts or updates the metadata of token contracts
+func (tx *PostgresTx) UpsertTokenContracts(ctx context.Context, contracts []types.TokenContract) error {
+	for _, contract := range contracts {
+		_, err := tx.tx.ExecContext(ctx, `
+			INSERT INTO token_contracts (chain_name, contract, standard, name, symbol, decimals, height, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (chain_name, contract)
+			DO UPDATE SET
+				standard = EXCLUDED.standard,
+				name = EXCLUDED.name,
+				symbol = EXCLUDED.symbol,
+				decimals = EXCLUDED.decimals,
+				height = EXCLUDED.height,
+				updated_at = EXCLUDED.updated_at
+			WHERE EXCLUDED.height >= token_contracts.height
+		`,
+			contract.ChainName,
+			contract.Contract,
+			contract.Standard,
+			contract.Name,
+			contract.Symbol,
+			contract.Decimals,
+			contract.Height,
+			contract.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert token contract %s: %w", contract.Contract, err)
+		}
+	}
+	return nil
+}
+
+// UpsertTokenHoldings inserts or updates token holdings. The contract metadata
+// fields of the holdings are ignored; they come from token_contracts.
+func (tx *PostgresTx) UpsertTokenHoldings(ctx context.Context, holdings []types.TokenHolding) error {
+	for _, holding := range holdings {
+		tokenIDs := holding.TokenIDs
+		if tokenIDs == nil {
+			tokenIDs = []string{}
+		}
+
+		_, err := tx.tx.ExecContext(ctx, `
+			INSERT INTO token_holdings (chain_name, contract, address, amount, token_ids, height, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (chain_name, contract, address)
+			DO UPDATE SET
+				amount = EXCLUDED.amount,
+				token_ids = EXCLUDED.token_ids,
+				height = EXCLUDED.height,
+				updated_at = EXCLUDED.updated_at
+			WHERE EXCLUDED.height >= token_holdings.height
+		`,
+			holding.ChainName,
+			holding.Contract,
+			holding.Address,
+			holding.Amount,
+			pq.Array(tokenIDs),
+			holding.Height,
+			holding.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert token holding: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetWatchedAddresses returns the addresses on any tenant's watchlists for a
+// chain
+func (s *PostgresStore) GetWatchedAddresses(ctx context.Context, chainName string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT unnest(addresses) AS address
+		FROM watchlists
+		WHERE chain_name = $1
+		ORDER BY address
+	`, chainName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watched addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses []string
+	for rows.Next() {
+		var address string
+		if err := rows.Scan(&address); err != nil {
+			return nil, fmt.Errorf("failed to scan watched address: %w", err)
+		}
+		addresses = append(addresses, address)
+	}
+
+	return addresses, rows.Err()
+}
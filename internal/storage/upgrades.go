@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// UpgradeStore reads the software upgrades scheduled by the chains' upgrade
+// modules
+type UpgradeStore interface {
+	GetUpgradePlan(ctx context.Context, chainName string) (*types.UpgradePlan, error)
+}
+
+var (
+	_ UpgradeStore = (*PostgresStore)(nil)
+	_ UpgradeStore = (*CockroachStore)(nil)
+	_ UpgradeStore = (*MemoryStore)(nil)
+)
+
+const (
+	// upgradeBlockSample is the number of latest blocks the block time of
+	// upgrade estimates is averaged over
+	upgradeBlockSample = 100
+
+	// upgradeProposalLimit bounds the proposals in their voting period read
+	// per chain for the upgrade calendar
+	upgradeProposalLimit = 100
+
+	// votingPeriodStatus is the gov status of proposals being voted on
+	votingPeriodStatus = "PROPOSAL_STATUS_VOTING_PERIOD"
+)
+
+// ReplaceUpgradePlan replaces the scheduled upgrade of a chain, removing it
+// when plan is nil
+func (tx *PostgresTx) ReplaceUpgradePlan(ctx context.Context, chainName string, plan *types.UpgradePlan) error {
+	if plan == nil {
+		if _, err := tx.tx.ExecContext(ctx, `DELETE FROM upgrade_plans WHERE chain_name = $1`, chainName); err != nil {
+			return fmt.Errorf("failed to delete upgrade plan: %w", err)
+		}
+		return nil
+	}
+
+	_, err := tx.tx.ExecContext(ctx, `
+		INSERT INTO upgrade_plans (chain_name, name, upgrade_height, info, height, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (chain_name)
+		DO UPDATE SET
+			name = EXCLUDED.name,
+			upgrade_height = EXCLUDED.upgrade_height,
+			info = EXCLUDED.info,
+			height = EXCLUDED.height,
+			updated_at = EXCLUDED.updated_at
+	`, chainName, plan.Name, plan.UpgradeHeight, plan.Info, plan.Height, plan.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert upgrade plan: %w", err)
+	}
+	return nil
+}
+
+// GetUpgradePlan returns the scheduled upgrade of a chain, or nil when none is
+func (s *PostgresStore) GetUpgradePlan(ctx context.Context, chainName string) (*types.UpgradePlan, error) {
+	var plan types.UpgradePlan
+	err := s.db.QueryRowContext(ctx, `
+		SELECT chain_name, name, upgrade_height, info, height, updated_at
+		FROM upgrade_plans
+		WHERE chain_name = $1
+	`, chainName).Scan(&plan.ChainName, &plan.Name, &plan.UpgradeHeight, &plan.Info, &plan.Height, &plan.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upgrade plan: %w", err)
+	}
+	return &plan, nil
+}
+
+// GetUpgradeCalendar returns the upcoming software upgrades of the given
+// chains, soonest first: the upgrades their upgrade modules scheduled and
+// those proposed by proposals still in their voting period. Upgrades without
+// an estimated time are listed last. Chains that fail or exceed the timeout
+// are listed in the errors.
+func (m *Manager) GetUpgradeCalendar(ctx context.Context, chains []string, timeout time.Duration) ([]types.ChainUpgrade, []types.ChainError) {
+	results, _, chainErrors := perChain(ctx, chains, timeout, func(ctx context.Context, i int) ([]types.ChainUpgrade, error) {
+		return m.chainUpgrades(ctx, chains[i])
+	})
+
+	upgrades := []types.ChainUpgrade{}
+	for _, result := range results {
+		upgrades = append(upgrades, result...)
+	}
+	sort.SliceStable(upgrades, func(i, j int) bool {
+		a, b := upgrades[i], upgrades[j]
+		if (a.EstimatedTime == nil) != (b.EstimatedTime == nil) {
+			return a.EstimatedTime != nil
+		}
+		if a.EstimatedTime != nil && !a.EstimatedTime.Equal(*b.EstimatedTime) {
+			return a.EstimatedTime.Before(*b.EstimatedTime)
+		}
+		if a.ChainName != b.ChainName {
+			return a.ChainName < b.ChainName
+		}
+		return a.Height < b.Height
+	})
+	return upgrades, chainErrors
+}
+
+// chainUpgrades returns the upcoming upgrades of a chain with their estimated
+// times
+func (m *Manager) chainUpgrades(ctx context.Context, chainName string) ([]types.ChainUpgrade, error) {
+	info, err := m.state.GetChain(ctx, chainName)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil // not ingested yet
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var upgrades []types.ChainUpgrade
+	plan, err := m.Upgrades().GetUpgradePlan(ctx, chainName)
+	if err != nil {
+		return nil, err
+	}
+	if plan != nil {
+		upgrades = append(upgrades, types.ChainUpgrade{
+			ChainName: chainName,
+			Name:      plan.Name,
+			Height:    plan.UpgradeHeight,
+			Info:      plan.Info,
+			Status:    types.UpgradeScheduled,
+		})
+	}
+
+	proposals, _, err := m.Proposals().GetProposals(ctx, chainName, votingPeriodStatus, upgradeProposalLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, proposal := range proposals {
+		upgrade := proposal.Content.SoftwareUpgrade
+		if upgrade == nil || (plan != nil && upgrade.Name == plan.Name) {
+			continue
+		}
+		votingEnd := proposal.VotingEndTime
+		upgrades = append(upgrades, types.ChainUpgrade{
+			ChainName:     chainName,
+			Name:          upgrade.Name,
+			Height:        upgrade.Height,
+			Info:          upgrade.Info,
+			Status:        types.UpgradeProposed,
+			ProposalID:    proposal.ProposalID,
+			VotingEndTime: &votingEnd,
+		})
+	}
+	if len(upgrades) == 0 {
+		return nil, nil
+	}
+
+	blockTime, err := m.averageBlockTime(ctx, chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming := upgrades[:0]
+	for _, upgrade := range upgrades {
+		if upgrade.Height <= info.LatestHeight {
+			continue // applied, or a proposal whose height has passed
+		}
+		upgrade.LatestHeight = info.LatestHeight
+		upgrade.BlocksRemaining = upgrade.Height - info.LatestHeight
+		if blockTime > 0 && !info.LatestTime.IsZero() {
+			upgrade.BlockTimeSeconds = blockTime.Seconds()
+			estimated := info.LatestTime.Add(time.Duration(upgrade.BlocksRemaining) * blockTime).UTC()
+			upgrade.EstimatedTime = &estimated
+		}
+		upcoming = append(upcoming, upgrade)
+	}
+	return upcoming, nil
+}
+
+// averageBlockTime returns the average block time over the latest stored
+// blocks of a chain, or 0 when fewer than two are stored
+func (m *Manager) averageBlockTime(ctx context.Context, chainName string) (time.Duration, error) {
+	blocks, err := m.state.GetBlocks(ctx, chainName, 0, upgradeBlockSample)
+	if err != nil {
+		return 0, err
+	}
+	if len(blocks) < 2 {
+		return 0, nil
+	}
+	newest, oldest := blocks[0], blocks[len(blocks)-1]
+	if newest.Height <= oldest.Height {
+		return 0, nil
+	}
+	return newest.Time.Sub(oldest.Time) / time.Duration(newest.Height-oldest.Height), nil
+}
+
+// Upgrades returns the store of scheduled software upgrades
+func (m *Manager) Upgrades() UpgradeStore {
+	return m.state.(UpgradeStore)
+}
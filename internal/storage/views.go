@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// APIViewSchema is the PostgreSQL schema holding the views for external
+// GraphQL engines and BI tools
+const APIViewSchema = "api"
+
+// API view kinds
+const (
+	APIViewLatest  = "latest"  // one row per entity, its latest state
+	APIViewHistory = "history" // one row per recorded change
+)
+
+// APIView is a view of the api schema. Views are a stable interface: later
+// migrations may add columns to them but do not rename or drop any.
+type APIView struct {
+	Name        string
+	Kind        string
+	Description string
+	PrimaryKey  []string
+	Migration   string // that creates it
+}
+
+// APIViews are the views of the api schema, as created by the migrations.
+// config/postgraphile.tags.json5 repeats their primary keys.
+var APIViews = []APIView{
+	{Name: "chains", Kind: APIViewLatest, Description: "Ingested chains and their latest block", PrimaryKey: []string{"name"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "accounts", Kind: APIViewLatest, Description: "Accounts seen on each chain", PrimaryKey: []string{"chain_name", "address"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "balances", Kind: APIViewLatest, Description: "Latest balance of each account and denom", PrimaryKey: []string{"chain_name", "address", "denom"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "validators", Kind: APIViewLatest, Description: "Latest state of each validator", PrimaryKey: []string{"chain_name", "operator_address"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "delegations", Kind: APIViewLatest, Description: "Latest delegation of each delegator to each validator", PrimaryKey: []string{"chain_name", "delegator_address", "validator_address"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "unbonding_delegations", Kind: APIViewLatest, Description: "Unbonding entries not yet completed", PrimaryKey: []string{"chain_name", "delegator_address", "validator_address", "creation_height"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "redelegations", Kind: APIViewLatest, Description: "Redelegation entries not yet completed", PrimaryKey: []string{"chain_name", "delegator_address", "validator_src_address", "validator_dst_address", "creation_height"}, Migration: "postgres/027_staking_entries.sql"},
+	{Name: "proposals", Kind: APIViewLatest, Description: "Governance proposals with their decoded content", PrimaryKey: []string{"chain_name", "proposal_id"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "supply", Kind: APIViewLatest, Description: "Total supply of each denom", PrimaryKey: []string{"chain_name", "denom"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "chain_params", Kind: APIViewLatest, Description: "Latest parameters of each module, as a JSON object by name", PrimaryKey: []string{"chain_name", "module"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "balance_history", Kind: APIViewHistory, Description: "Every recorded balance, within the history retention", PrimaryKey: []string{"id"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "delegation_history", Kind: APIViewHistory, Description: "Every recorded delegation, within the history retention", PrimaryKey: []string{"id"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "commission_changes", Kind: APIViewHistory, Description: "Validator commission rate changes", PrimaryKey: []string{"chain_name", "operator_address", "height"}, Migration: "postgres/026_api_views.sql"},
+	{Name: "param_changes", Kind: APIViewHistory, Description: "Module parameter changes", PrimaryKey: []string{"id"}, Migration: "postgres/026_api_views.sql"},
+}
+
+// ViewStore describes the columns of the api views
+type ViewStore interface {
+	ViewColumns(ctx context.Context, views []string) (map[string][]types.SQLViewColumn, error)
+}
+
+var (
+	_ ViewStore = (*PostgresStore)(nil)
+	_ ViewStore = (*CockroachStore)(nil)
+)
+
+// ViewColumns returns the columns of the api views by view name, in column
+// order. Views not yet created are left out.
+func (s *PostgresStore) ViewColumns(ctx context.Context, views []string) (map[string][]types.SQLViewColumn, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT table_name, column_name, data_type, is_nullable = 'YES'
+		FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = ANY($2)
+		ORDER BY table_name, ordinal_position
+	`, APIViewSchema, pq.Array(views))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list view columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string][]types.SQLViewColumn)
+	for rows.Next() {
+		var view string
+		var column types.SQLViewColumn
+		if err := rows.Scan(&view, &column.Name, &column.Type, &column.Nullable); err != nil {
+			return nil, fmt.Errorf("failed to scan view column: %w", err)
+		}
+		columns[view] = append(columns[view], column)
+	}
+	return columns, rows.Err()
+}
+
+// GetSQLViews describes the api views with their columns as the database
+// reports them. Views whose migration is not applied yet are left out.
+func (m *Manager) GetSQLViews(ctx context.Context) ([]types.SQLView, error) {
+	store, ok := m.state.(ViewStore)
+	if !ok {
+		return nil, fmt.Errorf("SQL views are not supported by the %s store: %w", m.driver, ErrUnavailable)
+	}
+
+	names := make([]string, len(APIViews))
+	for i, view := range APIViews {
+		names[i] = view.Name
+	}
+	columns, err := store.ViewColumns(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]types.SQLView, 0, len(APIViews))
+	for _, view := range APIViews {
+		if len(columns[view.Name]) == 0 {
+			continue
+		}
+		views = append(views, types.SQLView{
+			Schema:      APIViewSchema,
+			Name:        view.Name,
+			Kind:        view.Kind,
+			Description: view.Description,
+			PrimaryKey:  view.PrimaryKey,
+			Migration:   view.Migration,
+			Columns:     columns[view.Name],
+		})
+	}
+	return views, nil
+}
@@ -0,0 +1,345 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/cosmos/state-mesh/internal/diskqueue"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// walEntry is one committed transaction in the write-ahead log
+type walEntry struct {
+	Ops []walOp `json:"ops"`
+}
+
+// walOp is a single StateTx call. Op names the method; only the fields that
+// method takes are set.
+type walOp struct {
+	Op string `json:"op"`
+
+	Chain         *types.ChainInfo            `json:"chain,omitempty"`
+	ChainStatus   *types.ChainStatus          `json:"chain_status,omitempty"`
+	Account       *types.Account              `json:"account,omitempty"`
+	Balances      []types.Balance             `json:"balances,omitempty"`
+	Delegation    *types.Delegation           `json:"delegation,omitempty"`
+	Unbondings    []types.UnbondingDelegation `json:"unbondings,omitempty"`
+	Redelegations []types.Redelegation        `json:"redelegations,omitempty"`
+	Contracts     []types.TokenContract       `json:"contracts,omitempty"`
+	Holdings      []types.TokenHolding        `json:"holdings,omitempty"`
+	Validator     *types.Validator            `json:"validator,omitempty"`
+	Commission    *types.CommissionChange     `json:"commission,omitempty"`
+	Consumers     []types.ConsumerChain       `json:"consumers,omitempty"`
+	Supply        []types.Supply              `json:"supply,omitempty"`
+	Pool          []types.PoolBalance         `json:"pool,omitempty"`
+	Modules       []types.ModuleAccount       `json:"modules,omitempty"`
+	Vesting       []types.VestingLocked       `json:"vesting,omitempty"`
+	MintParams    *types.MintParams           `json:"mint_params,omitempty"`
+	Proposals     []types.Proposal            `json:"proposals,omitempty"`
+	Upgrade       *types.UpgradePlan          `json:"upgrade,omitempty"`
+	Params        *types.ChainParams          `json:"params,omitempty"`
+	Blocks        []types.Block               `json:"blocks,omitempty"`
+	Outbox        []types.OutboxMessage       `json:"outbox,omitempty"`
+
+	ChainName        string `json:"chain_name,omitempty"`
+	DelegatorAddress string `json:"delegator_address,omitempty"`
+	ValidatorAddress string `json:"validator_address,omitempty"`
+	ConsensusAddress string `json:"consensus_address,omitempty"`
+	OperatorAddress  string `json:"operator_address,omitempty"`
+	Height           int64  `json:"height,omitempty"`
+}
+
+// WAL operation names
+const (
+	walUpsertChain                 = "upsert_chain"
+	walUpsertChainStatus           = "upsert_chain_status"
+	walUpsertAccount               = "upsert_account"
+	walUpsertBalances              = "upsert_balances"
+	walUpsertDelegation            = "upsert_delegation"
+	walDeleteDelegation            = "delete_delegation"
+	walReplaceUnbondingDelegations = "replace_unbonding_delegations"
+	walReplaceRedelegations        = "replace_redelegations"
+	walUpsertTokenContracts        = "upsert_token_contracts"
+	walUpsertTokenHoldings         = "upsert_token_holdings"
+	walUpsertValidator             = "upsert_validator"
+	walUpsertConsensusAddress      = "upsert_consensus_address"
+	walInsertCommissionChange      = "insert_commission_change"
+	walReplaceConsumerChains       = "replace_consumer_chains"
+	walUpsertSupply                = "upsert_supply"
+	walReplaceCommunityPool        = "replace_community_pool"
+	walReplaceModuleAccounts       = "replace_module_accounts"
+	walReplaceVestingLocked        = "replace_vesting_locked"
+	walUpsertMintParams            = "upsert_mint_params"
+	walUpsertProposals             = "upsert_proposals"
+	walReplaceUpgradePlan          = "replace_upgrade_plan"
+	walUpsertChainParams           = "upsert_chain_params"
+	walInsertBlocks                = "insert_blocks"
+	walEnqueueOutbox               = "enqueue_outbox"
+)
+
+// walTx records a transaction's writes and appends them to the write-ahead
+// log on commit, for replay once the state store is reachable again
+type walTx struct {
+	log  *diskqueue.Queue
+	ops  []walOp
+	done bool
+}
+
+// Commit appends the recorded writes to the log as one entry
+func (tx *walTx) Commit() error {
+	if tx.done {
+		return fmt.Errorf("transaction already finished")
+	}
+	tx.done = true
+
+	if len(tx.ops) == 0 {
+		return nil
+	}
+	if err := tx.log.Append(walEntry{Ops: tx.ops}); err != nil {
+		return fmt.Errorf("failed to write to write-ahead log: %w", err)
+	}
+	return nil
+}
+
+// Rollback discards the recorded writes
+func (tx *walTx) Rollback() error {
+	tx.done = true
+	tx.ops = nil
+	return nil
+}
+
+func (tx *walTx) record(op walOp) error {
+	if tx.done {
+		return fmt.Errorf("transaction already finished")
+	}
+	tx.ops = append(tx.ops, op)
+	return nil
+}
+
+// UpsertChain records a chain upsert
+func (tx *walTx) UpsertChain(ctx context.Context, chain *types.ChainInfo) error {
+	c := *chain
+	return tx.record(walOp{Op: walUpsertChain, Chain: &c})
+}
+
+// UpsertChainStatus records a chain status upsert
+func (tx *walTx) UpsertChainStatus(ctx context.Context, status *types.ChainStatus) error {
+	s := *status
+	return tx.record(walOp{Op: walUpsertChainStatus, ChainStatus: &s})
+}
+
+// UpsertAccount records an account upsert
+func (tx *walTx) UpsertAccount(ctx context.Context, account *types.Account) error {
+	a := *account
+	return tx.record(walOp{Op: walUpsertAccount, Account: &a})
+}
+
+// UpsertBalance records a balance upsert
+func (tx *walTx) UpsertBalance(ctx context.Context, balance *types.Balance) error {
+	return tx.UpsertBalances(ctx, []types.Balance{*balance})
+}
+
+// UpsertBalances records a batch of balance upserts
+func (tx *walTx) UpsertBalances(ctx context.Context, balances []types.Balance) error {
+	return tx.record(walOp{Op: walUpsertBalances, Balances: append([]types.Balance(nil), balances...)})
+}
+
+// UpsertDelegation records a delegation upsert
+func (tx *walTx) UpsertDelegation(ctx context.Context, delegation *types.Delegation) error {
+	d := *delegation
+	return tx.record(walOp{Op: walUpsertDelegation, Delegation: &d})
+}
+
+// DeleteDelegation records a delegation removal
+func (tx *walTx) DeleteDelegation(ctx context.Context, chainName, delegatorAddress, validatorAddress string) error {
+	return tx.record(walOp{
+		Op:               walDeleteDelegation,
+		ChainName:        chainName,
+		DelegatorAddress: delegatorAddress,
+		ValidatorAddress: validatorAddress,
+	})
+}
+
+// ReplaceUnbondingDelegations records an unbonding queue snapshot
+func (tx *walTx) ReplaceUnbondingDelegations(ctx context.Context, chainName string, unbondings []types.UnbondingDelegation) error {
+	return tx.record(walOp{
+		Op:         walReplaceUnbondingDelegations,
+		ChainName:  chainName,
+		Unbondings: append([]types.UnbondingDelegation(nil), unbondings...),
+	})
+}
+
+// ReplaceRedelegations records a redelegation snapshot
+func (tx *walTx) ReplaceRedelegations(ctx context.Context, chainName string, redelegations []types.Redelegation) error {
+	return tx.record(walOp{
+		Op:            walReplaceRedelegations,
+		ChainName:     chainName,
+		Redelegations: append([]types.Redelegation(nil), redelegations...),
+	})
+}
+
+// UpsertTokenContracts records a token contract upsert
+func (tx *walTx) UpsertTokenContracts(ctx context.Context, contracts []types.TokenContract) error {
+	return tx.record(walOp{Op: walUpsertTokenContracts, Contracts: append([]types.TokenContract(nil), contracts...)})
+}
+
+// UpsertTokenHoldings records a token holding upsert
+func (tx *walTx) UpsertTokenHoldings(ctx context.Context, holdings []types.TokenHolding) error {
+	return tx.record(walOp{Op: walUpsertTokenHoldings, Holdings: append([]types.TokenHolding(nil), holdings...)})
+}
+
+// UpsertValidator records a validator upsert
+func (tx *walTx) UpsertValidator(ctx context.Context, validator *types.Validator) error {
+	v := *validator
+	return tx.record(walOp{Op: walUpsertValidator, Validator: &v})
+}
+
+// UpsertConsensusAddress records a consensus address mapping
+func (tx *walTx) UpsertConsensusAddress(ctx context.Context, chainName, consensusAddress, operatorAddress string, height int64) error {
+	return tx.record(walOp{
+		Op:               walUpsertConsensusAddress,
+		ChainName:        chainName,
+		ConsensusAddress: consensusAddress,
+		OperatorAddress:  operatorAddress,
+		Height:           height,
+	})
+}
+
+// InsertCommissionChange records a commission change insert
+func (tx *walTx) InsertCommissionChange(ctx context.Context, change *types.CommissionChange) error {
+	c := *change
+	return tx.record(walOp{Op: walInsertCommissionChange, Commission: &c})
+}
+
+// ReplaceConsumerChains records a provider's consumer chains snapshot
+func (tx *walTx) ReplaceConsumerChains(ctx context.Context, providerChain string, consumers []types.ConsumerChain) error {
+	return tx.record(walOp{
+		Op:        walReplaceConsumerChains,
+		ChainName: providerChain,
+		Consumers: append([]types.ConsumerChain(nil), consumers...),
+	})
+}
+
+// UpsertSupply records a supply upsert
+func (tx *walTx) UpsertSupply(ctx context.Context, supply []types.Supply) error {
+	return tx.record(walOp{Op: walUpsertSupply, Supply: append([]types.Supply(nil), supply...)})
+}
+
+// ReplaceCommunityPool records a community pool snapshot
+func (tx *walTx) ReplaceCommunityPool(ctx context.Context, chainName string, pool []types.PoolBalance) error {
+	return tx.record(walOp{Op: walReplaceCommunityPool, ChainName: chainName, Pool: append([]types.PoolBalance(nil), pool...)})
+}
+
+// ReplaceModuleAccounts records a module accounts snapshot
+func (tx *walTx) ReplaceModuleAccounts(ctx context.Context, chainName string, accounts []types.ModuleAccount) error {
+	return tx.record(walOp{Op: walReplaceModuleAccounts, ChainName: chainName, Modules: append([]types.ModuleAccount(nil), accounts...)})
+}
+
+// ReplaceVestingLocked records a vesting locked snapshot
+func (tx *walTx) ReplaceVestingLocked(ctx context.Context, chainName string, locked []types.VestingLocked) error {
+	return tx.record(walOp{Op: walReplaceVestingLocked, ChainName: chainName, Vesting: append([]types.VestingLocked(nil), locked...)})
+}
+
+// UpsertMintParams records a mint params upsert
+func (tx *walTx) UpsertMintParams(ctx context.Context, params *types.MintParams) error {
+	p := *params
+	return tx.record(walOp{Op: walUpsertMintParams, MintParams: &p})
+}
+
+// UpsertProposals records a proposals upsert
+func (tx *walTx) UpsertProposals(ctx context.Context, proposals []types.Proposal) error {
+	return tx.record(walOp{Op: walUpsertProposals, Proposals: append([]types.Proposal(nil), proposals...)})
+}
+
+// ReplaceUpgradePlan records a chain's scheduled upgrade, or its removal
+func (tx *walTx) ReplaceUpgradePlan(ctx context.Context, chainName string, plan *types.UpgradePlan) error {
+	op := walOp{Op: walReplaceUpgradePlan, ChainName: chainName}
+	if plan != nil {
+		p := *plan
+		op.Upgrade = &p
+	}
+	return tx.record(op)
+}
+
+// UpsertChainParams records a module params upsert
+func (tx *walTx) UpsertChainParams(ctx context.Context, params *types.ChainParams) error {
+	p := *params
+	p.Params = maps.Clone(params.Params)
+	return tx.record(walOp{Op: walUpsertChainParams, Params: &p})
+}
+
+// InsertBlocks records a block header insert
+func (tx *walTx) InsertBlocks(ctx context.Context, blocks []types.Block) error {
+	return tx.record(walOp{Op: walInsertBlocks, Blocks: append([]types.Block(nil), blocks...)})
+}
+
+// EnqueueOutbox records outbox messages, published once the transaction is
+// replayed
+func (tx *walTx) EnqueueOutbox(ctx context.Context, messages []types.OutboxMessage) error {
+	return tx.record(walOp{Op: walEnqueueOutbox, Outbox: append([]types.OutboxMessage(nil), messages...)})
+}
+
+// apply replays a logged transaction's writes against a state store
+// transaction
+func (e walEntry) apply(ctx context.Context, tx StateTx) error {
+	for _, op := range e.Ops {
+		var err error
+		switch op.Op {
+		case walUpsertChain:
+			err = tx.UpsertChain(ctx, op.Chain)
+		case walUpsertChainStatus:
+			err = tx.UpsertChainStatus(ctx, op.ChainStatus)
+		case walUpsertAccount:
+			err = tx.UpsertAccount(ctx, op.Account)
+		case walUpsertBalances:
+			err = tx.UpsertBalances(ctx, op.Balances)
+		case walUpsertDelegation:
+			err = tx.UpsertDelegation(ctx, op.Delegation)
+		case walDeleteDelegation:
+			err = tx.DeleteDelegation(ctx, op.ChainName, op.DelegatorAddress, op.ValidatorAddress)
+		case walReplaceUnbondingDelegations:
+			err = tx.ReplaceUnbondingDelegations(ctx, op.ChainName, op.Unbondings)
+		case walReplaceRedelegations:
+			err = tx.ReplaceRedelegations(ctx, op.ChainName, op.Redelegations)
+		case walUpsertTokenContracts:
+			err = tx.UpsertTokenContracts(ctx, op.Contracts)
+		case walUpsertTokenHoldings:
+			err = tx.UpsertTokenHoldings(ctx, op.Holdings)
+		case walUpsertValidator:
+			err = tx.UpsertValidator(ctx, op.Validator)
+		case walUpsertConsensusAddress:
+			err = tx.UpsertConsensusAddress(ctx, op.ChainName, op.ConsensusAddress, op.OperatorAddress, op.Height)
+		case walInsertCommissionChange:
+			err = tx.InsertCommissionChange(ctx, op.Commission)
+		case walReplaceConsumerChains:
+			err = tx.ReplaceConsumerChains(ctx, op.ChainName, op.Consumers)
+		case walUpsertSupply:
+			err = tx.UpsertSupply(ctx, op.Supply)
+		case walReplaceCommunityPool:
+			err = tx.ReplaceCommunityPool(ctx, op.ChainName, op.Pool)
+		case walReplaceModuleAccounts:
+			err = tx.ReplaceModuleAccounts(ctx, op.ChainName, op.Modules)
+		case walReplaceVestingLocked:
+			err = tx.ReplaceVestingLocked(ctx, op.ChainName, op.Vesting)
+		case walUpsertMintParams:
+			err = tx.UpsertMintParams(ctx, op.MintParams)
+		case walUpsertProposals:
+			err = tx.UpsertProposals(ctx, op.Proposals)
+		case walReplaceUpgradePlan:
+			err = tx.ReplaceUpgradePlan(ctx, op.ChainName, op.Upgrade)
+		case walUpsertChainParams:
+			err = tx.UpsertChainParams(ctx, op.Params)
+		case walInsertBlocks:
+			err = tx.InsertBlocks(ctx, op.Blocks)
+		case walEnqueueOutbox:
+			err = tx.EnqueueOutbox(ctx, op.Outbox)
+		default:
+			err = fmt.Errorf("unknown write-ahead log operation %q", op.Op)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to replay %s: %w", op.Op, err)
+		}
+	}
+	return nil
+}
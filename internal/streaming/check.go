@@ -0,0 +1,100 @@
+package streaming
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/cosmos/state-mesh/internal/config"
+)
+
+// CheckKafka connects to the configured brokers and checks that the topic
+// exists, waiting up to timeout for the cluster's metadata
+func CheckKafka(cfg config.KafkaConfig, timeout time.Duration) error {
+	configMap := &kafka.ConfigMap{
+		"bootstrap.servers": strings.Join(cfg.Brokers, ","),
+		"client.id":         "state-mesh-check",
+	}
+	if err := setSecurity(configMap, cfg); err != nil {
+		return err
+	}
+
+	producer, err := kafka.NewProducer(configMap)
+	if err != nil {
+		return fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+	defer producer.Close()
+
+	metadata, err := producer.GetMetadata(&cfg.Topic, false, int(timeout.Milliseconds()))
+	if err != nil {
+		return fmt.Errorf("failed to get cluster metadata: %w", err)
+	}
+	topic, ok := metadata.Topics[cfg.Topic]
+	if !ok {
+		return fmt.Errorf("topic %s not found", cfg.Topic)
+	}
+	if topic.Error.Code() != kafka.ErrNoError {
+		return fmt.Errorf("topic %s: %w", cfg.Topic, topic.Error)
+	}
+	return nil
+}
+
+// GroupBacklog returns the number of events of the topic a consumer group has
+// not read yet: the sum over partitions of the high watermark less the
+// group's committed offset. Partitions the group never committed are not
+// counted, since it starts them from the newest event.
+func GroupBacklog(cfg config.KafkaConfig, group string, timeout time.Duration) (int64, error) {
+	configMap := &kafka.ConfigMap{
+		"bootstrap.servers":  strings.Join(cfg.Brokers, ","),
+		"client.id":          "state-mesh-backlog",
+		"group.id":           group,
+		"enable.auto.commit": false,
+	}
+	if err := setSecurity(configMap, cfg); err != nil {
+		return 0, err
+	}
+
+	consumer, err := kafka.NewConsumer(configMap)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+	defer consumer.Close()
+
+	timeoutMs := int(timeout.Milliseconds())
+	metadata, err := consumer.GetMetadata(&cfg.Topic, false, timeoutMs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cluster metadata: %w", err)
+	}
+	topic, ok := metadata.Topics[cfg.Topic]
+	if !ok {
+		return 0, fmt.Errorf("topic %s not found", cfg.Topic)
+	}
+
+	partitions := make([]kafka.TopicPartition, len(topic.Partitions))
+	for i, partition := range topic.Partitions {
+		partitions[i] = kafka.TopicPartition{Topic: &cfg.Topic, Partition: partition.ID}
+	}
+	committed, err := consumer.Committed(partitions, timeoutMs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get committed offsets of %s: %w", group, err)
+	}
+
+	var backlog int64
+	for _, partition := range committed {
+		if partition.Error != nil {
+			return 0, fmt.Errorf("partition %d: %w", partition.Partition, partition.Error)
+		}
+		if partition.Offset < 0 {
+			continue
+		}
+		_, high, err := consumer.QueryWatermarkOffsets(cfg.Topic, partition.Partition, timeoutMs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get watermarks of partition %d: %w", partition.Partition, err)
+		}
+		if high > int64(partition.Offset) {
+			backlog += high - int64(partition.Offset)
+		}
+	}
+	return backlog, nil
+}
@@ -0,0 +1,213 @@
+package streaming
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var duplicateEvents = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "statemesh",
+	Subsystem: "consumer",
+	Name:      "duplicate_events_total",
+	Help:      "Redelivered events dropped by their event ID",
+})
+
+// Event types published to the state change topic
+const (
+	EventStateChange = "state_change"
+	EventBalance     = "balance"
+	EventDelegation  = "delegation"
+	EventValidator   = "validator"
+	EventProposal    = "proposal"
+)
+
+// EventTypes lists every event type. Each is published as the message of
+// proto/statemesh/events/v1/events.proto its schema header names.
+var EventTypes = []string{EventStateChange, EventBalance, EventDelegation, EventValidator, EventProposal}
+
+// Event is a message read back from the state change topic
+type Event struct {
+	ID     string          `json:"id,omitempty"`
+	Type   string          `json:"type"`
+	Chain  string          `json:"chain"`
+	Module string          `json:"module,omitempty"`
+	Offset int64           `json:"offset"`
+	Data   json.RawMessage `json:"data"`
+
+	// Addresses are the accounts and validators the event concerns
+	Addresses []string `json:"-"`
+}
+
+// recentEventIDs is the number of event IDs a consumer remembers to drop
+// redelivered events
+const recentEventIDs = 10000
+
+// Consumer reads the events published by the ingesters. Consumers created
+// with NewConsumer use their own group so that each API server receives every
+// event, starting from the newest. Events published more than once, by
+// retries or the outbox relay, are passed on once.
+type Consumer struct {
+	consumer *kafka.Consumer
+	topic    string
+	seen     *recentSet
+	logger   *zap.Logger
+}
+
+// NewConsumer creates a new streaming consumer
+func NewConsumer(cfg config.StreamingConfig, logger *zap.Logger) (*Consumer, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate consumer group: %w", err)
+	}
+	return newConsumer(cfg, "state-mesh-api-"+hex.EncodeToString(suffix), false, logger)
+}
+
+// NewGroupConsumer creates a consumer sharing a group with the consumers of
+// the other API servers, so that each event is read by one of them. Events of
+// the same account share a key and are read by the same consumer. Offsets are
+// committed, so that the group resumes where it stopped after a restart.
+func NewGroupConsumer(cfg config.StreamingConfig, group string, logger *zap.Logger) (*Consumer, error) {
+	return newConsumer(cfg, group, true, logger)
+}
+
+// newConsumer creates a consumer in a group, starting from the newest event
+// when the group has no committed offset
+func newConsumer(cfg config.StreamingConfig, group string, commit bool, logger *zap.Logger) (*Consumer, error) {
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("streaming is disabled")
+	}
+
+	configMap := &kafka.ConfigMap{
+		"bootstrap.servers":  cfg.Kafka.Brokers[0],
+		"client.id":          "state-mesh-consumer",
+		"group.id":           group,
+		"auto.offset.reset":  "latest",
+		"enable.auto.commit": commit,
+	}
+	if err := setSecurity(configMap, cfg.Kafka); err != nil {
+		return nil, err
+	}
+
+	consumer, err := kafka.NewConsumer(configMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka consumer: %w", err)
+	}
+
+	if err := consumer.Subscribe(cfg.Kafka.Topic, nil); err != nil {
+		consumer.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", cfg.Kafka.Topic, err)
+	}
+
+	return &Consumer{
+		consumer: consumer,
+		topic:    cfg.Kafka.Topic,
+		seen:     newRecentSet(recentEventIDs),
+		logger:   logger.Named("consumer"),
+	}, nil
+}
+
+// Run passes every event read to handle until ctx is done
+func (c *Consumer) Run(ctx context.Context, handle func(*Event)) {
+	for ctx.Err() == nil {
+		msg, err := c.consumer.ReadMessage(500 * time.Millisecond)
+		if err != nil {
+			if kerr, ok := err.(kafka.Error); ok && kerr.IsTimeout() {
+				continue
+			}
+			c.logger.Warn("Failed to read event", zap.Error(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		event := decodeEvent(msg)
+		if event.ID != "" && !c.seen.add(event.ID) {
+			duplicateEvents.Inc()
+			continue
+		}
+		handle(event)
+	}
+}
+
+// Close closes the consumer
+func (c *Consumer) Close() error {
+	return c.consumer.Close()
+}
+
+// decodeEvent builds an event from the headers the manager publishes with
+func decodeEvent(msg *kafka.Message) *Event {
+	event := &Event{
+		Type:   EventStateChange,
+		Offset: int64(msg.TopicPartition.Offset),
+		Data:   json.RawMessage(msg.Value),
+	}
+
+	for _, header := range msg.Headers {
+		value := string(header.Value)
+		switch header.Key {
+		case "event_id":
+			event.ID = value
+		case "chain":
+			event.Chain = value
+		case "type":
+			event.Type = value
+		case "store":
+			event.Module = value
+		case "address", "delegator", "validator":
+			event.Addresses = append(event.Addresses, value)
+		}
+	}
+
+	switch event.Type {
+	case EventBalance:
+		event.Module = "bank"
+	case EventDelegation, EventValidator:
+		event.Module = "staking"
+	case EventProposal:
+		event.Module = "gov"
+	}
+
+	return event
+}
+
+// recentSet remembers the most recently added keys up to a fixed number
+type recentSet struct {
+	keys  map[string]struct{}
+	order []string
+	next  int
+}
+
+// newRecentSet creates a set holding up to size keys
+func newRecentSet(size int) *recentSet {
+	return &recentSet{
+		keys:  make(map[string]struct{}, size),
+		order: make([]string, size),
+	}
+}
+
+// add adds a key, evicting the oldest when full, and reports whether it was
+// not already present
+func (s *recentSet) add(key string) bool {
+	if _, ok := s.keys[key]; ok {
+		return false
+	}
+
+	if evicted := s.order[s.next]; evicted != "" {
+		delete(s.keys, evicted)
+	}
+	s.order[s.next] = key
+	s.next = (s.next + 1) % len(s.order)
+	s.keys[key] = struct{}{}
+	return true
+}
@@ -2,13 +2,19 @@ package streaming
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/cosmos/state-mesh/internal/config"
+	eventsv1 "github.com/cosmos/state-mesh/pkg/events/v1"
 	"github.com/cosmos/state-mesh/pkg/types"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 // Manager handles streaming operations
@@ -34,6 +40,9 @@ func NewManager(cfg config.StreamingConfig, logger *zap.Logger) (*Manager, error
 		"linger.ms":        10,
 		"compression.type": "snappy",
 	}
+	if err := setSecurity(configMap, cfg.Kafka); err != nil {
+		return nil, err
+	}
 
 	producer, err := kafka.NewProducer(configMap)
 	if err != nil {
@@ -47,104 +56,233 @@ func NewManager(cfg config.StreamingConfig, logger *zap.Logger) (*Manager, error
 	}, nil
 }
 
-// Close closes the streaming manager
+// setSecurity adds the security protocol and SASL credentials of the Kafka
+// configuration to a client configuration
+func setSecurity(configMap *kafka.ConfigMap, cfg config.KafkaConfig) error {
+	settings := map[string]string{
+		"security.protocol": cfg.SecurityProtocol,
+		"sasl.mechanisms":   cfg.SASL.Mechanism,
+		"sasl.username":     cfg.SASL.Username,
+		"sasl.password":     cfg.SASL.Password,
+	}
+	for key, value := range settings {
+		if value == "" {
+			continue
+		}
+		if err := configMap.SetKey(key, value); err != nil {
+			return fmt.Errorf("failed to configure Kafka %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the streaming manager. Messages still buffered by the
+// producer are lost; Shutdown delivers them first.
 func (m *Manager) Close() error {
 	if m.producer != nil {
 		m.producer.Close()
+		m.producer = nil
 	}
 	return nil
 }
 
+// Shutdown delivers the messages buffered by the producer until ctx is done,
+// then closes the manager
+func (m *Manager) Shutdown(ctx context.Context) error {
+	if m.producer == nil {
+		return nil
+	}
+
+	var err error
+	for remaining := m.producer.Len(); remaining > 0; remaining = m.producer.Flush(100) {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%d Kafka messages not delivered before shutdown", remaining)
+			break
+		}
+	}
+	m.Close()
+	return err
+}
+
+// EventID derives the ID of the event for a state change from the chain,
+// store, height and key it concerns. Events built from the same change get the
+// same ID however often the change is processed or the event redelivered.
+func EventID(chainName, storeKey string, height int64, key []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00", chainName, storeKey, height)
+	h.Write(key)
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// eventJSON encodes events in the proto3 JSON mapping, with the field names
+// of the schema and every field present
+var eventJSON = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
 // PublishStateChange publishes a state change event
-func (m *Manager) PublishStateChange(ctx context.Context, change *types.StateChange) error {
-	data, err := json.Marshal(change)
+func (m *Manager) PublishStateChange(ctx context.Context, change *eventsv1.StateChange) error {
+	message, err := StateChangeMessage(change)
 	if err != nil {
-		return fmt.Errorf("failed to marshal state change: %w", err)
+		return err
 	}
+	return m.produceMessage(ctx, m.kafkaMessage(&message))
+}
 
-	message := &kafka.Message{
-		TopicPartition: kafka.TopicPartition{
-			Topic:     &m.topic,
-			Partition: kafka.PartitionAny,
-		},
-		Key:   []byte(fmt.Sprintf("%s:%s", change.ChainName, change.StoreKey)),
-		Value: data,
-		Headers: []kafka.Header{
-			{Key: "chain", Value: []byte(change.ChainName)},
-			{Key: "store", Value: []byte(change.StoreKey)},
-			{Key: "height", Value: []byte(fmt.Sprintf("%d", change.Height))},
-		},
+// PublishBalanceEvent publishes a balance change event
+func (m *Manager) PublishBalanceEvent(ctx context.Context, event *eventsv1.BalanceEvent) error {
+	message, err := BalanceEventMessage(event)
+	if err != nil {
+		return err
 	}
+	return m.produceMessage(ctx, m.kafkaMessage(&message))
+}
 
-	deliveryChan := make(chan kafka.Event)
-	err = m.producer.Produce(message, deliveryChan)
+// PublishDelegationEvent publishes a delegation change event
+func (m *Manager) PublishDelegationEvent(ctx context.Context, event *eventsv1.DelegationEvent) error {
+	message, err := DelegationEventMessage(event)
 	if err != nil {
-		return fmt.Errorf("failed to produce message: %w", err)
+		return err
 	}
+	return m.produceMessage(ctx, m.kafkaMessage(&message))
+}
 
-	// Wait for delivery confirmation
-	select {
-	case e := <-deliveryChan:
-		if msg, ok := e.(*kafka.Message); ok {
-			if msg.TopicPartition.Error != nil {
-				return fmt.Errorf("delivery failed: %w", msg.TopicPartition.Error)
-			}
-		}
-	case <-ctx.Done():
-		return ctx.Err()
-	}
+// StateChangeMessage builds the message a state change is published as
+func StateChangeMessage(change *eventsv1.StateChange) (types.OutboxMessage, error) {
+	return eventMessage(change, fmt.Sprintf("%s:%s", change.ChainName, change.StoreKey), map[string]string{
+		"chain":    change.ChainName,
+		"type":     EventStateChange,
+		"store":    change.StoreKey,
+		"height":   fmt.Sprintf("%d", change.Height),
+		"event_id": EventID(change.ChainName, change.StoreKey, change.Height, change.Key),
+	})
+}
 
-	return nil
+// BalanceEventMessage builds the message a balance change event is published as
+func BalanceEventMessage(event *eventsv1.BalanceEvent) (types.OutboxMessage, error) {
+	return eventMessage(event, fmt.Sprintf("%s:balance:%s:%s", event.ChainName, event.Address, event.Denom), map[string]string{
+		"chain":    event.ChainName,
+		"type":     EventBalance,
+		"address":  event.Address,
+		"denom":    event.Denom,
+		"event_id": event.EventId,
+	})
 }
 
-// PublishBalanceEvent publishes a balance change event
-func (m *Manager) PublishBalanceEvent(ctx context.Context, event *types.BalanceEvent) error {
-	data, err := json.Marshal(event)
+// DelegationEventMessage builds the message a delegation change event is
+// published as
+func DelegationEventMessage(event *eventsv1.DelegationEvent) (types.OutboxMessage, error) {
+	return eventMessage(event, fmt.Sprintf("%s:delegation:%s:%s", event.ChainName, event.DelegatorAddress, event.ValidatorAddress), map[string]string{
+		"chain":     event.ChainName,
+		"type":      EventDelegation,
+		"delegator": event.DelegatorAddress,
+		"validator": event.ValidatorAddress,
+		"event_id":  event.EventId,
+	})
+}
+
+// ValidatorEventMessage builds the message a validator change event is
+// published as
+func ValidatorEventMessage(event *eventsv1.ValidatorEvent) (types.OutboxMessage, error) {
+	return eventMessage(event, fmt.Sprintf("%s:validator:%s", event.ChainName, event.OperatorAddress), map[string]string{
+		"chain":     event.ChainName,
+		"type":      EventValidator,
+		"validator": event.OperatorAddress,
+		"event_id":  event.EventId,
+	})
+}
+
+// ProposalEventMessage builds the message a proposal change event is
+// published as
+func ProposalEventMessage(event *eventsv1.ProposalEvent) (types.OutboxMessage, error) {
+	return eventMessage(event, fmt.Sprintf("%s:proposal:%d", event.ChainName, event.ProposalId), map[string]string{
+		"chain":    event.ChainName,
+		"type":     EventProposal,
+		"proposal": fmt.Sprintf("%d", event.ProposalId),
+		"event_id": event.EventId,
+	})
+}
+
+// eventMessage builds the message an event is published as. The schema
+// header names the event's message in the proto schema.
+func eventMessage(event proto.Message, key string, headers map[string]string) (types.OutboxMessage, error) {
+	name := event.ProtoReflect().Descriptor().FullName()
+	data, err := eventJSON.Marshal(event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal balance event: %w", err)
+		return types.OutboxMessage{}, fmt.Errorf("failed to marshal %s: %w", name, err)
 	}
 
-	message := &kafka.Message{
-		TopicPartition: kafka.TopicPartition{
-			Topic:     &m.topic,
-			Partition: kafka.PartitionAny,
-		},
-		Key:   []byte(fmt.Sprintf("%s:balance:%s:%s", event.ChainName, event.Address, event.Denom)),
-		Value: data,
-		Headers: []kafka.Header{
-			{Key: "chain", Value: []byte(event.ChainName)},
-			{Key: "type", Value: []byte("balance")},
-			{Key: "address", Value: []byte(event.Address)},
-			{Key: "denom", Value: []byte(event.Denom)},
-		},
+	headers["schema"] = string(name)
+	return types.OutboxMessage{
+		Key:     key,
+		Value:   data,
+		Headers: headers,
+	}, nil
+}
+
+// PublishBatch publishes messages and waits for their delivery. It returns the
+// number of leading messages delivered, so that a failed batch is resumed from
+// the first message that may not have been.
+func (m *Manager) PublishBatch(ctx context.Context, messages []types.OutboxMessage) (int, error) {
+	deliveryChan := make(chan kafka.Event, len(messages))
+
+	delivered := len(messages)
+	var deliveryErr error
+	produced := 0
+	for i := range messages {
+		message := m.kafkaMessage(&messages[i])
+		message.Opaque = i
+		if err := m.producer.Produce(message, deliveryChan); err != nil {
+			delivered = i
+			deliveryErr = fmt.Errorf("failed to produce message: %w", err)
+			break
+		}
+		produced++
 	}
 
-	return m.produceMessage(ctx, message)
+	// Wait for delivery confirmation of everything produced
+	for ; produced > 0; produced-- {
+		select {
+		case e := <-deliveryChan:
+			msg, ok := e.(*kafka.Message)
+			if !ok || msg.TopicPartition.Error == nil {
+				continue
+			}
+			if i := msg.Opaque.(int); i < delivered {
+				delivered = i
+				deliveryErr = fmt.Errorf("delivery failed: %w", msg.TopicPartition.Error)
+			}
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	return delivered, deliveryErr
 }
 
-// PublishDelegationEvent publishes a delegation change event
-func (m *Manager) PublishDelegationEvent(ctx context.Context, event *types.DelegationEvent) error {
-	data, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("failed to marshal delegation event: %w", err)
+// kafkaMessage converts a message to the topic's Kafka message, with its
+// non-empty headers in a stable order
+func (m *Manager) kafkaMessage(message *types.OutboxMessage) *kafka.Message {
+	keys := make([]string, 0, len(message.Headers))
+	for key, value := range message.Headers {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	headers := make([]kafka.Header, len(keys))
+	for i, key := range keys {
+		headers[i] = kafka.Header{Key: key, Value: []byte(message.Headers[key])}
 	}
 
-	message := &kafka.Message{
+	return &kafka.Message{
 		TopicPartition: kafka.TopicPartition{
 			Topic:     &m.topic,
 			Partition: kafka.PartitionAny,
 		},
-		Key:   []byte(fmt.Sprintf("%s:delegation:%s:%s", event.ChainName, event.DelegatorAddress, event.ValidatorAddress)),
-		Value: data,
-		Headers: []kafka.Header{
-			{Key: "chain", Value: []byte(event.ChainName)},
-			{Key: "type", Value: []byte("delegation")},
-			{Key: "delegator", Value: []byte(event.DelegatorAddress)},
-			{Key: "validator", Value: []byte(event.ValidatorAddress)},
-		},
+		Key:     []byte(message.Key),
+		Value:   message.Value,
+		Headers: headers,
 	}
-
-	return m.produceMessage(ctx, message)
 }
 
 // produceMessage is a helper method to produce a message with delivery confirmation
@@ -174,6 +312,22 @@ func (m *Manager) produceMessage(ctx context.Context, message *kafka.Message) er
 	return nil
 }
 
+// Ping checks that the Kafka brokers are reachable by fetching topic metadata
+func (m *Manager) Ping(ctx context.Context) error {
+	timeoutMs := 5000
+	if deadline, ok := ctx.Deadline(); ok {
+		timeoutMs = int(time.Until(deadline).Milliseconds())
+		if timeoutMs <= 0 {
+			return ctx.Err()
+		}
+	}
+
+	if _, err := m.producer.GetMetadata(&m.topic, false, timeoutMs); err != nil {
+		return fmt.Errorf("failed to fetch Kafka metadata: %w", err)
+	}
+	return nil
+}
+
 // Flush flushes any pending messages
 func (m *Manager) Flush(timeoutMs int) error {
 	remaining := m.producer.Flush(timeoutMs)
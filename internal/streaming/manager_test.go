@@ -0,0 +1,73 @@
+package streaming
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	eventsv1 "github.com/cosmos/state-mesh/pkg/events/v1"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+func TestEventMessages(t *testing.T) {
+	timestamp := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	balance := eventsv1.NewBalanceEvent(&types.BalanceEvent{
+		EventID: "0123456789abcdef0123456789abcdef", Timestamp: timestamp, ChainName: "cosmoshub",
+		Address: "cosmos1abc", Denom: "uatom", Amount: "100", ChangeType: "current", Height: 42,
+	})
+	proposal := eventsv1.NewProposalEvent("fedcba9876543210fedcba9876543210", &types.Proposal{
+		ChainName: "cosmoshub", ProposalID: 7, Status: "PROPOSAL_STATUS_PASSED", Height: 42, UpdatedAt: timestamp,
+	})
+
+	tests := []struct {
+		name       string
+		event      proto.Message
+		build      func() (types.OutboxMessage, error)
+		wantKey    string
+		wantType   string
+		wantSchema string
+	}{
+		{
+			name:       "balance",
+			event:      balance,
+			build:      func() (types.OutboxMessage, error) { return BalanceEventMessage(balance) },
+			wantKey:    "cosmoshub:balance:cosmos1abc:uatom",
+			wantType:   EventBalance,
+			wantSchema: "statemesh.events.v1.BalanceEvent",
+		},
+		{
+			name:       "proposal",
+			event:      proposal,
+			build:      func() (types.OutboxMessage, error) { return ProposalEventMessage(proposal) },
+			wantKey:    "cosmoshub:proposal:7",
+			wantType:   EventProposal,
+			wantSchema: "statemesh.events.v1.ProposalEvent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := tt.build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if message.Key != tt.wantKey {
+				t.Errorf("key = %q, want %q", message.Key, tt.wantKey)
+			}
+			if message.Headers["type"] != tt.wantType || message.Headers["schema"] != tt.wantSchema {
+				t.Errorf("headers = %v, want type %q and schema %q", message.Headers, tt.wantType, tt.wantSchema)
+			}
+
+			// Values decode as the schema's message
+			decoded := tt.event.ProtoReflect().New().Interface()
+			if err := protojson.Unmarshal(message.Value, decoded); err != nil {
+				t.Fatalf("failed to decode %s: %v", message.Value, err)
+			}
+			if !proto.Equal(decoded, tt.event) {
+				t.Errorf("decoded %v, want %v", decoded, tt.event)
+			}
+		})
+	}
+}
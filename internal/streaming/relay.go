@@ -0,0 +1,146 @@
+package streaming
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// outboxPruneInterval is how often delivered outbox messages past their
+// retention are deleted
+const outboxPruneInterval = 10 * time.Minute
+
+var (
+	outboxPublished = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "outbox",
+		Name:      "published_total",
+		Help:      "Outbox messages published to Kafka and marked delivered",
+	})
+	outboxFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "statemesh",
+		Subsystem: "outbox",
+		Name:      "relay_failures_total",
+		Help:      "Outbox relay batches that failed to publish or be marked delivered",
+	})
+)
+
+// OutboxStore is the store the relay reads undelivered messages from
+type OutboxStore interface {
+	DeliverOutbox(ctx context.Context, limit int, deliver func([]types.OutboxMessage) (int, error)) (int, error)
+	PruneOutbox(ctx context.Context, before time.Time, batchSize int) (int64, error)
+}
+
+// Relay publishes the messages written to the outbox by state transactions and
+// marks them delivered. A message may be published more than once if the relay
+// stops between publishing and marking it, never not at all.
+type Relay struct {
+	cfg     config.OutboxConfig
+	manager *Manager
+	store   OutboxStore
+	logger  *zap.Logger
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	stop    sync.Once
+}
+
+// NewRelay creates a new outbox relay
+func NewRelay(cfg config.OutboxConfig, manager *Manager, store OutboxStore, logger *zap.Logger) *Relay {
+	return &Relay{
+		cfg:     cfg,
+		manager: manager,
+		store:   store,
+		logger:  logger.Named("outbox"),
+	}
+}
+
+// Start runs the relay in the background
+func (r *Relay) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run(ctx)
+	}()
+}
+
+// Stop stops polling the outbox and waits for the batch in flight, then
+// publishes what the outbox still holds until ctx is done. Messages left over
+// are published by the next relay. Later calls do nothing.
+func (r *Relay) Stop(ctx context.Context) {
+	r.stop.Do(func() {
+		if r.cancel != nil {
+			r.cancel()
+		}
+		r.wg.Wait()
+		r.drain(ctx)
+	})
+}
+
+// run drains the outbox, then polls it on the configured interval
+func (r *Relay) run(ctx context.Context) {
+	poll := time.NewTicker(r.cfg.PollInterval)
+	defer poll.Stop()
+	prune := time.NewTicker(outboxPruneInterval)
+	defer prune.Stop()
+
+	r.logger.Info("Outbox relay started",
+		zap.Int("batch_size", r.cfg.BatchSize),
+		zap.Duration("poll_interval", r.cfg.PollInterval))
+
+	for {
+		r.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-poll.C:
+		case <-prune.C:
+			r.prune(ctx)
+		}
+	}
+}
+
+// drain publishes full batches until the outbox holds less than one
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivered, err := r.store.DeliverOutbox(ctx, r.cfg.BatchSize, func(messages []types.OutboxMessage) (int, error) {
+			return r.manager.PublishBatch(ctx, messages)
+		})
+		outboxPublished.Add(float64(delivered))
+		if err != nil {
+			if ctx.Err() == nil {
+				outboxFailures.Inc()
+				r.logger.Warn("Failed to relay outbox messages", zap.Int("delivered", delivered), zap.Error(err))
+			}
+			return
+		}
+		if delivered < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// prune deletes delivered messages past the retention
+func (r *Relay) prune(ctx context.Context) {
+	if r.cfg.Retention <= 0 {
+		return
+	}
+
+	pruned, err := r.store.PruneOutbox(ctx, time.Now().Add(-r.cfg.Retention), 0)
+	if err != nil {
+		r.logger.Warn("Failed to prune outbox", zap.Error(err))
+		return
+	}
+	if pruned > 0 {
+		r.logger.Debug("Pruned delivered outbox messages", zap.Int64("messages", pruned))
+	}
+}
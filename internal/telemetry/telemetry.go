@@ -0,0 +1,157 @@
+// Package telemetry reports panics and repeated ingestion failures to Sentry
+// when telemetry.sentry is enabled. Until Init enables it, every function is a
+// no-op apart from Recover re-panicking.
+package telemetry
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/cosmos/state-mesh/internal/config"
+)
+
+// Version is the build version, set with
+// -ldflags "-X github.com/cosmos/state-mesh/internal/telemetry.Version=..."
+var Version = "dev"
+
+var (
+	enabled      atomic.Bool
+	flushTimeout = 2 * time.Second
+	failures     = &failureTracker{threshold: 1, counts: make(map[[2]string]int)}
+)
+
+// Init configures error reporting. It does nothing when Sentry is disabled.
+func Init(cfg config.SentryConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.DSN == "" {
+		return fmt.Errorf("telemetry.sentry.dsn is required when Sentry is enabled")
+	}
+
+	release := cfg.Release
+	if release == "" {
+		release = "state-mesh@" + Version
+	}
+
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:              cfg.DSN,
+		Environment:      cfg.Environment,
+		Release:          release,
+		SampleRate:       cfg.SampleRate,
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize Sentry: %w", err)
+	}
+
+	if cfg.FlushTimeout > 0 {
+		flushTimeout = cfg.FlushTimeout
+	}
+	if cfg.FailureThreshold > 1 {
+		failures.threshold = cfg.FailureThreshold
+	}
+	enabled.Store(true)
+	return nil
+}
+
+// Flush waits for buffered events to be sent, up to the flush timeout
+func Flush() {
+	if enabled.Load() {
+		sentry.Flush(flushTimeout)
+	}
+}
+
+// Recover reports a panic with tags and panics again. It must be deferred
+// directly:
+//
+//	defer telemetry.Recover(map[string]string{"chain": name})
+func Recover(tags map[string]string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	CapturePanic(r, tags)
+	Flush()
+	panic(r)
+}
+
+// CapturePanic reports a recovered panic with tags
+func CapturePanic(r any, tags map[string]string) {
+	if !enabled.Load() {
+		return
+	}
+
+	hub := sentry.CurrentHub().Clone()
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+	})
+	hub.Recover(r)
+}
+
+// CaptureError reports an error with tags
+func CaptureError(err error, tags map[string]string, context map[string]any) {
+	if !enabled.Load() || err == nil {
+		return
+	}
+
+	hub := sentry.CurrentHub().Clone()
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+		if len(context) > 0 {
+			scope.SetContext("state-mesh", context)
+		}
+	})
+	hub.CaptureException(err)
+}
+
+// failureTracker counts the consecutive ingestion failures of chains and
+// modules
+type failureTracker struct {
+	threshold int
+
+	mu     sync.Mutex
+	counts map[[2]string]int // by chain and module
+}
+
+// IngestFailed counts a failure to ingest a chain's module, or the chain
+// itself when module is empty, and reports err once the module has failed
+// failure_threshold times in a row, then again every as many failures
+func IngestFailed(chain, module string, err error) {
+	if !enabled.Load() {
+		return
+	}
+
+	failures.mu.Lock()
+	key := [2]string{chain, module}
+	failures.counts[key]++
+	count := failures.counts[key]
+	failures.mu.Unlock()
+
+	if count%failures.threshold != 0 {
+		return
+	}
+
+	tags := map[string]string{"chain": chain}
+	if module != "" {
+		tags["module"] = module
+	}
+	CaptureError(err, tags, map[string]any{"consecutive_failures": count})
+}
+
+// IngestSucceeded resets the failure count of a chain's module, or of the
+// chain itself when module is empty
+func IngestSucceeded(chain, module string) {
+	if !enabled.Load() {
+		return
+	}
+
+	failures.mu.Lock()
+	delete(failures.counts, [2]string{chain, module})
+	failures.mu.Unlock()
+}
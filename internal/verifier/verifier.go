@@ -0,0 +1,245 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cosmos/state-mesh/internal/config"
+	"github.com/cosmos/state-mesh/internal/storage"
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// Discrepancy kinds
+const (
+	KindBalance    = "balance"
+	KindDelegation = "delegation"
+)
+
+// Discrepancy describes a stored row that does not match the chain
+type Discrepancy struct {
+	ChainName string `json:"chain_name"`
+	Kind      string `json:"kind"`
+	Address   string `json:"address"`
+	Key       string `json:"key"` // denom for balances, validator for delegations
+	Height    int64  `json:"height"`
+	Stored    string `json:"stored"`
+	Live      string `json:"live"`
+	Repaired  bool   `json:"repaired"`
+}
+
+// Report summarizes a verification run for a single chain
+type Report struct {
+	ChainName          string        `json:"chain_name"`
+	BalancesChecked    int           `json:"balances_checked"`
+	DelegationsChecked int           `json:"delegations_checked"`
+	Errors             int           `json:"errors"`
+	Discrepancies      []Discrepancy `json:"discrepancies"`
+	Duration           time.Duration `json:"duration"`
+}
+
+// Unresolved returns the number of discrepancies that were not repaired
+func (r *Report) Unresolved() int {
+	count := 0
+	for _, d := range r.Discrepancies {
+		if !d.Repaired {
+			count++
+		}
+	}
+	return count
+}
+
+// Verifier cross-checks stored state against live chain queries
+type Verifier struct {
+	cfg     config.VerifyConfig
+	storage *storage.Manager
+	logger  *zap.Logger
+}
+
+// New creates a new verifier
+func New(cfg config.VerifyConfig, storage *storage.Manager) *Verifier {
+	return &Verifier{
+		cfg:     cfg,
+		storage: storage,
+		logger:  zap.L().Named("verifier"),
+	}
+}
+
+// VerifyChain samples stored balances and delegations for the client's chain and
+// compares each row against the chain state at the row's stored height
+func (v *Verifier) VerifyChain(ctx context.Context, client *cosmos.Client) (*Report, error) {
+	start := time.Now()
+	chainName := client.ChainName()
+	report := &Report{ChainName: chainName}
+
+	balances, err := v.storage.State().SampleBalances(ctx, chainName, v.cfg.SampleSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, balance := range balances {
+		report.BalancesChecked++
+
+		live, err := client.GetBalance(cosmos.WithHeight(ctx, balance.Height), balance.Address, balance.Denom)
+		if err != nil {
+			report.Errors++
+			v.logger.Warn("Failed to query live balance",
+				zap.String("chain", chainName),
+				zap.String("address", balance.Address),
+				zap.String("denom", balance.Denom),
+				zap.Int64("height", balance.Height),
+				zap.Error(err))
+			continue
+		}
+
+		if v.withinTolerance(balance.Amount, live.Amount.String()) {
+			continue
+		}
+
+		d := Discrepancy{
+			ChainName: chainName,
+			Kind:      KindBalance,
+			Address:   balance.Address,
+			Key:       balance.Denom,
+			Height:    balance.Height,
+			Stored:    balance.Amount,
+			Live:      live.Amount.String(),
+		}
+
+		if v.cfg.Repair {
+			if err := v.repairBalance(ctx, balance, d.Live); err != nil {
+				v.logger.Error("Failed to repair balance",
+					zap.String("chain", chainName),
+					zap.String("address", balance.Address),
+					zap.Error(err))
+			} else {
+				d.Repaired = true
+			}
+		}
+
+		report.Discrepancies = append(report.Discrepancies, d)
+	}
+
+	delegations, err := v.storage.State().SampleDelegations(ctx, chainName, v.cfg.SampleSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, delegation := range delegations {
+		report.DelegationsChecked++
+
+		live := "0"
+		resp, err := client.GetDelegation(cosmos.WithHeight(ctx, delegation.Height), delegation.DelegatorAddress, delegation.ValidatorAddress)
+		if err != nil && status.Code(err) != codes.NotFound {
+			report.Errors++
+			v.logger.Warn("Failed to query live delegation",
+				zap.String("chain", chainName),
+				zap.String("delegator", delegation.DelegatorAddress),
+				zap.String("validator", delegation.ValidatorAddress),
+				zap.Int64("height", delegation.Height),
+				zap.Error(err))
+			continue
+		}
+		if err == nil && resp != nil {
+			live = resp.Delegation.Shares.String()
+		}
+
+		if v.withinTolerance(delegation.Shares, live) {
+			continue
+		}
+
+		d := Discrepancy{
+			ChainName: chainName,
+			Kind:      KindDelegation,
+			Address:   delegation.DelegatorAddress,
+			Key:       delegation.ValidatorAddress,
+			Height:    delegation.Height,
+			Stored:    delegation.Shares,
+			Live:      live,
+		}
+
+		if v.cfg.Repair {
+			if err := v.repairDelegation(ctx, delegation, live); err != nil {
+				v.logger.Error("Failed to repair delegation",
+					zap.String("chain", chainName),
+					zap.String("delegator", delegation.DelegatorAddress),
+					zap.Error(err))
+			} else {
+				d.Repaired = true
+			}
+		}
+
+		report.Discrepancies = append(report.Discrepancies, d)
+	}
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// withinTolerance reports whether the stored value matches the live value within
+// the configured relative tolerance
+func (v *Verifier) withinTolerance(stored, live string) bool {
+	s, ok := new(big.Float).SetString(stored)
+	if !ok {
+		return false
+	}
+	l, ok := new(big.Float).SetString(live)
+	if !ok {
+		return false
+	}
+
+	diff := new(big.Float).Sub(s, l)
+	diff.Abs(diff)
+	if diff.Sign() == 0 {
+		return true
+	}
+
+	allowed := new(big.Float).Abs(l)
+	allowed.Mul(allowed, big.NewFloat(v.cfg.Tolerance))
+	return diff.Cmp(allowed) <= 0
+}
+
+// repairBalance overwrites a stored balance with the live chain value
+func (v *Verifier) repairBalance(ctx context.Context, balance types.Balance, live string) error {
+	tx, err := v.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	balance.Amount = live
+	balance.UpdatedAt = time.Now()
+	if err := tx.State().UpsertBalance(ctx, &balance); err != nil {
+		return fmt.Errorf("failed to upsert balance: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// repairDelegation overwrites or removes a stored delegation based on the live chain value
+func (v *Verifier) repairDelegation(ctx context.Context, delegation types.Delegation, live string) error {
+	tx, err := v.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if live == "0" {
+		err = tx.State().DeleteDelegation(ctx, delegation.ChainName, delegation.DelegatorAddress, delegation.ValidatorAddress)
+	} else {
+		delegation.Shares = live
+		delegation.UpdatedAt = time.Now()
+		err = tx.State().UpsertDelegation(ctx, &delegation)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to repair delegation: %w", err)
+	}
+
+	return tx.Commit()
+}
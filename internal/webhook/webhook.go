@@ -0,0 +1,78 @@
+// Package webhook delivers notifications to tenants' webhooks
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// Request headers. The signature is the hex HMAC-SHA256 of the body keyed
+// with the webhook's secret.
+const (
+	EventHeader     = "X-StateMesh-Event"
+	SignatureHeader = "X-StateMesh-Signature"
+)
+
+// Events webhooks may subscribe to
+const (
+	EventDigest              = "digest"
+	EventUnbondingCompletion = "unbonding_completion"
+	EventCommissionIncrease  = "commission_increase"
+	EventAnomaly             = "anomaly"
+	EventAlert               = "alert"
+)
+
+// Subscribed reports whether an enabled webhook subscribes to an event
+func Subscribed(webhook types.Webhook, event string) bool {
+	return webhook.Enabled && slices.Contains(webhook.Events, event)
+}
+
+// Sender posts signed JSON payloads to webhooks
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a sender whose requests time out after timeout
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts a payload for an event to a webhook, signed with its secret. Any
+// response other than 2xx is an error.
+func (s *Sender) Send(ctx context.Context, webhook *types.Webhook, event string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s payload: %w", event, err)
+	}
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write(body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
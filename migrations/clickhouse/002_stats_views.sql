@@ -0,0 +1,31 @@
+-- Align event tables with the columns written by the ingestion pipeline and
+-- add reward events used by the daily reward issuance view
+
+ALTER TABLE balance_events ADD COLUMN IF NOT EXISTS previous_amount String AFTER amount;
+ALTER TABLE balance_events ADD COLUMN IF NOT EXISTS change_type LowCardinality(String) AFTER previous_amount;
+ALTER TABLE balance_events ADD COLUMN IF NOT EXISTS tx_hash String AFTER height;
+
+ALTER TABLE delegation_events ADD COLUMN IF NOT EXISTS shares String AFTER validator_address;
+ALTER TABLE delegation_events ADD COLUMN IF NOT EXISTS previous_shares String AFTER shares;
+ALTER TABLE delegation_events ADD COLUMN IF NOT EXISTS change_type LowCardinality(String) AFTER previous_shares;
+ALTER TABLE delegation_events ADD COLUMN IF NOT EXISTS tx_hash String AFTER height;
+
+-- Reward events table for analytics
+CREATE TABLE IF NOT EXISTS reward_events (
+    chain_name LowCardinality(String),
+    delegator_address String,
+    validator_address String,
+    denom LowCardinality(String),
+    amount String,
+    height UInt64,
+    tx_hash String,
+    timestamp DateTime64(3),
+    date Date MATERIALIZED toDate(timestamp)
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMM(date)
+ORDER BY (chain_name, delegator_address, timestamp)
+SETTINGS index_granularity = 8192;
+
+-- The daily stats views (daily_active_addresses, daily_delegation_volume,
+-- daily_reward_issuance) are created and backfilled by the application at
+-- startup, see internal/storage/clickhouse_views.go
@@ -0,0 +1,26 @@
+-- Block proposers and per-validator commit signatures
+-- Written by the ingester's blocks module; re-ingested heights are deduplicated
+-- by counting distinct heights in queries
+
+CREATE TABLE IF NOT EXISTS block_proposers (
+    chain_name LowCardinality(String),
+    height UInt64,
+    proposer_address String,
+    timestamp DateTime64(3),
+    date Date MATERIALIZED toDate(timestamp)
+) ENGINE = ReplacingMergeTree()
+PARTITION BY toYYYYMM(date)
+ORDER BY (chain_name, height)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS block_signatures (
+    chain_name LowCardinality(String),
+    height UInt64,
+    consensus_address String,
+    signed UInt8,
+    timestamp DateTime64(3),
+    date Date MATERIALIZED toDate(timestamp)
+) ENGINE = ReplacingMergeTree()
+PARTITION BY toYYYYMM(date)
+ORDER BY (chain_name, consensus_address, height)
+SETTINGS index_granularity = 8192;
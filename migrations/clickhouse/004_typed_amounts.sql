@@ -0,0 +1,67 @@
+-- Store amounts as typed numeric columns instead of strings so analytics can
+-- aggregate them natively and they compress better. Balance amounts are
+-- integers (UInt256); delegation shares and reward amounts are decimals with
+-- 18 fractional digits (Decimal256(18)), the precision of the Cosmos SDK's
+-- LegacyDec. The application converts values at ingestion time.
+--
+-- The daily_delegation_volume_mv and daily_reward_issuance_mv views parse the
+-- old string columns and are dropped here; the application recreates them at
+-- startup (see internal/storage/clickhouse_views.go). Their target tables and
+-- data are kept.
+
+DROP VIEW IF EXISTS daily_delegation_volume_mv;
+DROP VIEW IF EXISTS daily_reward_issuance_mv;
+DROP VIEW IF EXISTS balance_changes_daily;
+
+-- Values that do not parse (including empty previous values) become zero
+ALTER TABLE balance_events
+    UPDATE amount = toString(toUInt256OrZero(amount))
+    WHERE toUInt256OrNull(amount) IS NULL
+    SETTINGS mutations_sync = 2;
+ALTER TABLE balance_events
+    UPDATE previous_amount = toString(toUInt256OrZero(previous_amount))
+    WHERE toUInt256OrNull(previous_amount) IS NULL
+    SETTINGS mutations_sync = 2;
+ALTER TABLE delegation_events
+    UPDATE shares = toString(toDecimal256OrZero(shares, 18))
+    WHERE toDecimal256OrNull(shares, 18) IS NULL
+    SETTINGS mutations_sync = 2;
+ALTER TABLE delegation_events
+    UPDATE previous_shares = toString(toDecimal256OrZero(previous_shares, 18))
+    WHERE toDecimal256OrNull(previous_shares, 18) IS NULL
+    SETTINGS mutations_sync = 2;
+ALTER TABLE reward_events
+    UPDATE amount = toString(toDecimal256OrZero(amount, 18))
+    WHERE toDecimal256OrNull(amount, 18) IS NULL
+    SETTINGS mutations_sync = 2;
+
+-- Heights increase along each table's sort order, so delta-encode them
+ALTER TABLE balance_events
+    MODIFY COLUMN amount UInt256 CODEC(ZSTD(3)),
+    MODIFY COLUMN previous_amount UInt256 CODEC(ZSTD(3)),
+    MODIFY COLUMN height UInt64 CODEC(Delta, ZSTD(1));
+
+ALTER TABLE delegation_events
+    MODIFY COLUMN shares Decimal256(18) CODEC(ZSTD(3)),
+    MODIFY COLUMN previous_shares Decimal256(18) CODEC(ZSTD(3)),
+    MODIFY COLUMN height UInt64 CODEC(Delta, ZSTD(1));
+
+ALTER TABLE reward_events
+    MODIFY COLUMN amount Decimal256(18) CODEC(ZSTD(3)),
+    MODIFY COLUMN height UInt64 CODEC(Delta, ZSTD(1));
+
+-- Daily balance changes, recreated over the typed amount column
+CREATE MATERIALIZED VIEW IF NOT EXISTS balance_changes_daily
+ENGINE = SummingMergeTree()
+PARTITION BY toYYYYMM(date)
+ORDER BY (chain_name, address, denom, date)
+POPULATE
+AS SELECT
+    chain_name,
+    address,
+    denom,
+    toDate(timestamp) as date,
+    count() as events_count,
+    sum(toFloat64(amount)) as total_amount_change
+FROM balance_events
+GROUP BY chain_name, address, denom, date;
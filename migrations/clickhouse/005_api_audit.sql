@@ -0,0 +1,21 @@
+-- Authenticated API calls for usage reporting and per-key metering. Query
+-- parameters are stored only as a hash so that addresses and other request
+-- details are not retained.
+
+CREATE TABLE IF NOT EXISTS api_audit (
+    timestamp DateTime64(3),
+    tenant_id LowCardinality(String),
+    key_id String,
+    method LowCardinality(String),
+    endpoint LowCardinality(String),
+    params_hash String,
+    status UInt16,
+    latency_ms Float64,
+    response_bytes UInt64,
+    request_id String,
+    date Date MATERIALIZED toDate(timestamp)
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMM(date)
+ORDER BY (tenant_id, key_id, timestamp)
+TTL date + INTERVAL 13 MONTH
+SETTINGS index_granularity = 8192;
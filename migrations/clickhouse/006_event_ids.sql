@@ -0,0 +1,16 @@
+-- Deterministic event IDs, derived from the chain, store, height and key of
+-- the state change behind an event. The application drops IDs repeated in a
+-- batch and derives the batch's deduplication token from its IDs, so replayed
+-- and retried events are not counted twice by the stats views. Events written
+-- before this migration have an empty ID.
+
+ALTER TABLE balance_events ADD COLUMN IF NOT EXISTS event_id String AFTER tx_hash;
+ALTER TABLE delegation_events ADD COLUMN IF NOT EXISTS event_id String AFTER tx_hash;
+
+ALTER TABLE balance_events ADD INDEX IF NOT EXISTS idx_event_id event_id TYPE bloom_filter(0.01) GRANULARITY 4;
+ALTER TABLE delegation_events ADD INDEX IF NOT EXISTS idx_event_id event_id TYPE bloom_filter(0.01) GRANULARITY 4;
+
+-- Inserts carry a deduplication token so that a retried insert that already
+-- landed is ignored; non-replicated tables only honour it within this window
+ALTER TABLE balance_events MODIFY SETTING non_replicated_deduplication_window = 1000;
+ALTER TABLE delegation_events MODIFY SETTING non_replicated_deduplication_window = 1000;
@@ -0,0 +1,19 @@
+-- Changes to stores without a built-in module, decoded by the rules configured
+-- in state_listener.decoders into generic key/value records. Values decoded
+-- from protobuf are JSON and can be queried with the JSONExtract functions.
+
+CREATE TABLE IF NOT EXISTS decoded_state_changes (
+    chain_name LowCardinality(String),
+    store_key LowCardinality(String),
+    entity LowCardinality(String),
+    key String,
+    value String,
+    value_encoding LowCardinality(String),
+    is_delete UInt8,
+    height UInt64,
+    timestamp DateTime64(3),
+    date Date MATERIALIZED toDate(timestamp)
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMM(date)
+ORDER BY (chain_name, store_key, entity, key, height)
+SETTINGS index_granularity = 8192;
@@ -0,0 +1,19 @@
+-- Every raw ADR-038 state change received by the state listener, kept when
+-- state_listener.archive is enabled so that stores can be decoded again with
+-- `state-mesh redecode` once new module support is added. Keys and values are
+-- the raw bytes.
+
+CREATE TABLE IF NOT EXISTS state_change_archive (
+    chain_name LowCardinality(String),
+    store_key LowCardinality(String),
+    key String,
+    value String,
+    is_delete UInt8,
+    height UInt64,
+    timestamp DateTime64(6),
+    sequence Int64,
+    date Date MATERIALIZED toDate(timestamp)
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMM(date)
+ORDER BY (chain_name, height, store_key, timestamp, sequence)
+SETTINGS index_granularity = 8192;
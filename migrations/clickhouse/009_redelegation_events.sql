@@ -0,0 +1,18 @@
+-- Stake moved between validators, the edges of the delegation flow graph.
+-- Amounts are the tokens of each redelegation entry (UInt256).
+
+CREATE TABLE IF NOT EXISTS redelegation_events (
+    chain_name LowCardinality(String),
+    delegator_address String,
+    src_validator_address String,
+    dst_validator_address String,
+    amount UInt256 CODEC(ZSTD(3)),
+    height UInt64 CODEC(Delta, ZSTD(1)),
+    event_id String,
+    timestamp DateTime64(3),
+    date Date MATERIALIZED toDate(timestamp),
+    INDEX idx_event_id event_id TYPE bloom_filter(0.01) GRANULARITY 4
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMM(date)
+ORDER BY (chain_name, timestamp, src_validator_address, dst_validator_address)
+SETTINGS index_granularity = 8192, non_replicated_deduplication_window = 1000;
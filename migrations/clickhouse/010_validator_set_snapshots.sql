@@ -0,0 +1,17 @@
+-- Bonded validator set of each chain, snapshotted by the staking module
+-- whenever the set or its voting power changes. The set at any height is the
+-- latest snapshot at or below it. Replayed snapshots replace themselves.
+
+CREATE TABLE IF NOT EXISTS validator_set_snapshots (
+    chain_name LowCardinality(String),
+    height UInt64 CODEC(Delta, ZSTD(1)),
+    operator_address String,
+    consensus_address String,
+    moniker String,
+    tokens UInt256 CODEC(ZSTD(3)),
+    voting_power UInt64,
+    timestamp DateTime64(3)
+) ENGINE = ReplacingMergeTree()
+PARTITION BY chain_name
+ORDER BY (chain_name, height, operator_address)
+SETTINGS index_granularity = 8192;
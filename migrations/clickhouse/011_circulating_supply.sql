@@ -0,0 +1,17 @@
+-- Circulating supply of each chain's staking denom, recorded by the auth
+-- module whenever it changes. Replayed records replace themselves.
+
+CREATE TABLE IF NOT EXISTS circulating_supply (
+    chain_name LowCardinality(String),
+    denom LowCardinality(String),
+    height UInt64 CODEC(Delta, ZSTD(1)),
+    total UInt256 CODEC(ZSTD(3)),
+    vesting_locked UInt256 CODEC(ZSTD(3)),
+    module_accounts UInt256 CODEC(ZSTD(3)),
+    treasury UInt256 CODEC(ZSTD(3)),
+    circulating UInt256 CODEC(ZSTD(3)),
+    timestamp DateTime64(3)
+) ENGINE = ReplacingMergeTree()
+PARTITION BY chain_name
+ORDER BY (chain_name, denom, height)
+SETTINGS index_granularity = 8192;
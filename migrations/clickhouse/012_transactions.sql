@@ -0,0 +1,22 @@
+-- Transactions indexed by the blocks module with their result, gas and fee.
+-- A fee paid in several denoms keeps only its first coin. The daily fee stats
+-- view (daily_fee_stats) is created and backfilled by the application at
+-- startup, see internal/storage/clickhouse_views.go
+
+CREATE TABLE IF NOT EXISTS transactions (
+    chain_name LowCardinality(String),
+    height UInt64 CODEC(Delta, ZSTD(1)),
+    tx_hash String,
+    code UInt32,
+    gas_wanted UInt64,
+    gas_used UInt64,
+    fee_denom LowCardinality(String),
+    fee_amount UInt256 CODEC(ZSTD(3)),
+    fee_payer String,
+    message_types Array(LowCardinality(String)),
+    timestamp DateTime64(3),
+    date Date MATERIALIZED toDate(timestamp)
+) ENGINE = ReplacingMergeTree()
+PARTITION BY toYYYYMM(date)
+ORDER BY (chain_name, height, tx_hash)
+SETTINGS index_granularity = 8192;
@@ -0,0 +1,22 @@
+-- IBC packet events (sent, received, acknowledged, timed out) emitted by the
+-- transactions the blocks module indexes. Ports and channels are those of the
+-- packet, so packets a chain sent are keyed by its own end of the channel.
+-- Replayed events replace themselves.
+
+CREATE TABLE IF NOT EXISTS ibc_packet_events (
+    chain_name LowCardinality(String),
+    event_type LowCardinality(String),
+    sequence UInt64,
+    src_port LowCardinality(String),
+    src_channel LowCardinality(String),
+    dst_port LowCardinality(String),
+    dst_channel LowCardinality(String),
+    relayer String,
+    tx_hash String,
+    height UInt64 CODEC(Delta, ZSTD(1)),
+    timestamp DateTime64(3),
+    date Date MATERIALIZED toDate(timestamp)
+) ENGINE = ReplacingMergeTree()
+PARTITION BY toYYYYMM(date)
+ORDER BY (chain_name, src_port, src_channel, sequence, event_type, tx_hash)
+SETTINGS index_granularity = 8192;
@@ -0,0 +1,15 @@
+-- Epoch boundaries of chains that process most state changes at epochs, e.g.
+-- Osmosis' daily epoch, recorded by the ingester when it sees a new epoch
+-- start. Analytics join events to the epoch they happened in by height, so
+-- they can be bucketed by epoch instead of by day.
+
+CREATE TABLE IF NOT EXISTS epochs (
+    chain_name LowCardinality(String),
+    identifier LowCardinality(String),
+    number UInt64,
+    start_height UInt64,
+    start_time DateTime64(3),
+    duration_seconds UInt64
+) ENGINE = ReplacingMergeTree()
+ORDER BY (chain_name, identifier, number)
+SETTINGS index_granularity = 8192;
@@ -0,0 +1,14 @@
+-- Projections sorting balance and delegation events by account and time, for
+-- account event replays and history bounded by time, which the tables' sort
+-- keys only serve per denom or validator. Checked at startup by the server
+-- and the ingester, which warn when one is missing.
+
+ALTER TABLE balance_events ADD PROJECTION IF NOT EXISTS by_address_time (
+    SELECT * ORDER BY (chain_name, address, timestamp)
+);
+ALTER TABLE balance_events MATERIALIZE PROJECTION by_address_time;
+
+ALTER TABLE delegation_events ADD PROJECTION IF NOT EXISTS by_delegator_time (
+    SELECT * ORDER BY (chain_name, delegator_address, timestamp)
+);
+ALTER TABLE delegation_events MATERIALIZE PROJECTION by_delegator_time;
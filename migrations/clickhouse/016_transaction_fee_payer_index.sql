@@ -0,0 +1,6 @@
+-- Skipping index on the fee payer of indexed transactions, for the
+-- transactions of an address in its activity feed, which the table's sort key
+-- only serves per height. Checked at startup with the other indexes.
+
+ALTER TABLE transactions ADD INDEX IF NOT EXISTS idx_fee_payer fee_payer TYPE bloom_filter GRANULARITY 1;
+ALTER TABLE transactions MATERIALIZE INDEX idx_fee_payer;
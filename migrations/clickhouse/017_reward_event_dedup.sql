@@ -0,0 +1,5 @@
+-- Reward events are inserted from indexed transactions one block at a time,
+-- with a deduplication token per block, so that re-polling a block does not
+-- count its rewards twice in daily_reward_issuance. Non-replicated tables only
+-- honour the token within this window.
+ALTER TABLE reward_events MODIFY SETTING non_replicated_deduplication_window = 1000;
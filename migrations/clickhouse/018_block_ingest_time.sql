@@ -0,0 +1,4 @@
+-- When each block was written, for charting ingest lag against the block time.
+-- Blocks written before this migration have no ingest time and are left out
+-- of the lag.
+ALTER TABLE block_proposers ADD COLUMN IF NOT EXISTS ingested_at Nullable(DateTime64(3)) AFTER timestamp;
@@ -0,0 +1,11 @@
+-- Whether each reward withdrawal was claimed by MsgWithdrawDelegatorReward,
+-- rather than paid by a delegation change, and whether the claim was delegated
+-- again within the block. Withdrawals indexed before this migration count as
+-- neither.
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS claimed UInt8 DEFAULT 0 AFTER tx_hash;
+ALTER TABLE reward_events ADD COLUMN IF NOT EXISTS compounded UInt8 DEFAULT 0 AFTER claimed;
+
+-- A Buffer table keeps the columns of its table when it was created; the
+-- application recreates it with the new columns at startup, and dropping it
+-- flushes its pending rows first
+DROP TABLE IF EXISTS reward_events_buffer;
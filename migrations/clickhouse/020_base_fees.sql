@@ -0,0 +1,14 @@
+-- Base fee of each chain's fee market by denom, recorded by the feemarket
+-- module at every poll for gas price recommendations and history. Replayed
+-- records replace themselves.
+
+CREATE TABLE IF NOT EXISTS base_fees (
+    chain_name LowCardinality(String),
+    denom LowCardinality(String),
+    height UInt64 CODEC(Delta, ZSTD(1)),
+    amount Decimal256(18) CODEC(ZSTD(3)),
+    timestamp DateTime64(3)
+) ENGINE = ReplacingMergeTree()
+PARTITION BY chain_name
+ORDER BY (chain_name, denom, height)
+SETTINGS index_granularity = 8192;
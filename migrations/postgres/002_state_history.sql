@@ -0,0 +1,56 @@
+-- Height-versioned history for balances and delegations
+-- Every write to the latest-state tables is mirrored into a history table so
+-- past values can be queried; retention is enforced by the pruner
+
+-- Balance history table
+CREATE TABLE balance_history (
+    id BIGSERIAL PRIMARY KEY,
+    chain_name VARCHAR(64) NOT NULL,
+    address VARCHAR(128) NOT NULL,
+    denom VARCHAR(128) NOT NULL,
+    amount DECIMAL(78, 0) NOT NULL DEFAULT 0,
+    height BIGINT NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for balance history
+CREATE INDEX idx_balance_history_chain_address ON balance_history(chain_name, address, denom, height DESC);
+CREATE INDEX idx_balance_history_recorded_at ON balance_history(recorded_at);
+
+-- Delegation history table
+CREATE TABLE delegation_history (
+    id BIGSERIAL PRIMARY KEY,
+    chain_name VARCHAR(64) NOT NULL,
+    delegator_address VARCHAR(128) NOT NULL,
+    validator_address VARCHAR(128) NOT NULL,
+    shares DECIMAL(78, 18) NOT NULL DEFAULT 0,
+    height BIGINT NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for delegation history
+CREATE INDEX idx_delegation_history_chain_delegator ON delegation_history(chain_name, delegator_address, validator_address, height DESC);
+CREATE INDEX idx_delegation_history_recorded_at ON delegation_history(recorded_at);
+
+-- Record history on every balance write
+CREATE OR REPLACE FUNCTION record_balance_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO balance_history (chain_name, address, denom, amount, height)
+    VALUES (NEW.chain_name, NEW.address, NEW.denom, NEW.amount, NEW.height);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Record history on every delegation write
+CREATE OR REPLACE FUNCTION record_delegation_history()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO delegation_history (chain_name, delegator_address, validator_address, shares, height)
+    VALUES (NEW.chain_name, NEW.delegator_address, NEW.validator_address, NEW.shares, NEW.height);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_balances_history AFTER INSERT OR UPDATE ON balances FOR EACH ROW EXECUTE FUNCTION record_balance_history();
+CREATE TRIGGER record_delegations_history AFTER INSERT OR UPDATE ON delegations FOR EACH ROW EXECUTE FUNCTION record_delegation_history();
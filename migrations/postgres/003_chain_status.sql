@@ -0,0 +1,16 @@
+-- Per-chain sync status
+-- Updated by the ingester every cycle with the node's latest height and version
+-- and the last height whose state was fully ingested
+
+CREATE TABLE chain_status (
+    chain_name VARCHAR(64) PRIMARY KEY REFERENCES chains(name) ON DELETE CASCADE,
+    latest_height BIGINT NOT NULL DEFAULT 0,
+    latest_block_time TIMESTAMP WITH TIME ZONE,
+    last_ingested_height BIGINT NOT NULL DEFAULT 0,
+    catching_up BOOLEAN NOT NULL DEFAULT FALSE,
+    node_version VARCHAR(64) NOT NULL DEFAULT '',
+    app_version VARCHAR(64) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_chain_status_updated_at BEFORE UPDATE ON chain_status FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,14 @@
+-- Consensus address to operator address mapping
+-- Slashing and uptime data is keyed by consensus address; this table joins it
+-- back to validators, including addresses a validator no longer uses
+
+CREATE TABLE validator_consensus_addresses (
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    consensus_address VARCHAR(128) NOT NULL,
+    operator_address VARCHAR(128) NOT NULL,
+    first_seen_height BIGINT NOT NULL,
+    last_seen_height BIGINT NOT NULL,
+    PRIMARY KEY (chain_name, consensus_address)
+);
+
+CREATE INDEX idx_validator_consensus_addresses_operator ON validator_consensus_addresses(chain_name, operator_address);
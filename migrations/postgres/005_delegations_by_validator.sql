@@ -0,0 +1,2 @@
+-- Delegator listings per validator are ordered by shares
+CREATE INDEX idx_delegations_chain_validator_shares ON delegations(chain_name, validator_address, shares DESC);
@@ -0,0 +1,11 @@
+-- Trigram indexes backing the search endpoint
+-- Moniker, proposal title and denom searches use similarity and ILIKE; address
+-- searches use prefix LIKE, which trigram indexes also serve
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_validators_moniker_trgm ON validators USING GIN (moniker gin_trgm_ops);
+CREATE INDEX idx_validators_operator_address_trgm ON validators USING GIN (operator_address gin_trgm_ops);
+CREATE INDEX idx_proposals_title_trgm ON proposals USING GIN ((content->>'title') gin_trgm_ops);
+CREATE INDEX idx_supply_denom_trgm ON supply USING GIN (denom gin_trgm_ops);
+CREATE INDEX idx_accounts_address_trgm ON accounts USING GIN (address gin_trgm_ops);
@@ -0,0 +1,14 @@
+-- Block headers for the explorer endpoints
+CREATE TABLE blocks (
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    height BIGINT NOT NULL,
+    hash VARCHAR(64) NOT NULL,
+    proposer_address VARCHAR(128) NOT NULL,
+    tx_count INTEGER NOT NULL DEFAULT 0,
+    time TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (chain_name, height)
+);
+
+CREATE INDEX idx_blocks_chain_time ON blocks(chain_name, time DESC);
+CREATE INDEX idx_blocks_hash ON blocks(hash);
@@ -0,0 +1,7 @@
+-- Accounts are discovered from state change events; record the first height an
+-- account was seen alongside the latest height in the existing height column
+
+ALTER TABLE accounts ADD COLUMN first_seen_height BIGINT NOT NULL DEFAULT 0;
+UPDATE accounts SET first_seen_height = height WHERE first_seen_height = 0;
+
+CREATE INDEX idx_accounts_first_seen_height ON accounts(chain_name, first_seen_height);
@@ -0,0 +1,3 @@
+-- API keys may be assigned a role from the configuration restricting the
+-- chains, modules and endpoints they can access; NULL means unrestricted
+ALTER TABLE api_keys ADD COLUMN role VARCHAR(64);
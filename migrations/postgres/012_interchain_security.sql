@@ -0,0 +1,22 @@
+-- Interchain Security consumer chains of each provider chain and the provider
+-- validators in their validator sets, replaced on every poll of the provider
+CREATE TABLE consumer_chains (
+    provider_chain VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    chain_id VARCHAR(64) NOT NULL,
+    consumer_id VARCHAR(64) NOT NULL DEFAULT '',
+    client_id VARCHAR(64) NOT NULL,
+    height BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (provider_chain, chain_id)
+);
+
+CREATE INDEX idx_consumer_chains_chain_id ON consumer_chains(chain_id);
+
+CREATE TABLE consumer_validators (
+    provider_chain VARCHAR(64) NOT NULL,
+    chain_id VARCHAR(64) NOT NULL,
+    provider_address VARCHAR(128) NOT NULL,
+    consumer_address VARCHAR(128) NOT NULL DEFAULT '',
+    PRIMARY KEY (provider_chain, chain_id, provider_address),
+    FOREIGN KEY (provider_chain, chain_id) REFERENCES consumer_chains(provider_chain, chain_id) ON DELETE CASCADE
+);
@@ -0,0 +1,15 @@
+-- Entity labels of known addresses (exchange wallets, foundation, bridges),
+-- managed through the admin API and shown on account responses and analytics
+CREATE TABLE address_labels (
+    chain_name VARCHAR(64) NOT NULL,
+    address VARCHAR(128) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    category VARCHAR(32) NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (chain_name, address)
+);
+
+CREATE INDEX idx_address_labels_category ON address_labels(chain_name, category);
+CREATE INDEX idx_address_labels_tags ON address_labels USING GIN (tags);
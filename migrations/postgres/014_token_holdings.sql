@@ -0,0 +1,27 @@
+-- CosmWasm token contracts (CW20 and CW721) and the holdings of watched
+-- addresses, read by smart-querying the contracts
+CREATE TABLE token_contracts (
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    contract VARCHAR(128) NOT NULL,
+    standard VARCHAR(16) NOT NULL,
+    name VARCHAR(128) NOT NULL DEFAULT '',
+    symbol VARCHAR(64) NOT NULL DEFAULT '',
+    decimals INTEGER NOT NULL DEFAULT 0,
+    height BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (chain_name, contract)
+);
+
+CREATE TABLE token_holdings (
+    chain_name VARCHAR(64) NOT NULL,
+    contract VARCHAR(128) NOT NULL,
+    address VARCHAR(128) NOT NULL,
+    amount DECIMAL(78, 0) NOT NULL DEFAULT 0,
+    token_ids TEXT[] NOT NULL DEFAULT '{}',
+    height BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (chain_name, contract, address),
+    FOREIGN KEY (chain_name, contract) REFERENCES token_contracts(chain_name, contract) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_token_holdings_address ON token_holdings(chain_name, address);
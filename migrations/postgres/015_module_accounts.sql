@@ -0,0 +1,21 @@
+-- Module accounts of each chain from the auth module (their balances are kept
+-- in balances like any other account) and the community pool, both replaced
+-- on every poll
+CREATE TABLE module_accounts (
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    address VARCHAR(128) NOT NULL,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    height BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (chain_name, name)
+);
+
+CREATE TABLE community_pool (
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    denom VARCHAR(128) NOT NULL,
+    amount DECIMAL(78, 18) NOT NULL DEFAULT 0,
+    height BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (chain_name, denom)
+);
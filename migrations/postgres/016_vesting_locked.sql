@@ -0,0 +1,16 @@
+-- Coins still locked in each vesting account of a chain, less what the account
+-- has delegated, replaced on every auth module poll. Fully vested accounts
+-- have no rows.
+CREATE TABLE vesting_locked (
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    address VARCHAR(128) NOT NULL,
+    denom VARCHAR(128) NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    amount DECIMAL(78, 0) NOT NULL DEFAULT 0,
+    end_time TIMESTAMP WITH TIME ZONE,
+    height BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (chain_name, address, denom)
+);
+
+CREATE INDEX idx_vesting_locked_chain_denom ON vesting_locked(chain_name, denom);
@@ -0,0 +1,18 @@
+-- Summaries of ingest cycles: per module duration, rows written to the state
+-- store, gRPC calls and errors, served by the admin API so operators can see
+-- ingestion trends without the logs. Pruned after retention.ingest_run_retention.
+CREATE TABLE ingest_runs (
+    id BIGSERIAL PRIMARY KEY,
+    chain_name VARCHAR(64) NOT NULL,
+    height BIGINT NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    rows_written BIGINT NOT NULL,
+    grpc_calls BIGINT NOT NULL,
+    errors INTEGER NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    modules JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX idx_ingest_runs_chain ON ingest_runs(chain_name, started_at DESC);
+CREATE INDEX idx_ingest_runs_started ON ingest_runs(started_at);
@@ -0,0 +1,9 @@
+-- Version of the applied migrations, checked by the startup probes and the
+-- ingester before they report ready or start writing. Every migration from
+-- here on records its number.
+CREATE TABLE IF NOT EXISTS schema_version (
+    version INTEGER PRIMARY KEY,
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO schema_version (version) VALUES (18) ON CONFLICT DO NOTHING;
@@ -0,0 +1,18 @@
+-- Indexes for the API's hot query patterns, checked at startup by the server
+-- and the ingester, which warn when one is missing. They are built
+-- concurrently so that the migration can be applied to a live database; run
+-- it outside a transaction.
+
+-- Account balances, by chain and address or by address across chains, read
+-- from the index alone
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_balances_address_chain_denom
+    ON balances(address, chain_name, denom) INCLUDE (amount, height, updated_at);
+DROP INDEX CONCURRENTLY IF EXISTS idx_balances_address;
+
+-- Delegator listings per validator, in their shares then address order, and
+-- their count
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_delegations_chain_validator_shares_delegator
+    ON delegations(chain_name, validator_address, shares DESC, delegator_address) INCLUDE (height, updated_at);
+DROP INDEX CONCURRENTLY IF EXISTS idx_delegations_chain_validator_shares;
+
+INSERT INTO schema_version (version) VALUES (19) ON CONFLICT DO NOTHING;
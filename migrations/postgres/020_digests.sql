@@ -0,0 +1,22 @@
+-- Scheduled notification digests of a tenant: a daily or weekly summary of
+-- its watched addresses' changes, upcoming proposal deadlines and unbonding
+-- completions, delivered to one of its webhooks or an email address.
+-- last_sent_at is claimed by the API server sending a digest, so that only one
+-- replica sends it.
+CREATE TABLE digests (
+    id VARCHAR(32) NOT NULL,
+    tenant_id VARCHAR(32) NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(128) NOT NULL,
+    schedule VARCHAR(16) NOT NULL CHECK (schedule IN ('daily', 'weekly')),
+    webhook_id VARCHAR(32),
+    email VARCHAR(254),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, id),
+    UNIQUE (tenant_id, name),
+    FOREIGN KEY (tenant_id, webhook_id) REFERENCES webhooks(tenant_id, id) ON DELETE CASCADE,
+    CHECK (webhook_id IS NOT NULL OR email IS NOT NULL)
+);
+
+INSERT INTO schema_version (version) VALUES (20) ON CONFLICT DO NOTHING;
@@ -0,0 +1,18 @@
+-- Unbonding completion reminders sent to a tenant, one per unbonding entry of
+-- a watched delegator. A row is claimed by the API server sending the
+-- reminder, so that only one replica sends it, and removed again when no
+-- webhook received it.
+CREATE TABLE unbonding_reminders (
+    tenant_id VARCHAR(32) NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    chain_name VARCHAR(64) NOT NULL,
+    delegator_address VARCHAR(128) NOT NULL,
+    validator_address VARCHAR(128) NOT NULL,
+    creation_height BIGINT NOT NULL,
+    completion_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, chain_name, delegator_address, validator_address, creation_height, completion_time)
+);
+
+CREATE INDEX idx_unbonding_reminders_completion_time ON unbonding_reminders (completion_time);
+
+INSERT INTO schema_version (version) VALUES (21) ON CONFLICT DO NOTHING;
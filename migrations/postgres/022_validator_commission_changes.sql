@@ -0,0 +1,21 @@
+-- Commission rate changes detected by the staking module between validator
+-- snapshots. notified_at is claimed by the API server notifying tenants of an
+-- increase, so that only one replica notifies them.
+CREATE TABLE validator_commission_changes (
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    operator_address VARCHAR(128) NOT NULL,
+    moniker VARCHAR(256),
+    old_rate DECIMAL(20, 18) NOT NULL,
+    new_rate DECIMAL(20, 18) NOT NULL,
+    effective_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    height BIGINT NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    notified_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (chain_name, operator_address, height)
+);
+
+-- Increases waiting to be notified
+CREATE INDEX idx_validator_commission_changes_pending ON validator_commission_changes (detected_at)
+    WHERE notified_at IS NULL AND new_rate > old_rate;
+
+INSERT INTO schema_version (version) VALUES (22) ON CONFLICT DO NOTHING;
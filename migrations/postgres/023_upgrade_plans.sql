@@ -0,0 +1,13 @@
+-- The software upgrade each chain's upgrade module has scheduled, if any.
+-- Replaced on every poll; the row is removed once the plan is applied or
+-- cancelled.
+CREATE TABLE upgrade_plans (
+    chain_name VARCHAR(64) PRIMARY KEY REFERENCES chains(name) ON DELETE CASCADE,
+    name VARCHAR(256) NOT NULL,
+    upgrade_height BIGINT NOT NULL,
+    info TEXT NOT NULL DEFAULT '',
+    height BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+INSERT INTO schema_version (version) VALUES (23) ON CONFLICT DO NOTHING;
@@ -0,0 +1,27 @@
+-- The latest parameters of each chain's modules, as a JSON object of the
+-- parameters by name
+CREATE TABLE chain_params (
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    module VARCHAR(64) NOT NULL,
+    params JSONB NOT NULL,
+    height BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (chain_name, module)
+);
+
+-- Every parameter change detected between ingested snapshots. The first
+-- snapshot of a module records none.
+CREATE TABLE chain_param_changes (
+    id BIGSERIAL PRIMARY KEY,
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    module VARCHAR(64) NOT NULL,
+    param VARCHAR(128) NOT NULL,
+    old_value JSONB,
+    new_value JSONB,
+    height BIGINT NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_chain_param_changes_module ON chain_param_changes (chain_name, module, changed_at DESC);
+
+INSERT INTO schema_version (version) VALUES (24) ON CONFLICT DO NOTHING;
@@ -0,0 +1,17 @@
+-- Hours in which a chain metric deviated from its moving average, recorded
+-- once each by the anomaly detector
+CREATE TABLE anomalies (
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    metric VARCHAR(64) NOT NULL,
+    hour TIMESTAMP WITH TIME ZONE NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    expected DOUBLE PRECISION NOT NULL,
+    std_dev DOUBLE PRECISION NOT NULL,
+    z_score DOUBLE PRECISION NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (chain_name, metric, hour)
+);
+
+CREATE INDEX idx_anomalies_hour ON anomalies (chain_name, hour DESC);
+
+INSERT INTO schema_version (version) VALUES (25) ON CONFLICT DO NOTHING;
@@ -0,0 +1,69 @@
+-- Stable views over the state tables for external GraphQL engines (Hasura,
+-- PostGraphile) and BI tools, in their own api schema. Their names and
+-- columns are a public interface: later migrations may add columns but do not
+-- rename or drop them. Latest-state views hold one row per entity; history
+-- views one row per recorded change. GET /api/v1/views describes them and
+-- config/postgraphile.tags.json5 declares their primary keys.
+CREATE SCHEMA IF NOT EXISTS api;
+
+-- Latest state
+
+CREATE VIEW api.chains AS
+SELECT name, chain_id, status, latest_height, latest_time, updated_at
+FROM chains;
+
+CREATE VIEW api.accounts AS
+SELECT chain_name, address, account_number, sequence, first_seen_height, height, updated_at
+FROM accounts;
+
+CREATE VIEW api.balances AS
+SELECT chain_name, address, denom, amount, height, updated_at
+FROM balances;
+
+CREATE VIEW api.validators AS
+SELECT chain_name, operator_address, consensus_address, moniker, identity, website, security_contact, details,
+       commission_rate, commission_max_rate, commission_max_change_rate, min_self_delegation,
+       status, jailed, tokens, delegator_shares, unbonding_height, unbonding_time, height, updated_at
+FROM validators;
+
+CREATE VIEW api.delegations AS
+SELECT chain_name, delegator_address, validator_address, shares, amount, height, updated_at
+FROM delegations;
+
+CREATE VIEW api.unbonding_delegations AS
+SELECT chain_name, delegator_address, validator_address, creation_height, completion_time,
+       initial_balance, balance, height, updated_at
+FROM unbonding_delegations;
+
+CREATE VIEW api.proposals AS
+SELECT chain_name, proposal_id, content->>'title' AS title, status, submit_time, deposit_end_time,
+       voting_start_time, voting_end_time, content, final_tally_result, total_deposit, height, updated_at
+FROM proposals;
+
+CREATE VIEW api.supply AS
+SELECT chain_name, denom, amount, height, updated_at
+FROM supply;
+
+CREATE VIEW api.chain_params AS
+SELECT chain_name, module, params, height, updated_at
+FROM chain_params;
+
+-- History
+
+CREATE VIEW api.balance_history AS
+SELECT id, chain_name, address, denom, amount, height, recorded_at
+FROM balance_history;
+
+CREATE VIEW api.delegation_history AS
+SELECT id, chain_name, delegator_address, validator_address, shares, height, recorded_at
+FROM delegation_history;
+
+CREATE VIEW api.commission_changes AS
+SELECT chain_name, operator_address, moniker, old_rate, new_rate, effective_time, height, detected_at
+FROM validator_commission_changes;
+
+CREATE VIEW api.param_changes AS
+SELECT id, chain_name, module, param, old_value, new_value, height, changed_at
+FROM chain_param_changes;
+
+INSERT INTO schema_version (version) VALUES (26) ON CONFLICT DO NOTHING;
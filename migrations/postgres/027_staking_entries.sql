@@ -0,0 +1,11 @@
+-- Pending unbonding and redelegation entries are snapshotted by the staking
+-- module and read per delegator for account state
+CREATE INDEX idx_unbonding_delegations_chain_delegator ON unbonding_delegations(chain_name, delegator_address);
+CREATE INDEX idx_redelegations_chain_delegator ON redelegations(chain_name, delegator_address);
+
+CREATE VIEW api.redelegations AS
+SELECT chain_name, delegator_address, validator_src_address, validator_dst_address, creation_height,
+       completion_time, initial_balance, shares_dst, height, updated_at
+FROM redelegations;
+
+INSERT INTO schema_version (version) VALUES (27) ON CONFLICT DO NOTHING;
@@ -0,0 +1,13 @@
+-- Named portfolios of a tenant: groups of accounts across chains, listed as
+-- [{"chain_name": ..., "address": ...}], whose holdings are reported together
+CREATE TABLE portfolios (
+    id VARCHAR(32) NOT NULL,
+    tenant_id VARCHAR(32) NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(128) NOT NULL,
+    accounts JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, id),
+    UNIQUE (tenant_id, name)
+);
+
+INSERT INTO schema_version (version) VALUES (28) ON CONFLICT DO NOTHING;
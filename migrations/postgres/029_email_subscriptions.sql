@@ -0,0 +1,16 @@
+-- Email addresses of a tenant receiving the notifications of the events they
+-- subscribe to. The unsubscribe token, linked from every email, disables the
+-- subscription without an API key.
+CREATE TABLE email_subscriptions (
+    id VARCHAR(32) NOT NULL,
+    tenant_id VARCHAR(32) NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    email VARCHAR(320) NOT NULL,
+    events TEXT[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, id),
+    UNIQUE (tenant_id, email)
+);
+
+INSERT INTO schema_version (version) VALUES (29) ON CONFLICT DO NOTHING;
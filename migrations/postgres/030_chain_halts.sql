@@ -0,0 +1,18 @@
+-- The time of the last block of a chain whose height stopped increasing,
+-- NULL while it produces blocks
+ALTER TABLE chain_status ADD COLUMN halted_since TIMESTAMP WITH TIME ZONE;
+
+-- Periods in which a chain's height did not increase for longer than the halt
+-- threshold, recorded by the ingester when it detects and ends each halt
+CREATE TABLE chain_halts (
+    chain_name VARCHAR(64) NOT NULL REFERENCES chains(name) ON DELETE CASCADE,
+    height BIGINT NOT NULL,
+    since TIMESTAMP WITH TIME ZONE NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    resumed_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (chain_name, height)
+);
+
+CREATE INDEX idx_chain_halts_since ON chain_halts (chain_name, since DESC);
+
+INSERT INTO schema_version (version) VALUES (30) ON CONFLICT DO NOTHING;
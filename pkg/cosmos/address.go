@@ -0,0 +1,130 @@
+package cosmos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/address"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	vestingexported "github.com/cosmos/cosmos-sdk/x/auth/vesting/exported"
+	vestingtypes "github.com/cosmos/cosmos-sdk/x/auth/vesting/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// Bech32 suffixes appended to a chain's account prefix
+const (
+	ValidatorOperatorSuffix  = "valoper"
+	ValidatorConsensusSuffix = "valcons"
+)
+
+// ModuleAccounts are the SDK module accounts holding protocol funds rather than
+// user balances
+var ModuleAccounts = []string{
+	"fee_collector",
+	"distribution",
+	"bonded_tokens_pool",
+	"not_bonded_tokens_pool",
+	"gov",
+	"mint",
+}
+
+// interfaceRegistry resolves the public key types packed in validator
+// responses and the account types packed in auth responses
+var interfaceRegistry = func() codectypes.InterfaceRegistry {
+	registry := codectypes.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(registry)
+	authtypes.RegisterInterfaces(registry)
+	vestingtypes.RegisterInterfaces(registry)
+	return registry
+}()
+
+// ConsensusAddress derives the bech32 consensus address of a packed consensus
+// public key using the given account prefix
+func ConsensusAddress(pubkey *codectypes.Any, prefix string) (string, error) {
+	if pubkey == nil {
+		return "", fmt.Errorf("missing consensus pubkey")
+	}
+
+	var pk cryptotypes.PubKey
+	if err := interfaceRegistry.UnpackAny(pubkey, &pk); err != nil {
+		return "", fmt.Errorf("failed to unpack consensus pubkey: %w", err)
+	}
+
+	return bech32.ConvertAndEncode(prefix+ValidatorConsensusSuffix, pk.Address())
+}
+
+// ValidatorConsensusAddress derives a validator's consensus address, taking the
+// account prefix from its operator address
+func ValidatorConsensusAddress(val stakingtypes.Validator) (string, error) {
+	hrp, _, err := bech32.DecodeAndConvert(val.OperatorAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid operator address %q: %w", val.OperatorAddress, err)
+	}
+
+	return ConsensusAddress(val.ConsensusPubkey, strings.TrimSuffix(hrp, ValidatorOperatorSuffix))
+}
+
+// ModuleAddress derives the bech32 address of a module account using the given
+// account prefix
+func ModuleAddress(module, prefix string) (string, error) {
+	return bech32.ConvertAndEncode(prefix, address.Module(module))
+}
+
+// VestingAccount decodes a packed account and, when it is a vesting account,
+// returns the coins still locked at the given time less those it delegated
+func VestingAccount(packed *codectypes.Any, at time.Time) (VestingAccountInfo, bool, error) {
+	var account sdk.AccountI
+	if err := interfaceRegistry.UnpackAny(packed, &account); err != nil {
+		return VestingAccountInfo{}, false, fmt.Errorf("failed to unpack account: %w", err)
+	}
+
+	vesting, ok := account.(vestingexported.VestingAccount)
+	if !ok {
+		return VestingAccountInfo{}, false, nil
+	}
+
+	delegated := vesting.GetDelegatedVesting()
+	locked := sdk.NewCoins()
+	for _, coin := range vesting.GetVestingCoins(at) {
+		amount := coin.Amount.Sub(delegated.AmountOf(coin.Denom))
+		if amount.IsPositive() {
+			locked = locked.Add(sdk.NewCoin(coin.Denom, amount))
+		}
+	}
+
+	info := VestingAccountInfo{
+		Address: vestingAddress(vesting),
+		Type:    strings.TrimPrefix(packed.TypeUrl, "/cosmos.vesting.v1beta1."),
+		Locked:  locked,
+	}
+	if end := vesting.GetEndTime(); end > 0 {
+		info.EndTime = time.Unix(end, 0).UTC()
+	}
+	return info, true, nil
+}
+
+// vestingAddress returns the bech32 address stored in a vesting account, which
+// unlike GetAddress does not depend on the global SDK prefix
+func vestingAddress(account vestingexported.VestingAccount) string {
+	var base *vestingtypes.BaseVestingAccount
+	switch account := account.(type) {
+	case *vestingtypes.ContinuousVestingAccount:
+		base = account.BaseVestingAccount
+	case *vestingtypes.DelayedVestingAccount:
+		base = account.BaseVestingAccount
+	case *vestingtypes.PeriodicVestingAccount:
+		base = account.BaseVestingAccount
+	case *vestingtypes.PermanentLockedAccount:
+		base = account.BaseVestingAccount
+	}
+	if base == nil || base.BaseAccount == nil {
+		return account.GetAddress().String()
+	}
+	return base.BaseAccount.Address
+}
@@ -2,18 +2,30 @@ package cosmos
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"go.uber.org/zap"
 
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
 	query "github.com/cosmos/cosmos-sdk/types/query"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 )
 
@@ -22,42 +34,159 @@ type Client struct {
 	conn     *grpc.ClientConn
 	chainName string
 	logger   *zap.Logger
+	calls    atomic.Int64 // gRPC calls made
 	
 	// Module clients
+	authClient   authtypes.QueryClient
 	bankClient   banktypes.QueryClient
 	stakingClient stakingtypes.QueryClient
 	distrClient  distrtypes.QueryClient
 	govClient    govtypes.QueryClient
+	mintClient   minttypes.QueryClient
+	slashingClient slashingtypes.QueryClient
+	nodeClient   cmtservice.ServiceClient
+	txClient     txtypes.ServiceClient
+}
+
+// ChainClient is the chain query surface used by the ingester. It is implemented
+// by Client and by the synthetic client in pkg/cosmos/mock.
+type ChainClient interface {
+	ChainName() string
+	Ping(ctx context.Context) error
+	Close() error
+
+	GetNodeStatus(ctx context.Context) (*NodeStatus, error)
+	GetBlock(ctx context.Context, height int64) (*BlockInfo, error)
+	GetLastCommitSignatures(ctx context.Context, block *BlockInfo) ([]CommitSignature, error)
+	GetBlockTxs(ctx context.Context, height int64) ([]TxInfo, error)
+
+	GetModuleAccounts(ctx context.Context) ([]ModuleAccountInfo, error)
+	GetVestingAccounts(ctx context.Context, at time.Time) ([]VestingAccountInfo, error)
+	GetAllBalances(ctx context.Context, address string) ([]sdk.Coin, error)
+	GetAllSupply(ctx context.Context) ([]sdk.Coin, error)
+	GetCommunityPool(ctx context.Context) ([]sdk.DecCoin, error)
+	GetValidators(ctx context.Context, status string) ([]stakingtypes.Validator, error)
+	GetValidatorUnbondingDelegations(ctx context.Context, validatorAddr string) ([]stakingtypes.UnbondingDelegation, error)
+	GetValidatorRedelegations(ctx context.Context, srcValidatorAddr string) ([]stakingtypes.RedelegationResponse, error)
+	GetProposals(ctx context.Context, status govtypes.ProposalStatus) ([]govtypes.Proposal, error)
+	GetMintParams(ctx context.Context) (*minttypes.Params, error)
+	GetInflation(ctx context.Context) (string, error)
+	GetAnnualProvisions(ctx context.Context) (string, error)
+}
+
+var _ ChainClient = (*Client)(nil)
+
+// CallCounter is implemented by chain clients that count the gRPC calls they
+// make, for ingest run reports
+type CallCounter interface {
+	// GRPCCalls returns the number of gRPC calls made since the client was created
+	GRPCCalls() int64
+}
+
+var _ CallCounter = (*Client)(nil)
+
+// ModuleAccountInfo is a module account registered in the auth module
+type ModuleAccountInfo struct {
+	Name        string
+	Address     string
+	Permissions []string
+}
+
+// VestingAccountInfo is a vesting account with the coins still locked at a
+// time. Locked excludes vesting coins the account has delegated, which the
+// bonded pool already holds.
+type VestingAccountInfo struct {
+	Address string
+	Type    string
+	Locked  sdk.Coins
+	EndTime time.Time
+}
+
+// BlockInfo summarizes a block header. ProposerAddress is the bech32 consensus
+// address when the node returns SDK blocks, otherwise the hex address.
+type BlockInfo struct {
+	Height          int64
+	Hash            string
+	Time            time.Time
+	ProposerAddress string
+	TxCount         int
+
+	// lastCommit holds whether each validator of the previous height's set signed
+	lastCommit []bool
+}
+
+// CommitSignature records whether a validator signed a block
+type CommitSignature struct {
+	ConsensusAddress string
+	Signed           bool
+}
+
+// NodeStatus describes the sync state and software version of a chain node
+type NodeStatus struct {
+	LatestHeight    int64
+	LatestBlockTime time.Time
+	CatchingUp      bool
+	NodeVersion     string
+	AppVersion      string
 }
 
 // NewClient creates a new Cosmos SDK client
 func NewClient(chainName, grpcEndpoint string) (*Client, error) {
 	logger := zap.L().Named("cosmos-client").With(zap.String("chain", chainName))
 	
+	client := &Client{
+		chainName: chainName,
+		logger:    logger,
+	}
+
 	// Create gRPC connection
 	conn, err := grpc.Dial(grpcEndpoint, 
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(1024*1024*16)), // 16MB
+		grpc.WithUnaryInterceptor(client.countCall),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to gRPC endpoint %s: %w", grpcEndpoint, err)
 	}
 
-	client := &Client{
-		conn:      conn,
-		chainName: chainName,
-		logger:    logger,
-		
-		// Initialize module clients
-		bankClient:   banktypes.NewQueryClient(conn),
-		stakingClient: stakingtypes.NewQueryClient(conn),
-		distrClient:  distrtypes.NewQueryClient(conn),
-		govClient:    govtypes.NewQueryClient(conn),
-	}
+	client.conn = conn
+
+	// Initialize module clients
+	client.authClient = authtypes.NewQueryClient(conn)
+	client.bankClient = banktypes.NewQueryClient(conn)
+	client.stakingClient = stakingtypes.NewQueryClient(conn)
+	client.distrClient = distrtypes.NewQueryClient(conn)
+	client.govClient = govtypes.NewQueryClient(conn)
+	client.mintClient = minttypes.NewQueryClient(conn)
+	client.slashingClient = slashingtypes.NewQueryClient(conn)
+	client.nodeClient = cmtservice.NewServiceClient(conn)
+	client.txClient = txtypes.NewServiceClient(conn)
 
 	return client, nil
 }
 
+// countCall counts a unary gRPC call before invoking it
+func (c *Client) countCall(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	c.calls.Add(1)
+	if count, ok := ctx.Value(callCountKey{}).(*atomic.Int64); ok {
+		count.Add(1)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+type callCountKey struct{}
+
+// WithCallCount returns a context whose gRPC calls are also added to count, to
+// attribute calls to one of several callers sharing a client
+func WithCallCount(ctx context.Context, count *atomic.Int64) context.Context {
+	return context.WithValue(ctx, callCountKey{}, count)
+}
+
+// GRPCCalls returns the number of gRPC calls made since the client was created
+func (c *Client) GRPCCalls() int64 {
+	return c.calls.Load()
+}
+
 // Close closes the gRPC connection
 func (c *Client) Close() error {
 	return c.conn.Close()
@@ -68,6 +197,70 @@ func (c *Client) ChainName() string {
 	return c.chainName
 }
 
+// Auth module methods
+
+// GetModuleAccounts gets the module accounts of the chain
+func (c *Client) GetModuleAccounts(ctx context.Context) ([]ModuleAccountInfo, error) {
+	resp, err := c.authClient.ModuleAccounts(ctx, &authtypes.QueryModuleAccountsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get module accounts: %w", err)
+	}
+
+	accounts := make([]ModuleAccountInfo, 0, len(resp.Accounts))
+	for _, packed := range resp.Accounts {
+		var account authtypes.ModuleAccount
+		if err := account.Unmarshal(packed.Value); err != nil {
+			return nil, fmt.Errorf("failed to decode module account: %w", err)
+		}
+		if account.BaseAccount == nil {
+			continue
+		}
+		accounts = append(accounts, ModuleAccountInfo{
+			Name:        account.Name,
+			Address:     account.BaseAccount.Address,
+			Permissions: account.Permissions,
+		})
+	}
+
+	return accounts, nil
+}
+
+// GetVestingAccounts gets the vesting accounts of the chain with the coins
+// still locked at the given time. It pages through every account, so it is
+// expensive on chains with many accounts.
+func (c *Client) GetVestingAccounts(ctx context.Context, at time.Time) ([]VestingAccountInfo, error) {
+	var accounts []VestingAccountInfo
+	var nextKey []byte
+	for {
+		req := &authtypes.QueryAccountsRequest{
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		}
+
+		resp, err := c.authClient.Accounts(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get accounts: %w", err)
+		}
+
+		for _, packed := range resp.Accounts {
+			info, ok, err := VestingAccount(packed, at)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				accounts = append(accounts, info)
+			}
+		}
+
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			return accounts, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+}
+
 // Bank module methods
 
 // GetBalance gets the balance for a specific address and denom
@@ -222,6 +415,58 @@ func (c *Client) GetDelegatorUnbondingDelegations(ctx context.Context, delegator
 	return resp.UnbondingResponses, nil
 }
 
+// GetValidatorUnbondingDelegations gets all unbonding delegations from a validator
+func (c *Client) GetValidatorUnbondingDelegations(ctx context.Context, validatorAddr string) ([]stakingtypes.UnbondingDelegation, error) {
+	var unbondings []stakingtypes.UnbondingDelegation
+	var nextKey []byte
+	for {
+		req := &stakingtypes.QueryValidatorUnbondingDelegationsRequest{
+			ValidatorAddr: validatorAddr,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		}
+
+		resp, err := c.stakingClient.ValidatorUnbondingDelegations(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validator unbonding delegations: %w", err)
+		}
+
+		unbondings = append(unbondings, resp.UnbondingResponses...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			return unbondings, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+}
+
+// GetValidatorRedelegations gets all redelegations from a source validator
+func (c *Client) GetValidatorRedelegations(ctx context.Context, srcValidatorAddr string) ([]stakingtypes.RedelegationResponse, error) {
+	var redelegations []stakingtypes.RedelegationResponse
+	var nextKey []byte
+	for {
+		req := &stakingtypes.QueryRedelegationsRequest{
+			SrcValidatorAddr: srcValidatorAddr,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		}
+
+		resp, err := c.stakingClient.Redelegations(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validator redelegations: %w", err)
+		}
+
+		redelegations = append(redelegations, resp.RedelegationResponses...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			return redelegations, nil
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+}
+
 // Distribution module methods
 
 // GetDelegatorRewards gets rewards for a delegator
@@ -238,6 +483,16 @@ func (c *Client) GetDelegatorRewards(ctx context.Context, delegatorAddr string)
 	return resp.Rewards, nil
 }
 
+// GetCommunityPool gets the community pool
+func (c *Client) GetCommunityPool(ctx context.Context) ([]sdk.DecCoin, error) {
+	resp, err := c.distrClient.CommunityPool(ctx, &distrtypes.QueryCommunityPoolRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get community pool: %w", err)
+	}
+
+	return resp.Pool, nil
+}
+
 // GetValidatorCommission gets validator commission
 func (c *Client) GetValidatorCommission(ctx context.Context, validatorAddr string) ([]sdk.DecCoin, error) {
 	req := &distrtypes.QueryValidatorCommissionRequest{
@@ -319,6 +574,38 @@ func (c *Client) GetVotes(ctx context.Context, proposalID uint64) ([]govtypes.Vo
 	return votes, nil
 }
 
+// Mint module methods
+
+// GetMintParams gets the mint module parameters
+func (c *Client) GetMintParams(ctx context.Context) (*minttypes.Params, error) {
+	resp, err := c.mintClient.Params(ctx, &minttypes.QueryParamsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mint params: %w", err)
+	}
+
+	return &resp.Params, nil
+}
+
+// GetInflation gets the current inflation rate
+func (c *Client) GetInflation(ctx context.Context) (string, error) {
+	resp, err := c.mintClient.Inflation(ctx, &minttypes.QueryInflationRequest{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get inflation: %w", err)
+	}
+
+	return resp.Inflation.String(), nil
+}
+
+// GetAnnualProvisions gets the current annual provisions
+func (c *Client) GetAnnualProvisions(ctx context.Context) (string, error) {
+	resp, err := c.mintClient.AnnualProvisions(ctx, &minttypes.QueryAnnualProvisionsRequest{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get annual provisions: %w", err)
+	}
+
+	return resp.AnnualProvisions.String(), nil
+}
+
 // Health check methods
 
 // Ping tests the connection to the chain
@@ -333,23 +620,161 @@ func (c *Client) Ping(ctx context.Context) error {
 
 // GetLatestHeight gets the latest block height
 func (c *Client) GetLatestHeight(ctx context.Context) (int64, error) {
-	// Get a validator to determine latest height
-	validators, err := c.GetValidators(ctx, "")
+	height, _, err := c.getLatestBlock(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get latest height: %w", err)
 	}
-	
-	if len(validators) == 0 {
-		return 0, fmt.Errorf("no validators found")
+	return height, nil
+}
+
+// GetNodeStatus gets the latest block, sync state and version of the node
+func (c *Client) GetNodeStatus(ctx context.Context) (*NodeStatus, error) {
+	height, blockTime, err := c.getLatestBlock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
 	}
-	
-	// Return the unbonding height of the first validator as a proxy for latest height
-	// In a real implementation, you'd query the consensus module or use Tendermint RPC
-	return validators[0].UnbondingHeight, nil
+
+	syncing, err := c.nodeClient.GetSyncing(ctx, &cmtservice.GetSyncingRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get syncing status: %w", err)
+	}
+
+	info, err := c.nodeClient.GetNodeInfo(ctx, &cmtservice.GetNodeInfoRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node info: %w", err)
+	}
+
+	status := &NodeStatus{
+		LatestHeight:    height,
+		LatestBlockTime: blockTime,
+		CatchingUp:      syncing.Syncing,
+	}
+	if info.DefaultNodeInfo != nil {
+		status.NodeVersion = info.DefaultNodeInfo.Version
+	}
+	if info.ApplicationVersion != nil {
+		status.AppVersion = info.ApplicationVersion.Version
+	}
+
+	return status, nil
+}
+
+// GetBlock gets the header summary of the block at the given height
+func (c *Client) GetBlock(ctx context.Context, height int64) (*BlockInfo, error) {
+	resp, err := c.nodeClient.GetBlockByHeight(ctx, &cmtservice.GetBlockByHeightRequest{Height: height})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", height, err)
+	}
+
+	block := &BlockInfo{Height: height}
+	if resp.BlockId != nil {
+		block.Hash = strings.ToUpper(hex.EncodeToString(resp.BlockId.Hash))
+	}
+
+	var lastCommit *tmproto.Commit
+	switch {
+	case resp.SdkBlock != nil:
+		block.Time = resp.SdkBlock.Header.Time
+		block.ProposerAddress = resp.SdkBlock.Header.ProposerAddress
+		block.TxCount = len(resp.SdkBlock.Data.Txs)
+		lastCommit = resp.SdkBlock.LastCommit
+	case resp.Block != nil:
+		block.Time = resp.Block.Header.Time
+		block.ProposerAddress = strings.ToUpper(hex.EncodeToString(resp.Block.Header.ProposerAddress))
+		block.TxCount = len(resp.Block.Data.Txs)
+		lastCommit = resp.Block.LastCommit
+	default:
+		return nil, fmt.Errorf("empty block response for height %d", height)
+	}
+
+	if lastCommit != nil {
+		block.lastCommit = make([]bool, len(lastCommit.Signatures))
+		for i, sig := range lastCommit.Signatures {
+			block.lastCommit[i] = sig.BlockIdFlag != tmproto.BlockIDFlagAbsent
+		}
+	}
+
+	return block, nil
+}
+
+// GetLastCommitSignatures returns which validators signed the block preceding
+// the given block. Absent signatures carry no address, so addresses are taken
+// from the previous height's validator set, which has the same order.
+func (c *Client) GetLastCommitSignatures(ctx context.Context, block *BlockInfo) ([]CommitSignature, error) {
+	if len(block.lastCommit) == 0 || block.Height <= 1 {
+		return nil, nil
+	}
+
+	validators, err := c.GetValidatorSetAddresses(ctx, block.Height-1)
+	if err != nil {
+		return nil, err
+	}
+	if len(validators) != len(block.lastCommit) {
+		return nil, fmt.Errorf("validator set at height %d has %d validators, commit has %d signatures",
+			block.Height-1, len(validators), len(block.lastCommit))
+	}
+
+	signatures := make([]CommitSignature, len(validators))
+	for i, address := range validators {
+		signatures[i] = CommitSignature{ConsensusAddress: address, Signed: block.lastCommit[i]}
+	}
+	return signatures, nil
+}
+
+// GetValidatorSetAddresses returns the consensus addresses of the validator set
+// at the given height, in voting power order
+func (c *Client) GetValidatorSetAddresses(ctx context.Context, height int64) ([]string, error) {
+	const pageSize = 100
+
+	var addresses []string
+	for offset := uint64(0); ; offset += pageSize {
+		resp, err := c.nodeClient.GetValidatorSetByHeight(ctx, &cmtservice.GetValidatorSetByHeightRequest{
+			Height: height,
+			Pagination: &query.PageRequest{
+				Offset: offset,
+				Limit:  pageSize,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validator set at height %d: %w", height, err)
+		}
+
+		for _, validator := range resp.Validators {
+			addresses = append(addresses, validator.Address)
+		}
+		if len(resp.Validators) < pageSize {
+			return addresses, nil
+		}
+	}
+}
+
+// getLatestBlock returns the height and time of the latest block
+func (c *Client) getLatestBlock(ctx context.Context) (int64, time.Time, error) {
+	resp, err := c.nodeClient.GetLatestBlock(ctx, &cmtservice.GetLatestBlockRequest{})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	if resp.SdkBlock != nil {
+		return resp.SdkBlock.Header.Height, resp.SdkBlock.Header.Time, nil
+	}
+	if resp.Block != nil {
+		return resp.Block.Header.Height, resp.Block.Header.Time, nil
+	}
+	return 0, time.Time{}, fmt.Errorf("empty latest block response")
 }
 
 // Utility methods
 
+// WithHeight returns a context that makes module queries run against the state
+// at the given height instead of the latest committed state
+func WithHeight(ctx context.Context, height int64) context.Context {
+	if height <= 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+}
+
 // WaitForHeight waits for the chain to reach a specific height
 func (c *Client) WaitForHeight(ctx context.Context, targetHeight int64, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -0,0 +1,160 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// epochsServices are the epochs query services, Osmosis' and the Cosmos SDK
+// one derived from it, which share their messages
+var epochsServices = []string{
+	"/osmosis.epochs.v1beta1.Query/",
+	"/cosmos.epochs.v1beta1.Query/",
+}
+
+// EpochInfo is the current epoch of an epoch identifier. The chain processes
+// an epoch's state changes in the first block of the next one, at
+// CurrentEpochStartHeight.
+type EpochInfo struct {
+	Identifier              string
+	Duration                time.Duration
+	CurrentEpoch            int64
+	CurrentEpochStartTime   time.Time
+	CurrentEpochStartHeight int64
+}
+
+// EpochsClient queries the epochs module. Client implements it; chain clients
+// without the module do not.
+type EpochsClient interface {
+	GetEpochInfos(ctx context.Context) ([]EpochInfo, error)
+}
+
+var _ EpochsClient = (*Client)(nil)
+
+// GetEpochInfos gets the current epoch of every epoch identifier
+func (c *Client) GetEpochInfos(ctx context.Context) ([]EpochInfo, error) {
+	var resp []byte
+	var err error
+	for _, service := range epochsServices {
+		resp, err = c.invokeRaw(ctx, service+"EpochInfos", nil)
+		if status.Code(err) != codes.Unimplemented {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch infos: %w", err)
+	}
+
+	var epochs []EpochInfo
+	err = scanFields(resp, func(num protowire.Number, value []byte) error {
+		if num != 1 { // epochs
+			return nil
+		}
+		epoch, err := decodeEpochInfo(value)
+		epochs = append(epochs, epoch)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode epoch infos: %w", err)
+	}
+
+	return epochs, nil
+}
+
+// decodeEpochInfo decodes an EpochInfo message, whose numeric fields
+// scanFields skips
+func decodeEpochInfo(b []byte) (EpochInfo, error) {
+	var epoch EpochInfo
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return epoch, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch typ {
+		case protowire.BytesType:
+			value, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return epoch, protowire.ParseError(n)
+			}
+			b = b[n:]
+
+			switch num {
+			case 1:
+				epoch.Identifier = string(value)
+			case 3:
+				seconds, nanos, err := decodeSecondsNanos(value)
+				if err != nil {
+					return epoch, err
+				}
+				epoch.Duration = time.Duration(seconds)*time.Second + time.Duration(nanos)
+			case 5:
+				seconds, nanos, err := decodeSecondsNanos(value)
+				if err != nil {
+					return epoch, err
+				}
+				epoch.CurrentEpochStartTime = time.Unix(seconds, nanos).UTC()
+			}
+		case protowire.VarintType:
+			value, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return epoch, protowire.ParseError(n)
+			}
+			b = b[n:]
+
+			switch num {
+			case 4:
+				epoch.CurrentEpoch = int64(value)
+			case 8:
+				epoch.CurrentEpochStartHeight = int64(value)
+			}
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return epoch, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return epoch, nil
+}
+
+// decodeSecondsNanos decodes a google.protobuf.Timestamp or Duration
+func decodeSecondsNanos(b []byte) (int64, int64, error) {
+	var seconds, nanos int64
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return 0, 0, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if typ != protowire.VarintType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return 0, 0, protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+
+		value, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return 0, 0, protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch num {
+		case 1:
+			seconds = int64(value)
+		case 2:
+			nanos = int64(int32(value))
+		}
+	}
+	return seconds, nanos, nil
+}
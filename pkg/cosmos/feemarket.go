@@ -0,0 +1,121 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// GasPrice is the minimum price of a unit of gas in a denom. Denom is empty
+// when the fee market does not name it, as those pricing gas in the chain's
+// fee or EVM denom only.
+type GasPrice struct {
+	Denom  string
+	Amount string // decimal
+}
+
+// FeeMarketClient queries a fee market module's base fee. Client implements
+// it; chain clients without a fee market do not.
+type FeeMarketClient interface {
+	GetGasPrices(ctx context.Context) ([]GasPrice, error)
+}
+
+var _ FeeMarketClient = (*Client)(nil)
+
+// feeMarketQueries are the base fee queries of the fee market modules, tried
+// in order: Skip's x/feemarket, which prices gas in every accepted denom,
+// Osmosis' EIP-1559 fee market in txfees, then the EVM fee markets of Cosmos
+// EVM and Ethermint
+var feeMarketQueries = []struct {
+	method string
+	decode func(resp []byte) ([]GasPrice, error)
+}{
+	{"/feemarket.feemarket.v1.Query/GasPrices", decodeDecCoins},
+	{"/osmosis.txfees.v1beta1.Query/GetEipBaseFee", decodeBaseFee(18)},
+	{"/cosmos.evm.feemarket.v1.Query/BaseFee", decodeBaseFee(18)},
+	{"/ethermint.feemarket.v1.Query/BaseFee", decodeBaseFee(0)},
+}
+
+// GetGasPrices gets the current base fee of the chain's fee market
+func (c *Client) GetGasPrices(ctx context.Context) ([]GasPrice, error) {
+	for _, query := range feeMarketQueries {
+		resp, err := c.invokeRaw(ctx, query.method, nil)
+		if status.Code(err) == codes.Unimplemented {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get base fee: %w", err)
+		}
+
+		prices, err := query.decode(resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base fee: %w", err)
+		}
+		return prices, nil
+	}
+	return nil, fmt.Errorf("chain has no supported fee market module")
+}
+
+// decodeDecCoins decodes the DecCoins of field 1 of a response. Their amounts
+// are LegacyDecs, encoded as integers scaled by 10^18.
+func decodeDecCoins(resp []byte) ([]GasPrice, error) {
+	var prices []GasPrice
+	err := scanFields(resp, func(num protowire.Number, value []byte) error {
+		if num != 1 {
+			return nil
+		}
+		var price GasPrice
+		var amount string
+		err := scanFields(value, func(num protowire.Number, value []byte) error {
+			switch num {
+			case 1:
+				price.Denom = string(value)
+			case 2:
+				amount = string(value)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if price.Amount, err = scaledDecimal(amount, 18); err != nil {
+			return err
+		}
+		prices = append(prices, price)
+		return nil
+	})
+	return prices, err
+}
+
+// decodeBaseFee returns a decoder of a response with the base fee in field 1,
+// an integer scaled by 10^scale. A fee market without a base fee returns none.
+func decodeBaseFee(scale int32) func(resp []byte) ([]GasPrice, error) {
+	return func(resp []byte) ([]GasPrice, error) {
+		var prices []GasPrice
+		err := scanFields(resp, func(num protowire.Number, value []byte) error {
+			if num != 1 || len(value) == 0 {
+				return nil
+			}
+			amount, err := scaledDecimal(string(value), scale)
+			if err != nil {
+				return err
+			}
+			prices = append(prices, GasPrice{Amount: amount})
+			return nil
+		})
+		return prices, err
+	}
+}
+
+// scaledDecimal converts an integer scaled by 10^scale to a decimal string
+func scaledDecimal(value string, scale int32) (string, error) {
+	amount, err := decimal.NewFromString(value)
+	if err != nil || !amount.IsInteger() {
+		return "", fmt.Errorf("invalid scaled decimal %q", value)
+	}
+	return amount.Shift(-scale).String(), nil
+}
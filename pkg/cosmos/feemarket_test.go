@@ -0,0 +1,46 @@
+package cosmos
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// bytesField encodes a length-delimited field
+func bytesField(b []byte, num protowire.Number, value []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+func TestDecodeGasPrices(t *testing.T) {
+	coin := func(denom, amount string) []byte {
+		return bytesField(bytesField(nil, 1, []byte(denom)), 2, []byte(amount))
+	}
+	prices := bytesField(nil, 1, coin("uatom", "5000000000000000"))
+	prices = bytesField(prices, 1, coin("ibc/ABC", "1250000000000000000"))
+
+	got, err := decodeDecCoins(prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []GasPrice{{Denom: "uatom", Amount: "0.005"}, {Denom: "ibc/ABC", Amount: "1.25"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeDecCoins() = %+v, want %+v", got, want)
+	}
+
+	got, err = decodeBaseFee(0)(bytesField(nil, 1, []byte("875000000")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []GasPrice{{Amount: "875000000"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeBaseFee(0) = %+v, want %+v", got, want)
+	}
+
+	if got, err := decodeBaseFee(18)(nil); err != nil || got != nil {
+		t.Errorf("decodeBaseFee() without a base fee = %+v, %v", got, err)
+	}
+	if _, err := decodeBaseFee(18)(bytesField(nil, 1, []byte("0.5"))); err == nil {
+		t.Errorf("decodeBaseFee() accepted an unscaled decimal")
+	}
+}
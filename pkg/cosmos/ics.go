@@ -0,0 +1,204 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// providerService is the Interchain Security provider query service
+const providerService = "/interchain_security.ccv.provider.v1.Query/"
+
+// ConsumerChainInfo is a consumer chain registered on a provider chain
+type ConsumerChainInfo struct {
+	ChainID    string
+	ClientID   string
+	ConsumerID string // set from ICS v6, which keys consumers by ID instead of chain ID
+}
+
+// ConsumerValidatorKey is a provider validator in a consumer chain's set. The
+// consumer address is empty when no key was assigned for the consumer.
+type ConsumerValidatorKey struct {
+	ProviderAddress string
+	ConsumerAddress string
+}
+
+// ProviderClient queries the Interchain Security provider module. Client
+// implements it; chain clients without the module do not.
+type ProviderClient interface {
+	GetConsumerChains(ctx context.Context) ([]ConsumerChainInfo, error)
+	GetConsumerValidators(ctx context.Context, consumer ConsumerChainInfo) ([]ConsumerValidatorKey, error)
+}
+
+var _ ProviderClient = (*Client)(nil)
+
+// GetConsumerChains gets the consumer chains launched by the provider
+func (c *Client) GetConsumerChains(ctx context.Context) ([]ConsumerChainInfo, error) {
+	resp, err := c.invokeRaw(ctx, providerService+"QueryConsumerChains", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumer chains: %w", err)
+	}
+
+	var chains []ConsumerChainInfo
+	err = scanFields(resp, func(num protowire.Number, value []byte) error {
+		if num != 1 { // chains
+			return nil
+		}
+		var chain ConsumerChainInfo
+		err := scanFields(value, func(num protowire.Number, value []byte) error {
+			switch num {
+			case 1:
+				chain.ChainID = string(value)
+			case 2:
+				chain.ClientID = string(value)
+			case 13:
+				chain.ConsumerID = string(value)
+			}
+			return nil
+		})
+		chains = append(chains, chain)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode consumer chains: %w", err)
+	}
+
+	return chains, nil
+}
+
+// GetConsumerValidators gets the validator set of a consumer chain with the
+// consensus addresses of the keys assigned for it
+func (c *Client) GetConsumerValidators(ctx context.Context, consumer ConsumerChainInfo) ([]ConsumerValidatorKey, error) {
+	// ICS v6 takes the consumer ID where earlier versions take the chain ID,
+	// in the same field
+	id := consumer.ConsumerID
+	if id == "" {
+		id = consumer.ChainID
+	}
+	req := protowire.AppendTag(nil, 1, protowire.BytesType)
+	req = protowire.AppendString(req, id)
+
+	resp, err := c.invokeRaw(ctx, providerService+"QueryAllPairsValConsAddrByConsumer", req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumer key assignments of %s: %w", consumer.ChainID, err)
+	}
+	assigned := make(map[string]string)
+	err = scanFields(resp, func(num protowire.Number, value []byte) error {
+		if num != 1 { // pair_val_con_addr
+			return nil
+		}
+		var providerAddr, consumerAddr string
+		err := scanFields(value, func(num protowire.Number, value []byte) error {
+			switch num {
+			case 1:
+				providerAddr = string(value)
+			case 2:
+				consumerAddr = string(value)
+			}
+			return nil
+		})
+		assigned[providerAddr] = consumerAddr
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode consumer key assignments: %w", err)
+	}
+
+	resp, err = c.invokeRaw(ctx, providerService+"QueryConsumerValidators", req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumer validators of %s: %w", consumer.ChainID, err)
+	}
+	var validators []ConsumerValidatorKey
+	err = scanFields(resp, func(num protowire.Number, value []byte) error {
+		if num != 1 { // validators
+			return nil
+		}
+		var validator ConsumerValidatorKey
+		err := scanFields(value, func(num protowire.Number, value []byte) error {
+			if num == 1 {
+				validator.ProviderAddress = string(value)
+			}
+			return nil
+		})
+		validator.ConsumerAddress = assigned[validator.ProviderAddress]
+		validators = append(validators, validator)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode consumer validators: %w", err)
+	}
+
+	return validators, nil
+}
+
+// invokeRaw calls a query method with an encoded request and returns the
+// encoded response. Used for modules whose types are not linked into the
+// binary; messages are read field by field and only the stable fields used
+// here are decoded.
+func (c *Client) invokeRaw(ctx context.Context, method string, req []byte) ([]byte, error) {
+	var resp []byte
+	if err := c.conn.Invoke(ctx, method, req, &resp, grpc.ForceCodec(rawCodec{})); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// scanFields calls fn with the number and content of each length-delimited
+// field of an encoded message, skipping fields of other wire types
+func scanFields(b []byte, fn func(num protowire.Number, value []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(num, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawCodec passes already encoded messages through gRPC
+type rawCodec struct{}
+
+// Marshal returns the encoded request
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("raw codec cannot marshal %T", v)
+	}
+	return b, nil
+}
+
+// Unmarshal copies the encoded response
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("raw codec cannot unmarshal into %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// Name returns the content subtype, the same as the default proto codec's
+func (rawCodec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,617 @@
+package mock
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/state-mesh/pkg/cosmos"
+)
+
+// Config controls the synthetic chain simulated by Client
+type Config struct {
+	ChainName    string
+	Bech32Prefix string
+	Denom        string
+	Seed         int64
+
+	Validators int
+	Accounts   int
+	BlockTime  time.Duration
+
+	// Per-block churn emitted by Changes
+	BalanceChanges      int
+	DelegationChanges   int
+	RedelegationChanges int
+
+	// IBCTransfers is the number of packets per block sent over and received
+	// from a simulated IBC channel
+	IBCTransfers int
+
+	// EpochBlocks is the length in blocks of the simulated "day" epoch, 0 for
+	// a chain without epochs
+	EpochBlocks int
+
+	// MissRate is the probability that a validator misses signing a block
+	MissRate float64
+}
+
+// StateChange is a synthetic store write in the key format the state listener
+// understands: balances/{address}/{denom}, delegations/{delegator}/{validator}
+// and redelegations/{delegator}/{src validator}/{dst validator}
+type StateChange struct {
+	StoreKey string
+	Key      []byte
+	Value    []byte
+	Delete   bool
+	Height   int64
+}
+
+// validator is a simulated validator with a stable key pair
+type validator struct {
+	operator  string
+	consensus string
+	pubkey    *codectypes.Any
+	tokens    int64
+}
+
+// Client simulates a chain deterministically from a seed. Heights advance with
+// wall-clock time at the configured block time, and every query result is a
+// pure function of the seed and height, so runs with the same seed are repeatable.
+type Client struct {
+	cfg        Config
+	start      time.Time
+	validators []validator
+	accounts   []string
+}
+
+var (
+	_ cosmos.ChainClient     = (*Client)(nil)
+	_ cosmos.EpochsClient    = (*Client)(nil)
+	_ cosmos.FeeMarketClient = (*Client)(nil)
+)
+
+// NewClient creates a simulated chain client
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Bech32Prefix == "" {
+		cfg.Bech32Prefix = "cosmos"
+	}
+	if cfg.Denom == "" {
+		cfg.Denom = "stake"
+	}
+	if cfg.Validators <= 0 {
+		cfg.Validators = 100
+	}
+	if cfg.Accounts <= 0 {
+		cfg.Accounts = 10000
+	}
+	if cfg.BlockTime <= 0 {
+		cfg.BlockTime = time.Second
+	}
+
+	c := &Client{
+		cfg:   cfg,
+		start: time.Now(),
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	for i := 0; i < cfg.Validators; i++ {
+		pk := ed25519.GenPrivKeyFromSecret([]byte(fmt.Sprintf("%s/%d/validator/%d", cfg.ChainName, cfg.Seed, i))).PubKey()
+		pubkey, err := codectypes.NewAnyWithValue(pk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack validator pubkey: %w", err)
+		}
+
+		operator, err := c.address(cfg.Bech32Prefix+cosmos.ValidatorOperatorSuffix, "operator", i)
+		if err != nil {
+			return nil, err
+		}
+		consensus, err := bech32.ConvertAndEncode(cfg.Bech32Prefix+cosmos.ValidatorConsensusSuffix, pk.Address())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode consensus address: %w", err)
+		}
+
+		c.validators = append(c.validators, validator{
+			operator:  operator,
+			consensus: consensus,
+			pubkey:    pubkey,
+			tokens:    rng.Int63n(1_000_000_000_000) + 1_000_000,
+		})
+	}
+
+	for i := 0; i < cfg.Accounts; i++ {
+		address, err := c.address(cfg.Bech32Prefix, "account", i)
+		if err != nil {
+			return nil, err
+		}
+		c.accounts = append(c.accounts, address)
+	}
+
+	return c, nil
+}
+
+// address derives a deterministic bech32 address for the i-th entity of a kind
+func (c *Client) address(hrp, kind string, i int) (string, error) {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d/%s/%d", c.cfg.ChainName, c.cfg.Seed, kind, i)))
+	address, err := bech32.ConvertAndEncode(hrp, sum[:20])
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s address: %w", kind, err)
+	}
+	return address, nil
+}
+
+// rng returns the random source for a height
+func (c *Client) rng(height int64) *rand.Rand {
+	return rand.New(rand.NewSource(c.cfg.Seed*1_000_003 + height))
+}
+
+// height returns the simulated height at the given time
+func (c *Client) height(now time.Time) int64 {
+	return 1 + int64(now.Sub(c.start)/c.cfg.BlockTime)
+}
+
+// blockTime returns the simulated time of a height
+func (c *Client) blockTime(height int64) time.Time {
+	return c.start.Add(time.Duration(height-1) * c.cfg.BlockTime)
+}
+
+// tokens returns a validator's bonded tokens at a height, drifting by up to 1%
+// per block around its initial stake
+func (c *Client) tokens(i int, height int64) sdkmath.Int {
+	base := c.validators[i].tokens
+	drift := c.rng(height*int64(len(c.validators))+int64(i)).Int63n(base/50+1) - base/100
+	return sdkmath.NewInt(base + drift)
+}
+
+// ChainName returns the chain name
+func (c *Client) ChainName() string {
+	return c.cfg.ChainName
+}
+
+// Ping always succeeds
+func (c *Client) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close releases nothing
+func (c *Client) Close() error {
+	return nil
+}
+
+// LatestHeight returns the current simulated height
+func (c *Client) LatestHeight() int64 {
+	return c.height(time.Now())
+}
+
+// GetNodeStatus returns the current simulated height
+func (c *Client) GetNodeStatus(ctx context.Context) (*cosmos.NodeStatus, error) {
+	height := c.LatestHeight()
+	return &cosmos.NodeStatus{
+		LatestHeight:    height,
+		LatestBlockTime: c.blockTime(height),
+		NodeVersion:     "mock",
+		AppVersion:      "mock",
+	}, nil
+}
+
+// GetBlock returns the simulated header of a height, proposed round-robin
+func (c *Client) GetBlock(ctx context.Context, height int64) (*cosmos.BlockInfo, error) {
+	if height < 1 || height > c.LatestHeight() {
+		return nil, fmt.Errorf("failed to get block %d: height not available", height)
+	}
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%d/block/%d", c.cfg.ChainName, c.cfg.Seed, height)))
+	return &cosmos.BlockInfo{
+		Height:          height,
+		Hash:            strings.ToUpper(hex.EncodeToString(hash[:])),
+		Time:            c.blockTime(height),
+		ProposerAddress: c.validators[int(height%int64(len(c.validators)))].consensus,
+		TxCount:         c.txCount(),
+	}, nil
+}
+
+// Simulated IBC channel: packets are sent on transferChannel and received on
+// it from counterpartyChannel, acknowledged packetAckDelay blocks after they
+// are sent except every stuckPacketEvery-th, which is never relayed
+const (
+	transferPort        = "transfer"
+	transferChannel     = "channel-0"
+	counterpartyChannel = "channel-141"
+	packetAckDelay      = 3
+	stuckPacketEvery    = 50
+	relayers            = 3
+)
+
+// txCount returns the transaction count simulated blocks report, which counts
+// an acknowledgement for every packet including the stuck ones
+func (c *Client) txCount() int {
+	return c.cfg.BalanceChanges + c.cfg.DelegationChanges + 3*c.cfg.IBCTransfers
+}
+
+// GetBlockTxs returns a block's simulated transactions: a bank send per
+// balance change and a delegation per delegation change, paid for by random
+// accounts, of which about one in fifty fails, and the IBC transfers, packet
+// receipts and acknowledgements of the simulated channel
+func (c *Client) GetBlockTxs(ctx context.Context, height int64) ([]cosmos.TxInfo, error) {
+	if height < 1 || height > c.LatestHeight() {
+		return nil, fmt.Errorf("failed to get transactions of block %d: height not available", height)
+	}
+
+	rng := c.rng(-height - 1_000_000_007)
+	txs := make([]cosmos.TxInfo, 0, c.txCount())
+	tx := func(msgType string, gasWanted int64, payer string, packet *cosmos.PacketEvent) {
+		gasUsed := gasWanted/2 + rng.Int63n(gasWanted/2)
+
+		var code uint32
+		if packet == nil && rng.Intn(50) == 0 {
+			code = 5
+		}
+
+		hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%d/tx/%d/%d", c.cfg.ChainName, c.cfg.Seed, height, len(txs))))
+		info := cosmos.TxInfo{
+			Hash:         strings.ToUpper(hex.EncodeToString(hash[:])),
+			Code:         code,
+			GasWanted:    gasWanted,
+			GasUsed:      gasUsed,
+			Fee:          sdk.NewCoins(sdk.NewCoin(c.cfg.Denom, sdkmath.NewInt(gasWanted/40+rng.Int63n(gasWanted/40)))),
+			FeePayer:     payer,
+			MessageTypes: []string{msgType},
+		}
+		if packet != nil {
+			info.Packets = []cosmos.PacketEvent{*packet}
+		}
+		txs = append(txs, info)
+	}
+
+	for i := 0; i < c.cfg.BalanceChanges; i++ {
+		tx("cosmos.bank.v1beta1.MsgSend", 100_000, c.accounts[rng.Intn(len(c.accounts))], nil)
+	}
+	for i := 0; i < c.cfg.DelegationChanges; i++ {
+		tx("cosmos.staking.v1beta1.MsgDelegate", 250_000, c.accounts[rng.Intn(len(c.accounts))], nil)
+	}
+
+	n := int64(c.cfg.IBCTransfers)
+	for i := int64(0); i < n; i++ {
+		outbound := cosmos.PacketEvent{
+			Type:       cosmos.PacketSend,
+			Sequence:   uint64(height*n + i),
+			SrcPort:    transferPort,
+			SrcChannel: transferChannel,
+			DstPort:    transferPort,
+			DstChannel: counterpartyChannel,
+		}
+		tx("ibc.applications.transfer.v1.MsgTransfer", 150_000, c.accounts[rng.Intn(len(c.accounts))], &outbound)
+
+		inbound := cosmos.PacketEvent{
+			Type:       cosmos.PacketRecv,
+			Sequence:   uint64(height*n + i),
+			SrcPort:    transferPort,
+			SrcChannel: counterpartyChannel,
+			DstPort:    transferPort,
+			DstChannel: transferChannel,
+		}
+		tx("ibc.core.channel.v1.MsgRecvPacket", 300_000, c.relayer(rng), &inbound)
+
+		acked := outbound
+		acked.Type = cosmos.PacketAcknowledge
+		acked.Sequence = uint64((height-packetAckDelay)*n + i)
+		if height > packetAckDelay && acked.Sequence%stuckPacketEvery != 0 {
+			tx("ibc.core.channel.v1.MsgAcknowledgement", 200_000, c.relayer(rng), &acked)
+		}
+	}
+
+	return txs, nil
+}
+
+// relayer returns one of the simulated relayer accounts
+func (c *Client) relayer(rng *rand.Rand) string {
+	return c.accounts[len(c.accounts)-1-rng.Intn(relayers)]
+}
+
+// GetLastCommitSignatures returns which validators signed the block preceding
+// the given block, missing at the configured rate
+func (c *Client) GetLastCommitSignatures(ctx context.Context, block *cosmos.BlockInfo) ([]cosmos.CommitSignature, error) {
+	if block.Height <= 1 {
+		return nil, nil
+	}
+
+	rng := c.rng(-block.Height)
+	signatures := make([]cosmos.CommitSignature, len(c.validators))
+	for i, val := range c.validators {
+		signatures[i] = cosmos.CommitSignature{
+			ConsensusAddress: val.consensus,
+			Signed:           rng.Float64() >= c.cfg.MissRate,
+		}
+	}
+	return signatures, nil
+}
+
+// GetEpochInfos returns the current "day" epoch, which starts every
+// EpochBlocks blocks from height 1
+func (c *Client) GetEpochInfos(ctx context.Context) ([]cosmos.EpochInfo, error) {
+	if c.cfg.EpochBlocks <= 0 {
+		return nil, nil
+	}
+
+	epochBlocks := int64(c.cfg.EpochBlocks)
+	current := (c.LatestHeight()-1)/epochBlocks + 1
+	start := (current-1)*epochBlocks + 1
+	return []cosmos.EpochInfo{{
+		Identifier:              "day",
+		Duration:                time.Duration(epochBlocks) * c.cfg.BlockTime,
+		CurrentEpoch:            current,
+		CurrentEpochStartTime:   c.blockTime(start),
+		CurrentEpochStartHeight: start,
+	}}, nil
+}
+
+// GetGasPrices returns a base fee in the simulated denom drifting between
+// 0.0025 and 0.0075 per unit of gas from block to block
+func (c *Client) GetGasPrices(ctx context.Context) ([]cosmos.GasPrice, error) {
+	drift := c.rng(-c.LatestHeight() - 2_000_000_014).Int63n(5000)
+	return []cosmos.GasPrice{{
+		Denom:  c.cfg.Denom,
+		Amount: sdkmath.LegacyNewDecWithPrec(2500+drift, 6).String(),
+	}}, nil
+}
+
+// GetModuleAccounts returns the standard SDK module accounts
+func (c *Client) GetModuleAccounts(ctx context.Context) ([]cosmos.ModuleAccountInfo, error) {
+	accounts := make([]cosmos.ModuleAccountInfo, len(cosmos.ModuleAccounts))
+	for i, name := range cosmos.ModuleAccounts {
+		address, err := cosmos.ModuleAddress(name, c.cfg.Bech32Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode module address: %w", err)
+		}
+		accounts[i] = cosmos.ModuleAccountInfo{Name: name, Address: address}
+	}
+	return accounts, nil
+}
+
+// vestingPeriod is how long the simulated vesting accounts take to fully vest
+const vestingPeriod = 365 * 24 * time.Hour
+
+// GetVestingAccounts returns every hundredth simulated account as continuously
+// vesting its whole balance over a year from the start of the simulation
+func (c *Client) GetVestingAccounts(ctx context.Context, at time.Time) ([]cosmos.VestingAccountInfo, error) {
+	end := c.start.Add(vestingPeriod)
+	remaining := end.Sub(at)
+	if remaining > vestingPeriod {
+		remaining = vestingPeriod
+	}
+
+	var accounts []cosmos.VestingAccountInfo
+	for i := 0; i < len(c.accounts); i += 100 {
+		locked := sdk.NewCoins()
+		if remaining > 0 {
+			amount := sdkmath.NewInt(1_000_000_000).MulRaw(int64(remaining / time.Second)).QuoRaw(int64(vestingPeriod / time.Second))
+			locked = sdk.NewCoins(sdk.NewCoin(c.cfg.Denom, amount))
+		}
+		accounts = append(accounts, cosmos.VestingAccountInfo{
+			Address: c.accounts[i],
+			Type:    "ContinuousVestingAccount",
+			Locked:  locked,
+			EndTime: end,
+		})
+	}
+	return accounts, nil
+}
+
+// GetAllBalances returns the balance of a module account, or a fixed liquid
+// balance for any other address
+func (c *Client) GetAllBalances(ctx context.Context, address string) ([]sdk.Coin, error) {
+	height := c.LatestHeight()
+	for _, name := range cosmos.ModuleAccounts {
+		if module, _ := cosmos.ModuleAddress(name, c.cfg.Bech32Prefix); module == address {
+			return []sdk.Coin{sdk.NewCoin(c.cfg.Denom, c.moduleBalance(name, height))}, nil
+		}
+	}
+	return []sdk.Coin{sdk.NewCoin(c.cfg.Denom, sdkmath.NewInt(1_000_000_000))}, nil
+}
+
+// GetAllSupply returns the liquid balances of the simulated accounts plus the
+// balances of the module accounts, which hold the bonded tokens
+func (c *Client) GetAllSupply(ctx context.Context) ([]sdk.Coin, error) {
+	height := c.LatestHeight()
+	total := sdkmath.NewInt(int64(len(c.accounts))).MulRaw(1_000_000_000)
+	for _, name := range cosmos.ModuleAccounts {
+		total = total.Add(c.moduleBalance(name, height))
+	}
+	return []sdk.Coin{sdk.NewCoin(c.cfg.Denom, total)}, nil
+}
+
+// GetCommunityPool returns a community pool of 1% of the liquid supply
+func (c *Client) GetCommunityPool(ctx context.Context) ([]sdk.DecCoin, error) {
+	return []sdk.DecCoin{sdk.NewDecCoin(c.cfg.Denom, c.communityPool())}, nil
+}
+
+// communityPool returns the tokens in the community pool
+func (c *Client) communityPool() sdkmath.Int {
+	return sdkmath.NewInt(int64(len(c.accounts))).MulRaw(10_000_000)
+}
+
+// moduleBalance returns the balance of a module account at a height. The
+// bonded pool holds the validators' tokens and the distribution account the
+// community pool plus unclaimed rewards.
+func (c *Client) moduleBalance(name string, height int64) sdkmath.Int {
+	switch name {
+	case "bonded_tokens_pool":
+		total := sdkmath.ZeroInt()
+		for i := range c.validators {
+			total = total.Add(c.tokens(i, height))
+		}
+		return total
+	case "not_bonded_tokens_pool":
+		return sdkmath.NewInt(int64(len(c.validators))).MulRaw(1_000_000_000)
+	case "distribution":
+		return c.communityPool().Add(sdkmath.NewInt(int64(len(c.accounts))).MulRaw(1_000_000))
+	case "fee_collector":
+		return sdkmath.NewInt(1_000_000)
+	default:
+		return sdkmath.ZeroInt()
+	}
+}
+
+// GetValidators returns every simulated validator as bonded with its tokens at
+// the current height. The status filter is ignored.
+func (c *Client) GetValidators(ctx context.Context, status string) ([]stakingtypes.Validator, error) {
+	height := c.LatestHeight()
+	validators := make([]stakingtypes.Validator, len(c.validators))
+	for i, val := range c.validators {
+		tokens := c.tokens(i, height)
+		validators[i] = stakingtypes.Validator{
+			OperatorAddress: val.operator,
+			ConsensusPubkey: val.pubkey,
+			Status:          stakingtypes.Bonded,
+			Tokens:          tokens,
+			DelegatorShares: sdkmath.LegacyNewDecFromInt(tokens),
+			Description:     stakingtypes.NewDescription(fmt.Sprintf("%s validator %d", c.cfg.ChainName, i+1), "", "", "", ""),
+			Commission: stakingtypes.NewCommission(
+				sdkmath.LegacyNewDecWithPrec(5, 2),
+				sdkmath.LegacyNewDecWithPrec(20, 2),
+				sdkmath.LegacyNewDecWithPrec(1, 2),
+			),
+			MinSelfDelegation: sdkmath.OneInt(),
+		}
+	}
+	return validators, nil
+}
+
+// GetValidatorUnbondingDelegations returns one unbonding entry per validator
+// that completes 21 days after the current block
+func (c *Client) GetValidatorUnbondingDelegations(ctx context.Context, validatorAddr string) ([]stakingtypes.UnbondingDelegation, error) {
+	height := c.LatestHeight()
+	rng := c.rng(height)
+	amount := sdkmath.NewInt(rng.Int63n(1_000_000_000) + 1)
+
+	return []stakingtypes.UnbondingDelegation{{
+		DelegatorAddress: c.accounts[rng.Intn(len(c.accounts))],
+		ValidatorAddress: validatorAddr,
+		Entries: []stakingtypes.UnbondingDelegationEntry{{
+			CreationHeight: height,
+			CompletionTime: c.blockTime(height).Add(21 * 24 * time.Hour),
+			InitialBalance: amount,
+			Balance:        amount,
+		}},
+	}}, nil
+}
+
+// GetValidatorRedelegations returns one redelegation per validator to the
+// next validator, whose entry completes 21 days after the current block
+func (c *Client) GetValidatorRedelegations(ctx context.Context, srcValidatorAddr string) ([]stakingtypes.RedelegationResponse, error) {
+	if len(c.validators) < 2 {
+		return nil, nil
+	}
+	dst := c.validators[0].operator
+	for i, val := range c.validators {
+		if val.operator == srcValidatorAddr {
+			dst = c.validators[(i+1)%len(c.validators)].operator
+		}
+	}
+
+	height := c.LatestHeight()
+	rng := c.rng(height)
+	amount := sdkmath.NewInt(rng.Int63n(1_000_000_000) + 1)
+	entry := stakingtypes.RedelegationEntry{
+		CreationHeight: height,
+		CompletionTime: c.blockTime(height).Add(21 * 24 * time.Hour),
+		InitialBalance: amount,
+		SharesDst:      sdkmath.LegacyNewDecFromInt(amount),
+	}
+
+	return []stakingtypes.RedelegationResponse{{
+		Redelegation: stakingtypes.Redelegation{
+			DelegatorAddress:    c.accounts[rng.Intn(len(c.accounts))],
+			ValidatorSrcAddress: srcValidatorAddr,
+			ValidatorDstAddress: dst,
+			Entries:             []stakingtypes.RedelegationEntry{entry},
+		},
+		Entries: []stakingtypes.RedelegationEntryResponse{{RedelegationEntry: entry, Balance: amount}},
+	}}, nil
+}
+
+// GetProposals returns no proposals
+func (c *Client) GetProposals(ctx context.Context, status govtypes.ProposalStatus) ([]govtypes.Proposal, error) {
+	return nil, nil
+}
+
+// GetMintParams returns the default mint parameters for the simulated denom
+func (c *Client) GetMintParams(ctx context.Context) (*minttypes.Params, error) {
+	params := minttypes.DefaultParams()
+	params.MintDenom = c.cfg.Denom
+	return &params, nil
+}
+
+// GetInflation returns a fixed 10% inflation
+func (c *Client) GetInflation(ctx context.Context) (string, error) {
+	return sdkmath.LegacyNewDecWithPrec(1, 1).String(), nil
+}
+
+// GetAnnualProvisions returns 10% of the current supply
+func (c *Client) GetAnnualProvisions(ctx context.Context) (string, error) {
+	supply, err := c.GetAllSupply(ctx)
+	if err != nil {
+		return "", err
+	}
+	return sdkmath.LegacyNewDecFromInt(supply[0].Amount).MulInt64(10).QuoInt64(100).String(), nil
+}
+
+// Changes returns the synthetic balance, delegation and redelegation writes of
+// a height
+func (c *Client) Changes(height int64) []StateChange {
+	rng := c.rng(height)
+	changes := make([]StateChange, 0, c.cfg.BalanceChanges+c.cfg.DelegationChanges+c.cfg.RedelegationChanges)
+
+	for i := 0; i < c.cfg.BalanceChanges; i++ {
+		address := c.accounts[rng.Intn(len(c.accounts))]
+		changes = append(changes, StateChange{
+			StoreKey: "bank",
+			Key:      []byte("balances/" + address + "/" + c.cfg.Denom),
+			Value:    []byte(fmt.Sprint(rng.Int63n(1_000_000_000_000))),
+			Height:   height,
+		})
+	}
+
+	for i := 0; i < c.cfg.DelegationChanges; i++ {
+		delegator := c.accounts[rng.Intn(len(c.accounts))]
+		val := c.validators[rng.Intn(len(c.validators))]
+		change := StateChange{
+			StoreKey: "staking",
+			Key:      []byte("delegations/" + delegator + "/" + val.operator),
+			Height:   height,
+		}
+		if rng.Intn(10) == 0 {
+			change.Delete = true
+		} else {
+			change.Value = []byte(sdkmath.LegacyNewDec(rng.Int63n(1_000_000_000) + 1).String())
+		}
+		changes = append(changes, change)
+	}
+
+	for i := 0; i < c.cfg.RedelegationChanges && len(c.validators) > 1; i++ {
+		delegator := c.accounts[rng.Intn(len(c.accounts))]
+		src := rng.Intn(len(c.validators))
+		dst := (src + 1 + rng.Intn(len(c.validators)-1)) % len(c.validators)
+		changes = append(changes, StateChange{
+			StoreKey: "staking",
+			Key:      []byte("redelegations/" + delegator + "/" + c.validators[src].operator + "/" + c.validators[dst].operator),
+			Value:    []byte(fmt.Sprint(rng.Int63n(1_000_000_000) + 1)),
+			Height:   height,
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,247 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	basev1beta1 "cosmossdk.io/api/cosmos/base/v1beta1"
+	distrv1beta1 "cosmossdk.io/api/cosmos/distribution/v1beta1"
+	govv1 "cosmossdk.io/api/cosmos/gov/v1"
+	govv1beta1 "cosmossdk.io/api/cosmos/gov/v1beta1"
+	paramsv1beta1 "cosmossdk.io/api/cosmos/params/v1beta1"
+	protocolpoolv1 "cosmossdk.io/api/cosmos/protocolpool/v1"
+	upgradev1beta1 "cosmossdk.io/api/cosmos/upgrade/v1beta1"
+	"github.com/cosmos/cosmos-sdk/codec"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	gogoproto "github.com/cosmos/gogoproto/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	// Registered so that their MsgUpdateParams decode
+	_ "cosmossdk.io/api/cosmos/auth/v1beta1"
+	_ "cosmossdk.io/api/cosmos/bank/v1beta1"
+	_ "cosmossdk.io/api/cosmos/consensus/v1"
+	_ "cosmossdk.io/api/cosmos/crisis/v1beta1"
+	_ "cosmossdk.io/api/cosmos/mint/v1beta1"
+	_ "cosmossdk.io/api/cosmos/slashing/v1beta1"
+	_ "cosmossdk.io/api/cosmos/staking/v1beta1"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// Proposal message type URLs decoded specially. The IBC client messages are
+// decoded by hand as ibc-go is not a dependency.
+const (
+	msgExecLegacyContentURL = "/cosmos.gov.v1.MsgExecLegacyContent"
+	msgRecoverClientURL     = "/ibc.core.client.v1.MsgRecoverClient"
+	clientUpdateProposalURL = "/ibc.core.client.v1.ClientUpdateProposal"
+)
+
+// jsonOptions encode messages whose SDK types are not linked with their
+// proto field names and unset fields, close to the chains' REST endpoints
+var jsonOptions = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// legacyContent is implemented by the gov v1beta1 proposal contents
+type legacyContent interface {
+	GetTitle() string
+	GetDescription() string
+}
+
+// DecodeProposalContent decodes the messages of a governance proposal. Each
+// message is rendered as JSON when its type is known, and parameter changes,
+// software upgrades, community pool spends and IBC client updates are
+// extracted into typed fields. Legacy content executed by MsgExecLegacyContent
+// is listed as the content itself. Messages that fail to decode are listed
+// with their type only.
+func DecodeProposalContent(proposal *govtypes.Proposal) types.ProposalContent {
+	content := types.ProposalContent{
+		Title:       proposal.Title,
+		Description: proposal.Summary,
+		Metadata:    proposal.Metadata,
+	}
+
+	for _, msg := range proposal.Messages {
+		if msg == nil {
+			continue
+		}
+		typeURL, value := msg.TypeUrl, msg.Value
+		if typeURL == msgExecLegacyContentURL {
+			var exec govv1.MsgExecLegacyContent
+			if err := proto.Unmarshal(value, &exec); err == nil && exec.Content != nil {
+				typeURL, value = exec.Content.TypeUrl, exec.Content.Value
+			}
+		}
+		content.Messages = append(content.Messages, decodeProposalMessage(&content, typeURL, value))
+	}
+
+	if len(content.Messages) == 1 {
+		content.Type = content.Messages[0].Type
+	}
+	return content
+}
+
+// decodeProposalMessage decodes one proposal message, adding what it changes
+// to the content
+func decodeProposalMessage(content *types.ProposalContent, typeURL string, value []byte) types.ProposalMessage {
+	message := types.ProposalMessage{Type: typeURL}
+
+	switch typeURL {
+	case msgRecoverClientURL, clientUpdateProposalURL:
+		update, err := decodeClientUpdate(typeURL, value)
+		if err != nil {
+			return message
+		}
+		content.ClientUpdates = append(content.ClientUpdates, update)
+		message.Value, _ = json.Marshal(update)
+		return message
+	}
+
+	msg, err := anypb.UnmarshalNew(&anypb.Any{TypeUrl: typeURL, Value: value}, proto.UnmarshalOptions{})
+	if err != nil {
+		return message
+	}
+	message.Value = messageJSON(typeURL, value, msg)
+
+	if legacy, ok := msg.(legacyContent); ok {
+		if content.Title == "" {
+			content.Title = legacy.GetTitle()
+		}
+		if content.Description == "" {
+			content.Description = legacy.GetDescription()
+		}
+	}
+
+	switch m := msg.(type) {
+	case *paramsv1beta1.ParameterChangeProposal:
+		for _, change := range m.Changes {
+			content.ParamChanges = append(content.ParamChanges, types.ParamChange{
+				Subspace: change.Subspace,
+				Key:      change.Key,
+				Value:    change.Value,
+			})
+		}
+	case *upgradev1beta1.MsgSoftwareUpgrade:
+		content.SoftwareUpgrade = softwareUpgrade(m.Plan)
+	case *upgradev1beta1.SoftwareUpgradeProposal:
+		content.SoftwareUpgrade = softwareUpgrade(m.Plan)
+	case *distrv1beta1.MsgCommunityPoolSpend:
+		content.CommunityPoolSpends = append(content.CommunityPoolSpends, communityPoolSpend(m.Recipient, m.Amount))
+	case *distrv1beta1.CommunityPoolSpendProposal:
+		content.CommunityPoolSpends = append(content.CommunityPoolSpends, communityPoolSpend(m.Recipient, m.Amount))
+	case *protocolpoolv1.MsgCommunityPoolSpend:
+		content.CommunityPoolSpends = append(content.CommunityPoolSpends, communityPoolSpend(m.Recipient, m.Amount))
+	case *govv1beta1.TextProposal:
+		// Title and description only
+	default:
+		if strings.HasSuffix(typeURL, ".MsgUpdateParams") {
+			content.ParamChanges = append(content.ParamChanges, paramUpdates(typeURL, message.Value)...)
+		}
+	}
+	return message
+}
+
+// messageJSON encodes a message the way the chains' REST endpoints do when
+// its SDK type is linked, so that decimals read as such, and otherwise from
+// its API type
+func messageJSON(typeURL string, value []byte, msg proto.Message) json.RawMessage {
+	if t := gogoproto.MessageType(strings.TrimPrefix(typeURL, "/")); t != nil {
+		if sdkMsg, ok := reflect.New(t.Elem()).Interface().(gogoproto.Message); ok && gogoproto.Unmarshal(value, sdkMsg) == nil {
+			if encoded, err := codec.ProtoMarshalJSON(sdkMsg, interfaceRegistry); err == nil {
+				return encoded
+			}
+		}
+	}
+	encoded, err := jsonOptions.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	return encoded
+}
+
+// paramUpdates lists the parameters an encoded MsgUpdateParams sets, one
+// change per field with its JSON value. The message replaces all of the
+// module's parameters, so unset fields are listed with their zero value.
+func paramUpdates(typeURL string, encoded json.RawMessage) []types.ParamChange {
+	var msg struct {
+		Params map[string]json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(encoded, &msg); err != nil || len(msg.Params) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(msg.Params))
+	for key := range msg.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	subspace := moduleOf(typeURL)
+	changes := make([]types.ParamChange, len(keys))
+	for i, key := range keys {
+		changes[i] = types.ParamChange{Subspace: subspace, Key: key, Value: string(msg.Params[key])}
+	}
+	return changes
+}
+
+// moduleOf returns the module of a message type URL: the package element
+// before its version, e.g. staking for /cosmos.staking.v1beta1.MsgUpdateParams
+func moduleOf(typeURL string) string {
+	parts := strings.Split(strings.TrimPrefix(typeURL, "/"), ".")
+	for i := 1; i < len(parts); i++ {
+		version := parts[i]
+		if len(version) > 1 && version[0] == 'v' && version[1] >= '0' && version[1] <= '9' {
+			return parts[i-1]
+		}
+	}
+	if len(parts) > 1 {
+		return parts[len(parts)-2]
+	}
+	return ""
+}
+
+// softwareUpgrade converts an upgrade plan
+func softwareUpgrade(plan *upgradev1beta1.Plan) *types.SoftwareUpgrade {
+	if plan == nil {
+		return nil
+	}
+	return &types.SoftwareUpgrade{Name: plan.Name, Height: plan.Height, Info: plan.Info}
+}
+
+// communityPoolSpend converts a community pool spend
+func communityPoolSpend(recipient string, amount []*basev1beta1.Coin) types.CommunityPoolSpend {
+	spend := types.CommunityPoolSpend{Recipient: recipient, Amount: make([]types.Coin, 0, len(amount))}
+	for _, coin := range amount {
+		spend.Amount = append(spend.Amount, types.Coin{Denom: coin.Denom, Amount: coin.Amount})
+	}
+	return spend
+}
+
+// decodeClientUpdate reads the subject and substitute clients of an IBC
+// MsgRecoverClient (fields 1 and 2) or legacy ClientUpdateProposal (fields 3
+// and 4, after its title and description)
+func decodeClientUpdate(typeURL string, value []byte) (types.ClientUpdate, error) {
+	subject, substitute := protowire.Number(1), protowire.Number(2)
+	if typeURL == clientUpdateProposalURL {
+		subject, substitute = 3, 4
+	}
+
+	var update types.ClientUpdate
+	err := scanFields(value, func(num protowire.Number, value []byte) error {
+		switch num {
+		case subject:
+			update.SubjectClientID = string(value)
+		case substitute:
+			update.SubstituteClientID = string(value)
+		}
+		return nil
+	})
+	if err != nil {
+		return update, fmt.Errorf("failed to decode %s: %w", typeURL, err)
+	}
+	return update, nil
+}
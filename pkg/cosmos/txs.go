@@ -0,0 +1,322 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+)
+
+// txPageSize is the number of transactions requested per page of a block
+const txPageSize = 100
+
+// TxInfo summarizes a transaction included in a block: its result, the gas it
+// requested and used, the fee it paid and the types of its messages, e.g.
+// cosmos.bank.v1beta1.MsgSend
+type TxInfo struct {
+	Hash         string
+	Code         uint32
+	GasWanted    int64
+	GasUsed      int64
+	Fee          sdk.Coins
+	FeePayer     string
+	MessageTypes []string
+	Packets      []PacketEvent      // none for failed transactions
+	Rewards      []RewardWithdrawal // none for failed transactions
+	Delegations  []DelegateEvent    // none for failed transactions
+}
+
+// RewardWithdrawal is a payout of staking rewards to a delegator, emitted by
+// MsgWithdrawDelegatorReward and by the implicit withdrawal when a delegation
+// changes. Claimed tells the former, including withdrawals executed through
+// authz, from the latter.
+type RewardWithdrawal struct {
+	Delegator string
+	Validator string
+	Amount    sdk.Coins
+	Claimed   bool
+	MsgIndex  int // index of the emitting message in the transaction, -1 when the chain doesn't tell
+}
+
+// DelegateEvent is a delegation of tokens to a validator. Chains before SDK
+// 0.47 emit the amount without its denom, leaving Amount zero.
+type DelegateEvent struct {
+	Delegator string
+	Validator string
+	Amount    sdk.Coin
+	MsgIndex  int // index of the emitting message in the transaction, -1 when the chain doesn't tell
+}
+
+// Messages whose rewards withdrawals are claims, and the authz message
+// executing messages on a granter's behalf, as auto-compounding services do
+const (
+	msgWithdrawDelegatorReward = "cosmos.distribution.v1beta1.MsgWithdrawDelegatorReward"
+	msgExec                    = "cosmos.authz.v1beta1.MsgExec"
+)
+
+// IBC packet lifecycle events, named after the SDK event types without their
+// _packet suffix
+const (
+	PacketSend        = "send"
+	PacketRecv        = "recv"
+	PacketAcknowledge = "acknowledge"
+	PacketTimeout     = "timeout"
+)
+
+// packetEventTypes maps the IBC core event types to packet lifecycle events
+var packetEventTypes = map[string]string{
+	"send_packet":             PacketSend,
+	"recv_packet":             PacketRecv,
+	"acknowledge_packet":      PacketAcknowledge,
+	"timeout_packet":          PacketTimeout,
+	"timeout_on_close_packet": PacketTimeout,
+}
+
+// PacketEvent is an IBC packet event emitted by a transaction. Ports and
+// channels are those of the packet, so the source is the sending chain's end
+// of the channel whichever chain emitted the event.
+type PacketEvent struct {
+	Type       string
+	Sequence   uint64
+	SrcPort    string
+	SrcChannel string
+	DstPort    string
+	DstChannel string
+}
+
+// GetBlockTxs gets the transactions included in a block, in block order
+func (c *Client) GetBlockTxs(ctx context.Context, height int64) ([]TxInfo, error) {
+	var txs []TxInfo
+	for page := uint64(1); ; page++ {
+		req := &txtypes.GetTxsEventRequest{
+			Query:   fmt.Sprintf("tx.height=%d", height),
+			OrderBy: txtypes.OrderBy_ORDER_BY_ASC,
+			Page:    page,
+			Limit:   txPageSize,
+		}
+
+		resp, err := c.txClient.GetTxsEvent(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transactions of block %d: %w", height, err)
+		}
+
+		for i, result := range resp.TxResponses {
+			info := TxInfo{
+				Hash:      result.TxHash,
+				Code:      result.Code,
+				GasWanted: result.GasWanted,
+				GasUsed:   result.GasUsed,
+				FeePayer:  eventAttribute(result.Events, "tx", "fee_payer"),
+			}
+			var claims []bool
+			if i < len(resp.Txs) && resp.Txs[i] != nil {
+				tx := resp.Txs[i]
+				if tx.AuthInfo != nil && tx.AuthInfo.Fee != nil {
+					info.Fee = tx.AuthInfo.Fee.Amount
+					if info.FeePayer == "" {
+						info.FeePayer = tx.AuthInfo.Fee.Payer
+					}
+				}
+				if tx.Body != nil {
+					for _, msg := range tx.Body.Messages {
+						info.MessageTypes = append(info.MessageTypes, strings.TrimPrefix(msg.TypeUrl, "/"))
+						claims = append(claims, claimsRewards(msg.TypeUrl, msg.Value))
+					}
+				}
+			}
+			if result.Code == 0 {
+				info.Packets = packetEvents(result.Events)
+				info.Rewards = rewardWithdrawals(result.Events, claims)
+				info.Delegations = delegateEvents(result.Events)
+			}
+			txs = append(txs, info)
+		}
+
+		if len(resp.TxResponses) < txPageSize || uint64(len(txs)) >= resp.Total {
+			return txs, nil
+		}
+	}
+}
+
+// packetEvents returns the IBC packet events among a transaction's events
+func packetEvents(events []abcitypes.Event) []PacketEvent {
+	var packets []PacketEvent
+	for _, event := range events {
+		kind, ok := packetEventTypes[event.Type]
+		if !ok {
+			continue
+		}
+
+		packet := PacketEvent{Type: kind}
+		for _, attr := range event.Attributes {
+			switch attr.Key {
+			case "packet_sequence":
+				packet.Sequence, _ = strconv.ParseUint(attr.Value, 10, 64)
+			case "packet_src_port":
+				packet.SrcPort = attr.Value
+			case "packet_src_channel":
+				packet.SrcChannel = attr.Value
+			case "packet_dst_port":
+				packet.DstPort = attr.Value
+			case "packet_dst_channel":
+				packet.DstChannel = attr.Value
+			}
+		}
+		packets = append(packets, packet)
+	}
+	return packets
+}
+
+// claimsRewards reports whether a transaction message claims staking
+// rewards, either itself or through an authz MsgExec
+func claimsRewards(typeURL string, value []byte) bool {
+	switch strings.TrimPrefix(typeURL, "/") {
+	case msgWithdrawDelegatorReward:
+		return true
+	case msgExec:
+		var exec authz.MsgExec
+		if err := exec.Unmarshal(value); err != nil {
+			return false
+		}
+		for _, msg := range exec.Msgs {
+			if msg != nil && strings.TrimPrefix(msg.TypeUrl, "/") == msgWithdrawDelegatorReward {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rewardWithdrawals returns the reward payouts among a transaction's events,
+// given whether each of its messages claims rewards. SDK versions before 0.47
+// leave out the delegator, which is then the message's sender, and before
+// 0.50 the index of the emitting message, when any claiming message makes the
+// withdrawal a claim.
+func rewardWithdrawals(events []abcitypes.Event, claims []bool) []RewardWithdrawal {
+	var rewards []RewardWithdrawal
+	for _, event := range events {
+		if event.Type != "withdraw_rewards" {
+			continue
+		}
+
+		reward := RewardWithdrawal{MsgIndex: -1}
+		for _, attr := range event.Attributes {
+			switch attr.Key {
+			case "amount":
+				// Nothing withdrawn is an empty amount
+				reward.Amount, _ = sdk.ParseCoinsNormalized(attr.Value)
+			case "validator":
+				reward.Validator = attr.Value
+			case "delegator":
+				reward.Delegator = attr.Value
+			case "msg_index":
+				reward.MsgIndex = msgIndex(attr.Value)
+			}
+		}
+		if reward.Amount.IsZero() {
+			continue
+		}
+		if reward.Delegator == "" {
+			reward.Delegator = eventAttribute(events, "message", "sender")
+		}
+		if reward.MsgIndex >= 0 && reward.MsgIndex < len(claims) {
+			reward.Claimed = claims[reward.MsgIndex]
+		} else {
+			reward.Claimed = slices.Contains(claims, true)
+		}
+		rewards = append(rewards, reward)
+	}
+	return rewards
+}
+
+// delegateEvents returns the delegations among a transaction's events.
+// SDK versions before 0.47 leave out the delegator, which is then the
+// message's sender.
+func delegateEvents(events []abcitypes.Event) []DelegateEvent {
+	var delegations []DelegateEvent
+	for _, event := range events {
+		if event.Type != "delegate" {
+			continue
+		}
+
+		delegation := DelegateEvent{MsgIndex: -1}
+		for _, attr := range event.Attributes {
+			switch attr.Key {
+			case "amount":
+				delegation.Amount, _ = sdk.ParseCoinNormalized(attr.Value)
+			case "validator":
+				delegation.Validator = attr.Value
+			case "delegator":
+				delegation.Delegator = attr.Value
+			case "msg_index":
+				delegation.MsgIndex = msgIndex(attr.Value)
+			}
+		}
+		if delegation.Delegator == "" {
+			delegation.Delegator = eventAttribute(events, "message", "sender")
+		}
+		delegations = append(delegations, delegation)
+	}
+	return delegations
+}
+
+// msgIndex parses the msg_index attribute SDK 0.50 adds to message events,
+// returning -1 when it is invalid
+func msgIndex(value string) int {
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 {
+		return -1
+	}
+	return index
+}
+
+// Compounders returns the delegators that claimed rewards in a block's
+// transactions and delegated after the claim in the same block, the pattern
+// of auto-compounding services restaking rewards on a delegator's behalf.
+// Within a transaction, a delegation counts when its message comes no earlier
+// than the claim's, or when the chain doesn't tell.
+func Compounders(txs []TxInfo) map[string]bool {
+	type claim struct{ tx, msg int }
+
+	claims := make(map[string]claim)
+	compounders := make(map[string]bool)
+	for i, tx := range txs {
+		for _, reward := range tx.Rewards {
+			if _, ok := claims[reward.Delegator]; reward.Claimed && !ok {
+				claims[reward.Delegator] = claim{i, reward.MsgIndex}
+			}
+		}
+		for _, delegation := range tx.Delegations {
+			first, ok := claims[delegation.Delegator]
+			if !ok {
+				continue
+			}
+			if first.tx < i || first.msg < 0 || delegation.MsgIndex < 0 || first.msg <= delegation.MsgIndex {
+				compounders[delegation.Delegator] = true
+			}
+		}
+	}
+	return compounders
+}
+
+// eventAttribute returns the value of the first attribute with the given key
+// in events of the given type, or "" when there is none
+func eventAttribute(events []abcitypes.Event, eventType, key string) string {
+	for _, event := range events {
+		if event.Type != eventType {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == key {
+				return attr.Value
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package cosmos
+
+import (
+	"reflect"
+	"testing"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+)
+
+// event returns an ABCI event with the given attribute keys and values
+func event(eventType string, attrs ...string) abcitypes.Event {
+	e := abcitypes.Event{Type: eventType}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		e.Attributes = append(e.Attributes, abcitypes.EventAttribute{Key: attrs[i], Value: attrs[i+1]})
+	}
+	return e
+}
+
+func TestRewardWithdrawalsClaimed(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []abcitypes.Event
+		claims []bool
+		want   []bool
+	}{
+		{
+			name: "claim and implicit withdrawal of a delegation",
+			events: []abcitypes.Event{
+				event("withdraw_rewards", "amount", "10uatom", "validator", "val1", "delegator", "del", "msg_index", "0"),
+				event("withdraw_rewards", "amount", "5uatom", "validator", "val2", "delegator", "del", "msg_index", "1"),
+			},
+			claims: []bool{true, false},
+			want:   []bool{true, false},
+		},
+		{
+			name: "without message index",
+			events: []abcitypes.Event{
+				event("withdraw_rewards", "amount", "10uatom", "validator", "val1", "delegator", "del"),
+			},
+			claims: []bool{false, true},
+			want:   []bool{true},
+		},
+		{
+			name: "nothing withdrawn",
+			events: []abcitypes.Event{
+				event("withdraw_rewards", "amount", "", "validator", "val1", "delegator", "del", "msg_index", "0"),
+			},
+			claims: []bool{true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []bool
+			for _, reward := range rewardWithdrawals(tt.events, tt.claims) {
+				got = append(got, reward.Claimed)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("claimed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompounders(t *testing.T) {
+	claim := func(delegator string, msg int) RewardWithdrawal {
+		return RewardWithdrawal{Delegator: delegator, Claimed: true, MsgIndex: msg}
+	}
+	delegate := func(delegator string, msg int) DelegateEvent {
+		return DelegateEvent{Delegator: delegator, MsgIndex: msg}
+	}
+
+	tests := []struct {
+		name string
+		txs  []TxInfo
+		want map[string]bool
+	}{
+		{
+			name: "claim and delegation in one authz exec",
+			txs:  []TxInfo{{Rewards: []RewardWithdrawal{claim("a", 0)}, Delegations: []DelegateEvent{delegate("a", 0)}}},
+			want: map[string]bool{"a": true},
+		},
+		{
+			name: "delegation in a later transaction",
+			txs: []TxInfo{
+				{Rewards: []RewardWithdrawal{claim("a", 0)}},
+				{Delegations: []DelegateEvent{delegate("a", 0)}},
+			},
+			want: map[string]bool{"a": true},
+		},
+		{
+			name: "delegation before the claim",
+			txs: []TxInfo{
+				{Delegations: []DelegateEvent{delegate("a", 0)}},
+				{Rewards: []RewardWithdrawal{claim("a", 1)}, Delegations: []DelegateEvent{delegate("a", 0)}},
+			},
+			want: map[string]bool{},
+		},
+		{
+			name: "implicit withdrawal of a delegation",
+			txs: []TxInfo{{
+				Rewards:     []RewardWithdrawal{{Delegator: "a", MsgIndex: 0}},
+				Delegations: []DelegateEvent{delegate("a", 0)},
+			}},
+			want: map[string]bool{},
+		},
+		{
+			name: "another delegator delegating",
+			txs:  []TxInfo{{Rewards: []RewardWithdrawal{claim("a", 0)}, Delegations: []DelegateEvent{delegate("b", 1)}}},
+			want: map[string]bool{},
+		},
+		{
+			name: "without message index",
+			txs:  []TxInfo{{Rewards: []RewardWithdrawal{claim("a", -1)}, Delegations: []DelegateEvent{delegate("a", -1)}}},
+			want: map[string]bool{"a": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compounders(tt.txs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compounders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+
+	upgradev1beta1 "cosmossdk.io/api/cosmos/upgrade/v1beta1"
+	"google.golang.org/protobuf/proto"
+)
+
+// upgradeService is the upgrade module query service
+const upgradeService = "/cosmos.upgrade.v1beta1.Query/"
+
+// UpgradePlanInfo is a software upgrade scheduled by the upgrade module. The
+// chain halts at Height until its binary is replaced.
+type UpgradePlanInfo struct {
+	Name   string
+	Height int64
+	Info   string
+}
+
+// UpgradeClient queries the upgrade module. Client implements it; chain
+// clients without the module do not.
+type UpgradeClient interface {
+	GetUpgradePlan(ctx context.Context) (*UpgradePlanInfo, error)
+}
+
+var _ UpgradeClient = (*Client)(nil)
+
+// GetUpgradePlan gets the scheduled software upgrade, or nil when none is
+func (c *Client) GetUpgradePlan(ctx context.Context) (*UpgradePlanInfo, error) {
+	req, err := proto.Marshal(&upgradev1beta1.QueryCurrentPlanRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode current plan request: %w", err)
+	}
+	resp, err := c.invokeRaw(ctx, upgradeService+"CurrentPlan", req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current upgrade plan: %w", err)
+	}
+
+	var current upgradev1beta1.QueryCurrentPlanResponse
+	if err := proto.Unmarshal(resp, &current); err != nil {
+		return nil, fmt.Errorf("failed to decode current upgrade plan: %w", err)
+	}
+	if current.Plan == nil {
+		return nil, nil
+	}
+	return &UpgradePlanInfo{Name: current.Plan.Name, Height: current.Plan.Height, Info: current.Plan.Info}, nil
+}
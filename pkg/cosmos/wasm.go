@@ -0,0 +1,124 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// wasmService is the CosmWasm query service
+const wasmService = "/cosmwasm.wasm.v1.Query/"
+
+// cw721PageSize is the number of token IDs requested per CW721 tokens query,
+// the maximum of the reference implementation
+const cw721PageSize = 100
+
+// CW20TokenInfo is the metadata a CW20 contract reports
+type CW20TokenInfo struct {
+	Name        string `json:"name"`
+	Symbol      string `json:"symbol"`
+	Decimals    int    `json:"decimals"`
+	TotalSupply string `json:"total_supply"`
+}
+
+// CW721ContractInfo is the metadata a CW721 contract reports
+type CW721ContractInfo struct {
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+}
+
+// WasmClient smart-queries CosmWasm token contracts. Client implements it;
+// chain clients without the wasm module do not.
+type WasmClient interface {
+	GetCW20TokenInfo(ctx context.Context, contract string) (*CW20TokenInfo, error)
+	GetCW20Balance(ctx context.Context, contract, address string) (string, error)
+	GetCW721ContractInfo(ctx context.Context, contract string) (*CW721ContractInfo, error)
+	GetCW721Tokens(ctx context.Context, contract, owner string) ([]string, error)
+}
+
+var _ WasmClient = (*Client)(nil)
+
+// GetCW20TokenInfo gets the name, symbol and decimals of a CW20 contract
+func (c *Client) GetCW20TokenInfo(ctx context.Context, contract string) (*CW20TokenInfo, error) {
+	var info CW20TokenInfo
+	if err := c.smartQuery(ctx, contract, map[string]any{"token_info": struct{}{}}, &info); err != nil {
+		return nil, fmt.Errorf("failed to get token info of %s: %w", contract, err)
+	}
+	return &info, nil
+}
+
+// GetCW20Balance gets the CW20 balance of an address
+func (c *Client) GetCW20Balance(ctx context.Context, contract, address string) (string, error) {
+	var resp struct {
+		Balance string `json:"balance"`
+	}
+	query := map[string]any{"balance": map[string]string{"address": address}}
+	if err := c.smartQuery(ctx, contract, query, &resp); err != nil {
+		return "", fmt.Errorf("failed to get balance in %s: %w", contract, err)
+	}
+	return resp.Balance, nil
+}
+
+// GetCW721ContractInfo gets the name and symbol of a CW721 contract
+func (c *Client) GetCW721ContractInfo(ctx context.Context, contract string) (*CW721ContractInfo, error) {
+	var info CW721ContractInfo
+	if err := c.smartQuery(ctx, contract, map[string]any{"contract_info": struct{}{}}, &info); err != nil {
+		return nil, fmt.Errorf("failed to get contract info of %s: %w", contract, err)
+	}
+	return &info, nil
+}
+
+// GetCW721Tokens gets the IDs of the CW721 tokens owned by an address,
+// following the contract's pagination
+func (c *Client) GetCW721Tokens(ctx context.Context, contract, owner string) ([]string, error) {
+	var tokens []string
+	args := map[string]any{"owner": owner, "limit": cw721PageSize}
+	for {
+		var resp struct {
+			Tokens []string `json:"tokens"`
+		}
+		if err := c.smartQuery(ctx, contract, map[string]any{"tokens": args}, &resp); err != nil {
+			return nil, fmt.Errorf("failed to get tokens in %s: %w", contract, err)
+		}
+		tokens = append(tokens, resp.Tokens...)
+		if len(resp.Tokens) < cw721PageSize {
+			return tokens, nil
+		}
+		args["start_after"] = resp.Tokens[len(resp.Tokens)-1]
+	}
+}
+
+// smartQuery runs a JSON smart query against a contract and decodes the JSON
+// result into result
+func (c *Client) smartQuery(ctx context.Context, contract string, query, result any) error {
+	data, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to encode query: %w", err)
+	}
+	req := protowire.AppendTag(nil, 1, protowire.BytesType) // address
+	req = protowire.AppendString(req, contract)
+	req = protowire.AppendTag(req, 2, protowire.BytesType) // query_data
+	req = protowire.AppendBytes(req, data)
+
+	resp, err := c.invokeRaw(ctx, wasmService+"SmartContractState", req)
+	if err != nil {
+		return err
+	}
+
+	var raw []byte
+	err = scanFields(resp, func(num protowire.Number, value []byte) error {
+		if num == 1 { // data
+			raw = value
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to decode query response: %w", err)
+	}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return fmt.Errorf("failed to decode query result: %w", err)
+	}
+	return nil
+}
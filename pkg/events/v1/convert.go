@@ -0,0 +1,98 @@
+// Package eventsv1 holds the events published to the state change topic,
+// generated from proto/statemesh/events/v1/events.proto. Run make proto after
+// changing the schema.
+package eventsv1
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/cosmos/state-mesh/pkg/types"
+)
+
+// NewStateChange converts a state change read from a store
+func NewStateChange(change *types.StateChange) *StateChange {
+	return &StateChange{
+		ChainName: change.ChainName,
+		StoreKey:  change.StoreKey,
+		Key:       change.Key,
+		Value:     change.Value,
+		Delete:    change.Delete,
+		Height:    change.Height,
+		Timestamp: timestamp(change.Timestamp),
+	}
+}
+
+// NewBalanceEvent converts a balance event
+func NewBalanceEvent(event *types.BalanceEvent) *BalanceEvent {
+	return &BalanceEvent{
+		EventId:        event.EventID,
+		Timestamp:      timestamp(event.Timestamp),
+		ChainName:      event.ChainName,
+		Address:        event.Address,
+		Denom:          event.Denom,
+		Amount:         event.Amount,
+		PreviousAmount: event.PreviousAmount,
+		ChangeType:     event.ChangeType,
+		Height:         event.Height,
+		TxHash:         event.TxHash,
+	}
+}
+
+// NewDelegationEvent converts a delegation event
+func NewDelegationEvent(event *types.DelegationEvent) *DelegationEvent {
+	return &DelegationEvent{
+		EventId:          event.EventID,
+		Timestamp:        timestamp(event.Timestamp),
+		ChainName:        event.ChainName,
+		DelegatorAddress: event.DelegatorAddress,
+		ValidatorAddress: event.ValidatorAddress,
+		Shares:           event.Shares,
+		PreviousShares:   event.PreviousShares,
+		ChangeType:       event.ChangeType,
+		Height:           event.Height,
+		TxHash:           event.TxHash,
+	}
+}
+
+// NewValidatorEvent builds the event of a validator's new state
+func NewValidatorEvent(eventID string, validator *types.Validator) *ValidatorEvent {
+	return &ValidatorEvent{
+		EventId:          eventID,
+		Timestamp:        timestamp(validator.UpdatedAt),
+		ChainName:        validator.ChainName,
+		OperatorAddress:  validator.OperatorAddress,
+		ConsensusAddress: validator.ConsensusAddress,
+		Moniker:          validator.Description.Moniker,
+		Status:           validator.Status,
+		Jailed:           validator.Jailed,
+		Tokens:           validator.Tokens,
+		DelegatorShares:  validator.DelegatorShares,
+		CommissionRate:   validator.Commission.Rate,
+		Height:           validator.Height,
+	}
+}
+
+// NewProposalEvent builds the event of a proposal's new state
+func NewProposalEvent(eventID string, proposal *types.Proposal) *ProposalEvent {
+	return &ProposalEvent{
+		EventId:       eventID,
+		Timestamp:     timestamp(proposal.UpdatedAt),
+		ChainName:     proposal.ChainName,
+		ProposalId:    proposal.ProposalID,
+		Title:         proposal.Content.Title,
+		Type:          proposal.Content.Type,
+		Status:        proposal.Status,
+		VotingEndTime: timestamp(proposal.VotingEndTime),
+		Height:        proposal.Height,
+	}
+}
+
+// timestamp converts a time, leaving the zero time unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,741 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: statemesh/events/v1/events.proto
+
+// Events published to the state change topic. Kafka message values are the
+// proto3 JSON encoding of these messages with the field names below; the
+// message's type header names the message, and its event_id header repeats
+// the event ID.
+
+package eventsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StateChange is a raw change to a module store, streamed through ADR-038.
+// Published with type "state_change".
+type StateChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChainName     string                 `protobuf:"bytes,1,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	StoreKey      string                 `protobuf:"bytes,2,opt,name=store_key,json=storeKey,proto3" json:"store_key,omitempty"`
+	Key           []byte                 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Value         []byte                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Delete        bool                   `protobuf:"varint,5,opt,name=delete,proto3" json:"delete,omitempty"`
+	Height        int64                  `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StateChange) Reset() {
+	*x = StateChange{}
+	mi := &file_statemesh_events_v1_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StateChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateChange) ProtoMessage() {}
+
+func (x *StateChange) ProtoReflect() protoreflect.Message {
+	mi := &file_statemesh_events_v1_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateChange.ProtoReflect.Descriptor instead.
+func (*StateChange) Descriptor() ([]byte, []int) {
+	return file_statemesh_events_v1_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StateChange) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *StateChange) GetStoreKey() string {
+	if x != nil {
+		return x.StoreKey
+	}
+	return ""
+}
+
+func (x *StateChange) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *StateChange) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *StateChange) GetDelete() bool {
+	if x != nil {
+		return x.Delete
+	}
+	return false
+}
+
+func (x *StateChange) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *StateChange) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// BalanceEvent is a change to the balance of an account in one denom.
+// Published with type "balance".
+type BalanceEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deterministic, identifies redeliveries
+	EventId        string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ChainName      string                 `protobuf:"bytes,3,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	Address        string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	Denom          string                 `protobuf:"bytes,5,opt,name=denom,proto3" json:"denom,omitempty"`
+	Amount         string                 `protobuf:"bytes,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	PreviousAmount string                 `protobuf:"bytes,7,opt,name=previous_amount,json=previousAmount,proto3" json:"previous_amount,omitempty"`
+	// "increase", "decrease" or "current"
+	ChangeType    string `protobuf:"bytes,8,opt,name=change_type,json=changeType,proto3" json:"change_type,omitempty"`
+	Height        int64  `protobuf:"varint,9,opt,name=height,proto3" json:"height,omitempty"`
+	TxHash        string `protobuf:"bytes,10,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BalanceEvent) Reset() {
+	*x = BalanceEvent{}
+	mi := &file_statemesh_events_v1_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BalanceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceEvent) ProtoMessage() {}
+
+func (x *BalanceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_statemesh_events_v1_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceEvent.ProtoReflect.Descriptor instead.
+func (*BalanceEvent) Descriptor() ([]byte, []int) {
+	return file_statemesh_events_v1_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BalanceEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *BalanceEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *BalanceEvent) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *BalanceEvent) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *BalanceEvent) GetDenom() string {
+	if x != nil {
+		return x.Denom
+	}
+	return ""
+}
+
+func (x *BalanceEvent) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *BalanceEvent) GetPreviousAmount() string {
+	if x != nil {
+		return x.PreviousAmount
+	}
+	return ""
+}
+
+func (x *BalanceEvent) GetChangeType() string {
+	if x != nil {
+		return x.ChangeType
+	}
+	return ""
+}
+
+func (x *BalanceEvent) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *BalanceEvent) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+// DelegationEvent is a change to the delegation of a delegator to a
+// validator. Published with type "delegation".
+type DelegationEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deterministic, identifies redeliveries
+	EventId          string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ChainName        string                 `protobuf:"bytes,3,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	DelegatorAddress string                 `protobuf:"bytes,4,opt,name=delegator_address,json=delegatorAddress,proto3" json:"delegator_address,omitempty"`
+	ValidatorAddress string                 `protobuf:"bytes,5,opt,name=validator_address,json=validatorAddress,proto3" json:"validator_address,omitempty"`
+	Shares           string                 `protobuf:"bytes,6,opt,name=shares,proto3" json:"shares,omitempty"`
+	PreviousShares   string                 `protobuf:"bytes,7,opt,name=previous_shares,json=previousShares,proto3" json:"previous_shares,omitempty"`
+	// "delegate", "undelegate", "redelegate" or "current"
+	ChangeType    string `protobuf:"bytes,8,opt,name=change_type,json=changeType,proto3" json:"change_type,omitempty"`
+	Height        int64  `protobuf:"varint,9,opt,name=height,proto3" json:"height,omitempty"`
+	TxHash        string `protobuf:"bytes,10,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DelegationEvent) Reset() {
+	*x = DelegationEvent{}
+	mi := &file_statemesh_events_v1_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelegationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelegationEvent) ProtoMessage() {}
+
+func (x *DelegationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_statemesh_events_v1_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelegationEvent.ProtoReflect.Descriptor instead.
+func (*DelegationEvent) Descriptor() ([]byte, []int) {
+	return file_statemesh_events_v1_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DelegationEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *DelegationEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *DelegationEvent) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *DelegationEvent) GetDelegatorAddress() string {
+	if x != nil {
+		return x.DelegatorAddress
+	}
+	return ""
+}
+
+func (x *DelegationEvent) GetValidatorAddress() string {
+	if x != nil {
+		return x.ValidatorAddress
+	}
+	return ""
+}
+
+func (x *DelegationEvent) GetShares() string {
+	if x != nil {
+		return x.Shares
+	}
+	return ""
+}
+
+func (x *DelegationEvent) GetPreviousShares() string {
+	if x != nil {
+		return x.PreviousShares
+	}
+	return ""
+}
+
+func (x *DelegationEvent) GetChangeType() string {
+	if x != nil {
+		return x.ChangeType
+	}
+	return ""
+}
+
+func (x *DelegationEvent) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *DelegationEvent) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+// ValidatorEvent is the new state of a validator whose state changed.
+// Published with type "validator".
+type ValidatorEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deterministic, identifies redeliveries
+	EventId          string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ChainName        string                 `protobuf:"bytes,3,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	OperatorAddress  string                 `protobuf:"bytes,4,opt,name=operator_address,json=operatorAddress,proto3" json:"operator_address,omitempty"`
+	ConsensusAddress string                 `protobuf:"bytes,5,opt,name=consensus_address,json=consensusAddress,proto3" json:"consensus_address,omitempty"`
+	Moniker          string                 `protobuf:"bytes,6,opt,name=moniker,proto3" json:"moniker,omitempty"`
+	// BOND_STATUS_BONDED, BOND_STATUS_UNBONDING or BOND_STATUS_UNBONDED
+	Status          string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Jailed          bool   `protobuf:"varint,8,opt,name=jailed,proto3" json:"jailed,omitempty"`
+	Tokens          string `protobuf:"bytes,9,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	DelegatorShares string `protobuf:"bytes,10,opt,name=delegator_shares,json=delegatorShares,proto3" json:"delegator_shares,omitempty"`
+	CommissionRate  string `protobuf:"bytes,11,opt,name=commission_rate,json=commissionRate,proto3" json:"commission_rate,omitempty"`
+	Height          int64  `protobuf:"varint,12,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ValidatorEvent) Reset() {
+	*x = ValidatorEvent{}
+	mi := &file_statemesh_events_v1_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidatorEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidatorEvent) ProtoMessage() {}
+
+func (x *ValidatorEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_statemesh_events_v1_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidatorEvent.ProtoReflect.Descriptor instead.
+func (*ValidatorEvent) Descriptor() ([]byte, []int) {
+	return file_statemesh_events_v1_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidatorEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ValidatorEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ValidatorEvent) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *ValidatorEvent) GetOperatorAddress() string {
+	if x != nil {
+		return x.OperatorAddress
+	}
+	return ""
+}
+
+func (x *ValidatorEvent) GetConsensusAddress() string {
+	if x != nil {
+		return x.ConsensusAddress
+	}
+	return ""
+}
+
+func (x *ValidatorEvent) GetMoniker() string {
+	if x != nil {
+		return x.Moniker
+	}
+	return ""
+}
+
+func (x *ValidatorEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ValidatorEvent) GetJailed() bool {
+	if x != nil {
+		return x.Jailed
+	}
+	return false
+}
+
+func (x *ValidatorEvent) GetTokens() string {
+	if x != nil {
+		return x.Tokens
+	}
+	return ""
+}
+
+func (x *ValidatorEvent) GetDelegatorShares() string {
+	if x != nil {
+		return x.DelegatorShares
+	}
+	return ""
+}
+
+func (x *ValidatorEvent) GetCommissionRate() string {
+	if x != nil {
+		return x.CommissionRate
+	}
+	return ""
+}
+
+func (x *ValidatorEvent) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+// ProposalEvent is the new state of a governance proposal that was submitted
+// or whose state changed. Published with type "proposal".
+type ProposalEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deterministic, identifies redeliveries
+	EventId    string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Timestamp  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ChainName  string                 `protobuf:"bytes,3,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	ProposalId uint64                 `protobuf:"varint,4,opt,name=proposal_id,json=proposalId,proto3" json:"proposal_id,omitempty"`
+	Title      string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	// Type of the proposal's message when it has exactly one
+	Type string `protobuf:"bytes,6,opt,name=type,proto3" json:"type,omitempty"`
+	// PROPOSAL_STATUS_DEPOSIT_PERIOD, PROPOSAL_STATUS_VOTING_PERIOD,
+	// PROPOSAL_STATUS_PASSED, PROPOSAL_STATUS_REJECTED or PROPOSAL_STATUS_FAILED
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	VotingEndTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=voting_end_time,json=votingEndTime,proto3" json:"voting_end_time,omitempty"`
+	Height        int64                  `protobuf:"varint,9,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProposalEvent) Reset() {
+	*x = ProposalEvent{}
+	mi := &file_statemesh_events_v1_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProposalEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposalEvent) ProtoMessage() {}
+
+func (x *ProposalEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_statemesh_events_v1_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposalEvent.ProtoReflect.Descriptor instead.
+func (*ProposalEvent) Descriptor() ([]byte, []int) {
+	return file_statemesh_events_v1_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ProposalEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ProposalEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ProposalEvent) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *ProposalEvent) GetProposalId() uint64 {
+	if x != nil {
+		return x.ProposalId
+	}
+	return 0
+}
+
+func (x *ProposalEvent) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ProposalEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ProposalEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ProposalEvent) GetVotingEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.VotingEndTime
+	}
+	return nil
+}
+
+func (x *ProposalEvent) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+var File_statemesh_events_v1_events_proto protoreflect.FileDescriptor
+
+const file_statemesh_events_v1_events_proto_rawDesc = "" +
+	"\n" +
+	" statemesh/events/v1/events.proto\x12\x13statemesh.events.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xdb\x01\n" +
+	"\vStateChange\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x01 \x01(\tR\tchainName\x12\x1b\n" +
+	"\tstore_key\x18\x02 \x01(\tR\bstoreKey\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\fR\x03key\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\fR\x05value\x12\x16\n" +
+	"\x06delete\x18\x05 \x01(\bR\x06delete\x12\x16\n" +
+	"\x06height\x18\x06 \x01(\x03R\x06height\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xc5\x02\n" +
+	"\fBalanceEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x03 \x01(\tR\tchainName\x12\x18\n" +
+	"\aaddress\x18\x04 \x01(\tR\aaddress\x12\x14\n" +
+	"\x05denom\x18\x05 \x01(\tR\x05denom\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\tR\x06amount\x12'\n" +
+	"\x0fprevious_amount\x18\a \x01(\tR\x0epreviousAmount\x12\x1f\n" +
+	"\vchange_type\x18\b \x01(\tR\n" +
+	"changeType\x12\x16\n" +
+	"\x06height\x18\t \x01(\x03R\x06height\x12\x17\n" +
+	"\atx_hash\x18\n" +
+	" \x01(\tR\x06txHash\"\xf2\x02\n" +
+	"\x0fDelegationEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x03 \x01(\tR\tchainName\x12+\n" +
+	"\x11delegator_address\x18\x04 \x01(\tR\x10delegatorAddress\x12+\n" +
+	"\x11validator_address\x18\x05 \x01(\tR\x10validatorAddress\x12\x16\n" +
+	"\x06shares\x18\x06 \x01(\tR\x06shares\x12'\n" +
+	"\x0fprevious_shares\x18\a \x01(\tR\x0epreviousShares\x12\x1f\n" +
+	"\vchange_type\x18\b \x01(\tR\n" +
+	"changeType\x12\x16\n" +
+	"\x06height\x18\t \x01(\x03R\x06height\x12\x17\n" +
+	"\atx_hash\x18\n" +
+	" \x01(\tR\x06txHash\"\xaa\x03\n" +
+	"\x0eValidatorEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x03 \x01(\tR\tchainName\x12)\n" +
+	"\x10operator_address\x18\x04 \x01(\tR\x0foperatorAddress\x12+\n" +
+	"\x11consensus_address\x18\x05 \x01(\tR\x10consensusAddress\x12\x18\n" +
+	"\amoniker\x18\x06 \x01(\tR\amoniker\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x16\n" +
+	"\x06jailed\x18\b \x01(\bR\x06jailed\x12\x16\n" +
+	"\x06tokens\x18\t \x01(\tR\x06tokens\x12)\n" +
+	"\x10delegator_shares\x18\n" +
+	" \x01(\tR\x0fdelegatorShares\x12'\n" +
+	"\x0fcommission_rate\x18\v \x01(\tR\x0ecommissionRate\x12\x16\n" +
+	"\x06height\x18\f \x01(\x03R\x06height\"\xc2\x02\n" +
+	"\rProposalEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x03 \x01(\tR\tchainName\x12\x1f\n" +
+	"\vproposal_id\x18\x04 \x01(\x04R\n" +
+	"proposalId\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x12\n" +
+	"\x04type\x18\x06 \x01(\tR\x04type\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12B\n" +
+	"\x0fvoting_end_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\rvotingEndTime\x12\x16\n" +
+	"\x06height\x18\t \x01(\x03R\x06heightB5Z3github.com/cosmos/state-mesh/pkg/events/v1;eventsv1b\x06proto3"
+
+var (
+	file_statemesh_events_v1_events_proto_rawDescOnce sync.Once
+	file_statemesh_events_v1_events_proto_rawDescData []byte
+)
+
+func file_statemesh_events_v1_events_proto_rawDescGZIP() []byte {
+	file_statemesh_events_v1_events_proto_rawDescOnce.Do(func() {
+		file_statemesh_events_v1_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_statemesh_events_v1_events_proto_rawDesc), len(file_statemesh_events_v1_events_proto_rawDesc)))
+	})
+	return file_statemesh_events_v1_events_proto_rawDescData
+}
+
+var file_statemesh_events_v1_events_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_statemesh_events_v1_events_proto_goTypes = []any{
+	(*StateChange)(nil),           // 0: statemesh.events.v1.StateChange
+	(*BalanceEvent)(nil),          // 1: statemesh.events.v1.BalanceEvent
+	(*DelegationEvent)(nil),       // 2: statemesh.events.v1.DelegationEvent
+	(*ValidatorEvent)(nil),        // 3: statemesh.events.v1.ValidatorEvent
+	(*ProposalEvent)(nil),         // 4: statemesh.events.v1.ProposalEvent
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+}
+var file_statemesh_events_v1_events_proto_depIdxs = []int32{
+	5, // 0: statemesh.events.v1.StateChange.timestamp:type_name -> google.protobuf.Timestamp
+	5, // 1: statemesh.events.v1.BalanceEvent.timestamp:type_name -> google.protobuf.Timestamp
+	5, // 2: statemesh.events.v1.DelegationEvent.timestamp:type_name -> google.protobuf.Timestamp
+	5, // 3: statemesh.events.v1.ValidatorEvent.timestamp:type_name -> google.protobuf.Timestamp
+	5, // 4: statemesh.events.v1.ProposalEvent.timestamp:type_name -> google.protobuf.Timestamp
+	5, // 5: statemesh.events.v1.ProposalEvent.voting_end_time:type_name -> google.protobuf.Timestamp
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_statemesh_events_v1_events_proto_init() }
+func file_statemesh_events_v1_events_proto_init() {
+	if File_statemesh_events_v1_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_statemesh_events_v1_events_proto_rawDesc), len(file_statemesh_events_v1_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_statemesh_events_v1_events_proto_goTypes,
+		DependencyIndexes: file_statemesh_events_v1_events_proto_depIdxs,
+		MessageInfos:      file_statemesh_events_v1_events_proto_msgTypes,
+	}.Build()
+	File_statemesh_events_v1_events_proto = out.File
+	file_statemesh_events_v1_events_proto_goTypes = nil
+	file_statemesh_events_v1_events_proto_depIdxs = nil
+}
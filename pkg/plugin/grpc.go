@@ -0,0 +1,176 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ServiceName is the gRPC service plugins implement, see module.proto
+const ServiceName = "statemesh.plugin.v1.ModulePlugin"
+
+// GRPCPlugin serves and dispenses a Module over gRPC for go-plugin
+type GRPCPlugin struct {
+	goplugin.NetRPCUnsupportedPlugin
+
+	// Impl is the served module, set only in the plugin process
+	Impl Module
+}
+
+var _ goplugin.GRPCPlugin = (*GRPCPlugin)(nil)
+
+// GRPCServer registers the module service
+func (p *GRPCPlugin) GRPCServer(broker *goplugin.GRPCBroker, s *grpc.Server) error {
+	s.RegisterService(&serviceDesc, &server{impl: p.Impl})
+	return nil
+}
+
+// GRPCClient returns a Module calling the plugin process
+func (p *GRPCPlugin) GRPCClient(ctx context.Context, broker *goplugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	return &client{conn: conn}, nil
+}
+
+// Messages are google.protobuf.Struct values holding the JSON encoding of the
+// request and response types, so plugins in any language need no generated
+// code beyond the well-known types
+func toStruct(v any) (*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(fields)
+}
+
+// fromStruct decodes a message into v
+func fromStruct(s *structpb.Struct, v any) error {
+	data, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// client is the Module of a plugin process
+type client struct {
+	conn *grpc.ClientConn
+}
+
+// call invokes a method with a request, decoding the response into resp
+func (c *client) call(ctx context.Context, method string, req, resp any) error {
+	in, err := toStruct(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+	out := new(structpb.Struct)
+	if err := c.conn.Invoke(ctx, "/"+ServiceName+"/"+method, in, out); err != nil {
+		return err
+	}
+	if err := fromStruct(out, resp); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	return nil
+}
+
+// Describe returns the plugin's module description
+func (c *client) Describe(ctx context.Context) (*Description, error) {
+	out := new(structpb.Struct)
+	if err := c.conn.Invoke(ctx, "/"+ServiceName+"/Describe", &emptypb.Empty{}, out); err != nil {
+		return nil, err
+	}
+	var desc Description
+	if err := fromStruct(out, &desc); err != nil {
+		return nil, fmt.Errorf("failed to decode Describe response: %w", err)
+	}
+	return &desc, nil
+}
+
+// Poll asks the plugin to ingest its module's state
+func (c *client) Poll(ctx context.Context, req *PollRequest) (*Response, error) {
+	var resp Response
+	if err := c.call(ctx, "Poll", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// HandleStateChange passes a state change to the plugin
+func (c *client) HandleStateChange(ctx context.Context, req *StateChangeRequest) (*Response, error) {
+	var resp Response
+	if err := c.call(ctx, "HandleStateChange", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// server serves a Module in the plugin process
+type server struct {
+	impl Module
+}
+
+// serviceDesc describes the module service as protoc would generate it
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Describe",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				if err := dec(new(emptypb.Empty)); err != nil {
+					return nil, err
+				}
+				desc, err := srv.(*server).impl.Describe(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return toStruct(desc)
+			},
+		},
+		{
+			MethodName: "Poll",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				var req PollRequest
+				if err := decode(dec, &req); err != nil {
+					return nil, err
+				}
+				resp, err := srv.(*server).impl.Poll(ctx, &req)
+				if err != nil {
+					return nil, err
+				}
+				return toStruct(resp)
+			},
+		},
+		{
+			MethodName: "HandleStateChange",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				var req StateChangeRequest
+				if err := decode(dec, &req); err != nil {
+					return nil, err
+				}
+				resp, err := srv.(*server).impl.HandleStateChange(ctx, &req)
+				if err != nil {
+					return nil, err
+				}
+				return toStruct(resp)
+			},
+		},
+	},
+	Metadata: "module.proto",
+}
+
+// decode reads a request message into v
+func decode(dec func(any) error, v any) error {
+	in := new(structpb.Struct)
+	if err := dec(in); err != nil {
+		return err
+	}
+	return fromStruct(in, v)
+}
@@ -0,0 +1,107 @@
+// Package plugin is the contract between state-mesh and out-of-process module
+// plugins. A plugin is an executable that decodes the state of a module
+// state-mesh does not know, such as an appchain's proprietary x/oracle, and
+// returns the rows to write to the tables it declares. It runs as a
+// hashicorp/go-plugin subprocess speaking gRPC; Go plugins implement Module and
+// call Serve from main.
+package plugin
+
+import (
+	"context"
+	"time"
+
+	goplugin "github.com/hashicorp/go-plugin"
+)
+
+// Handshake is the go-plugin handshake state-mesh starts plugins with. A
+// plugin started any other way exits with a message instead of serving.
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "STATE_MESH_PLUGIN",
+	MagicCookieValue: "module",
+}
+
+// PluginName is the name a plugin serves its module under
+const PluginName = "module"
+
+// Module is implemented by plugins
+type Module interface {
+	// Describe returns the module's name and the tables it writes
+	Describe(ctx context.Context) (*Description, error)
+
+	// Poll reads the module's state at a height, typically from the chain's
+	// gRPC endpoint, and returns the rows to write
+	Poll(ctx context.Context, req *PollRequest) (*Response, error)
+
+	// HandleStateChange decodes a change to the module's store streamed through
+	// ADR-038 and returns the rows to write
+	HandleStateChange(ctx context.Context, req *StateChangeRequest) (*Response, error)
+}
+
+// Description describes a plugin's module
+type Description struct {
+	Name string `json:"name"`
+
+	// Tables lists the tables the plugin writes. Writes to any other table
+	// are rejected.
+	Tables []string `json:"tables"`
+
+	// Schema holds idempotent PostgreSQL statements creating the tables, run
+	// when state-mesh starts
+	Schema []string `json:"schema,omitempty"`
+}
+
+// PollRequest asks a plugin to ingest its module's state for a chain
+type PollRequest struct {
+	Chain        string            `json:"chain"`
+	ChainID      string            `json:"chain_id"`
+	GRPCEndpoint string            `json:"grpc_endpoint"`
+	RESTEndpoint string            `json:"rest_endpoint,omitempty"`
+	Height       int64             `json:"height"`
+	Options      map[string]string `json:"options,omitempty"` // the module's options in the chain configuration
+}
+
+// StateChangeRequest is a change to the module's store
+type StateChangeRequest struct {
+	Chain     string    `json:"chain"`
+	Store     string    `json:"store"`
+	Key       []byte    `json:"key"`
+	Value     []byte    `json:"value,omitempty"`
+	Delete    bool      `json:"delete"`
+	Height    int64     `json:"height"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Response holds the rows a plugin asks state-mesh to write. They are written
+// in one transaction, in order.
+type Response struct {
+	Writes []Write `json:"writes,omitempty"`
+}
+
+// Write upserts or deletes one row of a table declared by the plugin
+type Write struct {
+	Table string `json:"table"`
+
+	// Key names the columns identifying the row. Upserts need a unique
+	// constraint over exactly these columns.
+	Key []string `json:"key"`
+
+	// Values holds the row's columns, including the key columns. Values that
+	// are objects or arrays are stored as JSON.
+	Values map[string]any `json:"values"`
+
+	// Delete removes the row with the key's values instead
+	Delete bool `json:"delete,omitempty"`
+}
+
+// Serve serves a module to state-mesh. It must be called from the plugin's
+// main function and does not return while state-mesh uses the plugin.
+func Serve(module Module) {
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins: goplugin.PluginSet{
+			PluginName: &GRPCPlugin{Impl: module},
+		},
+		GRPCServer: goplugin.DefaultGRPCServer,
+	})
+}
@@ -1,15 +1,18 @@
 package types
 
 import (
+	"encoding/json"
 	"time"
 )
 
 // Account represents a blockchain account
 type Account struct {
-	ChainName string    `json:"chain_name" db:"chain_name"`
-	Address   string    `json:"address" db:"address"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ChainName       string    `json:"chain_name" db:"chain_name"`
+	Address         string    `json:"address" db:"address"`
+	FirstSeenHeight int64     `json:"first_seen_height" db:"first_seen_height"`
+	Height          int64     `json:"height" db:"height"` // last height the account was seen
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // Balance represents an account balance
@@ -20,6 +23,10 @@ type Balance struct {
 	Amount    string    `json:"amount" db:"amount"`
 	Height    int64     `json:"height" db:"height"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// DisplayAmount is the amount in display units, set by the API for the
+	// chain's base denom
+	DisplayAmount string `json:"display_amount,omitempty" db:"-"`
 }
 
 // Delegation represents a staking delegation
@@ -32,11 +39,46 @@ type Delegation struct {
 	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// ValidatorDelegator represents a delegation to a validator with its estimated
+// token amount
+type ValidatorDelegator struct {
+	DelegatorAddress string    `json:"delegator_address" db:"delegator_address"`
+	Shares           string    `json:"shares" db:"shares"`
+	Tokens           string    `json:"tokens" db:"tokens"` // estimated from the validator's token/share ratio
+	Height           int64     `json:"height" db:"height"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Search result kinds
+const (
+	SearchKindValidator = "validator"
+	SearchKindProposal  = "proposal"
+	SearchKindDenom     = "denom"
+	SearchKindAddress   = "address"
+)
+
+// SearchResult represents a single match of a search query
+type SearchResult struct {
+	Kind      string  `json:"kind"`
+	ChainName string  `json:"chain_name"`
+	ID        string  `json:"id"`    // operator address, proposal ID, denom or account address
+	Label     string  `json:"label"` // moniker, proposal title, denom or address
+	Score     float64 `json:"score"`
+}
+
+// Page describes the position of a paginated result
+type Page struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
+
 // Validator represents a validator
 type Validator struct {
 	ChainName          string              `json:"chain_name" db:"chain_name"`
 	OperatorAddress    string              `json:"operator_address" db:"operator_address"`
 	ConsensusPubkey    string              `json:"consensus_pubkey" db:"consensus_pubkey"`
+	ConsensusAddress   string              `json:"consensus_address" db:"consensus_address"`
 	Jailed             bool                `json:"jailed" db:"jailed"`
 	Status             string              `json:"status" db:"status"`
 	Tokens             string              `json:"tokens" db:"tokens"`
@@ -66,6 +108,29 @@ type ValidatorCommission struct {
 	MaxChangeRate string `json:"max_change_rate" db:"commission_max_change_rate"`
 }
 
+// CommissionChange represents a change of a validator's commission rate
+// detected between two staking snapshots. EffectiveTime is the commission's
+// update time on chain.
+type CommissionChange struct {
+	ChainName       string    `json:"chain_name"`
+	OperatorAddress string    `json:"operator_address"`
+	Moniker         string    `json:"moniker"`
+	OldRate         string    `json:"old_rate"`
+	NewRate         string    `json:"new_rate"`
+	EffectiveTime   time.Time `json:"effective_time"`
+	Height          int64     `json:"height"`
+	DetectedAt      time.Time `json:"detected_at"`
+}
+
+// CommissionAlert is the webhook payload notifying a tenant that a validator
+// its watched delegators delegate to raised its commission
+type CommissionAlert struct {
+	TenantID   string           `json:"tenant_id"`
+	Change     CommissionChange `json:"change"`
+	Delegators []string         `json:"delegators"` // the watched addresses delegating to the validator
+	SentAt     time.Time        `json:"sent_at"`
+}
+
 // UnbondingDelegation represents an unbonding delegation
 type UnbondingDelegation struct {
 	ChainName        string                   `json:"chain_name" db:"chain_name"`
@@ -84,6 +149,14 @@ type UnbondingDelegationEntry struct {
 	Balance        string    `json:"balance"`
 }
 
+// UnbondingBucket represents the unbonding amount completing on a single day
+type UnbondingBucket struct {
+	ChainName string    `json:"chain_name"`
+	Date      time.Time `json:"date"`
+	Amount    string    `json:"amount"`
+	Entries   int64     `json:"entries"`
+}
+
 // Redelegation represents a redelegation
 type Redelegation struct {
 	ChainName             string              `json:"chain_name" db:"chain_name"`
@@ -135,11 +208,136 @@ type Proposal struct {
 	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
 }
 
-// ProposalContent represents proposal content
+// ProposalContent represents proposal content. Type is the type of the
+// proposal's message when it has exactly one. What the messages change is
+// decoded into the typed fields, so that consumers need not parse them.
 type ProposalContent struct {
-	Type        string `json:"@type"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Type                string               `json:"@type"`
+	Title               string               `json:"title"`
+	Description         string               `json:"description"`
+	Metadata            string               `json:"metadata,omitempty"`
+	Messages            []ProposalMessage    `json:"messages,omitempty"`
+	ParamChanges        []ParamChange        `json:"param_changes,omitempty"`
+	SoftwareUpgrade     *SoftwareUpgrade     `json:"software_upgrade,omitempty"`
+	CommunityPoolSpends []CommunityPoolSpend `json:"community_pool_spends,omitempty"`
+	ClientUpdates       []ClientUpdate       `json:"client_updates,omitempty"`
+}
+
+// ProposalMessage is a message a proposal executes, or the content of a
+// legacy proposal. Value is the decoded message, omitted when its type is
+// unknown.
+type ProposalMessage struct {
+	Type  string          `json:"@type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ParamChange is a parameter a proposal sets. Value is JSON encoded.
+type ParamChange struct {
+	Subspace string `json:"subspace"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+}
+
+// SoftwareUpgrade is the upgrade plan of a software upgrade proposal
+type SoftwareUpgrade struct {
+	Name   string `json:"name"`
+	Height int64  `json:"height"`
+	Info   string `json:"info,omitempty"`
+}
+
+// CommunityPoolSpend is a community pool spend of a proposal
+type CommunityPoolSpend struct {
+	Recipient string `json:"recipient"`
+	Amount    []Coin `json:"amount"`
+}
+
+// ClientUpdate is an IBC client a proposal replaces with a substitute
+type ClientUpdate struct {
+	SubjectClientID    string `json:"subject_client_id"`
+	SubstituteClientID string `json:"substitute_client_id"`
+}
+
+// UpgradePlan is the software upgrade a chain's upgrade module has scheduled
+type UpgradePlan struct {
+	ChainName     string    `json:"chain_name" db:"chain_name"`
+	Name          string    `json:"name" db:"name"`
+	UpgradeHeight int64     `json:"upgrade_height" db:"upgrade_height"`
+	Info          string    `json:"info,omitempty" db:"info"`
+	Height        int64     `json:"height" db:"height"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Statuses of the upgrades in the upgrade calendar
+const (
+	UpgradeScheduled = "scheduled" // planned by the upgrade module
+	UpgradeProposed  = "proposed"  // in a proposal still in its voting period
+)
+
+// ChainUpgrade is an upcoming software upgrade of the upgrade calendar. The
+// chain halts at Height. EstimatedTime extrapolates the chain's recent
+// average block time from its latest block and is omitted when no recent
+// blocks are stored.
+type ChainUpgrade struct {
+	ChainName        string     `json:"chain_name"`
+	Name             string     `json:"name"`
+	Height           int64      `json:"height"`
+	Info             string     `json:"info,omitempty"`
+	Status           string     `json:"status"`
+	ProposalID       uint64     `json:"proposal_id,omitempty"`
+	VotingEndTime    *time.Time `json:"voting_end_time,omitempty"`
+	LatestHeight     int64      `json:"latest_height"`
+	BlocksRemaining  int64      `json:"blocks_remaining"`
+	BlockTimeSeconds float64    `json:"block_time_seconds,omitempty"`
+	EstimatedTime    *time.Time `json:"estimated_time,omitempty"`
+}
+
+// ChainParams are the parameters of a chain's module, each JSON encoded as the
+// chain's REST endpoints return it
+type ChainParams struct {
+	ChainName string                     `json:"chain_name" db:"chain_name"`
+	Module    string                     `json:"module" db:"module"`
+	Params    map[string]json.RawMessage `json:"params" db:"params"`
+	Height    int64                      `json:"height" db:"height"`
+	UpdatedAt time.Time                  `json:"updated_at" db:"updated_at"`
+}
+
+// ChainParamChange is a module parameter that changed between two ingested
+// snapshots. OldValue is omitted for parameters the change added and NewValue
+// for those it removed.
+type ChainParamChange struct {
+	ChainName string          `json:"chain_name" db:"chain_name"`
+	Module    string          `json:"module" db:"module"`
+	Param     string          `json:"param" db:"param"`
+	OldValue  json.RawMessage `json:"old_value,omitempty" db:"old_value"`
+	NewValue  json.RawMessage `json:"new_value,omitempty" db:"new_value"`
+	Height    int64           `json:"height" db:"height"`
+	ChangedAt time.Time       `json:"changed_at" db:"changed_at"`
+}
+
+// ParamComparison is a module parameter across chains, by chain. Chains whose
+// params lack it are left out; Differs reports whether the chains disagree on
+// it, including when some lack it.
+type ParamComparison struct {
+	Param   string                     `json:"param"`
+	Values  map[string]json.RawMessage `json:"values"`
+	Differs bool                       `json:"differs"`
+}
+
+// ParamMatrix is a module's parameters across chains, one row per parameter
+// with its value on each of Chains, in order
+type ParamMatrix struct {
+	Module string           `json:"module"`
+	Chains []string         `json:"chains"`
+	Rows   []ParamMatrixRow `json:"rows"`
+	Errors []ChainError     `json:"errors"`
+}
+
+// ParamMatrixRow is a parameter of a ParamMatrix. Its values are JSON encoded,
+// nil for the chains whose params lack it or are not ingested yet.
+type ParamMatrixRow struct {
+	Param   string    `json:"param"`
+	Values  []*string `json:"values"`
+	Differs bool      `json:"differs"`
 }
 
 // TallyResult represents proposal tally result
@@ -164,6 +362,7 @@ type Vote struct {
 
 // BalanceEvent represents a balance change event
 type BalanceEvent struct {
+	EventID        string    `json:"event_id,omitempty"` // deterministic, identifies redeliveries
 	Timestamp      time.Time `json:"timestamp"`
 	ChainName      string    `json:"chain_name"`
 	Address        string    `json:"address"`
@@ -175,8 +374,19 @@ type BalanceEvent struct {
 	TxHash         string    `json:"tx_hash"`
 }
 
+// BalancePoint is a point of an address's balance history in a denom: the
+// balance after a change, or the last balance of an hour or day, timestamped
+// with its start. Downsampled points have no transaction.
+type BalancePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Amount    string    `json:"amount"`
+	Height    int64     `json:"height"`
+	TxHash    string    `json:"tx_hash,omitempty"`
+}
+
 // DelegationEvent represents a delegation change event
 type DelegationEvent struct {
+	EventID         string    `json:"event_id,omitempty"` // deterministic, identifies redeliveries
 	Timestamp       time.Time `json:"timestamp"`
 	ChainName       string    `json:"chain_name"`
 	DelegatorAddress string   `json:"delegator_address"`
@@ -188,22 +398,564 @@ type DelegationEvent struct {
 	TxHash          string    `json:"tx_hash"`
 }
 
+// RedelegationEvent records stake moved from one validator to another
+type RedelegationEvent struct {
+	EventID             string    `json:"event_id,omitempty"` // deterministic, identifies redeliveries
+	Timestamp           time.Time `json:"timestamp"`
+	ChainName           string    `json:"chain_name"`
+	DelegatorAddress    string    `json:"delegator_address"`
+	SrcValidatorAddress string    `json:"src_validator_address"`
+	DstValidatorAddress string    `json:"dst_validator_address"`
+	Amount              string    `json:"amount"` // tokens moved
+	Height              int64     `json:"height"`
+}
+
+// ValidatorSetMember is a bonded validator in a validator set snapshot
+type ValidatorSetMember struct {
+	OperatorAddress  string `json:"operator_address"`
+	ConsensusAddress string `json:"consensus_address"`
+	Moniker          string `json:"moniker"`
+	Tokens           string `json:"tokens"`
+	VotingPower      int64  `json:"voting_power"`
+}
+
+// ValidatorSetSnapshot is the bonded validator set of a chain at a height,
+// ordered by voting power
+type ValidatorSetSnapshot struct {
+	ChainName        string               `json:"chain_name"`
+	Height           int64                `json:"height"`
+	Timestamp        time.Time            `json:"timestamp"`
+	TotalVotingPower int64                `json:"total_voting_power"`
+	Validators       []ValidatorSetMember `json:"validators"`
+}
+
+// RewardEvent represents a staking reward payout event
+type RewardEvent struct {
+	Timestamp        time.Time `json:"timestamp"`
+	ChainName        string    `json:"chain_name"`
+	DelegatorAddress string    `json:"delegator_address"`
+	ValidatorAddress string    `json:"validator_address"`
+	Denom            string    `json:"denom"`
+	Amount           string    `json:"amount"`
+	Height           int64     `json:"height"`
+	TxHash           string    `json:"tx_hash"`
+	Claimed          bool      `json:"claimed"`    // paid by MsgWithdrawDelegatorReward rather than a delegation change
+	Compounded       bool      `json:"compounded"` // claimed and delegated again within the block
+}
+
+// DelegatorRewards summarizes the rewards a delegator withdrew on a chain, as
+// indexed from its transactions. AutoCompounding flags a delegator most of
+// whose claims were delegated again within the block, as auto-compounding
+// services do.
+type DelegatorRewards struct {
+	ChainName        string                 `json:"chain_name"`
+	DelegatorAddress string                 `json:"delegator_address"`
+	Totals           []DelegatorRewardTotal `json:"totals"`
+	Claims           uint64                 `json:"claims"`            // blocks with a claim
+	CompoundedClaims uint64                 `json:"compounded_claims"` // blocks with a compounded claim
+	AutoCompounding  bool                   `json:"auto_compounding"`
+	LastClaimHeight  int64                  `json:"last_claim_height,omitempty"`
+	LastClaimAt      *time.Time             `json:"last_claim_at,omitempty"`
+}
+
+// DelegatorRewardTotal is the cumulative amount of a denom a delegator
+// withdrew, of which Claimed was claimed and Compounded delegated again
+type DelegatorRewardTotal struct {
+	Denom      string `json:"denom"`
+	Withdrawn  string `json:"withdrawn"`
+	Claimed    string `json:"claimed"`
+	Compounded string `json:"compounded"`
+}
+
 // ChainStats represents aggregated chain statistics
 type ChainStats struct {
-	ChainName       string `json:"chain_name"`
-	TotalValidators int64  `json:"total_validators"`
-	ActiveValidators int64 `json:"active_validators"`
-	TotalDelegated  string `json:"total_delegated"`
-	TotalSupply     string `json:"total_supply"`
-	InflationRate   string `json:"inflation_rate"`
+	ChainName        string         `json:"chain_name"`
+	TotalValidators  int64          `json:"total_validators"`
+	ActiveValidators int64          `json:"active_validators"`
+	BondedTokens     string         `json:"bonded_tokens"`
+	BondedRatio      string         `json:"bonded_ratio"`
+	BondDenom        string         `json:"bond_denom"`
+	TotalSupply      []Coin         `json:"total_supply"`
+	AccountCount     int64          `json:"account_count"`
+	InflationRate    string         `json:"inflation_rate"`
+	Activity         *ChainActivity `json:"activity,omitempty"`
+	Anomalies        []Anomaly      `json:"anomalies,omitempty"` // detected over the last day
+	Halts            []ChainHalt    `json:"halts,omitempty"`     // ongoing or ended over the last day
+}
+
+// ChainActivity represents chain activity over the last complete day
+type ChainActivity struct {
+	Date            time.Time `json:"date"`
+	ActiveAddresses uint64    `json:"active_addresses"`
+	Delegated       float64   `json:"delegated"`
+	Undelegated     float64   `json:"undelegated"`
+}
+
+// Chain metrics scored for anomalies, per hour
+const (
+	MetricDelegationOutflow = "delegation_outflow" // shares undelegated or redelegated away
+	MetricSupplyChange      = "supply_change"      // change of the staking denom's total supply
+	MetricEventRate         = "event_rate"         // balance and delegation events
+)
+
+// AnomalyMetrics lists the metrics scored for anomalies
+var AnomalyMetrics = []string{MetricDelegationOutflow, MetricSupplyChange, MetricEventRate}
+
+// MetricPoint represents the value of a chain metric over the hour starting
+// at Time
+type MetricPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Charted metrics, as time series over a range of time
+const (
+	SeriesIngestLag       = "ingest_lag"       // seconds between a block's time and its ingestion
+	SeriesDelegationFlows = "delegation_flows" // shares delegated, undelegated and redelegated
+	SeriesSupply          = "supply"           // total and circulating supply of a denom
+)
+
+// SeriesMetrics lists the charted metrics
+var SeriesMetrics = []string{SeriesIngestLag, SeriesDelegationFlows, SeriesSupply}
+
+// TimeSeries represents the values of one series of a charted metric, by the
+// start of each interval. Intervals without data are left out.
+type TimeSeries struct {
+	Name   string        `json:"name"`
+	Points []MetricPoint `json:"points"`
+}
+
+// Anomaly represents an hour in which a chain metric deviated from its
+// exponentially weighted moving average by more than the configured number of
+// standard deviations
+type Anomaly struct {
+	ChainName  string    `json:"chain_name"`
+	Metric     string    `json:"metric"`
+	Hour       time.Time `json:"hour"`
+	Value      float64   `json:"value"`
+	Expected   float64   `json:"expected"` // the moving average before the hour
+	StdDev     float64   `json:"std_dev"`
+	ZScore     float64   `json:"z_score"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// ChainHalt represents a period in which a chain's latest height did not
+// increase for longer than the halt threshold. Since is the time of the last
+// block before the halt; ResumedAt is nil while the chain is halted.
+type ChainHalt struct {
+	ChainName  string     `json:"chain_name"`
+	Height     int64      `json:"height"` // the last height before the halt
+	Since      time.Time  `json:"since"`
+	DetectedAt time.Time  `json:"detected_at"`
+	ResumedAt  *time.Time `json:"resumed_at,omitempty"`
+}
+
+// AnomalyAlert is the webhook payload notifying a tenant of an anomaly
+type AnomalyAlert struct {
+	TenantID string    `json:"tenant_id"`
+	Anomaly  Anomaly   `json:"anomaly"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+// AnalyticsResult represents the rows an analytics query returned, each a
+// list of values in column order
+type AnalyticsResult struct {
+	Columns   []AnalyticsColumn `json:"columns"`
+	Rows      [][]any           `json:"rows"`
+	Truncated bool              `json:"truncated"` // more rows than the configured maximum
+}
+
+// AnalyticsColumn represents a column of an analytics query result with its
+// ClickHouse type
+type AnalyticsColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// SQLView represents a view of the api schema offered to external GraphQL
+// engines and BI tools
+type SQLView struct {
+	Schema      string          `json:"schema"`
+	Name        string          `json:"name"`
+	Kind        string          `json:"kind"` // latest or history
+	Description string          `json:"description"`
+	PrimaryKey  []string        `json:"primary_key"`
+	Migration   string          `json:"migration"`
+	Columns     []SQLViewColumn `json:"columns"`
+}
+
+// SQLViewColumn represents a column of an api view with its PostgreSQL type
+type SQLViewColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// Supply represents the total supply of a denom
+type Supply struct {
+	ChainName string    `json:"chain_name" db:"chain_name"`
+	Denom     string    `json:"denom" db:"denom"`
+	Amount    string    `json:"amount" db:"amount"`
+	Height    int64     `json:"height" db:"height"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PoolBalance is the amount of a denom in the community pool, a decimal
+type PoolBalance struct {
+	ChainName string    `json:"chain_name"`
+	Denom     string    `json:"denom"`
+	Amount    string    `json:"amount"`
+	Height    int64     `json:"height"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ModuleAccount is an account owned by a module, such as the bonded tokens
+// pool or the distribution module's escrow
+type ModuleAccount struct {
+	ChainName   string    `json:"chain_name"`
+	Name        string    `json:"name"`
+	Address     string    `json:"address"`
+	Permissions []string  `json:"permissions"`
+	Height      int64     `json:"height"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ModuleBalance is the balance of a denom held by a module account
+type ModuleBalance struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+}
+
+// SupplyBreakdown splits the total supply of a denom by where it is held. The
+// distribution module account holds the community pool and the rewards not
+// yet withdrawn; module escrow is every other module account. Circulating is
+// what remains, held by ordinary accounts.
+type SupplyBreakdown struct {
+	ChainName        string          `json:"chain_name"`
+	Denom            string          `json:"denom"`
+	Total            string          `json:"total"`
+	Bonded           string          `json:"bonded"`
+	Unbonding        string          `json:"unbonding"`
+	CommunityPool    string          `json:"community_pool"`
+	UnclaimedRewards string          `json:"unclaimed_rewards"`
+	ModuleEscrow     string          `json:"module_escrow"`
+	Circulating      string          `json:"circulating"`
+	Modules          []ModuleBalance `json:"modules"`
+	Height           int64           `json:"height"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// VestingLocked is the amount of a denom still locked in a vesting account,
+// less what the account has delegated
+type VestingLocked struct {
+	ChainName string    `json:"chain_name"`
+	Address   string    `json:"address"`
+	Type      string    `json:"type"`
+	Denom     string    `json:"denom"`
+	Amount    string    `json:"amount"`
+	EndTime   time.Time `json:"end_time"`
+	Height    int64     `json:"height"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CirculatingSupply is the total supply of a denom less the amounts locked in
+// vesting accounts, held by module accounts and held by configured treasury
+// addresses
+type CirculatingSupply struct {
+	ChainName      string    `json:"chain_name"`
+	Denom          string    `json:"denom"`
+	Total          string    `json:"total"`
+	VestingLocked  string    `json:"vesting_locked"`
+	ModuleAccounts string    `json:"module_accounts"`
+	Treasury       string    `json:"treasury"`
+	Circulating    string    `json:"circulating"`
+	Height         int64     `json:"height"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// MintParams represents mint module parameters and current minter state
+type MintParams struct {
+	ChainName           string    `json:"chain_name" db:"chain_name"`
+	MintDenom           string    `json:"mint_denom" db:"mint_denom"`
+	InflationRateChange string    `json:"inflation_rate_change" db:"inflation_rate_change"`
+	InflationMax        string    `json:"inflation_max" db:"inflation_max"`
+	InflationMin        string    `json:"inflation_min" db:"inflation_min"`
+	GoalBonded          string    `json:"goal_bonded" db:"goal_bonded"`
+	BlocksPerYear       uint64    `json:"blocks_per_year" db:"blocks_per_year"`
+	CurrentInflation    string    `json:"current_inflation" db:"current_inflation"`
+	AnnualProvisions    string    `json:"annual_provisions" db:"annual_provisions"`
+	Height              int64     `json:"height" db:"height"`
+	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DailyActiveAddresses represents the distinct active addresses on a chain for one day
+type DailyActiveAddresses struct {
+	ChainName       string    `json:"chain_name"`
+	Date            time.Time `json:"date"`
+	ActiveAddresses uint64    `json:"active_addresses"`
+}
+
+// DailyDelegationVolume represents delegation inflows and outflows on a chain for one day
+type DailyDelegationVolume struct {
+	ChainName   string    `json:"chain_name"`
+	Date        time.Time `json:"date"`
+	Delegated   float64   `json:"delegated"`
+	Undelegated float64   `json:"undelegated"`
+	Events      uint64    `json:"events"`
+}
+
+// DailyRewardIssuance represents staking rewards paid out on a chain for one day
+type DailyRewardIssuance struct {
+	ChainName string    `json:"chain_name"`
+	Date      time.Time `json:"date"`
+	Denom     string    `json:"denom"`
+	Amount    float64   `json:"amount"`
+	Events    uint64    `json:"events"`
+}
+
+// Transaction is an indexed transaction with its result, gas and fee. A fee
+// paid in several denoms keeps only its first coin.
+type Transaction struct {
+	ChainName    string    `json:"chain_name"`
+	Height       int64     `json:"height"`
+	Hash         string    `json:"hash"`
+	Code         uint32    `json:"code"`
+	GasWanted    int64     `json:"gas_wanted"`
+	GasUsed      int64     `json:"gas_used"`
+	FeeDenom     string    `json:"fee_denom"`
+	FeeAmount    string    `json:"fee_amount"`
+	FeePayer     string    `json:"fee_payer"`
+	MessageTypes []string  `json:"message_types"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// FeeStats aggregates the fees and gas of a set of transactions in one fee
+// denom. All fees end up with validators and delegators, less community tax.
+type FeeStats struct {
+	Denom      string  `json:"denom"`
+	Txs        uint64  `json:"txs"`
+	FailedTxs  uint64  `json:"failed_txs"`
+	Fees       float64 `json:"fees"`
+	AverageFee float64 `json:"average_fee"`
+	GasWanted  uint64  `json:"gas_wanted"`
+	GasUsed    uint64  `json:"gas_used"`
+}
+
+// DailyFeeStats represents the fees and gas of a chain's transactions for one day
+type DailyFeeStats struct {
+	ChainName string    `json:"chain_name"`
+	Date      time.Time `json:"date"`
+	FeeStats
+}
+
+// Epoch is the start of an epoch of a chain that processes most state
+// changes at epoch boundaries. The chain processes the previous epoch's changes
+// in the epoch's first block, at StartHeight.
+type Epoch struct {
+	ChainName       string    `json:"chain_name"`
+	Identifier      string    `json:"identifier"`
+	Number          int64     `json:"number"`
+	StartHeight     int64     `json:"start_height"`
+	StartTime       time.Time `json:"start_time"`
+	DurationSeconds int64     `json:"duration_seconds"`
+}
+
+// EpochFeeStats represents the fees and gas of a chain's transactions for one
+// epoch
+type EpochFeeStats struct {
+	Epoch       int64     `json:"epoch"`
+	StartHeight int64     `json:"start_height"`
+	StartTime   time.Time `json:"start_time"`
+	FeeStats
+}
+
+// BaseFee is the base fee of a chain's fee market in a denom at a height, the
+// minimum price of a unit of gas
+type BaseFee struct {
+	ChainName string    `json:"chain_name"`
+	Denom     string    `json:"denom"`
+	Amount    string    `json:"amount"`
+	Height    int64     `json:"height"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// BaseFeeStats represents the base fee of a denom over an hour
+type BaseFeeStats struct {
+	Time    time.Time `json:"time"`
+	Denom   string    `json:"denom"`
+	Min     string    `json:"min"`
+	Average string    `json:"average"`
+	Max     string    `json:"max"`
+	Samples uint64    `json:"samples"`
+}
+
+// GasPriceEstimate recommends gas prices in a denom from a chain's base fee:
+// Low is the current base fee, Average and High its mean and 90th percentile
+// over a recent window, never below the current base fee
+type GasPriceEstimate struct {
+	ChainName string    `json:"chain_name"`
+	Denom     string    `json:"denom"`
+	BaseFee   string    `json:"base_fee"`
+	Low       string    `json:"low"`
+	Average   string    `json:"average"`
+	High      string    `json:"high"`
+	Height    int64     `json:"height"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MessageTypeFeeStats represents the fees and gas of the transactions whose
+// first message is of a type
+type MessageTypeFeeStats struct {
+	MessageType string `json:"message_type"`
+	FeeStats
+}
+
+// FeePayer represents an address by the fees it paid
+type FeePayer struct {
+	Address string        `json:"address"`
+	Denom   string        `json:"denom"`
+	Fees    float64       `json:"fees"`
+	Txs     uint64        `json:"txs"`
+	Label   *AddressLabel `json:"label,omitempty"`
+}
+
+// DailyMessageActivity represents the messages of one type included in a
+// chain's transactions for one day
+type DailyMessageActivity struct {
+	ChainName      string    `json:"chain_name"`
+	Date           time.Time `json:"date"`
+	MessageType    string    `json:"message_type"`
+	Messages       uint64    `json:"messages"`
+	FailedMessages uint64    `json:"failed_messages"`
+}
+
+// MessageActivity represents the messages of one type over a period and
+// their share of all messages
+type MessageActivity struct {
+	MessageType    string  `json:"message_type"`
+	Messages       uint64  `json:"messages"`
+	FailedMessages uint64  `json:"failed_messages"`
+	Share          float64 `json:"share"`
+}
+
+// PacketEvent is an IBC packet event emitted on a chain: a packet sent,
+// received, acknowledged or timed out. Relayer is the fee payer of the
+// transaction, the sender for sent packets.
+type PacketEvent struct {
+	ChainName  string    `json:"chain_name"`
+	Type       string    `json:"type"`
+	Sequence   uint64    `json:"sequence"`
+	SrcPort    string    `json:"src_port"`
+	SrcChannel string    `json:"src_channel"`
+	DstPort    string    `json:"dst_port"`
+	DstChannel string    `json:"dst_channel"`
+	Relayer    string    `json:"relayer"`
+	TxHash     string    `json:"tx_hash"`
+	Height     int64     `json:"height"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// ChannelPerformance summarizes the packets a chain sent over a channel: how
+// many were acknowledged or timed out, how long acknowledgements took and how
+// many are stuck without either
+type ChannelPerformance struct {
+	Port                string  `json:"port"`
+	Channel             string  `json:"channel"`
+	CounterpartyChannel string  `json:"counterparty_channel"`
+	Sent                uint64  `json:"sent"`
+	Acknowledged        uint64  `json:"acknowledged"`
+	TimedOut            uint64  `json:"timed_out"`
+	Stuck               uint64  `json:"stuck"`
+	AvgLatencySeconds   float64 `json:"avg_latency_seconds"`
+	P50LatencySeconds   float64 `json:"p50_latency_seconds"`
+	P95LatencySeconds   float64 `json:"p95_latency_seconds"`
+}
+
+// StuckPacket is a packet sent by a chain that was neither acknowledged nor
+// timed out
+type StuckPacket struct {
+	Port       string    `json:"port"`
+	Channel    string    `json:"channel"`
+	Sequence   uint64    `json:"sequence"`
+	Sender     string    `json:"sender"`
+	TxHash     string    `json:"tx_hash"`
+	Height     int64     `json:"height"`
+	SentAt     time.Time `json:"sent_at"`
+	AgeSeconds float64   `json:"age_seconds"`
+}
+
+// RelayerStats counts the packets a relayer delivered to or acknowledged on a
+// chain
+type RelayerStats struct {
+	Address       string        `json:"address"`
+	Received      uint64        `json:"received"`
+	Acknowledged  uint64        `json:"acknowledged"`
+	TimedOut      uint64        `json:"timed_out"`
+	Total         uint64        `json:"total"`
+	Channels      uint64        `json:"channels"`
+	LastRelayedAt time.Time     `json:"last_relayed_at"`
+	Label         *AddressLabel `json:"label,omitempty"`
 }
 
 // TokenHolder represents a token holder for analytics
 type TokenHolder struct {
-	ChainName string `json:"chain_name"`
-	Address   string `json:"address"`
-	Denom     string `json:"denom"`
-	Amount    string `json:"amount"`
+	ChainName string        `json:"chain_name"`
+	Address   string        `json:"address"`
+	Denom     string        `json:"denom"`
+	Amount    string        `json:"amount"`
+	Label     *AddressLabel `json:"label,omitempty"`
+}
+
+// Snapshot kinds
+const (
+	SnapshotHolders    = "holders"
+	SnapshotDelegators = "delegators"
+)
+
+// SnapshotFilter selects the accounts of a holder or delegator snapshot.
+// Delegator snapshots total delegated tokens, so Denom only applies to holders.
+type SnapshotFilter struct {
+	ChainName        string
+	Kind             string
+	Denom            string
+	Height           int64
+	MinAmount        string   // smallest amount included, "" for any non-zero
+	ExcludeLabels    []string // label categories left out
+	ExcludeAddresses []string
+}
+
+// SnapshotEntry is an account's amount in a holder or delegator snapshot
+type SnapshotEntry struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+}
+
+// BalanceReconstruction is an account's balances at a past time, folded from
+// its balance events and cross-checked against the balance history of the
+// state store as of Height, the chain's height at that time
+type BalanceReconstruction struct {
+	ChainName       string                 `json:"chain_name"`
+	Address         string                 `json:"address"`
+	Time            time.Time              `json:"time"`
+	Height          int64                  `json:"height"`
+	SnapshotChecked bool                   `json:"snapshot_checked"`
+	Balances        []ReconstructedBalance `json:"balances"`
+	Discrepancies   int                    `json:"discrepancies"`
+}
+
+// ReconstructedBalance is the balance of a denom folded from Events balance
+// events, the last at EventHeight, next to the balance history's amount.
+// Difference is the history's amount less the folded one, omitted when they
+// agree.
+type ReconstructedBalance struct {
+	Denom          string `json:"denom"`
+	Amount         string `json:"amount"`
+	Events         int    `json:"events"`
+	EventHeight    int64  `json:"event_height,omitempty"`
+	SnapshotAmount string `json:"snapshot_amount,omitempty"`
+	SnapshotHeight int64  `json:"snapshot_height,omitempty"`
+	Difference     string `json:"difference,omitempty"`
 }
 
 // StateChange represents a generic state change from ADR-038
@@ -217,41 +969,622 @@ type StateChange struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
-// AccountState represents unified account state across modules
+// ArchivedStateChange is a raw state change kept in the archive. Sequence
+// numbers changes in the order the listener received them.
+type ArchivedStateChange struct {
+	StateChange
+	Sequence int64 `json:"sequence"`
+}
+
+// StateArchiveFilter selects archived state changes to decode again. Zero
+// values leave a bound open.
+type StateArchiveFilter struct {
+	ChainName  string
+	Stores     []string // empty means all
+	FromHeight int64
+	ToHeight   int64
+}
+
+// DecodedStateChange is a change to a store without a built-in module, decoded
+// by the rules configured for the store into a generic key/value record
+type DecodedStateChange struct {
+	ChainName string    `json:"chain_name"`
+	StoreKey  string    `json:"store_key"`
+	Entity    string    `json:"entity"`         // entity type of the matching rule, empty when none matched
+	Key       string    `json:"key"`            // the key, or 0x and its hex encoding when not printable
+	Value     string    `json:"value"`          // the value, encoded as Encoding says
+	Encoding  string    `json:"value_encoding"` // "json" when decoded from protobuf, "hex" otherwise
+	Delete    bool      `json:"delete"`
+	Height    int64     `json:"height"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AccountState is the canonical state of an account on one chain, which the
+// REST and GraphQL APIs return: its balances, token holdings, delegations,
+// pending unbonding and redelegation entries and the rewards it withdrew from
+// each validator. Lists are empty, never nil, when the account has none.
 type AccountState struct {
-	ChainName    string                `json:"chain_name"`
-	Address      string                `json:"address"`
-	Balances     []Balance             `json:"balances"`
-	Delegations  []Delegation          `json:"delegations"`
-	Unbonding    []UnbondingDelegation `json:"unbonding"`
-	Redelegations []Redelegation       `json:"redelegations"`
-	Rewards      []Reward              `json:"rewards"`
-	UpdatedAt    time.Time             `json:"updated_at"`
+	ChainName     string                `json:"chain_name"`
+	Address       string                `json:"address"`
+	Balances      []Balance             `json:"balances"`
+	Delegations   []Delegation          `json:"delegations"`
+	Unbonding     []UnbondingDelegation `json:"unbonding"`
+	Redelegations []Redelegation        `json:"redelegations"`
+	Rewards       []Reward              `json:"rewards"`
+	Tokens        []TokenHolding        `json:"tokens"`
+	Label         *AddressLabel         `json:"label,omitempty"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
+
+// ChainError reports a chain that failed during a cross-chain query
+type ChainError struct {
+	ChainName string `json:"chain_name"`
+	Error     string `json:"error"`
+}
+
+// Chain health values of the network overview
+const (
+	ChainHealthy    = "healthy"
+	ChainCatchingUp = "catching_up" // the node is syncing
+	ChainLagging    = "lagging"     // ingestion is behind the node
+	ChainHalted     = "halted"      // the height stopped increasing
+	ChainStale      = "stale"       // no recent block
+	ChainUnknown    = "unknown"     // not ingested yet, or its state failed to load
+)
+
+// NetworkOverview summarizes every configured chain. The staked value covers
+// the chains with a price.
+type NetworkOverview struct {
+	TotalChains    int             `json:"total_chains"`
+	HealthyChains  int             `json:"healthy_chains"`
+	TotalAccounts  int64           `json:"total_accounts"`
+	StakedValueUSD float64         `json:"staked_value_usd"`
+	PricedChains   int             `json:"priced_chains"`
+	EventsPerDay   uint64          `json:"events_per_day"`
+	Chains         []ChainOverview `json:"chains"`
+	Errors         []ChainError    `json:"errors,omitempty"`
+	UpdatedAt      time.Time       `json:"updated_at"`
 }
 
-// CrossChainAccountState represents account state across multiple chains
-type CrossChainAccountState struct {
-	Address   string                   `json:"address"`
-	Chains    map[string]AccountState  `json:"chains"`
-	Totals    CrossChainTotals         `json:"totals"`
-	UpdatedAt time.Time                `json:"updated_at"`
+// ChainOverview summarizes a chain in the network overview. Events per day
+// count the balance, delegation and redelegation events of the last 24 hours.
+type ChainOverview struct {
+	ChainName          string     `json:"chain_name"`
+	ChainID            string     `json:"chain_id"`
+	Health             string     `json:"health"`
+	LatestHeight       int64      `json:"latest_height"`
+	LatestBlockTime    time.Time  `json:"latest_block_time"`
+	LastIngestedHeight int64      `json:"last_ingested_height"`
+	CatchingUp         bool       `json:"catching_up"`
+	HaltedSince        *time.Time `json:"halted_since,omitempty"`
+	BondDenom          string     `json:"bond_denom"`
+	BondedTokens       string     `json:"bonded_tokens"`
+	PriceUSD           *float64   `json:"price_usd,omitempty"`
+	StakedValueUSD     *float64   `json:"staked_value_usd,omitempty"`
+	AccountCount       int64      `json:"account_count"`
+	EventsPerDay       uint64     `json:"events_per_day"`
 }
 
-// CrossChainTotals represents aggregated totals across chains
-type CrossChainTotals struct {
-	TotalBalance    map[string]string `json:"total_balance"`    // denom -> total amount
-	TotalDelegated  map[string]string `json:"total_delegated"`  // denom -> total delegated
-	TotalUnbonding  map[string]string `json:"total_unbonding"`  // denom -> total unbonding
-	TotalRewards    map[string]string `json:"total_rewards"`    // denom -> total rewards
+// CrossChainAccount is the state of an account across chains, with its
+// totals per chain and denom. Totals keep each chain's denoms apart: the same
+// denom may name different assets on different chains.
+type CrossChainAccount struct {
+	Address   string            `json:"address"`
+	Chains    []AccountState    `json:"chains"` // in the order the chains were requested
+	Totals    []CrossChainTotal `json:"totals"`
+	Errors    []ChainError      `json:"errors,omitempty"`
+	UpdatedAt time.Time         `json:"updated_at"`
 }
 
+// CrossChainTotal totals an account's holdings of one denom on one chain.
+// Amounts are in the denom's base unit; display amounts are set when the
+// denom's exponent is configured.
+type CrossChainTotal struct {
+	ChainName        string `json:"chain_name"`
+	Denom            string `json:"denom"`
+	Balance          string `json:"balance"`
+	Delegated        string `json:"delegated"`
+	Unbonding        string `json:"unbonding"`
+	Redelegating     string `json:"redelegating"` // part of Delegated moving between validators
+	Rewards          string `json:"rewards"`
+	DisplayBalance   string `json:"display_balance,omitempty"`
+	DisplayDelegated string `json:"display_delegated,omitempty"`
+}
+
+// Chain status values
+const (
+	ChainStatusActive      = "active"
+	ChainStatusUnreachable = "unreachable"
+	ChainStatusDegraded    = "degraded" // reachable, but not producing blocks
+	ChainStatusDisabled    = "disabled"
+)
+
 // ChainInfo represents chain information
 type ChainInfo struct {
-	Name         string    `json:"name"`
-	ChainID      string    `json:"chain_id"`
-	Status       string    `json:"status"`
-	LatestHeight int64     `json:"latest_height"`
-	LatestTime   time.Time `json:"latest_time"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	Name               string     `json:"name"`
+	ChainID            string     `json:"chain_id"`
+	Status             string     `json:"status"`
+	LatestHeight       int64      `json:"latest_height"`
+	LatestTime         time.Time  `json:"latest_time"`
+	LastIngestedHeight int64      `json:"last_ingested_height"`
+	CatchingUp         bool       `json:"catching_up"`
+	NodeVersion        string     `json:"node_version"`
+	AppVersion         string     `json:"app_version"`
+	HaltedSince        *time.Time `json:"halted_since,omitempty"` // the time of the last block of a halted chain
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// Block represents a block header
+type Block struct {
+	ChainName       string    `json:"chain_name" db:"chain_name"`
+	Height          int64     `json:"height" db:"height"`
+	Hash            string    `json:"hash" db:"hash"`
+	ProposerAddress string    `json:"proposer_address" db:"proposer_address"`
+	TxCount         int       `json:"tx_count" db:"tx_count"`
+	Time            time.Time `json:"time" db:"time"`
+}
+
+// BlockSignature records whether a validator signed a block
+type BlockSignature struct {
+	ChainName        string    `json:"chain_name"`
+	Height           int64     `json:"height"`
+	ConsensusAddress string    `json:"consensus_address"`
+	Signed           bool      `json:"signed"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// ValidatorBlockProduction summarizes a validator's proposed and signed blocks
+// over a time window
+type ValidatorBlockProduction struct {
+	ConsensusAddress string  `json:"consensus_address"`
+	OperatorAddress  string  `json:"operator_address"`
+	Moniker          string  `json:"moniker"`
+	Proposed         uint64  `json:"proposed"`
+	ExpectedProposed float64 `json:"expected_proposed"` // by share of bonded tokens
+	Signed           uint64  `json:"signed"`
+	Missed           uint64  `json:"missed"`
+	MissedStreak     uint64  `json:"missed_streak"` // consecutive misses up to the latest block
+}
+
+// DelegationFlow is the stake redelegated from one validator to another over
+// a time window
+type DelegationFlow struct {
+	SrcValidatorAddress string `json:"src_validator_address"`
+	SrcMoniker          string `json:"src_moniker"`
+	DstValidatorAddress string `json:"dst_validator_address"`
+	DstMoniker          string `json:"dst_moniker"`
+	Amount              string `json:"amount"`
+	Redelegations       uint64 `json:"redelegations"`
+	Delegators          uint64 `json:"delegators"` // distinct delegators moving stake
+}
+
+// ValidatorStakeFlow is the stake a validator gained and lost through
+// redelegations over a time window
+type ValidatorStakeFlow struct {
+	ValidatorAddress string `json:"validator_address"`
+	Moniker          string `json:"moniker"`
+	Inflow           string `json:"inflow"`
+	Outflow          string `json:"outflow"`
+	Net              string `json:"net"` // inflow minus outflow
+}
+
+// DelegationFlowGraph is the redelegation graph of a chain over a time window:
+// the largest flows between validators and the validators gaining and losing
+// the most stake
+type DelegationFlowGraph struct {
+	ChainName string               `json:"chain_name"`
+	From      time.Time            `json:"from"`
+	To        time.Time            `json:"to"`
+	Total     string               `json:"total"` // stake redelegated in the window
+	Flows     []DelegationFlow     `json:"flows"`
+	Gainers   []ValidatorStakeFlow `json:"gainers"`
+	Losers    []ValidatorStakeFlow `json:"losers"`
+}
+
+// CosmWasm token standards
+const (
+	TokenStandardCW20  = "cw20"
+	TokenStandardCW721 = "cw721"
+)
+
+// TokenContract is a CosmWasm token contract with the metadata it reports
+type TokenContract struct {
+	ChainName string    `json:"chain_name"`
+	Contract  string    `json:"contract"`
+	Standard  string    `json:"standard"`
+	Name      string    `json:"name"`
+	Symbol    string    `json:"symbol"`
+	Decimals  int       `json:"decimals"` // 0 for CW721
+	Height    int64     `json:"height"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TokenHolding is an account's CW20 balance or the CW721 tokens it owns, with
+// the metadata of the contract
+type TokenHolding struct {
+	ChainName string    `json:"chain_name"`
+	Address   string    `json:"address"`
+	Contract  string    `json:"contract"`
+	Standard  string    `json:"standard"`
+	Name      string    `json:"name"`
+	Symbol    string    `json:"symbol"`
+	Decimals  int       `json:"decimals"`
+	Amount    string    `json:"amount"` // balance, or number of tokens for CW721
+	TokenIDs  []string  `json:"token_ids,omitempty"`
+	Height    int64     `json:"height"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ConsumerChain is an Interchain Security consumer chain secured by the
+// validators of a provider chain
+type ConsumerChain struct {
+	ProviderChain string              `json:"provider_chain" db:"provider_chain"` // configured name of the provider
+	ChainID       string              `json:"chain_id" db:"chain_id"`
+	ConsumerID    string              `json:"consumer_id,omitempty" db:"consumer_id"` // set from ICS v6
+	ClientID      string              `json:"client_id" db:"client_id"`
+	Validators    []ConsumerValidator `json:"validators"`
+	Height        int64               `json:"height" db:"height"`
+	UpdatedAt     time.Time           `json:"updated_at" db:"updated_at"`
 }
 
+// ConsumerValidator is a provider validator in a consumer chain's validator set
+type ConsumerValidator struct {
+	ProviderAddress string `json:"provider_address" db:"provider_address"` // consensus address on the provider
+
+	// ConsumerAddress is the consensus address of the key assigned for the
+	// consumer chain, empty when the validator signs with its provider key
+	ConsumerAddress string `json:"consumer_address,omitempty" db:"consumer_address"`
+}
+
+// ConsumerValidatorSet is the validator set of a consumer chain joined to the
+// provider validators backing it
+type ConsumerValidatorSet struct {
+	ProviderChain string             `json:"provider_chain"`
+	ChainID       string             `json:"chain_id"`
+	ConsumerID    string             `json:"consumer_id,omitempty"`
+	Validators    []BackingValidator `json:"validators"`
+	StakeAtRisk   string             `json:"stake_at_risk"` // tokens of the bonded backing validators
+	Height        int64              `json:"height"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// BackingValidator is a consumer chain validator with the provider validator
+// running it, nil when the provider has not ingested it
+type BackingValidator struct {
+	ConsumerValidator
+	Validator *Validator `json:"provider_validator"`
+}
+
+// ChainStatus represents the sync status of a chain's node and ingester
+type ChainStatus struct {
+	ChainName          string     `json:"chain_name" db:"chain_name"`
+	LatestHeight       int64      `json:"latest_height" db:"latest_height"`
+	LatestBlockTime    time.Time  `json:"latest_block_time" db:"latest_block_time"`
+	LastIngestedHeight int64      `json:"last_ingested_height" db:"last_ingested_height"`
+	CatchingUp         bool       `json:"catching_up" db:"catching_up"`
+	NodeVersion        string     `json:"node_version" db:"node_version"`
+	AppVersion         string     `json:"app_version" db:"app_version"`
+	HaltedSince        *time.Time `json:"halted_since,omitempty" db:"halted_since"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IngestRun summarizes one ingest cycle of a chain: the modules polled, the
+// rows they wrote to the state store, the gRPC calls made and any errors
+type IngestRun struct {
+	ID          int64       `json:"id"`
+	ChainName   string      `json:"chain_name"`
+	Height      int64       `json:"height"`
+	StartedAt   time.Time   `json:"started_at"`
+	DurationMs  int64       `json:"duration_ms"`
+	RowsWritten int64       `json:"rows_written"`
+	GRPCCalls   int64       `json:"grpc_calls"`
+	Errors      int         `json:"errors"`
+	Error       string      `json:"error,omitempty"` // why the cycle failed
+	Modules     []ModuleRun `json:"modules"`
+}
+
+// ModuleRun summarizes the poll of one module in an ingest cycle
+type ModuleRun struct {
+	Module      string `json:"module"`
+	DurationMs  int64  `json:"duration_ms"`
+	RowsWritten int64  `json:"rows_written"`
+	GRPCCalls   int64            `json:"grpc_calls"`
+	Retries     int              `json:"retries,omitempty"`
+	Writes      map[string]int64 `json:"writes,omitempty"` // rows written by table
+	Error       string           `json:"error,omitempty"`
+}
+
+// IngestRunFilter selects ingest runs. Empty fields match every run.
+type IngestRunFilter struct {
+	ChainName string
+	Failed    bool // only runs with errors
+	Since     time.Time
+	Limit     int
+}
+
+
+// Tenant represents a team sharing a deployment
+type Tenant struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// APIKey represents an API key of a tenant. Only a hash of the key is stored.
+type APIKey struct {
+	ID        string     `json:"id"`
+	TenantID  string     `json:"tenant_id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Role      string     `json:"role,omitempty"` // empty for unrestricted keys
+	KeyHash   string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Watchlist represents a named set of addresses a tenant follows on a chain
+type Watchlist struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Name      string    `json:"name"`
+	ChainName string    `json:"chain_name"`
+	Addresses []string  `json:"addresses"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Portfolio is a named group of a tenant's accounts across chains, whose
+// balances, stake, rewards and value are reported together
+type Portfolio struct {
+	ID        string             `json:"id"`
+	TenantID  string             `json:"tenant_id"`
+	Name      string             `json:"name"`
+	Accounts  []PortfolioAccount `json:"accounts"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// PortfolioAccount is an address of a portfolio on one chain
+type PortfolioAccount struct {
+	ChainName string `json:"chain_name"`
+	Address   string `json:"address"`
+}
+
+// PortfolioHoldings is what a portfolio's accounts on a chain held in a denom
+// at the end of a day: their balances and, in the staking denom, their
+// delegations. ValueUSD prices them at the current price.
+type PortfolioHoldings struct {
+	Date      time.Time `json:"date"`
+	ChainName string    `json:"chain_name"`
+	Denom     string    `json:"denom"`
+	Balance   string    `json:"balance"`
+	Delegated string    `json:"delegated"`
+	ValueUSD  *float64  `json:"value_usd,omitempty"`
+}
+
+// PortfolioValuation is the value of a portfolio's holdings priced in USD at
+// the end of a day, over the chains with a price
+type PortfolioValuation struct {
+	Date     time.Time `json:"date"`
+	ValueUSD float64   `json:"value_usd"`
+}
+
+// Webhook represents an HTTP endpoint a tenant receives notifications on
+type Webhook struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EmailSubscription represents an email address a tenant receives the
+// notifications of the events it subscribes to on. UnsubscribeToken, linked
+// from every email, disables it.
+type EmailSubscription struct {
+	ID               string    `json:"id"`
+	TenantID         string    `json:"tenant_id"`
+	Email            string    `json:"email"`
+	Events           []string  `json:"events"`
+	Enabled          bool      `json:"enabled"`
+	UnsubscribeToken string    `json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// AlertRule represents a condition a tenant is alerted on, optionally limited
+// to a watchlist and delivered to a webhook
+type AlertRule struct {
+	ID          string    `json:"id"`
+	TenantID    string    `json:"tenant_id"`
+	Name        string    `json:"name"`
+	ChainName   string    `json:"chain_name"`
+	WatchlistID string    `json:"watchlist_id,omitempty"`
+	WebhookID   string    `json:"webhook_id,omitempty"`
+	Condition   string    `json:"condition"`
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RuleAlert is the webhook payload notifying a tenant that the condition of
+// an alert rule became true for an account, with the event that made it so
+type RuleAlert struct {
+	TenantID  string          `json:"tenant_id"`
+	Rule      AlertRule       `json:"rule"`
+	EventType string          `json:"event_type"`
+	Event     json.RawMessage `json:"event"`
+	SentAt    time.Time       `json:"sent_at"`
+}
+
+// Digest schedules
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// Digest represents a scheduled summary of a tenant's watched addresses,
+// upcoming proposal deadlines and unbonding completions, delivered to a
+// webhook, an email address or both
+type Digest struct {
+	ID         string     `json:"id"`
+	TenantID   string     `json:"tenant_id"`
+	Name       string     `json:"name"`
+	Schedule   string     `json:"schedule"` // "daily" or "weekly"
+	WebhookID  string     `json:"webhook_id,omitempty"`
+	Email      string     `json:"email,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// DigestReport is the payload of a digest: the changes of the tenant's
+// watched addresses between From and To, and the proposal deadlines and
+// unbonding completions between To and Until
+type DigestReport struct {
+	DigestID    string                `json:"digest_id"`
+	TenantID    string                `json:"tenant_id"`
+	Name        string                `json:"name"`
+	Schedule    string                `json:"schedule"`
+	From        time.Time             `json:"from"`
+	To          time.Time             `json:"to"`
+	Until       time.Time             `json:"until"`
+	Changes     []ActivityItem        `json:"changes"`
+	Truncated   bool                  `json:"truncated"` // more changes than the digest holds
+	Proposals   []ProposalDeadline    `json:"proposal_deadlines"`
+	Unbondings  []UnbondingCompletion `json:"unbonding_completions"`
+	GeneratedAt time.Time             `json:"generated_at"`
+}
+
+// ProposalDeadline is a proposal whose voting period ends soon
+type ProposalDeadline struct {
+	ChainName     string    `json:"chain_name"`
+	ProposalID    uint64    `json:"proposal_id"`
+	Title         string    `json:"title"`
+	Status        string    `json:"status"`
+	VotingEndTime time.Time `json:"voting_end_time"`
+}
+
+// UnbondingCompletion is a pending unbonding delegation entry
+type UnbondingCompletion struct {
+	ChainName        string    `json:"chain_name"`
+	DelegatorAddress string    `json:"delegator_address"`
+	ValidatorAddress string    `json:"validator_address"`
+	CreationHeight   int64     `json:"creation_height"`
+	Balance          string    `json:"balance"`
+	CompletionTime   time.Time `json:"completion_time"`
+}
+
+// UnbondingReminder is the webhook payload reminding a tenant that an
+// unbonding entry of a watched delegator completes soon
+type UnbondingReminder struct {
+	TenantID  string              `json:"tenant_id"`
+	Unbonding UnbondingCompletion `json:"unbonding"`
+	SentAt    time.Time           `json:"sent_at"`
+}
+
+// Address label categories
+const (
+	LabelCategoryExchange   = "exchange"
+	LabelCategoryFoundation = "foundation"
+	LabelCategoryBridge     = "bridge"
+	LabelCategoryOther      = "other"
+)
+
+// LabelCategories lists the valid address label categories
+var LabelCategories = []string{LabelCategoryExchange, LabelCategoryFoundation, LabelCategoryBridge, LabelCategoryOther}
+
+// AddressLabel names the entity behind an address, such as an exchange wallet
+type AddressLabel struct {
+	ChainName string    `json:"chain_name"`
+	Address   string    `json:"address"`
+	Label     string    `json:"label"`
+	Category  string    `json:"category"`
+	Tags      []string  `json:"tags"` // free-form, set by the team
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LabelFilter selects address labels. Empty fields match every label.
+type LabelFilter struct {
+	ChainName  string
+	Categories []string
+	Tag        string
+}
+
+// APIAuditEvent records one authenticated API call
+type APIAuditEvent struct {
+	Timestamp     time.Time `json:"timestamp"`
+	TenantID      string    `json:"tenant_id"`
+	KeyID         string    `json:"key_id"`
+	Method        string    `json:"method"`
+	Endpoint      string    `json:"endpoint"`
+	ParamsHash    string    `json:"params_hash"`
+	Status        int       `json:"status"`
+	LatencyMs     float64   `json:"latency_ms"`
+	ResponseBytes int64     `json:"response_bytes"`
+	RequestID     string    `json:"request_id"`
+}
+
+// APIUsage summarizes the calls of one API key to one endpoint for one day
+type APIUsage struct {
+	Date          time.Time `json:"date"`
+	TenantID      string    `json:"tenant_id"`
+	KeyID         string    `json:"key_id"`
+	Endpoint      string    `json:"endpoint"`
+	Calls         uint64    `json:"calls"`
+	Errors        uint64    `json:"errors"` // calls answered with status 400 or above
+	ResponseBytes uint64    `json:"response_bytes"`
+	AvgLatencyMs  float64   `json:"avg_latency_ms"`
+}
+
+// AccountEvent is a balance or delegation event of an account in replay order
+type AccountEvent struct {
+	Type       string           `json:"type"` // "balance" or "delegation"
+	Height     int64            `json:"height"`
+	Timestamp  time.Time        `json:"timestamp"`
+	Cursor     string           `json:"cursor"` // resumes a replay after this event
+	Balance    *BalanceEvent    `json:"balance,omitempty"`
+	Delegation *DelegationEvent `json:"delegation,omitempty"`
+}
+
+// AccountEventFilter selects the events of an account to replay. Zero values
+// leave a bound open.
+type AccountEventFilter struct {
+	Types      []string // "balance", "delegation"; empty means both
+	FromHeight int64
+	ToHeight   int64
+	From       time.Time
+	To         time.Time
+}
+
+// ActivityItem is an entry of an address's activity feed: a balance or
+// delegation event, a governance vote or a transaction the address paid for
+type ActivityItem struct {
+	Type        string           `json:"type"` // "balance", "delegation", "vote" or "tx"
+	ChainName   string           `json:"chain_name"`
+	Height      int64            `json:"height"`
+	Timestamp   time.Time        `json:"timestamp"`
+	TxHash      string           `json:"tx_hash,omitempty"`
+	Cursor      string           `json:"cursor"` // continues the feed after this item
+	Balance     *BalanceEvent    `json:"balance,omitempty"`
+	Delegation  *DelegationEvent `json:"delegation,omitempty"`
+	Vote        *Vote            `json:"vote,omitempty"`
+	Transaction *Transaction     `json:"transaction,omitempty"`
+}
+
+// ActivityFilter selects the entries of an activity feed. Zero values leave a
+// bound open.
+type ActivityFilter struct {
+	Types []string // "balance", "delegation", "vote", "tx"; empty means all
+	From  time.Time
+	To    time.Time
+}
+
+// OutboxMessage is a Kafka message written to the outbox in the transaction
+// that produced it, and published by the relay once committed
+type OutboxMessage struct {
+	ID        int64             `json:"id"`
+	Key       string            `json:"key"`
+	Value     []byte            `json:"value"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}